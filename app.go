@@ -4,67 +4,222 @@ import (
 	"context"
 	"database/sql"
 	_ "embed"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
 
 	"github.com/gorilla/sessions"
 	"github.com/nasermirzaei89/env"
+	"github.com/nasermirzaei89/scribble/audit"
 	"github.com/nasermirzaei89/scribble/authentication"
 	"github.com/nasermirzaei89/scribble/authorization"
 	"github.com/nasermirzaei89/scribble/authorization/casbin"
+	"github.com/nasermirzaei89/scribble/blobs"
+	"github.com/nasermirzaei89/scribble/communities"
 	"github.com/nasermirzaei89/scribble/contents"
 	"github.com/nasermirzaei89/scribble/database/sqlite3"
 	"github.com/nasermirzaei89/scribble/discuss"
+	"github.com/nasermirzaei89/scribble/emojis"
+	"github.com/nasermirzaei89/scribble/events"
+	"github.com/nasermirzaei89/scribble/federation"
+	"github.com/nasermirzaei89/scribble/live"
+	"github.com/nasermirzaei89/scribble/mailer"
+	"github.com/nasermirzaei89/scribble/mentions"
+	"github.com/nasermirzaei89/scribble/notifications"
 	"github.com/nasermirzaei89/scribble/random"
 	"github.com/nasermirzaei89/scribble/reactions"
+	"github.com/nasermirzaei89/scribble/reputation"
 	"github.com/nasermirzaei89/scribble/web"
+	"github.com/nasermirzaei89/scribble/webhooks"
 	"github.com/nasermirzaei89/server"
 )
 
 type App struct {
-	server  *server.Server
-	handler *web.Handler
-	db      *sql.DB
+	server        *server.Server
+	handler       *web.Handler
+	db            *sql.DB
+	federationSvc *federation.Service
+	liveBroker    *live.Broker
+	eventBus      *events.Bus
+	webhookWorker *webhooks.Worker
+	digester      *notifications.Digester
 }
 
 //go:embed policy.csv
 var defaultAuthorizationPolicyContent string
 
-func NewApp(ctx context.Context) (*App, error) {
-	db, err := sqlite3.NewDB(ctx, env.GetString("DB_DSN", "file::memory:?cache=shared"))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create database connection: %w", err)
-	}
+const defaultAuthorizationCacheSize = 10_000
 
-	err = sqlite3.MigrateUp(ctx, db)
+func NewApp(ctx context.Context) (*App, error) {
+	db, err := openDB(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to run database migrations: %w", err)
+		return nil, err
 	}
 
 	userRepo := sqlite3.NewUserRepository(db)
 	sessionRepo := sqlite3.NewSessionRepository(db)
 	postRepo := sqlite3.NewPostRepository(db)
 	commentRepo := sqlite3.NewCommentRepository(db)
+	commentEditRepo := sqlite3.NewCommentEditRepository(db)
 	userReactionRepo := sqlite3.NewUserReactionRepository(db)
+	reactionSetRepo := sqlite3.NewReactionSetRepository(db)
+	auditEventRepo := sqlite3.NewAuditEventRepository(db)
+	communityRepo := sqlite3.NewCommunityRepository(db)
+	communityMemberRepo := sqlite3.NewCommunityMemberRepository(db)
+	federationKeyRepo := sqlite3.NewFederationKeyRepository(db)
+	federationRemoteActorRepo := sqlite3.NewFederationRemoteActorRepository(db)
+	federationFollowerRepo := sqlite3.NewFederationFollowerRepository(db)
+	federationObjectRepo := sqlite3.NewFederationObjectRepository(db)
+	federationDeliveryRepo := sqlite3.NewFederationDeliveryRepository(db)
+	eventOutboxRepo := sqlite3.NewEventOutboxRepository(db)
+	webhookEndpointRepo := sqlite3.NewWebhookEndpointRepository(db)
+	webhookDeliveryRepo := sqlite3.NewWebhookDeliveryRepository(db)
+	notificationRepo := sqlite3.NewNotificationRepository(db)
+	notificationMuteRepo := sqlite3.NewNotificationMuteRepository(db)
+	notificationEmailSettingsRepo := sqlite3.NewNotificationEmailSettingsRepository(db)
+	mentionRepo := sqlite3.NewMentionRepository(db)
+	customEmojiRepo := sqlite3.NewCustomEmojiRepository(db)
+	reputationRepo := sqlite3.NewReputationRepository(db)
+	postRankingRepo := sqlite3.NewPostRankingRepository(db)
+	commentRankingRepo := sqlite3.NewCommentRankingRepository(db)
+
+	blobStore := blobs.NewLocalStore(env.GetString("BLOB_DIR", "./uploads"))
+
+	auditRecorder := audit.NewBaseService(auditEventRepo)
+	eventBus := events.NewBus(eventOutboxRepo, sqlite3.NewTransactor(db))
 
 	authzProvider, err := newAuthorizationProvider(ctx, db)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create authorization provider: %w", err)
 	}
 
-	authzSvc, err := authorization.NewService(authzProvider)
+	authzSvc, err := authorization.NewService(
+		audit.NewAuthorizationProviderMiddleware(auditRecorder, newAuthorizationCache(authzProvider)),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create authorization service: %w", err)
 	}
 
 	authzClient := authorization.NewClient(authzSvc)
-	authSvc := authentication.NewService(userRepo, sessionRepo, authzClient)
+	authSvc := authentication.NewService(userRepo, sessionRepo, authzClient, auditRecorder, eventBus)
+	auditSvc := audit.NewAuthorizationMiddleware(authzClient, auditRecorder)
+
+	liveBroker := live.NewBroker()
+
+	reactionTargets, err := newReactionTargets(userRepo, postRepo, commentRepo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reaction targets: %w", err)
+	}
+
+	var contentsSvc contents.Service = audit.NewContentsMiddleware(
+		auditRecorder,
+		contents.NewService(postRepo, authzClient, eventBus),
+	)
+	discussSvc := live.NewDiscussMiddleware(
+		liveBroker,
+		audit.NewDiscussMiddleware(auditRecorder, discuss.NewService(commentRepo, commentEditRepo, authzClient, eventBus)),
+	)
+	reactionsSvc := live.NewReactionsMiddleware(
+		liveBroker,
+		commentRepo,
+		reactions.NewService(
+			reactions.Config{
+				DefaultEmojis: env.GetStringSlice("REACTIONS_DEFAULT_EMOJIS", reactions.DefaultEmojis()),
+				MaxPerUser:    env.GetInt("REACTIONS_MAX_PER_USER", 1),
+			},
+			reactionTargets,
+			userReactionRepo,
+			reactionSetRepo,
+			postRepo,
+			commentRepo,
+			customEmojiRepo,
+			authzClient,
+			eventBus,
+		),
+	)
+	communitiesSvc := communities.NewService(communityRepo, communityMemberRepo, authzClient, eventBus)
+	webhooksSvc := webhooks.NewService(webhookEndpointRepo, webhookDeliveryRepo, authzClient)
+
+	webhookWorker := webhooks.NewWorker(
+		webhooks.Config{
+			HTTPClient: nil,
+			Now:        nil,
+		},
+		webhookEndpointRepo,
+		webhookDeliveryRepo,
+	)
+	webhookWorker.Subscribe(eventBus)
+
+	reputationCfg, err := newReputationConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reputation config: %w", err)
+	}
 
-	contentsSvc := contents.NewService(postRepo, authzClient)
-	discussSvc := discuss.NewService(commentRepo, authzClient)
-	reactionsSvc := reactions.NewService(userReactionRepo, authzClient)
+	reputationScorer := reputation.NewScorer(reputationCfg, reputationRepo, userRepo, postRepo, commentRepo, authzClient)
+	reputationScorer.Subscribe(eventBus)
+	reputationSvc := reputation.NewService(reputationRepo, reputationScorer, authzClient)
+
+	contents.NewRanker(newRankingConfig(reputationCfg), postRepo, postRankingRepo).Subscribe(eventBus)
+	discuss.NewRanker(newCommentRankingConfig(reputationCfg), commentRankingRepo).Subscribe(eventBus)
+
+	mentionsSvc := mentions.NewService(mentionRepo, authzClient)
+	emojisSvc := emojis.NewService(customEmojiRepo, blobStore, authzClient)
+	mentions.NewRecorder(eventBus, mentionRepo, userRepo, postRepo, commentRepo).Subscribe()
+
+	srv := newServer()
+	baseURL := env.GetString("BASE_URL", defaultBaseURL(srv))
+
+	unsubscribeKey, err := newUnsubscribeKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load email unsubscribe key: %w", err)
+	}
+
+	notificationsSvc := notifications.NewService(
+		notificationRepo,
+		notificationMuteRepo,
+		notificationEmailSettingsRepo,
+		unsubscribeKey,
+		authzClient,
+	)
+	notifications.NewNotifier(notificationRepo, notificationMuteRepo, postRepo, commentRepo).Subscribe(eventBus)
+
+	digester := notifications.NewDigester(
+		notifications.DigestConfig{
+			BaseURL:        baseURL,
+			UnsubscribeKey: unsubscribeKey,
+			Now:            nil,
+		},
+		newMailer(),
+		notificationEmailSettingsRepo,
+		notificationRepo,
+		userRepo,
+	)
+
+	federationSvc, err := federation.NewService(
+		federation.Config{
+			BaseURL:    baseURL,
+			HTTPClient: nil,
+			Now:        nil,
+		},
+		federationKeyRepo,
+		federationRemoteActorRepo,
+		federationFollowerRepo,
+		federationObjectRepo,
+		federationDeliveryRepo,
+		authSvc,
+		contentsSvc,
+		discussSvc,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create federation service: %w", err)
+	}
+
+	contentsSvc = federation.NewContentsMiddleware(federationSvc, contentsSvc)
+	contentsSvc = live.NewContentsMiddleware(liveBroker, contentsSvc)
 
 	sessionName := env.GetString("SESSION_NAME", "scribble-"+random.String(4))
 	sessionKey := env.GetString("SESSION_KEY", random.String(32))
@@ -75,9 +230,21 @@ func NewApp(ctx context.Context) (*App, error) {
 
 	httpHandler, err := web.NewHandler(
 		authSvc,
+		authzClient,
 		contentsSvc,
 		discussSvc,
 		reactionsSvc,
+		reactionTargets,
+		auditSvc,
+		communitiesSvc,
+		federationSvc,
+		liveBroker,
+		webhooksSvc,
+		notificationsSvc,
+		mentionsSvc,
+		emojisSvc,
+		reputationSvc,
+		baseURL,
 		cookieStore,
 		sessionName,
 		csrfAuthKeys,
@@ -88,9 +255,14 @@ func NewApp(ctx context.Context) (*App, error) {
 	}
 
 	app := &App{
-		server:  newServer(),
-		handler: httpHandler,
-		db:      db,
+		server:        srv,
+		handler:       httpHandler,
+		db:            db,
+		federationSvc: federationSvc,
+		liveBroker:    liveBroker,
+		eventBus:      eventBus,
+		webhookWorker: webhookWorker,
+		digester:      digester,
 	}
 
 	return app, nil
@@ -102,14 +274,23 @@ func (app *App) Run(ctx context.Context) error {
 	defer stop()
 
 	defer func() {
-		if app.db != nil {
-			err := app.db.Close()
-			if err != nil {
-				slog.ErrorContext(ctx, "failed to close database", "error", err)
-			}
+		err := app.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close app", "error", err)
 		}
 	}()
 
+	go app.eventBus.RunDispatcher(ctx)
+	go app.federationSvc.RunDeliveryWorker(ctx)
+	go app.webhookWorker.Run(ctx)
+	go app.digester.Run(ctx)
+
+	// End event streams on shutdown, so the server does not wait for them.
+	go func() {
+		<-ctx.Done()
+		app.liveBroker.Close()
+	}()
+
 	err := app.server.Run(ctx, app.handler)
 	if err != nil {
 		return fmt.Errorf("failed to run server: %w", err)
@@ -118,6 +299,179 @@ func (app *App) Run(ctx context.Context) error {
 	return nil
 }
 
+// Handler returns the HTTP handler of the app, for serving it without the app's own server.
+func (app *App) Handler() http.Handler {
+	return app.handler
+}
+
+func (app *App) Close() error {
+	if app.db == nil {
+		return nil
+	}
+
+	err := app.db.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close database: %w", err)
+	}
+
+	return nil
+}
+
+// openDB connects to the database of DB_DSN and migrates it.
+func openDB(ctx context.Context) (*sql.DB, error) {
+	db, err := sqlite3.NewDB(ctx, env.GetString("DB_DSN", "file::memory:?cache=shared"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create database connection: %w", err)
+	}
+
+	err = sqlite3.MigrateUp(ctx, db)
+	if err != nil {
+		closeErr := db.Close()
+		if closeErr != nil {
+			slog.ErrorContext(ctx, "failed to close database", "error", closeErr)
+		}
+
+		return nil, fmt.Errorf("failed to run database migrations: %w", err)
+	}
+
+	return db, nil
+}
+
+// RepairReactionCounts recomputes the reaction counts of the database from its reactions, and returns how many were
+// wrong.
+func RepairReactionCounts(ctx context.Context) (int, error) {
+	db, err := openDB(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		err := db.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close database", "error", err)
+		}
+	}()
+
+	fixed, err := sqlite3.NewUserReactionRepository(db).RepairCounts(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to repair reaction counts: %w", err)
+	}
+
+	return fixed, nil
+}
+
+// RecomputeReputations rescores every user of the database, like after the reputation weights changed, and returns
+// how many were scored. A running server picks trusted group changes up on restart.
+func RecomputeReputations(ctx context.Context) (int, error) {
+	db, err := openDB(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		err := db.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close database", "error", err)
+		}
+	}()
+
+	cfg, err := newReputationConfig()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load reputation config: %w", err)
+	}
+
+	authzProvider, err := newAuthorizationProvider(ctx, db)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create authorization provider: %w", err)
+	}
+
+	authzSvc, err := authorization.NewService(
+		audit.NewAuthorizationProviderMiddleware(audit.NewBaseService(sqlite3.NewAuditEventRepository(db)), authzProvider),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create authorization service: %w", err)
+	}
+
+	scorer := reputation.NewScorer(
+		cfg,
+		sqlite3.NewReputationRepository(db),
+		sqlite3.NewUserRepository(db),
+		sqlite3.NewPostRepository(db),
+		sqlite3.NewCommentRepository(db),
+		authorization.NewClient(authzSvc),
+	)
+
+	count, err := scorer.RecomputeAll(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to recompute reputations: %w", err)
+	}
+
+	return count, nil
+}
+
+// RerankPosts ranks every post of the database, like after the weights changed, and returns how many were ranked.
+func RerankPosts(ctx context.Context) (int, error) {
+	db, err := openDB(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		err := db.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close database", "error", err)
+		}
+	}()
+
+	reputationCfg, err := newReputationConfig()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load reputation config: %w", err)
+	}
+
+	ranker := contents.NewRanker(
+		newRankingConfig(reputationCfg),
+		sqlite3.NewPostRepository(db),
+		sqlite3.NewPostRankingRepository(db),
+	)
+
+	count, err := ranker.RerankAll(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to rerank posts: %w", err)
+	}
+
+	return count, nil
+}
+
+// RerankComments scores every comment of the database, like after the weights changed, and returns how many were
+// scored.
+func RerankComments(ctx context.Context) (int, error) {
+	db, err := openDB(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		err := db.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close database", "error", err)
+		}
+	}()
+
+	reputationCfg, err := newReputationConfig()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load reputation config: %w", err)
+	}
+
+	ranker := discuss.NewRanker(newCommentRankingConfig(reputationCfg), sqlite3.NewCommentRankingRepository(db))
+
+	count, err := ranker.RerankAll(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to rerank comments: %w", err)
+	}
+
+	return count, nil
+}
+
 func newServer() *server.Server {
 	server := &server.Server{
 		Port: env.GetString("PORT", server.DefaultPort),
@@ -139,6 +493,58 @@ func newServer() *server.Server {
 	return server
 }
 
+// newMailer returns the mailer of notification emails. By default, emails are dropped into MAIL_DIR instead of sent.
+func newMailer() mailer.Mailer { //nolint:ireturn
+	from := env.GetString("MAIL_FROM", "Scribble <no-reply@localhost>")
+
+	if env.GetString("MAIL_TRANSPORT", "file") == "smtp" {
+		return mailer.NewSMTPMailer(mailer.SMTPConfig{
+			Addr:     env.GetString("SMTP_ADDR", "localhost:25"),
+			Username: env.GetString("SMTP_USERNAME", ""),
+			Password: env.GetString("SMTP_PASSWORD", ""),
+			From:     from,
+		})
+	}
+
+	return mailer.NewFileMailer(env.GetString("MAIL_DIR", "./mail"), from)
+}
+
+var errMissingUnsubscribeKey = errors.New("EMAIL_UNSUBSCRIBE_KEY is required when emails are sent over smtp")
+
+// newUnsubscribeKey returns the key that signs the unsubscribe links in emails. Links signed by a key generated on
+// start stop working on restart, so sending emails over SMTP requires EMAIL_UNSUBSCRIBE_KEY. Emails dropped into
+// MAIL_DIR are only for development, and fall back to a generated key.
+func newUnsubscribeKey(ctx context.Context) ([]byte, error) {
+	key := env.GetString("EMAIL_UNSUBSCRIBE_KEY", "")
+	if key != "" {
+		return []byte(key), nil
+	}
+
+	if env.GetString("MAIL_TRANSPORT", "file") == "smtp" {
+		return nil, errMissingUnsubscribeKey
+	}
+
+	slog.WarnContext(ctx, "EMAIL_UNSUBSCRIBE_KEY is not set, unsubscribe links in emails stop working on restart")
+
+	return []byte(random.String(32)), nil
+}
+
+// defaultBaseURL is where the server listens locally. Federation, emails and feeds link to BASE_URL, so public sites
+// must set it to their public URL.
+func defaultBaseURL(srv *server.Server) string {
+	scheme := "http"
+	if srv.TLS.Enabled {
+		scheme = "https"
+	}
+
+	host := srv.Host
+	if host == "" {
+		host = "localhost"
+	}
+
+	return scheme + "://" + net.JoinHostPort(host, srv.Port)
+}
+
 func GetLogLevelFromEnv() slog.Level {
 	levelStr := env.GetString("LOG_LEVEL", "info")
 	switch levelStr {
@@ -158,17 +564,12 @@ func GetLogLevelFromEnv() slog.Level {
 }
 
 func newAuthorizationProvider(ctx context.Context, db *sql.DB) (*casbin.AuthorizationProvider, error) {
-	adapter, err := casbin.NewSQLAdapter(db, "sqlite3", "casbin_rule")
-	if err != nil {
-		return nil, fmt.Errorf("failed to create authorization adapter: %w", err)
-	}
-
-	provider, err := casbin.NewAuthorizationProvider(adapter)
+	provider, err := casbin.NewAuthorizationProvider(sqlite3.NewCasbinAdapter(db))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create authorization provider: %w", err)
 	}
 
-	policyContent, err := loadPolicyContent()
+	policyContent, err := LoadAuthorizationPolicy()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load authorization policy content: %w", err)
 	}
@@ -181,7 +582,88 @@ func newAuthorizationProvider(ctx context.Context, db *sql.DB) (*casbin.Authoriz
 	return provider, nil
 }
 
-func loadPolicyContent() (string, error) {
+//nolint:ireturn
+func newAuthorizationCache(provider authorization.AuthorizationProvider) authorization.AuthorizationProvider {
+	cacheSize := env.GetInt("AUTHORIZATION_CACHE_SIZE", defaultAuthorizationCacheSize)
+	if cacheSize <= 0 {
+		return provider
+	}
+
+	return authorization.NewCachingProvider(provider, cacheSize)
+}
+
+// newReactionTargets registers the types of things users can react to.
+func newReactionTargets(
+	userRepo authentication.UserRepository,
+	postRepo contents.PostRepository,
+	commentRepo discuss.CommentRepository,
+) (*reactions.TargetRegistry, error) {
+	targets := reactions.NewTargetRegistry()
+
+	for _, spec := range []reactions.TargetTypeSpec{
+		reactions.PostTargetType(postRepo),
+		reactions.CommentTargetType(commentRepo),
+		reactions.UserTargetType(userRepo),
+	} {
+		err := targets.Register(spec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to register reaction target type: %w", err)
+		}
+	}
+
+	return targets, nil
+}
+
+// newReputationConfig reads the reputation weights, which default to reputation.DefaultConfig.
+func newReputationConfig() (reputation.Config, error) {
+	cfg := reputation.DefaultConfig()
+
+	if entries := env.GetStringSlice("REPUTATION_EMOJI_WEIGHTS", []string{}); len(entries) > 0 {
+		weights, err := reputation.ParseEmojiWeights(entries)
+		if err != nil {
+			return reputation.Config{}, fmt.Errorf("failed to parse emoji weights: %w", err)
+		}
+
+		cfg.EmojiWeights = weights
+	}
+
+	cfg.DefaultEmojiWeight = env.GetInt("REPUTATION_DEFAULT_EMOJI_WEIGHT", cfg.DefaultEmojiWeight)
+	cfg.PostWeight = env.GetInt("REPUTATION_POST_WEIGHT", cfg.PostWeight)
+	cfg.CommentWeight = env.GetInt("REPUTATION_COMMENT_WEIGHT", cfg.CommentWeight)
+	cfg.AgePointDays = env.GetInt("REPUTATION_AGE_POINT_DAYS", cfg.AgePointDays)
+	cfg.MaxAgePoints = env.GetInt("REPUTATION_MAX_AGE_POINTS", cfg.MaxAgePoints)
+	cfg.TrustedThreshold = env.GetInt("REPUTATION_TRUSTED_THRESHOLD", cfg.TrustedThreshold)
+
+	return cfg, nil
+}
+
+// newRankingConfig weighs the reactions to posts like the reputation of their authors, so the two agree on what a
+// reaction is worth.
+func newRankingConfig(reputationCfg reputation.Config) contents.RankingConfig {
+	cfg := contents.DefaultRankingConfig()
+
+	cfg.EmojiWeights = reputationCfg.EmojiWeights
+	cfg.DefaultEmojiWeight = reputationCfg.DefaultEmojiWeight
+	cfg.CommentWeight = env.GetInt("POSTS_HOT_COMMENT_WEIGHT", cfg.CommentWeight)
+
+	return cfg
+}
+
+// newCommentRankingConfig weighs the reactions to comments like the reputation of their authors.
+func newCommentRankingConfig(reputationCfg reputation.Config) discuss.RankingConfig {
+	return discuss.RankingConfig{
+		EmojiWeights:       reputationCfg.EmojiWeights,
+		DefaultEmojiWeight: reputationCfg.DefaultEmojiWeight,
+	}
+}
+
+// DefaultAuthorizationPolicy returns the policy shipped with the application.
+func DefaultAuthorizationPolicy() string {
+	return defaultAuthorizationPolicyContent
+}
+
+// LoadAuthorizationPolicy returns the policy from AUTHORIZATION_POLICY_FILE, or the default policy when it is not set.
+func LoadAuthorizationPolicy() (string, error) {
 	policyFilePath := env.GetString("AUTHORIZATION_POLICY_FILE", "")
 
 	if policyFilePath == "" {
@@ -0,0 +1,144 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/authorization"
+)
+
+const ServiceName = "github.com/nasermirzaei89/scribble/audit"
+
+const (
+	defaultListLimit = 100
+	maxListLimit     = 1000
+)
+
+// Recorder appends events to the audit log. Hooks in other services depend on it rather than on Service, so recording
+// is never subject to authorization.
+type Recorder interface {
+	Record(ctx context.Context, req RecordRequest) error
+}
+
+type Service interface {
+	Recorder
+	ListEvents(ctx context.Context, req ListEventsRequest) ([]*Event, error)
+}
+
+type BaseService struct {
+	eventRepo EventRepository
+}
+
+var _ Service = (*BaseService)(nil)
+
+func NewService(eventRepo EventRepository, authzClient *authorization.Client) Service { //nolint:ireturn
+	return NewAuthorizationMiddleware(authzClient, NewBaseService(eventRepo))
+}
+
+func NewBaseService(eventRepo EventRepository) *BaseService {
+	return &BaseService{
+		eventRepo: eventRepo,
+	}
+}
+
+type RecordRequest struct {
+	Action  string
+	Target  string
+	Outcome Outcome
+	Detail  string
+}
+
+// Record stores an event performed by the subject in the context, from the client in the context.
+func (svc *BaseService) Record(ctx context.Context, req RecordRequest) error {
+	if !req.Outcome.IsValid() {
+		return InvalidOutcomeError{Outcome: req.Outcome}
+	}
+
+	clientInfo := ClientInfoFromContext(ctx)
+
+	event := &Event{
+		ID:         uuid.NewString(),
+		OccurredAt: time.Now(),
+		Actor:      authcontext.GetSubject(ctx),
+		Action:     req.Action,
+		Target:     req.Target,
+		Outcome:    req.Outcome,
+		IP:         clientInfo.IP,
+		UserAgent:  clientInfo.UserAgent,
+		Detail:     req.Detail,
+	}
+
+	err := svc.eventRepo.Insert(ctx, event)
+	if err != nil {
+		return fmt.Errorf("failed to insert audit event: %w", err)
+	}
+
+	return nil
+}
+
+type ListEventsRequest struct {
+	Actor   string
+	Action  string
+	Target  string
+	Outcome Outcome
+	Since   time.Time
+	Until   time.Time
+	Limit   int
+	Offset  int
+}
+
+func (svc *BaseService) ListEvents(ctx context.Context, req ListEventsRequest) ([]*Event, error) {
+	if req.Outcome != "" && !req.Outcome.IsValid() {
+		return nil, InvalidOutcomeError{Outcome: req.Outcome}
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	limit = min(limit, maxListLimit)
+
+	events, err := svc.eventRepo.List(ctx, &ListEventsParams{
+		Actor:   req.Actor,
+		Action:  req.Action,
+		Target:  req.Target,
+		Outcome: req.Outcome,
+		Since:   req.Since,
+		Until:   req.Until,
+		Limit:   limit,
+		Offset:  max(req.Offset, 0),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+
+	return events, nil
+}
+
+// OutcomeOf classifies the error returned by an audited operation.
+func OutcomeOf(err error) Outcome {
+	if err == nil {
+		return OutcomeSuccess
+	}
+
+	if _, ok := errors.AsType[*authorization.AccessDeniedError](err); ok {
+		return OutcomeDenied
+	}
+
+	return OutcomeFailure
+}
+
+// RecordOrLog records the event and logs instead of failing, so that a broken audit log never blocks the audited
+// operation itself.
+func RecordOrLog(ctx context.Context, recorder Recorder, req RecordRequest) {
+	err := recorder.Record(ctx, req)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to record audit event", "action", req.Action, "target", req.Target, "error", err)
+	}
+}
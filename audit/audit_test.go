@@ -0,0 +1,94 @@
+package audit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nasermirzaei89/scribble/audit"
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryEventRepository struct {
+	events []*audit.Event
+}
+
+func (repo *memoryEventRepository) Insert(ctx context.Context, event *audit.Event) error {
+	repo.events = append(repo.events, event)
+
+	return nil
+}
+
+func (repo *memoryEventRepository) List(ctx context.Context, params *audit.ListEventsParams) ([]*audit.Event, error) {
+	return repo.events, nil
+}
+
+type stubContentsService struct {
+	err error
+}
+
+func (s *stubContentsService) CreatePost(ctx context.Context, req contents.CreatePostRequest) (*contents.Post, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &contents.Post{ID: "post1", AuthorID: req.AuthorID, Content: req.Content}, nil
+}
+
+func (s *stubContentsService) ListPosts(ctx context.Context, req contents.ListPostsRequest) ([]*contents.Post, error) {
+	return []*contents.Post{}, nil
+}
+
+func (s *stubContentsService) GetPost(ctx context.Context, postID string) (*contents.Post, error) {
+	return &contents.Post{ID: postID, AuthorID: "author1", Content: "test"}, nil
+}
+
+func TestContentsMiddleware(t *testing.T) {
+	ctx := audit.WithClientInfo(
+		authcontext.WithSubject(context.Background(), "user1"),
+		audit.ClientInfo{IP: "127.0.0.1", UserAgent: "test-agent"},
+	)
+
+	t.Run("Success", func(t *testing.T) {
+		repo := &memoryEventRepository{}
+		svc := audit.NewContentsMiddleware(audit.NewBaseService(repo), &stubContentsService{})
+
+		_, err := svc.CreatePost(ctx, contents.CreatePostRequest{AuthorID: "user1", Content: "post"})
+		require.NoError(t, err)
+
+		require.Len(t, repo.events, 1)
+		assert.Equal(t, "user1", repo.events[0].Actor)
+		assert.Equal(t, audit.ActionCreatePost, repo.events[0].Action)
+		assert.Equal(t, "post:post1", repo.events[0].Target)
+		assert.Equal(t, audit.OutcomeSuccess, repo.events[0].Outcome)
+		assert.Equal(t, "127.0.0.1", repo.events[0].IP)
+		assert.Equal(t, "test-agent", repo.events[0].UserAgent)
+	})
+
+	t.Run("Denied", func(t *testing.T) {
+		repo := &memoryEventRepository{}
+		deniedErr := &authorization.AccessDeniedError{}
+		svc := audit.NewContentsMiddleware(audit.NewBaseService(repo), &stubContentsService{err: deniedErr})
+
+		_, err := svc.CreatePost(ctx, contents.CreatePostRequest{AuthorID: "user1", Content: "post"})
+		require.ErrorAs(t, err, &deniedErr)
+
+		require.Len(t, repo.events, 1)
+		assert.Equal(t, audit.OutcomeDenied, repo.events[0].Outcome)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		repo := &memoryEventRepository{}
+		svc := audit.NewContentsMiddleware(audit.NewBaseService(repo), &stubContentsService{err: errors.New("boom")})
+
+		_, err := svc.CreatePost(ctx, contents.CreatePostRequest{AuthorID: "user1", Content: "post"})
+		require.Error(t, err)
+
+		require.Len(t, repo.events, 1)
+		assert.Equal(t, audit.OutcomeFailure, repo.events[0].Outcome)
+	})
+}
@@ -0,0 +1,160 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nasermirzaei89/scribble/authorization"
+)
+
+// AuthorizationProviderMiddleware records policy edits and role changes made through an authorization provider.
+type AuthorizationProviderMiddleware struct {
+	recorder Recorder
+	next     authorization.AuthorizationProvider
+}
+
+var _ authorization.AuthorizationProvider = (*AuthorizationProviderMiddleware)(nil)
+
+func NewAuthorizationProviderMiddleware(
+	recorder Recorder,
+	next authorization.AuthorizationProvider,
+) *AuthorizationProviderMiddleware {
+	return &AuthorizationProviderMiddleware{
+		recorder: recorder,
+		next:     next,
+	}
+}
+
+func (mw *AuthorizationProviderMiddleware) CheckAccess(
+	ctx context.Context,
+	req authorization.CheckAccessRequest,
+) (*authorization.CheckAccessResponse, error) {
+	res, err := mw.next.CheckAccess(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next provider: %w", err)
+	}
+
+	return res, nil
+}
+
+func (mw *AuthorizationProviderMiddleware) AddPolicy(ctx context.Context, reqs ...authorization.AddPolicyRequest) error {
+	err := mw.next.AddPolicy(ctx, reqs...)
+
+	for _, req := range reqs {
+		RecordOrLog(ctx, mw.recorder, RecordRequest{
+			Action:  ActionAddPolicy,
+			Target:  req.Subject,
+			Outcome: OutcomeOf(err),
+			Detail:  policyDetail(req.Domain, req.Object, req.Action),
+		})
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to call next provider: %w", err)
+	}
+
+	return nil
+}
+
+func (mw *AuthorizationProviderMiddleware) RemovePolicy(
+	ctx context.Context,
+	reqs ...authorization.RemovePolicyRequest,
+) error {
+	err := mw.next.RemovePolicy(ctx, reqs...)
+
+	for _, req := range reqs {
+		RecordOrLog(ctx, mw.recorder, RecordRequest{
+			Action:  ActionRemovePolicy,
+			Target:  req.Subject,
+			Outcome: OutcomeOf(err),
+			Detail:  policyDetail(req.Domain, req.Object, req.Action),
+		})
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to call next provider: %w", err)
+	}
+
+	return nil
+}
+
+func (mw *AuthorizationProviderMiddleware) AddToGroup(ctx context.Context, sub string, groups ...string) error {
+	err := mw.next.AddToGroup(ctx, sub, groups...)
+
+	RecordOrLog(ctx, mw.recorder, RecordRequest{
+		Action:  ActionAddToGroup,
+		Target:  sub,
+		Outcome: OutcomeOf(err),
+		Detail:  "groups=" + strings.Join(groups, ","),
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to call next provider: %w", err)
+	}
+
+	return nil
+}
+
+func (mw *AuthorizationProviderMiddleware) RemoveFromGroup(ctx context.Context, sub string, groups ...string) error {
+	err := mw.next.RemoveFromGroup(ctx, sub, groups...)
+
+	RecordOrLog(ctx, mw.recorder, RecordRequest{
+		Action:  ActionRemoveFromGroup,
+		Target:  sub,
+		Outcome: OutcomeOf(err),
+		Detail:  "groups=" + strings.Join(groups, ","),
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to call next provider: %w", err)
+	}
+
+	return nil
+}
+
+func policyDetail(domain, object, action string) string {
+	return fmt.Sprintf("domain=%s object=%s action=%s", domain, object, action)
+}
+
+func (mw *AuthorizationProviderMiddleware) AddToDomainGroup(
+	ctx context.Context,
+	sub, domain string,
+	groups ...string,
+) error {
+	err := mw.next.AddToDomainGroup(ctx, sub, domain, groups...)
+
+	RecordOrLog(ctx, mw.recorder, RecordRequest{
+		Action:  ActionAddToGroup,
+		Target:  sub,
+		Outcome: OutcomeOf(err),
+		Detail:  "domain=" + domain + " groups=" + strings.Join(groups, ","),
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to call next provider: %w", err)
+	}
+
+	return nil
+}
+
+func (mw *AuthorizationProviderMiddleware) RemoveFromDomainGroup(
+	ctx context.Context,
+	sub, domain string,
+	groups ...string,
+) error {
+	err := mw.next.RemoveFromDomainGroup(ctx, sub, domain, groups...)
+
+	RecordOrLog(ctx, mw.recorder, RecordRequest{
+		Action:  ActionRemoveFromGroup,
+		Target:  sub,
+		Outcome: OutcomeOf(err),
+		Detail:  "domain=" + domain + " groups=" + strings.Join(groups, ","),
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to call next provider: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nasermirzaei89/scribble/authorization"
+)
+
+const (
+	ActionListEvents = "listEvents"
+)
+
+type AuthorizationMiddleware struct {
+	authzClient *authorization.Client
+	next        Service
+}
+
+var _ Service = (*AuthorizationMiddleware)(nil)
+
+func NewAuthorizationMiddleware(authzClient *authorization.Client, next Service) *AuthorizationMiddleware {
+	return &AuthorizationMiddleware{
+		authzClient: authzClient,
+		next:        next,
+	}
+}
+
+func (mw *AuthorizationMiddleware) Record(ctx context.Context, req RecordRequest) error {
+	err := mw.next.Record(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return nil
+}
+
+func (mw *AuthorizationMiddleware) ListEvents(ctx context.Context, req ListEventsRequest) ([]*Event, error) {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, "", ActionListEvents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	events, err := mw.next.ListEvents(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,97 @@
+package audit_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	fileadapter "github.com/casbin/casbin/v3/persist/file-adapter"
+	"github.com/google/uuid"
+	"github.com/nasermirzaei89/scribble/audit"
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/authorization/casbin"
+	"github.com/stretchr/testify/require"
+)
+
+type stubService struct{}
+
+func (s *stubService) Record(ctx context.Context, req audit.RecordRequest) error {
+	return nil
+}
+
+func (s *stubService) ListEvents(ctx context.Context, req audit.ListEventsRequest) ([]*audit.Event, error) {
+	return []*audit.Event{}, nil
+}
+
+func TestAuthorizationMiddleware(t *testing.T) {
+	ctx := context.Background()
+
+	tmpDir := t.TempDir()
+	tmpFile := filepath.Join(tmpDir, "policy.csv")
+	content := []byte(`g, system:anonymous, system:unauthenticated, *
+
+p, system:group:root, github.com/nasermirzaei89/scribble/audit, -, listEvents
+`)
+
+	err := os.WriteFile(tmpFile, content, 0o600)
+	require.NoError(t, err)
+
+	adapter := fileadapter.NewAdapter(tmpFile)
+
+	provider, err := casbin.NewAuthorizationProvider(adapter)
+	require.NoError(t, err)
+
+	authzSvc, err := authorization.NewService(provider)
+	require.NoError(t, err)
+
+	client := authorization.NewClient(authzSvc)
+	svc := audit.NewAuthorizationMiddleware(client, &stubService{})
+
+	userID := uuid.NewString()
+	err = client.AddToGroup(ctx, userID, authcontext.Authenticated)
+	require.NoError(t, err)
+
+	rootID := uuid.NewString()
+	err = client.AddToGroup(ctx, rootID, authcontext.Authenticated, "system:group:root")
+	require.NoError(t, err)
+
+	anonymousCtx := ctx
+	authenticatedCtx := authcontext.WithSubject(ctx, userID)
+	rootCtx := authcontext.WithSubject(ctx, rootID)
+
+	recordReq := audit.RecordRequest{
+		Action:  audit.ActionLogin,
+		Target:  "user:" + userID,
+		Outcome: audit.OutcomeSuccess,
+		Detail:  "",
+	}
+
+	t.Run("anonymous", func(t *testing.T) {
+		err := svc.Record(anonymousCtx, recordReq)
+		require.NoError(t, err)
+
+		_, err = svc.ListEvents(anonymousCtx, audit.ListEventsRequest{})
+		require.Error(t, err)
+
+		accessDeniedErr := &authorization.AccessDeniedError{}
+		require.ErrorAs(t, err, &accessDeniedErr)
+	})
+
+	t.Run("authenticated", func(t *testing.T) {
+		err := svc.Record(authenticatedCtx, recordReq)
+		require.NoError(t, err)
+
+		_, err = svc.ListEvents(authenticatedCtx, audit.ListEventsRequest{})
+		require.Error(t, err)
+
+		accessDeniedErr := &authorization.AccessDeniedError{}
+		require.ErrorAs(t, err, &accessDeniedErr)
+	})
+
+	t.Run("root", func(t *testing.T) {
+		_, err := svc.ListEvents(rootCtx, audit.ListEventsRequest{})
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nasermirzaei89/scribble/contents"
+)
+
+// ContentsMiddleware records post lifecycle events, including attempts the next service rejected.
+type ContentsMiddleware struct {
+	recorder Recorder
+	next     contents.Service
+}
+
+var _ contents.Service = (*ContentsMiddleware)(nil)
+
+func NewContentsMiddleware(recorder Recorder, next contents.Service) *ContentsMiddleware {
+	return &ContentsMiddleware{
+		recorder: recorder,
+		next:     next,
+	}
+}
+
+func (mw *ContentsMiddleware) CreatePost(ctx context.Context, req contents.CreatePostRequest) (*contents.Post, error) {
+	post, err := mw.next.CreatePost(ctx, req)
+
+	target := ""
+	if post != nil {
+		target = "post:" + post.ID
+	}
+
+	RecordOrLog(ctx, mw.recorder, RecordRequest{
+		Action:  ActionCreatePost,
+		Target:  target,
+		Outcome: OutcomeOf(err),
+		Detail:  "",
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return post, nil
+}
+
+func (mw *ContentsMiddleware) ListPosts(ctx context.Context, req contents.ListPostsRequest) ([]*contents.Post, error) {
+	posts, err := mw.next.ListPosts(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return posts, nil
+}
+
+func (mw *ContentsMiddleware) GetPost(ctx context.Context, postID string) (*contents.Post, error) {
+	post, err := mw.next.GetPost(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return post, nil
+}
@@ -0,0 +1,24 @@
+package audit
+
+import "context"
+
+// ClientInfo describes where a request came from.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+type contextKeyClientInfo struct{}
+
+func WithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	return context.WithValue(ctx, contextKeyClientInfo{}, info)
+}
+
+func ClientInfoFromContext(ctx context.Context) ClientInfo {
+	info, ok := ctx.Value(contextKeyClientInfo{}).(ClientInfo)
+	if !ok {
+		return ClientInfo{IP: "", UserAgent: ""}
+	}
+
+	return info
+}
@@ -0,0 +1,124 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nasermirzaei89/scribble/discuss"
+)
+
+// DiscussMiddleware records comment lifecycle events, including attempts the next service rejected.
+type DiscussMiddleware struct {
+	recorder Recorder
+	next     discuss.Service
+}
+
+var _ discuss.Service = (*DiscussMiddleware)(nil)
+
+func NewDiscussMiddleware(recorder Recorder, next discuss.Service) *DiscussMiddleware {
+	return &DiscussMiddleware{
+		recorder: recorder,
+		next:     next,
+	}
+}
+
+func (mw *DiscussMiddleware) CreateComment(
+	ctx context.Context,
+	req discuss.CreateCommentRequest,
+) (*discuss.Comment, error) {
+	comment, err := mw.next.CreateComment(ctx, req)
+
+	target := "post:" + req.PostID
+	if comment != nil {
+		target = "comment:" + comment.ID
+	}
+
+	RecordOrLog(ctx, mw.recorder, RecordRequest{
+		Action:  ActionCreateComment,
+		Target:  target,
+		Outcome: OutcomeOf(err),
+		Detail:  "",
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return comment, nil
+}
+
+func (mw *DiscussMiddleware) ListComments(
+	ctx context.Context,
+	req discuss.ListCommentsRequest,
+) ([]*discuss.Comment, error) {
+	comments, err := mw.next.ListComments(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return comments, nil
+}
+
+func (mw *DiscussMiddleware) GetComment(ctx context.Context, commentID string) (*discuss.Comment, error) {
+	comment, err := mw.next.GetComment(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return comment, nil
+}
+
+func (mw *DiscussMiddleware) CountComments(ctx context.Context, postID string) (int, error) {
+	count, err := mw.next.CountComments(ctx, postID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return count, nil
+}
+
+func (mw *DiscussMiddleware) UpdateComment(
+	ctx context.Context,
+	req discuss.UpdateCommentRequest,
+) (*discuss.Comment, bool, error) {
+	comment, changed, err := mw.next.UpdateComment(ctx, req)
+
+	RecordOrLog(ctx, mw.recorder, RecordRequest{
+		Action:  ActionUpdateComment,
+		Target:  "comment:" + req.CommentID,
+		Outcome: OutcomeOf(err),
+		Detail:  "",
+	})
+
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return comment, changed, nil
+}
+
+func (mw *DiscussMiddleware) DeleteComment(ctx context.Context, commentID string) (*discuss.Comment, error) {
+	comment, err := mw.next.DeleteComment(ctx, commentID)
+
+	RecordOrLog(ctx, mw.recorder, RecordRequest{
+		Action:  ActionDeleteComment,
+		Target:  "comment:" + commentID,
+		Outcome: OutcomeOf(err),
+		Detail:  "",
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return comment, nil
+}
+
+func (mw *DiscussMiddleware) ListCommentEdits(ctx context.Context, commentID string) ([]*discuss.CommentEdit, error) {
+	edits, err := mw.next.ListCommentEdits(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return edits, nil
+}
@@ -0,0 +1,74 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	ActionRegister        = "authentication.register"
+	ActionLogin           = "authentication.login"
+	ActionLogout          = "authentication.logout"
+	ActionAddPolicy       = "authorization.addPolicy"
+	ActionRemovePolicy    = "authorization.removePolicy"
+	ActionAddToGroup      = "authorization.addToGroup"
+	ActionRemoveFromGroup = "authorization.removeFromGroup"
+	ActionCreatePost      = "contents.createPost"
+	ActionCreateComment   = "discuss.createComment"
+	ActionUpdateComment   = "discuss.updateComment"
+	ActionDeleteComment   = "discuss.deleteComment"
+)
+
+type Outcome string
+
+const (
+	OutcomeSuccess Outcome = "success"
+	OutcomeFailure Outcome = "failure"
+	OutcomeDenied  Outcome = "denied"
+)
+
+func (outcome Outcome) IsValid() bool {
+	switch outcome {
+	case OutcomeSuccess, OutcomeFailure, OutcomeDenied:
+		return true
+	default:
+		return false
+	}
+}
+
+type Event struct {
+	ID         string
+	OccurredAt time.Time
+	Actor      string
+	Action     string
+	Target     string
+	Outcome    Outcome
+	IP         string
+	UserAgent  string
+	Detail     string
+}
+
+type EventRepository interface {
+	Insert(ctx context.Context, event *Event) (err error)
+	List(ctx context.Context, params *ListEventsParams) (events []*Event, err error)
+}
+
+type ListEventsParams struct {
+	Actor   string
+	Action  string
+	Target  string
+	Outcome Outcome
+	Since   time.Time
+	Until   time.Time
+	Limit   int
+	Offset  int
+}
+
+type InvalidOutcomeError struct {
+	Outcome Outcome
+}
+
+func (err InvalidOutcomeError) Error() string {
+	return fmt.Sprintf("invalid audit outcome: %q", err.Outcome)
+}
@@ -0,0 +1,144 @@
+package audit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type ExportFormat string
+
+const (
+	ExportFormatCSV   ExportFormat = "csv"
+	ExportFormatJSONL ExportFormat = "jsonl"
+)
+
+func (format ExportFormat) ContentType() string {
+	switch format {
+	case ExportFormatCSV:
+		return "text/csv; charset=utf-8"
+	case ExportFormatJSONL:
+		return "application/jsonl; charset=utf-8"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+type UnsupportedExportFormatError struct {
+	Format ExportFormat
+}
+
+func (err UnsupportedExportFormatError) Error() string {
+	return fmt.Sprintf("unsupported audit export format: %q", err.Format)
+}
+
+type exportedEvent struct {
+	ID         string  `json:"id"`
+	OccurredAt string  `json:"occurredAt"`
+	Actor      string  `json:"actor"`
+	Action     string  `json:"action"`
+	Target     string  `json:"target"`
+	Outcome    Outcome `json:"outcome"`
+	IP         string  `json:"ip"`
+	UserAgent  string  `json:"userAgent"`
+	Detail     string  `json:"detail"`
+}
+
+func newExportedEvent(event *Event) exportedEvent {
+	return exportedEvent{
+		ID:         event.ID,
+		OccurredAt: event.OccurredAt.UTC().Format(time.RFC3339Nano),
+		Actor:      event.Actor,
+		Action:     event.Action,
+		Target:     event.Target,
+		Outcome:    event.Outcome,
+		IP:         event.IP,
+		UserAgent:  event.UserAgent,
+		Detail:     event.Detail,
+	}
+}
+
+// Export writes the events to w in the given format.
+func Export(w io.Writer, format ExportFormat, events []*Event) error {
+	switch format {
+	case ExportFormatCSV:
+		return exportCSV(w, events)
+	case ExportFormatJSONL:
+		return exportJSONL(w, events)
+	default:
+		return UnsupportedExportFormatError{Format: format}
+	}
+}
+
+func exportCSV(w io.Writer, events []*Event) error {
+	writer := csv.NewWriter(w)
+
+	err := writer.Write([]string{
+		"id",
+		"occurred_at",
+		"actor",
+		"action",
+		"target",
+		"outcome",
+		"ip",
+		"user_agent",
+		"detail",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+
+	for _, event := range events {
+		exported := newExportedEvent(event)
+
+		err = writer.Write([]string{
+			exported.ID,
+			exported.OccurredAt,
+			escapeCSVFormula(exported.Actor),
+			exported.Action,
+			escapeCSVFormula(exported.Target),
+			string(exported.Outcome),
+			escapeCSVFormula(exported.IP),
+			escapeCSVFormula(exported.UserAgent),
+			escapeCSVFormula(exported.Detail),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to write csv record: %w", err)
+		}
+	}
+
+	writer.Flush()
+
+	err = writer.Error()
+	if err != nil {
+		return fmt.Errorf("failed to flush csv: %w", err)
+	}
+
+	return nil
+}
+
+// escapeCSVFormula prefixes values spreadsheets would run as formulas with a quote, so they are shown as text. Actors,
+// targets, client details and event details come from users.
+func escapeCSVFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
+
+func exportJSONL(w io.Writer, events []*Event) error {
+	encoder := json.NewEncoder(w)
+
+	for _, event := range events {
+		err := encoder.Encode(newExportedEvent(event))
+		if err != nil {
+			return fmt.Errorf("failed to encode json line: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package audit_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportCSVEscapesFormulas(t *testing.T) {
+	var buf strings.Builder
+
+	err := audit.Export(&buf, audit.ExportFormatCSV, []*audit.Event{{
+		ID:         "event1",
+		OccurredAt: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+		Actor:      "=HYPERLINK(\"https://example.com\")",
+		Action:     audit.ActionLogin,
+		Target:     "+target",
+		Outcome:    audit.OutcomeSuccess,
+		IP:         "-1",
+		UserAgent:  "@agent",
+		Detail:     "plain detail",
+	}})
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, `event1,2026-03-01T10:00:00Z,"'=HYPERLINK(""https://example.com"")",authentication.login,`+
+		`'+target,success,'-1,'@agent,plain detail`, lines[1])
+}
@@ -7,22 +7,34 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/nasermirzaei89/scribble/audit"
 	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
 	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/events"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type Service struct {
-	userRepo    UserRepository
-	sessionRepo SessionRepository
-	authzClient *authorization.Client
+	userRepo      UserRepository
+	sessionRepo   SessionRepository
+	authzClient   *authorization.Client
+	auditRecorder audit.Recorder
+	bus           *events.Bus
 }
 
-func NewService(userRepo UserRepository, sessionRepo SessionRepository, authzClient *authorization.Client) *Service {
+func NewService(
+	userRepo UserRepository,
+	sessionRepo SessionRepository,
+	authzClient *authorization.Client,
+	auditRecorder audit.Recorder,
+	bus *events.Bus,
+) *Service {
 	return &Service{
-		userRepo:    userRepo,
-		sessionRepo: sessionRepo,
-		authzClient: authzClient,
+		userRepo:      userRepo,
+		sessionRepo:   sessionRepo,
+		authzClient:   authzClient,
+		auditRecorder: auditRecorder,
+		bus:           bus,
 	}
 }
 
@@ -35,22 +47,54 @@ func HashPassword(password string) (string, error) {
 	return string(bcryptHash), nil
 }
 
-func (svc *Service) Register(ctx context.Context, username, password string) error {
+func (svc *Service) Register(ctx context.Context, username, password string) (*User, error) {
+	user, err := svc.register(ctx, username, password)
+
+	target := "username:" + username
+	if user != nil {
+		target = "user:" + user.ID
+	}
+
+	audit.RecordOrLog(ctx, svc.auditRecorder, audit.RecordRequest{
+		Action:  audit.ActionRegister,
+		Target:  target,
+		Outcome: audit.OutcomeOf(err),
+		Detail:  errorDetail(err),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	user.PasswordHash = "" // clear password hash before returning user
+
+	return user, nil
+}
+
+func (svc *Service) register(ctx context.Context, username, password string) (*User, error) {
 	// TODO: validate username and password
+	if IsRemoteUsername(username) {
+		return nil, &InvalidUsernameError{Username: username, Reason: "must not contain @"}
+	}
+
 	_, err := svc.userRepo.FindByUsername(ctx, username)
 	if err != nil {
 		if _, ok := errors.AsType[*UserByUsernameNotFoundError](err); !ok {
-			return fmt.Errorf("failed to check if username already exists: %w", err)
+			return nil, fmt.Errorf("failed to check if username already exists: %w", err)
 		}
 	} else {
-		return &UserAlreadyExistsError{Username: username}
+		return nil, &UserAlreadyExistsError{Username: username}
 	}
 
 	passwordHash, err := HashPassword(password)
 	if err != nil {
-		return fmt.Errorf("failed to hash password: %w", err)
+		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
+	return svc.insertUser(ctx, username, passwordHash)
+}
+
+func (svc *Service) insertUser(ctx context.Context, username, passwordHash string) (*User, error) {
 	user := &User{
 		ID:           uuid.NewString(),
 		Username:     username,
@@ -58,17 +102,69 @@ func (svc *Service) Register(ctx context.Context, username, password string) err
 		RegisteredAt: time.Now(),
 	}
 
-	err = svc.userRepo.Insert(ctx, user)
+	err := svc.bus.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := svc.userRepo.Insert(ctx, user)
+		if err != nil {
+			return fmt.Errorf("failed to insert user: %w", err)
+		}
+
+		err = svc.bus.Publish(ctx, events.UserRegistered{
+			UserID:       user.ID,
+			Username:     user.Username,
+			Remote:       IsRemoteUsername(user.Username),
+			RegisteredAt: user.RegisteredAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to publish event: %w", err)
+		}
+
+		// Authorization policies are kept in memory as well as stored, so the group is added last, when nothing
+		// before it can roll the transaction back.
+		err = svc.authzClient.AddToGroup(ctx, user.ID, authcontext.Authenticated)
+		if err != nil {
+			return fmt.Errorf("failed to add user to authenticated group: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to register user: %w", err)
+		return nil, fmt.Errorf("failed to register user: %w", err)
+	}
+
+	return user, nil
+}
+
+// RegisterRemoteUser returns the user standing in for a user of another server, registering it on first use. The
+// username is the handle of the remote user, and the user has no password, so nobody can log in as it.
+func (svc *Service) RegisterRemoteUser(ctx context.Context, username string) (*User, error) {
+	if !IsRemoteUsername(username) {
+		return nil, &InvalidUsernameError{Username: username, Reason: "must be a remote handle"}
 	}
 
-	err = svc.authzClient.AddToGroup(ctx, user.ID, authcontext.Authenticated)
+	user, err := svc.userRepo.FindByUsername(ctx, username)
+	if err == nil {
+		user.PasswordHash = "" // clear password hash before returning user
+
+		return user, nil
+	}
+
+	if _, ok := errors.AsType[*UserByUsernameNotFoundError](err); !ok {
+		return nil, fmt.Errorf("failed to find user by username: %w", err)
+	}
+
+	user, err = svc.insertUser(ctx, username, "")
 	if err != nil {
-		return fmt.Errorf("failed to add user to authenticated group: %w", err)
+		return nil, err
 	}
 
-	return nil
+	audit.RecordOrLog(ctx, svc.auditRecorder, audit.RecordRequest{
+		Action:  audit.ActionRegister,
+		Target:  "user:" + user.ID,
+		Outcome: audit.OutcomeSuccess,
+		Detail:  "remote user",
+	})
+
+	return user, nil
 }
 
 var ErrInvalidCredentials = errors.New("invalid credentials")
@@ -76,6 +172,29 @@ var ErrInvalidCredentials = errors.New("invalid credentials")
 const defaultSessionDuration = 30 * 24 * time.Hour
 
 func (svc *Service) Login(ctx context.Context, username, password string) (*Session, error) {
+	session, err := svc.login(ctx, username, password)
+	if err != nil {
+		audit.RecordOrLog(ctx, svc.auditRecorder, audit.RecordRequest{
+			Action:  audit.ActionLogin,
+			Target:  "username:" + username,
+			Outcome: audit.OutcomeOf(err),
+			Detail:  errorDetail(err),
+		})
+
+		return nil, err
+	}
+
+	audit.RecordOrLog(authcontext.WithSubject(ctx, session.UserID), svc.auditRecorder, audit.RecordRequest{
+		Action:  audit.ActionLogin,
+		Target:  "user:" + session.UserID,
+		Outcome: audit.OutcomeSuccess,
+		Detail:  "",
+	})
+
+	return session, nil
+}
+
+func (svc *Service) login(ctx context.Context, username, password string) (*Session, error) {
 	// TODO: validate username and password
 	user, err := svc.userRepo.FindByUsername(ctx, username)
 	if err != nil {
@@ -86,6 +205,11 @@ func (svc *Service) Login(ctx context.Context, username, password string) (*Sess
 		return nil, fmt.Errorf("failed to find user by username: %w", err)
 	}
 
+	// Remote users have no password.
+	if user.PasswordHash == "" {
+		return nil, ErrInvalidCredentials
+	}
+
 	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
 	if err != nil {
 		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
@@ -114,6 +238,14 @@ func (svc *Service) Login(ctx context.Context, username, password string) (*Sess
 
 func (svc *Service) Logout(ctx context.Context, sessionID string) error {
 	err := svc.sessionRepo.Delete(ctx, sessionID)
+
+	audit.RecordOrLog(ctx, svc.auditRecorder, audit.RecordRequest{
+		Action:  audit.ActionLogout,
+		Target:  "user:" + authcontext.GetSubject(ctx),
+		Outcome: audit.OutcomeOf(err),
+		Detail:  errorDetail(err),
+	})
+
 	if err != nil {
 		return fmt.Errorf("failed to delete session: %w", err)
 	}
@@ -121,6 +253,14 @@ func (svc *Service) Logout(ctx context.Context, sessionID string) error {
 	return nil
 }
 
+func errorDetail(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
+
 func (svc *Service) GetSession(ctx context.Context, sessionID string) (*Session, error) {
 	session, err := svc.sessionRepo.Find(ctx, sessionID)
 	if err != nil {
@@ -146,6 +286,31 @@ func (svc *Service) GetUser(ctx context.Context, userID string) (*User, error) {
 	return user, nil
 }
 
+// GetUsers returns the users of the IDs, for showing many users at once. Users that do not exist are left out.
+func (svc *Service) GetUsers(ctx context.Context, userIDs []string) ([]*User, error) {
+	users, err := svc.userRepo.FindMany(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find users by ids: %w", err)
+	}
+
+	for _, user := range users {
+		user.PasswordHash = "" // clear password hash before returning user
+	}
+
+	return users, nil
+}
+
+func (svc *Service) GetUserByUsername(ctx context.Context, username string) (*User, error) {
+	user, err := svc.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user by username: %w", err)
+	}
+
+	user.PasswordHash = "" // clear password hash before returning user
+
+	return user, nil
+}
+
 func (svc *Service) GetCurrentUser(ctx context.Context) (*User, error) {
 	sub := authcontext.GetSubject(ctx)
 	if sub == authcontext.Anonymous {
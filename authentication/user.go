@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -17,6 +18,8 @@ type User struct {
 type UserRepository interface {
 	Insert(ctx context.Context, user *User) (err error)
 	Find(ctx context.Context, userID string) (user *User, err error)
+	// FindMany finds the users of the IDs in one go. Users that do not exist are left out.
+	FindMany(ctx context.Context, userIDs []string) (users []*User, err error)
 	FindByUsername(ctx context.Context, username string) (user *User, err error)
 }
 
@@ -36,6 +39,20 @@ func (err UserByUsernameNotFoundError) Error() string {
 	return fmt.Sprintf("user with username %q not found", err.Username)
 }
 
+// IsRemoteUsername reports whether the username is the handle of a user of another server, like alice@example.com.
+func IsRemoteUsername(username string) bool {
+	return strings.Contains(username, "@")
+}
+
+type InvalidUsernameError struct {
+	Username string
+	Reason   string
+}
+
+func (err InvalidUsernameError) Error() string {
+	return fmt.Sprintf("username %q %s", err.Username, err.Reason)
+}
+
 type UserAlreadyExistsError struct {
 	Username string
 }
@@ -0,0 +1,295 @@
+package authorization
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Wildcard is the policy value that matches any domain, object or action.
+const Wildcard = "*"
+
+// RoleMembersLister is implemented by providers that can resolve every subject inheriting a role, directly or through
+// other roles. CachingProvider uses it to invalidate only the decisions a grouping change can affect.
+type RoleMembersLister interface {
+	ListRoleMembers(ctx context.Context, role string) (members []string, err error)
+}
+
+// CacheStats holds the counters of a CachingProvider.
+type CacheStats struct {
+	Hits    uint64
+	Misses  uint64
+	Entries int
+}
+
+// CachingProvider is an AuthorizationProvider decorator that keeps a bounded LRU cache of access decisions keyed by
+// subject, domain, object and action. Policy and grouping changes made through it invalidate the affected decisions.
+type CachingProvider struct {
+	next     AuthorizationProvider
+	capacity int
+
+	mu         sync.Mutex
+	entries    map[CheckAccessRequest]*list.Element
+	lru        *list.List
+	generation uint64
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+var _ AuthorizationProvider = (*CachingProvider)(nil)
+
+type cacheEntry struct {
+	req CheckAccessRequest
+	res CheckAccessResponse
+}
+
+func NewCachingProvider(next AuthorizationProvider, capacity int) *CachingProvider {
+	return &CachingProvider{
+		next:       next,
+		capacity:   max(capacity, 1),
+		entries:    make(map[CheckAccessRequest]*list.Element),
+		lru:        list.New(),
+		generation: 0,
+	}
+}
+
+func (cp *CachingProvider) CheckAccess(ctx context.Context, req CheckAccessRequest) (*CheckAccessResponse, error) {
+	cp.mu.Lock()
+
+	if elem, ok := cp.entries[req]; ok {
+		cp.lru.MoveToFront(elem)
+		res := elem.Value.(*cacheEntry).res //nolint:forcetypeassert
+		cp.mu.Unlock()
+
+		cp.hits.Add(1)
+
+		return &res, nil
+	}
+
+	generation := cp.generation
+	cp.mu.Unlock()
+
+	cp.misses.Add(1)
+
+	res, err := cp.next.CheckAccess(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next provider: %w", err)
+	}
+
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	// A policy change happened while the decision was being computed, so it may already be stale.
+	if generation != cp.generation {
+		return res, nil
+	}
+
+	cp.store(req, *res)
+
+	return res, nil
+}
+
+func (cp *CachingProvider) AddPolicy(ctx context.Context, reqs ...AddPolicyRequest) error {
+	err := cp.next.AddPolicy(ctx, reqs...)
+	if err != nil {
+		return fmt.Errorf("failed to call next provider: %w", err)
+	}
+
+	for _, req := range reqs {
+		err = cp.invalidatePolicy(ctx, req.Subject, req.Domain, req.Object, req.Action)
+		if err != nil {
+			return fmt.Errorf("failed to invalidate cached decisions: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (cp *CachingProvider) RemovePolicy(ctx context.Context, reqs ...RemovePolicyRequest) error {
+	err := cp.next.RemovePolicy(ctx, reqs...)
+	if err != nil {
+		return fmt.Errorf("failed to call next provider: %w", err)
+	}
+
+	for _, req := range reqs {
+		err = cp.invalidatePolicy(ctx, req.Subject, req.Domain, req.Object, req.Action)
+		if err != nil {
+			return fmt.Errorf("failed to invalidate cached decisions: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (cp *CachingProvider) AddToGroup(ctx context.Context, sub string, groups ...string) error {
+	err := cp.next.AddToGroup(ctx, sub, groups...)
+	if err != nil {
+		return fmt.Errorf("failed to call next provider: %w", err)
+	}
+
+	err = cp.invalidateSubject(ctx, sub, Wildcard)
+	if err != nil {
+		return fmt.Errorf("failed to invalidate cached decisions: %w", err)
+	}
+
+	return nil
+}
+
+func (cp *CachingProvider) RemoveFromGroup(ctx context.Context, sub string, groups ...string) error {
+	err := cp.next.RemoveFromGroup(ctx, sub, groups...)
+	if err != nil {
+		return fmt.Errorf("failed to call next provider: %w", err)
+	}
+
+	err = cp.invalidateSubject(ctx, sub, Wildcard)
+	if err != nil {
+		return fmt.Errorf("failed to invalidate cached decisions: %w", err)
+	}
+
+	return nil
+}
+
+func (cp *CachingProvider) AddToDomainGroup(ctx context.Context, sub, domain string, groups ...string) error {
+	err := cp.next.AddToDomainGroup(ctx, sub, domain, groups...)
+	if err != nil {
+		return fmt.Errorf("failed to call next provider: %w", err)
+	}
+
+	err = cp.invalidateSubject(ctx, sub, domain)
+	if err != nil {
+		return fmt.Errorf("failed to invalidate cached decisions: %w", err)
+	}
+
+	return nil
+}
+
+func (cp *CachingProvider) RemoveFromDomainGroup(ctx context.Context, sub, domain string, groups ...string) error {
+	err := cp.next.RemoveFromDomainGroup(ctx, sub, domain, groups...)
+	if err != nil {
+		return fmt.Errorf("failed to call next provider: %w", err)
+	}
+
+	err = cp.invalidateSubject(ctx, sub, domain)
+	if err != nil {
+		return fmt.Errorf("failed to invalidate cached decisions: %w", err)
+	}
+
+	return nil
+}
+
+// Stats returns the current hit and miss counters and the number of cached decisions.
+func (cp *CachingProvider) Stats() CacheStats {
+	cp.mu.Lock()
+	entries := cp.lru.Len()
+	cp.mu.Unlock()
+
+	return CacheStats{
+		Hits:    cp.hits.Load(),
+		Misses:  cp.misses.Load(),
+		Entries: entries,
+	}
+}
+
+// store must be called with cp.mu held.
+func (cp *CachingProvider) store(req CheckAccessRequest, res CheckAccessResponse) {
+	if elem, ok := cp.entries[req]; ok {
+		elem.Value.(*cacheEntry).res = res //nolint:forcetypeassert
+		cp.lru.MoveToFront(elem)
+
+		return
+	}
+
+	cp.entries[req] = cp.lru.PushFront(&cacheEntry{req: req, res: res})
+
+	for cp.lru.Len() > cp.capacity {
+		oldest := cp.lru.Back()
+		cp.lru.Remove(oldest)
+		delete(cp.entries, oldest.Value.(*cacheEntry).req) //nolint:forcetypeassert
+	}
+}
+
+// subjectMatcher returns a predicate reporting whether a subject's decisions can depend on rules granted to sub.
+// Without a RoleMembersLister every subject is considered affected.
+func (cp *CachingProvider) subjectMatcher(ctx context.Context, sub string) (func(subject string) bool, error) {
+	lister, ok := cp.next.(RoleMembersLister)
+	if !ok {
+		return func(string) bool { return true }, nil
+	}
+
+	members, err := lister.ListRoleMembers(ctx, sub)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list role members: %w", err)
+	}
+
+	subjects := make(map[string]struct{}, len(members)+1)
+	subjects[sub] = struct{}{}
+
+	for _, member := range members {
+		subjects[member] = struct{}{}
+	}
+
+	return func(subject string) bool {
+		_, ok := subjects[subject]
+
+		return ok
+	}, nil
+}
+
+func (cp *CachingProvider) invalidatePolicy(ctx context.Context, sub, domain, object, action string) error {
+	matchSubject, err := cp.subjectMatcher(ctx, sub)
+	if err != nil {
+		return err
+	}
+
+	cp.invalidate(func(req CheckAccessRequest) bool {
+		return matchSubject(req.Subject) &&
+			MatchPattern(req.Domain, domain) &&
+			MatchPattern(req.Object, object) &&
+			MatchPattern(req.Action, action)
+	})
+
+	return nil
+}
+
+func (cp *CachingProvider) invalidateSubject(ctx context.Context, sub, domain string) error {
+	matchSubject, err := cp.subjectMatcher(ctx, sub)
+	if err != nil {
+		return err
+	}
+
+	cp.invalidate(func(req CheckAccessRequest) bool {
+		return matchSubject(req.Subject) && MatchPattern(req.Domain, domain)
+	})
+
+	return nil
+}
+
+func (cp *CachingProvider) invalidate(affected func(req CheckAccessRequest) bool) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	cp.generation++
+
+	for req, elem := range cp.entries {
+		if affected(req) {
+			cp.lru.Remove(elem)
+			delete(cp.entries, req)
+		}
+	}
+}
+
+// MatchPattern reports whether value matches a policy pattern. The pattern is either a literal, the Wildcard, or a
+// prefix followed by the Wildcard.
+func MatchPattern(value, pattern string) bool {
+	if pattern == Wildcard || value == pattern {
+		return true
+	}
+
+	prefix, ok := strings.CutSuffix(pattern, Wildcard)
+
+	return ok && strings.HasPrefix(value, prefix)
+}
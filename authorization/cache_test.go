@@ -0,0 +1,174 @@
+package authorization_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	fileadapter "github.com/casbin/casbin/v3/persist/file-adapter"
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/authorization/casbin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCachingClient(t *testing.T, policy string, capacity int) (*authorization.Client, *authorization.CachingProvider) {
+	t.Helper()
+
+	tmpFile := filepath.Join(t.TempDir(), "policy.csv")
+
+	err := os.WriteFile(tmpFile, []byte(policy), 0o600)
+	require.NoError(t, err)
+
+	casbinProvider, err := casbin.NewAuthorizationProvider(fileadapter.NewAdapter(tmpFile))
+	require.NoError(t, err)
+
+	cache := authorization.NewCachingProvider(casbinProvider, capacity)
+
+	authzSvc, err := authorization.NewService(cache)
+	require.NoError(t, err)
+
+	return authorization.NewClient(authzSvc), cache
+}
+
+func TestCachingProvider(t *testing.T) {
+	ctx := context.Background()
+
+	aliceCtx := authcontext.WithSubject(ctx, "alice")
+	bobCtx := authcontext.WithSubject(ctx, "bob")
+
+	t.Run("repeated checks hit the cache", func(t *testing.T) {
+		client, cache := newCachingClient(t, "p, group1, domain1, data1, read\ng, alice, group1, *\n", 100)
+
+		require.True(t, client.CanI(aliceCtx, "domain1", "data1", "read"))
+		require.True(t, client.CanI(aliceCtx, "domain1", "data1", "read"))
+		require.False(t, client.CanI(bobCtx, "domain1", "data1", "read"))
+		require.False(t, client.CanI(bobCtx, "domain1", "data1", "read"))
+
+		stats := cache.Stats()
+		assert.Equal(t, uint64(2), stats.Hits)
+		assert.Equal(t, uint64(2), stats.Misses)
+		assert.Equal(t, 2, stats.Entries)
+	})
+
+	t.Run("add policy invalidates members of the granted group only", func(t *testing.T) {
+		client, cache := newCachingClient(t, "p, group1, domain1, data1, read\ng, alice, group1, *\n", 100)
+
+		require.False(t, client.CanI(aliceCtx, "domain1", "data2", "write"))
+		require.False(t, client.CanI(bobCtx, "domain1", "data2", "write"))
+
+		err := client.AddPolicyForSubject(ctx, "group1", "domain1", "data2", "write")
+		require.NoError(t, err)
+
+		require.True(t, client.CanI(aliceCtx, "domain1", "data2", "write"))
+		require.False(t, client.CanI(bobCtx, "domain1", "data2", "write"))
+
+		stats := cache.Stats()
+		assert.Equal(t, uint64(1), stats.Hits)
+		assert.Equal(t, uint64(3), stats.Misses)
+	})
+
+	t.Run("remove policy invalidates matching decisions", func(t *testing.T) {
+		client, _ := newCachingClient(t, "p, group1, domain1, data1, read\ng, alice, group1, *\n", 100)
+
+		require.True(t, client.CanI(aliceCtx, "domain1", "data1", "read"))
+
+		err := client.RemovePolicyForSubject(ctx, "group1", "domain1", "data1", "read")
+		require.NoError(t, err)
+
+		require.False(t, client.CanI(aliceCtx, "domain1", "data1", "read"))
+	})
+
+	t.Run("group changes invalidate the subject", func(t *testing.T) {
+		client, cache := newCachingClient(t, "p, group1, domain1, data1, read\n", 100)
+
+		require.False(t, client.CanI(aliceCtx, "domain1", "data1", "read"))
+		require.False(t, client.CanI(bobCtx, "domain1", "data1", "read"))
+
+		err := client.AddToGroup(ctx, "alice", "group1")
+		require.NoError(t, err)
+
+		require.True(t, client.CanI(aliceCtx, "domain1", "data1", "read"))
+		require.False(t, client.CanI(bobCtx, "domain1", "data1", "read"))
+
+		err = client.RemoveFromGroup(ctx, "alice", "group1")
+		require.NoError(t, err)
+
+		require.False(t, client.CanI(aliceCtx, "domain1", "data1", "read"))
+
+		stats := cache.Stats()
+		assert.Equal(t, uint64(1), stats.Hits)
+		assert.Equal(t, uint64(4), stats.Misses)
+	})
+
+	t.Run("group changes invalidate inheriting subjects", func(t *testing.T) {
+		client, _ := newCachingClient(t, "p, group2, domain1, data1, read\ng, alice, group1, *\n", 100)
+
+		require.False(t, client.CanI(aliceCtx, "domain1", "data1", "read"))
+
+		err := client.AddToGroup(ctx, "group1", "group2")
+		require.NoError(t, err)
+
+		require.True(t, client.CanI(aliceCtx, "domain1", "data1", "read"))
+	})
+
+	t.Run("domain group changes invalidate that domain only", func(t *testing.T) {
+		client, cache := newCachingClient(t, "p, moderator, rooms/*, -, kick\n", 100)
+
+		require.False(t, client.CanI(aliceCtx, "rooms/1", "", "kick"))
+		require.False(t, client.CanI(aliceCtx, "rooms/2", "", "kick"))
+
+		err := client.AddToDomainGroup(ctx, "alice", "rooms/1", "moderator")
+		require.NoError(t, err)
+
+		require.True(t, client.CanI(aliceCtx, "rooms/1", "", "kick"))
+		require.False(t, client.CanI(aliceCtx, "rooms/2", "", "kick"))
+		assert.Equal(t, uint64(1), cache.Stats().Hits)
+
+		err = client.RemoveFromDomainGroup(ctx, "alice", "rooms/1", "moderator")
+		require.NoError(t, err)
+
+		require.False(t, client.CanI(aliceCtx, "rooms/1", "", "kick"))
+	})
+
+	t.Run("capacity is bounded", func(t *testing.T) {
+		client, cache := newCachingClient(t, "p, group1, domain1, data1, read\n", 2)
+
+		client.CanI(ctx, "domain1", "data1", "read")
+		client.CanI(ctx, "domain1", "data2", "read")
+		client.CanI(ctx, "domain1", "data3", "read")
+
+		assert.Equal(t, 2, cache.Stats().Entries)
+
+		client.CanI(ctx, "domain1", "data1", "read")
+
+		assert.Equal(t, uint64(0), cache.Stats().Hits)
+	})
+}
+
+func TestMatchPattern(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		value   string
+		pattern string
+		matches bool
+	}{
+		{value: "data1", pattern: "data1", matches: true},
+		{value: "data1", pattern: "data2", matches: false},
+		{value: "data1", pattern: "*", matches: true},
+		{value: "", pattern: "*", matches: true},
+		{value: "communities/1", pattern: "communities/*", matches: true},
+		{value: "contents", pattern: "communities/*", matches: false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.value+" "+tc.pattern, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.matches, authorization.MatchPattern(tc.value, tc.pattern))
+		})
+	}
+}
@@ -1,17 +1,36 @@
 package casbin
 
 import (
-	"database/sql"
-	"fmt"
-
-	sqladapter "github.com/Blank-Xu/sql-adapter"
+	"github.com/casbin/casbin/v3/model"
+	"github.com/casbin/casbin/v3/persist"
 )
 
-func NewSQLAdapter(sqlDB *sql.DB, dbType, tableName string) (*sqladapter.Adapter, error) {
-	adapter, err := sqladapter.NewAdapter(sqlDB, dbType, tableName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to casbin database: %w", err)
-	}
+// MemoryAdapter keeps policies only in the enforcer's model. It is meant for evaluating a policy without a database,
+// such as in policy tests.
+type MemoryAdapter struct{}
+
+var _ persist.Adapter = (*MemoryAdapter)(nil)
+
+func NewMemoryAdapter() *MemoryAdapter {
+	return &MemoryAdapter{}
+}
+
+func (adapter *MemoryAdapter) LoadPolicy(_ model.Model) error {
+	return nil
+}
+
+func (adapter *MemoryAdapter) SavePolicy(_ model.Model) error {
+	return nil
+}
+
+func (adapter *MemoryAdapter) AddPolicy(_, _ string, _ []string) error {
+	return nil
+}
+
+func (adapter *MemoryAdapter) RemovePolicy(_, _ string, _ []string) error {
+	return nil
+}
 
-	return adapter, nil
+func (adapter *MemoryAdapter) RemoveFilteredPolicy(_, _ string, _ int, _ ...string) error {
+	return nil
 }
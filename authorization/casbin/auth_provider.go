@@ -10,6 +10,7 @@ import (
 	"github.com/casbin/casbin/v3"
 	"github.com/casbin/casbin/v3/model"
 	"github.com/casbin/casbin/v3/persist"
+	"github.com/casbin/casbin/v3/util"
 	"github.com/nasermirzaei89/scribble/authorization"
 )
 
@@ -20,8 +21,64 @@ var casbinModelContent string
 
 type AuthorizationProvider struct {
 	enforcer *casbin.Enforcer
+	// writer changes policies, in the transaction of the context when the adapter takes part in it.
+	writer policyWriter
 }
 
+type policyWriter interface {
+	AddPoliciesCtx(ctx context.Context, rules [][]string) (bool, error)
+	AddGroupingPoliciesCtx(ctx context.Context, rules [][]string) (bool, error)
+	RemovePoliciesCtx(ctx context.Context, rules [][]string) (bool, error)
+	RemoveGroupingPoliciesCtx(ctx context.Context, rules [][]string) (bool, error)
+}
+
+// enforcerWriter changes the policies of adapters that do not take a context.
+type enforcerWriter struct {
+	enforcer *casbin.Enforcer
+}
+
+func (writer enforcerWriter) AddPoliciesCtx(_ context.Context, rules [][]string) (bool, error) {
+	return writer.enforcer.AddPolicies(rules) //nolint:wrapcheck
+}
+
+func (writer enforcerWriter) AddGroupingPoliciesCtx(_ context.Context, rules [][]string) (bool, error) {
+	return writer.enforcer.AddGroupingPolicies(rules) //nolint:wrapcheck
+}
+
+func (writer enforcerWriter) RemovePoliciesCtx(_ context.Context, rules [][]string) (bool, error) {
+	return writer.enforcer.RemovePolicies(rules) //nolint:wrapcheck
+}
+
+func (writer enforcerWriter) RemoveGroupingPoliciesCtx(_ context.Context, rules [][]string) (bool, error) {
+	return writer.enforcer.RemoveGroupingPolicies(rules) //nolint:wrapcheck
+}
+
+// newEnforcer returns the enforcer of the model and the adapter, and what writes its policies.
+func newEnforcer(casbinModel model.Model, persistAdapter persist.Adapter) (*casbin.Enforcer, policyWriter, error) {
+	if _, ok := persistAdapter.(persist.ContextBatchAdapter); !ok {
+		enforcer, err := casbin.NewEnforcer(casbinModel, persistAdapter)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create enforcer: %w", err)
+		}
+
+		return enforcer, enforcerWriter{enforcer: enforcer}, nil
+	}
+
+	contextEnforcer, err := casbin.NewContextEnforcer(casbinModel, persistAdapter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create context enforcer: %w", err)
+	}
+
+	enforcer := contextEnforcer.(*casbin.ContextEnforcer) //nolint:forcetypeassert
+
+	return enforcer.Enforcer, enforcer, nil
+}
+
+var (
+	_ authorization.AuthorizationProvider = (*AuthorizationProvider)(nil)
+	_ authorization.RoleMembersLister     = (*AuthorizationProvider)(nil)
+)
+
 func NewAuthorizationProvider(persistAdapter persist.Adapter) (*AuthorizationProvider, error) {
 	// TODO: validate arguments
 	casbinModel, err := model.NewModelFromString(casbinModelContent)
@@ -29,7 +86,7 @@ func NewAuthorizationProvider(persistAdapter persist.Adapter) (*AuthorizationPro
 		return nil, fmt.Errorf("failed to load casbin model: %w", err)
 	}
 
-	enforcer, err := casbin.NewEnforcer(casbinModel, persistAdapter)
+	enforcer, writer, err := newEnforcer(casbinModel, persistAdapter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create casbin enforcer: %w", err)
 	}
@@ -37,6 +94,9 @@ func NewAuthorizationProvider(persistAdapter persist.Adapter) (*AuthorizationPro
 	enforcer.EnableAutoSave(true)
 	enforcer.EnableAutoBuildRoleLinks(true)
 
+	// Group bindings in a domain pattern, such as "*" for global groups, apply to every domain matching it.
+	enforcer.AddNamedDomainMatchingFunc("g", "keyMatch", util.KeyMatch)
+
 	err = enforcer.LoadPolicy()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load db policy: %w", err)
@@ -44,6 +104,7 @@ func NewAuthorizationProvider(persistAdapter persist.Adapter) (*AuthorizationPro
 
 	return &AuthorizationProvider{
 		enforcer: enforcer,
+		writer:   writer,
 	}, nil
 }
 
@@ -78,7 +139,7 @@ func (ap *AuthorizationProvider) AddPolicy(ctx context.Context, reqs ...authoriz
 		rules = append(rules, []string{req.Subject, req.Domain, req.Object, req.Action})
 	}
 
-	_, err := ap.enforcer.AddPolicies(rules)
+	_, err := ap.writer.AddPoliciesCtx(ctx, rules)
 	if err != nil {
 		return fmt.Errorf("failed to add policies: %w", err)
 	}
@@ -86,14 +147,19 @@ func (ap *AuthorizationProvider) AddPolicy(ctx context.Context, reqs ...authoriz
 	return nil
 }
 
+// AddToGroup adds the subject to the groups in every domain.
 func (ap *AuthorizationProvider) AddToGroup(ctx context.Context, sub string, groups ...string) error {
+	return ap.AddToDomainGroup(ctx, sub, authorization.Wildcard, groups...)
+}
+
+func (ap *AuthorizationProvider) AddToDomainGroup(ctx context.Context, sub, domain string, groups ...string) error {
 	rules := make([][]string, 0, len(groups))
 
 	for _, group := range groups {
-		rules = append(rules, []string{sub, group})
+		rules = append(rules, []string{sub, group, domain})
 	}
 
-	_, err := ap.enforcer.AddGroupingPolicies(rules)
+	_, err := ap.writer.AddGroupingPoliciesCtx(ctx, rules)
 	if err != nil {
 		return fmt.Errorf("failed to add grouping policies: %w", err)
 	}
@@ -112,7 +178,7 @@ func (ap *AuthorizationProvider) RemovePolicy(ctx context.Context, reqs ...autho
 		rules = append(rules, []string{req.Subject, req.Domain, req.Object, req.Action})
 	}
 
-	_, err := ap.enforcer.RemovePolicies(rules)
+	_, err := ap.writer.RemovePoliciesCtx(ctx, rules)
 	if err != nil {
 		return fmt.Errorf("failed to remove policies: %w", err)
 	}
@@ -120,14 +186,19 @@ func (ap *AuthorizationProvider) RemovePolicy(ctx context.Context, reqs ...autho
 	return nil
 }
 
+// RemoveFromGroup removes the subject from the groups it was added to in every domain.
 func (ap *AuthorizationProvider) RemoveFromGroup(ctx context.Context, sub string, groups ...string) error {
+	return ap.RemoveFromDomainGroup(ctx, sub, authorization.Wildcard, groups...)
+}
+
+func (ap *AuthorizationProvider) RemoveFromDomainGroup(ctx context.Context, sub, domain string, groups ...string) error {
 	rules := make([][]string, 0, len(groups))
 
 	for _, group := range groups {
-		rules = append(rules, []string{sub, group})
+		rules = append(rules, []string{sub, group, domain})
 	}
 
-	_, err := ap.enforcer.RemoveGroupingPolicies(rules)
+	_, err := ap.writer.RemoveGroupingPoliciesCtx(ctx, rules)
 	if err != nil {
 		return fmt.Errorf("failed to remove grouping policies: %w", err)
 	}
@@ -135,6 +206,42 @@ func (ap *AuthorizationProvider) RemoveFromGroup(ctx context.Context, sub string
 	return nil
 }
 
+// ListRoleMembers returns every subject inheriting the role in any domain. The result is a superset of the members in
+// a single domain, which is what cache invalidation needs.
+func (ap *AuthorizationProvider) ListRoleMembers(ctx context.Context, role string) ([]string, error) {
+	rules, err := ap.enforcer.GetGroupingPolicy()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get grouping policies: %w", err)
+	}
+
+	directMembers := make(map[string][]string)
+
+	for _, rule := range rules {
+		directMembers[rule[1]] = append(directMembers[rule[1]], rule[0])
+	}
+
+	seen := map[string]struct{}{role: {}}
+	queue := []string{role}
+	members := make([]string, 0)
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, member := range directMembers[current] {
+			if _, ok := seen[member]; ok {
+				continue
+			}
+
+			seen[member] = struct{}{}
+			members = append(members, member)
+			queue = append(queue, member)
+		}
+	}
+
+	return members, nil
+}
+
 func (ap *AuthorizationProvider) AddPolicyFromCSV(ctx context.Context, casbinPolicyContent string) error {
 	err := addPolicyFromString(ap.enforcer, casbinPolicyContent)
 	if err != nil {
@@ -187,7 +294,13 @@ func addPolicyFromRecord(enforcer *casbin.Enforcer, record []string) error {
 		}
 
 	case "g":
-		err := addGroupingPolicyIfNotExists(enforcer, record[1:]...)
+		params := record[1:]
+		if len(params) == 2 {
+			// Groups without a domain are global.
+			params = append(params, authorization.Wildcard)
+		}
+
+		err := addGroupingPolicyIfNotExists(enforcer, params...)
 		if err != nil {
 			return fmt.Errorf("failed to add grouping policy if not exists: %w", err)
 		}
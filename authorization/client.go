@@ -60,6 +60,70 @@ func (c *Client) Can(ctx context.Context, subject, domain, object, action string
 	return err == nil && res.Allowed
 }
 
+// ObjectAction is an action on an object, used to check many permissions within a domain at once.
+type ObjectAction struct {
+	Object string
+	Action string
+}
+
+// CheckAccessBatch checks whether the current user in the context may perform each of the object/action pairs within
+// the domain. The result has an entry for every requested pair.
+func (c *Client) CheckAccessBatch(ctx context.Context, domain string, checks ...ObjectAction) (
+	map[ObjectAction]bool,
+	error,
+) {
+	subject := authcontext.GetSubject(ctx)
+
+	reqs := make([]CheckAccessRequest, 0, len(checks))
+
+	for _, check := range checks {
+		reqs = append(reqs, CheckAccessRequest{
+			Subject: subject,
+			Domain:  domain,
+			Object:  check.Object,
+			Action:  check.Action,
+		})
+	}
+
+	responses, err := c.authzSvc.CheckAccessBatch(ctx, reqs...)
+	if err != nil {
+		return nil, fmt.Errorf("error on check permissions: %w", err)
+	}
+
+	result := make(map[ObjectAction]bool, len(checks))
+
+	for i, check := range checks {
+		result[check] = responses[i].Allowed
+	}
+
+	return result, nil
+}
+
+// AllowedActions returns the subset of actions the current user in the context may perform on the object within the
+// domain, keeping their order.
+func (c *Client) AllowedActions(ctx context.Context, domain, object string, actions ...string) ([]string, error) {
+	checks := make([]ObjectAction, 0, len(actions))
+
+	for _, action := range actions {
+		checks = append(checks, ObjectAction{Object: object, Action: action})
+	}
+
+	allowed, err := c.CheckAccessBatch(ctx, domain, checks...)
+	if err != nil {
+		return nil, fmt.Errorf("error on check allowed actions: %w", err)
+	}
+
+	result := make([]string, 0, len(actions))
+
+	for _, check := range checks {
+		if allowed[check] {
+			result = append(result, check.Action)
+		}
+	}
+
+	return result, nil
+}
+
 func (c *Client) AddPolicyForSubject(ctx context.Context, subject, domain, object string, action ...string) error {
 	reqs := make([]AddPolicyRequest, 0, len(action))
 
@@ -117,3 +181,21 @@ func (c *Client) RemoveFromGroup(ctx context.Context, sub string, group ...strin
 
 	return nil
 }
+
+func (c *Client) AddToDomainGroup(ctx context.Context, sub, domain string, group ...string) error {
+	err := c.authzSvc.AddToDomainGroup(ctx, sub, domain, group...)
+	if err != nil {
+		return fmt.Errorf("error on add to domain group: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) RemoveFromDomainGroup(ctx context.Context, sub, domain string, group ...string) error {
+	err := c.authzSvc.RemoveFromDomainGroup(ctx, sub, domain, group...)
+	if err != nil {
+		return fmt.Errorf("error on remove from domain group: %w", err)
+	}
+
+	return nil
+}
@@ -19,7 +19,7 @@ func TestClient_CheckAccess(t *testing.T) {
 
 	adapter := stringadapter.NewAdapter(`p, group1, domain1, data1, read
 p, group1, domain1, data2, write
-g, alice, group1
+g, alice, group1, *
 `)
 
 	casbinProvider, err := casbin.NewAuthorizationProvider(adapter)
@@ -65,7 +65,7 @@ func TestClient_CanI(t *testing.T) {
 
 	adapter := stringadapter.NewAdapter(`p, group1, domain1, data1, read
 p, group1, domain1, data2, write
-g, alice, group1
+g, alice, group1, *
 `)
 
 	casbinProvider, err := casbin.NewAuthorizationProvider(adapter)
@@ -102,7 +102,7 @@ func TestClient_Can(t *testing.T) {
 
 	adapter := stringadapter.NewAdapter(`p, group1, domain1, data1, read
 p, group1, domain1, data2, write
-g, alice, group1
+g, alice, group1, *
 `)
 
 	casbinProvider, err := casbin.NewAuthorizationProvider(adapter)
@@ -207,3 +207,137 @@ func TestClient_AddToGroup(t *testing.T) {
 		require.ErrorAs(t, err, &accessDeniedErr)
 	})
 }
+
+func TestClient_CheckAccessBatch(t *testing.T) {
+	ctx := context.Background()
+
+	adapter := stringadapter.NewAdapter(`p, group1, domain1, data1, read
+p, group1, domain1, data2, write
+g, alice, group1, *
+`)
+
+	casbinProvider, err := casbin.NewAuthorizationProvider(adapter)
+	require.NoError(t, err)
+
+	authzSvc, err := authorization.NewService(casbinProvider)
+	require.NoError(t, err)
+
+	client := authorization.NewClient(authzSvc)
+
+	checks := []authorization.ObjectAction{
+		{Object: "data1", Action: "read"},
+		{Object: "data1", Action: "write"},
+		{Object: "data2", Action: "write"},
+	}
+
+	t.Run("member of group", func(t *testing.T) {
+		allowed, err := client.CheckAccessBatch(authcontext.WithSubject(ctx, "alice"), "domain1", checks...)
+		require.NoError(t, err)
+		require.Len(t, allowed, 3)
+		require.True(t, allowed[checks[0]])
+		require.False(t, allowed[checks[1]])
+		require.True(t, allowed[checks[2]])
+	})
+
+	t.Run("another user", func(t *testing.T) {
+		allowed, err := client.CheckAccessBatch(authcontext.WithSubject(ctx, "bob"), "domain1", checks...)
+		require.NoError(t, err)
+		require.Len(t, allowed, 3)
+
+		for _, check := range checks {
+			require.False(t, allowed[check])
+		}
+	})
+}
+
+func TestClient_AllowedActions(t *testing.T) {
+	ctx := context.Background()
+
+	adapter := stringadapter.NewAdapter(`p, group1, domain1, data1, read
+p, group1, domain1, data1, write
+p, group1, domain1, data2, write
+g, alice, group1, *
+`)
+
+	casbinProvider, err := casbin.NewAuthorizationProvider(adapter)
+	require.NoError(t, err)
+
+	authzSvc, err := authorization.NewService(casbinProvider)
+	require.NoError(t, err)
+
+	client := authorization.NewClient(authzSvc)
+
+	t.Run("member of group", func(t *testing.T) {
+		actions, err := client.AllowedActions(
+			authcontext.WithSubject(ctx, "alice"),
+			"domain1",
+			"data1",
+			"delete",
+			"write",
+			"read",
+		)
+		require.NoError(t, err)
+		require.Equal(t, []string{"write", "read"}, actions)
+	})
+
+	t.Run("anonymous", func(t *testing.T) {
+		actions, err := client.AllowedActions(ctx, "domain1", "data1", "read", "write")
+		require.NoError(t, err)
+		require.Empty(t, actions)
+	})
+}
+
+func TestClient_DomainGroups(t *testing.T) {
+	ctx := context.Background()
+
+	tmpFile := filepath.Join(t.TempDir(), "policy.csv")
+
+	err := os.WriteFile(tmpFile, []byte(`p, member, rooms/*, -, post
+p, moderator, rooms/*, -, kick
+g, moderator, member, *
+`), 0o600)
+	require.NoError(t, err)
+
+	casbinProvider, err := casbin.NewAuthorizationProvider(fileadapter.NewAdapter(tmpFile))
+	require.NoError(t, err)
+
+	authzSvc, err := authorization.NewService(casbinProvider)
+	require.NoError(t, err)
+
+	client := authorization.NewClient(authzSvc)
+
+	err = client.AddToDomainGroup(ctx, "alice", "rooms/1", "moderator")
+	require.NoError(t, err)
+
+	err = client.AddToDomainGroup(ctx, "bob", "rooms/2", "member")
+	require.NoError(t, err)
+
+	t.Run("role applies in its domain", func(t *testing.T) {
+		require.True(t, client.Can(ctx, "alice", "rooms/1", "", "kick"))
+		require.True(t, client.Can(ctx, "alice", "rooms/1", "", "post"))
+		require.True(t, client.Can(ctx, "bob", "rooms/2", "", "post"))
+	})
+
+	t.Run("role does not apply in other domains", func(t *testing.T) {
+		require.False(t, client.Can(ctx, "alice", "rooms/2", "", "kick"))
+		require.False(t, client.Can(ctx, "alice", "rooms/2", "", "post"))
+		require.False(t, client.Can(ctx, "bob", "rooms/1", "", "post"))
+		require.False(t, client.Can(ctx, "bob", "rooms/2", "", "kick"))
+	})
+
+	t.Run("global groups apply in every domain", func(t *testing.T) {
+		err := client.AddToGroup(ctx, "carol", "member")
+		require.NoError(t, err)
+
+		require.True(t, client.Can(ctx, "carol", "rooms/1", "", "post"))
+		require.True(t, client.Can(ctx, "carol", "rooms/2", "", "post"))
+		require.False(t, client.Can(ctx, "carol", "rooms/1", "", "kick"))
+	})
+
+	t.Run("removing a domain role", func(t *testing.T) {
+		err := client.RemoveFromDomainGroup(ctx, "alice", "rooms/1", "moderator")
+		require.NoError(t, err)
+
+		require.False(t, client.Can(ctx, "alice", "rooms/1", "", "kick"))
+	})
+}
@@ -0,0 +1,237 @@
+// Package policytest evaluates expected access decisions against an authorization policy, so that policy changes can
+// be reviewed alongside the outcomes they are supposed to produce.
+//
+// Assertion files have one assertion per line in the form
+//
+//	subject, domain, object, action -> allow|deny
+//
+// where subject is a user or a group, and object is "-" for domain-wide actions. Lines in the policy's own grouping form,
+//
+//	g, user, group, domain
+//
+// bind fixture users to groups before the assertions are evaluated. Empty lines and lines starting with "#" are
+// ignored.
+package policytest
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/authorization/casbin"
+)
+
+type Expectation string
+
+const (
+	ExpectAllow Expectation = "allow"
+	ExpectDeny  Expectation = "deny"
+)
+
+var arrows = []string{"->", "→"}
+
+type Assertion struct {
+	Line    int
+	Subject string
+	Domain  string
+	Object  string
+	Action  string
+	Expect  Expectation
+}
+
+func (assertion Assertion) String() string {
+	return fmt.Sprintf(
+		"%s, %s, %s, %s -> %s",
+		assertion.Subject,
+		assertion.Domain,
+		assertion.Object,
+		assertion.Action,
+		assertion.Expect,
+	)
+}
+
+// Suite is the content of an assertions file.
+type Suite struct {
+	// Fixtures holds the grouping lines of the file in policy CSV form.
+	Fixtures   string
+	Assertions []Assertion
+}
+
+type InvalidAssertionError struct {
+	Line   int
+	Reason string
+}
+
+func (err InvalidAssertionError) Error() string {
+	return fmt.Sprintf("invalid assertion on line %d: %s", err.Line, err.Reason)
+}
+
+// LoadAssertions reads the suite from the file at path.
+func LoadAssertions(path string) (*Suite, error) {
+	file, err := os.Open(path) //nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("failed to open assertions file %q: %w", path, err)
+	}
+
+	defer func() {
+		err := file.Close()
+		if err != nil {
+			slog.Error("failed to close assertions file", "path", path, "error", err)
+		}
+	}()
+
+	suite, err := ParseAssertions(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse assertions file %q: %w", path, err)
+	}
+
+	return suite, nil
+}
+
+func ParseAssertions(r io.Reader) (*Suite, error) {
+	var fixtures strings.Builder
+
+	assertions := make([]Assertion, 0)
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "g,") {
+			fixtures.WriteString(line + "\n")
+
+			continue
+		}
+
+		assertion, err := parseAssertion(lineNumber, line)
+		if err != nil {
+			return nil, err
+		}
+
+		assertions = append(assertions, assertion)
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan assertions: %w", err)
+	}
+
+	return &Suite{Fixtures: fixtures.String(), Assertions: assertions}, nil
+}
+
+func parseAssertion(lineNumber int, line string) (Assertion, error) {
+	var (
+		request, expectation string
+		found                bool
+	)
+
+	for _, arrow := range arrows {
+		request, expectation, found = strings.Cut(line, arrow)
+		if found {
+			break
+		}
+	}
+
+	if !found {
+		return Assertion{}, InvalidAssertionError{Line: lineNumber, Reason: `missing "->" before the expectation`}
+	}
+
+	fields := strings.Split(request, ",")
+	if len(fields) != 4 {
+		return Assertion{}, InvalidAssertionError{
+			Line:   lineNumber,
+			Reason: fmt.Sprintf("expected subject, domain, object and action, got %d fields", len(fields)),
+		}
+	}
+
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+		if fields[i] == "" {
+			return Assertion{}, InvalidAssertionError{Line: lineNumber, Reason: "empty field"}
+		}
+	}
+
+	expect := Expectation(strings.ToLower(strings.TrimSpace(expectation)))
+	if expect != ExpectAllow && expect != ExpectDeny {
+		return Assertion{}, InvalidAssertionError{
+			Line:   lineNumber,
+			Reason: fmt.Sprintf("expectation must be %q or %q, got %q", ExpectAllow, ExpectDeny, expect),
+		}
+	}
+
+	return Assertion{
+		Line:    lineNumber,
+		Subject: fields[0],
+		Domain:  fields[1],
+		Object:  fields[2],
+		Action:  fields[3],
+		Expect:  expect,
+	}, nil
+}
+
+type Result struct {
+	Assertion Assertion
+	Allowed   bool
+}
+
+func (result Result) Passed() bool {
+	return result.Allowed == (result.Assertion.Expect == ExpectAllow)
+}
+
+func (result Result) String() string {
+	got := ExpectDeny
+	if result.Allowed {
+		got = ExpectAllow
+	}
+
+	return fmt.Sprintf("line %d: %s, got %s", result.Assertion.Line, result.Assertion, got)
+}
+
+// Evaluate loads the policy and the suite's fixtures into an in-memory casbin.AuthorizationProvider and checks every
+// assertion against it.
+func Evaluate(ctx context.Context, policyContent string, suite *Suite) ([]Result, error) {
+	provider, err := casbin.NewAuthorizationProvider(casbin.NewMemoryAdapter())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create authorization provider: %w", err)
+	}
+
+	err = provider.AddPolicyFromCSV(ctx, policyContent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add policy from csv: %w", err)
+	}
+
+	err = provider.AddPolicyFromCSV(ctx, suite.Fixtures)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add fixtures: %w", err)
+	}
+
+	results := make([]Result, 0, len(suite.Assertions))
+
+	for _, assertion := range suite.Assertions {
+		res, err := provider.CheckAccess(ctx, authorization.CheckAccessRequest{
+			Subject: assertion.Subject,
+			Domain:  assertion.Domain,
+			Object:  assertion.Object,
+			Action:  assertion.Action,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to check access on line %d: %w", assertion.Line, err)
+		}
+
+		results = append(results, Result{Assertion: assertion, Allowed: res.Allowed})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,82 @@
+package policytest_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nasermirzaei89/scribble/authorization/policytest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAssertions(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		suite, err := policytest.ParseAssertions(strings.NewReader(`# comment
+g, alice, editors, example/domain
+system:anonymous, example/domain, -, read -> allow
+system:anonymous, example/domain, obj1, write → DENY
+`))
+		require.NoError(t, err)
+		assert.Equal(t, "g, alice, editors, example/domain\n", suite.Fixtures)
+
+		assertions := suite.Assertions
+		require.Len(t, assertions, 2)
+
+		assert.Equal(t, policytest.Assertion{
+			Line:    3,
+			Subject: "system:anonymous",
+			Domain:  "example/domain",
+			Object:  "-",
+			Action:  "read",
+			Expect:  policytest.ExpectAllow,
+		}, assertions[0])
+		assert.Equal(t, "obj1", assertions[1].Object)
+		assert.Equal(t, policytest.ExpectDeny, assertions[1].Expect)
+	})
+
+	tt := []struct {
+		name  string
+		input string
+	}{
+		{name: "Missing arrow", input: "sub, dom, obj, act allow"},
+		{name: "Missing field", input: "sub, dom, act -> allow"},
+		{name: "Empty field", input: "sub, , obj, act -> allow"},
+		{name: "Unknown expectation", input: "sub, dom, obj, act -> maybe"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := policytest.ParseAssertions(strings.NewReader("\n" + tc.input))
+			require.Error(t, err)
+
+			invalidAssertionErr := policytest.InvalidAssertionError{}
+			require.ErrorAs(t, err, &invalidAssertionErr)
+			assert.Equal(t, 2, invalidAssertionErr.Line)
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	policy := `g, alice, editors
+p, editors, example/domain, -, write
+p, system:group:root, *, *, *
+`
+
+	suite, err := policytest.ParseAssertions(strings.NewReader(`alice, example/domain, -, write -> allow
+bob, example/domain, -, write -> allow
+system:group:root, other/domain, obj1, delete -> allow
+g, carol, editors, other/domain
+carol, example/domain, -, write -> deny
+`))
+	require.NoError(t, err)
+
+	results, err := policytest.Evaluate(t.Context(), policy, suite)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	assert.True(t, results[0].Passed())
+	assert.False(t, results[1].Passed())
+	assert.True(t, results[2].Passed())
+	assert.True(t, results[3].Passed())
+	assert.Equal(t, "line 2: bob, example/domain, -, write -> allow, got deny", results[1].String())
+}
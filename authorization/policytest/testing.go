@@ -0,0 +1,33 @@
+package policytest
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Run evaluates the assertions file against the policy and reports every assertion as a subtest.
+func Run(t *testing.T, policyContent, assertionsPath string) {
+	t.Helper()
+
+	suite, err := LoadAssertions(assertionsPath)
+	if err != nil {
+		t.Fatalf("failed to load assertions: %v", err)
+	}
+
+	if len(suite.Assertions) == 0 {
+		t.Fatalf("no assertions found in %q", assertionsPath)
+	}
+
+	results, err := Evaluate(t.Context(), policyContent, suite)
+	if err != nil {
+		t.Fatalf("failed to evaluate assertions: %v", err)
+	}
+
+	for _, result := range results {
+		t.Run(fmt.Sprintf("line %d", result.Assertion.Line), func(t *testing.T) {
+			if !result.Passed() {
+				t.Errorf("%s", result)
+			}
+		})
+	}
+}
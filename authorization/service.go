@@ -15,6 +15,8 @@ type AuthorizationProvider interface {
 	AddToGroup(ctx context.Context, sub string, groups ...string) (err error)
 	RemovePolicy(ctx context.Context, reqs ...RemovePolicyRequest) (err error)
 	RemoveFromGroup(ctx context.Context, sub string, groups ...string) (err error)
+	AddToDomainGroup(ctx context.Context, sub, domain string, groups ...string) (err error)
+	RemoveFromDomainGroup(ctx context.Context, sub, domain string, groups ...string) (err error)
 }
 
 func NewService(authzProvider AuthorizationProvider) (*Service, error) {
@@ -78,6 +80,22 @@ func (svc *Service) CheckAccess(ctx context.Context, req CheckAccessRequest) (*C
 	return res, nil
 }
 
+// CheckAccessBatch evaluates several requests in one call. Responses are returned in the order of the requests.
+func (svc *Service) CheckAccessBatch(ctx context.Context, reqs ...CheckAccessRequest) ([]*CheckAccessResponse, error) {
+	responses := make([]*CheckAccessResponse, 0, len(reqs))
+
+	for _, req := range reqs {
+		res, err := svc.authzProvider.CheckAccess(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check permission: %w", err)
+		}
+
+		responses = append(responses, res)
+	}
+
+	return responses, nil
+}
+
 type AddPolicyRequest struct {
 	Subject string
 	Domain  string
@@ -127,3 +145,22 @@ func (svc *Service) RemoveFromGroup(ctx context.Context, sub string, groups ...s
 
 	return nil
 }
+
+// AddToDomainGroup adds the subject to the groups only within the domain. Domain may be a pattern like "prefix/*".
+func (svc *Service) AddToDomainGroup(ctx context.Context, sub, domain string, groups ...string) error {
+	err := svc.authzProvider.AddToDomainGroup(ctx, sub, domain, groups...)
+	if err != nil {
+		return fmt.Errorf("failed to add domain grouping policies: %w", err)
+	}
+
+	return nil
+}
+
+func (svc *Service) RemoveFromDomainGroup(ctx context.Context, sub, domain string, groups ...string) error {
+	err := svc.authzProvider.RemoveFromDomainGroup(ctx, sub, domain, groups...)
+	if err != nil {
+		return fmt.Errorf("failed to remove domain grouping policies: %w", err)
+	}
+
+	return nil
+}
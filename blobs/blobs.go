@@ -0,0 +1,32 @@
+// Package blobs stores files, like uploaded images, by key.
+package blobs
+
+import (
+	"context"
+	"fmt"
+)
+
+// Store stores blobs by key. Keys are slash-separated paths, like "emojis/wave".
+type Store interface {
+	// Put stores the data under the key, replacing any blob already there.
+	Put(ctx context.Context, key string, data []byte) (err error)
+	Get(ctx context.Context, key string) (data []byte, err error)
+	// Delete removes the blob under the key. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) (err error)
+}
+
+type BlobNotFoundError struct {
+	Key string
+}
+
+func (err BlobNotFoundError) Error() string {
+	return fmt.Sprintf("blob %q not found", err.Key)
+}
+
+type InvalidKeyError struct {
+	Key string
+}
+
+func (err InvalidKeyError) Error() string {
+	return fmt.Sprintf("invalid blob key: %q", err.Key)
+}
@@ -0,0 +1,41 @@
+package blobs_test
+
+import (
+	"testing"
+
+	"github.com/nasermirzaei89/scribble/blobs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore(t *testing.T) {
+	ctx := t.Context()
+	store := blobs.NewLocalStore(t.TempDir())
+
+	_, err := store.Get(ctx, "emojis/wave")
+	require.ErrorAs(t, err, &blobs.BlobNotFoundError{})
+
+	err = store.Put(ctx, "emojis/wave", []byte("first"))
+	require.NoError(t, err)
+
+	err = store.Put(ctx, "emojis/wave", []byte("second"))
+	require.NoError(t, err)
+
+	data, err := store.Get(ctx, "emojis/wave")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("second"), data)
+
+	err = store.Delete(ctx, "emojis/wave")
+	require.NoError(t, err)
+
+	_, err = store.Get(ctx, "emojis/wave")
+	require.ErrorAs(t, err, &blobs.BlobNotFoundError{})
+
+	err = store.Delete(ctx, "emojis/wave")
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "/etc/passwd", "../outside", "emojis/../../outside", "emojis//wave"} {
+		err = store.Put(ctx, key, []byte("data"))
+		require.ErrorAs(t, err, &blobs.InvalidKeyError{}, key)
+	}
+}
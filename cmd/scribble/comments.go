@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nasermirzaei89/scribble"
+)
+
+var errUnknownCommentsCommand = errors.New("unknown comments command, expected: comments rerank")
+
+func runComments(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] != "rerank" {
+		return errUnknownCommentsCommand
+	}
+
+	return runRerankComments(ctx, os.Stdout)
+}
+
+// runRerankComments scores every comment of the configured database with the configured weights.
+func runRerankComments(ctx context.Context, out io.Writer) error {
+	count, err := scribble.RerankComments(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to rerank comments: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(out, "%d comments reranked\n", count)
+
+	return nil
+}
@@ -20,7 +20,23 @@ func main() {
 		slog.SetDefault(slog.New(slogcolor.NewHandler(os.Stderr, opts)))
 	}
 
-	err := run(ctx)
+	var err error
+
+	switch {
+	case len(os.Args) > 1 && os.Args[1] == "policy":
+		err = runPolicy(ctx, os.Args[2:])
+	case len(os.Args) > 1 && os.Args[1] == "reactions":
+		err = runReactions(ctx, os.Args[2:])
+	case len(os.Args) > 1 && os.Args[1] == "reputation":
+		err = runReputation(ctx, os.Args[2:])
+	case len(os.Args) > 1 && os.Args[1] == "posts":
+		err = runPosts(ctx, os.Args[2:])
+	case len(os.Args) > 1 && os.Args[1] == "comments":
+		err = runComments(ctx, os.Args[2:])
+	default:
+		err = run(ctx)
+	}
+
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to run", "error", err)
 		os.Exit(1)
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nasermirzaei89/scribble"
+	"github.com/nasermirzaei89/scribble/authorization/policytest"
+)
+
+const defaultPolicyAssertionsFile = "policy_assertions.txt"
+
+var (
+	errUnknownPolicyCommand = errors.New("unknown policy command, expected: policy check [assertions-file]")
+	errPolicyCheckFailed    = errors.New("policy check failed")
+)
+
+func runPolicy(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] != "check" {
+		return errUnknownPolicyCommand
+	}
+
+	return runPolicyCheck(ctx, os.Stdout, args[1:])
+}
+
+// runPolicyCheck evaluates an assertions file against the policy the application would load.
+func runPolicyCheck(ctx context.Context, out io.Writer, args []string) error {
+	flags := flag.NewFlagSet("policy check", flag.ContinueOnError)
+	policyFile := flags.String("policy", "", "policy file to check instead of the configured policy")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	assertionsFile := defaultPolicyAssertionsFile
+	if flags.NArg() > 0 {
+		assertionsFile = flags.Arg(0)
+	}
+
+	policyContent, err := loadPolicyToCheck(*policyFile)
+	if err != nil {
+		return err
+	}
+
+	suite, err := policytest.LoadAssertions(assertionsFile)
+	if err != nil {
+		return fmt.Errorf("failed to load assertions: %w", err)
+	}
+
+	results, err := policytest.Evaluate(ctx, policyContent, suite)
+	if err != nil {
+		return fmt.Errorf("failed to evaluate assertions: %w", err)
+	}
+
+	failures := 0
+
+	for _, result := range results {
+		if result.Passed() {
+			continue
+		}
+
+		failures++
+
+		_, _ = fmt.Fprintf(out, "FAIL %s\n", result)
+	}
+
+	_, _ = fmt.Fprintf(out, "%d assertions, %d failures\n", len(results), failures)
+
+	if failures > 0 {
+		return errPolicyCheckFailed
+	}
+
+	return nil
+}
+
+func loadPolicyToCheck(policyFile string) (string, error) {
+	if policyFile == "" {
+		policyContent, err := scribble.LoadAuthorizationPolicy()
+		if err != nil {
+			return "", fmt.Errorf("failed to load authorization policy: %w", err)
+		}
+
+		return policyContent, nil
+	}
+
+	content, err := os.ReadFile(policyFile) //nolint:gosec
+	if err != nil {
+		return "", fmt.Errorf("failed to read policy file %q: %w", policyFile, err)
+	}
+
+	return string(content), nil
+}
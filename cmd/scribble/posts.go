@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nasermirzaei89/scribble"
+)
+
+var errUnknownPostsCommand = errors.New("unknown posts command, expected: posts rerank")
+
+func runPosts(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] != "rerank" {
+		return errUnknownPostsCommand
+	}
+
+	return runRerankPosts(ctx, os.Stdout)
+}
+
+// runRerankPosts ranks every post of the configured database with the configured weights.
+func runRerankPosts(ctx context.Context, out io.Writer) error {
+	count, err := scribble.RerankPosts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to rerank posts: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(out, "%d posts reranked\n", count)
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nasermirzaei89/scribble"
+)
+
+var errUnknownReactionsCommand = errors.New("unknown reactions command, expected: reactions repair-counts")
+
+func runReactions(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] != "repair-counts" {
+		return errUnknownReactionsCommand
+	}
+
+	return runRepairCounts(ctx, os.Stdout)
+}
+
+// runRepairCounts recomputes the reaction counts of the configured database from its reactions.
+func runRepairCounts(ctx context.Context, out io.Writer) error {
+	fixed, err := scribble.RepairReactionCounts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to repair reaction counts: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(out, "%d reaction counts repaired\n", fixed)
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nasermirzaei89/scribble"
+)
+
+var errUnknownReputationCommand = errors.New("unknown reputation command, expected: reputation recompute")
+
+func runReputation(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] != "recompute" {
+		return errUnknownReputationCommand
+	}
+
+	return runRecomputeReputations(ctx, os.Stdout)
+}
+
+// runRecomputeReputations rescores every user of the configured database with the configured weights.
+func runRecomputeReputations(ctx context.Context, out io.Writer) error {
+	count, err := scribble.RecomputeReputations(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to recompute reputations: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(out, "%d reputations recomputed\n", count)
+
+	return nil
+}
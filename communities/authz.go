@@ -0,0 +1,200 @@
+package communities
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nasermirzaei89/scribble/authorization"
+)
+
+const (
+	ActionCreateCommunity = "createCommunity"
+	ActionGetCommunity    = "getCommunity"
+	ActionListCommunities = "listCommunities"
+	ActionJoinCommunity   = "joinCommunity"
+	ActionLeaveCommunity  = "leaveCommunity"
+	ActionListMembers     = "listMembers"
+	ActionListMemberships = "listMemberships"
+	// ActionSetMemberRole is checked in the community domain with the new role as the object.
+	ActionSetMemberRole = "setMemberRole"
+	// ActionRemoveMember is checked in the community domain with the removed member's role as the object, so removing a
+	// moderator takes more than removing a member.
+	ActionRemoveMember = "removeMember"
+)
+
+type AuthorizationMiddleware struct {
+	authzClient *authorization.Client
+	next        Service
+}
+
+var _ Service = (*AuthorizationMiddleware)(nil)
+
+func NewAuthorizationMiddleware(authzClient *authorization.Client, next Service) *AuthorizationMiddleware {
+	return &AuthorizationMiddleware{
+		authzClient: authzClient,
+		next:        next,
+	}
+}
+
+func (mw *AuthorizationMiddleware) CreateCommunity(ctx context.Context, req CreateCommunityRequest) (*Community, error) {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, "", ActionCreateCommunity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	community, err := mw.next.CreateCommunity(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return community, nil
+}
+
+func (mw *AuthorizationMiddleware) GetCommunity(ctx context.Context, communityID string) (*Community, error) {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, communityID, ActionGetCommunity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	community, err := mw.next.GetCommunity(ctx, communityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return community, nil
+}
+
+func (mw *AuthorizationMiddleware) GetCommunityBySlug(ctx context.Context, slug string) (*Community, error) {
+	community, err := mw.next.GetCommunityBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	err = mw.authzClient.CheckAccess(ctx, ServiceName, community.ID, ActionGetCommunity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	return community, nil
+}
+
+func (mw *AuthorizationMiddleware) ListCommunities(ctx context.Context) ([]*Community, error) {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, "", ActionListCommunities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	communities, err := mw.next.ListCommunities(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return communities, nil
+}
+
+func (mw *AuthorizationMiddleware) JoinCommunity(ctx context.Context, communityID, userID string) (*Member, error) {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, communityID, ActionJoinCommunity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	member, err := mw.next.JoinCommunity(ctx, communityID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return member, nil
+}
+
+func (mw *AuthorizationMiddleware) LeaveCommunity(ctx context.Context, communityID, userID string) error {
+	err := mw.authzClient.CheckAccess(ctx, Domain(communityID), "", ActionLeaveCommunity)
+	if err != nil {
+		return fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	err = mw.next.LeaveCommunity(ctx, communityID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return nil
+}
+
+func (mw *AuthorizationMiddleware) GetMember(ctx context.Context, communityID, userID string) (*Member, error) {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, communityID, ActionListMembers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	member, err := mw.next.GetMember(ctx, communityID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return member, nil
+}
+
+func (mw *AuthorizationMiddleware) ListMembers(ctx context.Context, communityID string) ([]*Member, error) {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, communityID, ActionListMembers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	members, err := mw.next.ListMembers(ctx, communityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return members, nil
+}
+
+func (mw *AuthorizationMiddleware) ListMemberships(ctx context.Context, userID string) ([]*Member, error) {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, "", ActionListMemberships)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	members, err := mw.next.ListMemberships(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return members, nil
+}
+
+func (mw *AuthorizationMiddleware) SetMemberRole(
+	ctx context.Context,
+	communityID, userID string,
+	role Role,
+) (*Member, error) {
+	err := mw.authzClient.CheckAccess(ctx, Domain(communityID), string(role), ActionSetMemberRole)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	member, err := mw.next.SetMemberRole(ctx, communityID, userID, role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return member, nil
+}
+
+func (mw *AuthorizationMiddleware) RemoveMember(ctx context.Context, communityID, userID string) error {
+	member, err := mw.next.GetMember(ctx, communityID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get member: %w", err)
+	}
+
+	err = mw.authzClient.CheckAccess(ctx, Domain(communityID), string(member.Role), ActionRemoveMember)
+	if err != nil {
+		return fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	err = mw.next.RemoveMember(ctx, communityID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package communities_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	fileadapter "github.com/casbin/casbin/v3/persist/file-adapter"
+	"github.com/google/uuid"
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/authorization/casbin"
+	"github.com/nasermirzaei89/scribble/communities"
+	"github.com/stretchr/testify/require"
+)
+
+type stubService struct {
+	roles map[string]communities.Role
+}
+
+func (s *stubService) CreateCommunity(
+	ctx context.Context,
+	req communities.CreateCommunityRequest,
+) (*communities.Community, error) {
+	return &communities.Community{ID: "community1", Slug: req.Slug, Name: req.Name, OwnerID: req.OwnerID}, nil
+}
+
+func (s *stubService) GetCommunity(ctx context.Context, communityID string) (*communities.Community, error) {
+	return &communities.Community{ID: communityID}, nil
+}
+
+func (s *stubService) GetCommunityBySlug(ctx context.Context, slug string) (*communities.Community, error) {
+	return &communities.Community{ID: slug, Slug: slug}, nil
+}
+
+func (s *stubService) ListCommunities(ctx context.Context) ([]*communities.Community, error) {
+	return []*communities.Community{}, nil
+}
+
+func (s *stubService) JoinCommunity(ctx context.Context, communityID, userID string) (*communities.Member, error) {
+	return &communities.Member{CommunityID: communityID, UserID: userID, Role: communities.RoleMember}, nil
+}
+
+func (s *stubService) LeaveCommunity(ctx context.Context, communityID, userID string) error {
+	return nil
+}
+
+func (s *stubService) GetMember(ctx context.Context, communityID, userID string) (*communities.Member, error) {
+	return &communities.Member{CommunityID: communityID, UserID: userID, Role: s.roles[userID]}, nil
+}
+
+func (s *stubService) ListMembers(ctx context.Context, communityID string) ([]*communities.Member, error) {
+	return []*communities.Member{}, nil
+}
+
+func (s *stubService) ListMemberships(ctx context.Context, userID string) ([]*communities.Member, error) {
+	return []*communities.Member{}, nil
+}
+
+func (s *stubService) SetMemberRole(
+	ctx context.Context,
+	communityID, userID string,
+	role communities.Role,
+) (*communities.Member, error) {
+	return &communities.Member{CommunityID: communityID, UserID: userID, Role: role}, nil
+}
+
+func (s *stubService) RemoveMember(ctx context.Context, communityID, userID string) error {
+	return nil
+}
+
+func TestAuthorizationMiddleware(t *testing.T) {
+	ctx := context.Background()
+
+	tmpDir := t.TempDir()
+	tmpFile := filepath.Join(tmpDir, "policy.csv")
+	content := []byte(`g, system:anonymous, system:unauthenticated, *
+g, community:owner, community:moderator, *
+g, community:moderator, community:member, *
+
+p, system:authenticated, github.com/nasermirzaei89/scribble/communities, -, createCommunity
+p, system:authenticated, github.com/nasermirzaei89/scribble/communities, -, listCommunities
+p, system:unauthenticated, github.com/nasermirzaei89/scribble/communities, -, listCommunities
+p, system:authenticated, github.com/nasermirzaei89/scribble/communities, *, getCommunity
+p, system:unauthenticated, github.com/nasermirzaei89/scribble/communities, *, getCommunity
+p, system:authenticated, github.com/nasermirzaei89/scribble/communities, *, joinCommunity
+p, community:member, github.com/nasermirzaei89/scribble/communities/*, -, leaveCommunity
+p, community:moderator, github.com/nasermirzaei89/scribble/communities/*, community:member, removeMember
+p, community:owner, github.com/nasermirzaei89/scribble/communities/*, community:moderator, removeMember
+p, community:owner, github.com/nasermirzaei89/scribble/communities/*, *, setMemberRole
+`)
+
+	err := os.WriteFile(tmpFile, content, 0o600)
+	require.NoError(t, err)
+
+	adapter := fileadapter.NewAdapter(tmpFile)
+
+	provider, err := casbin.NewAuthorizationProvider(adapter)
+	require.NoError(t, err)
+
+	authzSvc, err := authorization.NewService(provider)
+	require.NoError(t, err)
+
+	client := authorization.NewClient(authzSvc)
+
+	ownerID := uuid.NewString()
+	moderatorID := uuid.NewString()
+	memberID := uuid.NewString()
+
+	stub := &stubService{roles: map[string]communities.Role{
+		ownerID:     communities.RoleOwner,
+		moderatorID: communities.RoleModerator,
+		memberID:    communities.RoleMember,
+	}}
+	svc := communities.NewAuthorizationMiddleware(client, stub)
+
+	for userID, role := range stub.roles {
+		err = client.AddToGroup(ctx, userID, authcontext.Authenticated)
+		require.NoError(t, err)
+
+		err = client.AddToDomainGroup(ctx, userID, communities.Domain("c1"), string(role))
+		require.NoError(t, err)
+	}
+
+	anonymousCtx := ctx
+	ownerCtx := authcontext.WithSubject(ctx, ownerID)
+	moderatorCtx := authcontext.WithSubject(ctx, moderatorID)
+	memberCtx := authcontext.WithSubject(ctx, memberID)
+
+	requireAccessDenied := func(t *testing.T, err error) {
+		t.Helper()
+
+		require.Error(t, err)
+
+		accessDeniedErr := &authorization.AccessDeniedError{}
+		require.ErrorAs(t, err, &accessDeniedErr)
+	}
+
+	t.Run("anonymous", func(t *testing.T) {
+		_, err := svc.ListCommunities(anonymousCtx)
+		require.NoError(t, err)
+
+		_, err = svc.GetCommunityBySlug(anonymousCtx, "c1")
+		require.NoError(t, err)
+
+		_, err = svc.CreateCommunity(anonymousCtx, communities.CreateCommunityRequest{Slug: "c3", Name: "C3"})
+		requireAccessDenied(t, err)
+
+		_, err = svc.JoinCommunity(anonymousCtx, "c1", "")
+		requireAccessDenied(t, err)
+	})
+
+	t.Run("member", func(t *testing.T) {
+		_, err := svc.CreateCommunity(memberCtx, communities.CreateCommunityRequest{OwnerID: memberID, Slug: "c3"})
+		require.NoError(t, err)
+
+		err = svc.LeaveCommunity(memberCtx, "c1", memberID)
+		require.NoError(t, err)
+
+		err = svc.LeaveCommunity(memberCtx, "c2", memberID)
+		requireAccessDenied(t, err)
+
+		err = svc.RemoveMember(memberCtx, "c1", moderatorID)
+		requireAccessDenied(t, err)
+	})
+
+	t.Run("moderator", func(t *testing.T) {
+		err := svc.RemoveMember(moderatorCtx, "c1", memberID)
+		require.NoError(t, err)
+
+		err = svc.RemoveMember(moderatorCtx, "c2", memberID)
+		requireAccessDenied(t, err)
+
+		err = svc.RemoveMember(moderatorCtx, "c1", ownerID)
+		requireAccessDenied(t, err)
+
+		_, err = svc.SetMemberRole(moderatorCtx, "c1", memberID, communities.RoleModerator)
+		requireAccessDenied(t, err)
+	})
+
+	t.Run("owner", func(t *testing.T) {
+		err := svc.RemoveMember(ownerCtx, "c1", moderatorID)
+		require.NoError(t, err)
+
+		_, err = svc.SetMemberRole(ownerCtx, "c1", memberID, communities.RoleModerator)
+		require.NoError(t, err)
+
+		_, err = svc.SetMemberRole(ownerCtx, "c2", memberID, communities.RoleModerator)
+		requireAccessDenied(t, err)
+	})
+}
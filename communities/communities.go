@@ -0,0 +1,290 @@
+package communities
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/events"
+)
+
+const ServiceName = "github.com/nasermirzaei89/scribble/communities"
+
+// DomainPattern matches the authorization domain of every community.
+const DomainPattern = ServiceName + "/" + authorization.Wildcard
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,38}[a-z0-9]$`)
+
+// Domain returns the authorization domain of the community. Roles granted in it give no power in other communities.
+func Domain(communityID string) string {
+	return ServiceName + "/" + communityID
+}
+
+type Service interface {
+	CreateCommunity(ctx context.Context, req CreateCommunityRequest) (*Community, error)
+	GetCommunity(ctx context.Context, communityID string) (*Community, error)
+	GetCommunityBySlug(ctx context.Context, slug string) (*Community, error)
+	ListCommunities(ctx context.Context) ([]*Community, error)
+	JoinCommunity(ctx context.Context, communityID, userID string) (*Member, error)
+	LeaveCommunity(ctx context.Context, communityID, userID string) error
+	GetMember(ctx context.Context, communityID, userID string) (*Member, error)
+	ListMembers(ctx context.Context, communityID string) ([]*Member, error)
+	ListMemberships(ctx context.Context, userID string) ([]*Member, error)
+	SetMemberRole(ctx context.Context, communityID, userID string, role Role) (*Member, error)
+	RemoveMember(ctx context.Context, communityID, userID string) error
+}
+
+type BaseService struct {
+	communityRepo CommunityRepository
+	memberRepo    MemberRepository
+	authzClient   *authorization.Client
+}
+
+var _ Service = (*BaseService)(nil)
+
+func NewService( //nolint:ireturn
+	communityRepo CommunityRepository,
+	memberRepo MemberRepository,
+	authzClient *authorization.Client,
+	bus *events.Bus,
+) Service {
+	return NewAuthorizationMiddleware(
+		authzClient,
+		NewEventsMiddleware(bus, NewBaseService(communityRepo, memberRepo, authzClient)),
+	)
+}
+
+func NewBaseService(
+	communityRepo CommunityRepository,
+	memberRepo MemberRepository,
+	authzClient *authorization.Client,
+) *BaseService {
+	return &BaseService{
+		communityRepo: communityRepo,
+		memberRepo:    memberRepo,
+		authzClient:   authzClient,
+	}
+}
+
+type CreateCommunityRequest struct {
+	OwnerID     string
+	Slug        string
+	Name        string
+	Description string
+}
+
+func (svc *BaseService) CreateCommunity(ctx context.Context, req CreateCommunityRequest) (*Community, error) {
+	slug := strings.ToLower(strings.TrimSpace(req.Slug))
+	if !slugPattern.MatchString(slug) {
+		return nil, InvalidSlugError{Slug: req.Slug}
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, InvalidNameError{}
+	}
+
+	_, err := svc.communityRepo.FindBySlug(ctx, slug)
+	if err != nil {
+		if _, ok := errors.AsType[CommunityBySlugNotFoundError](err); !ok {
+			return nil, fmt.Errorf("failed to check if slug already exists: %w", err)
+		}
+	} else {
+		return nil, CommunityAlreadyExistsError{Slug: slug}
+	}
+
+	community := &Community{
+		ID:          uuid.NewString(),
+		Slug:        slug,
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+		OwnerID:     req.OwnerID,
+		CreatedAt:   time.Now(),
+	}
+
+	err = svc.communityRepo.Insert(ctx, community)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create community: %w", err)
+	}
+
+	_, err = svc.addMember(ctx, community.ID, req.OwnerID, RoleOwner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add owner: %w", err)
+	}
+
+	return community, nil
+}
+
+func (svc *BaseService) GetCommunity(ctx context.Context, communityID string) (*Community, error) {
+	community, err := svc.communityRepo.Find(ctx, communityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find community: %w", err)
+	}
+
+	return community, nil
+}
+
+func (svc *BaseService) GetCommunityBySlug(ctx context.Context, slug string) (*Community, error) {
+	community, err := svc.communityRepo.FindBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find community by slug: %w", err)
+	}
+
+	return community, nil
+}
+
+func (svc *BaseService) ListCommunities(ctx context.Context) ([]*Community, error) {
+	communities, err := svc.communityRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list communities: %w", err)
+	}
+
+	return communities, nil
+}
+
+func (svc *BaseService) JoinCommunity(ctx context.Context, communityID, userID string) (*Member, error) {
+	_, err := svc.communityRepo.Find(ctx, communityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find community: %w", err)
+	}
+
+	_, err = svc.memberRepo.Find(ctx, communityID, userID)
+	if err != nil {
+		if _, ok := errors.AsType[MemberNotFoundError](err); !ok {
+			return nil, fmt.Errorf("failed to check membership: %w", err)
+		}
+	} else {
+		return nil, AlreadyMemberError{CommunityID: communityID, UserID: userID}
+	}
+
+	member, err := svc.addMember(ctx, communityID, userID, RoleMember)
+	if err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+func (svc *BaseService) LeaveCommunity(ctx context.Context, communityID, userID string) error {
+	return svc.removeMember(ctx, communityID, userID)
+}
+
+func (svc *BaseService) GetMember(ctx context.Context, communityID, userID string) (*Member, error) {
+	member, err := svc.memberRepo.Find(ctx, communityID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find member: %w", err)
+	}
+
+	return member, nil
+}
+
+func (svc *BaseService) ListMembers(ctx context.Context, communityID string) ([]*Member, error) {
+	members, err := svc.memberRepo.List(ctx, communityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members: %w", err)
+	}
+
+	return members, nil
+}
+
+func (svc *BaseService) ListMemberships(ctx context.Context, userID string) ([]*Member, error) {
+	members, err := svc.memberRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list memberships: %w", err)
+	}
+
+	return members, nil
+}
+
+// SetMemberRole promotes a member to moderator or demotes a moderator to member.
+func (svc *BaseService) SetMemberRole(ctx context.Context, communityID, userID string, role Role) (*Member, error) {
+	if role != RoleMember && role != RoleModerator {
+		return nil, InvalidRoleError{Role: role}
+	}
+
+	member, err := svc.memberRepo.Find(ctx, communityID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find member: %w", err)
+	}
+
+	if member.Role == RoleOwner {
+		return nil, OwnerMembershipError{CommunityID: communityID}
+	}
+
+	if member.Role == role {
+		return member, nil
+	}
+
+	previousRole := member.Role
+	member.Role = role
+
+	err = svc.memberRepo.Update(ctx, member)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update member: %w", err)
+	}
+
+	err = svc.authzClient.RemoveFromDomainGroup(ctx, userID, Domain(communityID), string(previousRole))
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove previous role: %w", err)
+	}
+
+	err = svc.authzClient.AddToDomainGroup(ctx, userID, Domain(communityID), string(role))
+	if err != nil {
+		return nil, fmt.Errorf("failed to add role: %w", err)
+	}
+
+	return member, nil
+}
+
+func (svc *BaseService) RemoveMember(ctx context.Context, communityID, userID string) error {
+	return svc.removeMember(ctx, communityID, userID)
+}
+
+func (svc *BaseService) addMember(ctx context.Context, communityID, userID string, role Role) (*Member, error) {
+	member := &Member{
+		CommunityID: communityID,
+		UserID:      userID,
+		Role:        role,
+		JoinedAt:    time.Now(),
+	}
+
+	err := svc.memberRepo.Insert(ctx, member)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert member: %w", err)
+	}
+
+	err = svc.authzClient.AddToDomainGroup(ctx, userID, Domain(communityID), string(role))
+	if err != nil {
+		return nil, fmt.Errorf("failed to add member to community role: %w", err)
+	}
+
+	return member, nil
+}
+
+func (svc *BaseService) removeMember(ctx context.Context, communityID, userID string) error {
+	member, err := svc.memberRepo.Find(ctx, communityID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to find member: %w", err)
+	}
+
+	if member.Role == RoleOwner {
+		return OwnerMembershipError{CommunityID: communityID}
+	}
+
+	err = svc.memberRepo.Delete(ctx, communityID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete member: %w", err)
+	}
+
+	err = svc.authzClient.RemoveFromDomainGroup(ctx, userID, Domain(communityID), string(member.Role))
+	if err != nil {
+		return fmt.Errorf("failed to remove member from community role: %w", err)
+	}
+
+	return nil
+}
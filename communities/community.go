@@ -0,0 +1,148 @@
+package communities
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type Community struct {
+	ID          string
+	Slug        string
+	Name        string
+	Description string
+	OwnerID     string
+	CreatedAt   time.Time
+}
+
+// Role is the group a member is bound to in the community's authorization domain. Roles inherit each other, so an
+// owner is also a moderator and a moderator is also a member.
+type Role string
+
+const (
+	RoleMember    Role = "community:member"
+	RoleModerator Role = "community:moderator"
+	RoleOwner     Role = "community:owner"
+)
+
+func (role Role) IsValid() bool {
+	switch role {
+	case RoleMember, RoleModerator, RoleOwner:
+		return true
+	default:
+		return false
+	}
+}
+
+// Title returns the human-readable name of the role.
+func (role Role) Title() string {
+	switch role {
+	case RoleMember:
+		return "Member"
+	case RoleModerator:
+		return "Moderator"
+	case RoleOwner:
+		return "Owner"
+	default:
+		return string(role)
+	}
+}
+
+type Member struct {
+	CommunityID string
+	UserID      string
+	Role        Role
+	JoinedAt    time.Time
+}
+
+type CommunityRepository interface {
+	Insert(ctx context.Context, community *Community) (err error)
+	Find(ctx context.Context, communityID string) (community *Community, err error)
+	FindBySlug(ctx context.Context, slug string) (community *Community, err error)
+	List(ctx context.Context) (communities []*Community, err error)
+}
+
+type MemberRepository interface {
+	Insert(ctx context.Context, member *Member) (err error)
+	Find(ctx context.Context, communityID, userID string) (member *Member, err error)
+	Update(ctx context.Context, member *Member) (err error)
+	Delete(ctx context.Context, communityID, userID string) (err error)
+	List(ctx context.Context, communityID string) (members []*Member, err error)
+	ListByUser(ctx context.Context, userID string) (members []*Member, err error)
+}
+
+type CommunityNotFoundError struct {
+	ID string
+}
+
+func (err CommunityNotFoundError) Error() string {
+	return fmt.Sprintf("community with id %q not found", err.ID)
+}
+
+type CommunityBySlugNotFoundError struct {
+	Slug string
+}
+
+func (err CommunityBySlugNotFoundError) Error() string {
+	return fmt.Sprintf("community with slug %q not found", err.Slug)
+}
+
+type CommunityAlreadyExistsError struct {
+	Slug string
+}
+
+func (err CommunityAlreadyExistsError) Error() string {
+	return fmt.Sprintf("community with slug %q already exists", err.Slug)
+}
+
+type InvalidSlugError struct {
+	Slug string
+}
+
+func (err InvalidSlugError) Error() string {
+	return fmt.Sprintf(
+		"invalid community slug %q: use 3 to 40 lowercase letters, digits or dashes, not starting or ending with a dash",
+		err.Slug,
+	)
+}
+
+type InvalidNameError struct{}
+
+func (err InvalidNameError) Error() string {
+	return "community name is required"
+}
+
+type MemberNotFoundError struct {
+	CommunityID string
+	UserID      string
+}
+
+func (err MemberNotFoundError) Error() string {
+	return fmt.Sprintf("user %q is not a member of community %q", err.UserID, err.CommunityID)
+}
+
+type AlreadyMemberError struct {
+	CommunityID string
+	UserID      string
+}
+
+func (err AlreadyMemberError) Error() string {
+	return fmt.Sprintf("user %q is already a member of community %q", err.UserID, err.CommunityID)
+}
+
+type InvalidRoleError struct {
+	Role Role
+}
+
+func (err InvalidRoleError) Error() string {
+	return fmt.Sprintf("invalid community role %q", err.Role)
+}
+
+// OwnerMembershipError is returned when an operation would change the owner's membership, which is fixed.
+type OwnerMembershipError struct {
+	CommunityID string
+}
+
+func (err OwnerMembershipError) Error() string {
+	return fmt.Sprintf("the owner's membership of community %q cannot be changed", err.CommunityID)
+}
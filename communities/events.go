@@ -0,0 +1,227 @@
+package communities
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nasermirzaei89/scribble/events"
+)
+
+// EventsMiddleware publishes the changes of the next service, in the transaction of the change. The authorization
+// policies mirroring memberships are written in it too.
+type EventsMiddleware struct {
+	bus  *events.Bus
+	next Service
+}
+
+var _ Service = (*EventsMiddleware)(nil)
+
+func NewEventsMiddleware(bus *events.Bus, next Service) *EventsMiddleware {
+	return &EventsMiddleware{
+		bus:  bus,
+		next: next,
+	}
+}
+
+func (mw *EventsMiddleware) CreateCommunity(ctx context.Context, req CreateCommunityRequest) (*Community, error) {
+	var community *Community
+
+	err := mw.bus.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+
+		community, err = mw.next.CreateCommunity(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to call next method: %w", err)
+		}
+
+		err = mw.bus.Publish(ctx, events.CommunityCreated{
+			CommunityID: community.ID,
+			Slug:        community.Slug,
+			OwnerID:     community.OwnerID,
+			CreatedAt:   community.CreatedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to publish event: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create community: %w", err)
+	}
+
+	return community, nil
+}
+
+func (mw *EventsMiddleware) GetCommunity(ctx context.Context, communityID string) (*Community, error) {
+	community, err := mw.next.GetCommunity(ctx, communityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return community, nil
+}
+
+func (mw *EventsMiddleware) GetCommunityBySlug(ctx context.Context, slug string) (*Community, error) {
+	community, err := mw.next.GetCommunityBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return community, nil
+}
+
+func (mw *EventsMiddleware) ListCommunities(ctx context.Context) ([]*Community, error) {
+	communities, err := mw.next.ListCommunities(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return communities, nil
+}
+
+func (mw *EventsMiddleware) JoinCommunity(ctx context.Context, communityID, userID string) (*Member, error) {
+	var member *Member
+
+	err := mw.bus.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+
+		member, err = mw.next.JoinCommunity(ctx, communityID, userID)
+		if err != nil {
+			return fmt.Errorf("failed to call next method: %w", err)
+		}
+
+		err = mw.bus.Publish(ctx, events.CommunityMemberJoined{
+			CommunityID: member.CommunityID,
+			UserID:      member.UserID,
+			Role:        string(member.Role),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to publish event: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to join community: %w", err)
+	}
+
+	return member, nil
+}
+
+func (mw *EventsMiddleware) LeaveCommunity(ctx context.Context, communityID, userID string) error {
+	err := mw.bus.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := mw.next.LeaveCommunity(ctx, communityID, userID)
+		if err != nil {
+			return fmt.Errorf("failed to call next method: %w", err)
+		}
+
+		err = mw.bus.Publish(ctx, events.CommunityMemberLeft{
+			CommunityID: communityID,
+			UserID:      userID,
+			Removed:     false,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to publish event: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to leave community: %w", err)
+	}
+
+	return nil
+}
+
+func (mw *EventsMiddleware) GetMember(ctx context.Context, communityID, userID string) (*Member, error) {
+	member, err := mw.next.GetMember(ctx, communityID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return member, nil
+}
+
+func (mw *EventsMiddleware) ListMembers(ctx context.Context, communityID string) ([]*Member, error) {
+	members, err := mw.next.ListMembers(ctx, communityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return members, nil
+}
+
+func (mw *EventsMiddleware) ListMemberships(ctx context.Context, userID string) ([]*Member, error) {
+	members, err := mw.next.ListMemberships(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return members, nil
+}
+
+func (mw *EventsMiddleware) SetMemberRole(
+	ctx context.Context,
+	communityID, userID string,
+	role Role,
+) (*Member, error) {
+	var member *Member
+
+	err := mw.bus.WithinTransaction(ctx, func(ctx context.Context) error {
+		previous, err := mw.next.GetMember(ctx, communityID, userID)
+		if err != nil {
+			return fmt.Errorf("failed to get member: %w", err)
+		}
+
+		member, err = mw.next.SetMemberRole(ctx, communityID, userID, role)
+		if err != nil {
+			return fmt.Errorf("failed to call next method: %w", err)
+		}
+
+		if member.Role == previous.Role {
+			return nil
+		}
+
+		err = mw.bus.Publish(ctx, events.CommunityMemberRoleChanged{
+			CommunityID: member.CommunityID,
+			UserID:      member.UserID,
+			Role:        string(member.Role),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to publish event: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set member role: %w", err)
+	}
+
+	return member, nil
+}
+
+func (mw *EventsMiddleware) RemoveMember(ctx context.Context, communityID, userID string) error {
+	err := mw.bus.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := mw.next.RemoveMember(ctx, communityID, userID)
+		if err != nil {
+			return fmt.Errorf("failed to call next method: %w", err)
+		}
+
+		err = mw.bus.Publish(ctx, events.CommunityMemberLeft{
+			CommunityID: communityID,
+			UserID:      userID,
+			Removed:     true,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to publish event: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove member: %w", err)
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/communities"
 )
 
 const (
@@ -28,7 +29,12 @@ func NewAuthorizationMiddleware(authzClient *authorization.Client, next Service)
 }
 
 func (mw *AuthorizationMiddleware) CreatePost(ctx context.Context, req CreatePostRequest) (*Post, error) {
-	err := mw.authzClient.CheckAccess(ctx, ServiceName, "", ActionCreatePost)
+	domain := ServiceName
+	if req.CommunityID != "" {
+		domain = communities.Domain(req.CommunityID)
+	}
+
+	err := mw.authzClient.CheckAccess(ctx, domain, "", ActionCreatePost)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check authorization: %w", err)
 	}
@@ -41,13 +47,13 @@ func (mw *AuthorizationMiddleware) CreatePost(ctx context.Context, req CreatePos
 	return post, nil
 }
 
-func (mw *AuthorizationMiddleware) ListPosts(ctx context.Context) ([]*Post, error) {
+func (mw *AuthorizationMiddleware) ListPosts(ctx context.Context, req ListPostsRequest) ([]*Post, error) {
 	err := mw.authzClient.CheckAccess(ctx, ServiceName, "", ActionListPosts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check authorization: %w", err)
 	}
 
-	posts, err := mw.next.ListPosts(ctx)
+	posts, err := mw.next.ListPosts(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call next method: %w", err)
 	}
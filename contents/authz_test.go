@@ -11,6 +11,7 @@ import (
 	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
 	"github.com/nasermirzaei89/scribble/authorization"
 	"github.com/nasermirzaei89/scribble/authorization/casbin"
+	"github.com/nasermirzaei89/scribble/communities"
 	"github.com/nasermirzaei89/scribble/contents"
 	"github.com/stretchr/testify/require"
 )
@@ -21,7 +22,7 @@ func (s *stubService) CreatePost(ctx context.Context, req contents.CreatePostReq
 	return &contents.Post{ID: "post1", AuthorID: req.AuthorID, Content: req.Content}, nil
 }
 
-func (s *stubService) ListPosts(ctx context.Context) ([]*contents.Post, error) {
+func (s *stubService) ListPosts(ctx context.Context, req contents.ListPostsRequest) ([]*contents.Post, error) {
 	return []*contents.Post{}, nil
 }
 
@@ -34,13 +35,14 @@ func TestAuthorizationMiddleware(t *testing.T) {
 
 	tmpDir := t.TempDir()
 	tmpFile := filepath.Join(tmpDir, "policy.csv")
-	content := []byte(`g, system:anonymous, system:unauthenticated
+	content := []byte(`g, system:anonymous, system:unauthenticated, *
 
 p, system:authenticated, github.com/nasermirzaei89/scribble/contents, -, createPost
 p, system:authenticated, github.com/nasermirzaei89/scribble/contents, -, listPosts
 p, system:unauthenticated, github.com/nasermirzaei89/scribble/contents, -, listPosts
 p, system:authenticated, github.com/nasermirzaei89/scribble/contents, *, getPost
 p, system:unauthenticated, github.com/nasermirzaei89/scribble/contents, *, getPost
+p, community:member, github.com/nasermirzaei89/scribble/communities/*, -, createPost
 `)
 
 	err := os.WriteFile(tmpFile, content, 0o600)
@@ -73,7 +75,7 @@ p, system:unauthenticated, github.com/nasermirzaei89/scribble/contents, *, getPo
 		accessDeniedErr := &authorization.AccessDeniedError{}
 		require.ErrorAs(t, err, &accessDeniedErr)
 
-		_, err = svc.ListPosts(anonymousCtx)
+		_, err = svc.ListPosts(anonymousCtx, contents.ListPostsRequest{})
 		require.NoError(t, err)
 
 		_, err = svc.GetPost(anonymousCtx, "post1")
@@ -84,10 +86,32 @@ p, system:unauthenticated, github.com/nasermirzaei89/scribble/contents, *, getPo
 		_, err := svc.CreatePost(authenticatedCtx, contents.CreatePostRequest{AuthorID: authorID, Content: "post"})
 		require.NoError(t, err)
 
-		_, err = svc.ListPosts(authenticatedCtx)
+		_, err = svc.ListPosts(authenticatedCtx, contents.ListPostsRequest{})
 		require.NoError(t, err)
 
 		_, err = svc.GetPost(authenticatedCtx, "post1")
 		require.NoError(t, err)
 	})
+
+	t.Run("community", func(t *testing.T) {
+		err := client.AddToDomainGroup(ctx, userID, communities.Domain("c1"), string(communities.RoleMember))
+		require.NoError(t, err)
+
+		_, err = svc.CreatePost(authenticatedCtx, contents.CreatePostRequest{
+			AuthorID:    authorID,
+			CommunityID: "c1",
+			Content:     "post",
+		})
+		require.NoError(t, err)
+
+		_, err = svc.CreatePost(authenticatedCtx, contents.CreatePostRequest{
+			AuthorID:    authorID,
+			CommunityID: "c2",
+			Content:     "post",
+		})
+		require.Error(t, err)
+
+		accessDeniedErr := &authorization.AccessDeniedError{}
+		require.ErrorAs(t, err, &accessDeniedErr)
+	})
 }
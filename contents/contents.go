@@ -7,13 +7,14 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/events"
 )
 
 const ServiceName = "github.com/nasermirzaei89/scribble/contents"
 
 type Service interface {
 	CreatePost(ctx context.Context, req CreatePostRequest) (*Post, error)
-	ListPosts(ctx context.Context) ([]*Post, error)
+	ListPosts(ctx context.Context, req ListPostsRequest) ([]*Post, error)
 	GetPost(ctx context.Context, postID string) (*Post, error)
 }
 
@@ -23,8 +24,12 @@ type BaseService struct {
 
 var _ Service = (*BaseService)(nil)
 
-func NewService(postRepo PostRepository, authzClient *authorization.Client) Service { //nolint:ireturn
-	return NewAuthorizationMiddleware(authzClient, NewBaseService(postRepo))
+func NewService( //nolint:ireturn
+	postRepo PostRepository,
+	authzClient *authorization.Client,
+	bus *events.Bus,
+) Service {
+	return NewAuthorizationMiddleware(authzClient, NewEventsMiddleware(bus, NewBaseService(postRepo)))
 }
 
 func NewBaseService(postRepo PostRepository) *BaseService {
@@ -35,15 +40,22 @@ func NewBaseService(postRepo PostRepository) *BaseService {
 
 type CreatePostRequest struct {
 	AuthorID string
-	Content  string
+	// CommunityID posts into a community, which requires membership. Empty posts outside any community.
+	CommunityID string
+	Content     string
 }
 
 func (svc *BaseService) CreatePost(ctx context.Context, req CreatePostRequest) (*Post, error) {
+	now := time.Now()
+
 	post := &Post{
-		ID:        uuid.NewString(),
-		AuthorID:  req.AuthorID,
-		Content:   req.Content,
-		CreatedAt: time.Now(),
+		ID:            uuid.NewString(),
+		AuthorID:      req.AuthorID,
+		CommunityID:   req.CommunityID,
+		Content:       req.Content,
+		CreatedAt:     now,
+		ReactionScore: 0,
+		Hotness:       hotness(0, now),
 	}
 
 	err := svc.postRepo.Insert(ctx, post)
@@ -54,8 +66,48 @@ func (svc *BaseService) CreatePost(ctx context.Context, req CreatePostRequest) (
 	return post, nil
 }
 
-func (svc *BaseService) ListPosts(ctx context.Context) ([]*Post, error) {
-	posts, err := svc.postRepo.List(ctx)
+type ListPostsRequest struct {
+	AuthorID    string
+	CommunityID string
+	// Sort is the order of the list. Empty means PostSortNew.
+	Sort PostSort
+	// Period is how far back the top sort looks. Empty means TopPeriodAll. Other sorts ignore it.
+	Period TopPeriod
+	// Before is the cursor of the last post of the previous page, from Post.Cursor with the same sort.
+	Before *PostCursor
+	Limit  int
+}
+
+func (svc *BaseService) ListPosts(ctx context.Context, req ListPostsRequest) ([]*Post, error) {
+	if req.Sort == "" {
+		req.Sort = PostSortNew
+	}
+
+	if !req.Sort.IsValid() {
+		return nil, InvalidPostSortError{Sort: req.Sort}
+	}
+
+	if req.Period == "" {
+		req.Period = TopPeriodAll
+	}
+
+	if !req.Period.IsValid() {
+		return nil, InvalidTopPeriodError{Period: req.Period}
+	}
+
+	since := time.Time{}
+	if req.Sort == PostSortTop {
+		since = req.Period.since(time.Now())
+	}
+
+	posts, err := svc.postRepo.List(ctx, &ListPostsParams{
+		AuthorID:    req.AuthorID,
+		CommunityID: req.CommunityID,
+		Sort:        req.Sort,
+		Since:       since,
+		Before:      req.Before,
+		Limit:       req.Limit,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list posts: %w", err)
 	}
@@ -0,0 +1,71 @@
+package contents
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nasermirzaei89/scribble/events"
+)
+
+// EventsMiddleware publishes the changes of the next service, in the same transaction.
+type EventsMiddleware struct {
+	bus  *events.Bus
+	next Service
+}
+
+var _ Service = (*EventsMiddleware)(nil)
+
+func NewEventsMiddleware(bus *events.Bus, next Service) *EventsMiddleware {
+	return &EventsMiddleware{
+		bus:  bus,
+		next: next,
+	}
+}
+
+func (mw *EventsMiddleware) CreatePost(ctx context.Context, req CreatePostRequest) (*Post, error) {
+	var post *Post
+
+	err := mw.bus.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+
+		post, err = mw.next.CreatePost(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to call next method: %w", err)
+		}
+
+		err = mw.bus.Publish(ctx, events.PostCreated{
+			PostID:      post.ID,
+			AuthorID:    post.AuthorID,
+			CommunityID: post.CommunityID,
+			CreatedAt:   post.CreatedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to publish event: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create post: %w", err)
+	}
+
+	return post, nil
+}
+
+func (mw *EventsMiddleware) ListPosts(ctx context.Context, req ListPostsRequest) ([]*Post, error) {
+	posts, err := mw.next.ListPosts(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return posts, nil
+}
+
+func (mw *EventsMiddleware) GetPost(ctx context.Context, postID string) (*Post, error) {
+	post, err := mw.next.GetPost(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return post, nil
+}
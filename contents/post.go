@@ -7,16 +7,61 @@ import (
 )
 
 type Post struct {
-	ID        string
-	AuthorID  string
-	Content   string
-	CreatedAt time.Time
+	ID       string
+	AuthorID string
+	// CommunityID is empty for posts outside any community.
+	CommunityID string
+	Content     string
+	CreatedAt   time.Time
+	// ReactionScore is the weighted sum of the reactions to the post, which the top sort ranks by.
+	ReactionScore int
+	// Hotness is the rank of the post in the hot sort.
+	Hotness float64
+}
+
+// Cursor returns the position of the post in a list in the sort.
+func (post *Post) Cursor(sort PostSort) *PostCursor {
+	cursor := &PostCursor{Score: 0, CreatedAt: post.CreatedAt, ID: post.ID}
+
+	switch sort {
+	case PostSortTop:
+		cursor.Score = float64(post.ReactionScore)
+	case PostSortHot:
+		cursor.Score = post.Hotness
+	case PostSortNew:
+	}
+
+	return cursor
 }
 
 type PostRepository interface {
 	Insert(ctx context.Context, post *Post) (err error)
 	Find(ctx context.Context, postID string) (post *Post, err error)
-	List(ctx context.Context) (posts []*Post, err error)
+	List(ctx context.Context, params *ListPostsParams) (posts []*Post, err error)
+}
+
+// PostCursor is the position of a post in a list of posts. The new sort orders posts from newest to oldest, the top
+// sort by reaction score and then like the new sort, and the hot sort by hotness with the ID breaking ties.
+type PostCursor struct {
+	// Score is the reaction score of the post in the top sort and its hotness in the hot sort.
+	Score     float64
+	CreatedAt time.Time
+	ID        string
+}
+
+type ListPostsParams struct {
+	// AuthorID limits the list to posts of the user. Empty means posts of every user.
+	AuthorID string
+	// CommunityID limits the list to posts of the community. Empty means posts of every community and none.
+	CommunityID string
+	// Sort is the order of the list.
+	Sort PostSort
+	// Since limits the list to posts created at or after it. The zero time means posts of all time.
+	Since time.Time
+	// Before limits the list to posts after the cursor in list order.
+	Before *PostCursor
+	// Limit is the maximum number of posts to return. Zero means no limit.
+	Limit int
 }
 
 type PostNotFoundError struct {
@@ -26,3 +71,19 @@ type PostNotFoundError struct {
 func (err PostNotFoundError) Error() string {
 	return fmt.Sprintf("post with id %q not found", err.ID)
 }
+
+type InvalidPostSortError struct {
+	Sort PostSort
+}
+
+func (err InvalidPostSortError) Error() string {
+	return fmt.Sprintf("invalid post sort: %q", err.Sort)
+}
+
+type InvalidTopPeriodError struct {
+	Period TopPeriod
+}
+
+func (err InvalidTopPeriodError) Error() string {
+	return fmt.Sprintf("invalid top period: %q", err.Period)
+}
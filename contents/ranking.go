@@ -0,0 +1,233 @@
+package contents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/events"
+)
+
+// PostSort is the order posts are listed in.
+type PostSort string
+
+const (
+	// PostSortNew lists posts from newest to oldest.
+	PostSortNew PostSort = "new"
+	// PostSortTop lists posts from the highest reaction score to the lowest, within a TopPeriod.
+	PostSortTop PostSort = "top"
+	// PostSortHot lists posts by their reaction score and comments, decayed by their age.
+	PostSortHot PostSort = "hot"
+)
+
+// PostSorts returns all post sorts, in the order they are offered.
+func PostSorts() []PostSort {
+	return []PostSort{PostSortHot, PostSortNew, PostSortTop}
+}
+
+func (sort PostSort) IsValid() bool {
+	switch sort {
+	case PostSortNew, PostSortTop, PostSortHot:
+		return true
+	default:
+		return false
+	}
+}
+
+// TopPeriod is how far back the top sort looks for posts.
+type TopPeriod string
+
+const (
+	TopPeriodDay   TopPeriod = "day"
+	TopPeriodWeek  TopPeriod = "week"
+	TopPeriodMonth TopPeriod = "month"
+	TopPeriodAll   TopPeriod = "all"
+)
+
+// TopPeriods returns all top periods, in the order they are offered.
+func TopPeriods() []TopPeriod {
+	return []TopPeriod{TopPeriodDay, TopPeriodWeek, TopPeriodMonth, TopPeriodAll}
+}
+
+func (period TopPeriod) IsValid() bool {
+	switch period {
+	case TopPeriodDay, TopPeriodWeek, TopPeriodMonth, TopPeriodAll:
+		return true
+	default:
+		return false
+	}
+}
+
+// since returns when the period starts, or the zero time for all time.
+func (period TopPeriod) since(now time.Time) time.Time {
+	switch period {
+	case TopPeriodDay:
+		return now.AddDate(0, 0, -1)
+	case TopPeriodWeek:
+		return now.AddDate(0, 0, -7)
+	case TopPeriodMonth:
+		return now.AddDate(0, -1, 0)
+	default:
+		return time.Time{}
+	}
+}
+
+// hotTenfold is how much newer a post with a tenth of the score of another ranks as hot as it.
+const hotTenfold = 12*time.Hour + 30*time.Minute
+
+// hotness ranks a post in the hot sort. It grows with the logarithm of the score and linearly with the creation time,
+// so it never needs recomputing as posts age, only when their score changes.
+func hotness(score int, createdAt time.Time) float64 {
+	order := math.Log10(math.Max(math.Abs(float64(score)), 1))
+	if score < 0 {
+		order = -order
+	}
+
+	return order + float64(createdAt.UnixMilli())/float64(hotTenfold.Milliseconds())
+}
+
+// RankingConfig weighs the reactions and comments of posts.
+type RankingConfig struct {
+	// EmojiWeights are the points a reaction with the emoji adds to the reaction score of a post. Emojis not in it add
+	// DefaultEmojiWeight.
+	EmojiWeights       map[string]int
+	DefaultEmojiWeight int
+	// CommentWeight is the points a comment adds to the score of a post in the hot sort.
+	CommentWeight int
+}
+
+// DefaultRankingConfig counts a thumbs down against the post and every other reaction and comment for it.
+func DefaultRankingConfig() RankingConfig {
+	return RankingConfig{
+		EmojiWeights:       map[string]int{"👎": -1},
+		DefaultEmojiWeight: 1,
+		CommentWeight:      1,
+	}
+}
+
+func (cfg RankingConfig) reactionScore(reactionCounts map[string]int) int {
+	score := 0
+
+	for emoji, count := range reactionCounts {
+		weight, ok := cfg.EmojiWeights[emoji]
+		if !ok {
+			weight = cfg.DefaultEmojiWeight
+		}
+
+		score += weight * count
+	}
+
+	return score
+}
+
+// PostRankingRepository counts what ranks posts and keeps their ranks.
+type PostRankingRepository interface {
+	CountReactions(ctx context.Context, postID string) (reactionCounts map[string]int, err error)
+	CountComments(ctx context.Context, postID string) (comments int, err error)
+	SaveRank(ctx context.Context, postID string, reactionScore int, hotness float64) (err error)
+	ListPostIDs(ctx context.Context) (postIDs []string, err error)
+}
+
+// postTargetType is the target type of reactions to posts, reactions.TargetTypePost, which imports this package.
+const postTargetType = "post"
+
+// Ranker keeps the ranks of posts up to date. Each reaction and comment reranks the one post it changes.
+type Ranker struct {
+	cfg         RankingConfig
+	postRepo    PostRepository
+	rankingRepo PostRankingRepository
+}
+
+func NewRanker(cfg RankingConfig, postRepo PostRepository, rankingRepo PostRankingRepository) *Ranker {
+	if cfg.EmojiWeights == nil {
+		cfg.EmojiWeights = DefaultRankingConfig().EmojiWeights
+	}
+
+	return &Ranker{
+		cfg:         cfg,
+		postRepo:    postRepo,
+		rankingRepo: rankingRepo,
+	}
+}
+
+func (ranker *Ranker) Subscribe(bus *events.Bus) {
+	events.SubscribeAsync(bus, ranker.handleReactionToggled)
+	events.SubscribeAsync(bus, ranker.handleCommentCreated)
+}
+
+func (ranker *Ranker) handleReactionToggled(ctx context.Context, event events.ReactionToggled) error {
+	if event.TargetType != postTargetType {
+		return nil
+	}
+
+	return ranker.update(ctx, event.TargetID)
+}
+
+func (ranker *Ranker) handleCommentCreated(ctx context.Context, event events.CommentCreated) error {
+	return ranker.update(ctx, event.PostID)
+}
+
+// update reranks the post, if the post still exists.
+func (ranker *Ranker) update(ctx context.Context, postID string) error {
+	err := ranker.Rerank(ctx, postID)
+	if err != nil {
+		if _, ok := errors.AsType[PostNotFoundError](err); ok {
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// Rerank ranks the post from scratch and saves the rank.
+func (ranker *Ranker) Rerank(ctx context.Context, postID string) error {
+	post, err := ranker.postRepo.Find(ctx, postID)
+	if err != nil {
+		return fmt.Errorf("failed to find post: %w", err)
+	}
+
+	reactionCounts, err := ranker.rankingRepo.CountReactions(ctx, postID)
+	if err != nil {
+		return fmt.Errorf("failed to count reactions: %w", err)
+	}
+
+	comments, err := ranker.rankingRepo.CountComments(ctx, postID)
+	if err != nil {
+		return fmt.Errorf("failed to count comments: %w", err)
+	}
+
+	reactionScore := ranker.cfg.reactionScore(reactionCounts)
+
+	err = ranker.rankingRepo.SaveRank(
+		ctx,
+		postID,
+		reactionScore,
+		hotness(reactionScore+ranker.cfg.CommentWeight*comments, post.CreatedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save rank: %w", err)
+	}
+
+	return nil
+}
+
+// RerankAll ranks every post, like after the weights changed, and returns how many were ranked.
+func (ranker *Ranker) RerankAll(ctx context.Context) (int, error) {
+	postIDs, err := ranker.rankingRepo.ListPostIDs(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list post ids: %w", err)
+	}
+
+	for _, postID := range postIDs {
+		err := ranker.Rerank(ctx, postID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to rerank post %q: %w", postID, err)
+		}
+	}
+
+	return len(postIDs), nil
+}
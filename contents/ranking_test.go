@@ -0,0 +1,216 @@
+package contents_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nasermirzaei89/scribble/authentication"
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/database/sqlite3"
+	"github.com/nasermirzaei89/scribble/discuss"
+	"github.com/nasermirzaei89/scribble/events"
+	"github.com/nasermirzaei89/scribble/reactions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRanker(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sqlite3.NewDB(ctx, "file:TestRanker?mode=memory&cache=shared")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := db.Close()
+		require.NoError(t, err)
+	})
+
+	err = sqlite3.MigrateUp(ctx, db)
+	require.NoError(t, err)
+
+	userRepo := sqlite3.NewUserRepository(db)
+	postRepo := sqlite3.NewPostRepository(db)
+	commentRepo := sqlite3.NewCommentRepository(db)
+	commentEditRepo := sqlite3.NewCommentEditRepository(db)
+
+	bus := events.NewBus(sqlite3.NewEventOutboxRepository(db), sqlite3.NewTransactor(db))
+
+	ranker := contents.NewRanker(contents.DefaultRankingConfig(), postRepo, sqlite3.NewPostRankingRepository(db))
+	ranker.Subscribe(bus)
+
+	for _, username := range []string{"alice", "bob", "carol"} {
+		err := userRepo.Insert(ctx, &authentication.User{
+			ID:           username,
+			Username:     username,
+			PasswordHash: "hash",
+			RegisteredAt: time.Now(),
+		})
+		require.NoError(t, err)
+	}
+
+	targets := reactions.NewTargetRegistry()
+
+	err = targets.Register(reactions.PostTargetType(postRepo))
+	require.NoError(t, err)
+
+	contentsSvc := contents.NewEventsMiddleware(bus, contents.NewBaseService(postRepo))
+	discussSvc := discuss.NewEventsMiddleware(bus, discuss.NewBaseService(commentRepo, commentEditRepo))
+	reactionsSvc := reactions.NewEventsMiddleware(bus, reactions.NewBaseService(
+		reactions.Config{DefaultEmojis: []string{"👍", "👎"}, MaxPerUser: 1},
+		targets,
+		sqlite3.NewUserReactionRepository(db),
+		sqlite3.NewReactionSetRepository(db),
+		postRepo,
+		commentRepo,
+		sqlite3.NewCustomEmojiRepository(db),
+	))
+
+	// Posts are created at set times, so their hotness by age does not depend on how fast the test runs.
+	createPost := func(content string, createdAt time.Time) *contents.Post {
+		t.Helper()
+
+		post := &contents.Post{
+			ID:            uuid.NewString(),
+			AuthorID:      "alice",
+			CommunityID:   "",
+			Content:       content,
+			CreatedAt:     createdAt,
+			ReactionScore: 0,
+			Hotness:       0,
+		}
+
+		err := postRepo.Insert(ctx, post)
+		require.NoError(t, err)
+
+		err = ranker.Rerank(ctx, post.ID)
+		require.NoError(t, err)
+
+		return post
+	}
+
+	react := func(userID, postID, emoji string) {
+		t.Helper()
+
+		err := reactionsSvc.ToggleMyReaction(authcontext.WithSubject(ctx, userID), reactions.TargetTypePost, postID, emoji)
+		require.NoError(t, err)
+
+		_, err = bus.ProcessOutbox(ctx)
+		require.NoError(t, err)
+	}
+
+	listIDs := func(sort contents.PostSort, period contents.TopPeriod) []string {
+		t.Helper()
+
+		posts, err := contentsSvc.ListPosts(ctx, contents.ListPostsRequest{
+			AuthorID:    "",
+			CommunityID: "",
+			Sort:        sort,
+			Period:      period,
+			Before:      nil,
+			Limit:       0,
+		})
+		require.NoError(t, err)
+
+		ids := make([]string, 0, len(posts))
+		for _, post := range posts {
+			ids = append(ids, post.ID)
+		}
+
+		return ids
+	}
+
+	now := time.Now()
+	older := createPost("older", now.Add(-time.Hour))
+	newer := createPost("newer", now)
+
+	t.Run("new posts are hot by age", func(t *testing.T) {
+		assert.Equal(t, []string{newer.ID, older.ID}, listIDs(contents.PostSortHot, ""))
+		assert.Equal(t, []string{newer.ID, older.ID}, listIDs("", ""))
+	})
+
+	t.Run("reactions rank posts", func(t *testing.T) {
+		react("bob", older.ID, "👍")
+		react("carol", older.ID, "👍")
+		react("bob", newer.ID, "👎")
+
+		post, err := contentsSvc.GetPost(ctx, older.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, post.ReactionScore)
+
+		post, err = contentsSvc.GetPost(ctx, newer.ID)
+		require.NoError(t, err)
+		assert.Equal(t, -1, post.ReactionScore)
+
+		assert.Equal(t, []string{older.ID, newer.ID}, listIDs(contents.PostSortTop, contents.TopPeriodDay))
+		assert.Equal(t, []string{older.ID, newer.ID}, listIDs(contents.PostSortHot, ""))
+		assert.Equal(t, []string{newer.ID, older.ID}, listIDs(contents.PostSortNew, ""))
+	})
+
+	t.Run("comments make posts hot", func(t *testing.T) {
+		hotnessBefore := func() float64 {
+			post, err := contentsSvc.GetPost(ctx, newer.ID)
+			require.NoError(t, err)
+
+			return post.Hotness
+		}()
+
+		for range 4 {
+			_, err := discussSvc.CreateComment(ctx, discuss.CreateCommentRequest{
+				PostID:   newer.ID,
+				AuthorID: "carol",
+				Content:  "comment",
+				ReplyTo:  "",
+			})
+			require.NoError(t, err)
+		}
+
+		_, err := bus.ProcessOutbox(ctx)
+		require.NoError(t, err)
+
+		post, err := contentsSvc.GetPost(ctx, newer.ID)
+		require.NoError(t, err)
+		assert.Equal(t, -1, post.ReactionScore, "comments do not count in the top sort")
+		assert.Greater(t, post.Hotness, hotnessBefore)
+
+		assert.Equal(t, []string{newer.ID, older.ID}, listIDs(contents.PostSortHot, ""))
+	})
+
+	t.Run("top periods", func(t *testing.T) {
+		err := postRepo.Insert(ctx, &contents.Post{
+			ID:            "old-post",
+			AuthorID:      "alice",
+			CommunityID:   "",
+			Content:       "old",
+			CreatedAt:     time.Now().AddDate(0, 0, -3),
+			ReactionScore: 10,
+			Hotness:       0,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{older.ID, newer.ID}, listIDs(contents.PostSortTop, contents.TopPeriodDay))
+		assert.Equal(t, []string{"old-post", older.ID, newer.ID}, listIDs(contents.PostSortTop, contents.TopPeriodWeek))
+		assert.Equal(t, []string{"old-post", older.ID, newer.ID}, listIDs(contents.PostSortTop, ""))
+	})
+
+	t.Run("rerank all", func(t *testing.T) {
+		count, err := ranker.RerankAll(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+
+		post, err := contentsSvc.GetPost(ctx, "old-post")
+		require.NoError(t, err)
+		assert.Equal(t, 0, post.ReactionScore)
+		assert.Equal(t, []string{newer.ID, older.ID, "old-post"}, listIDs(contents.PostSortHot, ""))
+	})
+
+	t.Run("invalid sort and period", func(t *testing.T) {
+		_, err := contentsSvc.ListPosts(ctx, contents.ListPostsRequest{Sort: "best"})
+		require.ErrorAs(t, err, &contents.InvalidPostSortError{})
+
+		_, err = contentsSvc.ListPosts(ctx, contents.ListPostsRequest{Sort: contents.PostSortTop, Period: "year"})
+		require.ErrorAs(t, err, &contents.InvalidTopPeriodError{})
+	})
+}
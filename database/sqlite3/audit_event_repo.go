@@ -0,0 +1,165 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/nasermirzaei89/scribble/audit"
+)
+
+const tableAuditEvents = "audit_events"
+
+type AuditEventRepository struct {
+	db *sql.DB
+}
+
+var _ audit.EventRepository = (*AuditEventRepository)(nil)
+
+func NewAuditEventRepository(db *sql.DB) *AuditEventRepository {
+	return &AuditEventRepository{db: db}
+}
+
+const (
+	auditEventFieldID         = "id"
+	auditEventFieldOccurredAt = "occurred_at"
+	auditEventFieldActor      = "actor"
+	auditEventFieldAction     = "action"
+	auditEventFieldTarget     = "target"
+	auditEventFieldOutcome    = "outcome"
+	auditEventFieldIP         = "ip"
+	auditEventFieldUserAgent  = "user_agent"
+	auditEventFieldDetail     = "detail"
+)
+
+func auditEventColumns() []string {
+	return []string{
+		auditEventFieldID,
+		auditEventFieldOccurredAt,
+		auditEventFieldActor,
+		auditEventFieldAction,
+		auditEventFieldTarget,
+		auditEventFieldOutcome,
+		auditEventFieldIP,
+		auditEventFieldUserAgent,
+		auditEventFieldDetail,
+	}
+}
+
+func scanAuditEvent(row sq.RowScanner) (*audit.Event, error) {
+	var event audit.Event
+
+	err := row.Scan(
+		&event.ID,
+		&event.OccurredAt,
+		&event.Actor,
+		&event.Action,
+		&event.Target,
+		&event.Outcome,
+		&event.IP,
+		&event.UserAgent,
+		&event.Detail,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	return &event, nil
+}
+
+func (repo *AuditEventRepository) Insert(ctx context.Context, event *audit.Event) error {
+	q := sq.Insert(tableAuditEvents).
+		Columns(auditEventColumns()...).
+		Values(
+			event.ID,
+			event.OccurredAt,
+			event.Actor,
+			event.Action,
+			event.Target,
+			event.Outcome,
+			event.IP,
+			event.UserAgent,
+			event.Detail,
+		)
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec insert: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *AuditEventRepository) List(ctx context.Context, params *audit.ListEventsParams) ([]*audit.Event, error) {
+	query := sq.Select(auditEventColumns()...).
+		From(tableAuditEvents).
+		OrderBy(auditEventFieldOccurredAt+" DESC", auditEventFieldID+" DESC")
+
+	if params.Actor != "" {
+		query = query.Where(sq.Eq{auditEventFieldActor: params.Actor})
+	}
+
+	if params.Action != "" {
+		query = query.Where(sq.Eq{auditEventFieldAction: params.Action})
+	}
+
+	if params.Target != "" {
+		query = query.Where(sq.Eq{auditEventFieldTarget: params.Target})
+	}
+
+	if params.Outcome != "" {
+		query = query.Where(sq.Eq{auditEventFieldOutcome: params.Outcome})
+	}
+
+	if !params.Since.IsZero() {
+		query = query.Where(sq.GtOrEq{auditEventFieldOccurredAt: params.Since})
+	}
+
+	if !params.Until.IsZero() {
+		query = query.Where(sq.Lt{auditEventFieldOccurredAt: params.Until})
+	}
+
+	if params.Limit > 0 {
+		query = query.Limit(uint64(params.Limit))
+	}
+
+	if params.Offset > 0 {
+		query = query.Offset(uint64(params.Offset))
+	}
+
+	query = query.RunWith(runner(ctx, repo.db))
+
+	rows, err := query.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	events := make([]*audit.Event, 0)
+
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan audit event failed: %w", err)
+		}
+
+		events = append(events, event)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("rows iteration failed: %w", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,117 @@
+package sqlite3_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nasermirzaei89/scribble/audit"
+	"github.com/nasermirzaei89/scribble/database/sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditEventRepository(t *testing.T) {
+	ctx, db := newTestDB(t)
+
+	repo := sqlite3.NewAuditEventRepository(db)
+
+	t.Run("List empty", func(t *testing.T) {
+		events, err := repo.List(ctx, &audit.ListEventsParams{})
+		require.NoError(t, err)
+		assert.Empty(t, events)
+	})
+
+	event1 := &audit.Event{
+		ID:         uuid.NewString(),
+		OccurredAt: time.Date(2026, 2, 24, 10, 0, 0, 0, time.UTC),
+		Actor:      "user1",
+		Action:     audit.ActionLogin,
+		Target:     "user:user1",
+		Outcome:    audit.OutcomeSuccess,
+		IP:         "127.0.0.1",
+		UserAgent:  "test-agent",
+		Detail:     "",
+	}
+
+	event2 := &audit.Event{
+		ID:         uuid.NewString(),
+		OccurredAt: time.Date(2026, 2, 24, 11, 0, 0, 0, time.UTC),
+		Actor:      "system:anonymous",
+		Action:     audit.ActionLogin,
+		Target:     "username:user1",
+		Outcome:    audit.OutcomeFailure,
+		IP:         "127.0.0.2",
+		UserAgent:  "test-agent",
+		Detail:     "invalid credentials",
+	}
+
+	event3 := &audit.Event{
+		ID:         uuid.NewString(),
+		OccurredAt: time.Date(2026, 2, 24, 12, 0, 0, 0, time.UTC),
+		Actor:      "user1",
+		Action:     audit.ActionAddToGroup,
+		Target:     "user2",
+		Outcome:    audit.OutcomeSuccess,
+		IP:         "127.0.0.1",
+		UserAgent:  "test-agent",
+		Detail:     "groups=moderators",
+	}
+
+	t.Run("Insert and list newest first", func(t *testing.T) {
+		for _, event := range []*audit.Event{event1, event2, event3} {
+			err := repo.Insert(ctx, event)
+			require.NoError(t, err)
+		}
+
+		events, err := repo.List(ctx, &audit.ListEventsParams{})
+		require.NoError(t, err)
+		require.Len(t, events, 3)
+		assert.Equal(t, event3.ID, events[0].ID)
+		assert.Equal(t, event2.ID, events[1].ID)
+		assert.Equal(t, event1.ID, events[2].ID)
+
+		assert.Equal(t, event2.Actor, events[1].Actor)
+		assert.Equal(t, event2.Action, events[1].Action)
+		assert.Equal(t, event2.Target, events[1].Target)
+		assert.Equal(t, event2.Outcome, events[1].Outcome)
+		assert.Equal(t, event2.IP, events[1].IP)
+		assert.Equal(t, event2.UserAgent, events[1].UserAgent)
+		assert.Equal(t, event2.Detail, events[1].Detail)
+		assert.True(t, events[1].OccurredAt.Equal(event2.OccurredAt))
+	})
+
+	t.Run("List with filters", func(t *testing.T) {
+		events, err := repo.List(ctx, &audit.ListEventsParams{Actor: "user1"})
+		require.NoError(t, err)
+		assert.Len(t, events, 2)
+
+		events, err = repo.List(ctx, &audit.ListEventsParams{Action: audit.ActionLogin, Outcome: audit.OutcomeFailure})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, event2.ID, events[0].ID)
+
+		events, err = repo.List(ctx, &audit.ListEventsParams{
+			Since: time.Date(2026, 2, 24, 10, 30, 0, 0, time.UTC),
+			Until: time.Date(2026, 2, 24, 12, 0, 0, 0, time.UTC),
+		})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, event2.ID, events[0].ID)
+	})
+
+	t.Run("List with limit and offset", func(t *testing.T) {
+		events, err := repo.List(ctx, &audit.ListEventsParams{Limit: 1, Offset: 1})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, event2.ID, events[0].ID)
+	})
+
+	t.Run("Events are append-only", func(t *testing.T) {
+		_, err := db.ExecContext(ctx, "UPDATE audit_events SET outcome = 'success' WHERE id = ?", event2.ID)
+		require.Error(t, err)
+
+		_, err = db.ExecContext(ctx, "DELETE FROM audit_events WHERE id = ?", event2.ID)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,251 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/casbin/casbin/v3/model"
+	"github.com/casbin/casbin/v3/persist"
+)
+
+const tableCasbinRules = "casbin_rule"
+
+// CasbinAdapter stores authorization policies. Policies written with a context take part in the transaction of the
+// context, so they are written together with the changes they mirror, like a new user or a community membership.
+type CasbinAdapter struct {
+	db *sql.DB
+}
+
+var (
+	_ persist.Adapter             = (*CasbinAdapter)(nil)
+	_ persist.BatchAdapter        = (*CasbinAdapter)(nil)
+	_ persist.ContextAdapter      = (*CasbinAdapter)(nil)
+	_ persist.ContextBatchAdapter = (*CasbinAdapter)(nil)
+)
+
+func NewCasbinAdapter(db *sql.DB) *CasbinAdapter {
+	return &CasbinAdapter{db: db}
+}
+
+const casbinRuleFieldPType = "p_type"
+
+// casbinRuleValueCount is how many values a rule may have, in the columns v0 to v5.
+const casbinRuleValueCount = 6
+
+func casbinRuleValueField(index int) string {
+	return "v" + strconv.Itoa(index)
+}
+
+func casbinRuleColumns() []string {
+	columns := []string{casbinRuleFieldPType}
+	for i := range casbinRuleValueCount {
+		columns = append(columns, casbinRuleValueField(i))
+	}
+
+	return columns
+}
+
+// casbinRuleValues returns the row of the rule, with the unused values empty.
+func casbinRuleValues(ptype string, rule []string) []any {
+	values := make([]any, 0, casbinRuleValueCount+1)
+	values = append(values, ptype)
+
+	for i := range casbinRuleValueCount {
+		value := ""
+		if i < len(rule) {
+			value = rule[i]
+		}
+
+		values = append(values, value)
+	}
+
+	return values
+}
+
+func (adapter *CasbinAdapter) LoadPolicy(model model.Model) error {
+	return adapter.LoadPolicyCtx(context.Background(), model)
+}
+
+func (adapter *CasbinAdapter) LoadPolicyCtx(ctx context.Context, model model.Model) error {
+	q := sq.Select(casbinRuleColumns()...).From(tableCasbinRules)
+
+	q = q.RunWith(runner(ctx, adapter.db))
+
+	rows, err := q.QueryContext(ctx)
+	if err != nil {
+		return fmt.Errorf("query failed: %w", err)
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	for rows.Next() {
+		line := make([]string, casbinRuleValueCount+1)
+
+		dest := make([]any, 0, len(line))
+		for i := range line {
+			dest = append(dest, &line[i])
+		}
+
+		err = rows.Scan(dest...)
+		if err != nil {
+			return fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		// Unused values are empty, and are not part of the rule.
+		for len(line) > 1 && line[len(line)-1] == "" {
+			line = line[:len(line)-1]
+		}
+
+		err = persist.LoadPolicyArray(line, model)
+		if err != nil {
+			return fmt.Errorf("failed to load policy line: %w", err)
+		}
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return fmt.Errorf("rows iteration failed: %w", err)
+	}
+
+	return nil
+}
+
+func (adapter *CasbinAdapter) SavePolicy(model model.Model) error {
+	return adapter.SavePolicyCtx(context.Background(), model)
+}
+
+func (adapter *CasbinAdapter) SavePolicyCtx(ctx context.Context, model model.Model) error {
+	return withinTransaction(ctx, adapter.db, func(ctx context.Context) error {
+		q := sq.Delete(tableCasbinRules).RunWith(runner(ctx, adapter.db))
+
+		_, err := q.ExecContext(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to exec delete: %w", err)
+		}
+
+		for _, sec := range []string{"p", "g"} {
+			for ptype, assertion := range model[sec] {
+				err = adapter.AddPoliciesCtx(ctx, sec, ptype, assertion.Policy)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+func (adapter *CasbinAdapter) AddPolicy(sec, ptype string, rule []string) error {
+	return adapter.AddPolicyCtx(context.Background(), sec, ptype, rule)
+}
+
+func (adapter *CasbinAdapter) AddPolicyCtx(ctx context.Context, sec, ptype string, rule []string) error {
+	return adapter.AddPoliciesCtx(ctx, sec, ptype, [][]string{rule})
+}
+
+func (adapter *CasbinAdapter) AddPolicies(sec, ptype string, rules [][]string) error {
+	return adapter.AddPoliciesCtx(context.Background(), sec, ptype, rules)
+}
+
+func (adapter *CasbinAdapter) AddPoliciesCtx(ctx context.Context, _, ptype string, rules [][]string) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	q := sq.Insert(tableCasbinRules).Columns(casbinRuleColumns()...)
+	for _, rule := range rules {
+		q = q.Values(casbinRuleValues(ptype, rule)...)
+	}
+
+	q = q.RunWith(runner(ctx, adapter.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec insert: %w", err)
+	}
+
+	return nil
+}
+
+func (adapter *CasbinAdapter) RemovePolicy(sec, ptype string, rule []string) error {
+	return adapter.RemovePolicyCtx(context.Background(), sec, ptype, rule)
+}
+
+func (adapter *CasbinAdapter) RemovePolicyCtx(ctx context.Context, sec, ptype string, rule []string) error {
+	return adapter.RemovePoliciesCtx(ctx, sec, ptype, [][]string{rule})
+}
+
+func (adapter *CasbinAdapter) RemovePolicies(sec, ptype string, rules [][]string) error {
+	return adapter.RemovePoliciesCtx(context.Background(), sec, ptype, rules)
+}
+
+func (adapter *CasbinAdapter) RemovePoliciesCtx(ctx context.Context, _, ptype string, rules [][]string) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	columns := casbinRuleColumns()
+	conditions := make(sq.Or, 0, len(rules))
+
+	for _, rule := range rules {
+		condition := sq.Eq{}
+		for i, value := range casbinRuleValues(ptype, rule) {
+			condition[columns[i]] = value
+		}
+
+		conditions = append(conditions, condition)
+	}
+
+	q := sq.Delete(tableCasbinRules).Where(conditions)
+
+	q = q.RunWith(runner(ctx, adapter.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec delete: %w", err)
+	}
+
+	return nil
+}
+
+func (adapter *CasbinAdapter) RemoveFilteredPolicy(sec, ptype string, fieldIndex int, fieldValues ...string) error {
+	return adapter.RemoveFilteredPolicyCtx(context.Background(), sec, ptype, fieldIndex, fieldValues...)
+}
+
+// RemoveFilteredPolicyCtx removes the rules whose values from the field index on match the field values. Empty field
+// values match any value.
+func (adapter *CasbinAdapter) RemoveFilteredPolicyCtx(
+	ctx context.Context,
+	_, ptype string,
+	fieldIndex int,
+	fieldValues ...string,
+) error {
+	condition := sq.Eq{casbinRuleFieldPType: ptype}
+
+	for i, value := range fieldValues {
+		if value != "" && fieldIndex+i < casbinRuleValueCount {
+			condition[casbinRuleValueField(fieldIndex+i)] = value
+		}
+	}
+
+	q := sq.Delete(tableCasbinRules).Where(condition)
+
+	q = q.RunWith(runner(ctx, adapter.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec delete: %w", err)
+	}
+
+	return nil
+}
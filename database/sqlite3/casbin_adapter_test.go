@@ -0,0 +1,95 @@
+package sqlite3_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/casbin/casbin/v3/model"
+	"github.com/nasermirzaei89/scribble/database/sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCasbinAdapter(t *testing.T) {
+	ctx, db := newTestDB(t)
+
+	adapter := sqlite3.NewCasbinAdapter(db)
+	transactor := sqlite3.NewTransactor(db)
+
+	loadPolicies := func(t *testing.T) ([][]string, [][]string) {
+		t.Helper()
+
+		m, err := model.NewModelFromString(`
+[request_definition]
+r = sub, dom, obj, act
+
+[policy_definition]
+p = sub, dom, obj, act
+
+[role_definition]
+g = _, _, _
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = g(r.sub, p.sub, r.dom) && r.dom == p.dom && r.obj == p.obj && r.act == p.act
+`)
+		require.NoError(t, err)
+
+		err = adapter.LoadPolicyCtx(ctx, m)
+		require.NoError(t, err)
+
+		return m["p"]["p"].Policy, m["g"]["g"].Policy
+	}
+
+	err := adapter.AddPoliciesCtx(ctx, "p", "p", [][]string{
+		{"role:admin", "*", "*", "deletePost"},
+		{"role:member", "community:1", "*", "createPost"},
+	})
+	require.NoError(t, err)
+
+	err = adapter.AddPolicyCtx(ctx, "g", "g", []string{"user1", "role:member", "community:1"})
+	require.NoError(t, err)
+
+	policies, groups := loadPolicies(t)
+	assert.Contains(t, policies, []string{"role:admin", "*", "*", "deletePost"})
+	assert.Contains(t, policies, []string{"role:member", "community:1", "*", "createPost"})
+	assert.Contains(t, groups, []string{"user1", "role:member", "community:1"})
+
+	t.Run("remove", func(t *testing.T) {
+		err := adapter.RemovePolicyCtx(ctx, "p", "p", []string{"role:admin", "*", "*", "deletePost"})
+		require.NoError(t, err)
+
+		policies, _ := loadPolicies(t)
+		assert.NotContains(t, policies, []string{"role:admin", "*", "*", "deletePost"})
+		assert.Contains(t, policies, []string{"role:member", "community:1", "*", "createPost"})
+	})
+
+	t.Run("remove filtered", func(t *testing.T) {
+		err := adapter.AddPolicyCtx(ctx, "g", "g", []string{"user2", "role:member", "community:1"})
+		require.NoError(t, err)
+
+		err = adapter.RemoveFilteredPolicyCtx(ctx, "g", "g", 1, "role:member", "community:1")
+		require.NoError(t, err)
+
+		_, groups := loadPolicies(t)
+		assert.Empty(t, groups)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		errFailed := errors.New("failed")
+
+		err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			err := adapter.AddPolicyCtx(ctx, "g", "g", []string{"user3", "role:member", "community:1"})
+			require.NoError(t, err)
+
+			return errFailed
+		})
+		require.ErrorIs(t, err, errFailed)
+
+		_, groups := loadPolicies(t)
+		assert.NotContains(t, groups, []string{"user3", "role:member", "community:1"})
+	})
+}
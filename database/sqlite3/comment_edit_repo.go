@@ -0,0 +1,128 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/nasermirzaei89/scribble/discuss"
+)
+
+const tableCommentEdits = "comment_edits"
+
+type CommentEditRepository struct {
+	db *sql.DB
+}
+
+var _ discuss.CommentEditRepository = (*CommentEditRepository)(nil)
+
+func NewCommentEditRepository(db *sql.DB) *CommentEditRepository {
+	return &CommentEditRepository{db: db}
+}
+
+const (
+	commentEditFieldID        = "id"
+	commentEditFieldCommentID = "comment_id"
+	commentEditFieldContent   = "content"
+	commentEditFieldEditedAt  = "edited_at"
+)
+
+func commentEditColumns() []string {
+	return []string{
+		commentEditFieldID,
+		commentEditFieldCommentID,
+		commentEditFieldContent,
+		commentEditFieldEditedAt,
+	}
+}
+
+func scanCommentEdit(row sq.RowScanner) (*discuss.CommentEdit, error) {
+	var edit discuss.CommentEdit
+
+	err := row.Scan(
+		&edit.ID,
+		&edit.CommentID,
+		&edit.Content,
+		&edit.EditedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	return &edit, nil
+}
+
+func (repo *CommentEditRepository) Insert(ctx context.Context, edit *discuss.CommentEdit) error {
+	q := sq.Insert(tableCommentEdits).
+		Columns(commentEditColumns()...).
+		Values(
+			edit.ID,
+			edit.CommentID,
+			edit.Content,
+			edit.EditedAt.UTC(),
+		)
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec insert: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *CommentEditRepository) List(ctx context.Context, commentID string) ([]*discuss.CommentEdit, error) {
+	q := sq.Select(commentEditColumns()...).
+		From(tableCommentEdits).
+		Where(sq.Eq{commentEditFieldCommentID: commentID}).
+		OrderBy(commentEditFieldEditedAt+" DESC", commentEditFieldID+" DESC")
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	rows, err := q.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	edits := make([]*discuss.CommentEdit, 0)
+
+	for rows.Next() {
+		edit, err := scanCommentEdit(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan comment edit failed: %w", err)
+		}
+
+		edits = append(edits, edit)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("rows iteration failed: %w", err)
+	}
+
+	return edits, nil
+}
+
+func (repo *CommentEditRepository) DeleteAll(ctx context.Context, commentID string) error {
+	q := sq.Delete(tableCommentEdits).
+		Where(sq.Eq{commentEditFieldCommentID: commentID})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec delete: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/nasermirzaei89/scribble/discuss"
+	"github.com/nasermirzaei89/scribble/reactions"
+)
+
+type CommentRankingRepository struct {
+	db *sql.DB
+}
+
+var _ discuss.CommentRankingRepository = (*CommentRankingRepository)(nil)
+
+func NewCommentRankingRepository(db *sql.DB) *CommentRankingRepository {
+	return &CommentRankingRepository{db: db}
+}
+
+func (repo *CommentRankingRepository) CountReactions(ctx context.Context, commentID string) (map[string]int, error) {
+	q := sq.Select(reactionCountFieldEmoji, reactionCountFieldCount).
+		From(tableReactionCounts).
+		Where(sq.Eq{
+			reactionCountFieldTargetType: reactions.TargetTypeComment,
+			reactionCountFieldTargetID:   commentID,
+		})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	rows, err := q.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	counts := make(map[string]int)
+
+	for rows.Next() {
+		var emoji string
+
+		var count int
+
+		err := rows.Scan(&emoji, &count)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		counts[emoji] = count
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return counts, nil
+}
+
+func (repo *CommentRankingRepository) SaveRank(ctx context.Context, commentID string, reactionScore int) error {
+	q := sq.Update(tableComments).
+		Set(commentFieldScore, reactionScore).
+		Where(sq.Eq{commentFieldID: commentID})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec update: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *CommentRankingRepository) ListCommentIDs(ctx context.Context) ([]string, error) {
+	q := sq.Select(commentFieldID).
+		From(tableComments).
+		OrderBy(commentFieldID + " ASC")
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	rows, err := q.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	commentIDs := make([]string, 0)
+
+	for rows.Next() {
+		var commentID string
+
+		err := rows.Scan(&commentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		commentIDs = append(commentIDs, commentID)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return commentIDs, nil
+}
@@ -3,8 +3,10 @@ package sqlite3
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/nasermirzaei89/scribble/discuss"
@@ -29,6 +31,9 @@ const (
 	commentFieldReplyTo   = "reply_to"
 	commentFieldContent   = "content"
 	commentFieldCreatedAt = "created_at"
+	commentFieldScore     = "reaction_score"
+	commentFieldEditedAt  = "edited_at"
+	commentFieldDeletedAt = "deleted_at"
 )
 
 func commentColumns() []string {
@@ -39,6 +44,9 @@ func commentColumns() []string {
 		commentFieldReplyTo,
 		commentFieldContent,
 		commentFieldCreatedAt,
+		commentFieldScore,
+		commentFieldEditedAt,
+		commentFieldDeletedAt,
 	}
 }
 
@@ -52,6 +60,9 @@ func scanComment(row sq.RowScanner) (*discuss.Comment, error) {
 		&comment.ReplyTo,
 		&comment.Content,
 		&comment.CreatedAt,
+		&comment.ReactionScore,
+		&comment.EditedAt,
+		&comment.DeletedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan row: %w", err)
@@ -69,10 +80,13 @@ func (repo *CommentRepository) Insert(ctx context.Context, comment *discuss.Comm
 			comment.AuthorID,
 			comment.ReplyTo,
 			comment.Content,
-			comment.CreatedAt,
+			comment.CreatedAt.UTC(),
+			comment.ReactionScore,
+			utcTime(comment.EditedAt),
+			utcTime(comment.DeletedAt),
 		)
 
-	q = q.RunWith(repo.db)
+	q = q.RunWith(runner(ctx, repo.db))
 
 	_, err := q.ExecContext(ctx)
 	if err != nil {
@@ -82,19 +96,139 @@ func (repo *CommentRepository) Insert(ctx context.Context, comment *discuss.Comm
 	return nil
 }
 
+// Update saves the content of the comment and when it was edited or deleted. The rest of a comment does not change.
+func (repo *CommentRepository) Update(ctx context.Context, comment *discuss.Comment) error {
+	q := sq.Update(tableComments).
+		Set(commentFieldContent, comment.Content).
+		Set(commentFieldEditedAt, utcTime(comment.EditedAt)).
+		Set(commentFieldDeletedAt, utcTime(comment.DeletedAt)).
+		Where(sq.Eq{commentFieldID: comment.ID})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	res, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec update: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if affected == 0 {
+		return discuss.CommentNotFoundError{ID: comment.ID}
+	}
+
+	return nil
+}
+
+// utcTime returns the time in UTC, or nil for no time.
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	utc := t.UTC()
+
+	return &utc
+}
+
+func (repo *CommentRepository) Find(ctx context.Context, commentID string) (*discuss.Comment, error) {
+	q := sq.Select(commentColumns()...).
+		From(tableComments).
+		Where(sq.Eq{commentFieldID: commentID})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	row := q.QueryRowContext(ctx)
+
+	comment, err := scanComment(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, discuss.CommentNotFoundError{ID: commentID}
+		}
+
+		return nil, fmt.Errorf("failed to scan comment: %w", err)
+	}
+
+	return comment, nil
+}
+
+// commentsAfter matches the comments newer than the cursor, with the ID breaking ties. Creation times are stored and
+// compared in UTC, for their text to sort like the times.
+func commentsAfter(cursor *discuss.CommentCursor) sq.Sqlizer {
+	return sq.Or{
+		sq.Gt{commentFieldCreatedAt: cursor.CreatedAt.UTC()},
+		sq.And{
+			sq.Eq{commentFieldCreatedAt: cursor.CreatedAt.UTC()},
+			sq.Gt{commentFieldID: cursor.ID},
+		},
+	}
+}
+
+// commentsBefore matches the comments older than the cursor, with the ID breaking ties.
+func commentsBefore(cursor *discuss.CommentCursor) sq.Sqlizer {
+	return sq.Or{
+		sq.Lt{commentFieldCreatedAt: cursor.CreatedAt.UTC()},
+		sq.And{
+			sq.Eq{commentFieldCreatedAt: cursor.CreatedAt.UTC()},
+			sq.Lt{commentFieldID: cursor.ID},
+		},
+	}
+}
+
 func (repo *CommentRepository) List(
 	ctx context.Context,
 	params *discuss.ListCommentsParams,
 ) ([]*discuss.Comment, error) {
 	query := sq.Select(commentColumns()...).
-		From(tableComments).
-		OrderBy(commentFieldCreatedAt + " ASC")
+		From(tableComments)
 
 	if params.PostID != "" {
 		query = query.Where(sq.Eq{commentFieldPostID: params.PostID})
 	}
 
-	query = query.RunWith(repo.db)
+	if params.TopLevel {
+		query = query.Where(sq.Eq{commentFieldReplyTo: nil})
+	}
+
+	if len(params.ReplyTo) > 0 {
+		query = query.Where(sq.Eq{commentFieldReplyTo: params.ReplyTo})
+	}
+
+	switch params.Sort {
+	case discuss.CommentSortNewest:
+		query = query.OrderBy(commentFieldCreatedAt+" DESC", commentFieldID+" DESC")
+
+		if params.After != nil {
+			query = query.Where(commentsBefore(params.After))
+		}
+	case discuss.CommentSortTop:
+		query = query.OrderBy(commentFieldScore+" DESC", commentFieldCreatedAt+" ASC", commentFieldID+" ASC")
+
+		if params.After != nil {
+			query = query.Where(sq.Or{
+				sq.Lt{commentFieldScore: params.After.ReactionScore},
+				sq.And{
+					sq.Eq{commentFieldScore: params.After.ReactionScore},
+					commentsAfter(params.After),
+				},
+			})
+		}
+	default:
+		query = query.OrderBy(commentFieldCreatedAt+" ASC", commentFieldID+" ASC")
+
+		if params.After != nil {
+			query = query.Where(commentsAfter(params.After))
+		}
+	}
+
+	if params.Limit > 0 {
+		query = query.Limit(uint64(params.Limit))
+	}
+
+	query = query.RunWith(runner(ctx, repo.db))
 
 	rows, err := query.QueryContext(ctx)
 	if err != nil {
@@ -138,7 +272,7 @@ func (repo *CommentRepository) Count(
 		query = query.Where(sq.Eq{commentFieldPostID: params.PostID})
 	}
 
-	query = query.RunWith(repo.db)
+	query = query.RunWith(runner(ctx, repo.db))
 
 	var count int
 
@@ -109,6 +109,29 @@ func TestCommentRepository(t *testing.T) {
 		assert.Equal(t, comment1.ID, post1Comments[0].ID)
 		assert.Equal(t, comment2.ID, post1Comments[1].ID)
 
+		post1Page, err := commentRepo.List(ctx, &discuss.ListCommentsParams{PostID: post1.ID, Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, post1Page, 1)
+		assert.Equal(t, comment1.ID, post1Page[0].ID)
+
+		post1Page, err = commentRepo.List(ctx, &discuss.ListCommentsParams{
+			PostID: post1.ID,
+			After:  &discuss.CommentCursor{CreatedAt: comment1.CreatedAt, ID: comment1.ID},
+			Limit:  1,
+		})
+		require.NoError(t, err)
+		require.Len(t, post1Page, 1)
+		assert.Equal(t, comment2.ID, post1Page[0].ID)
+
+		found, err := commentRepo.Find(ctx, comment2.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "reply comment", found.Content)
+		require.NotNil(t, found.ReplyTo)
+		assert.Equal(t, comment1.ID, *found.ReplyTo)
+
+		_, err = commentRepo.Find(ctx, uuid.NewString())
+		require.ErrorAs(t, err, &discuss.CommentNotFoundError{})
+
 		countAll, err := commentRepo.Count(ctx, &discuss.CountCommentsParams{})
 		require.NoError(t, err)
 		assert.Equal(t, 3, countAll)
@@ -117,4 +140,169 @@ func TestCommentRepository(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, 2, countPost1)
 	})
+
+	t.Run("List sorted and filtered", func(t *testing.T) {
+		post := &contents.Post{
+			ID:        uuid.NewString(),
+			AuthorID:  user.ID,
+			Content:   "post for sorted comments",
+			CreatedAt: time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC),
+		}
+
+		err := postRepo.Insert(ctx, post)
+		require.NoError(t, err)
+
+		insert := func(content string, hour int, score int, replyTo *string) *discuss.Comment {
+			t.Helper()
+
+			comment := &discuss.Comment{
+				ID:            uuid.NewString(),
+				PostID:        post.ID,
+				AuthorID:      user.ID,
+				ReplyTo:       replyTo,
+				Content:       content,
+				CreatedAt:     time.Date(2026, 2, 25, hour, 0, 0, 0, time.UTC),
+				ReactionScore: score,
+			}
+
+			err := commentRepo.Insert(ctx, comment)
+			require.NoError(t, err)
+
+			return comment
+		}
+
+		listIDs := func(params *discuss.ListCommentsParams) []string {
+			t.Helper()
+
+			params.PostID = post.ID
+
+			comments, err := commentRepo.List(ctx, params)
+			require.NoError(t, err)
+
+			ids := make([]string, 0, len(comments))
+			for _, comment := range comments {
+				ids = append(ids, comment.ID)
+			}
+
+			return ids
+		}
+
+		first := insert("first", 11, 1, nil)
+		second := insert("second", 12, 5, nil)
+		third := insert("third", 13, 1, nil)
+		reply := insert("reply", 14, 9, &first.ID)
+		nested := insert("nested", 15, 0, &reply.ID)
+
+		assert.Equal(t, []string{first.ID, second.ID, third.ID, reply.ID, nested.ID}, listIDs(&discuss.ListCommentsParams{}))
+		assert.Equal(t, []string{first.ID, second.ID, third.ID}, listIDs(&discuss.ListCommentsParams{TopLevel: true}))
+		assert.Equal(t, []string{reply.ID, nested.ID}, listIDs(&discuss.ListCommentsParams{
+			ReplyTo: []string{first.ID, reply.ID},
+		}))
+
+		assert.Equal(t, []string{third.ID, second.ID, first.ID}, listIDs(&discuss.ListCommentsParams{
+			TopLevel: true,
+			Sort:     discuss.CommentSortNewest,
+		}))
+		assert.Equal(t, []string{first.ID}, listIDs(&discuss.ListCommentsParams{
+			TopLevel: true,
+			Sort:     discuss.CommentSortNewest,
+			After:    second.Cursor(discuss.CommentSortNewest),
+		}))
+
+		assert.Equal(t, []string{second.ID, first.ID, third.ID}, listIDs(&discuss.ListCommentsParams{
+			TopLevel: true,
+			Sort:     discuss.CommentSortTop,
+		}))
+		assert.Equal(t, []string{third.ID}, listIDs(&discuss.ListCommentsParams{
+			TopLevel: true,
+			Sort:     discuss.CommentSortTop,
+			After:    first.Cursor(discuss.CommentSortTop),
+		}))
+		assert.Equal(t, []string{first.ID, third.ID}, listIDs(&discuss.ListCommentsParams{
+			TopLevel: true,
+			Sort:     discuss.CommentSortTop,
+			After:    second.Cursor(discuss.CommentSortTop),
+			Limit:    2,
+		}))
+
+		found, err := commentRepo.Find(ctx, reply.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 9, found.ReactionScore)
+	})
+
+	t.Run("Update and edit history", func(t *testing.T) {
+		commentEditRepo := sqlite3.NewCommentEditRepository(db)
+
+		comment := &discuss.Comment{
+			ID:        uuid.NewString(),
+			PostID:    post2.ID,
+			AuthorID:  user.ID,
+			Content:   "first version",
+			CreatedAt: time.Date(2026, 2, 24, 16, 0, 0, 0, time.UTC),
+		}
+
+		err := commentRepo.Insert(ctx, comment)
+		require.NoError(t, err)
+
+		found, err := commentRepo.Find(ctx, comment.ID)
+		require.NoError(t, err)
+		assert.Nil(t, found.EditedAt)
+		assert.Nil(t, found.DeletedAt)
+
+		edits, err := commentEditRepo.List(ctx, comment.ID)
+		require.NoError(t, err)
+		assert.Empty(t, edits)
+
+		for i, content := range []string{"first version", "second version"} {
+			err = commentEditRepo.Insert(ctx, &discuss.CommentEdit{
+				ID:        uuid.NewString(),
+				CommentID: comment.ID,
+				Content:   content,
+				EditedAt:  time.Date(2026, 2, 24, 17+i, 0, 0, 0, time.UTC),
+			})
+			require.NoError(t, err)
+		}
+
+		editedAt := time.Date(2026, 2, 24, 18, 0, 0, 0, time.UTC)
+		comment.Content = "third version"
+		comment.EditedAt = &editedAt
+
+		err = commentRepo.Update(ctx, comment)
+		require.NoError(t, err)
+
+		found, err = commentRepo.Find(ctx, comment.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "third version", found.Content)
+		require.NotNil(t, found.EditedAt)
+		assert.True(t, editedAt.Equal(*found.EditedAt))
+		assert.False(t, found.IsDeleted())
+
+		edits, err = commentEditRepo.List(ctx, comment.ID)
+		require.NoError(t, err)
+		require.Len(t, edits, 2)
+		assert.Equal(t, "second version", edits[0].Content)
+		assert.Equal(t, "first version", edits[1].Content)
+
+		err = commentEditRepo.DeleteAll(ctx, comment.ID)
+		require.NoError(t, err)
+
+		deletedAt := time.Date(2026, 2, 24, 19, 0, 0, 0, time.UTC)
+		comment.Content = ""
+		comment.DeletedAt = &deletedAt
+
+		err = commentRepo.Update(ctx, comment)
+		require.NoError(t, err)
+
+		found, err = commentRepo.Find(ctx, comment.ID)
+		require.NoError(t, err)
+		assert.Empty(t, found.Content)
+		assert.True(t, found.IsDeleted())
+
+		edits, err = commentEditRepo.List(ctx, comment.ID)
+		require.NoError(t, err)
+		assert.Empty(t, edits)
+
+		err = commentRepo.Update(ctx, &discuss.Comment{ID: uuid.NewString()})
+		require.ErrorAs(t, err, &discuss.CommentNotFoundError{})
+	})
 }
@@ -0,0 +1,176 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/nasermirzaei89/scribble/communities"
+)
+
+const tableCommunityMembers = "community_members"
+
+type CommunityMemberRepository struct {
+	db *sql.DB
+}
+
+var _ communities.MemberRepository = (*CommunityMemberRepository)(nil)
+
+func NewCommunityMemberRepository(db *sql.DB) *CommunityMemberRepository {
+	return &CommunityMemberRepository{db: db}
+}
+
+const (
+	communityMemberFieldCommunityID = "community_id"
+	communityMemberFieldUserID      = "user_id"
+	communityMemberFieldRole        = "role"
+	communityMemberFieldJoinedAt    = "joined_at"
+)
+
+func communityMemberColumns() []string {
+	return []string{
+		communityMemberFieldCommunityID,
+		communityMemberFieldUserID,
+		communityMemberFieldRole,
+		communityMemberFieldJoinedAt,
+	}
+}
+
+func scanCommunityMember(row sq.RowScanner) (*communities.Member, error) {
+	var member communities.Member
+
+	err := row.Scan(
+		&member.CommunityID,
+		&member.UserID,
+		&member.Role,
+		&member.JoinedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	return &member, nil
+}
+
+func (repo *CommunityMemberRepository) Insert(ctx context.Context, member *communities.Member) error {
+	q := sq.Insert(tableCommunityMembers).
+		Columns(communityMemberColumns()...).
+		Values(member.CommunityID, member.UserID, member.Role, member.JoinedAt)
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec insert: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *CommunityMemberRepository) Find(ctx context.Context, communityID, userID string) (*communities.Member, error) {
+	q := sq.Select(communityMemberColumns()...).
+		From(tableCommunityMembers).
+		Where(sq.Eq{
+			communityMemberFieldCommunityID: communityID,
+			communityMemberFieldUserID:      userID,
+		})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	row := q.QueryRowContext(ctx)
+
+	member, err := scanCommunityMember(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, communities.MemberNotFoundError{CommunityID: communityID, UserID: userID}
+		}
+
+		return nil, fmt.Errorf("failed to scan member: %w", err)
+	}
+
+	return member, nil
+}
+
+func (repo *CommunityMemberRepository) Update(ctx context.Context, member *communities.Member) error {
+	q := sq.Update(tableCommunityMembers).
+		Set(communityMemberFieldRole, member.Role).
+		Where(sq.Eq{
+			communityMemberFieldCommunityID: member.CommunityID,
+			communityMemberFieldUserID:      member.UserID,
+		})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec update: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *CommunityMemberRepository) Delete(ctx context.Context, communityID, userID string) error {
+	q := sq.Delete(tableCommunityMembers).
+		Where(sq.Eq{
+			communityMemberFieldCommunityID: communityID,
+			communityMemberFieldUserID:      userID,
+		}).
+		RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec delete: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *CommunityMemberRepository) List(ctx context.Context, communityID string) ([]*communities.Member, error) {
+	return repo.list(ctx, sq.Eq{communityMemberFieldCommunityID: communityID})
+}
+
+func (repo *CommunityMemberRepository) ListByUser(ctx context.Context, userID string) ([]*communities.Member, error) {
+	return repo.list(ctx, sq.Eq{communityMemberFieldUserID: userID})
+}
+
+func (repo *CommunityMemberRepository) list(ctx context.Context, where sq.Eq) ([]*communities.Member, error) {
+	q := sq.Select(communityMemberColumns()...).
+		From(tableCommunityMembers).
+		Where(where).
+		OrderBy(communityMemberFieldJoinedAt + " ASC")
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	rows, err := q.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	members := make([]*communities.Member, 0)
+
+	for rows.Next() {
+		member, err := scanCommunityMember(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan member: %w", err)
+		}
+
+		members = append(members, member)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return members, nil
+}
@@ -0,0 +1,164 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/nasermirzaei89/scribble/communities"
+)
+
+const tableCommunities = "communities"
+
+type CommunityRepository struct {
+	db *sql.DB
+}
+
+var _ communities.CommunityRepository = (*CommunityRepository)(nil)
+
+func NewCommunityRepository(db *sql.DB) *CommunityRepository {
+	return &CommunityRepository{db: db}
+}
+
+const (
+	communityFieldID          = "id"
+	communityFieldSlug        = "slug"
+	communityFieldName        = "name"
+	communityFieldDescription = "description"
+	communityFieldOwnerID     = "owner_id"
+	communityFieldCreatedAt   = "created_at"
+)
+
+func communityColumns() []string {
+	return []string{
+		communityFieldID,
+		communityFieldSlug,
+		communityFieldName,
+		communityFieldDescription,
+		communityFieldOwnerID,
+		communityFieldCreatedAt,
+	}
+}
+
+func scanCommunity(row sq.RowScanner) (*communities.Community, error) {
+	var community communities.Community
+
+	err := row.Scan(
+		&community.ID,
+		&community.Slug,
+		&community.Name,
+		&community.Description,
+		&community.OwnerID,
+		&community.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	return &community, nil
+}
+
+func (repo *CommunityRepository) Insert(ctx context.Context, community *communities.Community) error {
+	q := sq.Insert(tableCommunities).
+		Columns(communityColumns()...).
+		Values(
+			community.ID,
+			community.Slug,
+			community.Name,
+			community.Description,
+			community.OwnerID,
+			community.CreatedAt,
+		)
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec insert: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *CommunityRepository) Find(ctx context.Context, communityID string) (*communities.Community, error) {
+	q := sq.Select(communityColumns()...).
+		From(tableCommunities).
+		Where(sq.Eq{communityFieldID: communityID})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	row := q.QueryRowContext(ctx)
+
+	community, err := scanCommunity(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, communities.CommunityNotFoundError{ID: communityID}
+		}
+
+		return nil, fmt.Errorf("failed to scan community: %w", err)
+	}
+
+	return community, nil
+}
+
+func (repo *CommunityRepository) FindBySlug(ctx context.Context, slug string) (*communities.Community, error) {
+	q := sq.Select(communityColumns()...).
+		From(tableCommunities).
+		Where(sq.Eq{communityFieldSlug: slug})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	row := q.QueryRowContext(ctx)
+
+	community, err := scanCommunity(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, communities.CommunityBySlugNotFoundError{Slug: slug}
+		}
+
+		return nil, fmt.Errorf("failed to scan community: %w", err)
+	}
+
+	return community, nil
+}
+
+func (repo *CommunityRepository) List(ctx context.Context) ([]*communities.Community, error) {
+	q := sq.Select(communityColumns()...).
+		From(tableCommunities).
+		OrderBy(communityFieldName + " ASC")
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	rows, err := q.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	result := make([]*communities.Community, 0)
+
+	for rows.Next() {
+		community, err := scanCommunity(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan community: %w", err)
+		}
+
+		result = append(result, community)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,158 @@
+package sqlite3_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nasermirzaei89/scribble/authentication"
+	"github.com/nasermirzaei89/scribble/communities"
+	"github.com/nasermirzaei89/scribble/database/sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommunityRepository(t *testing.T) {
+	ctx, db := newTestDB(t)
+
+	userRepo := sqlite3.NewUserRepository(db)
+	communityRepo := sqlite3.NewCommunityRepository(db)
+	memberRepo := sqlite3.NewCommunityMemberRepository(db)
+
+	owner := &authentication.User{
+		ID:           uuid.NewString(),
+		Username:     "community-owner-" + uuid.NewString(),
+		PasswordHash: "password-hash",
+		RegisteredAt: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+	}
+
+	member := &authentication.User{
+		ID:           uuid.NewString(),
+		Username:     "community-member-" + uuid.NewString(),
+		PasswordHash: "password-hash",
+		RegisteredAt: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+	}
+
+	err := userRepo.Insert(ctx, owner)
+	require.NoError(t, err)
+
+	err = userRepo.Insert(ctx, member)
+	require.NoError(t, err)
+
+	golang := &communities.Community{
+		ID:          uuid.NewString(),
+		Slug:        "golang",
+		Name:        "Go",
+		Description: "All things Go",
+		OwnerID:     owner.ID,
+		CreatedAt:   time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC),
+	}
+
+	books := &communities.Community{
+		ID:        uuid.NewString(),
+		Slug:      "books",
+		Name:      "Books",
+		OwnerID:   owner.ID,
+		CreatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("Insert and find", func(t *testing.T) {
+		err := communityRepo.Insert(ctx, golang)
+		require.NoError(t, err)
+
+		err = communityRepo.Insert(ctx, books)
+		require.NoError(t, err)
+
+		found, err := communityRepo.Find(ctx, golang.ID)
+		require.NoError(t, err)
+		assert.Equal(t, golang.Slug, found.Slug)
+		assert.Equal(t, golang.Description, found.Description)
+		assert.True(t, golang.CreatedAt.Equal(found.CreatedAt))
+
+		found, err = communityRepo.FindBySlug(ctx, books.Slug)
+		require.NoError(t, err)
+		assert.Equal(t, books.ID, found.ID)
+	})
+
+	t.Run("Duplicate slug", func(t *testing.T) {
+		err := communityRepo.Insert(ctx, &communities.Community{
+			ID:        uuid.NewString(),
+			Slug:      golang.Slug,
+			Name:      "Another Go",
+			OwnerID:   owner.ID,
+			CreatedAt: time.Date(2026, 3, 1, 13, 0, 0, 0, time.UTC),
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("Find not found", func(t *testing.T) {
+		_, err := communityRepo.Find(ctx, uuid.NewString())
+		require.ErrorAs(t, err, &communities.CommunityNotFoundError{})
+
+		_, err = communityRepo.FindBySlug(ctx, "missing")
+		require.ErrorAs(t, err, &communities.CommunityBySlugNotFoundError{})
+	})
+
+	t.Run("List orders by name", func(t *testing.T) {
+		list, err := communityRepo.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		assert.Equal(t, books.ID, list[0].ID)
+		assert.Equal(t, golang.ID, list[1].ID)
+	})
+
+	t.Run("Members", func(t *testing.T) {
+		ownerMember := &communities.Member{
+			CommunityID: golang.ID,
+			UserID:      owner.ID,
+			Role:        communities.RoleOwner,
+			JoinedAt:    time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC),
+		}
+
+		regularMember := &communities.Member{
+			CommunityID: golang.ID,
+			UserID:      member.ID,
+			Role:        communities.RoleMember,
+			JoinedAt:    time.Date(2026, 3, 1, 14, 0, 0, 0, time.UTC),
+		}
+
+		err := memberRepo.Insert(ctx, ownerMember)
+		require.NoError(t, err)
+
+		err = memberRepo.Insert(ctx, regularMember)
+		require.NoError(t, err)
+
+		err = memberRepo.Insert(ctx, regularMember)
+		require.Error(t, err)
+
+		members, err := memberRepo.List(ctx, golang.ID)
+		require.NoError(t, err)
+		require.Len(t, members, 2)
+		assert.Equal(t, owner.ID, members[0].UserID)
+		assert.Equal(t, member.ID, members[1].UserID)
+
+		regularMember.Role = communities.RoleModerator
+
+		err = memberRepo.Update(ctx, regularMember)
+		require.NoError(t, err)
+
+		found, err := memberRepo.Find(ctx, golang.ID, member.ID)
+		require.NoError(t, err)
+		assert.Equal(t, communities.RoleModerator, found.Role)
+
+		memberships, err := memberRepo.ListByUser(ctx, member.ID)
+		require.NoError(t, err)
+		require.Len(t, memberships, 1)
+		assert.Equal(t, golang.ID, memberships[0].CommunityID)
+
+		err = memberRepo.Delete(ctx, golang.ID, member.ID)
+		require.NoError(t, err)
+
+		_, err = memberRepo.Find(ctx, golang.ID, member.ID)
+		require.ErrorAs(t, err, &communities.MemberNotFoundError{})
+
+		memberships, err = memberRepo.ListByUser(ctx, member.ID)
+		require.NoError(t, err)
+		assert.Empty(t, memberships)
+	})
+}
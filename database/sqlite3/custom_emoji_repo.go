@@ -0,0 +1,147 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/nasermirzaei89/scribble/emojis"
+)
+
+const tableCustomEmojis = "custom_emojis"
+
+type CustomEmojiRepository struct {
+	db *sql.DB
+}
+
+var _ emojis.CustomEmojiRepository = (*CustomEmojiRepository)(nil)
+
+func NewCustomEmojiRepository(db *sql.DB) *CustomEmojiRepository {
+	return &CustomEmojiRepository{db: db}
+}
+
+const (
+	customEmojiFieldShortcode   = "shortcode"
+	customEmojiFieldContentType = "content_type"
+	customEmojiFieldCreatedBy   = "created_by"
+	customEmojiFieldCreatedAt   = "created_at"
+)
+
+func customEmojiColumns() []string {
+	return []string{
+		customEmojiFieldShortcode,
+		customEmojiFieldContentType,
+		customEmojiFieldCreatedBy,
+		customEmojiFieldCreatedAt,
+	}
+}
+
+func scanCustomEmoji(row sq.RowScanner) (*emojis.CustomEmoji, error) {
+	var emoji emojis.CustomEmoji
+
+	err := row.Scan(
+		&emoji.Shortcode,
+		&emoji.ContentType,
+		&emoji.CreatedBy,
+		&emoji.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	return &emoji, nil
+}
+
+func (repo *CustomEmojiRepository) Insert(ctx context.Context, emoji *emojis.CustomEmoji) error {
+	q := sq.Insert(tableCustomEmojis).
+		Columns(customEmojiColumns()...).
+		Values(
+			emoji.Shortcode,
+			emoji.ContentType,
+			emoji.CreatedBy,
+			emoji.CreatedAt,
+		)
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec insert: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *CustomEmojiRepository) Find(ctx context.Context, shortcode string) (*emojis.CustomEmoji, error) {
+	q := sq.Select(customEmojiColumns()...).
+		From(tableCustomEmojis).
+		Where(sq.Eq{customEmojiFieldShortcode: shortcode})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	emoji, err := scanCustomEmoji(q.QueryRowContext(ctx))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, emojis.CustomEmojiNotFoundError{Shortcode: shortcode}
+		}
+
+		return nil, fmt.Errorf("failed to scan custom emoji: %w", err)
+	}
+
+	return emoji, nil
+}
+
+func (repo *CustomEmojiRepository) List(ctx context.Context) ([]*emojis.CustomEmoji, error) {
+	q := sq.Select(customEmojiColumns()...).
+		From(tableCustomEmojis).
+		OrderBy(customEmojiFieldShortcode + " ASC")
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	rows, err := q.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	result := make([]*emojis.CustomEmoji, 0)
+
+	for rows.Next() {
+		emoji, err := scanCustomEmoji(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan custom emoji: %w", err)
+		}
+
+		result = append(result, emoji)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return result, nil
+}
+
+func (repo *CustomEmojiRepository) Delete(ctx context.Context, shortcode string) error {
+	q := sq.Delete(tableCustomEmojis).
+		Where(sq.Eq{customEmojiFieldShortcode: shortcode})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec delete: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package sqlite3_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/database/sqlite3"
+	"github.com/nasermirzaei89/scribble/emojis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomEmojiRepository(t *testing.T) {
+	ctx, db := newTestDB(t)
+
+	repo := sqlite3.NewCustomEmojiRepository(db)
+
+	_, err := repo.Find(ctx, "wave")
+	require.ErrorAs(t, err, &emojis.CustomEmojiNotFoundError{})
+
+	for _, shortcode := range []string{"wave", "party_parrot"} {
+		err = repo.Insert(ctx, &emojis.CustomEmoji{
+			Shortcode:   shortcode,
+			ContentType: "image/png",
+			CreatedBy:   "admin",
+			CreatedAt:   time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+		})
+		require.NoError(t, err)
+	}
+
+	found, err := repo.Find(ctx, "wave")
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", found.ContentType)
+	assert.Equal(t, "admin", found.CreatedBy)
+
+	list, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "party_parrot", list[0].Shortcode)
+	assert.Equal(t, "wave", list[1].Shortcode)
+
+	err = repo.Delete(ctx, "wave")
+	require.NoError(t, err)
+
+	_, err = repo.Find(ctx, "wave")
+	require.ErrorAs(t, err, &emojis.CustomEmojiNotFoundError{})
+}
@@ -0,0 +1,174 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/nasermirzaei89/scribble/events"
+)
+
+const tableEventOutbox = "event_outbox"
+
+type EventOutboxRepository struct {
+	db *sql.DB
+}
+
+var _ events.OutboxRepository = (*EventOutboxRepository)(nil)
+
+func NewEventOutboxRepository(db *sql.DB) *EventOutboxRepository {
+	return &EventOutboxRepository{db: db}
+}
+
+const (
+	eventOutboxFieldID            = "id"
+	eventOutboxFieldName          = "name"
+	eventOutboxFieldPayload       = "payload"
+	eventOutboxFieldOccurredAt    = "occurred_at"
+	eventOutboxFieldStatus        = "status"
+	eventOutboxFieldAttempts      = "attempts"
+	eventOutboxFieldNextAttemptAt = "next_attempt_at"
+	eventOutboxFieldLastError     = "last_error"
+)
+
+func eventOutboxColumns() []string {
+	return []string{
+		eventOutboxFieldID,
+		eventOutboxFieldName,
+		eventOutboxFieldPayload,
+		eventOutboxFieldOccurredAt,
+		eventOutboxFieldStatus,
+		eventOutboxFieldAttempts,
+		eventOutboxFieldNextAttemptAt,
+		eventOutboxFieldLastError,
+	}
+}
+
+func scanEventOutboxRecord(row sq.RowScanner) (*events.Record, error) {
+	var (
+		record  events.Record
+		payload string
+	)
+
+	err := row.Scan(
+		&record.ID,
+		&record.Name,
+		&payload,
+		&record.OccurredAt,
+		&record.Status,
+		&record.Attempts,
+		&record.NextAttemptAt,
+		&record.LastError,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	record.Payload = []byte(payload)
+
+	return &record, nil
+}
+
+func (repo *EventOutboxRepository) Insert(ctx context.Context, record *events.Record) error {
+	q := sq.Insert(tableEventOutbox).
+		Columns(eventOutboxColumns()...).
+		Values(
+			record.ID,
+			record.Name,
+			string(record.Payload),
+			record.OccurredAt.UTC(),
+			record.Status,
+			record.Attempts,
+			record.NextAttemptAt.UTC(),
+			record.LastError,
+		)
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec insert: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *EventOutboxRepository) Update(ctx context.Context, record *events.Record) error {
+	q := sq.Update(tableEventOutbox).
+		Set(eventOutboxFieldStatus, record.Status).
+		Set(eventOutboxFieldAttempts, record.Attempts).
+		Set(eventOutboxFieldNextAttemptAt, record.NextAttemptAt.UTC()).
+		Set(eventOutboxFieldLastError, record.LastError).
+		Where(sq.Eq{eventOutboxFieldID: record.ID})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec update: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *EventOutboxRepository) Delete(ctx context.Context, recordID string) error {
+	q := sq.Delete(tableEventOutbox).
+		Where(sq.Eq{eventOutboxFieldID: recordID})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec delete: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *EventOutboxRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]*events.Record, error) {
+	// Times are stored in UTC, so they compare in order.
+	q := sq.Select(eventOutboxColumns()...).
+		From(tableEventOutbox).
+		Where(sq.Eq{eventOutboxFieldStatus: events.RecordStatusPending}).
+		Where(sq.LtOrEq{eventOutboxFieldNextAttemptAt: now.UTC()}).
+		OrderBy(eventOutboxFieldNextAttemptAt+" ASC", eventOutboxFieldOccurredAt+" ASC", eventOutboxFieldID+" ASC")
+
+	if limit > 0 {
+		q = q.Limit(uint64(limit))
+	}
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	rows, err := q.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	result := make([]*events.Record, 0)
+
+	for rows.Next() {
+		record, err := scanEventOutboxRecord(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan outbox record: %w", err)
+		}
+
+		result = append(result, record)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,146 @@
+package sqlite3_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nasermirzaei89/scribble/authentication"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/database/sqlite3"
+	"github.com/nasermirzaei89/scribble/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventOutboxRepository(t *testing.T) {
+	ctx, db := newTestDB(t)
+
+	outboxRepo := sqlite3.NewEventOutboxRepository(db)
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	newRecord := func(name string, occurredAt time.Time) *events.Record {
+		return &events.Record{
+			ID:            uuid.NewString(),
+			Name:          name,
+			Payload:       []byte(`{"postId":"post1"}`),
+			OccurredAt:    occurredAt,
+			Status:        events.RecordStatusPending,
+			Attempts:      0,
+			NextAttemptAt: occurredAt,
+			LastError:     "",
+		}
+	}
+
+	record1 := newRecord("post.created", now.Add(-2*time.Minute))
+	record2 := newRecord("comment.created", now.Add(-time.Minute))
+	record3 := newRecord("post.created", now.Add(time.Minute))
+
+	for _, record := range []*events.Record{record3, record2, record1} {
+		err := outboxRepo.Insert(ctx, record)
+		require.NoError(t, err)
+	}
+
+	due, err := outboxRepo.ListDue(ctx, now, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	assert.Equal(t, record1.ID, due[0].ID)
+	assert.Equal(t, record2.ID, due[1].ID)
+	assert.Equal(t, "post.created", due[0].Name)
+	assert.JSONEq(t, `{"postId":"post1"}`, string(due[0].Payload))
+	assert.True(t, due[0].OccurredAt.Equal(record1.OccurredAt))
+
+	record1.Attempts = 1
+	record1.LastError = "unavailable"
+	record1.NextAttemptAt = now.Add(time.Hour)
+
+	err = outboxRepo.Update(ctx, record1)
+	require.NoError(t, err)
+
+	record2.Status = events.RecordStatusFailed
+
+	err = outboxRepo.Update(ctx, record2)
+	require.NoError(t, err)
+
+	due, err = outboxRepo.ListDue(ctx, now.Add(time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	assert.Equal(t, record3.ID, due[0].ID)
+	assert.Equal(t, record1.ID, due[1].ID)
+	assert.Equal(t, 1, due[1].Attempts)
+	assert.Equal(t, "unavailable", due[1].LastError)
+
+	err = outboxRepo.Delete(ctx, record3.ID)
+	require.NoError(t, err)
+
+	due, err = outboxRepo.ListDue(ctx, now.Add(time.Hour), 1)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, record1.ID, due[0].ID)
+}
+
+func TestTransactor(t *testing.T) {
+	ctx, db := newTestDB(t)
+
+	transactor := sqlite3.NewTransactor(db)
+	userRepo := sqlite3.NewUserRepository(db)
+	postRepo := sqlite3.NewPostRepository(db)
+
+	user := &authentication.User{
+		ID:           uuid.NewString(),
+		Username:     "transaction-user",
+		PasswordHash: "password-hash",
+		RegisteredAt: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+	}
+
+	err := userRepo.Insert(ctx, user)
+	require.NoError(t, err)
+
+	newPost := func() *contents.Post {
+		return &contents.Post{
+			ID:          uuid.NewString(),
+			AuthorID:    user.ID,
+			CommunityID: "",
+			Content:     "content",
+			CreatedAt:   time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC),
+		}
+	}
+
+	t.Run("commit", func(t *testing.T) {
+		post := newPost()
+
+		err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			err := postRepo.Insert(ctx, post)
+			require.NoError(t, err)
+
+			// Nested calls join the transaction, which sees its own changes.
+			return transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+				_, err := postRepo.Find(ctx, post.ID)
+
+				return err
+			})
+		})
+		require.NoError(t, err)
+
+		_, err = postRepo.Find(ctx, post.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		post := newPost()
+		errFailed := errors.New("failed")
+
+		err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			err := postRepo.Insert(ctx, post)
+			require.NoError(t, err)
+
+			return errFailed
+		})
+		require.ErrorIs(t, err, errFailed)
+
+		_, err = postRepo.Find(ctx, post.ID)
+		require.ErrorAs(t, err, new(contents.PostNotFoundError))
+	})
+}
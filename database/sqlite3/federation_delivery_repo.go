@@ -0,0 +1,173 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/nasermirzaei89/scribble/federation"
+)
+
+const tableFederationDeliveries = "federation_deliveries"
+
+type FederationDeliveryRepository struct {
+	db *sql.DB
+}
+
+var _ federation.DeliveryRepository = (*FederationDeliveryRepository)(nil)
+
+func NewFederationDeliveryRepository(db *sql.DB) *FederationDeliveryRepository {
+	return &FederationDeliveryRepository{db: db}
+}
+
+const (
+	federationDeliveryFieldID            = "id"
+	federationDeliveryFieldUserID        = "user_id"
+	federationDeliveryFieldInbox         = "inbox"
+	federationDeliveryFieldActivity      = "activity"
+	federationDeliveryFieldStatus        = "status"
+	federationDeliveryFieldAttempts      = "attempts"
+	federationDeliveryFieldNextAttemptAt = "next_attempt_at"
+	federationDeliveryFieldLastError     = "last_error"
+	federationDeliveryFieldCreatedAt     = "created_at"
+	federationDeliveryFieldUpdatedAt     = "updated_at"
+)
+
+func federationDeliveryColumns() []string {
+	return []string{
+		federationDeliveryFieldID,
+		federationDeliveryFieldUserID,
+		federationDeliveryFieldInbox,
+		federationDeliveryFieldActivity,
+		federationDeliveryFieldStatus,
+		federationDeliveryFieldAttempts,
+		federationDeliveryFieldNextAttemptAt,
+		federationDeliveryFieldLastError,
+		federationDeliveryFieldCreatedAt,
+		federationDeliveryFieldUpdatedAt,
+	}
+}
+
+func scanFederationDelivery(row sq.RowScanner) (*federation.Delivery, error) {
+	var (
+		delivery federation.Delivery
+		activity string
+	)
+
+	err := row.Scan(
+		&delivery.ID,
+		&delivery.UserID,
+		&delivery.Inbox,
+		&activity,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.NextAttemptAt,
+		&delivery.LastError,
+		&delivery.CreatedAt,
+		&delivery.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	delivery.Activity = []byte(activity)
+
+	return &delivery, nil
+}
+
+func (repo *FederationDeliveryRepository) Insert(ctx context.Context, delivery *federation.Delivery) error {
+	q := sq.Insert(tableFederationDeliveries).
+		Columns(federationDeliveryColumns()...).
+		Values(
+			delivery.ID,
+			delivery.UserID,
+			delivery.Inbox,
+			string(delivery.Activity),
+			delivery.Status,
+			delivery.Attempts,
+			delivery.NextAttemptAt.UTC(),
+			delivery.LastError,
+			delivery.CreatedAt,
+			delivery.UpdatedAt,
+		)
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec insert: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *FederationDeliveryRepository) Update(ctx context.Context, delivery *federation.Delivery) error {
+	q := sq.Update(tableFederationDeliveries).
+		Set(federationDeliveryFieldStatus, delivery.Status).
+		Set(federationDeliveryFieldAttempts, delivery.Attempts).
+		Set(federationDeliveryFieldNextAttemptAt, delivery.NextAttemptAt.UTC()).
+		Set(federationDeliveryFieldLastError, delivery.LastError).
+		Set(federationDeliveryFieldUpdatedAt, delivery.UpdatedAt).
+		Where(sq.Eq{federationDeliveryFieldID: delivery.ID})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec update: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *FederationDeliveryRepository) ListDue(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]*federation.Delivery, error) {
+	// Times are stored in UTC, so they compare in order.
+	q := sq.Select(federationDeliveryColumns()...).
+		From(tableFederationDeliveries).
+		Where(sq.Eq{federationDeliveryFieldStatus: federation.DeliveryStatusPending}).
+		Where(sq.LtOrEq{federationDeliveryFieldNextAttemptAt: now.UTC()}).
+		OrderBy(federationDeliveryFieldNextAttemptAt+" ASC", federationDeliveryFieldID+" ASC")
+
+	if limit > 0 {
+		q = q.Limit(uint64(limit))
+	}
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	rows, err := q.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	result := make([]*federation.Delivery, 0)
+
+	for rows.Next() {
+		delivery, err := scanFederationDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan delivery: %w", err)
+		}
+
+		result = append(result, delivery)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,149 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/nasermirzaei89/scribble/federation"
+)
+
+const tableFederationFollowers = "federation_followers"
+
+type FederationFollowerRepository struct {
+	db *sql.DB
+}
+
+var _ federation.FollowerRepository = (*FederationFollowerRepository)(nil)
+
+func NewFederationFollowerRepository(db *sql.DB) *FederationFollowerRepository {
+	return &FederationFollowerRepository{db: db}
+}
+
+const (
+	federationFollowerFieldUserID     = "user_id"
+	federationFollowerFieldActorID    = "actor_id"
+	federationFollowerFieldInbox      = "inbox"
+	federationFollowerFieldFollowedAt = "followed_at"
+)
+
+func federationFollowerColumns() []string {
+	return []string{
+		federationFollowerFieldUserID,
+		federationFollowerFieldActorID,
+		federationFollowerFieldInbox,
+		federationFollowerFieldFollowedAt,
+	}
+}
+
+func scanFederationFollower(row sq.RowScanner) (*federation.Follower, error) {
+	var follower federation.Follower
+
+	err := row.Scan(
+		&follower.UserID,
+		&follower.ActorID,
+		&follower.Inbox,
+		&follower.FollowedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	return &follower, nil
+}
+
+func (repo *FederationFollowerRepository) Upsert(ctx context.Context, follower *federation.Follower) error {
+	q := sq.Insert(tableFederationFollowers).
+		Columns(federationFollowerColumns()...).
+		Values(
+			follower.UserID,
+			follower.ActorID,
+			follower.Inbox,
+			follower.FollowedAt,
+		).
+		Suffix(`ON CONFLICT (` + federationFollowerFieldUserID + `, ` + federationFollowerFieldActorID + `)
+			DO UPDATE SET inbox = excluded.inbox`)
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec upsert: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *FederationFollowerRepository) Delete(ctx context.Context, userID, actorID string) error {
+	q := sq.Delete(tableFederationFollowers).
+		Where(sq.Eq{
+			federationFollowerFieldUserID:  userID,
+			federationFollowerFieldActorID: actorID,
+		}).
+		RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec delete: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *FederationFollowerRepository) List(ctx context.Context, userID string) ([]*federation.Follower, error) {
+	q := sq.Select(federationFollowerColumns()...).
+		From(tableFederationFollowers).
+		Where(sq.Eq{federationFollowerFieldUserID: userID}).
+		OrderBy(federationFollowerFieldFollowedAt + " ASC")
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	rows, err := q.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	result := make([]*federation.Follower, 0)
+
+	for rows.Next() {
+		follower, err := scanFederationFollower(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan follower: %w", err)
+		}
+
+		result = append(result, follower)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return result, nil
+}
+
+func (repo *FederationFollowerRepository) Count(ctx context.Context, userID string) (int, error) {
+	q := sq.Select("COUNT(*)").
+		From(tableFederationFollowers).
+		Where(sq.Eq{federationFollowerFieldUserID: userID})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	var count int
+
+	err := q.QueryRowContext(ctx).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan count: %w", err)
+	}
+
+	return count, nil
+}
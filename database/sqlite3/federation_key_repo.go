@@ -0,0 +1,96 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/nasermirzaei89/scribble/federation"
+)
+
+const tableFederationKeys = "federation_keys"
+
+type FederationKeyRepository struct {
+	db *sql.DB
+}
+
+var _ federation.KeyRepository = (*FederationKeyRepository)(nil)
+
+func NewFederationKeyRepository(db *sql.DB) *FederationKeyRepository {
+	return &FederationKeyRepository{db: db}
+}
+
+const (
+	federationKeyFieldUserID        = "user_id"
+	federationKeyFieldPublicKeyPEM  = "public_key_pem"
+	federationKeyFieldPrivateKeyPEM = "private_key_pem"
+	federationKeyFieldCreatedAt     = "created_at"
+)
+
+func federationKeyColumns() []string {
+	return []string{
+		federationKeyFieldUserID,
+		federationKeyFieldPublicKeyPEM,
+		federationKeyFieldPrivateKeyPEM,
+		federationKeyFieldCreatedAt,
+	}
+}
+
+func scanFederationKey(row sq.RowScanner) (*federation.Key, error) {
+	var key federation.Key
+
+	err := row.Scan(
+		&key.UserID,
+		&key.PublicKeyPEM,
+		&key.PrivateKeyPEM,
+		&key.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	return &key, nil
+}
+
+func (repo *FederationKeyRepository) Insert(ctx context.Context, key *federation.Key) error {
+	q := sq.Insert(tableFederationKeys).
+		Columns(federationKeyColumns()...).
+		Values(
+			key.UserID,
+			key.PublicKeyPEM,
+			key.PrivateKeyPEM,
+			key.CreatedAt,
+		)
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec insert: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *FederationKeyRepository) Find(ctx context.Context, userID string) (*federation.Key, error) {
+	q := sq.Select(federationKeyColumns()...).
+		From(tableFederationKeys).
+		Where(sq.Eq{federationKeyFieldUserID: userID})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	row := q.QueryRowContext(ctx)
+
+	key, err := scanFederationKey(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, federation.KeyNotFoundError{UserID: userID}
+		}
+
+		return nil, fmt.Errorf("failed to scan key: %w", err)
+	}
+
+	return key, nil
+}
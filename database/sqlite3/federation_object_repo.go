@@ -0,0 +1,96 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/nasermirzaei89/scribble/federation"
+)
+
+const tableFederationObjects = "federation_objects"
+
+type FederationObjectRepository struct {
+	db *sql.DB
+}
+
+var _ federation.ObjectRepository = (*FederationObjectRepository)(nil)
+
+func NewFederationObjectRepository(db *sql.DB) *FederationObjectRepository {
+	return &FederationObjectRepository{db: db}
+}
+
+const (
+	federationObjectFieldID         = "id"
+	federationObjectFieldPostID     = "post_id"
+	federationObjectFieldCommentID  = "comment_id"
+	federationObjectFieldReceivedAt = "received_at"
+)
+
+func federationObjectColumns() []string {
+	return []string{
+		federationObjectFieldID,
+		federationObjectFieldPostID,
+		federationObjectFieldCommentID,
+		federationObjectFieldReceivedAt,
+	}
+}
+
+func scanFederationObject(row sq.RowScanner) (*federation.Object, error) {
+	var object federation.Object
+
+	err := row.Scan(
+		&object.ID,
+		&object.PostID,
+		&object.CommentID,
+		&object.ReceivedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	return &object, nil
+}
+
+func (repo *FederationObjectRepository) Insert(ctx context.Context, object *federation.Object) error {
+	q := sq.Insert(tableFederationObjects).
+		Columns(federationObjectColumns()...).
+		Values(
+			object.ID,
+			object.PostID,
+			object.CommentID,
+			object.ReceivedAt,
+		)
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec insert: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *FederationObjectRepository) Find(ctx context.Context, objectID string) (*federation.Object, error) {
+	q := sq.Select(federationObjectColumns()...).
+		From(tableFederationObjects).
+		Where(sq.Eq{federationObjectFieldID: objectID})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	row := q.QueryRowContext(ctx)
+
+	object, err := scanFederationObject(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, federation.ObjectNotFoundError{ID: objectID}
+		}
+
+		return nil, fmt.Errorf("failed to scan object: %w", err)
+	}
+
+	return object, nil
+}
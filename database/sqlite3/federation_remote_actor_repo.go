@@ -0,0 +1,158 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/nasermirzaei89/scribble/federation"
+)
+
+const tableFederationRemoteActors = "federation_remote_actors"
+
+type FederationRemoteActorRepository struct {
+	db *sql.DB
+}
+
+var _ federation.RemoteActorRepository = (*FederationRemoteActorRepository)(nil)
+
+func NewFederationRemoteActorRepository(db *sql.DB) *FederationRemoteActorRepository {
+	return &FederationRemoteActorRepository{db: db}
+}
+
+const (
+	federationRemoteActorFieldID           = "id"
+	federationRemoteActorFieldUsername     = "username"
+	federationRemoteActorFieldUserID       = "user_id"
+	federationRemoteActorFieldInbox        = "inbox"
+	federationRemoteActorFieldSharedInbox  = "shared_inbox"
+	federationRemoteActorFieldPublicKeyID  = "public_key_id"
+	federationRemoteActorFieldPublicKeyPEM = "public_key_pem"
+	federationRemoteActorFieldFetchedAt    = "fetched_at"
+)
+
+func federationRemoteActorColumns() []string {
+	return []string{
+		federationRemoteActorFieldID,
+		federationRemoteActorFieldUsername,
+		federationRemoteActorFieldUserID,
+		federationRemoteActorFieldInbox,
+		federationRemoteActorFieldSharedInbox,
+		federationRemoteActorFieldPublicKeyID,
+		federationRemoteActorFieldPublicKeyPEM,
+		federationRemoteActorFieldFetchedAt,
+	}
+}
+
+func scanFederationRemoteActor(row sq.RowScanner) (*federation.RemoteActor, error) {
+	var (
+		actor  federation.RemoteActor
+		userID sql.NullString
+	)
+
+	err := row.Scan(
+		&actor.ID,
+		&actor.Username,
+		&userID,
+		&actor.Inbox,
+		&actor.SharedInbox,
+		&actor.PublicKeyID,
+		&actor.PublicKeyPEM,
+		&actor.FetchedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	actor.UserID = userID.String
+
+	return &actor, nil
+}
+
+func (repo *FederationRemoteActorRepository) Upsert(ctx context.Context, actor *federation.RemoteActor) error {
+	var userID *string
+	if actor.UserID != "" {
+		userID = &actor.UserID
+	}
+
+	q := sq.Insert(tableFederationRemoteActors).
+		Columns(federationRemoteActorColumns()...).
+		Values(
+			actor.ID,
+			actor.Username,
+			userID,
+			actor.Inbox,
+			actor.SharedInbox,
+			actor.PublicKeyID,
+			actor.PublicKeyPEM,
+			actor.FetchedAt,
+		).
+		Suffix(`ON CONFLICT (` + federationRemoteActorFieldID + `) DO UPDATE SET
+			username = excluded.username,
+			user_id = excluded.user_id,
+			inbox = excluded.inbox,
+			shared_inbox = excluded.shared_inbox,
+			public_key_id = excluded.public_key_id,
+			public_key_pem = excluded.public_key_pem,
+			fetched_at = excluded.fetched_at`)
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec upsert: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *FederationRemoteActorRepository) Find(
+	ctx context.Context,
+	actorID string,
+) (*federation.RemoteActor, error) {
+	actor, err := repo.find(ctx, sq.Eq{federationRemoteActorFieldID: actorID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, federation.RemoteActorNotFoundError{ID: actorID}
+		}
+
+		return nil, err
+	}
+
+	return actor, nil
+}
+
+func (repo *FederationRemoteActorRepository) FindByPublicKeyID(
+	ctx context.Context,
+	publicKeyID string,
+) (*federation.RemoteActor, error) {
+	actor, err := repo.find(ctx, sq.Eq{federationRemoteActorFieldPublicKeyID: publicKeyID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, federation.RemoteActorByPublicKeyIDNotFoundError{PublicKeyID: publicKeyID}
+		}
+
+		return nil, err
+	}
+
+	return actor, nil
+}
+
+func (repo *FederationRemoteActorRepository) find(ctx context.Context, where sq.Eq) (*federation.RemoteActor, error) {
+	q := sq.Select(federationRemoteActorColumns()...).
+		From(tableFederationRemoteActors).
+		Where(where)
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	row := q.QueryRowContext(ctx)
+
+	actor, err := scanFederationRemoteActor(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan remote actor: %w", err)
+	}
+
+	return actor, nil
+}
@@ -0,0 +1,224 @@
+package sqlite3_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nasermirzaei89/scribble/authentication"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/database/sqlite3"
+	"github.com/nasermirzaei89/scribble/discuss"
+	"github.com/nasermirzaei89/scribble/federation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFederationRepositories(t *testing.T) {
+	ctx, db := newTestDB(t)
+
+	userRepo := sqlite3.NewUserRepository(db)
+	keyRepo := sqlite3.NewFederationKeyRepository(db)
+	remoteActorRepo := sqlite3.NewFederationRemoteActorRepository(db)
+	followerRepo := sqlite3.NewFederationFollowerRepository(db)
+	objectRepo := sqlite3.NewFederationObjectRepository(db)
+	deliveryRepo := sqlite3.NewFederationDeliveryRepository(db)
+
+	user := &authentication.User{
+		ID:           uuid.NewString(),
+		Username:     "federated-" + uuid.NewString(),
+		PasswordHash: "password-hash",
+		RegisteredAt: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+	}
+
+	err := userRepo.Insert(ctx, user)
+	require.NoError(t, err)
+
+	t.Run("Keys", func(t *testing.T) {
+		_, err := keyRepo.Find(ctx, user.ID)
+		require.ErrorAs(t, err, &federation.KeyNotFoundError{})
+
+		key := &federation.Key{
+			UserID:        user.ID,
+			PublicKeyPEM:  "public",
+			PrivateKeyPEM: "private",
+			CreatedAt:     time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC),
+		}
+
+		err = keyRepo.Insert(ctx, key)
+		require.NoError(t, err)
+
+		err = keyRepo.Insert(ctx, key)
+		require.Error(t, err)
+
+		found, err := keyRepo.Find(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "public", found.PublicKeyPEM)
+		assert.Equal(t, "private", found.PrivateKeyPEM)
+	})
+
+	t.Run("Remote actors", func(t *testing.T) {
+		actor := &federation.RemoteActor{
+			ID:           "https://remote.example/users/bob",
+			Username:     "bob@remote.example",
+			UserID:       "",
+			Inbox:        "https://remote.example/users/bob/inbox",
+			SharedInbox:  "",
+			PublicKeyID:  "https://remote.example/users/bob#main-key",
+			PublicKeyPEM: "old",
+			FetchedAt:    time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC),
+		}
+
+		err := remoteActorRepo.Upsert(ctx, actor)
+		require.NoError(t, err)
+
+		found, err := remoteActorRepo.FindByPublicKeyID(ctx, actor.PublicKeyID)
+		require.NoError(t, err)
+		assert.Equal(t, actor.ID, found.ID)
+		assert.Empty(t, found.UserID)
+
+		actor.UserID = user.ID
+		actor.SharedInbox = "https://remote.example/inbox"
+		actor.PublicKeyPEM = "new"
+
+		err = remoteActorRepo.Upsert(ctx, actor)
+		require.NoError(t, err)
+
+		found, err = remoteActorRepo.Find(ctx, actor.ID)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, found.UserID)
+		assert.Equal(t, "new", found.PublicKeyPEM)
+		assert.Equal(t, "https://remote.example/inbox", found.DeliveryInbox())
+
+		_, err = remoteActorRepo.Find(ctx, "https://remote.example/users/carol")
+		require.ErrorAs(t, err, &federation.RemoteActorNotFoundError{})
+
+		_, err = remoteActorRepo.FindByPublicKeyID(ctx, "https://remote.example/users/carol#main-key")
+		require.ErrorAs(t, err, &federation.RemoteActorByPublicKeyIDNotFoundError{})
+	})
+
+	t.Run("Followers", func(t *testing.T) {
+		for i, actorID := range []string{"https://remote.example/users/bob", "https://other.example/users/carol"} {
+			err := followerRepo.Upsert(ctx, &federation.Follower{
+				UserID:     user.ID,
+				ActorID:    actorID,
+				Inbox:      actorID + "/inbox",
+				FollowedAt: time.Date(2026, 3, 1, 12+i, 0, 0, 0, time.UTC),
+			})
+			require.NoError(t, err)
+		}
+
+		err := followerRepo.Upsert(ctx, &federation.Follower{
+			UserID:     user.ID,
+			ActorID:    "https://remote.example/users/bob",
+			Inbox:      "https://remote.example/inbox",
+			FollowedAt: time.Date(2026, 3, 1, 14, 0, 0, 0, time.UTC),
+		})
+		require.NoError(t, err)
+
+		followers, err := followerRepo.List(ctx, user.ID)
+		require.NoError(t, err)
+		require.Len(t, followers, 2)
+		assert.Equal(t, "https://remote.example/inbox", followers[0].Inbox)
+
+		err = followerRepo.Delete(ctx, user.ID, "https://remote.example/users/bob")
+		require.NoError(t, err)
+
+		count, err := followerRepo.Count(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("Objects", func(t *testing.T) {
+		post := &contents.Post{
+			ID:          uuid.NewString(),
+			AuthorID:    user.ID,
+			CommunityID: "",
+			Content:     "Hello",
+			CreatedAt:   time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		}
+
+		err := sqlite3.NewPostRepository(db).Insert(ctx, post)
+		require.NoError(t, err)
+
+		comment := &discuss.Comment{
+			ID:        uuid.NewString(),
+			PostID:    post.ID,
+			AuthorID:  user.ID,
+			ReplyTo:   nil,
+			Content:   "Hi",
+			CreatedAt: time.Date(2026, 3, 1, 13, 0, 0, 0, time.UTC),
+		}
+
+		err = sqlite3.NewCommentRepository(db).Insert(ctx, comment)
+		require.NoError(t, err)
+
+		object := &federation.Object{
+			ID:         "https://remote.example/notes/1",
+			PostID:     post.ID,
+			CommentID:  comment.ID,
+			ReceivedAt: time.Date(2026, 3, 1, 13, 0, 0, 0, time.UTC),
+		}
+
+		err = objectRepo.Insert(ctx, object)
+		require.NoError(t, err)
+
+		found, err := objectRepo.Find(ctx, object.ID)
+		require.NoError(t, err)
+		assert.Equal(t, post.ID, found.PostID)
+		assert.Equal(t, comment.ID, found.CommentID)
+
+		_, err = objectRepo.Find(ctx, "https://remote.example/notes/2")
+		require.ErrorAs(t, err, &federation.ObjectNotFoundError{})
+	})
+
+	t.Run("Deliveries", func(t *testing.T) {
+		now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+		newDelivery := func(nextAttemptAt time.Time) *federation.Delivery {
+			return &federation.Delivery{
+				ID:            uuid.NewString(),
+				UserID:        user.ID,
+				Inbox:         "https://remote.example/inbox",
+				Activity:      []byte(`{"type":"Create"}`),
+				Status:        federation.DeliveryStatusPending,
+				Attempts:      0,
+				NextAttemptAt: nextAttemptAt,
+				LastError:     "",
+				CreatedAt:     now,
+				UpdatedAt:     now,
+			}
+		}
+
+		later := newDelivery(now.Add(time.Minute))
+		due := newDelivery(now.Add(-time.Minute))
+		done := newDelivery(now.Add(-time.Hour))
+		done.Status = federation.DeliveryStatusDelivered
+
+		for _, delivery := range []*federation.Delivery{later, due, done} {
+			err := deliveryRepo.Insert(ctx, delivery)
+			require.NoError(t, err)
+		}
+
+		list, err := deliveryRepo.ListDue(ctx, now, 10)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, due.ID, list[0].ID)
+		assert.JSONEq(t, `{"type":"Create"}`, string(list[0].Activity))
+
+		due.Attempts = 1
+		due.LastError = "boom"
+		due.NextAttemptAt = now.Add(2 * time.Minute)
+
+		err = deliveryRepo.Update(ctx, due)
+		require.NoError(t, err)
+
+		list, err = deliveryRepo.ListDue(ctx, now.Add(2*time.Minute), 10)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		assert.Equal(t, later.ID, list[0].ID)
+		assert.Equal(t, due.ID, list[1].ID)
+		assert.Equal(t, 1, list[1].Attempts)
+		assert.Equal(t, "boom", list[1].LastError)
+	})
+}
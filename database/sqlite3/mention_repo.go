@@ -0,0 +1,133 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/nasermirzaei89/scribble/mentions"
+)
+
+const tableMentions = "mentions"
+
+type MentionRepository struct {
+	db *sql.DB
+}
+
+var _ mentions.MentionRepository = (*MentionRepository)(nil)
+
+func NewMentionRepository(db *sql.DB) *MentionRepository {
+	return &MentionRepository{db: db}
+}
+
+const (
+	mentionFieldID        = "id"
+	mentionFieldUserID    = "user_id"
+	mentionFieldAuthorID  = "author_id"
+	mentionFieldPostID    = "post_id"
+	mentionFieldCommentID = "comment_id"
+	mentionFieldCreatedAt = "created_at"
+)
+
+func mentionColumns() []string {
+	return []string{
+		mentionFieldID,
+		mentionFieldUserID,
+		mentionFieldAuthorID,
+		mentionFieldPostID,
+		mentionFieldCommentID,
+		mentionFieldCreatedAt,
+	}
+}
+
+func scanMention(row sq.RowScanner) (*mentions.Mention, error) {
+	var mention mentions.Mention
+
+	err := row.Scan(
+		&mention.ID,
+		&mention.UserID,
+		&mention.AuthorID,
+		&mention.PostID,
+		&mention.CommentID,
+		&mention.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	return &mention, nil
+}
+
+func (repo *MentionRepository) Insert(ctx context.Context, mention *mentions.Mention) error {
+	q := sq.Insert(tableMentions).
+		Columns(mentionColumns()...).
+		Values(
+			mention.ID,
+			mention.UserID,
+			mention.AuthorID,
+			mention.PostID,
+			mention.CommentID,
+			mention.CreatedAt,
+		)
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec insert: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *MentionRepository) List(
+	ctx context.Context,
+	params *mentions.ListMentionsParams,
+) ([]*mentions.Mention, error) {
+	q := sq.Select(mentionColumns()...).
+		From(tableMentions).
+		Where(sq.Eq{mentionFieldUserID: params.UserID}).
+		OrderBy(mentionFieldCreatedAt+" DESC", mentionFieldID+" DESC")
+
+	if params.Limit > 0 {
+		q = q.Limit(uint64(params.Limit))
+	}
+
+	if params.Offset > 0 {
+		q = q.Offset(uint64(params.Offset))
+	}
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	rows, err := q.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	result := make([]*mentions.Mention, 0)
+
+	for rows.Next() {
+		mention, err := scanMention(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan mention: %w", err)
+		}
+
+		result = append(result, mention)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,67 @@
+package sqlite3_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nasermirzaei89/scribble/database/sqlite3"
+	"github.com/nasermirzaei89/scribble/mentions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMentionRepository(t *testing.T) {
+	ctx, db := newTestDB(t)
+
+	repo := sqlite3.NewMentionRepository(db)
+
+	userID := uuid.NewString()
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	inserted := make([]*mentions.Mention, 0, 3)
+
+	for i, commentID := range []string{"", "comment1", "comment2"} {
+		mention := &mentions.Mention{
+			ID:        uuid.NewString(),
+			UserID:    userID,
+			AuthorID:  "author1",
+			PostID:    "post1",
+			CommentID: commentID,
+			CreatedAt: base.Add(time.Duration(i) * time.Minute),
+		}
+
+		err := repo.Insert(ctx, mention)
+		require.NoError(t, err)
+
+		inserted = append(inserted, mention)
+	}
+
+	err := repo.Insert(ctx, &mentions.Mention{
+		ID:        uuid.NewString(),
+		UserID:    uuid.NewString(),
+		AuthorID:  "author1",
+		PostID:    "post1",
+		CommentID: "",
+		CreatedAt: base,
+	})
+	require.NoError(t, err)
+
+	t.Run("List", func(t *testing.T) {
+		list, err := repo.List(ctx, &mentions.ListMentionsParams{UserID: userID, Limit: 0, Offset: 0})
+		require.NoError(t, err)
+		require.Len(t, list, 3)
+		assert.Equal(t, inserted[2].ID, list[0].ID)
+		assert.Equal(t, "comment2", list[0].CommentID)
+		assert.Equal(t, inserted[0].ID, list[2].ID)
+		assert.Empty(t, list[2].CommentID)
+		assert.True(t, inserted[0].CreatedAt.Equal(list[2].CreatedAt))
+	})
+
+	t.Run("List paginated", func(t *testing.T) {
+		list, err := repo.List(ctx, &mentions.ListMentionsParams{UserID: userID, Limit: 1, Offset: 1})
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, inserted[1].ID, list[0].ID)
+	})
+}
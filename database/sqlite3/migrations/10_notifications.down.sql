@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS notification_mutes;
+DROP INDEX IF EXISTS idx_notifications_user_id_updated_at;
+DROP INDEX IF EXISTS idx_notifications_user_id_group_key_unread;
+DROP TABLE IF EXISTS notifications;
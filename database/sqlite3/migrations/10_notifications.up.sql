@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS notifications (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('comment', 'reply', 'reaction')),
+    group_key TEXT NOT NULL,
+    post_id TEXT NOT NULL,
+    comment_id TEXT NOT NULL DEFAULT '',
+    emoji TEXT NOT NULL DEFAULT '',
+    -- JSON array of the users who acted, the latest first.
+    actor_ids TEXT NOT NULL,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Actions on the same thing are grouped into one unread notification.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_user_id_group_key_unread
+    ON notifications (user_id, group_key) WHERE read_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id_updated_at ON notifications (user_id, updated_at);
+
+CREATE TABLE IF NOT EXISTS notification_mutes (
+    user_id TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('comment', 'reply', 'reaction')),
+    PRIMARY KEY (user_id, type),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,38 @@
+CREATE TABLE notification_mutes_old (
+    user_id TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('comment', 'reply', 'reaction')),
+    PRIMARY KEY (user_id, type),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+INSERT INTO notification_mutes_old SELECT * FROM notification_mutes WHERE type != 'mention';
+DROP TABLE notification_mutes;
+ALTER TABLE notification_mutes_old RENAME TO notification_mutes;
+
+CREATE TABLE notifications_old (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('comment', 'reply', 'reaction')),
+    group_key TEXT NOT NULL,
+    post_id TEXT NOT NULL,
+    comment_id TEXT NOT NULL DEFAULT '',
+    emoji TEXT NOT NULL DEFAULT '',
+    -- JSON array of the users who acted, the latest first.
+    actor_ids TEXT NOT NULL,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+INSERT INTO notifications_old SELECT * FROM notifications WHERE type != 'mention';
+DROP TABLE notifications;
+ALTER TABLE notifications_old RENAME TO notifications;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_user_id_group_key_unread
+    ON notifications (user_id, group_key) WHERE read_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id_updated_at ON notifications (user_id, updated_at);
+
+DROP INDEX IF EXISTS idx_mentions_user_id_created_at;
+DROP TABLE IF EXISTS mentions;
@@ -0,0 +1,51 @@
+CREATE TABLE IF NOT EXISTS mentions (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    author_id TEXT NOT NULL,
+    post_id TEXT NOT NULL,
+    comment_id TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_mentions_user_id_created_at ON mentions (user_id, created_at);
+
+-- SQLite cannot alter CHECK constraints, so the notification tables are rebuilt to take the mention type.
+CREATE TABLE notifications_new (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('comment', 'reply', 'reaction', 'mention')),
+    group_key TEXT NOT NULL,
+    post_id TEXT NOT NULL,
+    comment_id TEXT NOT NULL DEFAULT '',
+    emoji TEXT NOT NULL DEFAULT '',
+    -- JSON array of the users who acted, the latest first.
+    actor_ids TEXT NOT NULL,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+INSERT INTO notifications_new SELECT * FROM notifications;
+DROP TABLE notifications;
+ALTER TABLE notifications_new RENAME TO notifications;
+
+-- Actions on the same thing are grouped into one unread notification.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_user_id_group_key_unread
+    ON notifications (user_id, group_key) WHERE read_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id_updated_at ON notifications (user_id, updated_at);
+
+CREATE TABLE notification_mutes_new (
+    user_id TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('comment', 'reply', 'reaction', 'mention')),
+    PRIMARY KEY (user_id, type),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+INSERT INTO notification_mutes_new SELECT * FROM notification_mutes;
+DROP TABLE notification_mutes;
+ALTER TABLE notification_mutes_new RENAME TO notification_mutes;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS notification_email_settings (
+    user_id TEXT PRIMARY KEY,
+    address TEXT NOT NULL DEFAULT '',
+    frequency TEXT NOT NULL CHECK (frequency IN ('off', 'immediate', 'daily', 'weekly')),
+    -- Notifications updated since go into the next email.
+    last_sent_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS reaction_sets (
+    scope TEXT NOT NULL CHECK (scope IN ('site', 'targetType', 'community', 'post')),
+    -- The target type, community ID or post ID, empty for the site.
+    scope_id TEXT NOT NULL DEFAULT '',
+    -- JSON array of emojis.
+    emojis TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (scope, scope_id)
+);
@@ -0,0 +1,23 @@
+ALTER TABLE reaction_sets DROP COLUMN max_per_user;
+
+CREATE TABLE reactions_old (
+    target_type TEXT NOT NULL CHECK (target_type IN ('post', 'comment')),
+    target_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    emoji TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (target_type, target_id, user_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Only the latest reaction of each user on a target is kept.
+INSERT INTO reactions_old
+SELECT target_type, target_id, user_id, emoji, MAX(created_at)
+FROM reactions
+GROUP BY target_type, target_id, user_id;
+
+DROP TABLE reactions;
+ALTER TABLE reactions_old RENAME TO reactions;
+
+CREATE INDEX IF NOT EXISTS idx_reactions_target ON reactions (target_type, target_id);
+CREATE INDEX IF NOT EXISTS idx_reactions_target_emoji ON reactions (target_type, target_id, emoji);
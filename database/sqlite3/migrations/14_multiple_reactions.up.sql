@@ -0,0 +1,20 @@
+-- SQLite cannot alter primary keys, so the reactions table is rebuilt to hold several emojis per user and target.
+CREATE TABLE reactions_new (
+    target_type TEXT NOT NULL CHECK (target_type IN ('post', 'comment')),
+    target_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    emoji TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (target_type, target_id, user_id, emoji),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+INSERT INTO reactions_new SELECT * FROM reactions;
+DROP TABLE reactions;
+ALTER TABLE reactions_new RENAME TO reactions;
+
+CREATE INDEX IF NOT EXISTS idx_reactions_target ON reactions (target_type, target_id);
+CREATE INDEX IF NOT EXISTS idx_reactions_target_emoji ON reactions (target_type, target_id, emoji);
+
+-- How many emojis a user can react with at once. With one, reacting again replaces the reaction.
+ALTER TABLE reaction_sets ADD COLUMN max_per_user INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS custom_emojis (
+    shortcode TEXT PRIMARY KEY,
+    content_type TEXT NOT NULL,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,12 @@
+-- Counts of the reactions to each target by emoji, kept up to date by the reaction repository, so pages do not
+-- count the reactions of every post and comment they show.
+CREATE TABLE IF NOT EXISTS reaction_counts (
+    target_type TEXT NOT NULL,
+    target_id TEXT NOT NULL,
+    emoji TEXT NOT NULL,
+    count INTEGER NOT NULL,
+    PRIMARY KEY (target_type, target_id, emoji)
+);
+
+INSERT INTO reaction_counts (target_type, target_id, emoji, count)
+SELECT target_type, target_id, emoji, COUNT(*) FROM reactions GROUP BY target_type, target_id, emoji;
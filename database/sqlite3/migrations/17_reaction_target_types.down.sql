@@ -0,0 +1,19 @@
+CREATE TABLE reactions_old (
+    target_type TEXT NOT NULL CHECK (target_type IN ('post', 'comment')),
+    target_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    emoji TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (target_type, target_id, user_id, emoji),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Reactions to the other types of targets cannot be kept.
+INSERT INTO reactions_old SELECT * FROM reactions WHERE target_type IN ('post', 'comment');
+DELETE FROM reaction_counts WHERE target_type NOT IN ('post', 'comment');
+
+DROP TABLE reactions;
+ALTER TABLE reactions_old RENAME TO reactions;
+
+CREATE INDEX IF NOT EXISTS idx_reactions_target ON reactions (target_type, target_id);
+CREATE INDEX IF NOT EXISTS idx_reactions_target_emoji ON reactions (target_type, target_id, emoji);
@@ -0,0 +1,17 @@
+-- The types of targets are registered by the application, so the reactions table is rebuilt without limiting them.
+CREATE TABLE reactions_new (
+    target_type TEXT NOT NULL,
+    target_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    emoji TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (target_type, target_id, user_id, emoji),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+INSERT INTO reactions_new SELECT * FROM reactions;
+DROP TABLE reactions;
+ALTER TABLE reactions_new RENAME TO reactions;
+
+CREATE INDEX IF NOT EXISTS idx_reactions_target ON reactions (target_type, target_id);
+CREATE INDEX IF NOT EXISTS idx_reactions_target_emoji ON reactions (target_type, target_id, emoji);
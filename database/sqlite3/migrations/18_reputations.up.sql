@@ -0,0 +1,11 @@
+-- The reputations of users, rescored by the application as they receive reactions and write.
+CREATE TABLE IF NOT EXISTS reputations (
+    user_id TEXT PRIMARY KEY,
+    reaction_points INTEGER NOT NULL DEFAULT 0,
+    posts INTEGER NOT NULL DEFAULT 0,
+    comments INTEGER NOT NULL DEFAULT 0,
+    score INTEGER NOT NULL DEFAULT 0,
+    trusted BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
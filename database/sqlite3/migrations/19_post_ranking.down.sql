@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_posts_hot;
+DROP INDEX IF EXISTS idx_posts_top;
+ALTER TABLE posts DROP COLUMN hotness;
+ALTER TABLE posts DROP COLUMN reaction_score;
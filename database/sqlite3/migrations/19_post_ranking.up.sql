@@ -0,0 +1,9 @@
+-- Posts keep their rank in the top and hot sorts, so listing them does not count reactions and comments. Existing
+-- posts rank by age alone until the application reranks them.
+ALTER TABLE posts ADD COLUMN reaction_score INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE posts ADD COLUMN hotness REAL NOT NULL DEFAULT 0;
+
+UPDATE posts SET hotness = (julianday(substr(created_at, 1, 19)) - 2440587.5) * 86400 / 45000;
+
+CREATE INDEX IF NOT EXISTS idx_posts_top ON posts (reaction_score, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_posts_hot ON posts (hotness, id);
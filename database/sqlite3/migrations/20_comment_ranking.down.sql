@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_comments_reply_to;
+DROP INDEX IF EXISTS idx_comments_post_top;
+DROP INDEX IF EXISTS idx_comments_post_created_at;
+ALTER TABLE comments DROP COLUMN reaction_score;
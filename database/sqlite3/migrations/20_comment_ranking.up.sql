@@ -0,0 +1,10 @@
+-- Comments keep their reaction score for the top sort, scored by the application as they receive reactions, and are
+-- indexed for loading threads a page and a level at a time.
+ALTER TABLE comments ADD COLUMN reaction_score INTEGER NOT NULL DEFAULT 0;
+
+-- Creation times are compared to page through comments, so they drop the monotonic clock reading some were stored with.
+UPDATE comments SET created_at = substr(created_at, 1, instr(created_at, ' m=') - 1) WHERE instr(created_at, ' m=') > 0;
+
+CREATE INDEX IF NOT EXISTS idx_comments_post_created_at ON comments (post_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_comments_post_top ON comments (post_id, reaction_score, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_comments_reply_to ON comments (reply_to);
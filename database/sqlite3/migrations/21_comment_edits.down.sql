@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_comment_edits_comment_id;
+DROP TABLE IF EXISTS comment_edits;
+ALTER TABLE comments DROP COLUMN deleted_at;
+ALTER TABLE comments DROP COLUMN edited_at;
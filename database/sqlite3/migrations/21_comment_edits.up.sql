@@ -0,0 +1,14 @@
+-- Authors can edit and delete their comments. Edits keep the previous content, and deleted comments stay as tombstones
+-- without content so their replies keep their place.
+ALTER TABLE comments ADD COLUMN edited_at TIMESTAMP;
+ALTER TABLE comments ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS comment_edits (
+    id TEXT PRIMARY KEY,
+    comment_id TEXT NOT NULL,
+    content TEXT NOT NULL,
+    edited_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_edits_comment_id ON comment_edits (comment_id, edited_at);
@@ -0,0 +1,5 @@
+DELETE FROM casbin_rule
+WHERE p_type = 'p'
+  AND v1 = 'github.com/nasermirzaei89/scribble/discuss'
+  AND (v2 = 'own' OR v0 = 'system:group:moderator')
+  AND v3 IN ('updateComment', 'deleteComment');
@@ -0,0 +1,8 @@
+-- Authors change their own comments through the "own" object instead of a grant on every comment, which the policy no
+-- longer has.
+DELETE FROM casbin_rule
+WHERE p_type = 'p'
+  AND v0 = 'system:authenticated'
+  AND v1 = 'github.com/nasermirzaei89/scribble/discuss'
+  AND v2 = '*'
+  AND v3 IN ('updateComment', 'deleteComment');
@@ -0,0 +1,9 @@
+DELETE FROM casbin_rule WHERE p_type = 'g' AND v2 NOT IN ('', '*');
+UPDATE casbin_rule SET v2 = '' WHERE p_type = 'g' AND v2 = '*';
+
+DROP INDEX IF EXISTS idx_posts_community_id;
+ALTER TABLE posts DROP COLUMN community_id;
+
+DROP INDEX IF EXISTS idx_community_members_user_id;
+DROP TABLE IF EXISTS community_members;
+DROP TABLE IF EXISTS communities;
@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS communities (
+    id TEXT PRIMARY KEY,
+    slug TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    owner_id TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS community_members (
+    community_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    role TEXT NOT NULL CHECK (role IN ('community:member', 'community:moderator', 'community:owner')),
+    joined_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (community_id, user_id),
+    FOREIGN KEY (community_id) REFERENCES communities(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_community_members_user_id ON community_members (user_id);
+
+ALTER TABLE posts ADD COLUMN community_id TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_posts_community_id ON posts (community_id);
+
+-- Group bindings now have a domain. Bindings stored before that are global.
+CREATE TABLE IF NOT EXISTS casbin_rule (
+    p_type VARCHAR(32) DEFAULT '' NOT NULL,
+    v0 VARCHAR(255) DEFAULT '' NOT NULL,
+    v1 VARCHAR(255) DEFAULT '' NOT NULL,
+    v2 VARCHAR(255) DEFAULT '' NOT NULL,
+    v3 VARCHAR(255) DEFAULT '' NOT NULL,
+    v4 VARCHAR(255) DEFAULT '' NOT NULL,
+    v5 VARCHAR(255) DEFAULT '' NOT NULL
+);
+
+UPDATE casbin_rule SET v2 = '*' WHERE p_type = 'g' AND v2 = '';
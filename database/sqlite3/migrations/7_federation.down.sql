@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_federation_deliveries_status_next_attempt_at;
+DROP TABLE IF EXISTS federation_deliveries;
+DROP TABLE IF EXISTS federation_objects;
+DROP TABLE IF EXISTS federation_followers;
+DROP INDEX IF EXISTS idx_federation_remote_actors_public_key_id;
+DROP TABLE IF EXISTS federation_remote_actors;
+DROP TABLE IF EXISTS federation_keys;
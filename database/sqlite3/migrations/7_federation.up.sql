@@ -0,0 +1,58 @@
+CREATE TABLE IF NOT EXISTS federation_keys (
+    user_id TEXT PRIMARY KEY,
+    public_key_pem TEXT NOT NULL,
+    private_key_pem TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Remote actors that reply get a local user without a password, so their replies can be comments.
+CREATE TABLE IF NOT EXISTS federation_remote_actors (
+    id TEXT PRIMARY KEY,
+    username TEXT NOT NULL,
+    user_id TEXT UNIQUE,
+    inbox TEXT NOT NULL,
+    shared_inbox TEXT NOT NULL DEFAULT '',
+    public_key_id TEXT NOT NULL,
+    public_key_pem TEXT NOT NULL,
+    fetched_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_federation_remote_actors_public_key_id ON federation_remote_actors (public_key_id);
+
+CREATE TABLE IF NOT EXISTS federation_followers (
+    user_id TEXT NOT NULL,
+    actor_id TEXT NOT NULL,
+    inbox TEXT NOT NULL,
+    followed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, actor_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Remote objects that became comments, to skip redelivered activities and thread replies to them.
+CREATE TABLE IF NOT EXISTS federation_objects (
+    id TEXT PRIMARY KEY,
+    post_id TEXT NOT NULL,
+    comment_id TEXT NOT NULL UNIQUE,
+    received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS federation_deliveries (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    inbox TEXT NOT NULL,
+    activity TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('pending', 'delivered', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_federation_deliveries_status_next_attempt_at
+    ON federation_deliveries (status, next_attempt_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_event_outbox_status_next_attempt_at;
+DROP TABLE IF EXISTS event_outbox;
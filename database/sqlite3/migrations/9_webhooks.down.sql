@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_webhook_deliveries_endpoint_id_created_at;
+DROP INDEX IF EXISTS idx_webhook_deliveries_status_next_attempt_at;
+DROP INDEX IF EXISTS idx_webhook_deliveries_endpoint_id_event_id;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_endpoints;
@@ -0,0 +1,148 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/nasermirzaei89/scribble/notifications"
+)
+
+const tableNotificationEmailSettings = "notification_email_settings"
+
+type NotificationEmailSettingsRepository struct {
+	db *sql.DB
+}
+
+var _ notifications.EmailSettingsRepository = (*NotificationEmailSettingsRepository)(nil)
+
+func NewNotificationEmailSettingsRepository(db *sql.DB) *NotificationEmailSettingsRepository {
+	return &NotificationEmailSettingsRepository{db: db}
+}
+
+const (
+	notificationEmailSettingsFieldUserID     = "user_id"
+	notificationEmailSettingsFieldAddress    = "address"
+	notificationEmailSettingsFieldFrequency  = "frequency"
+	notificationEmailSettingsFieldLastSentAt = "last_sent_at"
+)
+
+func notificationEmailSettingsColumns() []string {
+	return []string{
+		notificationEmailSettingsFieldUserID,
+		notificationEmailSettingsFieldAddress,
+		notificationEmailSettingsFieldFrequency,
+		notificationEmailSettingsFieldLastSentAt,
+	}
+}
+
+func scanNotificationEmailSettings(row sq.RowScanner) (*notifications.EmailSettings, error) {
+	var settings notifications.EmailSettings
+
+	err := row.Scan(
+		&settings.UserID,
+		&settings.Address,
+		&settings.Frequency,
+		&settings.LastSentAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	return &settings, nil
+}
+
+func (repo *NotificationEmailSettingsRepository) Find(
+	ctx context.Context,
+	userID string,
+) (*notifications.EmailSettings, error) {
+	q := sq.Select(notificationEmailSettingsColumns()...).
+		From(tableNotificationEmailSettings).
+		Where(sq.Eq{notificationEmailSettingsFieldUserID: userID})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	row := q.QueryRowContext(ctx)
+
+	settings, err := scanNotificationEmailSettings(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notifications.EmailSettingsNotFoundError{UserID: userID}
+		}
+
+		return nil, fmt.Errorf("failed to scan email settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+func (repo *NotificationEmailSettingsRepository) Save(
+	ctx context.Context,
+	settings *notifications.EmailSettings,
+) error {
+	q := sq.Insert(tableNotificationEmailSettings).
+		Columns(notificationEmailSettingsColumns()...).
+		Values(
+			settings.UserID,
+			settings.Address,
+			settings.Frequency,
+			settings.LastSentAt,
+		).
+		Suffix(`ON CONFLICT (` + notificationEmailSettingsFieldUserID + `) DO UPDATE SET ` +
+			notificationEmailSettingsFieldAddress + ` = excluded.` + notificationEmailSettingsFieldAddress + `, ` +
+			notificationEmailSettingsFieldFrequency + ` = excluded.` + notificationEmailSettingsFieldFrequency + `, ` +
+			notificationEmailSettingsFieldLastSentAt + ` = excluded.` + notificationEmailSettingsFieldLastSentAt)
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec insert: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *NotificationEmailSettingsRepository) ListEnabled(
+	ctx context.Context,
+) ([]*notifications.EmailSettings, error) {
+	q := sq.Select(notificationEmailSettingsColumns()...).
+		From(tableNotificationEmailSettings).
+		Where(sq.NotEq{notificationEmailSettingsFieldFrequency: notifications.EmailFrequencyOff}).
+		OrderBy(notificationEmailSettingsFieldUserID + " ASC")
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	rows, err := q.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	result := make([]*notifications.EmailSettings, 0)
+
+	for rows.Next() {
+		settings, err := scanNotificationEmailSettings(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan email settings: %w", err)
+		}
+
+		result = append(result, settings)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,106 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/nasermirzaei89/scribble/notifications"
+)
+
+const tableNotificationMutes = "notification_mutes"
+
+// NotificationMuteRepository stores the notification types users muted.
+type NotificationMuteRepository struct {
+	db *sql.DB
+}
+
+var _ notifications.PreferenceRepository = (*NotificationMuteRepository)(nil)
+
+func NewNotificationMuteRepository(db *sql.DB) *NotificationMuteRepository {
+	return &NotificationMuteRepository{db: db}
+}
+
+const (
+	notificationMuteFieldUserID = "user_id"
+	notificationMuteFieldType   = "type"
+)
+
+func (repo *NotificationMuteRepository) ListMuted(ctx context.Context, userID string) ([]notifications.Type, error) {
+	q := sq.Select(notificationMuteFieldType).
+		From(tableNotificationMutes).
+		Where(sq.Eq{notificationMuteFieldUserID: userID}).
+		OrderBy(notificationMuteFieldType + " ASC")
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	rows, err := q.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	result := make([]notifications.Type, 0)
+
+	for rows.Next() {
+		var typ notifications.Type
+
+		err := rows.Scan(&typ)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		result = append(result, typ)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return result, nil
+}
+
+func (repo *NotificationMuteRepository) SetMuted(
+	ctx context.Context,
+	userID string,
+	typ notifications.Type,
+	muted bool,
+) error {
+	if !muted {
+		q := sq.Delete(tableNotificationMutes).
+			Where(sq.Eq{
+				notificationMuteFieldUserID: userID,
+				notificationMuteFieldType:   typ,
+			}).
+			RunWith(runner(ctx, repo.db))
+
+		_, err := q.ExecContext(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to exec delete: %w", err)
+		}
+
+		return nil
+	}
+
+	q := sq.Insert(tableNotificationMutes).
+		Columns(notificationMuteFieldUserID, notificationMuteFieldType).
+		Values(userID, typ).
+		Suffix(`ON CONFLICT (` + notificationMuteFieldUserID + `, ` + notificationMuteFieldType + `) DO NOTHING`).
+		RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec insert: %w", err)
+	}
+
+	return nil
+}
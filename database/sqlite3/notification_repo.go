@@ -0,0 +1,304 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/nasermirzaei89/scribble/notifications"
+)
+
+const tableNotifications = "notifications"
+
+type NotificationRepository struct {
+	db *sql.DB
+}
+
+var _ notifications.NotificationRepository = (*NotificationRepository)(nil)
+
+func NewNotificationRepository(db *sql.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+const (
+	notificationFieldID        = "id"
+	notificationFieldUserID    = "user_id"
+	notificationFieldType      = "type"
+	notificationFieldGroupKey  = "group_key"
+	notificationFieldPostID    = "post_id"
+	notificationFieldCommentID = "comment_id"
+	notificationFieldEmoji     = "emoji"
+	notificationFieldActorIDs  = "actor_ids"
+	notificationFieldReadAt    = "read_at"
+	notificationFieldCreatedAt = "created_at"
+	notificationFieldUpdatedAt = "updated_at"
+)
+
+func notificationColumns() []string {
+	return []string{
+		notificationFieldID,
+		notificationFieldUserID,
+		notificationFieldType,
+		notificationFieldGroupKey,
+		notificationFieldPostID,
+		notificationFieldCommentID,
+		notificationFieldEmoji,
+		notificationFieldActorIDs,
+		notificationFieldReadAt,
+		notificationFieldCreatedAt,
+		notificationFieldUpdatedAt,
+	}
+}
+
+func scanNotification(row sq.RowScanner) (*notifications.Notification, error) {
+	var (
+		notification notifications.Notification
+		actorIDs     string
+	)
+
+	err := row.Scan(
+		&notification.ID,
+		&notification.UserID,
+		&notification.Type,
+		&notification.GroupKey,
+		&notification.PostID,
+		&notification.CommentID,
+		&notification.Emoji,
+		&actorIDs,
+		&notification.ReadAt,
+		&notification.CreatedAt,
+		&notification.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	err = json.Unmarshal([]byte(actorIDs), &notification.ActorIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal actor ids: %w", err)
+	}
+
+	return &notification, nil
+}
+
+func (repo *NotificationRepository) Insert(ctx context.Context, notification *notifications.Notification) error {
+	actorIDs, err := json.Marshal(notification.ActorIDs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal actor ids: %w", err)
+	}
+
+	q := sq.Insert(tableNotifications).
+		Columns(notificationColumns()...).
+		Values(
+			notification.ID,
+			notification.UserID,
+			notification.Type,
+			notification.GroupKey,
+			notification.PostID,
+			notification.CommentID,
+			notification.Emoji,
+			string(actorIDs),
+			notification.ReadAt,
+			notification.CreatedAt,
+			notification.UpdatedAt,
+		)
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err = q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec insert: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *NotificationRepository) Update(ctx context.Context, notification *notifications.Notification) error {
+	actorIDs, err := json.Marshal(notification.ActorIDs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal actor ids: %w", err)
+	}
+
+	q := sq.Update(tableNotifications).
+		Set(notificationFieldActorIDs, string(actorIDs)).
+		Set(notificationFieldReadAt, notification.ReadAt).
+		Set(notificationFieldUpdatedAt, notification.UpdatedAt).
+		Where(sq.Eq{notificationFieldID: notification.ID})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err = q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec update: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *NotificationRepository) Delete(ctx context.Context, notificationID string) error {
+	q := sq.Delete(tableNotifications).
+		Where(sq.Eq{notificationFieldID: notificationID})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec delete: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *NotificationRepository) Find(
+	ctx context.Context,
+	notificationID string,
+) (*notifications.Notification, error) {
+	q := sq.Select(notificationColumns()...).
+		From(tableNotifications).
+		Where(sq.Eq{notificationFieldID: notificationID})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	row := q.QueryRowContext(ctx)
+
+	notification, err := scanNotification(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notifications.NotificationNotFoundError{ID: notificationID}
+		}
+
+		return nil, fmt.Errorf("failed to scan notification: %w", err)
+	}
+
+	return notification, nil
+}
+
+func (repo *NotificationRepository) FindUnread(
+	ctx context.Context,
+	userID string,
+	groupKey string,
+) (*notifications.Notification, error) {
+	q := sq.Select(notificationColumns()...).
+		From(tableNotifications).
+		Where(sq.Eq{
+			notificationFieldUserID:   userID,
+			notificationFieldGroupKey: groupKey,
+			notificationFieldReadAt:   nil,
+		})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	row := q.QueryRowContext(ctx)
+
+	notification, err := scanNotification(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notifications.UnreadNotificationNotFoundError{UserID: userID, GroupKey: groupKey}
+		}
+
+		return nil, fmt.Errorf("failed to scan notification: %w", err)
+	}
+
+	return notification, nil
+}
+
+func (repo *NotificationRepository) List(
+	ctx context.Context,
+	params *notifications.ListNotificationsParams,
+) ([]*notifications.Notification, error) {
+	q := sq.Select(notificationColumns()...).
+		From(tableNotifications).
+		Where(sq.Eq{notificationFieldUserID: params.UserID}).
+		OrderBy(notificationFieldUpdatedAt+" DESC", notificationFieldID+" DESC")
+
+	if params.UnreadOnly {
+		q = q.Where(sq.Eq{notificationFieldReadAt: nil})
+	}
+
+	if !params.UpdatedAfter.IsZero() {
+		q = q.Where(sq.Gt{notificationFieldUpdatedAt: params.UpdatedAfter})
+	}
+
+	if params.Limit > 0 {
+		q = q.Limit(uint64(params.Limit))
+	}
+
+	if params.Offset > 0 {
+		q = q.Offset(uint64(params.Offset))
+	}
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	rows, err := q.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	result := make([]*notifications.Notification, 0)
+
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+
+		result = append(result, notification)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return result, nil
+}
+
+func (repo *NotificationRepository) CountUnread(ctx context.Context, userID string) (int, error) {
+	q := sq.Select("COUNT(*)").
+		From(tableNotifications).
+		Where(sq.Eq{
+			notificationFieldUserID: userID,
+			notificationFieldReadAt: nil,
+		})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	var count int
+
+	err := q.QueryRowContext(ctx).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query: %w", err)
+	}
+
+	return count, nil
+}
+
+func (repo *NotificationRepository) MarkAllRead(ctx context.Context, userID string, readAt time.Time) error {
+	q := sq.Update(tableNotifications).
+		Set(notificationFieldReadAt, readAt).
+		Where(sq.Eq{
+			notificationFieldUserID: userID,
+			notificationFieldReadAt: nil,
+		})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec update: %w", err)
+	}
+
+	return nil
+}
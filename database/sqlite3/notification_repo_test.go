@@ -0,0 +1,212 @@
+package sqlite3_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nasermirzaei89/scribble/authentication"
+	"github.com/nasermirzaei89/scribble/database/sqlite3"
+	"github.com/nasermirzaei89/scribble/notifications"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationRepository(t *testing.T) {
+	ctx, db := newTestDB(t)
+
+	userRepo := sqlite3.NewUserRepository(db)
+	repo := sqlite3.NewNotificationRepository(db)
+
+	user := &authentication.User{
+		ID:           uuid.NewString(),
+		Username:     "notification-user-" + uuid.NewString(),
+		PasswordHash: "password-hash",
+		RegisteredAt: time.Date(2026, 2, 24, 10, 0, 0, 0, time.UTC),
+	}
+
+	err := userRepo.Insert(ctx, user)
+	require.NoError(t, err)
+
+	newNotification := func(groupKey string, updatedAt time.Time) *notifications.Notification {
+		return &notifications.Notification{
+			ID:        uuid.NewString(),
+			UserID:    user.ID,
+			Type:      notifications.TypeReaction,
+			GroupKey:  groupKey,
+			PostID:    "post1",
+			CommentID: "",
+			Emoji:     "👍",
+			ActorIDs:  []string{"actor1"},
+			ReadAt:    nil,
+			CreatedAt: updatedAt,
+			UpdatedAt: updatedAt,
+		}
+	}
+
+	notification1 := newNotification("reaction:post:post1:👍", time.Date(2026, 2, 24, 11, 0, 0, 0, time.UTC))
+	notification2 := newNotification("comment:post1", time.Date(2026, 2, 24, 12, 0, 0, 0, time.UTC))
+
+	for _, notification := range []*notifications.Notification{notification1, notification2} {
+		err := repo.Insert(ctx, notification)
+		require.NoError(t, err)
+	}
+
+	found, err := repo.FindUnread(ctx, user.ID, notification1.GroupKey)
+	require.NoError(t, err)
+	assert.Equal(t, notification1.ID, found.ID)
+	assert.Equal(t, []string{"actor1"}, found.ActorIDs)
+	assert.False(t, found.IsRead())
+
+	_, err = repo.FindUnread(ctx, user.ID, "reply:comment1")
+	require.ErrorAs(t, err, &notifications.UnreadNotificationNotFoundError{})
+
+	found.ActorIDs = []string{"actor2", "actor1"}
+	found.UpdatedAt = time.Date(2026, 2, 24, 13, 0, 0, 0, time.UTC)
+
+	err = repo.Update(ctx, found)
+	require.NoError(t, err)
+
+	list, err := repo.List(ctx, &notifications.ListNotificationsParams{
+		UserID:       user.ID,
+		UnreadOnly:   false,
+		UpdatedAfter: time.Time{},
+		Limit:        10,
+		Offset:       0,
+	})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, notification1.ID, list[0].ID)
+	assert.Equal(t, []string{"actor2", "actor1"}, list[0].ActorIDs)
+	assert.Equal(t, notification2.ID, list[1].ID)
+
+	count, err := repo.CountUnread(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	t.Run("read notifications are not grouped", func(t *testing.T) {
+		readAt := time.Date(2026, 2, 24, 14, 0, 0, 0, time.UTC)
+		found.ReadAt = &readAt
+
+		err := repo.Update(ctx, found)
+		require.NoError(t, err)
+
+		_, err = repo.FindUnread(ctx, user.ID, notification1.GroupKey)
+		require.ErrorAs(t, err, &notifications.UnreadNotificationNotFoundError{})
+
+		err = repo.Insert(ctx, newNotification(notification1.GroupKey, readAt))
+		require.NoError(t, err)
+
+		count, err := repo.CountUnread(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		unread, err := repo.List(ctx, &notifications.ListNotificationsParams{
+			UserID:       user.ID,
+			UnreadOnly:   true,
+			UpdatedAfter: notification2.UpdatedAt,
+			Limit:        0,
+			Offset:       0,
+		})
+		require.NoError(t, err)
+		require.Len(t, unread, 1)
+		assert.Equal(t, notification1.GroupKey, unread[0].GroupKey)
+		assert.False(t, unread[0].IsRead())
+
+		read, err := repo.Find(ctx, notification1.ID)
+		require.NoError(t, err)
+		require.NotNil(t, read.ReadAt)
+		assert.True(t, read.ReadAt.Equal(readAt))
+	})
+
+	err = repo.MarkAllRead(ctx, user.ID, time.Date(2026, 2, 24, 15, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	count, err = repo.CountUnread(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	err = repo.Delete(ctx, notification2.ID)
+	require.NoError(t, err)
+
+	_, err = repo.Find(ctx, notification2.ID)
+	require.ErrorAs(t, err, &notifications.NotificationNotFoundError{})
+}
+
+func TestNotificationMuteRepository(t *testing.T) {
+	ctx, db := newTestDB(t)
+
+	repo := sqlite3.NewNotificationMuteRepository(db)
+	userID := uuid.NewString()
+
+	err := repo.SetMuted(ctx, userID, notifications.TypeReaction, true)
+	require.NoError(t, err)
+
+	err = repo.SetMuted(ctx, userID, notifications.TypeReaction, true)
+	require.NoError(t, err)
+
+	err = repo.SetMuted(ctx, userID, notifications.TypeComment, true)
+	require.NoError(t, err)
+
+	muted, err := repo.ListMuted(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, []notifications.Type{notifications.TypeComment, notifications.TypeReaction}, muted)
+
+	err = repo.SetMuted(ctx, userID, notifications.TypeComment, false)
+	require.NoError(t, err)
+
+	muted, err = repo.ListMuted(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, []notifications.Type{notifications.TypeReaction}, muted)
+
+	muted, err = repo.ListMuted(ctx, uuid.NewString())
+	require.NoError(t, err)
+	assert.Empty(t, muted)
+}
+
+func TestNotificationEmailSettingsRepository(t *testing.T) {
+	ctx, db := newTestDB(t)
+
+	repo := sqlite3.NewNotificationEmailSettingsRepository(db)
+	userID := uuid.NewString()
+
+	_, err := repo.Find(ctx, userID)
+	require.ErrorAs(t, err, &notifications.EmailSettingsNotFoundError{})
+
+	settings := &notifications.EmailSettings{
+		UserID:     userID,
+		Address:    "alice@example.com",
+		Frequency:  notifications.EmailFrequencyDaily,
+		LastSentAt: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+	}
+
+	err = repo.Save(ctx, settings)
+	require.NoError(t, err)
+
+	err = repo.Save(ctx, &notifications.EmailSettings{
+		UserID:     uuid.NewString(),
+		Address:    "",
+		Frequency:  notifications.EmailFrequencyOff,
+		LastSentAt: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	found, err := repo.Find(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", found.Address)
+	assert.Equal(t, notifications.EmailFrequencyDaily, found.Frequency)
+	assert.True(t, settings.LastSentAt.Equal(found.LastSentAt))
+
+	settings.Frequency = notifications.EmailFrequencyWeekly
+	settings.LastSentAt = time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	err = repo.Save(ctx, settings)
+	require.NoError(t, err)
+
+	enabled, err := repo.ListEnabled(ctx)
+	require.NoError(t, err)
+	require.Len(t, enabled, 1)
+	assert.Equal(t, userID, enabled[0].UserID)
+	assert.Equal(t, notifications.EmailFrequencyWeekly, enabled[0].Frequency)
+	assert.True(t, settings.LastSentAt.Equal(enabled[0].LastSentAt))
+}
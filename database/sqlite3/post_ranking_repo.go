@@ -0,0 +1,140 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/reactions"
+)
+
+type PostRankingRepository struct {
+	db *sql.DB
+}
+
+var _ contents.PostRankingRepository = (*PostRankingRepository)(nil)
+
+func NewPostRankingRepository(db *sql.DB) *PostRankingRepository {
+	return &PostRankingRepository{db: db}
+}
+
+func (repo *PostRankingRepository) CountReactions(ctx context.Context, postID string) (map[string]int, error) {
+	q := sq.Select(reactionCountFieldEmoji, reactionCountFieldCount).
+		From(tableReactionCounts).
+		Where(sq.Eq{
+			reactionCountFieldTargetType: reactions.TargetTypePost,
+			reactionCountFieldTargetID:   postID,
+		})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	rows, err := q.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	counts := make(map[string]int)
+
+	for rows.Next() {
+		var emoji string
+
+		var count int
+
+		err := rows.Scan(&emoji, &count)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		counts[emoji] = count
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return counts, nil
+}
+
+func (repo *PostRankingRepository) CountComments(ctx context.Context, postID string) (int, error) {
+	q := sq.Select("COUNT(*)").
+		From(tableComments).
+		Where(sq.Eq{commentFieldPostID: postID})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	var comments int
+
+	err := q.QueryRowContext(ctx).Scan(&comments)
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan count: %w", err)
+	}
+
+	return comments, nil
+}
+
+func (repo *PostRankingRepository) SaveRank(ctx context.Context, postID string, reactionScore int, hotness float64) error {
+	q := sq.Update(tablePosts).
+		Set(postFieldScore, reactionScore).
+		Set(postFieldHotness, hotness).
+		Where(sq.Eq{postFieldID: postID})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec update: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *PostRankingRepository) ListPostIDs(ctx context.Context) ([]string, error) {
+	q := sq.Select(postFieldID).
+		From(tablePosts).
+		OrderBy(postFieldID + " ASC")
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	rows, err := q.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	postIDs := make([]string, 0)
+
+	for rows.Next() {
+		var postID string
+
+		err := rows.Scan(&postID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		postIDs = append(postIDs, postID)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return postIDs, nil
+}
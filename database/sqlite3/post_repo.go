@@ -24,43 +24,70 @@ func NewPostRepository(db *sql.DB) *PostRepository {
 }
 
 const (
-	postFieldID        = "id"
-	postFieldAuthorID  = "author_id"
-	postFieldContent   = "content"
-	postFieldCreatedAt = "created_at"
+	postFieldID          = "id"
+	postFieldAuthorID    = "author_id"
+	postFieldCommunityID = "community_id"
+	postFieldContent     = "content"
+	postFieldCreatedAt   = "created_at"
+	postFieldScore       = "reaction_score"
+	postFieldHotness     = "hotness"
 )
 
 func postColumns() []string {
 	return []string{
 		postFieldID,
 		postFieldAuthorID,
+		postFieldCommunityID,
 		postFieldContent,
 		postFieldCreatedAt,
+		postFieldScore,
+		postFieldHotness,
 	}
 }
 
 func scanPost(row sq.RowScanner) (*contents.Post, error) {
-	var post contents.Post
+	var (
+		post        contents.Post
+		communityID sql.NullString
+	)
 
 	err := row.Scan(
 		&post.ID,
 		&post.AuthorID,
+		&communityID,
 		&post.Content,
 		&post.CreatedAt,
+		&post.ReactionScore,
+		&post.Hotness,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan row: %w", err)
 	}
 
+	post.CommunityID = communityID.String
+
 	return &post, nil
 }
 
+// nullableString stores empty strings as NULL, for optional references.
+func nullableString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
 func (repo *PostRepository) Insert(ctx context.Context, post *contents.Post) error {
 	q := sq.Insert(tablePosts).
 		Columns(postColumns()...).
-		Values(post.ID, post.AuthorID, post.Content, post.CreatedAt)
-
-	q = q.RunWith(repo.db)
+		Values(
+			post.ID,
+			post.AuthorID,
+			nullableString(post.CommunityID),
+			post.Content,
+			post.CreatedAt,
+			post.ReactionScore,
+			post.Hotness,
+		)
+
+	q = q.RunWith(runner(ctx, repo.db))
 
 	_, err := q.ExecContext(ctx)
 	if err != nil {
@@ -75,7 +102,7 @@ func (repo *PostRepository) Find(ctx context.Context, postID string) (*contents.
 		From(tablePosts).
 		Where(sq.Eq{postFieldID: postID})
 
-	q = q.RunWith(repo.db)
+	q = q.RunWith(runner(ctx, repo.db))
 
 	row := q.QueryRowContext(ctx)
 
@@ -91,12 +118,71 @@ func (repo *PostRepository) Find(ctx context.Context, postID string) (*contents.
 	return post, nil
 }
 
-func (repo *PostRepository) List(ctx context.Context) ([]*contents.Post, error) {
+// postsBefore matches the posts older than the cursor, with the ID breaking ties.
+func postsBefore(cursor *contents.PostCursor) sq.Sqlizer {
+	return sq.Or{
+		sq.Lt{postFieldCreatedAt: cursor.CreatedAt},
+		sq.And{
+			sq.Eq{postFieldCreatedAt: cursor.CreatedAt},
+			sq.Lt{postFieldID: cursor.ID},
+		},
+	}
+}
+
+func (repo *PostRepository) List(ctx context.Context, params *contents.ListPostsParams) ([]*contents.Post, error) {
 	q := sq.Select(postColumns()...).
-		From(tablePosts).
-		OrderBy(postFieldCreatedAt + " DESC")
+		From(tablePosts)
+
+	if params.AuthorID != "" {
+		q = q.Where(sq.Eq{postFieldAuthorID: params.AuthorID})
+	}
+
+	if params.CommunityID != "" {
+		q = q.Where(sq.Eq{postFieldCommunityID: params.CommunityID})
+	}
+
+	if !params.Since.IsZero() {
+		q = q.Where(sq.GtOrEq{postFieldCreatedAt: params.Since})
+	}
+
+	switch params.Sort {
+	case contents.PostSortTop:
+		q = q.OrderBy(postFieldScore+" DESC", postFieldCreatedAt+" DESC", postFieldID+" DESC")
+
+		if params.Before != nil {
+			q = q.Where(sq.Or{
+				sq.Lt{postFieldScore: params.Before.Score},
+				sq.And{
+					sq.Eq{postFieldScore: params.Before.Score},
+					postsBefore(params.Before),
+				},
+			})
+		}
+	case contents.PostSortHot:
+		q = q.OrderBy(postFieldHotness+" DESC", postFieldID+" DESC")
+
+		if params.Before != nil {
+			q = q.Where(sq.Or{
+				sq.Lt{postFieldHotness: params.Before.Score},
+				sq.And{
+					sq.Eq{postFieldHotness: params.Before.Score},
+					sq.Lt{postFieldID: params.Before.ID},
+				},
+			})
+		}
+	default:
+		q = q.OrderBy(postFieldCreatedAt+" DESC", postFieldID+" DESC")
+
+		if params.Before != nil {
+			q = q.Where(postsBefore(params.Before))
+		}
+	}
+
+	if params.Limit > 0 {
+		q = q.Limit(uint64(params.Limit))
+	}
 
-	q = q.RunWith(repo.db)
+	q = q.RunWith(runner(ctx, repo.db))
 
 	rows, err := q.QueryContext(ctx)
 	if err != nil {
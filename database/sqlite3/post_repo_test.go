@@ -29,7 +29,7 @@ func TestPostRepository(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("List empty", func(t *testing.T) {
-		posts, err := postRepo.List(ctx)
+		posts, err := postRepo.List(ctx, &contents.ListPostsParams{})
 		require.NoError(t, err)
 		assert.Empty(t, posts)
 	})
@@ -73,7 +73,7 @@ func TestPostRepository(t *testing.T) {
 		assert.Equal(t, post1.Content, found.Content)
 		assert.True(t, found.CreatedAt.Equal(post1.CreatedAt))
 
-		posts, err := postRepo.List(ctx)
+		posts, err := postRepo.List(ctx, &contents.ListPostsParams{})
 		require.NoError(t, err)
 		assert.Len(t, posts, 2)
 
@@ -81,4 +81,157 @@ func TestPostRepository(t *testing.T) {
 		assert.Contains(t, postIDs, post1.ID)
 		assert.Contains(t, postIDs, post2.ID)
 	})
+
+	t.Run("List by community", func(t *testing.T) {
+		communityID := uuid.NewString()
+
+		communityPost := &contents.Post{
+			ID:          uuid.NewString(),
+			AuthorID:    user.ID,
+			CommunityID: communityID,
+			Content:     "community post",
+			CreatedAt:   time.Date(2026, 2, 24, 13, 0, 0, 0, time.UTC),
+		}
+
+		err := postRepo.Insert(ctx, communityPost)
+		require.NoError(t, err)
+
+		found, err := postRepo.Find(ctx, communityPost.ID)
+		require.NoError(t, err)
+		assert.Equal(t, communityID, found.CommunityID)
+
+		posts, err := postRepo.List(ctx, &contents.ListPostsParams{CommunityID: communityID})
+		require.NoError(t, err)
+		require.Len(t, posts, 1)
+		assert.Equal(t, communityPost.ID, posts[0].ID)
+
+		posts, err = postRepo.List(ctx, &contents.ListPostsParams{})
+		require.NoError(t, err)
+		assert.Len(t, posts, 3)
+	})
+
+	t.Run("List by author", func(t *testing.T) {
+		otherUser := &authentication.User{
+			ID:           uuid.NewString(),
+			Username:     "other-" + uuid.NewString(),
+			PasswordHash: "hash",
+			RegisteredAt: time.Now(),
+		}
+
+		err := userRepo.Insert(ctx, otherUser)
+		require.NoError(t, err)
+
+		otherPost := &contents.Post{
+			ID:          uuid.NewString(),
+			AuthorID:    otherUser.ID,
+			CommunityID: "",
+			Content:     "other post",
+			CreatedAt:   time.Date(2026, 2, 24, 14, 0, 0, 0, time.UTC),
+		}
+
+		err = postRepo.Insert(ctx, otherPost)
+		require.NoError(t, err)
+
+		posts, err := postRepo.List(ctx, &contents.ListPostsParams{AuthorID: otherUser.ID})
+		require.NoError(t, err)
+		require.Len(t, posts, 1)
+		assert.Equal(t, otherPost.ID, posts[0].ID)
+	})
+
+	t.Run("List pages with cursor", func(t *testing.T) {
+		communityID := uuid.NewString()
+		createdAt := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+
+		for _, id := range []string{"page-post-a", "page-post-b", "page-post-c"} {
+			err := postRepo.Insert(ctx, &contents.Post{
+				ID:          id,
+				AuthorID:    user.ID,
+				CommunityID: communityID,
+				Content:     id,
+				CreatedAt:   createdAt,
+			})
+			require.NoError(t, err)
+		}
+
+		page, err := postRepo.List(ctx, &contents.ListPostsParams{CommunityID: communityID, Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, page, 2)
+		assert.Equal(t, "page-post-c", page[0].ID)
+		assert.Equal(t, "page-post-b", page[1].ID)
+
+		page, err = postRepo.List(ctx, &contents.ListPostsParams{
+			CommunityID: communityID,
+			Before:      &contents.PostCursor{CreatedAt: page[1].CreatedAt, ID: page[1].ID},
+			Limit:       2,
+		})
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		assert.Equal(t, "page-post-a", page[0].ID)
+	})
+
+	t.Run("List sorted", func(t *testing.T) {
+		communityID := uuid.NewString()
+		createdAt := time.Date(2026, 2, 26, 10, 0, 0, 0, time.UTC)
+
+		for _, post := range []*contents.Post{
+			{ID: "sort-post-a", ReactionScore: 5, Hotness: 1, CreatedAt: createdAt},
+			{ID: "sort-post-b", ReactionScore: 5, Hotness: 3, CreatedAt: createdAt.Add(time.Hour)},
+			{ID: "sort-post-c", ReactionScore: -1, Hotness: 2, CreatedAt: createdAt.Add(2 * time.Hour)},
+			{ID: "sort-post-d", ReactionScore: 9, Hotness: 2, CreatedAt: createdAt.Add(-48 * time.Hour)},
+		} {
+			post.AuthorID = user.ID
+			post.CommunityID = communityID
+			post.Content = post.ID
+
+			err := postRepo.Insert(ctx, post)
+			require.NoError(t, err)
+		}
+
+		listIDs := func(params *contents.ListPostsParams) []string {
+			t.Helper()
+
+			ids := make([]string, 0)
+
+			for {
+				page, err := postRepo.List(ctx, params)
+				require.NoError(t, err)
+
+				if len(page) == 0 {
+					return ids
+				}
+
+				for _, post := range page {
+					ids = append(ids, post.ID)
+				}
+
+				params.Before = page[len(page)-1].Cursor(params.Sort)
+			}
+		}
+
+		assert.Equal(
+			t,
+			[]string{"sort-post-d", "sort-post-b", "sort-post-a", "sort-post-c"},
+			listIDs(&contents.ListPostsParams{CommunityID: communityID, Sort: contents.PostSortTop, Limit: 1}),
+		)
+		assert.Equal(
+			t,
+			[]string{"sort-post-b", "sort-post-a", "sort-post-c"},
+			listIDs(&contents.ListPostsParams{
+				CommunityID: communityID,
+				Sort:        contents.PostSortTop,
+				Since:       createdAt,
+				Limit:       2,
+			}),
+		)
+		assert.Equal(
+			t,
+			[]string{"sort-post-b", "sort-post-d", "sort-post-c", "sort-post-a"},
+			listIDs(&contents.ListPostsParams{CommunityID: communityID, Sort: contents.PostSortHot, Limit: 1}),
+		)
+		assert.Equal(
+			t,
+			[]string{"sort-post-c", "sort-post-b", "sort-post-a", "sort-post-d"},
+			listIDs(&contents.ListPostsParams{CommunityID: communityID, Sort: contents.PostSortNew, Limit: 3}),
+		)
+	})
 }
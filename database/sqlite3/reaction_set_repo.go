@@ -0,0 +1,179 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/nasermirzaei89/scribble/reactions"
+)
+
+const tableReactionSets = "reaction_sets"
+
+type ReactionSetRepository struct {
+	db *sql.DB
+}
+
+var _ reactions.ReactionSetRepository = (*ReactionSetRepository)(nil)
+
+func NewReactionSetRepository(db *sql.DB) *ReactionSetRepository {
+	return &ReactionSetRepository{db: db}
+}
+
+const (
+	reactionSetFieldScope      = "scope"
+	reactionSetFieldScopeID    = "scope_id"
+	reactionSetFieldEmojis     = "emojis"
+	reactionSetFieldMaxPerUser = "max_per_user"
+	reactionSetFieldUpdatedAt  = "updated_at"
+)
+
+func reactionSetColumns() []string {
+	return []string{
+		reactionSetFieldScope,
+		reactionSetFieldScopeID,
+		reactionSetFieldEmojis,
+		reactionSetFieldMaxPerUser,
+		reactionSetFieldUpdatedAt,
+	}
+}
+
+func scanReactionSet(row sq.RowScanner) (*reactions.ReactionSet, error) {
+	var (
+		set    reactions.ReactionSet
+		emojis string
+	)
+
+	err := row.Scan(
+		&set.Scope,
+		&set.ScopeID,
+		&emojis,
+		&set.MaxPerUser,
+		&set.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	err = json.Unmarshal([]byte(emojis), &set.Emojis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal emojis: %w", err)
+	}
+
+	return &set, nil
+}
+
+func (repo *ReactionSetRepository) Find(
+	ctx context.Context,
+	scope reactions.SetScope,
+	scopeID string,
+) (*reactions.ReactionSet, error) {
+	q := sq.Select(reactionSetColumns()...).
+		From(tableReactionSets).
+		Where(sq.Eq{
+			reactionSetFieldScope:   scope,
+			reactionSetFieldScopeID: scopeID,
+		})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	set, err := scanReactionSet(q.QueryRowContext(ctx))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, reactions.ReactionSetNotFoundError{Scope: scope, ScopeID: scopeID}
+		}
+
+		return nil, fmt.Errorf("failed to scan reaction set: %w", err)
+	}
+
+	return set, nil
+}
+
+func (repo *ReactionSetRepository) List(ctx context.Context) ([]*reactions.ReactionSet, error) {
+	q := sq.Select(reactionSetColumns()...).
+		From(tableReactionSets).
+		OrderBy(reactionSetFieldScope+" ASC", reactionSetFieldScopeID+" ASC")
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	rows, err := q.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	result := make([]*reactions.ReactionSet, 0)
+
+	for rows.Next() {
+		set, err := scanReactionSet(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reaction set: %w", err)
+		}
+
+		result = append(result, set)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return result, nil
+}
+
+func (repo *ReactionSetRepository) Save(ctx context.Context, set *reactions.ReactionSet) error {
+	emojis, err := json.Marshal(set.Emojis)
+	if err != nil {
+		return fmt.Errorf("failed to marshal emojis: %w", err)
+	}
+
+	q := sq.Insert(tableReactionSets).
+		Columns(reactionSetColumns()...).
+		Values(
+			set.Scope,
+			set.ScopeID,
+			string(emojis),
+			set.MaxPerUser,
+			set.UpdatedAt,
+		).
+		Suffix(`ON CONFLICT (` + reactionSetFieldScope + `, ` + reactionSetFieldScopeID + `) DO UPDATE SET ` +
+			reactionSetFieldEmojis + ` = excluded.` + reactionSetFieldEmojis + `, ` +
+			reactionSetFieldMaxPerUser + ` = excluded.` + reactionSetFieldMaxPerUser + `, ` +
+			reactionSetFieldUpdatedAt + ` = excluded.` + reactionSetFieldUpdatedAt)
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err = q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec insert: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *ReactionSetRepository) Delete(ctx context.Context, scope reactions.SetScope, scopeID string) error {
+	q := sq.Delete(tableReactionSets).
+		Where(sq.Eq{
+			reactionSetFieldScope:   scope,
+			reactionSetFieldScopeID: scopeID,
+		})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec delete: %w", err)
+	}
+
+	return nil
+}
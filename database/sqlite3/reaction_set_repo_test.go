@@ -0,0 +1,68 @@
+package sqlite3_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/database/sqlite3"
+	"github.com/nasermirzaei89/scribble/reactions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReactionSetRepository(t *testing.T) {
+	ctx, db := newTestDB(t)
+
+	repo := sqlite3.NewReactionSetRepository(db)
+
+	_, err := repo.Find(ctx, reactions.SetScopeSite, "")
+	require.ErrorAs(t, err, &reactions.ReactionSetNotFoundError{})
+
+	site := &reactions.ReactionSet{
+		Scope:      reactions.SetScopeSite,
+		ScopeID:    "",
+		Emojis:     []string{"👍", "❤️"},
+		MaxPerUser: 1,
+		UpdatedAt:  time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+	}
+
+	err = repo.Save(ctx, site)
+	require.NoError(t, err)
+
+	err = repo.Save(ctx, &reactions.ReactionSet{
+		Scope:      reactions.SetScopePost,
+		ScopeID:    "post1",
+		Emojis:     []string{"🎉"},
+		MaxPerUser: 1,
+		UpdatedAt:  time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	found, err := repo.Find(ctx, reactions.SetScopeSite, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"👍", "❤️"}, found.Emojis)
+	assert.Equal(t, 1, found.MaxPerUser)
+
+	site.Emojis = []string{"❤️"}
+	site.MaxPerUser = 2
+	site.UpdatedAt = time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	err = repo.Save(ctx, site)
+	require.NoError(t, err)
+
+	sets, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, sets, 2)
+	assert.Equal(t, reactions.SetScopePost, sets[0].Scope)
+	assert.Equal(t, "post1", sets[0].ScopeID)
+	assert.Equal(t, reactions.SetScopeSite, sets[1].Scope)
+	assert.Equal(t, []string{"❤️"}, sets[1].Emojis)
+	assert.Equal(t, 2, sets[1].MaxPerUser)
+	assert.True(t, site.UpdatedAt.Equal(sets[1].UpdatedAt))
+
+	err = repo.Delete(ctx, reactions.SetScopePost, "post1")
+	require.NoError(t, err)
+
+	_, err = repo.Find(ctx, reactions.SetScopePost, "post1")
+	require.ErrorAs(t, err, &reactions.ReactionSetNotFoundError{})
+}
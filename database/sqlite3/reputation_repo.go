@@ -0,0 +1,232 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/nasermirzaei89/scribble/reactions"
+	"github.com/nasermirzaei89/scribble/reputation"
+)
+
+const tableReputations = "reputations"
+
+type ReputationRepository struct {
+	db *sql.DB
+}
+
+var _ reputation.ReputationRepository = (*ReputationRepository)(nil)
+
+func NewReputationRepository(db *sql.DB) *ReputationRepository {
+	return &ReputationRepository{db: db}
+}
+
+const (
+	reputationFieldUserID         = "user_id"
+	reputationFieldReactionPoints = "reaction_points"
+	reputationFieldPosts          = "posts"
+	reputationFieldComments       = "comments"
+	reputationFieldScore          = "score"
+	reputationFieldTrusted        = "trusted"
+	reputationFieldUpdatedAt      = "updated_at"
+)
+
+func reputationColumns() []string {
+	return []string{
+		reputationFieldUserID,
+		reputationFieldReactionPoints,
+		reputationFieldPosts,
+		reputationFieldComments,
+		reputationFieldScore,
+		reputationFieldTrusted,
+		reputationFieldUpdatedAt,
+	}
+}
+
+func scanReputation(row sq.RowScanner) (*reputation.Reputation, error) {
+	var rep reputation.Reputation
+
+	err := row.Scan(
+		&rep.UserID,
+		&rep.ReactionPoints,
+		&rep.Posts,
+		&rep.Comments,
+		&rep.Score,
+		&rep.Trusted,
+		&rep.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	return &rep, nil
+}
+
+func (repo *ReputationRepository) Find(ctx context.Context, userID string) (*reputation.Reputation, error) {
+	q := sq.Select(reputationColumns()...).
+		From(tableReputations).
+		Where(sq.Eq{reputationFieldUserID: userID})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	rep, err := scanReputation(q.QueryRowContext(ctx))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, reputation.ReputationNotFoundError{UserID: userID}
+		}
+
+		return nil, fmt.Errorf("failed to scan reputation: %w", err)
+	}
+
+	return rep, nil
+}
+
+func (repo *ReputationRepository) Save(ctx context.Context, rep *reputation.Reputation) error {
+	q := sq.Insert(tableReputations).
+		Columns(reputationColumns()...).
+		Values(
+			rep.UserID,
+			rep.ReactionPoints,
+			rep.Posts,
+			rep.Comments,
+			rep.Score,
+			rep.Trusted,
+			rep.UpdatedAt,
+		).
+		Suffix(`ON CONFLICT (` + reputationFieldUserID + `) DO UPDATE SET ` +
+			reputationFieldReactionPoints + ` = excluded.` + reputationFieldReactionPoints + `, ` +
+			reputationFieldPosts + ` = excluded.` + reputationFieldPosts + `, ` +
+			reputationFieldComments + ` = excluded.` + reputationFieldComments + `, ` +
+			reputationFieldScore + ` = excluded.` + reputationFieldScore + `, ` +
+			reputationFieldTrusted + ` = excluded.` + reputationFieldTrusted + `, ` +
+			reputationFieldUpdatedAt + ` = excluded.` + reputationFieldUpdatedAt)
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec insert: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *ReputationRepository) CountReactionsReceived(ctx context.Context, userID string) (map[string]int, error) {
+	postIDs := sq.Select(postFieldID).From(tablePosts).Where(sq.Eq{postFieldAuthorID: userID})
+	commentIDs := sq.Select(commentFieldID).From(tableComments).Where(sq.Eq{commentFieldAuthorID: userID})
+
+	q := sq.Select(userReactionFieldEmoji, "COUNT(*)").
+		From(tableReactions).
+		Where(sq.NotEq{userReactionFieldUserID: userID}).
+		Where(sq.Or{
+			sq.And{
+				sq.Eq{userReactionFieldTargetType: reactions.TargetTypePost},
+				sq.Expr(userReactionFieldTargetID+" IN (?)", postIDs),
+			},
+			sq.And{
+				sq.Eq{userReactionFieldTargetType: reactions.TargetTypeComment},
+				sq.Expr(userReactionFieldTargetID+" IN (?)", commentIDs),
+			},
+			sq.Eq{
+				userReactionFieldTargetType: reactions.TargetTypeUser,
+				userReactionFieldTargetID:   userID,
+			},
+		}).
+		GroupBy(userReactionFieldEmoji)
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	rows, err := q.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	counts := make(map[string]int)
+
+	for rows.Next() {
+		var emoji string
+
+		var count int
+
+		err := rows.Scan(&emoji, &count)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		counts[emoji] = count
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return counts, nil
+}
+
+func (repo *ReputationRepository) CountActivity(ctx context.Context, userID string) (int, int, error) {
+	q := sq.Select().
+		Column(sq.Alias(sq.Select("COUNT(*)").From(tablePosts).Where(sq.Eq{postFieldAuthorID: userID}), "posts")).
+		Column(sq.Alias(sq.Select("COUNT(*)").From(tableComments).Where(sq.Eq{commentFieldAuthorID: userID}), "comments"))
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	var posts, comments int
+
+	err := q.QueryRowContext(ctx).Scan(&posts, &comments)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to scan counts: %w", err)
+	}
+
+	return posts, comments, nil
+}
+
+func (repo *ReputationRepository) ListUserIDs(ctx context.Context) ([]string, error) {
+	q := sq.Select(userFieldID).
+		From(tableUsers).
+		OrderBy(userFieldID + " ASC")
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	rows, err := q.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	userIDs := make([]string, 0)
+
+	for rows.Next() {
+		var userID string
+
+		err := rows.Scan(&userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		userIDs = append(userIDs, userID)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return userIDs, nil
+}
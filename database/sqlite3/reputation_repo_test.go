@@ -0,0 +1,111 @@
+package sqlite3_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/authentication"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/database/sqlite3"
+	"github.com/nasermirzaei89/scribble/discuss"
+	"github.com/nasermirzaei89/scribble/reactions"
+	"github.com/nasermirzaei89/scribble/reputation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReputationRepository(t *testing.T) {
+	ctx, db := newTestDB(t)
+
+	repo := sqlite3.NewReputationRepository(db)
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	for _, userID := range []string{"bob", "alice"} {
+		err := sqlite3.NewUserRepository(db).Insert(ctx, &authentication.User{
+			ID:           userID,
+			Username:     userID,
+			PasswordHash: "hash",
+			RegisteredAt: now,
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("save and find", func(t *testing.T) {
+		_, err := repo.Find(ctx, "alice")
+		require.ErrorAs(t, err, &reputation.ReputationNotFoundError{})
+
+		rep := &reputation.Reputation{
+			UserID:         "alice",
+			ReactionPoints: 3,
+			Posts:          1,
+			Comments:       2,
+			Score:          7,
+			Trusted:        false,
+			UpdatedAt:      now,
+		}
+
+		err = repo.Save(ctx, rep)
+		require.NoError(t, err)
+
+		rep.Score = 60
+		rep.Trusted = true
+
+		err = repo.Save(ctx, rep)
+		require.NoError(t, err)
+
+		found, err := repo.Find(ctx, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, 3, found.ReactionPoints)
+		assert.Equal(t, 60, found.Score)
+		assert.True(t, found.Trusted)
+	})
+
+	t.Run("counts", func(t *testing.T) {
+		for _, post := range []*contents.Post{
+			{ID: "post1", AuthorID: "alice", CommunityID: "", Content: "post", CreatedAt: now},
+			{ID: "post2", AuthorID: "bob", CommunityID: "", Content: "post", CreatedAt: now},
+		} {
+			err := sqlite3.NewPostRepository(db).Insert(ctx, post)
+			require.NoError(t, err)
+		}
+
+		err := sqlite3.NewCommentRepository(db).Insert(ctx, &discuss.Comment{
+			ID:        "comment1",
+			PostID:    "post2",
+			AuthorID:  "alice",
+			ReplyTo:   nil,
+			Content:   "comment",
+			CreatedAt: now,
+		})
+		require.NoError(t, err)
+
+		reactionRepo := sqlite3.NewUserReactionRepository(db)
+
+		for _, reaction := range []*reactions.UserReaction{
+			{TargetType: reactions.TargetTypePost, TargetID: "post1", UserID: "bob", Emoji: "👍", CreatedAt: now},
+			{TargetType: reactions.TargetTypePost, TargetID: "post1", UserID: "carol", Emoji: "👎", CreatedAt: now},
+			{TargetType: reactions.TargetTypeComment, TargetID: "comment1", UserID: "bob", Emoji: "👍", CreatedAt: now},
+			{TargetType: reactions.TargetTypeUser, TargetID: "alice", UserID: "bob", Emoji: "😂", CreatedAt: now},
+			// Reactions to their own things and to the things of others do not count.
+			{TargetType: reactions.TargetTypePost, TargetID: "post1", UserID: "alice", Emoji: "👍", CreatedAt: now},
+			{TargetType: reactions.TargetTypePost, TargetID: "post2", UserID: "carol", Emoji: "👍", CreatedAt: now},
+			{TargetType: reactions.TargetTypeUser, TargetID: "bob", UserID: "carol", Emoji: "👍", CreatedAt: now},
+		} {
+			err := reactionRepo.Insert(ctx, reaction)
+			require.NoError(t, err)
+		}
+
+		counts, err := repo.CountReactionsReceived(ctx, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"👍": 2, "👎": 1, "😂": 1}, counts)
+
+		posts, comments, err := repo.CountActivity(ctx, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, 1, posts)
+		assert.Equal(t, 1, comments)
+
+		userIDs, err := repo.ListUserIDs(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"alice", "bob"}, userIDs)
+	})
+}
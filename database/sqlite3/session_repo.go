@@ -59,7 +59,7 @@ func (repo *SessionRepository) Insert(ctx context.Context, session *authenticati
 		Columns(sessionColumns()...).
 		Values(session.ID, session.UserID, session.CreatedAt, session.ExpiresAt)
 
-	q = q.RunWith(repo.db)
+	q = q.RunWith(runner(ctx, repo.db))
 
 	_, err := q.ExecContext(ctx)
 	if err != nil {
@@ -74,7 +74,7 @@ func (repo *SessionRepository) Find(ctx context.Context, id string) (*authentica
 		From(tableSessions).
 		Where(sq.Eq{sessionFieldID: id})
 
-	q = q.RunWith(repo.db)
+	q = q.RunWith(runner(ctx, repo.db))
 
 	row := q.QueryRowContext(ctx)
 
@@ -94,7 +94,7 @@ func (repo *SessionRepository) Delete(ctx context.Context, id string) error {
 	q := sq.Delete(tableSessions).
 		Where(sq.Eq{sessionFieldID: id})
 
-	q = q.RunWith(repo.db)
+	q = q.RunWith(runner(ctx, repo.db))
 
 	result, err := q.ExecContext(ctx)
 	if err != nil {
@@ -0,0 +1,68 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/nasermirzaei89/scribble/events"
+)
+
+type transactionContextKey struct{}
+
+// runner returns the transaction of the context if there is one, so repositories take part in it, and the database
+// otherwise.
+func runner(ctx context.Context, db *sql.DB) sq.StdSqlCtx { //nolint:ireturn
+	tx, ok := ctx.Value(transactionContextKey{}).(*sql.Tx)
+	if ok {
+		return tx
+	}
+
+	return db
+}
+
+// Transactor runs functions in transactions the repositories of this package take part in.
+type Transactor struct {
+	db *sql.DB
+}
+
+var _ events.Transactor = (*Transactor)(nil)
+
+func NewTransactor(db *sql.DB) *Transactor {
+	return &Transactor{db: db}
+}
+
+func (transactor *Transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return withinTransaction(ctx, transactor.db, fn)
+}
+
+// withinTransaction runs the function in the transaction of the context, or in a new one if there is none.
+func withinTransaction(ctx context.Context, db *sql.DB, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(transactionContextKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	err = fn(context.WithValue(ctx, transactionContextKey{}, tx))
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			slog.ErrorContext(ctx, "failed to rollback transaction", "error", rollbackErr)
+		}
+
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -3,7 +3,6 @@ package sqlite3
 import (
 	"context"
 	"database/sql"
-	"errors"
 	"fmt"
 	"log/slog"
 
@@ -11,7 +10,10 @@ import (
 	"github.com/nasermirzaei89/scribble/reactions"
 )
 
-const tableReactions = "reactions"
+const (
+	tableReactions      = "reactions"
+	tableReactionCounts = "reaction_counts"
+)
 
 type UserReactionRepository struct {
 	db *sql.DB
@@ -31,6 +33,13 @@ const (
 	userReactionFieldCreatedAt  = "created_at"
 )
 
+const (
+	reactionCountFieldTargetType = "target_type"
+	reactionCountFieldTargetID   = "target_id"
+	reactionCountFieldEmoji      = "emoji"
+	reactionCountFieldCount      = "count"
+)
+
 func reactionColumns() []string {
 	return []string{
 		userReactionFieldTargetType,
@@ -58,99 +67,247 @@ func scanUserReaction(row sq.RowScanner) (*reactions.UserReaction, error) {
 	return &reaction, nil
 }
 
-func (repo *UserReactionRepository) FindByUserTarget(
+func (repo *UserReactionRepository) ListByUserTarget(
 	ctx context.Context,
 	targetType reactions.TargetType,
 	targetID string,
 	userID string,
-) (*reactions.UserReaction, error) {
+) ([]*reactions.UserReaction, error) {
 	q := sq.Select(reactionColumns()...).
 		From(tableReactions).
 		Where(sq.Eq{
 			userReactionFieldTargetType: targetType,
 			userReactionFieldTargetID:   targetID,
 			userReactionFieldUserID:     userID,
-		})
+		}).
+		OrderBy(userReactionFieldCreatedAt+" ASC", userReactionFieldEmoji+" ASC")
 
-	q = q.RunWith(repo.db)
+	q = q.RunWith(runner(ctx, repo.db))
 
-	reaction, err := scanUserReaction(q.QueryRowContext(ctx))
+	rows, err := q.QueryContext(ctx)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, &reactions.UserReactionNotFoundError{
-				TargetType: targetType,
-				TargetID:   targetID,
-				UserID:     userID,
-			}
+		return nil, fmt.Errorf("failed to query reactions: %w", err)
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close reaction rows", "error", err)
+		}
+	}()
+
+	result := make([]*reactions.UserReaction, 0)
+
+	for rows.Next() {
+		reaction, err := scanUserReaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reaction: %w", err)
 		}
 
-		return nil, fmt.Errorf("failed to find reaction by user target: %w", err)
+		result = append(result, reaction)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate reaction rows: %w", err)
 	}
 
-	return reaction, nil
+	return result, nil
 }
 
-func (repo *UserReactionRepository) Upsert(ctx context.Context, reaction *reactions.UserReaction) error {
-	query := fmt.Sprintf(`
-INSERT INTO %s (target_type, target_id, user_id, emoji, created_at)
-VALUES (?, ?, ?, ?, ?)
-ON CONFLICT(target_type, target_id, user_id)
-DO UPDATE SET
-    emoji = excluded.emoji,
-    created_at = excluded.created_at
-`, tableReactions)
-
-	_, err := repo.db.ExecContext(
-		ctx,
-		query,
-		reaction.TargetType,
-		reaction.TargetID,
-		reaction.UserID,
-		reaction.Emoji,
-		reaction.CreatedAt,
-	)
+// Insert adds the reaction and counts it, in one transaction.
+func (repo *UserReactionRepository) Insert(ctx context.Context, reaction *reactions.UserReaction) error {
+	return withinTransaction(ctx, repo.db, func(ctx context.Context) error {
+		q := sq.Insert(tableReactions).
+			Columns(reactionColumns()...).
+			Values(
+				reaction.TargetType,
+				reaction.TargetID,
+				reaction.UserID,
+				reaction.Emoji,
+				reaction.CreatedAt,
+			).
+			RunWith(runner(ctx, repo.db))
+
+		_, err := q.ExecContext(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to insert reaction: %w", err)
+		}
+
+		err = repo.incrementCount(ctx, reaction.TargetType, reaction.TargetID, reaction.Emoji)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// Delete removes the reaction and uncounts it, in one transaction.
+func (repo *UserReactionRepository) Delete(
+	ctx context.Context,
+	targetType reactions.TargetType,
+	targetID string,
+	userID string,
+	emoji string,
+) error {
+	return withinTransaction(ctx, repo.db, func(ctx context.Context) error {
+		q := sq.Delete(tableReactions).
+			Where(sq.Eq{
+				userReactionFieldTargetType: targetType,
+				userReactionFieldTargetID:   targetID,
+				userReactionFieldUserID:     userID,
+				userReactionFieldEmoji:      emoji,
+			}).
+			RunWith(runner(ctx, repo.db))
+
+		res, err := q.ExecContext(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to delete reaction: %w", err)
+		}
+
+		deleted, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+
+		if deleted == 0 {
+			return nil
+		}
+
+		err = repo.decrementCounts(ctx, targetType, targetID, []string{emoji})
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// DeleteByUserTarget removes the reactions of the user on the target and uncounts them, in one transaction.
+func (repo *UserReactionRepository) DeleteByUserTarget(
+	ctx context.Context,
+	targetType reactions.TargetType,
+	targetID string,
+	userID string,
+) error {
+	return withinTransaction(ctx, repo.db, func(ctx context.Context) error {
+		existing, err := repo.ListByUserTarget(ctx, targetType, targetID, userID)
+		if err != nil {
+			return fmt.Errorf("failed to list reactions: %w", err)
+		}
+
+		if len(existing) == 0 {
+			return nil
+		}
+
+		q := sq.Delete(tableReactions).
+			Where(sq.Eq{
+				userReactionFieldTargetType: targetType,
+				userReactionFieldTargetID:   targetID,
+				userReactionFieldUserID:     userID,
+			}).
+			RunWith(runner(ctx, repo.db))
+
+		_, err = q.ExecContext(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to delete reactions: %w", err)
+		}
+
+		emojis := make([]string, 0, len(existing))
+
+		for _, reaction := range existing {
+			emojis = append(emojis, reaction.Emoji)
+		}
+
+		err = repo.decrementCounts(ctx, targetType, targetID, emojis)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (repo *UserReactionRepository) incrementCount(
+	ctx context.Context,
+	targetType reactions.TargetType,
+	targetID string,
+	emoji string,
+) error {
+	q := sq.Insert(tableReactionCounts).
+		Columns(
+			reactionCountFieldTargetType,
+			reactionCountFieldTargetID,
+			reactionCountFieldEmoji,
+			reactionCountFieldCount,
+		).
+		Values(targetType, targetID, emoji, 1).
+		Suffix(
+			"ON CONFLICT (" + reactionCountFieldTargetType + ", " + reactionCountFieldTargetID + ", " +
+				reactionCountFieldEmoji + ") DO UPDATE SET " + reactionCountFieldCount + " = " +
+				reactionCountFieldCount + " + 1",
+		).
+		RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to upsert reaction: %w", err)
+		return fmt.Errorf("failed to increment reaction count: %w", err)
 	}
 
 	return nil
 }
 
-func (repo *UserReactionRepository) DeleteByUserTarget(
+// decrementCounts uncounts a reaction with each of the emojis, and drops the counts that reach zero.
+func (repo *UserReactionRepository) decrementCounts(
 	ctx context.Context,
 	targetType reactions.TargetType,
 	targetID string,
-	userID string,
+	emojis []string,
 ) error {
-	q := sq.Delete(tableReactions).
+	update := sq.Update(tableReactionCounts).
+		Set(reactionCountFieldCount, sq.Expr(reactionCountFieldCount+" - 1")).
 		Where(sq.Eq{
-			userReactionFieldTargetType: targetType,
-			userReactionFieldTargetID:   targetID,
-			userReactionFieldUserID:     userID,
+			reactionCountFieldTargetType: targetType,
+			reactionCountFieldTargetID:   targetID,
+			reactionCountFieldEmoji:      emojis,
 		}).
-		RunWith(repo.db)
+		RunWith(runner(ctx, repo.db))
 
-	_, err := q.ExecContext(ctx)
+	_, err := update.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to decrement reaction counts: %w", err)
+	}
+
+	del := sq.Delete(tableReactionCounts).
+		Where(sq.Eq{
+			reactionCountFieldTargetType: targetType,
+			reactionCountFieldTargetID:   targetID,
+		}).
+		Where(sq.LtOrEq{reactionCountFieldCount: 0}).
+		RunWith(runner(ctx, repo.db))
+
+	_, err = del.ExecContext(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to delete reaction: %w", err)
+		return fmt.Errorf("failed to delete empty reaction counts: %w", err)
 	}
 
 	return nil
 }
 
+// CountByTarget returns the counts of the reactions to the target by emoji, from the maintained counts.
 func (repo *UserReactionRepository) CountByTarget(
 	ctx context.Context,
 	targetType reactions.TargetType,
 	targetID string,
 ) (map[string]int, error) {
-	q := sq.Select(userReactionFieldEmoji, "COUNT(*)").
-		From(tableReactions).
+	q := sq.Select(reactionCountFieldEmoji, reactionCountFieldCount).
+		From(tableReactionCounts).
 		Where(sq.Eq{
-			userReactionFieldTargetType: targetType,
-			userReactionFieldTargetID:   targetID,
+			reactionCountFieldTargetType: targetType,
+			reactionCountFieldTargetID:   targetID,
 		}).
-		GroupBy(userReactionFieldEmoji).
-		RunWith(repo.db)
+		RunWith(runner(ctx, repo.db))
 
 	rows, err := q.QueryContext(ctx)
 	if err != nil {
@@ -160,7 +317,7 @@ func (repo *UserReactionRepository) CountByTarget(
 	defer func() {
 		err := rows.Close()
 		if err != nil {
-			slog.ErrorContext(ctx, "failed to close reaction rows", "error", err)
+			slog.ErrorContext(ctx, "failed to close reaction count rows", "error", err)
 		}
 	}()
 
@@ -186,3 +343,187 @@ func (repo *UserReactionRepository) CountByTarget(
 
 	return counts, nil
 }
+
+// reactionCountKey identifies the count of the reactions to a target with an emoji.
+type reactionCountKey struct {
+	targetType string
+	targetID   string
+	emoji      string
+}
+
+// RepairCounts recomputes the reaction counts from the reactions, for when they drifted apart, like after reactions
+// were removed by hand. It returns how many counts were wrong.
+func (repo *UserReactionRepository) RepairCounts(ctx context.Context) (int, error) {
+	wrong := 0
+
+	err := withinTransaction(ctx, repo.db, func(ctx context.Context) error {
+		recount := sq.Select(
+			userReactionFieldTargetType,
+			userReactionFieldTargetID,
+			userReactionFieldEmoji,
+			"COUNT(*)",
+		).
+			From(tableReactions).
+			GroupBy(userReactionFieldTargetType, userReactionFieldTargetID, userReactionFieldEmoji)
+
+		expected, err := repo.queryCounts(ctx, recount)
+		if err != nil {
+			return err
+		}
+
+		actual, err := repo.queryCounts(ctx, sq.Select(
+			reactionCountFieldTargetType,
+			reactionCountFieldTargetID,
+			reactionCountFieldEmoji,
+			reactionCountFieldCount,
+		).From(tableReactionCounts))
+		if err != nil {
+			return err
+		}
+
+		for key, count := range expected {
+			if actual[key] != count {
+				wrong++
+			}
+		}
+
+		for key := range actual {
+			if _, ok := expected[key]; !ok {
+				wrong++
+			}
+		}
+
+		if wrong == 0 {
+			return nil
+		}
+
+		_, err = sq.Delete(tableReactionCounts).RunWith(runner(ctx, repo.db)).ExecContext(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to delete reaction counts: %w", err)
+		}
+
+		insert := sq.Insert(tableReactionCounts).
+			Columns(
+				reactionCountFieldTargetType,
+				reactionCountFieldTargetID,
+				reactionCountFieldEmoji,
+				reactionCountFieldCount,
+			).
+			Select(recount).
+			RunWith(runner(ctx, repo.db))
+
+		_, err = insert.ExecContext(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to insert reaction counts: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return wrong, nil
+}
+
+func (repo *UserReactionRepository) queryCounts(
+	ctx context.Context,
+	q sq.SelectBuilder,
+) (map[reactionCountKey]int, error) {
+	rows, err := q.RunWith(runner(ctx, repo.db)).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reaction counts: %w", err)
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close reaction count rows", "error", err)
+		}
+	}()
+
+	counts := make(map[reactionCountKey]int)
+
+	for rows.Next() {
+		var key reactionCountKey
+
+		var count int
+
+		err := rows.Scan(&key.targetType, &key.targetID, &key.emoji, &count)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reaction count row: %w", err)
+		}
+
+		counts[key] = count
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate reaction count rows: %w", err)
+	}
+
+	return counts, nil
+}
+
+func (repo *UserReactionRepository) ListByTarget(
+	ctx context.Context,
+	params *reactions.ListReactorsParams,
+) ([]*reactions.UserReaction, error) {
+	q := sq.Select(reactionColumns()...).
+		From(tableReactions).
+		Where(sq.Eq{
+			userReactionFieldTargetType: params.TargetType,
+			userReactionFieldTargetID:   params.TargetID,
+		}).
+		OrderBy(userReactionFieldCreatedAt+" DESC", userReactionFieldUserID+" DESC")
+
+	if params.Emoji != "" {
+		q = q.Where(sq.Eq{userReactionFieldEmoji: params.Emoji})
+	}
+
+	if params.Before != nil {
+		q = q.Where(sq.Or{
+			sq.Lt{userReactionFieldCreatedAt: params.Before.CreatedAt},
+			sq.And{
+				sq.Eq{userReactionFieldCreatedAt: params.Before.CreatedAt},
+				sq.Lt{userReactionFieldUserID: params.Before.UserID},
+			},
+		})
+	}
+
+	if params.Limit > 0 {
+		q = q.Limit(uint64(params.Limit))
+	}
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	rows, err := q.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reactions: %w", err)
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close reaction rows", "error", err)
+		}
+	}()
+
+	result := make([]*reactions.UserReaction, 0)
+
+	for rows.Next() {
+		reaction, err := scanUserReaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reaction: %w", err)
+		}
+
+		result = append(result, reaction)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate reaction rows: %w", err)
+	}
+
+	return result, nil
+}
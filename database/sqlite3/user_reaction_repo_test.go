@@ -40,18 +40,13 @@ func TestUserReactionRepository(t *testing.T) {
 
 	targetID := uuid.NewString()
 
-	t.Run("FindByUserTarget not found", func(t *testing.T) {
-		_, err := repo.FindByUserTarget(ctx, reactions.TargetTypePost, targetID, user1.ID)
-
-		var userReactionNotFoundErr *reactions.UserReactionNotFoundError
-
-		require.ErrorAs(t, err, &userReactionNotFoundErr)
-		assert.Equal(t, reactions.TargetTypePost, userReactionNotFoundErr.TargetType)
-		assert.Equal(t, targetID, userReactionNotFoundErr.TargetID)
-		assert.Equal(t, user1.ID, userReactionNotFoundErr.UserID)
+	t.Run("ListByUserTarget empty", func(t *testing.T) {
+		found, err := repo.ListByUserTarget(ctx, reactions.TargetTypePost, targetID, user1.ID)
+		require.NoError(t, err)
+		assert.Empty(t, found)
 	})
 
-	t.Run("Upsert and find", func(t *testing.T) {
+	t.Run("Insert and list", func(t *testing.T) {
 		reaction := &reactions.UserReaction{
 			TargetType: reactions.TargetTypePost,
 			TargetID:   targetID,
@@ -60,38 +55,41 @@ func TestUserReactionRepository(t *testing.T) {
 			CreatedAt:  time.Date(2026, 2, 24, 11, 0, 0, 0, time.UTC),
 		}
 
-		err := repo.Upsert(ctx, reaction)
+		err := repo.Insert(ctx, reaction)
 		require.NoError(t, err)
 
-		found, err := repo.FindByUserTarget(ctx, reaction.TargetType, reaction.TargetID, reaction.UserID)
+		found, err := repo.ListByUserTarget(ctx, reaction.TargetType, reaction.TargetID, reaction.UserID)
 		require.NoError(t, err)
-		assert.Equal(t, reaction.TargetType, found.TargetType)
-		assert.Equal(t, reaction.TargetID, found.TargetID)
-		assert.Equal(t, reaction.UserID, found.UserID)
-		assert.Equal(t, reaction.Emoji, found.Emoji)
-		assert.True(t, found.CreatedAt.Equal(reaction.CreatedAt))
+		require.Len(t, found, 1)
+		assert.Equal(t, reaction.TargetType, found[0].TargetType)
+		assert.Equal(t, reaction.TargetID, found[0].TargetID)
+		assert.Equal(t, reaction.UserID, found[0].UserID)
+		assert.Equal(t, reaction.Emoji, found[0].Emoji)
+		assert.True(t, found[0].CreatedAt.Equal(reaction.CreatedAt))
+
+		err = repo.Insert(ctx, reaction)
+		require.Error(t, err)
 	})
 
-	t.Run("Upsert updates existing reaction", func(t *testing.T) {
-		reaction := &reactions.UserReaction{
+	t.Run("Insert another emoji", func(t *testing.T) {
+		err := repo.Insert(ctx, &reactions.UserReaction{
 			TargetType: reactions.TargetTypePost,
 			TargetID:   targetID,
 			UserID:     user1.ID,
 			Emoji:      "❤️",
 			CreatedAt:  time.Date(2026, 2, 24, 12, 0, 0, 0, time.UTC),
-		}
-
-		err := repo.Upsert(ctx, reaction)
+		})
 		require.NoError(t, err)
 
-		found, err := repo.FindByUserTarget(ctx, reaction.TargetType, reaction.TargetID, reaction.UserID)
+		found, err := repo.ListByUserTarget(ctx, reactions.TargetTypePost, targetID, user1.ID)
 		require.NoError(t, err)
-		assert.Equal(t, "❤️", found.Emoji)
-		assert.True(t, found.CreatedAt.Equal(reaction.CreatedAt))
+		require.Len(t, found, 2)
+		assert.Equal(t, "🔥", found[0].Emoji)
+		assert.Equal(t, "❤️", found[1].Emoji)
 	})
 
 	t.Run("CountByTarget groups by emoji", func(t *testing.T) {
-		err := repo.Upsert(ctx, &reactions.UserReaction{
+		err := repo.Insert(ctx, &reactions.UserReaction{
 			TargetType: reactions.TargetTypePost,
 			TargetID:   targetID,
 			UserID:     user2.ID,
@@ -102,11 +100,11 @@ func TestUserReactionRepository(t *testing.T) {
 
 		otherTargetID := uuid.NewString()
 
-		err = repo.Upsert(ctx, &reactions.UserReaction{
+		err = repo.Insert(ctx, &reactions.UserReaction{
 			TargetType: reactions.TargetTypePost,
 			TargetID:   otherTargetID,
 			UserID:     user1.ID,
-			Emoji:      "🔥",
+			Emoji:      "👍",
 			CreatedAt:  time.Date(2026, 2, 24, 13, 1, 0, 0, time.UTC),
 		})
 		require.NoError(t, err)
@@ -114,20 +112,139 @@ func TestUserReactionRepository(t *testing.T) {
 		counts, err := repo.CountByTarget(ctx, reactions.TargetTypePost, targetID)
 		require.NoError(t, err)
 		assert.Equal(t, 2, counts["❤️"])
-		assert.Equal(t, 0, counts["🔥"])
+		assert.Equal(t, 1, counts["🔥"])
+		assert.Equal(t, 0, counts["👍"])
+	})
+
+	t.Run("ListByTarget", func(t *testing.T) {
+		all, err := repo.ListByTarget(ctx, &reactions.ListReactorsParams{
+			TargetType: reactions.TargetTypePost,
+			TargetID:   targetID,
+			Emoji:      "",
+			Before:     nil,
+			Limit:      0,
+		})
+		require.NoError(t, err)
+		require.Len(t, all, 3)
+		assert.Equal(t, user2.ID, all[0].UserID)
+		assert.Equal(t, "❤️", all[1].Emoji)
+		assert.Equal(t, "🔥", all[2].Emoji)
+
+		hearts, err := repo.ListByTarget(ctx, &reactions.ListReactorsParams{
+			TargetType: reactions.TargetTypePost,
+			TargetID:   targetID,
+			Emoji:      "❤️",
+			Before:     nil,
+			Limit:      1,
+		})
+		require.NoError(t, err)
+		require.Len(t, hearts, 1)
+		assert.Equal(t, user2.ID, hearts[0].UserID)
+
+		hearts, err = repo.ListByTarget(ctx, &reactions.ListReactorsParams{
+			TargetType: reactions.TargetTypePost,
+			TargetID:   targetID,
+			Emoji:      "❤️",
+			Before:     &reactions.ReactorCursor{CreatedAt: hearts[0].CreatedAt, UserID: hearts[0].UserID},
+			Limit:      1,
+		})
+		require.NoError(t, err)
+		require.Len(t, hearts, 1)
+		assert.Equal(t, user1.ID, hearts[0].UserID)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		err := repo.Delete(ctx, reactions.TargetTypePost, targetID, user1.ID, "🔥")
+		require.NoError(t, err)
+
+		found, err := repo.ListByUserTarget(ctx, reactions.TargetTypePost, targetID, user1.ID)
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, "❤️", found[0].Emoji)
+
+		counts, err := repo.CountByTarget(ctx, reactions.TargetTypePost, targetID)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"❤️": 2}, counts)
+
+		// Deleting a reaction that is gone leaves the counts alone.
+		err = repo.Delete(ctx, reactions.TargetTypePost, targetID, user1.ID, "🔥")
+		require.NoError(t, err)
+
+		counts, err = repo.CountByTarget(ctx, reactions.TargetTypePost, targetID)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"❤️": 2}, counts)
 	})
 
 	t.Run("DeleteByUserTarget", func(t *testing.T) {
-		err := repo.DeleteByUserTarget(ctx, reactions.TargetTypePost, targetID, user1.ID)
+		err := repo.Insert(ctx, &reactions.UserReaction{
+			TargetType: reactions.TargetTypePost,
+			TargetID:   targetID,
+			UserID:     user1.ID,
+			Emoji:      "🔥",
+			CreatedAt:  time.Date(2026, 2, 24, 14, 0, 0, 0, time.UTC),
+		})
 		require.NoError(t, err)
 
-		_, err = repo.FindByUserTarget(ctx, reactions.TargetTypePost, targetID, user1.ID)
+		err = repo.DeleteByUserTarget(ctx, reactions.TargetTypePost, targetID, user1.ID)
+		require.NoError(t, err)
 
-		var userReactionNotFoundErr *reactions.UserReactionNotFoundError
+		found, err := repo.ListByUserTarget(ctx, reactions.TargetTypePost, targetID, user1.ID)
+		require.NoError(t, err)
+		assert.Empty(t, found)
 
-		require.ErrorAs(t, err, &userReactionNotFoundErr)
+		counts, err := repo.CountByTarget(ctx, reactions.TargetTypePost, targetID)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"❤️": 1}, counts)
 
 		err = repo.DeleteByUserTarget(ctx, reactions.TargetTypePost, targetID, user1.ID)
 		require.NoError(t, err)
 	})
+
+	t.Run("Failed inserts are not counted", func(t *testing.T) {
+		reaction := &reactions.UserReaction{
+			TargetType: reactions.TargetTypePost,
+			TargetID:   targetID,
+			UserID:     user2.ID,
+			Emoji:      "❤️",
+			CreatedAt:  time.Date(2026, 2, 24, 15, 0, 0, 0, time.UTC),
+		}
+
+		err := repo.Insert(ctx, reaction)
+		require.Error(t, err)
+
+		counts, err := repo.CountByTarget(ctx, reactions.TargetTypePost, targetID)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"❤️": 1}, counts)
+	})
+
+	t.Run("RepairCounts", func(t *testing.T) {
+		fixed, err := repo.RepairCounts(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, fixed)
+
+		// Reactions removed behind the repository's back leave their counts behind.
+		_, err = db.ExecContext(ctx, "DELETE FROM reactions WHERE target_id = ?", targetID)
+		require.NoError(t, err)
+
+		_, err = db.ExecContext(ctx, "UPDATE reaction_counts SET count = 5 WHERE emoji = ?", "👍")
+		require.NoError(t, err)
+
+		fixed, err = repo.RepairCounts(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, fixed)
+
+		counts, err := repo.CountByTarget(ctx, reactions.TargetTypePost, targetID)
+		require.NoError(t, err)
+		assert.Empty(t, counts)
+
+		var thumbsUp int
+
+		err = db.QueryRowContext(ctx, "SELECT count FROM reaction_counts WHERE emoji = ?", "👍").Scan(&thumbsUp)
+		require.NoError(t, err)
+		assert.Equal(t, 1, thumbsUp)
+
+		fixed, err = repo.RepairCounts(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, fixed)
+	})
 }
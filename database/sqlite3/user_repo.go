@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"log/slog"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/nasermirzaei89/scribble/authentication"
@@ -59,7 +60,7 @@ func (repo *UserRepository) Insert(ctx context.Context, user *authentication.Use
 		Columns(userColumns()...).
 		Values(user.ID, user.Username, user.PasswordHash, user.RegisteredAt)
 
-	q = q.RunWith(repo.db)
+	q = q.RunWith(runner(ctx, repo.db))
 
 	_, err := q.ExecContext(ctx)
 	if err != nil {
@@ -74,7 +75,7 @@ func (repo *UserRepository) Find(ctx context.Context, userID string) (*authentic
 		From(tableUsers).
 		Where(sq.Eq{userFieldID: userID})
 
-	q = q.RunWith(repo.db)
+	q = q.RunWith(runner(ctx, repo.db))
 
 	row := q.QueryRowContext(ctx)
 
@@ -90,12 +91,54 @@ func (repo *UserRepository) Find(ctx context.Context, userID string) (*authentic
 	return user, nil
 }
 
+func (repo *UserRepository) FindMany(ctx context.Context, userIDs []string) ([]*authentication.User, error) {
+	users := make([]*authentication.User, 0, len(userIDs))
+
+	if len(userIDs) == 0 {
+		return users, nil
+	}
+
+	q := sq.Select(userColumns()...).
+		From(tableUsers).
+		Where(sq.Eq{userFieldID: userIDs})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	rows, err := q.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan user failed: %w", err)
+		}
+
+		users = append(users, user)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("rows iteration failed: %w", err)
+	}
+
+	return users, nil
+}
+
 func (repo *UserRepository) FindByUsername(ctx context.Context, username string) (*authentication.User, error) {
 	q := sq.Select(userColumns()...).
 		From(tableUsers).
 		Where(sq.Eq{userFieldUsername: username})
 
-	q = q.RunWith(repo.db)
+	q = q.RunWith(runner(ctx, repo.db))
 
 	row := q.QueryRowContext(ctx)
 
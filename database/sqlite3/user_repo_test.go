@@ -63,6 +63,27 @@ func TestUserRepository(t *testing.T) {
 		assert.Equal(t, user.Username, foundByUsername.Username)
 	})
 
+	t.Run("FindMany leaves out missing users", func(t *testing.T) {
+		user := &authentication.User{
+			ID:           uuid.NewString(),
+			Username:     "janedoe",
+			PasswordHash: "password-hash",
+			RegisteredAt: time.Date(2026, 2, 24, 10, 45, 0, 0, time.UTC),
+		}
+
+		err := repo.Insert(ctx, user)
+		require.NoError(t, err)
+
+		found, err := repo.FindMany(ctx, []string{user.ID, uuid.NewString()})
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, user.Username, found[0].Username)
+
+		found, err = repo.FindMany(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, found)
+	})
+
 	t.Run("Insert duplicate username", func(t *testing.T) {
 		user := &authentication.User{
 			ID:           uuid.NewString(),
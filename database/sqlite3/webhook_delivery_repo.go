@@ -0,0 +1,243 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/nasermirzaei89/scribble/webhooks"
+)
+
+const tableWebhookDeliveries = "webhook_deliveries"
+
+type WebhookDeliveryRepository struct {
+	db *sql.DB
+}
+
+var _ webhooks.DeliveryRepository = (*WebhookDeliveryRepository)(nil)
+
+func NewWebhookDeliveryRepository(db *sql.DB) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{db: db}
+}
+
+const (
+	webhookDeliveryFieldID             = "id"
+	webhookDeliveryFieldEndpointID     = "endpoint_id"
+	webhookDeliveryFieldEventID        = "event_id"
+	webhookDeliveryFieldEventType      = "event_type"
+	webhookDeliveryFieldPayload        = "payload"
+	webhookDeliveryFieldRedeliveryOf   = "redelivery_of"
+	webhookDeliveryFieldStatus         = "status"
+	webhookDeliveryFieldAttempts       = "attempts"
+	webhookDeliveryFieldNextAttemptAt  = "next_attempt_at"
+	webhookDeliveryFieldLastError      = "last_error"
+	webhookDeliveryFieldResponseStatus = "response_status"
+	webhookDeliveryFieldCreatedAt      = "created_at"
+	webhookDeliveryFieldUpdatedAt      = "updated_at"
+)
+
+func webhookDeliveryColumns() []string {
+	return []string{
+		webhookDeliveryFieldID,
+		webhookDeliveryFieldEndpointID,
+		webhookDeliveryFieldEventID,
+		webhookDeliveryFieldEventType,
+		webhookDeliveryFieldPayload,
+		webhookDeliveryFieldRedeliveryOf,
+		webhookDeliveryFieldStatus,
+		webhookDeliveryFieldAttempts,
+		webhookDeliveryFieldNextAttemptAt,
+		webhookDeliveryFieldLastError,
+		webhookDeliveryFieldResponseStatus,
+		webhookDeliveryFieldCreatedAt,
+		webhookDeliveryFieldUpdatedAt,
+	}
+}
+
+func scanWebhookDelivery(row sq.RowScanner) (*webhooks.Delivery, error) {
+	var (
+		delivery     webhooks.Delivery
+		payload      string
+		redeliveryOf sql.NullString
+	)
+
+	err := row.Scan(
+		&delivery.ID,
+		&delivery.EndpointID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&payload,
+		&redeliveryOf,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.NextAttemptAt,
+		&delivery.LastError,
+		&delivery.ResponseStatus,
+		&delivery.CreatedAt,
+		&delivery.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	delivery.Payload = []byte(payload)
+	delivery.RedeliveryOf = redeliveryOf.String
+
+	return &delivery, nil
+}
+
+func (repo *WebhookDeliveryRepository) Insert(ctx context.Context, delivery *webhooks.Delivery) (bool, error) {
+	var redeliveryOf sql.NullString
+	if delivery.RedeliveryOf != "" {
+		redeliveryOf = sql.NullString{String: delivery.RedeliveryOf, Valid: true}
+	}
+
+	q := sq.Insert(tableWebhookDeliveries).
+		Columns(webhookDeliveryColumns()...).
+		Values(
+			delivery.ID,
+			delivery.EndpointID,
+			delivery.EventID,
+			delivery.EventType,
+			string(delivery.Payload),
+			redeliveryOf,
+			delivery.Status,
+			delivery.Attempts,
+			delivery.NextAttemptAt.UTC(),
+			delivery.LastError,
+			delivery.ResponseStatus,
+			delivery.CreatedAt,
+			delivery.UpdatedAt,
+		).
+		Suffix(`ON CONFLICT (` + webhookDeliveryFieldEndpointID + `, ` + webhookDeliveryFieldEventID + `)
+			WHERE ` + webhookDeliveryFieldRedeliveryOf + ` IS NULL DO NOTHING`)
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	result, err := q.ExecContext(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to exec insert: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (repo *WebhookDeliveryRepository) Find(ctx context.Context, deliveryID string) (*webhooks.Delivery, error) {
+	q := sq.Select(webhookDeliveryColumns()...).
+		From(tableWebhookDeliveries).
+		Where(sq.Eq{webhookDeliveryFieldID: deliveryID})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	row := q.QueryRowContext(ctx)
+
+	delivery, err := scanWebhookDelivery(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, webhooks.DeliveryNotFoundError{ID: deliveryID}
+		}
+
+		return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+func (repo *WebhookDeliveryRepository) Update(ctx context.Context, delivery *webhooks.Delivery) error {
+	q := sq.Update(tableWebhookDeliveries).
+		Set(webhookDeliveryFieldStatus, delivery.Status).
+		Set(webhookDeliveryFieldAttempts, delivery.Attempts).
+		Set(webhookDeliveryFieldNextAttemptAt, delivery.NextAttemptAt.UTC()).
+		Set(webhookDeliveryFieldLastError, delivery.LastError).
+		Set(webhookDeliveryFieldResponseStatus, delivery.ResponseStatus).
+		Set(webhookDeliveryFieldUpdatedAt, delivery.UpdatedAt).
+		Where(sq.Eq{webhookDeliveryFieldID: delivery.ID})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err := q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec update: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *WebhookDeliveryRepository) ListDue(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]*webhooks.Delivery, error) {
+	// Times are stored in UTC, so they compare in order.
+	q := sq.Select(webhookDeliveryColumns()...).
+		From(tableWebhookDeliveries).
+		Where(sq.Eq{webhookDeliveryFieldStatus: webhooks.DeliveryStatusPending}).
+		Where(sq.LtOrEq{webhookDeliveryFieldNextAttemptAt: now.UTC()}).
+		OrderBy(webhookDeliveryFieldNextAttemptAt+" ASC", webhookDeliveryFieldID+" ASC")
+
+	if limit > 0 {
+		q = q.Limit(uint64(limit))
+	}
+
+	return repo.list(ctx, q)
+}
+
+func (repo *WebhookDeliveryRepository) ListByEndpoint(
+	ctx context.Context,
+	endpointID string,
+	limit int,
+) ([]*webhooks.Delivery, error) {
+	q := sq.Select(webhookDeliveryColumns()...).
+		From(tableWebhookDeliveries).
+		Where(sq.Eq{webhookDeliveryFieldEndpointID: endpointID}).
+		OrderBy(webhookDeliveryFieldCreatedAt+" DESC", webhookDeliveryFieldID+" DESC")
+
+	if limit > 0 {
+		q = q.Limit(uint64(limit))
+	}
+
+	return repo.list(ctx, q)
+}
+
+func (repo *WebhookDeliveryRepository) list(ctx context.Context, q sq.SelectBuilder) ([]*webhooks.Delivery, error) {
+	q = q.RunWith(runner(ctx, repo.db))
+
+	rows, err := q.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	result := make([]*webhooks.Delivery, 0)
+
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+
+		result = append(result, delivery)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return result, nil
+}
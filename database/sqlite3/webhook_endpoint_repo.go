@@ -0,0 +1,182 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/nasermirzaei89/scribble/webhooks"
+)
+
+const tableWebhookEndpoints = "webhook_endpoints"
+
+type WebhookEndpointRepository struct {
+	db *sql.DB
+}
+
+var _ webhooks.EndpointRepository = (*WebhookEndpointRepository)(nil)
+
+func NewWebhookEndpointRepository(db *sql.DB) *WebhookEndpointRepository {
+	return &WebhookEndpointRepository{db: db}
+}
+
+const (
+	webhookEndpointFieldID          = "id"
+	webhookEndpointFieldURL         = "url"
+	webhookEndpointFieldDescription = "description"
+	webhookEndpointFieldSecret      = "secret"
+	webhookEndpointFieldEventTypes  = "event_types"
+	webhookEndpointFieldCreatedAt   = "created_at"
+)
+
+func webhookEndpointColumns() []string {
+	return []string{
+		webhookEndpointFieldID,
+		webhookEndpointFieldURL,
+		webhookEndpointFieldDescription,
+		webhookEndpointFieldSecret,
+		webhookEndpointFieldEventTypes,
+		webhookEndpointFieldCreatedAt,
+	}
+}
+
+func scanWebhookEndpoint(row sq.RowScanner) (*webhooks.Endpoint, error) {
+	var (
+		endpoint   webhooks.Endpoint
+		eventTypes string
+	)
+
+	err := row.Scan(
+		&endpoint.ID,
+		&endpoint.URL,
+		&endpoint.Description,
+		&endpoint.Secret,
+		&eventTypes,
+		&endpoint.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	err = json.Unmarshal([]byte(eventTypes), &endpoint.EventTypes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event types: %w", err)
+	}
+
+	return &endpoint, nil
+}
+
+func (repo *WebhookEndpointRepository) Insert(ctx context.Context, endpoint *webhooks.Endpoint) error {
+	eventTypes, err := json.Marshal(endpoint.EventTypes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event types: %w", err)
+	}
+
+	q := sq.Insert(tableWebhookEndpoints).
+		Columns(webhookEndpointColumns()...).
+		Values(
+			endpoint.ID,
+			endpoint.URL,
+			endpoint.Description,
+			endpoint.Secret,
+			string(eventTypes),
+			endpoint.CreatedAt,
+		)
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err = q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec insert: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *WebhookEndpointRepository) Find(ctx context.Context, endpointID string) (*webhooks.Endpoint, error) {
+	q := sq.Select(webhookEndpointColumns()...).
+		From(tableWebhookEndpoints).
+		Where(sq.Eq{webhookEndpointFieldID: endpointID})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	row := q.QueryRowContext(ctx)
+
+	endpoint, err := scanWebhookEndpoint(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, webhooks.EndpointNotFoundError{ID: endpointID}
+		}
+
+		return nil, fmt.Errorf("failed to scan webhook endpoint: %w", err)
+	}
+
+	return endpoint, nil
+}
+
+func (repo *WebhookEndpointRepository) List(ctx context.Context) ([]*webhooks.Endpoint, error) {
+	q := sq.Select(webhookEndpointColumns()...).
+		From(tableWebhookEndpoints).
+		OrderBy(webhookEndpointFieldCreatedAt+" ASC", webhookEndpointFieldID+" ASC")
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	rows, err := q.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	result := make([]*webhooks.Endpoint, 0)
+
+	for rows.Next() {
+		endpoint, err := scanWebhookEndpoint(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook endpoint: %w", err)
+		}
+
+		result = append(result, endpoint)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return result, nil
+}
+
+// Delete deletes the endpoint with its deliveries.
+func (repo *WebhookEndpointRepository) Delete(ctx context.Context, endpointID string) error {
+	// Foreign keys are not enforced on every connection, so deliveries are not left to the cascade.
+	deliveriesQuery := sq.Delete(tableWebhookDeliveries).
+		Where(sq.Eq{webhookDeliveryFieldEndpointID: endpointID}).
+		RunWith(runner(ctx, repo.db))
+
+	_, err := deliveriesQuery.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec delete: %w", err)
+	}
+
+	q := sq.Delete(tableWebhookEndpoints).
+		Where(sq.Eq{webhookEndpointFieldID: endpointID})
+
+	q = q.RunWith(runner(ctx, repo.db))
+
+	_, err = q.ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to exec delete: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package sqlite3_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nasermirzaei89/scribble/database/sqlite3"
+	"github.com/nasermirzaei89/scribble/webhooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookRepositories(t *testing.T) {
+	ctx, db := newTestDB(t)
+
+	endpointRepo := sqlite3.NewWebhookEndpointRepository(db)
+	deliveryRepo := sqlite3.NewWebhookDeliveryRepository(db)
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	endpoint := &webhooks.Endpoint{
+		ID:          uuid.NewString(),
+		URL:         "https://chat.example/hooks/scribble",
+		Description: "Team chat",
+		Secret:      "secret",
+		EventTypes:  []string{"post.created", "comment.created"},
+		CreatedAt:   now,
+	}
+
+	err := endpointRepo.Insert(ctx, endpoint)
+	require.NoError(t, err)
+
+	found, err := endpointRepo.Find(ctx, endpoint.ID)
+	require.NoError(t, err)
+	assert.Equal(t, endpoint.URL, found.URL)
+	assert.Equal(t, "Team chat", found.Description)
+	assert.Equal(t, "secret", found.Secret)
+	assert.Equal(t, []string{"post.created", "comment.created"}, found.EventTypes)
+
+	_, err = endpointRepo.Find(ctx, uuid.NewString())
+	require.ErrorAs(t, err, &webhooks.EndpointNotFoundError{})
+
+	endpoints, err := endpointRepo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+
+	newDelivery := func(eventID string, createdAt time.Time) *webhooks.Delivery {
+		return &webhooks.Delivery{
+			ID:             uuid.NewString(),
+			EndpointID:     endpoint.ID,
+			EventID:        eventID,
+			EventType:      "post.created",
+			Payload:        []byte(`{"id":"` + eventID + `"}`),
+			RedeliveryOf:   "",
+			Status:         webhooks.DeliveryStatusPending,
+			Attempts:       0,
+			NextAttemptAt:  createdAt,
+			LastError:      "",
+			ResponseStatus: 0,
+			CreatedAt:      createdAt,
+			UpdatedAt:      createdAt,
+		}
+	}
+
+	delivery1 := newDelivery("event1", now.Add(-2*time.Minute))
+	delivery2 := newDelivery("event2", now.Add(-time.Minute))
+	delivery3 := newDelivery("event3", now.Add(time.Minute))
+
+	for _, delivery := range []*webhooks.Delivery{delivery1, delivery2, delivery3} {
+		inserted, err := deliveryRepo.Insert(ctx, delivery)
+		require.NoError(t, err)
+		assert.True(t, inserted)
+	}
+
+	t.Run("event is queued once per endpoint", func(t *testing.T) {
+		inserted, err := deliveryRepo.Insert(ctx, newDelivery("event1", now))
+		require.NoError(t, err)
+		assert.False(t, inserted)
+	})
+
+	due, err := deliveryRepo.ListDue(ctx, now, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	assert.Equal(t, delivery1.ID, due[0].ID)
+	assert.Equal(t, delivery2.ID, due[1].ID)
+	assert.JSONEq(t, `{"id":"event1"}`, string(due[0].Payload))
+	assert.Empty(t, due[0].RedeliveryOf)
+
+	delivery1.Status = webhooks.DeliveryStatusDelivered
+	delivery1.Attempts = 1
+	delivery1.ResponseStatus = 204
+	delivery1.UpdatedAt = now
+
+	err = deliveryRepo.Update(ctx, delivery1)
+	require.NoError(t, err)
+
+	found1, err := deliveryRepo.Find(ctx, delivery1.ID)
+	require.NoError(t, err)
+	assert.Equal(t, webhooks.DeliveryStatusDelivered, found1.Status)
+	assert.Equal(t, 1, found1.Attempts)
+	assert.Equal(t, 204, found1.ResponseStatus)
+
+	_, err = deliveryRepo.Find(ctx, uuid.NewString())
+	require.ErrorAs(t, err, &webhooks.DeliveryNotFoundError{})
+
+	t.Run("redeliveries are queued again", func(t *testing.T) {
+		redelivery := newDelivery("event1", now.Add(2*time.Minute))
+		redelivery.RedeliveryOf = delivery1.ID
+
+		inserted, err := deliveryRepo.Insert(ctx, redelivery)
+		require.NoError(t, err)
+		assert.True(t, inserted)
+
+		found, err := deliveryRepo.Find(ctx, redelivery.ID)
+		require.NoError(t, err)
+		assert.Equal(t, delivery1.ID, found.RedeliveryOf)
+	})
+
+	deliveries, err := deliveryRepo.ListByEndpoint(ctx, endpoint.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 4)
+	assert.Equal(t, delivery1.ID, deliveries[0].RedeliveryOf)
+	assert.Equal(t, delivery3.ID, deliveries[1].ID)
+	assert.Equal(t, delivery1.ID, deliveries[3].ID)
+
+	err = endpointRepo.Delete(ctx, endpoint.ID)
+	require.NoError(t, err)
+
+	_, err = endpointRepo.Find(ctx, endpoint.ID)
+	require.ErrorAs(t, err, &webhooks.EndpointNotFoundError{})
+
+	deliveries, err = deliveryRepo.ListByEndpoint(ctx, endpoint.ID, 10)
+	require.NoError(t, err)
+	assert.Empty(t, deliveries)
+}
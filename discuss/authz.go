@@ -4,15 +4,33 @@ import (
 	"context"
 	"fmt"
 
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
 	"github.com/nasermirzaei89/scribble/authorization"
 )
 
 const (
-	ActionCreateComment = "createComment"
-	ActionListComments  = "listComments"
-	ActionCountComments = "countComments"
+	ActionCreateComment    = "createComment"
+	ActionListComments     = "listComments"
+	ActionGetComment       = "getComment"
+	ActionCountComments    = "countComments"
+	ActionUpdateComment    = "updateComment"
+	ActionDeleteComment    = "deleteComment"
+	ActionListCommentEdits = "listCommentEdits"
 )
 
+// ObjectOwnComment is the object of checks on a comment of the subject, so policies can allow changing only one's own
+// comments, and any comment to moderators.
+const ObjectOwnComment = "own"
+
+// CommentObject returns the object the permissions on the comment are checked on for the subject in the context.
+func CommentObject(ctx context.Context, comment *Comment) string {
+	if comment.AuthorID == authcontext.GetSubject(ctx) {
+		return ObjectOwnComment
+	}
+
+	return comment.ID
+}
+
 type AuthorizationMiddleware struct {
 	authzClient *authorization.Client
 	next        Service
@@ -41,13 +59,13 @@ func (mw *AuthorizationMiddleware) CreateComment(ctx context.Context, req Create
 	return comment, nil
 }
 
-func (mw *AuthorizationMiddleware) ListComments(ctx context.Context, postID string) ([]*Comment, error) {
+func (mw *AuthorizationMiddleware) ListComments(ctx context.Context, req ListCommentsRequest) ([]*Comment, error) {
 	err := mw.authzClient.CheckAccess(ctx, ServiceName, "", ActionListComments)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check authorization: %w", err)
 	}
 
-	comments, err := mw.next.ListComments(ctx, postID)
+	comments, err := mw.next.ListComments(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call next method: %w", err)
 	}
@@ -55,6 +73,20 @@ func (mw *AuthorizationMiddleware) ListComments(ctx context.Context, postID stri
 	return comments, nil
 }
 
+func (mw *AuthorizationMiddleware) GetComment(ctx context.Context, commentID string) (*Comment, error) {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, commentID, ActionGetComment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	comment, err := mw.next.GetComment(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return comment, nil
+}
+
 func (mw *AuthorizationMiddleware) CountComments(ctx context.Context, postID string) (int, error) {
 	err := mw.authzClient.CheckAccess(ctx, ServiceName, "", ActionCountComments)
 	if err != nil {
@@ -68,3 +100,58 @@ func (mw *AuthorizationMiddleware) CountComments(ctx context.Context, postID str
 
 	return count, nil
 }
+
+func (mw *AuthorizationMiddleware) UpdateComment(
+	ctx context.Context,
+	req UpdateCommentRequest,
+) (*Comment, bool, error) {
+	comment, err := mw.next.GetComment(ctx, req.CommentID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	err = mw.authzClient.CheckAccess(ctx, ServiceName, CommentObject(ctx, comment), ActionUpdateComment)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	comment, changed, err := mw.next.UpdateComment(ctx, req)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return comment, changed, nil
+}
+
+func (mw *AuthorizationMiddleware) DeleteComment(ctx context.Context, commentID string) (*Comment, error) {
+	comment, err := mw.next.GetComment(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	err = mw.authzClient.CheckAccess(ctx, ServiceName, CommentObject(ctx, comment), ActionDeleteComment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	comment, err = mw.next.DeleteComment(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return comment, nil
+}
+
+func (mw *AuthorizationMiddleware) ListCommentEdits(ctx context.Context, commentID string) ([]*CommentEdit, error) {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, commentID, ActionListCommentEdits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	edits, err := mw.next.ListCommentEdits(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return edits, nil
+}
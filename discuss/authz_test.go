@@ -15,7 +15,9 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-type stubService struct{}
+type stubService struct {
+	commentAuthorID string
+}
 
 func (s *stubService) CreateComment(ctx context.Context, req discuss.CreateCommentRequest) (*discuss.Comment, error) {
 	return &discuss.Comment{
@@ -26,26 +28,52 @@ func (s *stubService) CreateComment(ctx context.Context, req discuss.CreateComme
 	}, nil
 }
 
-func (s *stubService) ListComments(ctx context.Context, postID string) ([]*discuss.Comment, error) {
+func (s *stubService) ListComments(ctx context.Context, req discuss.ListCommentsRequest) ([]*discuss.Comment, error) {
 	return []*discuss.Comment{}, nil
 }
 
+func (s *stubService) GetComment(ctx context.Context, commentID string) (*discuss.Comment, error) {
+	return &discuss.Comment{ID: commentID, AuthorID: s.commentAuthorID}, nil
+}
+
 func (s *stubService) CountComments(ctx context.Context, postID string) (int, error) {
 	return 0, nil
 }
 
+func (s *stubService) UpdateComment(
+	ctx context.Context,
+	req discuss.UpdateCommentRequest,
+) (*discuss.Comment, bool, error) {
+	return &discuss.Comment{ID: req.CommentID, Content: req.Content}, true, nil
+}
+
+func (s *stubService) DeleteComment(ctx context.Context, commentID string) (*discuss.Comment, error) {
+	return &discuss.Comment{ID: commentID}, nil
+}
+
+func (s *stubService) ListCommentEdits(ctx context.Context, commentID string) ([]*discuss.CommentEdit, error) {
+	return []*discuss.CommentEdit{}, nil
+}
+
 func TestAuthorizationMiddleware(t *testing.T) {
 	ctx := context.Background()
 
 	tmpDir := t.TempDir()
 	tmpFile := filepath.Join(tmpDir, "policy.csv")
-	content := []byte(`g, system:anonymous, system:unauthenticated
+	content := []byte(`g, system:anonymous, system:unauthenticated, *
 
 p, system:authenticated, github.com/nasermirzaei89/scribble/discuss, -, createComment
 p, system:authenticated, github.com/nasermirzaei89/scribble/discuss, -, listComments
 p, system:unauthenticated, github.com/nasermirzaei89/scribble/discuss, -, listComments
+p, system:authenticated, github.com/nasermirzaei89/scribble/discuss, *, getComment
+p, system:unauthenticated, github.com/nasermirzaei89/scribble/discuss, *, getComment
 p, system:authenticated, github.com/nasermirzaei89/scribble/discuss, -, countComments
 p, system:unauthenticated, github.com/nasermirzaei89/scribble/discuss, -, countComments
+p, system:authenticated, github.com/nasermirzaei89/scribble/discuss, *, listCommentEdits
+p, system:unauthenticated, github.com/nasermirzaei89/scribble/discuss, *, listCommentEdits
+p, system:authenticated, github.com/nasermirzaei89/scribble/discuss, own, updateComment
+p, system:authenticated, github.com/nasermirzaei89/scribble/discuss, own, deleteComment
+p, system:group:moderator, github.com/nasermirzaei89/scribble/discuss, *, deleteComment
 `)
 
 	err := os.WriteFile(tmpFile, content, 0o600)
@@ -60,17 +88,26 @@ p, system:unauthenticated, github.com/nasermirzaei89/scribble/discuss, -, countC
 	require.NoError(t, err)
 
 	client := authorization.NewClient(authzSvc)
-	svc := discuss.NewAuthorizationMiddleware(client, &stubService{})
+	authorID := uuid.NewString()
+	svc := discuss.NewAuthorizationMiddleware(client, &stubService{commentAuthorID: authorID})
 
 	userID := uuid.NewString()
 	err = client.AddToGroup(ctx, userID, authcontext.Authenticated)
 	require.NoError(t, err)
 
-	authorID := uuid.NewString()
+	err = client.AddToGroup(ctx, authorID, authcontext.Authenticated)
+	require.NoError(t, err)
+
+	moderatorID := uuid.NewString()
+	err = client.AddToGroup(ctx, moderatorID, authcontext.Authenticated, "system:group:moderator")
+	require.NoError(t, err)
+
 	postID := uuid.NewString()
 
 	anonymousCtx := ctx
 	authenticatedCtx := authcontext.WithSubject(ctx, userID)
+	authorCtx := authcontext.WithSubject(ctx, authorID)
+	moderatorCtx := authcontext.WithSubject(ctx, moderatorID)
 
 	t.Run("anonymous", func(t *testing.T) {
 		_, err := svc.CreateComment(anonymousCtx, discuss.CreateCommentRequest{
@@ -83,11 +120,23 @@ p, system:unauthenticated, github.com/nasermirzaei89/scribble/discuss, -, countC
 		accessDeniedErr := &authorization.AccessDeniedError{}
 		require.ErrorAs(t, err, &accessDeniedErr)
 
-		_, err = svc.ListComments(anonymousCtx, postID)
+		_, err = svc.ListComments(anonymousCtx, discuss.ListCommentsRequest{PostID: postID})
+		require.NoError(t, err)
+
+		_, err = svc.GetComment(anonymousCtx, uuid.NewString())
 		require.NoError(t, err)
 
 		_, err = svc.CountComments(anonymousCtx, postID)
 		require.NoError(t, err)
+
+		_, err = svc.ListCommentEdits(anonymousCtx, uuid.NewString())
+		require.NoError(t, err)
+
+		_, _, err = svc.UpdateComment(anonymousCtx, discuss.UpdateCommentRequest{CommentID: uuid.NewString()})
+		require.ErrorAs(t, err, &accessDeniedErr)
+
+		_, err = svc.DeleteComment(anonymousCtx, uuid.NewString())
+		require.ErrorAs(t, err, &accessDeniedErr)
 	})
 
 	t.Run("authenticated", func(t *testing.T) {
@@ -98,10 +147,42 @@ p, system:unauthenticated, github.com/nasermirzaei89/scribble/discuss, -, countC
 		})
 		require.NoError(t, err)
 
-		_, err = svc.ListComments(authenticatedCtx, postID)
+		_, err = svc.ListComments(authenticatedCtx, discuss.ListCommentsRequest{PostID: postID})
+		require.NoError(t, err)
+
+		_, err = svc.GetComment(authenticatedCtx, uuid.NewString())
 		require.NoError(t, err)
 
 		_, err = svc.CountComments(authenticatedCtx, postID)
 		require.NoError(t, err)
+
+		_, err = svc.ListCommentEdits(authenticatedCtx, uuid.NewString())
+		require.NoError(t, err)
+
+		accessDeniedErr := &authorization.AccessDeniedError{}
+
+		_, _, err = svc.UpdateComment(authenticatedCtx, discuss.UpdateCommentRequest{CommentID: uuid.NewString()})
+		require.ErrorAs(t, err, &accessDeniedErr)
+
+		_, err = svc.DeleteComment(authenticatedCtx, uuid.NewString())
+		require.ErrorAs(t, err, &accessDeniedErr)
+	})
+
+	t.Run("author", func(t *testing.T) {
+		_, _, err := svc.UpdateComment(authorCtx, discuss.UpdateCommentRequest{CommentID: uuid.NewString()})
+		require.NoError(t, err)
+
+		_, err = svc.DeleteComment(authorCtx, uuid.NewString())
+		require.NoError(t, err)
+	})
+
+	t.Run("moderator", func(t *testing.T) {
+		accessDeniedErr := &authorization.AccessDeniedError{}
+
+		_, _, err := svc.UpdateComment(moderatorCtx, discuss.UpdateCommentRequest{CommentID: uuid.NewString()})
+		require.ErrorAs(t, err, &accessDeniedErr)
+
+		_, err = svc.DeleteComment(moderatorCtx, uuid.NewString())
+		require.NoError(t, err)
 	})
 }
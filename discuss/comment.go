@@ -2,6 +2,7 @@ package discuss
 
 import (
 	"context"
+	"fmt"
 	"time"
 )
 
@@ -12,18 +13,129 @@ type Comment struct {
 	ReplyTo   *string
 	Content   string
 	CreatedAt time.Time
+	// ReactionScore is the weighted sum of the reactions to the comment, which the top sort ranks by.
+	ReactionScore int
+	// EditedAt is when the content was last changed by its author, nil for comments never edited.
+	EditedAt *time.Time
+	// DeletedAt is when the comment was deleted, nil for comments not deleted. Deleted comments are kept as tombstones
+	// without content, so their replies stay in place.
+	DeletedAt *time.Time
+}
+
+func (comment *Comment) IsDeleted() bool {
+	return comment.DeletedAt != nil
+}
+
+// Cursor returns the position of the comment in a list in the sort.
+func (comment *Comment) Cursor(sort CommentSort) *CommentCursor {
+	cursor := &CommentCursor{ReactionScore: 0, CreatedAt: comment.CreatedAt, ID: comment.ID}
+
+	if sort == CommentSortTop {
+		cursor.ReactionScore = comment.ReactionScore
+	}
+
+	return cursor
+}
+
+// CommentSort is the order comments are listed in.
+type CommentSort string
+
+const (
+	// CommentSortOldest lists comments from oldest to newest, in the order the conversation happened.
+	CommentSortOldest CommentSort = "oldest"
+	// CommentSortNewest lists comments from newest to oldest.
+	CommentSortNewest CommentSort = "newest"
+	// CommentSortTop lists comments from the highest reaction score to the lowest, oldest first among equals.
+	CommentSortTop CommentSort = "top"
+)
+
+// CommentSorts returns all comment sorts, in the order they are offered.
+func CommentSorts() []CommentSort {
+	return []CommentSort{CommentSortOldest, CommentSortNewest, CommentSortTop}
+}
+
+func (sort CommentSort) IsValid() bool {
+	switch sort {
+	case CommentSortOldest, CommentSortNewest, CommentSortTop:
+		return true
+	default:
+		return false
+	}
 }
 
 type CommentRepository interface {
 	Insert(ctx context.Context, comment *Comment) (err error)
+	Update(ctx context.Context, comment *Comment) (err error)
+	Find(ctx context.Context, commentID string) (comment *Comment, err error)
 	List(ctx context.Context, params *ListCommentsParams) (comments []*Comment, err error)
 	Count(ctx context.Context, params *CountCommentsParams) (count int, err error)
 }
 
+// CommentCursor is the position of a comment in a list of comments, ordered by the reaction score in the top sort and
+// by creation time, with the ID breaking ties.
+type CommentCursor struct {
+	// ReactionScore is the reaction score of the comment in the top sort.
+	ReactionScore int
+	CreatedAt     time.Time
+	ID            string
+}
+
 type ListCommentsParams struct {
 	PostID string
+	// TopLevel limits the list to comments on the post itself, which reply to no other comment.
+	TopLevel bool
+	// ReplyTo limits the list to replies to any of the comments. Empty means comments replying to anything.
+	ReplyTo []string
+	// Sort is the order of the list.
+	Sort CommentSort
+	// After limits the list to comments after the cursor in list order.
+	After *CommentCursor
+	// Limit is the maximum number of comments to return. Zero means no limit.
+	Limit int
 }
 
 type CountCommentsParams struct {
 	PostID string
 }
+
+type CommentNotFoundError struct {
+	ID string
+}
+
+func (err CommentNotFoundError) Error() string {
+	return fmt.Sprintf("comment with id %q not found", err.ID)
+}
+
+// CommentEdit is a previous version of an edited comment.
+type CommentEdit struct {
+	ID        string
+	CommentID string
+	// Content is the content of the comment before the edit.
+	Content string
+	// EditedAt is when the content was replaced.
+	EditedAt time.Time
+}
+
+// CommentEditRepository keeps the edit history of comments.
+type CommentEditRepository interface {
+	Insert(ctx context.Context, edit *CommentEdit) (err error)
+	// List returns the edits of the comment from the newest to the oldest.
+	List(ctx context.Context, commentID string) (edits []*CommentEdit, err error)
+	DeleteAll(ctx context.Context, commentID string) (err error)
+}
+
+type CommentDeletedError struct {
+	ID string
+}
+
+func (err CommentDeletedError) Error() string {
+	return fmt.Sprintf("comment with id %q is deleted", err.ID)
+}
+
+type InvalidCommentSortError struct {
+	Sort CommentSort
+}
+
+func (err InvalidCommentSortError) Error() string {
+	return fmt.Sprintf("invalid comment sort: %q", err.Sort)
+}
@@ -2,34 +2,51 @@ package discuss
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/events"
 )
 
 const ServiceName = "github.com/nasermirzaei89/scribble/discuss"
 
 type Service interface {
 	CreateComment(ctx context.Context, req CreateCommentRequest) (*Comment, error)
-	ListComments(ctx context.Context, postID string) ([]*Comment, error)
+	ListComments(ctx context.Context, req ListCommentsRequest) ([]*Comment, error)
+	GetComment(ctx context.Context, commentID string) (*Comment, error)
 	CountComments(ctx context.Context, postID string) (int, error)
+	// UpdateComment returns the comment, and whether its content changed. Saving it unchanged is no edit.
+	UpdateComment(ctx context.Context, req UpdateCommentRequest) (comment *Comment, changed bool, err error)
+	DeleteComment(ctx context.Context, commentID string) (*Comment, error)
+	ListCommentEdits(ctx context.Context, commentID string) ([]*CommentEdit, error)
 }
 
 type BaseService struct {
-	commentRepo CommentRepository
+	commentRepo     CommentRepository
+	commentEditRepo CommentEditRepository
 }
 
 var _ Service = (*BaseService)(nil)
 
-func NewService(commentRepo CommentRepository, authzClient *authorization.Client) Service { //nolint:ireturn
-	return NewAuthorizationMiddleware(authzClient, NewBaseService(commentRepo))
+func NewService( //nolint:ireturn
+	commentRepo CommentRepository,
+	commentEditRepo CommentEditRepository,
+	authzClient *authorization.Client,
+	bus *events.Bus,
+) Service {
+	return NewAuthorizationMiddleware(
+		authzClient,
+		NewEventsMiddleware(bus, NewBaseService(commentRepo, commentEditRepo)),
+	)
 }
 
-func NewBaseService(commentRepo CommentRepository) *BaseService {
+func NewBaseService(commentRepo CommentRepository, commentEditRepo CommentEditRepository) *BaseService {
 	return &BaseService{
-		commentRepo: commentRepo,
+		commentRepo:     commentRepo,
+		commentEditRepo: commentEditRepo,
 	}
 }
 
@@ -43,16 +60,33 @@ type CreateCommentRequest struct {
 func (svc *BaseService) CreateComment(ctx context.Context, req CreateCommentRequest) (*Comment, error) {
 	var replyTo *string
 	if req.ReplyTo != "" {
+		// Replies stay on the post of the comment they reply to.
+		parent, err := svc.commentRepo.Find(ctx, req.ReplyTo)
+		if err != nil {
+			if _, ok := errors.AsType[CommentNotFoundError](err); ok {
+				return nil, CommentNotFoundError{ID: req.ReplyTo}
+			}
+
+			return nil, fmt.Errorf("failed to find comment to reply to: %w", err)
+		}
+
+		if parent.PostID != req.PostID {
+			return nil, CommentNotFoundError{ID: req.ReplyTo}
+		}
+
 		replyTo = &req.ReplyTo
 	}
 
 	comment := &Comment{
-		ID:        uuid.NewString(),
-		PostID:    req.PostID,
-		AuthorID:  req.AuthorID,
-		ReplyTo:   replyTo,
-		Content:   req.Content,
-		CreatedAt: time.Now(),
+		ID:            uuid.NewString(),
+		PostID:        req.PostID,
+		AuthorID:      req.AuthorID,
+		ReplyTo:       replyTo,
+		Content:       req.Content,
+		CreatedAt:     time.Now(),
+		ReactionScore: 0,
+		EditedAt:      nil,
+		DeletedAt:     nil,
 	}
 
 	err := svc.commentRepo.Insert(ctx, comment)
@@ -63,8 +97,36 @@ func (svc *BaseService) CreateComment(ctx context.Context, req CreateCommentRequ
 	return comment, nil
 }
 
-func (svc *BaseService) ListComments(ctx context.Context, postID string) ([]*Comment, error) {
-	comments, err := svc.commentRepo.List(ctx, &ListCommentsParams{PostID: postID})
+type ListCommentsRequest struct {
+	PostID string
+	// TopLevel limits the list to comments on the post itself, for paging through threads.
+	TopLevel bool
+	// ReplyTo limits the list to replies to any of the comments, for loading threads a level at a time.
+	ReplyTo []string
+	// Sort is the order of the list. Empty means CommentSortOldest.
+	Sort CommentSort
+	// After is the cursor of the last comment of the previous page, from Comment.Cursor with the same sort.
+	After *CommentCursor
+	Limit int
+}
+
+func (svc *BaseService) ListComments(ctx context.Context, req ListCommentsRequest) ([]*Comment, error) {
+	if req.Sort == "" {
+		req.Sort = CommentSortOldest
+	}
+
+	if !req.Sort.IsValid() {
+		return nil, InvalidCommentSortError{Sort: req.Sort}
+	}
+
+	comments, err := svc.commentRepo.List(ctx, &ListCommentsParams{
+		PostID:   req.PostID,
+		TopLevel: req.TopLevel,
+		ReplyTo:  req.ReplyTo,
+		Sort:     req.Sort,
+		After:    req.After,
+		Limit:    req.Limit,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list comments: %w", err)
 	}
@@ -72,6 +134,15 @@ func (svc *BaseService) ListComments(ctx context.Context, postID string) ([]*Com
 	return comments, nil
 }
 
+func (svc *BaseService) GetComment(ctx context.Context, commentID string) (*Comment, error) {
+	comment, err := svc.commentRepo.Find(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find comment: %w", err)
+	}
+
+	return comment, nil
+}
+
 func (svc *BaseService) CountComments(ctx context.Context, postID string) (int, error) {
 	count, err := svc.commentRepo.Count(ctx, &CountCommentsParams{PostID: postID})
 	if err != nil {
@@ -80,3 +151,91 @@ func (svc *BaseService) CountComments(ctx context.Context, postID string) (int,
 
 	return count, nil
 }
+
+type UpdateCommentRequest struct {
+	CommentID string
+	Content   string
+}
+
+// UpdateComment replaces the content of the comment, keeping the previous content in its edit history. Deleted
+// comments cannot be edited.
+func (svc *BaseService) UpdateComment(ctx context.Context, req UpdateCommentRequest) (*Comment, bool, error) {
+	comment, err := svc.commentRepo.Find(ctx, req.CommentID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to find comment: %w", err)
+	}
+
+	if comment.IsDeleted() {
+		return nil, false, CommentDeletedError{ID: comment.ID}
+	}
+
+	if req.Content == comment.Content {
+		return comment, false, nil
+	}
+
+	now := time.Now()
+
+	err = svc.commentEditRepo.Insert(ctx, &CommentEdit{
+		ID:        uuid.NewString(),
+		CommentID: comment.ID,
+		Content:   comment.Content,
+		EditedAt:  now,
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to insert comment edit: %w", err)
+	}
+
+	comment.Content = req.Content
+	comment.EditedAt = &now
+
+	err = svc.commentRepo.Update(ctx, comment)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	return comment, true, nil
+}
+
+// DeleteComment replaces the comment with a tombstone, which keeps its place in the thread for the replies to it. The
+// content and its edit history are removed.
+func (svc *BaseService) DeleteComment(ctx context.Context, commentID string) (*Comment, error) {
+	comment, err := svc.commentRepo.Find(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find comment: %w", err)
+	}
+
+	if comment.IsDeleted() {
+		return nil, CommentDeletedError{ID: comment.ID}
+	}
+
+	err = svc.commentEditRepo.DeleteAll(ctx, comment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete comment edits: %w", err)
+	}
+
+	now := time.Now()
+
+	comment.Content = ""
+	comment.DeletedAt = &now
+
+	err = svc.commentRepo.Update(ctx, comment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	return comment, nil
+}
+
+func (svc *BaseService) ListCommentEdits(ctx context.Context, commentID string) ([]*CommentEdit, error) {
+	comment, err := svc.commentRepo.Find(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find comment: %w", err)
+	}
+
+	edits, err := svc.commentEditRepo.List(ctx, comment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list comment edits: %w", err)
+	}
+
+	return edits, nil
+}
@@ -0,0 +1,212 @@
+package discuss_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/authentication"
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/database/sqlite3"
+	"github.com/nasermirzaei89/scribble/discuss"
+	"github.com/nasermirzaei89/scribble/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateAndDeleteComment(t *testing.T) {
+	ctx := authcontext.WithSubject(context.Background(), "alice")
+
+	db, err := sqlite3.NewDB(ctx, "file:TestDiscussUpdateAndDeleteComment?mode=memory&cache=shared")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := db.Close()
+		require.NoError(t, err)
+	})
+
+	err = sqlite3.MigrateUp(ctx, db)
+	require.NoError(t, err)
+
+	postRepo := sqlite3.NewPostRepository(db)
+
+	bus := events.NewBus(sqlite3.NewEventOutboxRepository(db), sqlite3.NewTransactor(db))
+
+	var updated []events.CommentUpdated
+
+	events.Subscribe(bus, func(_ context.Context, event events.CommentUpdated) error {
+		updated = append(updated, event)
+
+		return nil
+	})
+
+	var deleted []events.CommentDeleted
+
+	events.Subscribe(bus, func(_ context.Context, event events.CommentDeleted) error {
+		deleted = append(deleted, event)
+
+		return nil
+	})
+
+	svc := discuss.NewEventsMiddleware(bus, discuss.NewBaseService(
+		sqlite3.NewCommentRepository(db),
+		sqlite3.NewCommentEditRepository(db),
+	))
+
+	err = sqlite3.NewUserRepository(db).Insert(ctx, &authentication.User{
+		ID:           "alice",
+		Username:     "alice",
+		PasswordHash: "hash",
+		RegisteredAt: time.Now(),
+	})
+	require.NoError(t, err)
+
+	post := &contents.Post{
+		ID:            "post",
+		AuthorID:      "alice",
+		CommunityID:   "",
+		Content:       "post",
+		CreatedAt:     time.Now(),
+		ReactionScore: 0,
+		Hotness:       0,
+	}
+
+	err = postRepo.Insert(ctx, post)
+	require.NoError(t, err)
+
+	comment, err := svc.CreateComment(ctx, discuss.CreateCommentRequest{
+		PostID:   post.ID,
+		AuthorID: "alice",
+		Content:  "first version",
+		ReplyTo:  "",
+	})
+	require.NoError(t, err)
+
+	reply, err := svc.CreateComment(ctx, discuss.CreateCommentRequest{
+		PostID:   post.ID,
+		AuthorID: "alice",
+		Content:  "reply",
+		ReplyTo:  comment.ID,
+	})
+	require.NoError(t, err)
+
+	t.Run("replies stay on the post of the comment", func(t *testing.T) {
+		otherPost := &contents.Post{
+			ID:            "other-post",
+			AuthorID:      "alice",
+			CommunityID:   "",
+			Content:       "other post",
+			CreatedAt:     time.Now(),
+			ReactionScore: 0,
+			Hotness:       0,
+		}
+
+		err := postRepo.Insert(ctx, otherPost)
+		require.NoError(t, err)
+
+		for _, replyTo := range []string{"missing", comment.ID} {
+			_, err = svc.CreateComment(ctx, discuss.CreateCommentRequest{
+				PostID:   otherPost.ID,
+				AuthorID: "alice",
+				Content:  "reply",
+				ReplyTo:  replyTo,
+			})
+			require.ErrorAs(t, err, &discuss.CommentNotFoundError{}, replyTo)
+		}
+
+		count, err := svc.CountComments(ctx, otherPost.ID)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("update keeps the edit history", func(t *testing.T) {
+		for _, content := range []string{"second version", "third version"} {
+			_, changed, err := svc.UpdateComment(ctx, discuss.UpdateCommentRequest{CommentID: comment.ID, Content: content})
+			require.NoError(t, err)
+			assert.True(t, changed)
+		}
+
+		found, err := svc.GetComment(ctx, comment.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "third version", found.Content)
+		assert.NotNil(t, found.EditedAt)
+
+		edits, err := svc.ListCommentEdits(ctx, comment.ID)
+		require.NoError(t, err)
+		require.Len(t, edits, 2)
+		assert.Equal(t, "second version", edits[0].Content)
+		assert.Equal(t, "first version", edits[1].Content)
+
+		require.Len(t, updated, 2)
+		assert.Equal(t, comment.ID, updated[1].CommentID)
+	})
+
+	t.Run("unchanged content is no edit", func(t *testing.T) {
+		found, changed, err := svc.UpdateComment(ctx, discuss.UpdateCommentRequest{
+			CommentID: comment.ID,
+			Content:   "third version",
+		})
+		require.NoError(t, err)
+		assert.False(t, changed)
+		assert.Equal(t, "third version", found.Content)
+
+		edits, err := svc.ListCommentEdits(ctx, comment.ID)
+		require.NoError(t, err)
+		assert.Len(t, edits, 2)
+		assert.Len(t, updated, 2)
+	})
+
+	t.Run("delete leaves a tombstone with the replies", func(t *testing.T) {
+		tombstone, err := svc.DeleteComment(ctx, comment.ID)
+		require.NoError(t, err)
+		assert.True(t, tombstone.IsDeleted())
+		assert.Empty(t, tombstone.Content)
+
+		found, err := svc.GetComment(ctx, comment.ID)
+		require.NoError(t, err)
+		assert.True(t, found.IsDeleted())
+		assert.Empty(t, found.Content)
+
+		edits, err := svc.ListCommentEdits(ctx, comment.ID)
+		require.NoError(t, err)
+		assert.Empty(t, edits)
+
+		replies, err := svc.ListComments(ctx, discuss.ListCommentsRequest{
+			PostID:   post.ID,
+			TopLevel: false,
+			ReplyTo:  []string{comment.ID},
+			Sort:     "",
+			After:    nil,
+			Limit:    0,
+		})
+		require.NoError(t, err)
+		require.Len(t, replies, 1)
+		assert.Equal(t, reply.ID, replies[0].ID)
+
+		count, err := svc.CountComments(ctx, post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		require.Len(t, deleted, 1)
+		assert.Equal(t, comment.ID, deleted[0].CommentID)
+	})
+
+	t.Run("deleted comments cannot change", func(t *testing.T) {
+		_, _, err := svc.UpdateComment(ctx, discuss.UpdateCommentRequest{CommentID: comment.ID, Content: "back"})
+		require.ErrorAs(t, err, &discuss.CommentDeletedError{})
+
+		_, err = svc.DeleteComment(ctx, comment.ID)
+		require.ErrorAs(t, err, &discuss.CommentDeletedError{})
+
+		assert.Len(t, deleted, 1)
+	})
+
+	t.Run("missing comments", func(t *testing.T) {
+		_, _, err := svc.UpdateComment(ctx, discuss.UpdateCommentRequest{CommentID: "missing", Content: "content"})
+		require.ErrorAs(t, err, &discuss.CommentNotFoundError{})
+
+		_, err = svc.DeleteComment(ctx, "missing")
+		require.ErrorAs(t, err, &discuss.CommentNotFoundError{})
+	})
+}
@@ -0,0 +1,166 @@
+package discuss
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nasermirzaei89/scribble/events"
+)
+
+// EventsMiddleware publishes the changes of the next service, in the same transaction.
+type EventsMiddleware struct {
+	bus  *events.Bus
+	next Service
+}
+
+var _ Service = (*EventsMiddleware)(nil)
+
+func NewEventsMiddleware(bus *events.Bus, next Service) *EventsMiddleware {
+	return &EventsMiddleware{
+		bus:  bus,
+		next: next,
+	}
+}
+
+func (mw *EventsMiddleware) CreateComment(ctx context.Context, req CreateCommentRequest) (*Comment, error) {
+	var comment *Comment
+
+	err := mw.bus.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+
+		comment, err = mw.next.CreateComment(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to call next method: %w", err)
+		}
+
+		event := events.CommentCreated{
+			CommentID: comment.ID,
+			PostID:    comment.PostID,
+			AuthorID:  comment.AuthorID,
+			ReplyTo:   "",
+			CreatedAt: comment.CreatedAt,
+		}
+
+		if comment.ReplyTo != nil {
+			event.ReplyTo = *comment.ReplyTo
+		}
+
+		err = mw.bus.Publish(ctx, event)
+		if err != nil {
+			return fmt.Errorf("failed to publish event: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	return comment, nil
+}
+
+func (mw *EventsMiddleware) ListComments(ctx context.Context, req ListCommentsRequest) ([]*Comment, error) {
+	comments, err := mw.next.ListComments(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return comments, nil
+}
+
+func (mw *EventsMiddleware) GetComment(ctx context.Context, commentID string) (*Comment, error) {
+	comment, err := mw.next.GetComment(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return comment, nil
+}
+
+func (mw *EventsMiddleware) CountComments(ctx context.Context, postID string) (int, error) {
+	count, err := mw.next.CountComments(ctx, postID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return count, nil
+}
+
+func (mw *EventsMiddleware) UpdateComment(
+	ctx context.Context,
+	req UpdateCommentRequest,
+) (*Comment, bool, error) {
+	var (
+		comment *Comment
+		changed bool
+	)
+
+	err := mw.bus.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+
+		comment, changed, err = mw.next.UpdateComment(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to call next method: %w", err)
+		}
+
+		if !changed {
+			return nil
+		}
+
+		err = mw.bus.Publish(ctx, events.CommentUpdated{
+			CommentID: comment.ID,
+			PostID:    comment.PostID,
+			AuthorID:  comment.AuthorID,
+			EditedAt:  *comment.EditedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to publish event: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	return comment, changed, nil
+}
+
+func (mw *EventsMiddleware) DeleteComment(ctx context.Context, commentID string) (*Comment, error) {
+	var comment *Comment
+
+	err := mw.bus.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+
+		comment, err = mw.next.DeleteComment(ctx, commentID)
+		if err != nil {
+			return fmt.Errorf("failed to call next method: %w", err)
+		}
+
+		err = mw.bus.Publish(ctx, events.CommentDeleted{
+			CommentID: comment.ID,
+			PostID:    comment.PostID,
+			AuthorID:  comment.AuthorID,
+			DeletedAt: *comment.DeletedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to publish event: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	return comment, nil
+}
+
+func (mw *EventsMiddleware) ListCommentEdits(ctx context.Context, commentID string) ([]*CommentEdit, error) {
+	edits, err := mw.next.ListCommentEdits(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return edits, nil
+}
@@ -0,0 +1,111 @@
+package discuss
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nasermirzaei89/scribble/events"
+)
+
+// RankingConfig weighs the reactions to comments.
+type RankingConfig struct {
+	// EmojiWeights are the points a reaction with the emoji adds to the reaction score of a comment. Emojis not in it
+	// add DefaultEmojiWeight.
+	EmojiWeights       map[string]int
+	DefaultEmojiWeight int
+}
+
+// DefaultRankingConfig counts a thumbs down against the comment and every other reaction for it.
+func DefaultRankingConfig() RankingConfig {
+	return RankingConfig{
+		EmojiWeights:       map[string]int{"👎": -1},
+		DefaultEmojiWeight: 1,
+	}
+}
+
+func (cfg RankingConfig) reactionScore(reactionCounts map[string]int) int {
+	score := 0
+
+	for emoji, count := range reactionCounts {
+		weight, ok := cfg.EmojiWeights[emoji]
+		if !ok {
+			weight = cfg.DefaultEmojiWeight
+		}
+
+		score += weight * count
+	}
+
+	return score
+}
+
+// CommentRankingRepository counts the reactions that rank comments and keeps their scores.
+type CommentRankingRepository interface {
+	CountReactions(ctx context.Context, commentID string) (reactionCounts map[string]int, err error)
+	SaveRank(ctx context.Context, commentID string, reactionScore int) (err error)
+	ListCommentIDs(ctx context.Context) (commentIDs []string, err error)
+}
+
+// commentTargetType is the target type of reactions to comments, reactions.TargetTypeComment, which imports this
+// package.
+const commentTargetType = "comment"
+
+// Ranker keeps the reaction scores of comments up to date. Each reaction reranks the one comment it changes.
+type Ranker struct {
+	cfg         RankingConfig
+	rankingRepo CommentRankingRepository
+}
+
+func NewRanker(cfg RankingConfig, rankingRepo CommentRankingRepository) *Ranker {
+	if cfg.EmojiWeights == nil {
+		cfg.EmojiWeights = DefaultRankingConfig().EmojiWeights
+	}
+
+	return &Ranker{
+		cfg:         cfg,
+		rankingRepo: rankingRepo,
+	}
+}
+
+func (ranker *Ranker) Subscribe(bus *events.Bus) {
+	events.SubscribeAsync(bus, ranker.handleReactionToggled)
+}
+
+func (ranker *Ranker) handleReactionToggled(ctx context.Context, event events.ReactionToggled) error {
+	if event.TargetType != commentTargetType {
+		return nil
+	}
+
+	return ranker.Rerank(ctx, event.TargetID)
+}
+
+// Rerank scores the comment from scratch and saves the score. Comments that are gone are left alone.
+func (ranker *Ranker) Rerank(ctx context.Context, commentID string) error {
+	reactionCounts, err := ranker.rankingRepo.CountReactions(ctx, commentID)
+	if err != nil {
+		return fmt.Errorf("failed to count reactions: %w", err)
+	}
+
+	err = ranker.rankingRepo.SaveRank(ctx, commentID, ranker.cfg.reactionScore(reactionCounts))
+	if err != nil {
+		return fmt.Errorf("failed to save rank: %w", err)
+	}
+
+	return nil
+}
+
+// RerankAll scores every comment, like after the weights changed, and returns how many were scored.
+func (ranker *Ranker) RerankAll(ctx context.Context) (int, error) {
+	commentIDs, err := ranker.rankingRepo.ListCommentIDs(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list comment ids: %w", err)
+	}
+
+	for _, commentID := range commentIDs {
+		err := ranker.Rerank(ctx, commentID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to rerank comment %q: %w", commentID, err)
+		}
+	}
+
+	return len(commentIDs), nil
+}
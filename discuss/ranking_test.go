@@ -0,0 +1,178 @@
+package discuss_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/authentication"
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/database/sqlite3"
+	"github.com/nasermirzaei89/scribble/discuss"
+	"github.com/nasermirzaei89/scribble/events"
+	"github.com/nasermirzaei89/scribble/reactions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRanker(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sqlite3.NewDB(ctx, "file:TestDiscussRanker?mode=memory&cache=shared")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := db.Close()
+		require.NoError(t, err)
+	})
+
+	err = sqlite3.MigrateUp(ctx, db)
+	require.NoError(t, err)
+
+	userRepo := sqlite3.NewUserRepository(db)
+	postRepo := sqlite3.NewPostRepository(db)
+	commentRepo := sqlite3.NewCommentRepository(db)
+	commentEditRepo := sqlite3.NewCommentEditRepository(db)
+
+	bus := events.NewBus(sqlite3.NewEventOutboxRepository(db), sqlite3.NewTransactor(db))
+
+	ranker := discuss.NewRanker(discuss.DefaultRankingConfig(), sqlite3.NewCommentRankingRepository(db))
+	ranker.Subscribe(bus)
+
+	for _, username := range []string{"alice", "bob", "carol"} {
+		err := userRepo.Insert(ctx, &authentication.User{
+			ID:           username,
+			Username:     username,
+			PasswordHash: "hash",
+			RegisteredAt: time.Now(),
+		})
+		require.NoError(t, err)
+	}
+
+	targets := reactions.NewTargetRegistry()
+
+	err = targets.Register(reactions.CommentTargetType(commentRepo))
+	require.NoError(t, err)
+
+	discussSvc := discuss.NewEventsMiddleware(bus, discuss.NewBaseService(commentRepo, commentEditRepo))
+	reactionsSvc := reactions.NewEventsMiddleware(bus, reactions.NewBaseService(
+		reactions.Config{DefaultEmojis: []string{"👍", "👎"}, MaxPerUser: 1},
+		targets,
+		sqlite3.NewUserReactionRepository(db),
+		sqlite3.NewReactionSetRepository(db),
+		postRepo,
+		commentRepo,
+		sqlite3.NewCustomEmojiRepository(db),
+	))
+
+	post := &contents.Post{
+		ID:            "post",
+		AuthorID:      "alice",
+		CommunityID:   "",
+		Content:       "post",
+		CreatedAt:     time.Now(),
+		ReactionScore: 0,
+		Hotness:       0,
+	}
+
+	err = postRepo.Insert(ctx, post)
+	require.NoError(t, err)
+
+	comment := func(content string) *discuss.Comment {
+		t.Helper()
+
+		comment, err := discussSvc.CreateComment(ctx, discuss.CreateCommentRequest{
+			PostID:   post.ID,
+			AuthorID: "alice",
+			Content:  content,
+			ReplyTo:  "",
+		})
+		require.NoError(t, err)
+
+		return comment
+	}
+
+	react := func(userID, commentID, emoji string) {
+		t.Helper()
+
+		err := reactionsSvc.ToggleMyReaction(
+			authcontext.WithSubject(ctx, userID),
+			reactions.TargetTypeComment,
+			commentID,
+			emoji,
+		)
+		require.NoError(t, err)
+
+		_, err = bus.ProcessOutbox(ctx)
+		require.NoError(t, err)
+	}
+
+	listIDs := func(sort discuss.CommentSort) []string {
+		t.Helper()
+
+		comments, err := discussSvc.ListComments(ctx, discuss.ListCommentsRequest{
+			PostID:   post.ID,
+			TopLevel: true,
+			ReplyTo:  nil,
+			Sort:     sort,
+			After:    nil,
+			Limit:    0,
+		})
+		require.NoError(t, err)
+
+		ids := make([]string, 0, len(comments))
+		for _, comment := range comments {
+			ids = append(ids, comment.ID)
+		}
+
+		return ids
+	}
+
+	first := comment("first")
+	second := comment("second")
+	third := comment("third")
+
+	t.Run("reactions rank comments", func(t *testing.T) {
+		react("bob", third.ID, "👍")
+		react("carol", third.ID, "👍")
+		react("bob", first.ID, "👎")
+
+		found, err := discussSvc.GetComment(ctx, third.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, found.ReactionScore)
+
+		found, err = discussSvc.GetComment(ctx, first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, -1, found.ReactionScore)
+
+		assert.Equal(t, []string{third.ID, second.ID, first.ID}, listIDs(discuss.CommentSortTop))
+		assert.Equal(t, []string{first.ID, second.ID, third.ID}, listIDs(""))
+	})
+
+	t.Run("rerank all", func(t *testing.T) {
+		err := commentRepo.Insert(ctx, &discuss.Comment{
+			ID:            "imported",
+			PostID:        post.ID,
+			AuthorID:      "alice",
+			ReplyTo:       nil,
+			Content:       "imported",
+			CreatedAt:     time.Now(),
+			ReactionScore: 10,
+		})
+		require.NoError(t, err)
+
+		count, err := ranker.RerankAll(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 4, count)
+
+		found, err := discussSvc.GetComment(ctx, "imported")
+		require.NoError(t, err)
+		assert.Equal(t, 0, found.ReactionScore)
+	})
+
+	t.Run("invalid sort", func(t *testing.T) {
+		_, err := discussSvc.ListComments(ctx, discuss.ListCommentsRequest{PostID: post.ID, Sort: "best"})
+		require.ErrorAs(t, err, &discuss.InvalidCommentSortError{})
+	})
+}
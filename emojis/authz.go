@@ -0,0 +1,94 @@
+package emojis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nasermirzaei89/scribble/authorization"
+)
+
+const (
+	ActionListCustomEmojis = "listCustomEmojis"
+	// ActionManageCustomEmojis lets admins add and delete custom emojis.
+	ActionManageCustomEmojis = "manageCustomEmojis"
+)
+
+type AuthorizationMiddleware struct {
+	authzClient *authorization.Client
+	next        Service
+}
+
+var _ Service = (*AuthorizationMiddleware)(nil)
+
+func NewAuthorizationMiddleware(authzClient *authorization.Client, next Service) *AuthorizationMiddleware {
+	return &AuthorizationMiddleware{
+		authzClient: authzClient,
+		next:        next,
+	}
+}
+
+func (mw *AuthorizationMiddleware) ListCustomEmojis(ctx context.Context) ([]*CustomEmoji, error) {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, "", ActionListCustomEmojis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	emojis, err := mw.next.ListCustomEmojis(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return emojis, nil
+}
+
+func (mw *AuthorizationMiddleware) GetCustomEmoji(ctx context.Context, shortcode string) (*CustomEmoji, error) {
+	emoji, err := mw.next.GetCustomEmoji(ctx, shortcode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return emoji, nil
+}
+
+func (mw *AuthorizationMiddleware) GetCustomEmojiImage(
+	ctx context.Context,
+	shortcode string,
+) (*CustomEmoji, []byte, error) {
+	emoji, image, err := mw.next.GetCustomEmojiImage(ctx, shortcode)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return emoji, image, nil
+}
+
+func (mw *AuthorizationMiddleware) CreateCustomEmoji(
+	ctx context.Context,
+	req CreateCustomEmojiRequest,
+) (*CustomEmoji, error) {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, "", ActionManageCustomEmojis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	emoji, err := mw.next.CreateCustomEmoji(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return emoji, nil
+}
+
+func (mw *AuthorizationMiddleware) DeleteCustomEmoji(ctx context.Context, shortcode string) error {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, "", ActionManageCustomEmojis)
+	if err != nil {
+		return fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	err = mw.next.DeleteCustomEmoji(ctx, shortcode)
+	if err != nil {
+		return fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package emojis_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	fileadapter "github.com/casbin/casbin/v3/persist/file-adapter"
+	"github.com/google/uuid"
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/authorization/casbin"
+	"github.com/nasermirzaei89/scribble/emojis"
+	"github.com/stretchr/testify/require"
+)
+
+type stubService struct{}
+
+func (s *stubService) ListCustomEmojis(ctx context.Context) ([]*emojis.CustomEmoji, error) {
+	return []*emojis.CustomEmoji{}, nil
+}
+
+func (s *stubService) GetCustomEmoji(ctx context.Context, shortcode string) (*emojis.CustomEmoji, error) {
+	return &emojis.CustomEmoji{}, nil
+}
+
+func (s *stubService) GetCustomEmojiImage(
+	ctx context.Context,
+	shortcode string,
+) (*emojis.CustomEmoji, []byte, error) {
+	return &emojis.CustomEmoji{}, []byte{}, nil
+}
+
+func (s *stubService) CreateCustomEmoji(
+	ctx context.Context,
+	req emojis.CreateCustomEmojiRequest,
+) (*emojis.CustomEmoji, error) {
+	return &emojis.CustomEmoji{}, nil
+}
+
+func (s *stubService) DeleteCustomEmoji(ctx context.Context, shortcode string) error {
+	return nil
+}
+
+func TestAuthorizationMiddleware(t *testing.T) {
+	ctx := context.Background()
+
+	tmpDir := t.TempDir()
+	tmpFile := filepath.Join(tmpDir, "policy.csv")
+	content := []byte(`g, system:anonymous, system:unauthenticated, *
+
+p, system:group:root, *, *, *
+
+p, system:authenticated, github.com/nasermirzaei89/scribble/emojis, -, listCustomEmojis
+p, system:unauthenticated, github.com/nasermirzaei89/scribble/emojis, -, listCustomEmojis
+`)
+
+	err := os.WriteFile(tmpFile, content, 0o600)
+	require.NoError(t, err)
+
+	adapter := fileadapter.NewAdapter(tmpFile)
+
+	provider, err := casbin.NewAuthorizationProvider(adapter)
+	require.NoError(t, err)
+
+	authzSvc, err := authorization.NewService(provider)
+	require.NoError(t, err)
+
+	client := authorization.NewClient(authzSvc)
+	svc := emojis.NewAuthorizationMiddleware(client, &stubService{})
+
+	userID := uuid.NewString()
+	err = client.AddToGroup(ctx, userID, authcontext.Authenticated)
+	require.NoError(t, err)
+
+	rootID := uuid.NewString()
+	err = client.AddToGroup(ctx, rootID, authcontext.Authenticated, "system:group:root")
+	require.NoError(t, err)
+
+	t.Run("anonymous", func(t *testing.T) {
+		_, err := svc.ListCustomEmojis(ctx)
+		require.NoError(t, err)
+
+		_, _, err = svc.GetCustomEmojiImage(ctx, "wave")
+		require.NoError(t, err)
+
+		_, err = svc.CreateCustomEmoji(ctx, emojis.CreateCustomEmojiRequest{Shortcode: "wave", Image: nil})
+		require.Error(t, err)
+
+		accessDeniedErr := &authorization.AccessDeniedError{}
+		require.ErrorAs(t, err, &accessDeniedErr)
+	})
+
+	t.Run("authenticated", func(t *testing.T) {
+		ctx := authcontext.WithSubject(ctx, userID)
+
+		_, err := svc.ListCustomEmojis(ctx)
+		require.NoError(t, err)
+
+		err = svc.DeleteCustomEmoji(ctx, "wave")
+		require.Error(t, err)
+
+		accessDeniedErr := &authorization.AccessDeniedError{}
+		require.ErrorAs(t, err, &accessDeniedErr)
+	})
+
+	t.Run("root", func(t *testing.T) {
+		ctx := authcontext.WithSubject(ctx, rootID)
+
+		_, err := svc.CreateCustomEmoji(ctx, emojis.CreateCustomEmojiRequest{Shortcode: "wave", Image: nil})
+		require.NoError(t, err)
+
+		err = svc.DeleteCustomEmoji(ctx, "wave")
+		require.NoError(t, err)
+	})
+}
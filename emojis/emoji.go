@@ -0,0 +1,100 @@
+package emojis
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// MaxImageSize is how large the image of a custom emoji can be, in bytes.
+const MaxImageSize = 256 << 10
+
+// shortcodePattern matches the shortcodes custom emojis are referred to by, like wave in :wave:.
+var shortcodePattern = regexp.MustCompile(`^[a-z0-9_+-]{2,32}$`)
+
+// ContentTypes returns the image types custom emojis can have.
+func ContentTypes() []string {
+	return []string{"image/png", "image/gif", "image/webp"}
+}
+
+// CustomEmoji is an image users can use like an emoji, by its shortcode surrounded by colons.
+type CustomEmoji struct {
+	Shortcode   string
+	ContentType string
+	CreatedBy   string
+	CreatedAt   time.Time
+}
+
+// Reference returns how content and reactions refer to the emoji, like :wave:.
+func (emoji *CustomEmoji) Reference() string {
+	return Reference(emoji.Shortcode)
+}
+
+// Reference returns how content and reactions refer to the custom emoji with the shortcode.
+func Reference(shortcode string) string {
+	return ":" + shortcode + ":"
+}
+
+// ParseReference returns the shortcode of the custom emoji the text refers to, if it refers to one.
+func ParseReference(text string) (string, bool) {
+	if len(text) < 2 || text[0] != ':' || text[len(text)-1] != ':' {
+		return "", false
+	}
+
+	shortcode := text[1 : len(text)-1]
+	if !IsValidShortcode(shortcode) {
+		return "", false
+	}
+
+	return shortcode, true
+}
+
+func IsValidShortcode(shortcode string) bool {
+	return shortcodePattern.MatchString(shortcode)
+}
+
+// ImageKey returns the key of the image of the custom emoji in the blob store.
+func ImageKey(shortcode string) string {
+	return "emojis/" + shortcode
+}
+
+type CustomEmojiRepository interface {
+	Insert(ctx context.Context, emoji *CustomEmoji) (err error)
+	Find(ctx context.Context, shortcode string) (emoji *CustomEmoji, err error)
+	// List lists the custom emojis by shortcode.
+	List(ctx context.Context) (emojis []*CustomEmoji, err error)
+	Delete(ctx context.Context, shortcode string) (err error)
+}
+
+type CustomEmojiNotFoundError struct {
+	Shortcode string
+}
+
+func (err CustomEmojiNotFoundError) Error() string {
+	return fmt.Sprintf("custom emoji %q not found", err.Shortcode)
+}
+
+type CustomEmojiExistsError struct {
+	Shortcode string
+}
+
+func (err CustomEmojiExistsError) Error() string {
+	return fmt.Sprintf("custom emoji %q already exists", err.Shortcode)
+}
+
+type InvalidShortcodeError struct {
+	Shortcode string
+}
+
+func (err InvalidShortcodeError) Error() string {
+	return fmt.Sprintf("invalid shortcode: %q", err.Shortcode)
+}
+
+type InvalidImageError struct {
+	Reason string
+}
+
+func (err InvalidImageError) Error() string {
+	return "invalid emoji image: " + err.Reason
+}
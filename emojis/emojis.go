@@ -0,0 +1,163 @@
+package emojis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/blobs"
+)
+
+const ServiceName = "github.com/nasermirzaei89/scribble/emojis"
+
+// Service manages the custom emojis of the site. Their images are kept in a blob store.
+type Service interface {
+	ListCustomEmojis(ctx context.Context) ([]*CustomEmoji, error)
+	GetCustomEmoji(ctx context.Context, shortcode string) (*CustomEmoji, error)
+	// GetCustomEmojiImage returns the emoji with its image, whose type is the content type of the emoji.
+	GetCustomEmojiImage(ctx context.Context, shortcode string) (*CustomEmoji, []byte, error)
+	CreateCustomEmoji(ctx context.Context, req CreateCustomEmojiRequest) (*CustomEmoji, error)
+	// DeleteCustomEmoji deletes the emoji and its image. Reactions with it are kept, like those with retired emojis.
+	DeleteCustomEmoji(ctx context.Context, shortcode string) error
+}
+
+type BaseService struct {
+	emojiRepo CustomEmojiRepository
+	blobStore blobs.Store
+}
+
+var _ Service = (*BaseService)(nil)
+
+func NewService( //nolint:ireturn
+	emojiRepo CustomEmojiRepository,
+	blobStore blobs.Store,
+	authzClient *authorization.Client,
+) Service {
+	return NewAuthorizationMiddleware(authzClient, NewBaseService(emojiRepo, blobStore))
+}
+
+func NewBaseService(emojiRepo CustomEmojiRepository, blobStore blobs.Store) *BaseService {
+	return &BaseService{
+		emojiRepo: emojiRepo,
+		blobStore: blobStore,
+	}
+}
+
+func (svc *BaseService) ListCustomEmojis(ctx context.Context) ([]*CustomEmoji, error) {
+	emojis, err := svc.emojiRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list custom emojis: %w", err)
+	}
+
+	return emojis, nil
+}
+
+func (svc *BaseService) GetCustomEmoji(ctx context.Context, shortcode string) (*CustomEmoji, error) {
+	emoji, err := svc.emojiRepo.Find(ctx, shortcode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find custom emoji: %w", err)
+	}
+
+	return emoji, nil
+}
+
+func (svc *BaseService) GetCustomEmojiImage(ctx context.Context, shortcode string) (*CustomEmoji, []byte, error) {
+	emoji, err := svc.emojiRepo.Find(ctx, shortcode)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find custom emoji: %w", err)
+	}
+
+	image, err := svc.blobStore.Get(ctx, ImageKey(shortcode))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get custom emoji image: %w", err)
+	}
+
+	return emoji, image, nil
+}
+
+type CreateCustomEmojiRequest struct {
+	Shortcode string
+	Image     []byte
+}
+
+// CreateCustomEmoji adds an emoji with the image, which must be a PNG, GIF or WebP of at most MaxImageSize bytes.
+func (svc *BaseService) CreateCustomEmoji(ctx context.Context, req CreateCustomEmojiRequest) (*CustomEmoji, error) {
+	if !IsValidShortcode(req.Shortcode) {
+		return nil, InvalidShortcodeError{Shortcode: req.Shortcode}
+	}
+
+	if len(req.Image) == 0 {
+		return nil, InvalidImageError{Reason: "no image"}
+	}
+
+	if len(req.Image) > MaxImageSize {
+		return nil, InvalidImageError{Reason: fmt.Sprintf("larger than %d KiB", MaxImageSize>>10)}
+	}
+
+	// The type is sniffed from the image itself, as it is what browsers go by.
+	contentType := http.DetectContentType(req.Image)
+	if !slices.Contains(ContentTypes(), contentType) {
+		return nil, InvalidImageError{Reason: "not a PNG, GIF or WebP image"}
+	}
+
+	_, err := svc.emojiRepo.Find(ctx, req.Shortcode)
+	if err == nil {
+		return nil, CustomEmojiExistsError{Shortcode: req.Shortcode}
+	}
+
+	if _, ok := errors.AsType[CustomEmojiNotFoundError](err); !ok {
+		return nil, fmt.Errorf("failed to find custom emoji: %w", err)
+	}
+
+	emoji := &CustomEmoji{
+		Shortcode:   req.Shortcode,
+		ContentType: contentType,
+		CreatedBy:   authcontext.GetSubject(ctx),
+		CreatedAt:   time.Now(),
+	}
+
+	// The image is stored first, so emojis never refer to missing images.
+	err = svc.blobStore.Put(ctx, ImageKey(emoji.Shortcode), req.Image)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store custom emoji image: %w", err)
+	}
+
+	err = svc.emojiRepo.Insert(ctx, emoji)
+	if err != nil {
+		svc.deleteImage(ctx, emoji.Shortcode)
+
+		return nil, fmt.Errorf("failed to insert custom emoji: %w", err)
+	}
+
+	return emoji, nil
+}
+
+func (svc *BaseService) DeleteCustomEmoji(ctx context.Context, shortcode string) error {
+	_, err := svc.emojiRepo.Find(ctx, shortcode)
+	if err != nil {
+		return fmt.Errorf("failed to find custom emoji: %w", err)
+	}
+
+	err = svc.emojiRepo.Delete(ctx, shortcode)
+	if err != nil {
+		return fmt.Errorf("failed to delete custom emoji: %w", err)
+	}
+
+	svc.deleteImage(ctx, shortcode)
+
+	return nil
+}
+
+// deleteImage deletes the image of an emoji. Failures only leave an unused blob behind, so they are logged.
+func (svc *BaseService) deleteImage(ctx context.Context, shortcode string) {
+	err := svc.blobStore.Delete(ctx, ImageKey(shortcode))
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to delete custom emoji image", "shortcode", shortcode, "error", err)
+	}
+}
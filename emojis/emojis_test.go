@@ -0,0 +1,96 @@
+package emojis_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/blobs"
+	"github.com/nasermirzaei89/scribble/database/sqlite3"
+	"github.com/nasermirzaei89/scribble/emojis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pngImage starts like a PNG, which is all content sniffing looks at.
+var pngImage = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestCustomEmojis(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sqlite3.NewDB(ctx, "file:TestCustomEmojis?mode=memory&cache=shared")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := db.Close()
+		require.NoError(t, err)
+	})
+
+	err = sqlite3.MigrateUp(ctx, db)
+	require.NoError(t, err)
+
+	blobStore := blobs.NewLocalStore(t.TempDir())
+	svc := emojis.NewBaseService(sqlite3.NewCustomEmojiRepository(db), blobStore)
+
+	ctx = authcontext.WithSubject(ctx, "admin")
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := svc.CreateCustomEmoji(ctx, emojis.CreateCustomEmojiRequest{Shortcode: "Wave!", Image: pngImage})
+		require.ErrorAs(t, err, &emojis.InvalidShortcodeError{})
+
+		_, err = svc.CreateCustomEmoji(ctx, emojis.CreateCustomEmojiRequest{Shortcode: "wave", Image: nil})
+		require.ErrorAs(t, err, &emojis.InvalidImageError{})
+
+		_, err = svc.CreateCustomEmoji(ctx, emojis.CreateCustomEmojiRequest{
+			Shortcode: "wave",
+			Image:     []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"),
+		})
+		require.ErrorAs(t, err, &emojis.InvalidImageError{})
+
+		_, err = svc.CreateCustomEmoji(ctx, emojis.CreateCustomEmojiRequest{
+			Shortcode: "wave",
+			Image:     append(bytes.Clone(pngImage), make([]byte, emojis.MaxImageSize)...),
+		})
+		require.ErrorAs(t, err, &emojis.InvalidImageError{})
+
+		list, err := svc.ListCustomEmojis(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, list)
+	})
+
+	t.Run("create", func(t *testing.T) {
+		emoji, err := svc.CreateCustomEmoji(ctx, emojis.CreateCustomEmojiRequest{Shortcode: "wave", Image: pngImage})
+		require.NoError(t, err)
+		assert.Equal(t, "image/png", emoji.ContentType)
+		assert.Equal(t, "admin", emoji.CreatedBy)
+		assert.Equal(t, ":wave:", emoji.Reference())
+
+		_, err = svc.CreateCustomEmoji(ctx, emojis.CreateCustomEmojiRequest{Shortcode: "wave", Image: pngImage})
+		require.ErrorAs(t, err, &emojis.CustomEmojiExistsError{})
+
+		found, image, err := svc.GetCustomEmojiImage(ctx, "wave")
+		require.NoError(t, err)
+		assert.Equal(t, "image/png", found.ContentType)
+		assert.Equal(t, pngImage, image)
+
+		list, err := svc.ListCustomEmojis(ctx)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, "wave", list[0].Shortcode)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		err := svc.DeleteCustomEmoji(ctx, "wave")
+		require.NoError(t, err)
+
+		_, err = svc.GetCustomEmoji(ctx, "wave")
+		require.ErrorAs(t, err, &emojis.CustomEmojiNotFoundError{})
+
+		_, err = blobStore.Get(ctx, emojis.ImageKey("wave"))
+		require.ErrorAs(t, err, &blobs.BlobNotFoundError{})
+
+		err = svc.DeleteCustomEmoji(ctx, "wave")
+		require.ErrorAs(t, err, &emojis.CustomEmojiNotFoundError{})
+	})
+}
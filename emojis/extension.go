@@ -0,0 +1,123 @@
+package emojis
+
+import (
+	"regexp"
+	"unicode"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// referencePattern matches a custom emoji reference at the start of the line.
+var referencePattern = regexp.MustCompile(`^:([a-z0-9_+-]{2,32}):`)
+
+// Resolver resolves the shortcodes of custom emojis to the URLs of their images.
+type Resolver interface {
+	ResolveEmoji(shortcode string) (url string, ok bool)
+}
+
+var KindCustomEmoji = ast.NewNodeKind("CustomEmoji")
+
+// Node is a custom emoji in Markdown content.
+type Node struct {
+	ast.BaseInline
+
+	Shortcode string
+	URL       string
+}
+
+func (node *Node) Kind() ast.NodeKind {
+	return KindCustomEmoji
+}
+
+func (node *Node) Dump(source []byte, level int) {
+	ast.DumpHelper(node, source, level, map[string]string{"Shortcode": node.Shortcode, "URL": node.URL}, nil)
+}
+
+type emojiParser struct {
+	resolver Resolver
+}
+
+func (p *emojiParser) Trigger() []byte {
+	return []byte{':'}
+}
+
+func (p *emojiParser) Parse(_ ast.Node, block text.Reader, _ parser.Context) ast.Node {
+	// References start words, so times like 10:30: and the like are left alone.
+	before := block.PrecendingCharacter()
+	if unicode.IsLetter(before) || unicode.IsNumber(before) {
+		return nil
+	}
+
+	line, _ := block.PeekLine()
+
+	match := referencePattern.FindSubmatch(line)
+	if match == nil {
+		return nil
+	}
+
+	url, ok := p.resolver.ResolveEmoji(string(match[1]))
+	if !ok {
+		return nil
+	}
+
+	block.Advance(len(match[0]))
+
+	return &Node{
+		BaseInline: ast.BaseInline{},
+		Shortcode:  string(match[1]),
+		URL:        url,
+	}
+}
+
+type emojiRenderer struct{}
+
+var _ renderer.NodeRenderer = (*emojiRenderer)(nil)
+
+func (r *emojiRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindCustomEmoji, r.renderEmoji)
+}
+
+func (r *emojiRenderer) renderEmoji(w util.BufWriter, _ []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	node, _ := n.(*Node)
+	reference := util.EscapeHTML([]byte(Reference(node.Shortcode)))
+
+	_, _ = w.WriteString(`<img src="`)
+	_, _ = w.Write(util.EscapeHTML(util.URLEscape([]byte(node.URL), true)))
+	_, _ = w.WriteString(`" alt="`)
+	_, _ = w.Write(reference)
+	_, _ = w.WriteString(`" title="`)
+	_, _ = w.Write(reference)
+	_, _ = w.WriteString(`" class="custom-emoji">`)
+
+	return ast.WalkSkipChildren, nil
+}
+
+// Extension renders :shortcode: references to custom emojis as their images. References the resolver does not
+// resolve stay plain text.
+type Extension struct {
+	resolver Resolver
+}
+
+var _ goldmark.Extender = (*Extension)(nil)
+
+func NewExtension(resolver Resolver) *Extension {
+	return &Extension{resolver: resolver}
+}
+
+func (e *Extension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithInlineParsers(
+		util.Prioritized(&emojiParser{resolver: e.resolver}, 500),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&emojiRenderer{}, 500),
+	))
+}
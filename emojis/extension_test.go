@@ -0,0 +1,81 @@
+package emojis_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nasermirzaei89/scribble/emojis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+type stubResolver map[string]string
+
+func (resolver stubResolver) ResolveEmoji(shortcode string) (string, bool) {
+	url, ok := resolver[shortcode]
+
+	return url, ok
+}
+
+func TestExtension(t *testing.T) {
+	md := goldmark.New(goldmark.WithExtensions(
+		extension.GFM,
+		emojis.NewExtension(stubResolver{"wave": "/emojis/wave", "party_parrot": "/emojis/party_parrot"}),
+	))
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "known emoji",
+			content:  "Hi :wave:!",
+			expected: `<p>Hi <img src="/emojis/wave" alt=":wave:" title=":wave:" class="custom-emoji">!</p>`,
+		},
+		{
+			name:    "adjacent emojis",
+			content: ":wave::party_parrot:",
+			expected: `<p><img src="/emojis/wave" alt=":wave:" title=":wave:" class="custom-emoji">` +
+				`<img src="/emojis/party_parrot" alt=":party_parrot:" title=":party_parrot:" class="custom-emoji"></p>`,
+		},
+		{
+			name:     "unknown emoji",
+			content:  "Hi :carol:",
+			expected: `<p>Hi :carol:</p>`,
+		},
+		{
+			name:     "within a word",
+			content:  "ratio 1:wave:",
+			expected: `<p>ratio 1:wave:</p>`,
+		},
+		{
+			name:     "code",
+			content:  "Type `:wave:`",
+			expected: `<p>Type <code>:wave:</code></p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := md.Convert([]byte(tt.content), &buf)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected+"\n", buf.String())
+		})
+	}
+}
+
+func TestParseReference(t *testing.T) {
+	shortcode, ok := emojis.ParseReference(":party_parrot:")
+	assert.True(t, ok)
+	assert.Equal(t, "party_parrot", shortcode)
+
+	for _, text := range []string{"👍", "party_parrot", ":Party:", "::", ":a:", ":wave"} {
+		_, ok := emojis.ParseReference(text)
+		assert.False(t, ok, text)
+	}
+}
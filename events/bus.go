@@ -0,0 +1,274 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nasermirzaei89/scribble/queue"
+)
+
+const (
+	maxDispatchAttempts = 10
+	dispatchBaseDelay   = 10 * time.Second
+	dispatchMaxDelay    = time.Hour
+	dispatchBatchSize   = 100
+
+	// DispatchPollInterval is how often the dispatcher checks the outbox for events it was not woken up for, like
+	// retries and events left by a crash.
+	DispatchPollInterval = 10 * time.Second
+)
+
+// dispatchBackoff doubles the wait after every failed attempt, starting at ten seconds.
+var dispatchBackoff = queue.Backoff{Base: dispatchBaseDelay, Max: dispatchMaxDelay}
+
+type (
+	syncHandler  func(ctx context.Context, event Event) error
+	asyncHandler func(ctx context.Context, payload []byte) error
+)
+
+type Bus struct {
+	outboxRepo OutboxRepository
+	transactor Transactor
+	now        func() time.Time
+
+	mu            sync.RWMutex
+	syncHandlers  map[string][]syncHandler
+	asyncHandlers map[string][]asyncHandler
+
+	waker *queue.Waker
+}
+
+func NewBus(outboxRepo OutboxRepository, transactor Transactor) *Bus {
+	return &Bus{
+		outboxRepo:    outboxRepo,
+		transactor:    transactor,
+		now:           time.Now,
+		mu:            sync.RWMutex{},
+		syncHandlers:  make(map[string][]syncHandler),
+		asyncHandlers: make(map[string][]asyncHandler),
+		waker:         queue.NewWaker(),
+	}
+}
+
+// Subscribe registers a synchronous handler for events of type E. It runs in the transaction of the change, which
+// fails with it, so it should be quick and must not wait on other database connections.
+func Subscribe[E Event](bus *Bus, handler func(ctx context.Context, event E) error) {
+	var zero E
+
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	name := zero.EventName()
+	bus.syncHandlers[name] = append(bus.syncHandlers[name], func(ctx context.Context, event Event) error {
+		typed, ok := event.(E)
+		if !ok {
+			return fmt.Errorf("unexpected type %T of event %q", event, name)
+		}
+
+		return handler(ctx, typed)
+	})
+}
+
+// SubscribeAsync registers an asynchronous handler for events of type E. It runs after the change is committed and
+// is retried with exponential backoff while it fails. Events are delivered at least once, to all asynchronous
+// handlers again on retries, so handlers must be idempotent.
+func SubscribeAsync[E Event](bus *Bus, handler func(ctx context.Context, event E) error) {
+	var zero E
+
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	name := zero.EventName()
+	bus.asyncHandlers[name] = append(bus.asyncHandlers[name], func(ctx context.Context, payload []byte) error {
+		var event E
+
+		err := json.Unmarshal(payload, &event)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal event %q: %w", name, err)
+		}
+
+		return handler(ctx, event)
+	})
+}
+
+type transactionContextKey struct{}
+
+func inTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(transactionContextKey{}).(bool)
+
+	return ok
+}
+
+// WithinTransaction runs fn in a transaction, so the events it publishes are stored with its changes. Calls within
+// fn join the transaction.
+func (bus *Bus) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if inTransaction(ctx) {
+		return fn(ctx)
+	}
+
+	err := bus.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		return fn(context.WithValue(ctx, transactionContextKey{}, true))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to run transaction: %w", err)
+	}
+
+	bus.waker.Wake()
+
+	return nil
+}
+
+// Publish runs the synchronous handlers of the events and stores those with asynchronous handlers in the outbox.
+// Within a transaction, the events are dispatched after it is committed.
+func (bus *Bus) Publish(ctx context.Context, events ...Event) error {
+	stored := false
+
+	for _, event := range events {
+		name := event.EventName()
+
+		bus.mu.RLock()
+		syncHandlers := bus.syncHandlers[name]
+		hasAsyncHandlers := len(bus.asyncHandlers[name]) > 0
+		bus.mu.RUnlock()
+
+		for _, handler := range syncHandlers {
+			err := handler(ctx, event)
+			if err != nil {
+				return fmt.Errorf("failed to handle event %q: %w", name, err)
+			}
+		}
+
+		if !hasAsyncHandlers {
+			continue
+		}
+
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to marshal event %q: %w", name, err)
+		}
+
+		now := bus.now()
+
+		err = bus.outboxRepo.Insert(ctx, &Record{
+			ID:            uuid.NewString(),
+			Name:          name,
+			Payload:       payload,
+			OccurredAt:    now,
+			Status:        RecordStatusPending,
+			Attempts:      0,
+			NextAttemptAt: now,
+			LastError:     "",
+		})
+		if err != nil {
+			return fmt.Errorf("failed to insert outbox record: %w", err)
+		}
+
+		stored = true
+	}
+
+	if stored && !inTransaction(ctx) {
+		bus.waker.Wake()
+	}
+
+	return nil
+}
+
+// ProcessOutbox dispatches due events to their asynchronous handlers. Dispatched events are removed from the outbox.
+// It returns how many events were processed.
+func (bus *Bus) ProcessOutbox(ctx context.Context) (int, error) {
+	records, err := bus.outboxRepo.ListDue(ctx, bus.now(), dispatchBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list due outbox records: %w", err)
+	}
+
+	for _, record := range records {
+		err = bus.dispatch(ctx, record)
+		if err == nil {
+			err = bus.outboxRepo.Delete(ctx, record.ID)
+			if err != nil {
+				return 0, fmt.Errorf("failed to delete outbox record %q: %w", record.ID, err)
+			}
+
+			continue
+		}
+
+		record.Attempts++
+		record.LastError = err.Error()
+
+		_, unknown := errors.AsType[UnknownEventError](err)
+		if unknown || record.Attempts >= maxDispatchAttempts {
+			record.Status = RecordStatusFailed
+
+			slog.ErrorContext(ctx, "failed to dispatch event", "recordId", record.ID, "name", record.Name,
+				"attempts", record.Attempts, "error", err)
+		} else {
+			record.NextAttemptAt = bus.now().Add(dispatchBackoff.Delay(record.Attempts))
+		}
+
+		err = bus.outboxRepo.Update(ctx, record)
+		if err != nil {
+			return 0, fmt.Errorf("failed to update outbox record %q: %w", record.ID, err)
+		}
+	}
+
+	return len(records), nil
+}
+
+// Metadata describes the stored event an asynchronous handler is called for.
+type Metadata struct {
+	// ID identifies the event. It is the same when the event is dispatched again.
+	ID         string
+	Name       string
+	OccurredAt time.Time
+}
+
+type metadataContextKey struct{}
+
+// MetadataFromContext returns the metadata of the event, in the context of asynchronous handlers.
+func MetadataFromContext(ctx context.Context) (Metadata, bool) {
+	metadata, ok := ctx.Value(metadataContextKey{}).(Metadata)
+
+	return metadata, ok
+}
+
+func (bus *Bus) dispatch(ctx context.Context, record *Record) error {
+	bus.mu.RLock()
+	handlers := bus.asyncHandlers[record.Name]
+	bus.mu.RUnlock()
+
+	if len(handlers) == 0 {
+		return UnknownEventError{Name: record.Name}
+	}
+
+	ctx = context.WithValue(ctx, metadataContextKey{}, Metadata{
+		ID:         record.ID,
+		Name:       record.Name,
+		OccurredAt: record.OccurredAt,
+	})
+
+	errs := make([]error, 0, len(handlers))
+
+	for _, handler := range handlers {
+		errs = append(errs, handler(ctx, record.Payload))
+	}
+
+	return errors.Join(errs...)
+}
+
+// RunDispatcher processes the outbox whenever events are published, and every DispatchPollInterval, until the
+// context is done.
+func (bus *Bus) RunDispatcher(ctx context.Context) {
+	queue.Worker{
+		Name:      "event outbox",
+		Interval:  DispatchPollInterval,
+		BatchSize: dispatchBatchSize,
+		Process:   bus.ProcessOutbox,
+		Waker:     bus.waker,
+	}.Run(ctx)
+}
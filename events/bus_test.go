@@ -0,0 +1,299 @@
+package events_test
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryOutbox keeps records in memory. Records inserted in a failed transaction are discarded with it.
+type memoryOutbox struct {
+	mu      sync.Mutex
+	records map[string]*events.Record
+}
+
+var _ events.OutboxRepository = (*memoryOutbox)(nil)
+
+func newMemoryOutbox() *memoryOutbox {
+	return &memoryOutbox{mu: sync.Mutex{}, records: make(map[string]*events.Record)}
+}
+
+func (outbox *memoryOutbox) Insert(_ context.Context, record *events.Record) error {
+	outbox.mu.Lock()
+	defer outbox.mu.Unlock()
+
+	stored := *record
+	outbox.records[record.ID] = &stored
+
+	return nil
+}
+
+func (outbox *memoryOutbox) Update(_ context.Context, record *events.Record) error {
+	return outbox.Insert(context.Background(), record)
+}
+
+func (outbox *memoryOutbox) Delete(_ context.Context, recordID string) error {
+	outbox.mu.Lock()
+	defer outbox.mu.Unlock()
+
+	delete(outbox.records, recordID)
+
+	return nil
+}
+
+func (outbox *memoryOutbox) ListDue(_ context.Context, now time.Time, _ int) ([]*events.Record, error) {
+	outbox.mu.Lock()
+	defer outbox.mu.Unlock()
+
+	result := make([]*events.Record, 0)
+
+	for _, record := range outbox.records {
+		if record.Status == events.RecordStatusPending && !record.NextAttemptAt.After(now) {
+			stored := *record
+			result = append(result, &stored)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b *events.Record) int {
+		return a.OccurredAt.Compare(b.OccurredAt)
+	})
+
+	return result, nil
+}
+
+func (outbox *memoryOutbox) list() []*events.Record {
+	outbox.mu.Lock()
+	defer outbox.mu.Unlock()
+
+	return slices.Collect(maps.Values(outbox.records))
+}
+
+// makeDue moves the next attempts of all records to the past, as if the backoff had passed.
+func (outbox *memoryOutbox) makeDue() {
+	outbox.mu.Lock()
+	defer outbox.mu.Unlock()
+
+	for _, record := range outbox.records {
+		record.NextAttemptAt = time.Now().Add(-time.Second)
+	}
+}
+
+// memoryTransactor rolls the outbox back when the function fails.
+type memoryTransactor struct {
+	outbox *memoryOutbox
+}
+
+func (transactor *memoryTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	transactor.outbox.mu.Lock()
+	snapshot := maps.Clone(transactor.outbox.records)
+	transactor.outbox.mu.Unlock()
+
+	err := fn(ctx)
+	if err != nil {
+		transactor.outbox.mu.Lock()
+		transactor.outbox.records = snapshot
+		transactor.outbox.mu.Unlock()
+
+		return err
+	}
+
+	return nil
+}
+
+func newTestBus() (*events.Bus, *memoryOutbox) {
+	outbox := newMemoryOutbox()
+
+	return events.NewBus(outbox, &memoryTransactor{outbox: outbox}), outbox
+}
+
+func TestSubscribe(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	bus, outbox := newTestBus()
+
+	var received []events.PostCreated
+
+	events.Subscribe(bus, func(_ context.Context, event events.PostCreated) error {
+		received = append(received, event)
+
+		return nil
+	})
+
+	err := bus.Publish(ctx, events.PostCreated{PostID: "post1", AuthorID: "user1"}, events.CommentCreated{})
+	require.NoError(t, err)
+
+	require.Len(t, received, 1)
+	assert.Equal(t, "post1", received[0].PostID)
+	assert.Empty(t, outbox.list(), "events without asynchronous subscribers are not stored")
+
+	t.Run("failing subscriber fails the transaction", func(t *testing.T) {
+		t.Parallel()
+
+		bus, outbox := newTestBus()
+		errVeto := errors.New("veto")
+
+		events.Subscribe(bus, func(context.Context, events.CommentCreated) error {
+			return errVeto
+		})
+		events.SubscribeAsync(bus, func(context.Context, events.CommentCreated) error {
+			return nil
+		})
+		events.SubscribeAsync(bus, func(context.Context, events.PostCreated) error {
+			return nil
+		})
+
+		err := bus.WithinTransaction(ctx, func(ctx context.Context) error {
+			err := bus.Publish(ctx, events.PostCreated{PostID: "post1"})
+			if err != nil {
+				return err
+			}
+
+			return bus.Publish(ctx, events.CommentCreated{CommentID: "comment1"})
+		})
+		require.ErrorIs(t, err, errVeto)
+
+		assert.Empty(t, outbox.list())
+	})
+}
+
+func TestSubscribeAsync(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	bus, outbox := newTestBus()
+
+	var (
+		received []events.ReactionToggled
+		fail     = true
+	)
+
+	events.SubscribeAsync(bus, func(ctx context.Context, event events.ReactionToggled) error {
+		metadata, ok := events.MetadataFromContext(ctx)
+		if !ok || metadata.Name != event.EventName() || metadata.ID == "" {
+			return errors.New("missing metadata")
+		}
+
+		if fail {
+			return errors.New("unavailable")
+		}
+
+		received = append(received, event)
+
+		return nil
+	})
+
+	err := bus.WithinTransaction(ctx, func(ctx context.Context) error {
+		return bus.Publish(ctx, events.ReactionToggled{
+			TargetType: "post",
+			TargetID:   "post1",
+			UserID:     "user1",
+			Emoji:      "👍",
+			Added:      true,
+		})
+	})
+	require.NoError(t, err)
+
+	records := outbox.list()
+	require.Len(t, records, 1)
+	assert.Equal(t, "reaction.toggled", records[0].Name)
+	assert.JSONEq(t,
+		`{"targetType":"post","targetId":"post1","userId":"user1","emoji":"👍","added":true}`,
+		string(records[0].Payload),
+	)
+
+	processed, err := bus.ProcessOutbox(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, processed)
+
+	records = outbox.list()
+	require.Len(t, records, 1)
+	assert.Equal(t, events.RecordStatusPending, records[0].Status)
+	assert.Equal(t, 1, records[0].Attempts)
+	assert.Equal(t, "unavailable", records[0].LastError)
+	assert.True(t, records[0].NextAttemptAt.After(time.Now()))
+
+	processed, err = bus.ProcessOutbox(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, processed, "retries wait for the backoff")
+
+	fail = false
+
+	outbox.makeDue()
+
+	_, err = bus.ProcessOutbox(ctx)
+	require.NoError(t, err)
+
+	require.Len(t, received, 1)
+	assert.Equal(t, events.ReactionToggled{
+		TargetType: "post",
+		TargetID:   "post1",
+		UserID:     "user1",
+		Emoji:      "👍",
+		Added:      true,
+	}, received[0])
+	assert.Empty(t, outbox.list(), "dispatched events are removed")
+
+	t.Run("gives up", func(t *testing.T) {
+		t.Parallel()
+
+		bus, outbox := newTestBus()
+
+		events.SubscribeAsync(bus, func(context.Context, events.UserRegistered) error {
+			return errors.New("unavailable")
+		})
+
+		err := bus.Publish(ctx, events.UserRegistered{UserID: "user1", Username: "alice"})
+		require.NoError(t, err)
+
+		for range 20 {
+			outbox.makeDue()
+
+			_, err = bus.ProcessOutbox(ctx)
+			require.NoError(t, err)
+		}
+
+		records := outbox.list()
+		require.Len(t, records, 1)
+		assert.Equal(t, events.RecordStatusFailed, records[0].Status)
+		assert.Equal(t, 10, records[0].Attempts)
+	})
+}
+
+func TestRunDispatcher(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus, _ := newTestBus()
+	received := make(chan events.CommunityCreated, 1)
+
+	events.SubscribeAsync(bus, func(_ context.Context, event events.CommunityCreated) error {
+		received <- event
+
+		return nil
+	})
+
+	go bus.RunDispatcher(ctx)
+
+	err := bus.WithinTransaction(ctx, func(ctx context.Context) error {
+		return bus.Publish(ctx, events.CommunityCreated{CommunityID: "community1", Slug: "go"})
+	})
+	require.NoError(t, err)
+
+	select {
+	case event := <-received:
+		assert.Equal(t, "go", event.Slug)
+	case <-time.After(time.Second):
+		require.Fail(t, "event was not dispatched after the commit")
+	}
+}
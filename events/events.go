@@ -0,0 +1,156 @@
+// Package events carries typed domain events from the services that make changes to the features reacting to them.
+//
+// Services publish events through their EventsMiddleware in the transaction of the change. Synchronous subscribers
+// run in that transaction and can veto the change by failing. Events with asynchronous subscribers are stored in an
+// outbox in the same transaction, so they survive a crash, and are dispatched by RunDispatcher after the commit.
+package events
+
+import (
+	"context"
+	"time"
+)
+
+// Event is a typed domain event. It is stored in the outbox as JSON.
+type Event interface {
+	// EventName identifies the type of the event in the outbox. It must not change once events are stored.
+	EventName() string
+}
+
+// Transactor runs functions in a database transaction.
+type Transactor interface {
+	// WithinTransaction runs fn in a transaction, which repositories pick up from the context fn is given. The
+	// transaction is committed if fn returns nil and rolled back otherwise. Nested calls join the outer transaction.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type PostCreated struct {
+	PostID      string    `json:"postId"`
+	AuthorID    string    `json:"authorId"`
+	CommunityID string    `json:"communityId,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+func (PostCreated) EventName() string {
+	return "post.created"
+}
+
+type CommentCreated struct {
+	CommentID string `json:"commentId"`
+	PostID    string `json:"postId"`
+	AuthorID  string `json:"authorId"`
+	// ReplyTo is the comment replied to, empty for comments on the post itself.
+	ReplyTo   string    `json:"replyTo,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (CommentCreated) EventName() string {
+	return "comment.created"
+}
+
+// CommentUpdated is published when the author of a comment edits its content.
+type CommentUpdated struct {
+	CommentID string    `json:"commentId"`
+	PostID    string    `json:"postId"`
+	AuthorID  string    `json:"authorId"`
+	EditedAt  time.Time `json:"editedAt"`
+}
+
+func (CommentUpdated) EventName() string {
+	return "comment.updated"
+}
+
+// CommentDeleted is published when a comment is replaced with a tombstone.
+type CommentDeleted struct {
+	CommentID string    `json:"commentId"`
+	PostID    string    `json:"postId"`
+	AuthorID  string    `json:"authorId"`
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
+func (CommentDeleted) EventName() string {
+	return "comment.deleted"
+}
+
+// ReactionToggled is published when a user adds or removes a reaction. Switching to another emoji is published
+// as its addition, replacing the previous one.
+type ReactionToggled struct {
+	TargetType string `json:"targetType"`
+	TargetID   string `json:"targetId"`
+	UserID     string `json:"userId"`
+	Emoji      string `json:"emoji"`
+	Added      bool   `json:"added"`
+}
+
+func (ReactionToggled) EventName() string {
+	return "reaction.toggled"
+}
+
+// UserMentioned is published for each user mentioned in a new post or comment.
+type UserMentioned struct {
+	MentionID string `json:"mentionId"`
+	UserID    string `json:"userId"`
+	AuthorID  string `json:"authorId"`
+	PostID    string `json:"postId"`
+	// CommentID is the comment the user is mentioned in, empty for mentions in the post itself.
+	CommentID string    `json:"commentId,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (UserMentioned) EventName() string {
+	return "user.mentioned"
+}
+
+type UserRegistered struct {
+	UserID   string `json:"userId"`
+	Username string `json:"username"`
+	// Remote tells users standing in for accounts on other servers apart.
+	Remote       bool      `json:"remote"`
+	RegisteredAt time.Time `json:"registeredAt"`
+}
+
+func (UserRegistered) EventName() string {
+	return "user.registered"
+}
+
+type CommunityCreated struct {
+	CommunityID string    `json:"communityId"`
+	Slug        string    `json:"slug"`
+	OwnerID     string    `json:"ownerId"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+func (CommunityCreated) EventName() string {
+	return "community.created"
+}
+
+type CommunityMemberJoined struct {
+	CommunityID string `json:"communityId"`
+	UserID      string `json:"userId"`
+	Role        string `json:"role"`
+}
+
+func (CommunityMemberJoined) EventName() string {
+	return "community.member_joined"
+}
+
+type CommunityMemberRoleChanged struct {
+	CommunityID string `json:"communityId"`
+	UserID      string `json:"userId"`
+	Role        string `json:"role"`
+}
+
+func (CommunityMemberRoleChanged) EventName() string {
+	return "community.member_role_changed"
+}
+
+// CommunityMemberLeft is published when a member leaves a community or is removed from it.
+type CommunityMemberLeft struct {
+	CommunityID string `json:"communityId"`
+	UserID      string `json:"userId"`
+	// Removed tells removals by moderators apart from members leaving.
+	Removed bool `json:"removed"`
+}
+
+func (CommunityMemberLeft) EventName() string {
+	return "community.member_left"
+}
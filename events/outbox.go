@@ -0,0 +1,42 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type RecordStatus string
+
+const (
+	RecordStatusPending RecordStatus = "pending"
+	RecordStatusFailed  RecordStatus = "failed"
+)
+
+// Record is an event stored in the outbox until its asynchronous subscribers handled it.
+type Record struct {
+	ID            string
+	Name          string
+	Payload       []byte
+	OccurredAt    time.Time
+	Status        RecordStatus
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+}
+
+type OutboxRepository interface {
+	Insert(ctx context.Context, record *Record) error
+	Update(ctx context.Context, record *Record) error
+	Delete(ctx context.Context, recordID string) error
+	// ListDue lists pending records due at the time, oldest first.
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*Record, error)
+}
+
+type UnknownEventError struct {
+	Name string
+}
+
+func (err UnknownEventError) Error() string {
+	return fmt.Sprintf("no subscriber for event %q", err.Name)
+}
@@ -0,0 +1,146 @@
+package federation
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	// ContentType is the media type of ActivityStreams documents.
+	ContentType = "application/activity+json"
+
+	// acceptHeader asks other servers for their ActivityStreams representation rather than HTML.
+	acceptHeader = ContentType + `, application/ld+json; profile="https://www.w3.org/ns/activitystreams"`
+
+	activityStreamsContext = "https://www.w3.org/ns/activitystreams"
+	securityContext        = "https://w3id.org/security/v1"
+
+	// PublicAddress addresses an activity to everyone.
+	PublicAddress = activityStreamsContext + "#Public"
+)
+
+const (
+	TypePerson            = "Person"
+	TypeNote              = "Note"
+	TypeTombstone         = "Tombstone"
+	TypeCreate            = "Create"
+	TypeDelete            = "Delete"
+	TypeFollow            = "Follow"
+	TypeAccept            = "Accept"
+	TypeUndo              = "Undo"
+	TypeOrderedCollection = "OrderedCollection"
+)
+
+type Actor struct {
+	Context           []string   `json:"@context"`
+	ID                string     `json:"id"`
+	Type              string     `json:"type"`
+	PreferredUsername string     `json:"preferredUsername"`
+	Name              string     `json:"name,omitempty"`
+	URL               string     `json:"url,omitempty"`
+	Inbox             string     `json:"inbox"`
+	Outbox            string     `json:"outbox,omitempty"`
+	Followers         string     `json:"followers,omitempty"`
+	Endpoints         *Endpoints `json:"endpoints,omitempty"`
+	PublicKey         *PublicKey `json:"publicKey,omitempty"`
+	Published         *time.Time `json:"published,omitempty"`
+}
+
+type Endpoints struct {
+	SharedInbox string `json:"sharedInbox,omitempty"`
+}
+
+type PublicKey struct {
+	ID           string `json:"id"`
+	Owner        string `json:"owner"`
+	PublicKeyPEM string `json:"publicKeyPem"`
+}
+
+type Note struct {
+	Context      []string  `json:"@context,omitempty"`
+	ID           string    `json:"id"`
+	Type         string    `json:"type"`
+	AttributedTo string    `json:"attributedTo"`
+	Content      string    `json:"content"`
+	MediaType    string    `json:"mediaType,omitempty"`
+	URL          string    `json:"url,omitempty"`
+	InReplyTo    string    `json:"inReplyTo,omitempty"`
+	Published    time.Time `json:"published"`
+	To           []string  `json:"to"`
+	Cc           []string  `json:"cc,omitempty"`
+}
+
+type Tombstone struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+// Activity is an outgoing activity. Object is an ID or an embedded object.
+type Activity struct {
+	Context   []string   `json:"@context,omitempty"`
+	ID        string     `json:"id"`
+	Type      string     `json:"type"`
+	Actor     string     `json:"actor"`
+	Published *time.Time `json:"published,omitempty"`
+	To        []string   `json:"to,omitempty"`
+	Cc        []string   `json:"cc,omitempty"`
+	Object    any        `json:"object"`
+}
+
+type OrderedCollection struct {
+	Context      []string `json:"@context"`
+	ID           string   `json:"id"`
+	Type         string   `json:"type"`
+	TotalItems   int      `json:"totalItems"`
+	OrderedItems []any    `json:"orderedItems"`
+}
+
+// WebFinger is a JSON Resource Descriptor (RFC 7033) pointing a handle at its actor.
+type WebFinger struct {
+	Subject string          `json:"subject"`
+	Aliases []string        `json:"aliases,omitempty"`
+	Links   []WebFingerLink `json:"links"`
+}
+
+type WebFingerLink struct {
+	Rel  string `json:"rel"`
+	Type string `json:"type,omitempty"`
+	Href string `json:"href"`
+}
+
+// incomingObject is the part of a received activity or object this server reads. References are IDs or embedded
+// objects, so they are decoded lazily.
+type incomingObject struct {
+	ID           string          `json:"id"`
+	Type         string          `json:"type"`
+	Actor        json.RawMessage `json:"actor"`
+	AttributedTo json.RawMessage `json:"attributedTo"`
+	Object       json.RawMessage `json:"object"`
+	InReplyTo    json.RawMessage `json:"inReplyTo"`
+	Content      string          `json:"content"`
+}
+
+// referenceID returns the ID of a reference, which is either a string or an object with an id.
+func referenceID(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var id string
+
+	err := json.Unmarshal(raw, &id)
+	if err == nil {
+		return id
+	}
+
+	var object struct {
+		ID string `json:"id"`
+	}
+
+	err = json.Unmarshal(raw, &object)
+	if err != nil {
+		return ""
+	}
+
+	return object.ID
+}
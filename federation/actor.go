@@ -0,0 +1,264 @@
+package federation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nasermirzaei89/scribble/authentication"
+	"github.com/nasermirzaei89/scribble/contents"
+)
+
+const outboxSize = 20
+
+// localUser returns the local user with the username. Remote users have no actor here.
+func (svc *Service) localUser(ctx context.Context, username string) (*authentication.User, error) {
+	if authentication.IsRemoteUsername(username) {
+		return nil, ActorNotFoundError{Name: username}
+	}
+
+	user, err := svc.authSvc.GetUserByUsername(ctx, username)
+	if err != nil {
+		if _, ok := errors.AsType[*authentication.UserByUsernameNotFoundError](err); ok {
+			return nil, ActorNotFoundError{Name: username}
+		}
+
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// WebFinger resolves an acct: URI of a local user, or the actor ID itself, to the user's actor.
+func (svc *Service) WebFinger(ctx context.Context, resource string) (*WebFinger, error) {
+	username, ok := svc.localUsername(resource)
+	if !ok {
+		acct, isAcct := strings.CutPrefix(resource, "acct:")
+		if !isAcct {
+			return nil, ActorNotFoundError{Name: resource}
+		}
+
+		// Handles are sometimes written with a leading @.
+		name, host, found := strings.Cut(strings.TrimPrefix(acct, "@"), "@")
+		if !found || !strings.EqualFold(host, svc.host) {
+			return nil, ActorNotFoundError{Name: resource}
+		}
+
+		username = name
+	}
+
+	user, err := svc.localUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	actorID := svc.actorID(user.Username)
+
+	return &WebFinger{
+		Subject: "acct:" + user.Username + "@" + svc.host,
+		Aliases: []string{actorID},
+		Links: []WebFingerLink{
+			{Rel: "self", Type: ContentType, Href: actorID},
+		},
+	}, nil
+}
+
+// Actor returns the actor document of a local user.
+func (svc *Service) Actor(ctx context.Context, username string) (*Actor, error) {
+	user, err := svc.localUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := svc.userKey(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	actorID := svc.actorID(user.Username)
+
+	return &Actor{
+		Context:           []string{activityStreamsContext, securityContext},
+		ID:                actorID,
+		Type:              TypePerson,
+		PreferredUsername: user.Username,
+		Name:              user.Username,
+		URL:               actorID,
+		Inbox:             actorID + "/inbox",
+		Outbox:            actorID + "/outbox",
+		Followers:         actorID + "/followers",
+		Endpoints:         &Endpoints{SharedInbox: svc.sharedInbox()},
+		PublicKey: &PublicKey{
+			ID:           svc.keyID(user.Username),
+			Owner:        actorID,
+			PublicKeyPEM: key.PublicKeyPEM,
+		},
+		Published: &user.RegisteredAt,
+	}, nil
+}
+
+// Followers returns the size of a local user's followers collection. Followers are not listed.
+func (svc *Service) Followers(ctx context.Context, username string) (*OrderedCollection, error) {
+	user, err := svc.localUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := svc.followerRepo.Count(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count followers: %w", err)
+	}
+
+	return &OrderedCollection{
+		Context:      []string{activityStreamsContext},
+		ID:           svc.actorID(user.Username) + "/followers",
+		Type:         TypeOrderedCollection,
+		TotalItems:   count,
+		OrderedItems: []any{},
+	}, nil
+}
+
+// Outbox returns the latest posts of a local user as Create activities.
+func (svc *Service) Outbox(ctx context.Context, username string) (*OrderedCollection, error) {
+	user, err := svc.localUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := svc.contentsSvc.ListPosts(ctx, contents.ListPostsRequest{
+		AuthorID:    user.ID,
+		CommunityID: "",
+		Sort:        contents.PostSortNew,
+		Period:      contents.TopPeriodAll,
+		Before:      nil,
+		Limit:       outboxSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list posts: %w", err)
+	}
+
+	items := make([]any, 0, len(posts))
+
+	for _, post := range posts {
+		activity, err := svc.createActivity(user, post)
+		if err != nil {
+			return nil, err
+		}
+
+		activity.Context = nil
+		items = append(items, activity)
+	}
+
+	return &OrderedCollection{
+		Context:      []string{activityStreamsContext},
+		ID:           svc.actorID(user.Username) + "/outbox",
+		Type:         TypeOrderedCollection,
+		TotalItems:   len(items),
+		OrderedItems: items,
+	}, nil
+}
+
+// Note returns a local post as a note.
+func (svc *Service) Note(ctx context.Context, postID string) (*Note, error) {
+	post, err := svc.contentsSvc.GetPost(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+
+	author, err := svc.authSvc.GetUser(ctx, post.AuthorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post author: %w", err)
+	}
+
+	note, err := svc.note(author, post)
+	if err != nil {
+		return nil, err
+	}
+
+	note.Context = []string{activityStreamsContext}
+
+	return note, nil
+}
+
+func (svc *Service) note(author *authentication.User, post *contents.Post) (*Note, error) {
+	var content strings.Builder
+
+	err := svc.markdown.Convert([]byte(post.Content), &content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render post %s: %w", post.ID, err)
+	}
+
+	actorID := svc.actorID(author.Username)
+	objectID := svc.postID(post.ID)
+
+	return &Note{
+		Context:      nil,
+		ID:           objectID,
+		Type:         TypeNote,
+		AttributedTo: actorID,
+		Content:      content.String(),
+		MediaType:    "text/html",
+		URL:          objectID,
+		InReplyTo:    "",
+		Published:    post.CreatedAt,
+		To:           []string{PublicAddress},
+		Cc:           []string{actorID + "/followers"},
+	}, nil
+}
+
+func (svc *Service) createActivity(author *authentication.User, post *contents.Post) (*Activity, error) {
+	note, err := svc.note(author, post)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Activity{
+		Context:   []string{activityStreamsContext},
+		ID:        note.ID + "/activity",
+		Type:      TypeCreate,
+		Actor:     note.AttributedTo,
+		Published: &note.Published,
+		To:        note.To,
+		Cc:        note.Cc,
+		Object:    note,
+	}, nil
+}
+
+// PublishPost delivers a Create activity for a new post to the followers of its author.
+func (svc *Service) PublishPost(ctx context.Context, post *contents.Post) error {
+	author, err := svc.authSvc.GetUser(ctx, post.AuthorID)
+	if err != nil {
+		return fmt.Errorf("failed to get post author: %w", err)
+	}
+
+	activity, err := svc.createActivity(author, post)
+	if err != nil {
+		return err
+	}
+
+	return svc.enqueueToFollowers(ctx, author.ID, activity)
+}
+
+// PublishPostDeletion delivers a Delete activity for a removed post to the followers of its author, so their servers
+// replace it with a tombstone.
+func (svc *Service) PublishPostDeletion(ctx context.Context, post *contents.Post) error {
+	author, err := svc.authSvc.GetUser(ctx, post.AuthorID)
+	if err != nil {
+		return fmt.Errorf("failed to get post author: %w", err)
+	}
+
+	actorID := svc.actorID(author.Username)
+	objectID := svc.postID(post.ID)
+
+	return svc.enqueueToFollowers(ctx, author.ID, &Activity{
+		Context:   []string{activityStreamsContext},
+		ID:        objectID + "#delete",
+		Type:      TypeDelete,
+		Actor:     actorID,
+		Published: nil,
+		To:        []string{PublicAddress},
+		Cc:        []string{actorID + "/followers"},
+		Object:    &Tombstone{ID: objectID, Type: TypeTombstone},
+	})
+}
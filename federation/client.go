@@ -0,0 +1,55 @@
+package federation
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// defaultHTTPTimeout bounds requests to other servers.
+const defaultHTTPTimeout = 30 * time.Second
+
+// BlockedAddressError is returned for connections to addresses other servers may not make this server reach, like
+// loopback, private and link-local ones, where cloud metadata services listen.
+type BlockedAddressError struct {
+	Address string
+}
+
+func (err BlockedAddressError) Error() string {
+	return fmt.Sprintf("address %q is not public", err.Address)
+}
+
+// newHTTPClient returns the default client for fetching from and delivering to other servers. Their URLs come from
+// requests anyone can send, so it only connects to public addresses. The check runs on the resolved address of every
+// connection, redirects included, and proxies are not used since they would resolve the address themselves.
+func newHTTPClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   defaultHTTPTimeout,
+		KeepAlive: defaultHTTPTimeout,
+		Control:   refusePrivateAddress,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Transport: transport, Timeout: defaultHTTPTimeout}
+}
+
+func refusePrivateAddress(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return BlockedAddressError{Address: address}
+	}
+
+	addr := addrPort.Addr().Unmap()
+
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast() {
+		return BlockedAddressError{Address: address}
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package federation
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefusePrivateAddress(t *testing.T) {
+	t.Parallel()
+
+	for _, address := range []string{
+		"127.0.0.1:443",
+		"[::1]:443",
+		"10.0.0.1:443",
+		"172.16.0.1:443",
+		"192.168.1.1:443",
+		"169.254.169.254:80",
+		"[fe80::1]:443",
+		"[fd00:ec2::254]:80",
+		"0.0.0.0:443",
+		"[::ffff:127.0.0.1]:443",
+	} {
+		err := refusePrivateAddress("tcp", address, nil)
+		require.ErrorAs(t, err, &BlockedAddressError{}, address)
+	}
+
+	for _, address := range []string{"93.184.216.34:443", "[2606:2800:220:1:248:1893:25c8:1946]:443"} {
+		err := refusePrivateAddress("tcp", address, nil)
+		assert.NoError(t, err, address)
+	}
+}
+
+func TestHTTPClientRefusesLoopback(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := newHTTPClient().Do(req)
+	if err == nil {
+		_ = resp.Body.Close()
+	}
+
+	require.ErrorAs(t, err, &BlockedAddressError{})
+}
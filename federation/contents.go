@@ -0,0 +1,57 @@
+package federation
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/nasermirzaei89/scribble/contents"
+)
+
+// ContentsMiddleware publishes new posts to the followers of their authors.
+type ContentsMiddleware struct {
+	svc  *Service
+	next contents.Service
+}
+
+var _ contents.Service = (*ContentsMiddleware)(nil)
+
+func NewContentsMiddleware(svc *Service, next contents.Service) *ContentsMiddleware {
+	return &ContentsMiddleware{
+		svc:  svc,
+		next: next,
+	}
+}
+
+func (mw *ContentsMiddleware) CreatePost(ctx context.Context, req contents.CreatePostRequest) (*contents.Post, error) {
+	post, err := mw.next.CreatePost(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	// The post exists either way, so failing to queue its delivery must not fail the request.
+	err = mw.svc.PublishPost(ctx, post)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to publish post", "postId", post.ID, "error", err)
+	}
+
+	return post, nil
+}
+
+func (mw *ContentsMiddleware) ListPosts(ctx context.Context, req contents.ListPostsRequest) ([]*contents.Post, error) {
+	posts, err := mw.next.ListPosts(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return posts, nil
+}
+
+func (mw *ContentsMiddleware) GetPost(ctx context.Context, postID string) (*contents.Post, error) {
+	post, err := mw.next.GetPost(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return post, nil
+}
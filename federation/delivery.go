@@ -0,0 +1,223 @@
+package federation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nasermirzaei89/scribble/queue"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusDelivered DeliveryStatus = "delivered"
+	DeliveryStatusFailed    DeliveryStatus = "failed"
+)
+
+// Delivery is an activity queued for posting to a remote inbox on behalf of a local user.
+type Delivery struct {
+	ID            string
+	UserID        string
+	Inbox         string
+	Activity      []byte
+	Status        DeliveryStatus
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type DeliveryRepository interface {
+	Insert(ctx context.Context, delivery *Delivery) (err error)
+	Update(ctx context.Context, delivery *Delivery) (err error)
+	// ListDue lists pending deliveries whose next attempt is due, oldest first.
+	ListDue(ctx context.Context, now time.Time, limit int) (deliveries []*Delivery, err error)
+}
+
+const (
+	maxDeliveryAttempts = 8
+	deliveryBaseDelay   = time.Minute
+	deliveryMaxDelay    = 12 * time.Hour
+	deliveryBatchSize   = 50
+	deliveryTimeout     = 30 * time.Second
+
+	// DeliveryPollInterval is how often the worker checks the queue for due deliveries.
+	DeliveryPollInterval = 10 * time.Second
+)
+
+// deliveryBackoff doubles the wait after every failed attempt, starting at a minute.
+var deliveryBackoff = queue.Backoff{Base: deliveryBaseDelay, Max: deliveryMaxDelay}
+
+// enqueue queues the activity for each inbox once.
+func (svc *Service) enqueue(ctx context.Context, userID string, inboxes []string, activity any) error {
+	body, err := json.Marshal(activity)
+	if err != nil {
+		return fmt.Errorf("failed to marshal activity: %w", err)
+	}
+
+	now := svc.now()
+	seen := make(map[string]bool, len(inboxes))
+
+	for _, inbox := range inboxes {
+		if seen[inbox] {
+			continue
+		}
+
+		seen[inbox] = true
+
+		err = svc.deliveryRepo.Insert(ctx, &Delivery{
+			ID:            uuid.NewString(),
+			UserID:        userID,
+			Inbox:         inbox,
+			Activity:      body,
+			Status:        DeliveryStatusPending,
+			Attempts:      0,
+			NextAttemptAt: now,
+			LastError:     "",
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to insert delivery to %q: %w", inbox, err)
+		}
+	}
+
+	return nil
+}
+
+// enqueueToFollowers queues the activity for the inboxes of all followers of the user.
+func (svc *Service) enqueueToFollowers(ctx context.Context, userID string, activity any) error {
+	followers, err := svc.followerRepo.List(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list followers: %w", err)
+	}
+
+	inboxes := make([]string, 0, len(followers))
+	for _, follower := range followers {
+		inboxes = append(inboxes, follower.Inbox)
+	}
+
+	return svc.enqueue(ctx, userID, inboxes, activity)
+}
+
+// ProcessDeliveries attempts the deliveries that are due and returns how many were attempted. Failed attempts are
+// retried with exponential backoff until they run out of attempts or the inbox rejects the activity for good.
+func (svc *Service) ProcessDeliveries(ctx context.Context) (int, error) {
+	deliveries, err := svc.deliveryRepo.ListDue(ctx, svc.now(), deliveryBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list due deliveries: %w", err)
+	}
+
+	for _, delivery := range deliveries {
+		svc.attempt(ctx, delivery)
+
+		err = svc.deliveryRepo.Update(ctx, delivery)
+		if err != nil {
+			return 0, fmt.Errorf("failed to update delivery %q: %w", delivery.ID, err)
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+func (svc *Service) attempt(ctx context.Context, delivery *Delivery) {
+	err := svc.deliver(ctx, delivery)
+
+	now := svc.now()
+	delivery.Attempts++
+	delivery.UpdatedAt = now
+
+	if err == nil {
+		delivery.Status = DeliveryStatusDelivered
+		delivery.LastError = ""
+
+		return
+	}
+
+	delivery.LastError = err.Error()
+
+	statusErr, ok := errors.AsType[DeliveryStatusError](err)
+	if (ok && statusErr.Permanent()) || delivery.Attempts >= maxDeliveryAttempts {
+		delivery.Status = DeliveryStatusFailed
+
+		slog.WarnContext(ctx, "federation delivery failed", "deliveryId", delivery.ID, "inbox", delivery.Inbox,
+			"attempts", delivery.Attempts, "error", err)
+
+		return
+	}
+
+	delivery.NextAttemptAt = now.Add(deliveryBackoff.Delay(delivery.Attempts))
+}
+
+func (svc *Service) deliver(ctx context.Context, delivery *Delivery) error {
+	user, err := svc.authSvc.GetUser(ctx, delivery.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	key, err := svc.userKey(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	privateKey, err := parsePrivateKeyPEM(key.PrivateKeyPEM)
+	if err != nil {
+		return fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Inbox, bytes.NewReader(delivery.Activity))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", ContentType)
+
+	err = signRequest(req, svc.keyID(user.Username), privateKey, delivery.Activity, svc.now())
+	if err != nil {
+		return fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	resp, err := svc.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+
+	defer func() {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDocumentSize))
+
+		err := resp.Body.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close response body", "error", err)
+		}
+	}()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return DeliveryStatusError{Inbox: delivery.Inbox, StatusCode: resp.StatusCode}
+	}
+
+	return nil
+}
+
+// RunDeliveryWorker processes due deliveries every DeliveryPollInterval until the context is done.
+func (svc *Service) RunDeliveryWorker(ctx context.Context) {
+	queue.Worker{
+		Name:      "federation deliveries",
+		Interval:  DeliveryPollInterval,
+		BatchSize: deliveryBatchSize,
+		Process:   svc.ProcessDeliveries,
+		Waker:     nil,
+	}.Run(ctx)
+}
@@ -0,0 +1,49 @@
+package federation
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// ActorNotFoundError is returned for a resource or actor that is not a local user.
+type ActorNotFoundError struct {
+	Name string
+}
+
+func (err ActorNotFoundError) Error() string {
+	return fmt.Sprintf("actor %q not found", err.Name)
+}
+
+// InvalidSignatureError is returned for an activity whose sender could not be verified.
+type InvalidSignatureError struct {
+	Reason string
+}
+
+func (err InvalidSignatureError) Error() string {
+	return "invalid http signature: " + err.Reason
+}
+
+// InvalidActivityError is returned for a received activity this server can not read.
+type InvalidActivityError struct {
+	Reason string
+}
+
+func (err InvalidActivityError) Error() string {
+	return "invalid activity: " + err.Reason
+}
+
+// DeliveryStatusError is returned when a remote inbox rejects a delivery.
+type DeliveryStatusError struct {
+	Inbox      string
+	StatusCode int
+}
+
+func (err DeliveryStatusError) Error() string {
+	return fmt.Sprintf("inbox %q responded with status %d", err.Inbox, err.StatusCode)
+}
+
+// Permanent reports whether retrying can not help. Client errors are permanent, except timeouts and rate limits.
+func (err DeliveryStatusError) Permanent() bool {
+	return err.StatusCode >= http.StatusBadRequest && err.StatusCode < http.StatusInternalServerError &&
+		err.StatusCode != http.StatusRequestTimeout && err.StatusCode != http.StatusTooManyRequests
+}
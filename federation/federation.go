@@ -0,0 +1,219 @@
+// Package federation publishes posts to the fediverse with ActivityPub. Every local user is an actor others can find
+// through WebFinger and follow. New and deleted posts are delivered to followers through a retrying queue, and replies
+// from other servers become comments.
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/authentication"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/discuss"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+type Config struct {
+	// BaseURL is the public URL of the site, like https://scribble.example. Actor and object IDs are built from it,
+	// so it must not change once the site federates.
+	BaseURL string
+	// HTTPClient fetches remote actors and delivers activities. Defaults to a client with a timeout, that only connects
+	// to public addresses.
+	HTTPClient *http.Client
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+type Service struct {
+	baseURL         string
+	host            string
+	httpClient      *http.Client
+	now             func() time.Time
+	markdown        goldmark.Markdown
+	keyRepo         KeyRepository
+	remoteActorRepo RemoteActorRepository
+	followerRepo    FollowerRepository
+	objectRepo      ObjectRepository
+	deliveryRepo    DeliveryRepository
+	authSvc         *authentication.Service
+	contentsSvc     contents.Service
+	discussSvc      discuss.Service
+}
+
+var errInvalidBaseURL = errors.New("base url must be an absolute http or https url")
+
+func NewService(
+	cfg Config,
+	keyRepo KeyRepository,
+	remoteActorRepo RemoteActorRepository,
+	followerRepo FollowerRepository,
+	objectRepo ObjectRepository,
+	deliveryRepo DeliveryRepository,
+	authSvc *authentication.Service,
+	contentsSvc contents.Service,
+	discussSvc discuss.Service,
+) (*Service, error) {
+	baseURL, err := url.Parse(strings.TrimSuffix(cfg.BaseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse base url: %w", err)
+	}
+
+	if (baseURL.Scheme != "http" && baseURL.Scheme != "https") || baseURL.Host == "" {
+		return nil, errInvalidBaseURL
+	}
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = newHTTPClient()
+	}
+
+	now := cfg.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	return &Service{
+		baseURL:    baseURL.String(),
+		host:       baseURL.Host,
+		httpClient: httpClient,
+		now:        now,
+		// Raw HTML is left out, other servers sanitize notes but should not have to.
+		markdown:        goldmark.New(goldmark.WithExtensions(extension.GFM)),
+		keyRepo:         keyRepo,
+		remoteActorRepo: remoteActorRepo,
+		followerRepo:    followerRepo,
+		objectRepo:      objectRepo,
+		deliveryRepo:    deliveryRepo,
+		authSvc:         authSvc,
+		contentsSvc:     contentsSvc,
+		discussSvc:      discussSvc,
+	}, nil
+}
+
+// Host returns the host of the site, the domain part of its users' handles.
+func (svc *Service) Host() string {
+	return svc.host
+}
+
+func (svc *Service) actorID(username string) string {
+	return svc.baseURL + "/users/" + url.PathEscape(username)
+}
+
+func (svc *Service) keyID(username string) string {
+	return svc.actorID(username) + "#main-key"
+}
+
+func (svc *Service) postID(postID string) string {
+	return svc.baseURL + "/p/" + url.PathEscape(postID)
+}
+
+func (svc *Service) sharedInbox() string {
+	return svc.baseURL + "/inbox"
+}
+
+// localUsername returns the username of a local actor ID.
+func (svc *Service) localUsername(actorID string) (string, bool) {
+	escaped, ok := strings.CutPrefix(actorID, svc.baseURL+"/users/")
+	if !ok || strings.Contains(escaped, "/") {
+		return "", false
+	}
+
+	username, err := url.PathUnescape(escaped)
+	if err != nil {
+		return "", false
+	}
+
+	return username, true
+}
+
+// localPostID returns the post ID of a local post object ID.
+func (svc *Service) localPostID(objectID string) (string, bool) {
+	escaped, ok := strings.CutPrefix(objectID, svc.baseURL+"/p/")
+	if !ok || strings.ContainsAny(escaped, "/#?") {
+		return "", false
+	}
+
+	postID, err := url.PathUnescape(escaped)
+	if err != nil {
+		return "", false
+	}
+
+	return postID, true
+}
+
+// userKey returns the key of the user, generating it on first use.
+func (svc *Service) userKey(ctx context.Context, userID string) (*Key, error) {
+	key, err := svc.keyRepo.Find(ctx, userID)
+	if err == nil {
+		return key, nil
+	}
+
+	if _, ok := errors.AsType[KeyNotFoundError](err); !ok {
+		return nil, fmt.Errorf("failed to find key: %w", err)
+	}
+
+	key, err = generateKey(userID, svc.now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	err = svc.keyRepo.Insert(ctx, key)
+	if err != nil {
+		// Another request may have generated it first.
+		existing, findErr := svc.keyRepo.Find(ctx, userID)
+		if findErr == nil {
+			return existing, nil
+		}
+
+		return nil, fmt.Errorf("failed to insert key: %w", err)
+	}
+
+	return key, nil
+}
+
+// maxDocumentSize bounds documents read from other servers.
+const maxDocumentSize = 1 << 20
+
+var errUnexpectedStatus = errors.New("unexpected status")
+
+// fetchDocument gets the ActivityStreams representation of a remote object.
+func (svc *Service) fetchDocument(ctx context.Context, id string, document any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, id, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", acceptHeader)
+
+	resp, err := svc.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+
+	defer func() {
+		err := resp.Body.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close response body", "error", err)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w %d fetching %q", errUnexpectedStatus, resp.StatusCode, id)
+	}
+
+	err = json.NewDecoder(io.LimitReader(resp.Body, maxDocumentSize)).Decode(document)
+	if err != nil {
+		return fmt.Errorf("failed to decode document: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,650 @@
+package federation_test
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/scribble"
+	"github.com/nasermirzaei89/scribble/audit"
+	"github.com/nasermirzaei89/scribble/authentication"
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/authorization/casbin"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/database/sqlite3"
+	"github.com/nasermirzaei89/scribble/discuss"
+	"github.com/nasermirzaei89/scribble/events"
+	"github.com/nasermirzaei89/scribble/federation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const baseURL = "https://scribble.test"
+
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// remoteServer is a fake fediverse server with one actor, bob. It verifies the signatures of the activities posted to
+// it against the sender's actor document, and fails deliveries with the queued statuses before accepting them.
+type remoteServer struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	fedSvc *federation.Service
+
+	mu       sync.Mutex
+	statuses []int
+	received []map[string]any
+	attempts int
+	// actorFetches counts the requests for the actor document of bob.
+	actorFetches int
+}
+
+func newRemoteServer(t *testing.T) *remoteServer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	remote := &remoteServer{t: t, key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/bob", remote.handleActor)
+	mux.HandleFunc("POST /inbox", remote.handleInbox)
+	mux.HandleFunc("POST /users/bob/inbox", remote.handleInbox)
+
+	remote.server = httptest.NewTLSServer(mux)
+	t.Cleanup(remote.server.Close)
+
+	return remote
+}
+
+func (remote *remoteServer) actorID() string {
+	return remote.server.URL + "/users/bob"
+}
+
+func (remote *remoteServer) keyID() string {
+	return remote.actorID() + "#main-key"
+}
+
+func (remote *remoteServer) handleActor(w http.ResponseWriter, _ *http.Request) {
+	remote.mu.Lock()
+	remote.actorFetches++
+	remote.mu.Unlock()
+
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(&remote.key.PublicKey)
+	require.NoError(remote.t, err)
+
+	w.Header().Set("Content-Type", federation.ContentType)
+
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"id":                remote.actorID(),
+		"type":              "Person",
+		"preferredUsername": "bob",
+		"inbox":             remote.actorID() + "/inbox",
+		"endpoints":         map[string]any{"sharedInbox": remote.server.URL + "/inbox"},
+		"publicKey": map[string]any{
+			"id":           remote.keyID(),
+			"owner":        remote.actorID(),
+			"publicKeyPem": string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyDER})),
+		},
+	})
+}
+
+func (remote *remoteServer) handleInbox(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	require.NoError(remote.t, err)
+
+	var activity map[string]any
+
+	err = json.Unmarshal(body, &activity)
+	require.NoError(remote.t, err)
+
+	if !remote.verify(r, body, activity["actor"].(string)) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+
+		return
+	}
+
+	remote.mu.Lock()
+	defer remote.mu.Unlock()
+
+	remote.attempts++
+
+	if len(remote.statuses) > 0 {
+		status := remote.statuses[0]
+		remote.statuses = remote.statuses[1:]
+
+		w.WriteHeader(status)
+
+		return
+	}
+
+	remote.received = append(remote.received, activity)
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// verify checks the signature against the key in the actor document of the local user.
+func (remote *remoteServer) verify(r *http.Request, body []byte, actorID string) bool {
+	params := make(map[string]string)
+
+	for param := range strings.SplitSeq(r.Header.Get("Signature"), ",") {
+		name, value, _ := strings.Cut(param, "=")
+		params[name] = strings.Trim(value, `"`)
+	}
+
+	username := strings.TrimPrefix(actorID, baseURL+"/users/")
+
+	actor, err := remote.fedSvc.Actor(context.Background(), username)
+	if err != nil || params["keyId"] != actor.PublicKey.ID {
+		return false
+	}
+
+	block, _ := pem.Decode([]byte(actor.PublicKey.PublicKeyPEM))
+
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return false
+	}
+
+	digest := sha256.Sum256(body)
+	if r.Header.Get("Digest") != "SHA-256="+base64.StdEncoding.EncodeToString(digest[:]) {
+		return false
+	}
+
+	lines := make([]string, 0)
+
+	for header := range strings.FieldsSeq(params["headers"]) {
+		switch header {
+		case "(request-target)":
+			lines = append(lines, header+": post "+r.URL.RequestURI())
+		case "host":
+			lines = append(lines, header+": "+r.Host)
+		default:
+			lines = append(lines, header+": "+r.Header.Get(header))
+		}
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(params["signature"])
+	if err != nil {
+		return false
+	}
+
+	hashed := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+
+	return rsa.VerifyPKCS1v15(publicKey.(*rsa.PublicKey), crypto.SHA256, hashed[:], signature) == nil
+}
+
+func (remote *remoteServer) failNext(statuses ...int) {
+	remote.mu.Lock()
+	defer remote.mu.Unlock()
+
+	remote.statuses = append(remote.statuses, statuses...)
+}
+
+func (remote *remoteServer) takeReceived() ([]map[string]any, int) {
+	remote.mu.Lock()
+	defer remote.mu.Unlock()
+
+	received, attempts := remote.received, remote.attempts
+	remote.received, remote.attempts = nil, 0
+
+	return received, attempts
+}
+
+// newInboxRequest builds a request posting the activity to the local inbox, signed with the key of bob.
+func (remote *remoteServer) newInboxRequest(t *testing.T, activity any, date time.Time) (*http.Request, []byte) {
+	t.Helper()
+
+	body, err := json.Marshal(activity)
+	require.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodPost, baseURL+"/inbox", bytes.NewReader(body))
+	r.Header.Set("Content-Type", federation.ContentType)
+	r.Header.Set("Date", date.UTC().Format(http.TimeFormat))
+
+	digest := sha256.Sum256(body)
+	r.Header.Set("Digest", "SHA-256="+base64.StdEncoding.EncodeToString(digest[:]))
+
+	signed := strings.Join([]string{
+		"(request-target): post /inbox",
+		"host: " + r.Host,
+		"date: " + r.Header.Get("Date"),
+		"digest: " + r.Header.Get("Digest"),
+	}, "\n")
+
+	hashed := sha256.Sum256([]byte(signed))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, remote.key, crypto.SHA256, hashed[:])
+	require.NoError(t, err)
+
+	r.Header.Set("Signature", fmt.Sprintf(
+		`keyId="%s",algorithm="rsa-sha256",headers="(request-target) host date digest",signature="%s"`,
+		remote.keyID(),
+		base64.StdEncoding.EncodeToString(signature),
+	))
+
+	return r, body
+}
+
+type testEnv struct {
+	clock       *clock
+	remote      *remoteServer
+	fedSvc      *federation.Service
+	authSvc     *authentication.Service
+	contentsSvc contents.Service
+	discussSvc  discuss.Service
+	alice       *authentication.User
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	ctx := context.Background()
+
+	db, err := sqlite3.NewDB(ctx, "file:"+strings.ReplaceAll(t.Name(), "/", "_")+"?mode=memory&cache=shared")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := db.Close()
+		require.NoError(t, err)
+	})
+
+	err = sqlite3.MigrateUp(ctx, db)
+	require.NoError(t, err)
+
+	provider, err := casbin.NewAuthorizationProvider(sqlite3.NewCasbinAdapter(db))
+	require.NoError(t, err)
+
+	err = provider.AddPolicyFromCSV(ctx, scribble.DefaultAuthorizationPolicy())
+	require.NoError(t, err)
+
+	authzSvc, err := authorization.NewService(provider)
+	require.NoError(t, err)
+
+	authzClient := authorization.NewClient(authzSvc)
+	auditRecorder := audit.NewBaseService(sqlite3.NewAuditEventRepository(db))
+	eventBus := events.NewBus(sqlite3.NewEventOutboxRepository(db), sqlite3.NewTransactor(db))
+
+	authSvc := authentication.NewService(
+		sqlite3.NewUserRepository(db),
+		sqlite3.NewSessionRepository(db),
+		authzClient,
+		auditRecorder,
+		eventBus,
+	)
+	contentsSvc := contents.NewService(sqlite3.NewPostRepository(db), authzClient, eventBus)
+	discussSvc := discuss.NewService(
+		sqlite3.NewCommentRepository(db),
+		sqlite3.NewCommentEditRepository(db),
+		authzClient,
+		eventBus,
+	)
+
+	remote := newRemoteServer(t)
+	testClock := &clock{now: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)}
+
+	fedSvc, err := federation.NewService(
+		federation.Config{BaseURL: baseURL, HTTPClient: remote.server.Client(), Now: testClock.Now},
+		sqlite3.NewFederationKeyRepository(db),
+		sqlite3.NewFederationRemoteActorRepository(db),
+		sqlite3.NewFederationFollowerRepository(db),
+		sqlite3.NewFederationObjectRepository(db),
+		sqlite3.NewFederationDeliveryRepository(db),
+		authSvc,
+		contentsSvc,
+		discussSvc,
+	)
+	require.NoError(t, err)
+
+	remote.fedSvc = fedSvc
+
+	alice, err := authSvc.Register(ctx, "alice", "password")
+	require.NoError(t, err)
+
+	return &testEnv{
+		clock:       testClock,
+		remote:      remote,
+		fedSvc:      fedSvc,
+		authSvc:     authSvc,
+		contentsSvc: federation.NewContentsMiddleware(fedSvc, contentsSvc),
+		discussSvc:  discussSvc,
+		alice:       alice,
+	}
+}
+
+func (env *testEnv) receive(t *testing.T, activity any) error {
+	t.Helper()
+
+	r, body := env.remote.newInboxRequest(t, activity, env.clock.Now())
+
+	return env.fedSvc.ReceiveActivity(context.Background(), r, body)
+}
+
+func (env *testEnv) follow(t *testing.T) {
+	t.Helper()
+
+	err := env.receive(t, map[string]any{
+		"id":     env.remote.actorID() + "/follows/1",
+		"type":   "Follow",
+		"actor":  env.remote.actorID(),
+		"object": baseURL + "/users/alice",
+	})
+	require.NoError(t, err)
+}
+
+func (env *testEnv) createPost(t *testing.T, content string) *contents.Post {
+	t.Helper()
+
+	post, err := env.contentsSvc.CreatePost(authcontext.WithSubject(context.Background(), env.alice.ID),
+		contents.CreatePostRequest{AuthorID: env.alice.ID, CommunityID: "", Content: content})
+	require.NoError(t, err)
+
+	return post
+}
+
+func TestDiscovery(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	webFinger, err := env.fedSvc.WebFinger(ctx, "acct:alice@scribble.test")
+	require.NoError(t, err)
+	assert.Equal(t, "acct:alice@scribble.test", webFinger.Subject)
+	assert.Contains(t, webFinger.Links, federation.WebFingerLink{
+		Rel:  "self",
+		Type: federation.ContentType,
+		Href: baseURL + "/users/alice",
+	})
+
+	for _, resource := range []string{"acct:alice@elsewhere.test", "acct:carol@scribble.test", "alice"} {
+		_, err = env.fedSvc.WebFinger(ctx, resource)
+		require.ErrorAs(t, err, &federation.ActorNotFoundError{}, resource)
+	}
+
+	actor, err := env.fedSvc.Actor(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, baseURL+"/users/alice", actor.ID)
+	assert.Equal(t, baseURL+"/users/alice/inbox", actor.Inbox)
+	assert.Equal(t, baseURL+"/inbox", actor.Endpoints.SharedInbox)
+	assert.Equal(t, baseURL+"/users/alice#main-key", actor.PublicKey.ID)
+	assert.Contains(t, actor.PublicKey.PublicKeyPEM, "BEGIN PUBLIC KEY")
+
+	again, err := env.fedSvc.Actor(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, actor.PublicKey.PublicKeyPEM, again.PublicKey.PublicKeyPEM, "key is generated once")
+
+	post := env.createPost(t, "Hello **fediverse**")
+
+	note, err := env.fedSvc.Note(ctx, post.ID)
+	require.NoError(t, err)
+	assert.Equal(t, baseURL+"/p/"+post.ID, note.ID)
+	assert.Equal(t, actor.ID, note.AttributedTo)
+	assert.Equal(t, "<p>Hello <strong>fediverse</strong></p>\n", note.Content)
+
+	outbox, err := env.fedSvc.Outbox(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 1, outbox.TotalItems)
+}
+
+func TestDelivery(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	env.follow(t)
+
+	followers, err := env.fedSvc.Followers(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 1, followers.TotalItems)
+
+	processed, err := env.fedSvc.ProcessDeliveries(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, processed)
+
+	received, _ := env.remote.takeReceived()
+	require.Len(t, received, 1)
+	assert.Equal(t, "Accept", received[0]["type"])
+	assert.Equal(t, env.remote.actorID()+"/follows/1", received[0]["object"].(map[string]any)["id"])
+
+	t.Run("retries with backoff", func(t *testing.T) {
+		env.remote.failNext(http.StatusServiceUnavailable, http.StatusTooManyRequests)
+
+		post := env.createPost(t, "Hello fediverse")
+
+		processed, err := env.fedSvc.ProcessDeliveries(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, processed)
+
+		processed, err = env.fedSvc.ProcessDeliveries(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, processed, "the retry is not due yet")
+
+		env.clock.Advance(time.Minute)
+
+		processed, err = env.fedSvc.ProcessDeliveries(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, processed)
+
+		env.clock.Advance(time.Minute)
+
+		processed, err = env.fedSvc.ProcessDeliveries(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, processed, "the delay doubles")
+
+		env.clock.Advance(time.Minute)
+
+		processed, err = env.fedSvc.ProcessDeliveries(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, processed)
+
+		received, attempts := env.remote.takeReceived()
+		assert.Equal(t, 3, attempts)
+		require.Len(t, received, 1)
+		assert.Equal(t, "Create", received[0]["type"])
+
+		note := received[0]["object"].(map[string]any)
+		assert.Equal(t, baseURL+"/p/"+post.ID, note["id"])
+		assert.Equal(t, "<p>Hello fediverse</p>\n", note["content"])
+
+		err = env.fedSvc.PublishPostDeletion(ctx, post)
+		require.NoError(t, err)
+
+		_, err = env.fedSvc.ProcessDeliveries(ctx)
+		require.NoError(t, err)
+
+		received, _ = env.remote.takeReceived()
+		require.Len(t, received, 1)
+		assert.Equal(t, "Delete", received[0]["type"])
+		assert.Equal(t, "Tombstone", received[0]["object"].(map[string]any)["type"])
+	})
+
+	t.Run("gives up on client errors", func(t *testing.T) {
+		env.remote.failNext(http.StatusGone)
+
+		env.createPost(t, "Gone")
+
+		processed, err := env.fedSvc.ProcessDeliveries(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, processed)
+
+		env.clock.Advance(24 * time.Hour)
+
+		processed, err = env.fedSvc.ProcessDeliveries(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, processed)
+
+		received, attempts := env.remote.takeReceived()
+		assert.Empty(t, received)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("undo follow", func(t *testing.T) {
+		err := env.receive(t, map[string]any{
+			"id":    env.remote.actorID() + "/follows/1/undo",
+			"type":  "Undo",
+			"actor": env.remote.actorID(),
+			"object": map[string]any{
+				"id":     env.remote.actorID() + "/follows/1",
+				"type":   "Follow",
+				"actor":  env.remote.actorID(),
+				"object": baseURL + "/users/alice",
+			},
+		})
+		require.NoError(t, err)
+
+		env.createPost(t, "Nobody is listening")
+
+		processed, err := env.fedSvc.ProcessDeliveries(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, processed)
+	})
+}
+
+func TestReceiveReplies(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	post := env.createPost(t, "Hello fediverse")
+
+	reply := func(id, inReplyTo, content string) map[string]any {
+		return map[string]any{
+			"id":    id + "/activity",
+			"type":  "Create",
+			"actor": env.remote.actorID(),
+			"object": map[string]any{
+				"id":           id,
+				"type":         "Note",
+				"attributedTo": env.remote.actorID(),
+				"inReplyTo":    inReplyTo,
+				"content":      content,
+			},
+		}
+	}
+
+	listComments := func(t *testing.T) []*discuss.Comment {
+		t.Helper()
+
+		comments, err := env.discussSvc.ListComments(ctx, discuss.ListCommentsRequest{PostID: post.ID, After: nil, Limit: 0})
+		require.NoError(t, err)
+
+		return comments
+	}
+
+	noteID := env.remote.server.URL + "/notes/1"
+
+	err := env.receive(t, reply(noteID, baseURL+"/p/"+post.ID,
+		`<p><span class="h-card"><a href="`+baseURL+`/users/alice" class="u-url mention">@alice</a></span> hi `+
+			`<script>alert(1)</script><b>*there*</b></p><p>second<br>line</p>`))
+	require.NoError(t, err)
+
+	comments := listComments(t)
+	require.Len(t, comments, 1)
+	assert.Equal(t, "[@alice]("+baseURL+"/users/alice) hi \\*there\\*\n\nsecond\\\nline", comments[0].Content)
+	assert.Nil(t, comments[0].ReplyTo)
+
+	author, err := env.authSvc.GetUser(ctx, comments[0].AuthorID)
+	require.NoError(t, err)
+
+	remoteURL, err := url.Parse(env.remote.server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "bob@"+remoteURL.Host, author.Username)
+
+	_, err = env.authSvc.Login(ctx, author.Username, "")
+	require.ErrorIs(t, err, authentication.ErrInvalidCredentials, "remote users can not log in")
+
+	t.Run("redelivered", func(t *testing.T) {
+		err := env.receive(t, reply(noteID, baseURL+"/p/"+post.ID, "<p>again</p>"))
+		require.NoError(t, err)
+
+		assert.Len(t, listComments(t), 1)
+	})
+
+	t.Run("reply to a reply", func(t *testing.T) {
+		err := env.receive(t, reply(env.remote.server.URL+"/notes/2", noteID, "<p>threaded</p>"))
+		require.NoError(t, err)
+
+		comments := listComments(t)
+		require.Len(t, comments, 2)
+		require.NotNil(t, comments[1].ReplyTo)
+		assert.Equal(t, comments[0].ID, *comments[1].ReplyTo)
+		assert.Equal(t, comments[0].AuthorID, comments[1].AuthorID)
+	})
+
+	t.Run("unrelated note", func(t *testing.T) {
+		err := env.receive(t, reply(env.remote.server.URL+"/notes/3", "https://elsewhere.test/notes/1", "<p>hi</p>"))
+		require.NoError(t, err)
+
+		assert.Len(t, listComments(t), 2)
+	})
+
+	t.Run("tampered body", func(t *testing.T) {
+		r, _ := env.remote.newInboxRequest(t, reply(env.remote.server.URL+"/notes/4", baseURL+"/p/"+post.ID, "hi"),
+			env.clock.Now())
+
+		body, err := json.Marshal(reply(env.remote.server.URL+"/notes/4", baseURL+"/p/"+post.ID, "spam"))
+		require.NoError(t, err)
+
+		env.remote.mu.Lock()
+		fetches := env.remote.actorFetches
+		env.remote.mu.Unlock()
+
+		err = env.fedSvc.ReceiveActivity(ctx, r, body)
+		require.ErrorAs(t, err, &federation.InvalidSignatureError{})
+
+		env.remote.mu.Lock()
+		defer env.remote.mu.Unlock()
+
+		assert.Equal(t, fetches, env.remote.actorFetches, "the key is not fetched for a body that does not match")
+	})
+
+	t.Run("stale date", func(t *testing.T) {
+		r, body := env.remote.newInboxRequest(t, reply(env.remote.server.URL+"/notes/5", baseURL+"/p/"+post.ID, "hi"),
+			env.clock.Now().Add(-24*time.Hour))
+
+		err := env.fedSvc.ReceiveActivity(ctx, r, body)
+		require.ErrorAs(t, err, &federation.InvalidSignatureError{})
+	})
+
+	t.Run("impersonation", func(t *testing.T) {
+		activity := reply(env.remote.server.URL+"/notes/6", baseURL+"/p/"+post.ID, "hi")
+		activity["actor"] = "https://elsewhere.test/users/carol"
+
+		err := env.receive(t, activity)
+		require.ErrorAs(t, err, &federation.InvalidSignatureError{})
+
+		assert.Len(t, listComments(t), 2)
+	})
+}
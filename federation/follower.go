@@ -0,0 +1,21 @@
+package federation
+
+import (
+	"context"
+	"time"
+)
+
+// Follower is a remote actor following a local user.
+type Follower struct {
+	UserID     string
+	ActorID    string
+	Inbox      string
+	FollowedAt time.Time
+}
+
+type FollowerRepository interface {
+	Upsert(ctx context.Context, follower *Follower) (err error)
+	Delete(ctx context.Context, userID, actorID string) (err error)
+	List(ctx context.Context, userID string) (followers []*Follower, err error)
+	Count(ctx context.Context, userID string) (count int, err error)
+}
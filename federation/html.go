@@ -0,0 +1,127 @@
+package federation
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// htmlToMarkdown converts the HTML content of a remote note to Markdown. Only paragraphs, line breaks and links are
+// kept. Everything else becomes escaped text, so the note can not inject markup into the site.
+func htmlToMarkdown(content string) (string, error) {
+	nodes, err := html.ParseFragment(strings.NewReader(content), &html.Node{
+		Type:     html.ElementNode,
+		DataAtom: atom.Body,
+		Data:     "body",
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to parse html: %w", err)
+	}
+
+	var buf strings.Builder
+
+	for _, node := range nodes {
+		writeMarkdown(&buf, node)
+	}
+
+	return strings.TrimSpace(buf.String()), nil
+}
+
+func writeMarkdown(buf *strings.Builder, node *html.Node) {
+	switch node.Type {
+	case html.TextNode:
+		buf.WriteString(markdownEscaper.Replace(node.Data))
+
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	switch node.DataAtom {
+	case atom.Script, atom.Style:
+		return
+	case atom.Br:
+		buf.WriteString("\\\n")
+
+		return
+	case atom.A:
+		if destination, ok := linkDestination(node); ok {
+			var text strings.Builder
+
+			writeChildrenMarkdown(&text, node)
+
+			label := strings.TrimSpace(text.String())
+			if label == "" {
+				label = markdownEscaper.Replace(destination)
+			}
+
+			buf.WriteString("[" + label + "](" + destination + ")")
+
+			return
+		}
+	case atom.P:
+		writeChildrenMarkdown(buf, node)
+		buf.WriteString("\n\n")
+
+		return
+	}
+
+	writeChildrenMarkdown(buf, node)
+}
+
+func writeChildrenMarkdown(buf *strings.Builder, node *html.Node) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		writeMarkdown(buf, child)
+	}
+}
+
+// linkDestination returns the href of a link when it is an http URL, encoded for a Markdown link destination.
+func linkDestination(node *html.Node) (string, bool) {
+	for _, attr := range node.Attr {
+		if attr.Key != "href" {
+			continue
+		}
+
+		u, err := url.Parse(attr.Val)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return "", false
+		}
+
+		return destinationEscaper.Replace(u.String()), true
+	}
+
+	return "", false
+}
+
+var (
+	// markdownEscaper escapes the characters that start Markdown or HTML markup.
+	markdownEscaper = strings.NewReplacer(
+		`\`, `\\`,
+		"`", "\\`",
+		"*", `\*`,
+		"_", `\_`,
+		"[", `\[`,
+		"]", `\]`,
+		"#", `\#`,
+		"!", `\!`,
+		"|", `\|`,
+		"~", `\~`,
+		">", `\>`,
+		"<", "&lt;",
+		"&", "&amp;",
+	)
+
+	destinationEscaper = strings.NewReplacer(
+		" ", "%20",
+		"(", "%28",
+		")", "%29",
+		"<", "%3C",
+		">", "%3E",
+		`\`, "%5C",
+		"\n", "",
+	)
+)
@@ -0,0 +1,374 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/authentication"
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/discuss"
+)
+
+// ReceiveActivity verifies the signature of an activity posted to an inbox and applies it. Follows are accepted,
+// replies to local posts become comments, and everything else is ignored.
+func (svc *Service) ReceiveActivity(ctx context.Context, r *http.Request, body []byte) error {
+	sig, err := parseSignature(r.Header.Get("Signature"))
+	if err != nil {
+		return err
+	}
+
+	// Requests that can not verify are rejected before their key is fetched.
+	err = checkSignedRequest(r, body, sig, svc.now())
+	if err != nil {
+		return err
+	}
+
+	actor, err := svc.verifiedActor(ctx, r, body, sig)
+	if err != nil {
+		return err
+	}
+
+	var activity incomingObject
+
+	err = json.Unmarshal(body, &activity)
+	if err != nil {
+		return InvalidActivityError{Reason: "body is not json"}
+	}
+
+	if referenceID(activity.Actor) != actor.ID {
+		return InvalidSignatureError{Reason: "activity actor is not the signer"}
+	}
+
+	switch activity.Type {
+	case TypeFollow:
+		return svc.receiveFollow(ctx, actor, &activity)
+	case TypeUndo:
+		return svc.receiveUndo(ctx, actor, &activity)
+	case TypeCreate:
+		return svc.receiveCreate(ctx, actor, &activity)
+	default:
+		slog.DebugContext(ctx, "ignored federated activity", "type", activity.Type, "actor", actor.ID)
+
+		return nil
+	}
+}
+
+// verifiedActor returns the actor whose key signed the request. A cached key that does not verify is fetched again,
+// since actors rotate keys.
+func (svc *Service) verifiedActor(
+	ctx context.Context,
+	r *http.Request,
+	body []byte,
+	sig *signature,
+) (*RemoteActor, error) {
+	actor, err := svc.remoteActorRepo.FindByPublicKeyID(ctx, sig.KeyID)
+	if err != nil {
+		if _, ok := errors.AsType[RemoteActorByPublicKeyIDNotFoundError](err); !ok {
+			return nil, fmt.Errorf("failed to find remote actor: %w", err)
+		}
+
+		actor = nil
+	}
+
+	if actor != nil {
+		err = svc.verifyWithActor(r, body, sig, actor)
+		if err == nil {
+			return actor, nil
+		}
+	}
+
+	actor, err = svc.refreshRemoteActor(ctx, sig.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = svc.verifyWithActor(r, body, sig, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	return actor, nil
+}
+
+func (svc *Service) verifyWithActor(r *http.Request, body []byte, sig *signature, actor *RemoteActor) error {
+	if actor.PublicKeyID != sig.KeyID {
+		return InvalidSignatureError{Reason: "key does not belong to the actor"}
+	}
+
+	publicKey, err := parsePublicKeyPEM(actor.PublicKeyPEM)
+	if err != nil {
+		return InvalidSignatureError{Reason: "public key is invalid"}
+	}
+
+	return verifySignature(r, body, sig, publicKey, svc.now())
+}
+
+// refreshRemoteActor fetches the actor owning the key and caches it.
+func (svc *Service) refreshRemoteActor(ctx context.Context, keyID string) (*RemoteActor, error) {
+	actorURL, err := url.Parse(keyID)
+	if err != nil || actorURL.Scheme != "https" || actorURL.Host == "" {
+		return nil, InvalidSignatureError{Reason: "keyId is not an https url"}
+	}
+
+	if actorURL.Host == svc.host {
+		return nil, InvalidSignatureError{Reason: "keyId is a local url"}
+	}
+
+	// Key IDs are usually the actor ID with a fragment.
+	actorURL.Fragment = ""
+
+	var document Actor
+
+	err = svc.fetchDocument(ctx, actorURL.String(), &document)
+	if err != nil {
+		return nil, InvalidSignatureError{Reason: "failed to fetch the key owner: " + err.Error()}
+	}
+
+	if document.PublicKey == nil || document.PublicKey.ID != keyID || document.PublicKey.Owner != document.ID {
+		return nil, InvalidSignatureError{Reason: "key owner does not publish the key"}
+	}
+
+	actorHost, err := url.Parse(document.ID)
+	if err != nil || actorHost.Host != actorURL.Host || document.Inbox == "" || document.PreferredUsername == "" {
+		return nil, InvalidSignatureError{Reason: "key owner is not a valid actor"}
+	}
+
+	actor, err := svc.remoteActorRepo.Find(ctx, document.ID)
+	if err != nil {
+		if _, ok := errors.AsType[RemoteActorNotFoundError](err); !ok {
+			return nil, fmt.Errorf("failed to find remote actor: %w", err)
+		}
+
+		actor = &RemoteActor{
+			ID:           document.ID,
+			Username:     "",
+			UserID:       "",
+			Inbox:        "",
+			SharedInbox:  "",
+			PublicKeyID:  "",
+			PublicKeyPEM: "",
+			FetchedAt:    time.Time{},
+		}
+	}
+
+	actor.Username = document.PreferredUsername + "@" + actorHost.Host
+	actor.Inbox = document.Inbox
+	actor.SharedInbox = ""
+	actor.PublicKeyID = document.PublicKey.ID
+	actor.PublicKeyPEM = document.PublicKey.PublicKeyPEM
+	actor.FetchedAt = svc.now()
+
+	if document.Endpoints != nil {
+		actor.SharedInbox = document.Endpoints.SharedInbox
+	}
+
+	err = svc.remoteActorRepo.Upsert(ctx, actor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert remote actor: %w", err)
+	}
+
+	return actor, nil
+}
+
+func (svc *Service) receiveFollow(ctx context.Context, actor *RemoteActor, activity *incomingObject) error {
+	username, ok := svc.localUsername(referenceID(activity.Object))
+	if !ok {
+		return InvalidActivityError{Reason: "follow object is not a local actor"}
+	}
+
+	user, err := svc.localUser(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	err = svc.followerRepo.Upsert(ctx, &Follower{
+		UserID:     user.ID,
+		ActorID:    actor.ID,
+		Inbox:      actor.DeliveryInbox(),
+		FollowedAt: svc.now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upsert follower: %w", err)
+	}
+
+	actorID := svc.actorID(user.Username)
+
+	accept := &Activity{
+		Context:   []string{activityStreamsContext},
+		ID:        actorID + "#accepts/" + url.PathEscape(activity.ID),
+		Type:      TypeAccept,
+		Actor:     actorID,
+		Published: nil,
+		To:        []string{actor.ID},
+		Cc:        nil,
+		Object: map[string]string{
+			"id":     activity.ID,
+			"type":   activity.Type,
+			"actor":  actor.ID,
+			"object": svc.actorID(user.Username),
+		},
+	}
+
+	return svc.enqueue(ctx, user.ID, []string{actor.Inbox}, accept)
+}
+
+func (svc *Service) receiveUndo(ctx context.Context, actor *RemoteActor, activity *incomingObject) error {
+	var undone incomingObject
+
+	// Only embedded follows can be undone, since follow activities are not kept.
+	if json.Unmarshal(activity.Object, &undone) != nil || undone.Type != TypeFollow {
+		return nil
+	}
+
+	if referenceID(undone.Actor) != actor.ID {
+		return InvalidActivityError{Reason: "undone activity is not by the actor"}
+	}
+
+	username, ok := svc.localUsername(referenceID(undone.Object))
+	if !ok {
+		return nil
+	}
+
+	user, err := svc.localUser(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	err = svc.followerRepo.Delete(ctx, user.ID, actor.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete follower: %w", err)
+	}
+
+	return nil
+}
+
+// receiveCreate turns a note replying to a local post, or to a comment that came from another server, into a
+// comment. Other notes are ignored, and a note already received is not added again.
+func (svc *Service) receiveCreate(ctx context.Context, actor *RemoteActor, activity *incomingObject) error {
+	var note incomingObject
+
+	if json.Unmarshal(activity.Object, &note) != nil || note.Type != TypeNote {
+		return nil
+	}
+
+	if note.ID == "" || referenceID(note.AttributedTo) != actor.ID {
+		return InvalidActivityError{Reason: "note is not attributed to the actor"}
+	}
+
+	_, err := svc.objectRepo.Find(ctx, note.ID)
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := errors.AsType[ObjectNotFoundError](err); !ok {
+		return fmt.Errorf("failed to find object: %w", err)
+	}
+
+	postID, replyTo, err := svc.replyTarget(ctx, referenceID(note.InReplyTo))
+	if err != nil || postID == "" {
+		return err
+	}
+
+	content, err := htmlToMarkdown(note.Content)
+	if err != nil {
+		return InvalidActivityError{Reason: "content is not html"}
+	}
+
+	if strings.TrimSpace(content) == "" {
+		return nil
+	}
+
+	user, err := svc.remoteUser(ctx, actor)
+	if err != nil {
+		return err
+	}
+
+	// The comment is created as the remote user, so it is authorized like any other comment.
+	comment, err := svc.discussSvc.CreateComment(authcontext.WithSubject(ctx, user.ID), discuss.CreateCommentRequest{
+		PostID:   postID,
+		AuthorID: user.ID,
+		Content:  content,
+		ReplyTo:  replyTo,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	err = svc.objectRepo.Insert(ctx, &Object{
+		ID:         note.ID,
+		PostID:     postID,
+		CommentID:  comment.ID,
+		ReceivedAt: svc.now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to insert object: %w", err)
+	}
+
+	return nil
+}
+
+// replyTarget returns the post and comment a note replies to. The post is empty when the note does not reply to
+// anything here.
+func (svc *Service) replyTarget(ctx context.Context, inReplyTo string) (string, string, error) {
+	if inReplyTo == "" {
+		return "", "", nil
+	}
+
+	if postID, ok := svc.localPostID(inReplyTo); ok {
+		post, err := svc.contentsSvc.GetPost(ctx, postID)
+		if err != nil {
+			if _, ok := errors.AsType[contents.PostNotFoundError](err); ok {
+				return "", "", nil
+			}
+
+			return "", "", fmt.Errorf("failed to get post: %w", err)
+		}
+
+		return post.ID, "", nil
+	}
+
+	object, err := svc.objectRepo.Find(ctx, inReplyTo)
+	if err != nil {
+		if _, ok := errors.AsType[ObjectNotFoundError](err); ok {
+			return "", "", nil
+		}
+
+		return "", "", fmt.Errorf("failed to find object: %w", err)
+	}
+
+	return object.PostID, object.CommentID, nil
+}
+
+// remoteUser returns the local user comments of the actor are authored by, registering it on the first reply.
+func (svc *Service) remoteUser(ctx context.Context, actor *RemoteActor) (*authentication.User, error) {
+	if actor.UserID != "" {
+		user, err := svc.authSvc.GetUser(ctx, actor.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get remote user: %w", err)
+		}
+
+		return user, nil
+	}
+
+	user, err := svc.authSvc.RegisterRemoteUser(ctx, actor.Username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register remote user: %w", err)
+	}
+
+	actor.UserID = user.ID
+
+	err = svc.remoteActorRepo.Upsert(ctx, actor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert remote actor: %w", err)
+	}
+
+	return user, nil
+}
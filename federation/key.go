@@ -0,0 +1,116 @@
+package federation
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Key is the RSA key pair a local user signs outgoing activities with.
+type Key struct {
+	UserID        string
+	PublicKeyPEM  string
+	PrivateKeyPEM string
+	CreatedAt     time.Time
+}
+
+type KeyRepository interface {
+	Insert(ctx context.Context, key *Key) (err error)
+	Find(ctx context.Context, userID string) (key *Key, err error)
+}
+
+type KeyNotFoundError struct {
+	UserID string
+}
+
+func (err KeyNotFoundError) Error() string {
+	return fmt.Sprintf("federation key of user %q not found", err.UserID)
+}
+
+const keyBits = 2048
+
+func generateKey(userID string, now time.Time) (*Key, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, keyBits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate rsa key: %w", err)
+	}
+
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal public key: %w", err)
+	}
+
+	privateKeyDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal private key: %w", err)
+	}
+
+	return &Key{
+		UserID:        userID,
+		PublicKeyPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Headers: nil, Bytes: publicKeyDER})),
+		PrivateKeyPEM: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Headers: nil, Bytes: privateKeyDER})),
+		CreatedAt:     now,
+	}, nil
+}
+
+var (
+	errNoPEMBlock   = errors.New("no pem block found")
+	errNotRSAKey    = errors.New("key is not an rsa key")
+	errUnknownBlock = errors.New("unknown pem block type")
+)
+
+func parsePrivateKeyPEM(data string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errNoPEMBlock
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errNotRSAKey
+	}
+
+	return rsaKey, nil
+}
+
+// parsePublicKeyPEM accepts PKIX keys, which most servers publish, and PKCS #1 keys.
+func parsePublicKeyPEM(data string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errNoPEMBlock
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return nil, errNotRSAKey
+		}
+
+		return rsaKey, nil
+	case "RSA PUBLIC KEY":
+		key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+
+		return key, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", errUnknownBlock, block.Type)
+	}
+}
@@ -0,0 +1,28 @@
+package federation
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Object is a remote object that became a comment.
+type Object struct {
+	ID         string
+	PostID     string
+	CommentID  string
+	ReceivedAt time.Time
+}
+
+type ObjectRepository interface {
+	Insert(ctx context.Context, object *Object) (err error)
+	Find(ctx context.Context, objectID string) (object *Object, err error)
+}
+
+type ObjectNotFoundError struct {
+	ID string
+}
+
+func (err ObjectNotFoundError) Error() string {
+	return fmt.Sprintf("federated object %q not found", err.ID)
+}
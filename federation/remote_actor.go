@@ -0,0 +1,52 @@
+package federation
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RemoteActor is a cached actor document of another server.
+type RemoteActor struct {
+	ID string
+	// Username is the handle of the actor, like alice@example.com.
+	Username string
+	// UserID is the local user the actor's replies are authored by. Empty until the actor first replies.
+	UserID       string
+	Inbox        string
+	SharedInbox  string
+	PublicKeyID  string
+	PublicKeyPEM string
+	FetchedAt    time.Time
+}
+
+// DeliveryInbox returns the shared inbox when the server has one, so one delivery reaches all its followers.
+func (actor *RemoteActor) DeliveryInbox() string {
+	if actor.SharedInbox != "" {
+		return actor.SharedInbox
+	}
+
+	return actor.Inbox
+}
+
+type RemoteActorRepository interface {
+	Upsert(ctx context.Context, actor *RemoteActor) (err error)
+	Find(ctx context.Context, actorID string) (actor *RemoteActor, err error)
+	FindByPublicKeyID(ctx context.Context, publicKeyID string) (actor *RemoteActor, err error)
+}
+
+type RemoteActorNotFoundError struct {
+	ID string
+}
+
+func (err RemoteActorNotFoundError) Error() string {
+	return fmt.Sprintf("remote actor %q not found", err.ID)
+}
+
+type RemoteActorByPublicKeyIDNotFoundError struct {
+	PublicKeyID string
+}
+
+func (err RemoteActorByPublicKeyIDNotFoundError) Error() string {
+	return fmt.Sprintf("remote actor with public key %q not found", err.PublicKeyID)
+}
@@ -0,0 +1,58 @@
+package live
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nasermirzaei89/scribble/contents"
+)
+
+// ContentsMiddleware publishes created posts.
+type ContentsMiddleware struct {
+	broker *Broker
+	next   contents.Service
+}
+
+var _ contents.Service = (*ContentsMiddleware)(nil)
+
+func NewContentsMiddleware(broker *Broker, next contents.Service) *ContentsMiddleware {
+	return &ContentsMiddleware{
+		broker: broker,
+		next:   next,
+	}
+}
+
+func (mw *ContentsMiddleware) CreatePost(ctx context.Context, req contents.CreatePostRequest) (*contents.Post, error) {
+	post, err := mw.next.CreatePost(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	mw.broker.Publish(Event{
+		Type:       EventPostCreated,
+		Post:       post,
+		Comment:    nil,
+		TargetType: "",
+		TargetID:   "",
+	})
+
+	return post, nil
+}
+
+func (mw *ContentsMiddleware) ListPosts(ctx context.Context, req contents.ListPostsRequest) ([]*contents.Post, error) {
+	posts, err := mw.next.ListPosts(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return posts, nil
+}
+
+func (mw *ContentsMiddleware) GetPost(ctx context.Context, postID string) (*contents.Post, error) {
+	post, err := mw.next.GetPost(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return post, nil
+}
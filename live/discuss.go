@@ -0,0 +1,119 @@
+package live
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nasermirzaei89/scribble/discuss"
+)
+
+// DiscussMiddleware publishes created, edited and deleted comments.
+type DiscussMiddleware struct {
+	broker *Broker
+	next   discuss.Service
+}
+
+var _ discuss.Service = (*DiscussMiddleware)(nil)
+
+func NewDiscussMiddleware(broker *Broker, next discuss.Service) *DiscussMiddleware {
+	return &DiscussMiddleware{
+		broker: broker,
+		next:   next,
+	}
+}
+
+func (mw *DiscussMiddleware) CreateComment(
+	ctx context.Context,
+	req discuss.CreateCommentRequest,
+) (*discuss.Comment, error) {
+	comment, err := mw.next.CreateComment(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	mw.broker.Publish(Event{
+		Type:       EventCommentCreated,
+		Post:       nil,
+		Comment:    comment,
+		TargetType: "",
+		TargetID:   "",
+	})
+
+	return comment, nil
+}
+
+func (mw *DiscussMiddleware) ListComments(
+	ctx context.Context,
+	req discuss.ListCommentsRequest,
+) ([]*discuss.Comment, error) {
+	comments, err := mw.next.ListComments(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return comments, nil
+}
+
+func (mw *DiscussMiddleware) GetComment(ctx context.Context, commentID string) (*discuss.Comment, error) {
+	comment, err := mw.next.GetComment(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return comment, nil
+}
+
+func (mw *DiscussMiddleware) CountComments(ctx context.Context, postID string) (int, error) {
+	count, err := mw.next.CountComments(ctx, postID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return count, nil
+}
+
+func (mw *DiscussMiddleware) UpdateComment(
+	ctx context.Context,
+	req discuss.UpdateCommentRequest,
+) (*discuss.Comment, bool, error) {
+	comment, changed, err := mw.next.UpdateComment(ctx, req)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	if changed {
+		mw.publishChanged(comment)
+	}
+
+	return comment, changed, nil
+}
+
+func (mw *DiscussMiddleware) DeleteComment(ctx context.Context, commentID string) (*discuss.Comment, error) {
+	comment, err := mw.next.DeleteComment(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	mw.publishChanged(comment)
+
+	return comment, nil
+}
+
+func (mw *DiscussMiddleware) ListCommentEdits(ctx context.Context, commentID string) ([]*discuss.CommentEdit, error) {
+	edits, err := mw.next.ListCommentEdits(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return edits, nil
+}
+
+func (mw *DiscussMiddleware) publishChanged(comment *discuss.Comment) {
+	mw.broker.Publish(Event{
+		Type:       EventCommentChanged,
+		Post:       nil,
+		Comment:    comment,
+		TargetType: "",
+		TargetID:   "",
+	})
+}
@@ -0,0 +1,190 @@
+// Package live fans out changes to posts, comments and reactions to subscribers in the same process, so open pages
+// can show them without reloading.
+package live
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/discuss"
+	"github.com/nasermirzaei89/scribble/reactions"
+)
+
+type EventType string
+
+const (
+	EventPostCreated      EventType = "post-created"
+	EventCommentCreated   EventType = "comment-created"
+	EventCommentChanged   EventType = "comment-changed"
+	EventReactionsChanged EventType = "reactions-changed"
+)
+
+// TopicHome is the topic of the home feed.
+const TopicHome = "home"
+
+// PostTopic returns the topic of a post page, which receives reactions to the post and the changes to its comments.
+func PostTopic(postID string) string {
+	return "post:" + postID
+}
+
+type Event struct {
+	Type EventType
+	// Post is the created post.
+	Post *contents.Post
+	// Comment is the created comment, the edited or deleted one, or the one whose reactions changed.
+	Comment *discuss.Comment
+	// TargetType and TargetID are what the changed reactions are on.
+	TargetType reactions.TargetType
+	TargetID   string
+}
+
+// Topics returns the topics the event is published to.
+func (event Event) Topics() []string {
+	switch event.Type {
+	case EventPostCreated:
+		return []string{TopicHome}
+	case EventCommentCreated, EventCommentChanged:
+		return []string{PostTopic(event.Comment.PostID)}
+	case EventReactionsChanged:
+		switch event.TargetType {
+		case reactions.TargetTypePost:
+			return []string{TopicHome, PostTopic(event.TargetID)}
+		case reactions.TargetTypeComment:
+			return []string{PostTopic(event.Comment.PostID)}
+		default:
+			// No live page shows reactions to the other types of targets.
+			return nil
+		}
+	default:
+		return nil
+	}
+}
+
+// subscriptionBufferSize is how many events a subscriber may fall behind before it misses some.
+const subscriptionBufferSize = 32
+
+// Broker delivers published events to the subscriptions of their topics. Publishing never blocks, events for a
+// subscriber that is too far behind are dropped.
+type Broker struct {
+	mu            sync.RWMutex
+	subscriptions map[*Subscription]struct{}
+	closed        bool
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		mu:            sync.RWMutex{},
+		subscriptions: make(map[*Subscription]struct{}),
+		closed:        false,
+	}
+}
+
+type Subscription struct {
+	broker *Broker
+	events chan Event
+
+	mu     sync.RWMutex
+	topics map[string]bool
+}
+
+// Subscribe returns a subscription to the topics. It must be closed when no longer used.
+func (broker *Broker) Subscribe(topics ...string) *Subscription {
+	sub := &Subscription{
+		broker: broker,
+		events: make(chan Event, subscriptionBufferSize),
+		mu:     sync.RWMutex{},
+		topics: make(map[string]bool, len(topics)),
+	}
+
+	sub.Add(topics...)
+
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+
+	if broker.closed {
+		close(sub.events)
+
+		return sub
+	}
+
+	broker.subscriptions[sub] = struct{}{}
+
+	return sub
+}
+
+func (broker *Broker) Publish(event Event) {
+	topics := event.Topics()
+
+	broker.mu.RLock()
+	defer broker.mu.RUnlock()
+
+	for sub := range broker.subscriptions {
+		if !sub.matches(topics) {
+			continue
+		}
+
+		select {
+		case sub.events <- event:
+		default:
+			slog.Warn("dropped live event for slow subscriber", "type", event.Type)
+		}
+	}
+}
+
+// Close ends all subscriptions, for shutting down.
+func (broker *Broker) Close() {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+
+	if broker.closed {
+		return
+	}
+
+	broker.closed = true
+
+	for sub := range broker.subscriptions {
+		close(sub.events)
+		delete(broker.subscriptions, sub)
+	}
+}
+
+// Events returns the events of the subscribed topics. The channel is closed when the subscription or the broker is.
+func (sub *Subscription) Events() <-chan Event {
+	return sub.events
+}
+
+// Add subscribes to more topics.
+func (sub *Subscription) Add(topics ...string) {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	for _, topic := range topics {
+		sub.topics[topic] = true
+	}
+}
+
+func (sub *Subscription) matches(topics []string) bool {
+	sub.mu.RLock()
+	defer sub.mu.RUnlock()
+
+	for _, topic := range topics {
+		if sub.topics[topic] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (sub *Subscription) Close() {
+	sub.broker.mu.Lock()
+	defer sub.broker.mu.Unlock()
+
+	if _, ok := sub.broker.subscriptions[sub]; !ok {
+		return
+	}
+
+	delete(sub.broker.subscriptions, sub)
+	close(sub.events)
+}
@@ -0,0 +1,174 @@
+package live_test
+
+import (
+	"testing"
+
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/discuss"
+	"github.com/nasermirzaei89/scribble/live"
+	"github.com/nasermirzaei89/scribble/reactions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func postCreated(postID string) live.Event {
+	return live.Event{
+		Type:       live.EventPostCreated,
+		Post:       &contents.Post{ID: postID},
+		Comment:    nil,
+		TargetType: "",
+		TargetID:   "",
+	}
+}
+
+func commentCreated(postID, commentID string) live.Event {
+	return live.Event{
+		Type:       live.EventCommentCreated,
+		Post:       nil,
+		Comment:    &discuss.Comment{ID: commentID, PostID: postID},
+		TargetType: "",
+		TargetID:   "",
+	}
+}
+
+func commentChanged(postID, commentID string) live.Event {
+	return live.Event{
+		Type:       live.EventCommentChanged,
+		Post:       nil,
+		Comment:    &discuss.Comment{ID: commentID, PostID: postID},
+		TargetType: "",
+		TargetID:   "",
+	}
+}
+
+func reactionsChanged(targetType reactions.TargetType, targetID string) live.Event {
+	return live.Event{
+		Type:       live.EventReactionsChanged,
+		Post:       nil,
+		Comment:    nil,
+		TargetType: targetType,
+		TargetID:   targetID,
+	}
+}
+
+func commentReactionsChanged(postID, commentID string) live.Event {
+	return live.Event{
+		Type:       live.EventReactionsChanged,
+		Post:       nil,
+		Comment:    &discuss.Comment{ID: commentID, PostID: postID},
+		TargetType: reactions.TargetTypeComment,
+		TargetID:   commentID,
+	}
+}
+
+func receive(t *testing.T, sub *live.Subscription) []live.Event {
+	t.Helper()
+
+	var events []live.Event
+
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				return events
+			}
+
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestEventTopics(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{live.TopicHome}, postCreated("post1").Topics())
+	assert.Equal(t, []string{"post:post1"}, commentCreated("post1", "comment1").Topics())
+	assert.Equal(t, []string{"post:post1"}, commentChanged("post1", "comment1").Topics())
+	assert.Equal(t, []string{live.TopicHome, "post:post1"}, reactionsChanged(reactions.TargetTypePost, "post1").Topics())
+	assert.Equal(t, []string{"post:post1"}, commentReactionsChanged("post1", "comment1").Topics())
+	assert.Empty(t, reactionsChanged(reactions.TargetTypeUser, "user1").Topics())
+}
+
+func TestBroker(t *testing.T) {
+	t.Parallel()
+
+	broker := live.NewBroker()
+
+	home := broker.Subscribe(live.TopicHome)
+	defer home.Close()
+
+	post := broker.Subscribe(live.PostTopic("post1"))
+	defer post.Close()
+
+	broker.Publish(postCreated("post2"))
+	broker.Publish(commentCreated("post1", "comment1"))
+	broker.Publish(commentCreated("post2", "comment2"))
+	broker.Publish(commentReactionsChanged("post1", "comment1"))
+	broker.Publish(commentReactionsChanged("post2", "comment2"))
+
+	homeEvents := receive(t, home)
+	require.Len(t, homeEvents, 1)
+	assert.Equal(t, "post2", homeEvents[0].Post.ID)
+
+	postEvents := receive(t, post)
+	require.Len(t, postEvents, 2)
+	assert.Equal(t, live.EventCommentCreated, postEvents[0].Type)
+	assert.Equal(t, "comment1", postEvents[0].Comment.ID)
+	assert.Equal(t, live.EventReactionsChanged, postEvents[1].Type)
+	assert.Equal(t, "comment1", postEvents[1].TargetID)
+
+	post.Add(live.PostTopic("post2"))
+	broker.Publish(commentChanged("post2", "comment2"))
+	broker.Publish(reactionsChanged(reactions.TargetTypePost, "post1"))
+
+	assert.Len(t, receive(t, home), 1)
+	assert.Len(t, receive(t, post), 2)
+
+	t.Run("slow subscriber", func(t *testing.T) {
+		t.Parallel()
+
+		broker := live.NewBroker()
+
+		sub := broker.Subscribe(live.TopicHome)
+		defer sub.Close()
+
+		for range 1000 {
+			broker.Publish(postCreated("post1"))
+		}
+
+		events := receive(t, sub)
+		assert.NotEmpty(t, events)
+		assert.Less(t, len(events), 1000)
+	})
+
+	t.Run("close", func(t *testing.T) {
+		t.Parallel()
+
+		broker := live.NewBroker()
+
+		sub := broker.Subscribe(live.TopicHome)
+		sub.Close()
+		sub.Close()
+
+		broker.Publish(postCreated("post1"))
+
+		_, ok := <-sub.Events()
+		assert.False(t, ok)
+
+		other := broker.Subscribe(live.TopicHome)
+		broker.Close()
+
+		_, ok = <-other.Events()
+		assert.False(t, ok)
+
+		other.Close()
+
+		late := broker.Subscribe(live.TopicHome)
+		defer late.Close()
+
+		_, ok = <-late.Events()
+		assert.False(t, ok)
+	})
+}
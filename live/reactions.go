@@ -0,0 +1,164 @@
+package live
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/nasermirzaei89/scribble/discuss"
+	"github.com/nasermirzaei89/scribble/reactions"
+)
+
+// ReactionsMiddleware publishes changes to the reactions of a target.
+type ReactionsMiddleware struct {
+	broker *Broker
+	// commentRepo finds the post of a comment whose reactions changed, as the post page shows them.
+	commentRepo discuss.CommentRepository
+	next        reactions.Service
+}
+
+var _ reactions.Service = (*ReactionsMiddleware)(nil)
+
+func NewReactionsMiddleware(
+	broker *Broker,
+	commentRepo discuss.CommentRepository,
+	next reactions.Service,
+) *ReactionsMiddleware {
+	return &ReactionsMiddleware{
+		broker:      broker,
+		commentRepo: commentRepo,
+		next:        next,
+	}
+}
+
+func (mw *ReactionsMiddleware) AllowedEmojis(
+	ctx context.Context,
+	targetType reactions.TargetType,
+	targetID string,
+) ([]string, error) {
+	emojis, err := mw.next.AllowedEmojis(ctx, targetType, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return emojis, nil
+}
+
+func (mw *ReactionsMiddleware) ToggleMyReaction(
+	ctx context.Context,
+	targetType reactions.TargetType,
+	targetID string,
+	emoji string,
+) error {
+	err := mw.next.ToggleMyReaction(ctx, targetType, targetID, emoji)
+	if err != nil {
+		return fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	var comment *discuss.Comment
+
+	if targetType == reactions.TargetTypeComment {
+		comment, err = mw.commentRepo.Find(ctx, targetID)
+		if err != nil {
+			// The reaction is toggled, only open pages miss it.
+			slog.ErrorContext(ctx, "failed to find comment of live event", "commentId", targetID, "error", err)
+
+			return nil
+		}
+	}
+
+	mw.broker.Publish(Event{
+		Type:       EventReactionsChanged,
+		Post:       nil,
+		Comment:    comment,
+		TargetType: targetType,
+		TargetID:   targetID,
+	})
+
+	return nil
+}
+
+func (mw *ReactionsMiddleware) GetMyReactions(
+	ctx context.Context,
+	targetType reactions.TargetType,
+	targetID string,
+) (*reactions.TargetReactions, error) {
+	targetReactions, err := mw.next.GetMyReactions(ctx, targetType, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return targetReactions, nil
+}
+
+func (mw *ReactionsMiddleware) ListReactors(
+	ctx context.Context,
+	req reactions.ListReactorsRequest,
+) ([]*reactions.UserReaction, error) {
+	reactors, err := mw.next.ListReactors(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return reactors, nil
+}
+
+func (mw *ReactionsMiddleware) ListReactionSets(ctx context.Context) ([]*reactions.ReactionSet, error) {
+	sets, err := mw.next.ListReactionSets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return sets, nil
+}
+
+func (mw *ReactionsMiddleware) GetReactionSet(
+	ctx context.Context,
+	scope reactions.SetScope,
+	scopeID string,
+) (*reactions.ReactionSet, error) {
+	set, err := mw.next.GetReactionSet(ctx, scope, scopeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return set, nil
+}
+
+func (mw *ReactionsMiddleware) SetReactionSet(ctx context.Context, req reactions.SetReactionSetRequest) error {
+	err := mw.next.SetReactionSet(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	mw.publishSetChanged(req.Scope, req.ScopeID)
+
+	return nil
+}
+
+func (mw *ReactionsMiddleware) DeleteReactionSet(ctx context.Context, scope reactions.SetScope, scopeID string) error {
+	err := mw.next.DeleteReactionSet(ctx, scope, scopeID)
+	if err != nil {
+		return fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	mw.publishSetChanged(scope, scopeID)
+
+	return nil
+}
+
+// publishSetChanged refreshes the reactions of a post whose set changed. Broader sets change too many targets to
+// refresh.
+func (mw *ReactionsMiddleware) publishSetChanged(scope reactions.SetScope, scopeID string) {
+	if scope != reactions.SetScopePost {
+		return
+	}
+
+	mw.broker.Publish(Event{
+		Type:       EventReactionsChanged,
+		Post:       nil,
+		Comment:    nil,
+		TargetType: reactions.TargetTypePost,
+		TargetID:   scopeID,
+	})
+}
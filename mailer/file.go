@@ -0,0 +1,52 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FileMailer drops messages as .eml files into a directory instead of sending them, for development.
+type FileMailer struct {
+	dir  string
+	from string
+	now  func() time.Time
+}
+
+var _ Mailer = (*FileMailer)(nil)
+
+func NewFileMailer(dir, from string) *FileMailer {
+	return &FileMailer{
+		dir:  dir,
+		from: from,
+		now:  time.Now,
+	}
+}
+
+func (mailer *FileMailer) Send(_ context.Context, msg *Message) error {
+	now := mailer.now()
+
+	data, err := msg.Bytes(mailer.from, now)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	err = os.MkdirAll(mailer.dir, 0o750)
+	if err != nil {
+		return fmt.Errorf("failed to create mail directory: %w", err)
+	}
+
+	// Named by time first, so the directory lists messages in the order they were sent.
+	name := now.UTC().Format("20060102T150405.000000000Z") + "-" + uuid.NewString() + ".eml"
+
+	err = os.WriteFile(filepath.Join(mailer.dir, name), data, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+// Package mailer sends email. Messages are composed once and handed to a Mailer, which delivers them over SMTP or
+// drops them into a directory for development.
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Mailer sends messages.
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Message is an email with plain text and HTML alternatives of the same content. The sender is set by the Mailer.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+	// Headers are added to the message, like List-Unsubscribe.
+	Headers map[string]string
+}
+
+// Bytes encodes the message from the sender in the Internet Message Format, dated at the time.
+func (msg *Message) Bytes(from string, date time.Time) ([]byte, error) {
+	var body bytes.Buffer
+
+	parts := multipart.NewWriter(&body)
+
+	for _, alternative := range []struct {
+		contentType string
+		content     string
+	}{
+		// The last alternative is the preferred one.
+		{contentType: "text/plain; charset=utf-8", content: msg.Text},
+		{contentType: "text/html; charset=utf-8", content: msg.HTML},
+	} {
+		part, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {alternative.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create part: %w", err)
+		}
+
+		qp := quotedprintable.NewWriter(part)
+
+		_, err = qp.Write([]byte(alternative.content))
+		if err != nil {
+			return nil, fmt.Errorf("failed to write part: %w", err)
+		}
+
+		err = qp.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to close part: %w", err)
+		}
+	}
+
+	err := parts.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to close multipart writer: %w", err)
+	}
+
+	headers := map[string]string{
+		"From":         from,
+		"To":           msg.To,
+		"Subject":      mime.QEncoding.Encode("utf-8", msg.Subject),
+		"Date":         date.Format(time.RFC1123Z),
+		"Message-ID":   "<" + uuid.NewString() + "@scribble>",
+		"MIME-Version": "1.0",
+		"Content-Type": "multipart/alternative; boundary=" + parts.Boundary(),
+	}
+
+	for name, value := range msg.Headers {
+		headers[textproto.CanonicalMIMEHeaderKey(name)] = value
+	}
+
+	var buf bytes.Buffer
+
+	// Sorted, so messages are stable for tests and diffs.
+	for _, name := range slices.Sorted(maps.Keys(headers)) {
+		if strings.ContainsAny(headers[name], "\r\n") {
+			return nil, InvalidHeaderError{Name: name}
+		}
+
+		fmt.Fprintf(&buf, "%s: %s\r\n", name, headers[name])
+	}
+
+	buf.WriteString("\r\n")
+	buf.Write(body.Bytes())
+
+	return buf.Bytes(), nil
+}
+
+// InvalidHeaderError is returned for header values spanning lines, which would inject headers of their own.
+type InvalidHeaderError struct {
+	Name string
+}
+
+func (err InvalidHeaderError) Error() string {
+	return fmt.Sprintf("invalid value of header %q", err.Name)
+}
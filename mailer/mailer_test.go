@@ -0,0 +1,112 @@
+package mailer_test
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/mailer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageBytes(t *testing.T) {
+	msg := &mailer.Message{
+		To:      "alice@example.com",
+		Subject: "Hi ✌️",
+		Text:    "Hello, alice!",
+		HTML:    "<p>Hello, <b>alice</b>!</p>",
+		Headers: map[string]string{"list-unsubscribe": "<https://example.com/unsubscribe>"},
+	}
+
+	data, err := msg.Bytes("Scribble <no-reply@example.com>", time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	parsed, err := mail.ReadMessage(strings.NewReader(string(data)))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Scribble <no-reply@example.com>", parsed.Header.Get("From"))
+	assert.Equal(t, "alice@example.com", parsed.Header.Get("To"))
+	assert.Equal(t, "<https://example.com/unsubscribe>", parsed.Header.Get("List-Unsubscribe"))
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Hi ✌️", subject)
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	parts := multipart.NewReader(parsed.Body, params["boundary"])
+
+	for _, want := range []struct {
+		contentType string
+		content     string
+	}{
+		{contentType: "text/plain; charset=utf-8", content: "Hello, alice!"},
+		{contentType: "text/html; charset=utf-8", content: "<p>Hello, <b>alice</b>!</p>"},
+	} {
+		part, err := parts.NextPart()
+		require.NoError(t, err)
+		assert.Equal(t, want.contentType, part.Header.Get("Content-Type"))
+
+		// The reader decodes quoted-printable parts.
+		content, err := io.ReadAll(part)
+		require.NoError(t, err)
+		assert.Equal(t, want.content, string(content))
+	}
+
+	_, err = parts.NextPart()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestMessageBytesRejectsHeaderInjection(t *testing.T) {
+	msg := &mailer.Message{
+		To:      "alice@example.com\r\nBcc: mallory@example.com",
+		Subject: "Hi",
+		Text:    "Hello",
+		HTML:    "<p>Hello</p>",
+		Headers: nil,
+	}
+
+	_, err := msg.Bytes("no-reply@example.com", time.Now())
+	require.ErrorAs(t, err, &mailer.InvalidHeaderError{})
+}
+
+func TestFileMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	m := mailer.NewFileMailer(dir, "no-reply@example.com")
+
+	err := m.Send(t.Context(), &mailer.Message{
+		To:      "alice@example.com",
+		Subject: "Hi",
+		Text:    "Hello",
+		HTML:    "<p>Hello</p>",
+		Headers: nil,
+	})
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, ".eml", filepath.Ext(entries[0].Name()))
+
+	f, err := os.Open(filepath.Join(dir, entries[0].Name()))
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := f.Close()
+		require.NoError(t, err)
+	})
+
+	parsed, err := mail.ReadMessage(f)
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", parsed.Header.Get("To"))
+	assert.Equal(t, "no-reply@example.com", parsed.Header.Get("From"))
+}
@@ -0,0 +1,71 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+)
+
+type SMTPConfig struct {
+	// Addr is the host and port of the server, like smtp.example.com:587.
+	Addr string
+	// Username and Password authenticate with PLAIN auth, which the client only sends over TLS or to localhost.
+	// Empty skips authentication.
+	Username string
+	Password string
+	// From is the sender of all messages.
+	From string
+}
+
+// SMTPMailer sends messages through an SMTP server, with STARTTLS when the server offers it.
+type SMTPMailer struct {
+	cfg SMTPConfig
+	now func() time.Time
+}
+
+var _ Mailer = (*SMTPMailer)(nil)
+
+func NewSMTPMailer(cfg SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{
+		cfg: cfg,
+		now: time.Now,
+	}
+}
+
+func (mailer *SMTPMailer) Send(_ context.Context, msg *Message) error {
+	from, err := mail.ParseAddress(mailer.cfg.From)
+	if err != nil {
+		return fmt.Errorf("failed to parse sender address: %w", err)
+	}
+
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("failed to parse recipient address: %w", err)
+	}
+
+	data, err := msg.Bytes(mailer.cfg.From, mailer.now())
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	var auth smtp.Auth
+
+	if mailer.cfg.Username != "" {
+		host, _, err := net.SplitHostPort(mailer.cfg.Addr)
+		if err != nil {
+			return fmt.Errorf("failed to split server address: %w", err)
+		}
+
+		auth = smtp.PlainAuth("", mailer.cfg.Username, mailer.cfg.Password, host)
+	}
+
+	err = smtp.SendMail(mailer.cfg.Addr, auth, from.Address, []string{to.Address}, data)
+	if err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package mentions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nasermirzaei89/scribble/authorization"
+)
+
+const ActionListMyMentions = "listMyMentions"
+
+type AuthorizationMiddleware struct {
+	authzClient *authorization.Client
+	next        Service
+}
+
+var _ Service = (*AuthorizationMiddleware)(nil)
+
+func NewAuthorizationMiddleware(authzClient *authorization.Client, next Service) *AuthorizationMiddleware {
+	return &AuthorizationMiddleware{
+		authzClient: authzClient,
+		next:        next,
+	}
+}
+
+func (mw *AuthorizationMiddleware) ListMyMentions(ctx context.Context, req ListMentionsRequest) ([]*Mention, error) {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, "", ActionListMyMentions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	mentions, err := mw.next.ListMyMentions(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return mentions, nil
+}
@@ -0,0 +1,185 @@
+package mentions
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// usernamePattern matches a mention at the start of the line. Trailing dots and hyphens end the sentence rather than
+// the username.
+var usernamePattern = regexp.MustCompile(`^@([\p{L}\p{N}_](?:[\p{L}\p{N}_.-]*[\p{L}\p{N}_])?)`)
+
+// Resolver resolves mentioned usernames to the URLs of their profiles.
+type Resolver interface {
+	ResolveMention(username string) (url string, ok bool)
+}
+
+var KindMention = ast.NewNodeKind("Mention")
+
+// Node is a mention of a user in Markdown content.
+type Node struct {
+	ast.BaseInline
+
+	Username string
+	// URL is the profile of the user, empty when mentions are not resolved.
+	URL string
+}
+
+func (node *Node) Kind() ast.NodeKind {
+	return KindMention
+}
+
+func (node *Node) Dump(source []byte, level int) {
+	ast.DumpHelper(node, source, level, map[string]string{"Username": node.Username, "URL": node.URL}, nil)
+}
+
+type mentionParser struct {
+	resolver Resolver
+}
+
+func (p *mentionParser) Trigger() []byte {
+	return []byte{'@'}
+}
+
+func (p *mentionParser) Parse(_ ast.Node, block text.Reader, _ parser.Context) ast.Node {
+	// Mentions start words, so email addresses and the hosts of remote handles are not mentions.
+	before := block.PrecendingCharacter()
+	if unicode.IsLetter(before) || unicode.IsNumber(before) || strings.ContainsRune("_.-@/", before) {
+		return nil
+	}
+
+	line, _ := block.PeekLine()
+
+	match := usernamePattern.FindSubmatch(line)
+	if match == nil {
+		return nil
+	}
+
+	// Remote handles, like @user@example.com, are left alone.
+	if len(line) > len(match[0]) && line[len(match[0])] == '@' {
+		return nil
+	}
+
+	node := &Node{
+		BaseInline: ast.BaseInline{},
+		Username:   string(match[1]),
+		URL:        "",
+	}
+
+	if p.resolver != nil {
+		url, ok := p.resolver.ResolveMention(node.Username)
+		if !ok {
+			return nil
+		}
+
+		node.URL = url
+	}
+
+	block.Advance(len(match[0]))
+
+	return node
+}
+
+type mentionRenderer struct{}
+
+var _ renderer.NodeRenderer = (*mentionRenderer)(nil)
+
+func (r *mentionRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindMention, r.renderMention)
+}
+
+func (r *mentionRenderer) renderMention(
+	w util.BufWriter,
+	_ []byte,
+	n ast.Node,
+	entering bool,
+) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	node, _ := n.(*Node)
+
+	if node.URL == "" || inLink(node) {
+		_, _ = w.WriteString("@")
+		_, _ = w.Write(util.EscapeHTML([]byte(node.Username)))
+
+		return ast.WalkSkipChildren, nil
+	}
+
+	_, _ = w.WriteString(`<a href="`)
+	_, _ = w.Write(util.EscapeHTML(util.URLEscape([]byte(node.URL), true)))
+	_, _ = w.WriteString(`" class="mention">@`)
+	_, _ = w.Write(util.EscapeHTML([]byte(node.Username)))
+	_, _ = w.WriteString(`</a>`)
+
+	return ast.WalkSkipChildren, nil
+}
+
+// inLink reports whether the node is in the text of a link, which cannot hold another one.
+func inLink(node ast.Node) bool {
+	for parent := node.Parent(); parent != nil; parent = parent.Parent() {
+		if parent.Kind() == ast.KindLink || parent.Kind() == ast.KindAutoLink {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Extension renders @username mentions as links to the profiles of the users. Mentions the resolver does not
+// resolve stay plain text.
+type Extension struct {
+	resolver Resolver
+}
+
+var _ goldmark.Extender = (*Extension)(nil)
+
+// NewExtension returns the extension. Without a resolver, every mention is parsed and none is linked.
+func NewExtension(resolver Resolver) *Extension {
+	return &Extension{resolver: resolver}
+}
+
+func (e *Extension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithInlineParsers(
+		util.Prioritized(&mentionParser{resolver: e.resolver}, 500),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&mentionRenderer{}, 500),
+	))
+}
+
+// Usernames returns the users mentioned in the Markdown content, each once, in the order they are first mentioned.
+// Mentions in code and links are not mentions.
+func Usernames(content string) []string {
+	source := []byte(content)
+	md := goldmark.New(goldmark.WithExtensions(extension.GFM, NewExtension(nil)))
+	doc := md.Parser().Parse(text.NewReader(source))
+
+	usernames := []string{}
+
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		node, ok := n.(*Node)
+		if ok && !inLink(node) && !slices.Contains(usernames, node.Username) {
+			usernames = append(usernames, node.Username)
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return usernames
+}
@@ -0,0 +1,84 @@
+package mentions_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nasermirzaei89/scribble/mentions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+type stubResolver map[string]string
+
+func (resolver stubResolver) ResolveMention(username string) (string, bool) {
+	url, ok := resolver[username]
+
+	return url, ok
+}
+
+func TestExtension(t *testing.T) {
+	md := goldmark.New(goldmark.WithExtensions(
+		extension.GFM,
+		mentions.NewExtension(stubResolver{"alice": "/u/alice", "bob.smith": "/u/bob.smith"}),
+	))
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "known user",
+			content:  "Hi @alice!",
+			expected: `<p>Hi <a href="/u/alice" class="mention">@alice</a>!</p>`,
+		},
+		{
+			name:     "trailing dot",
+			content:  "Thanks @bob.smith.",
+			expected: `<p>Thanks <a href="/u/bob.smith" class="mention">@bob.smith</a>.</p>`,
+		},
+		{
+			name:     "unknown user",
+			content:  "Hi @carol",
+			expected: `<p>Hi @carol</p>`,
+		},
+		{
+			name:     "email address",
+			content:  "Mail me at me@alice",
+			expected: `<p>Mail me at me@alice</p>`,
+		},
+		{
+			name:     "remote handle",
+			content:  "Hi @alice@example.com",
+			expected: `<p>Hi @alice@example.com</p>`,
+		},
+		{
+			name:     "code",
+			content:  "Run `@alice`",
+			expected: `<p>Run <code>@alice</code></p>`,
+		},
+		{
+			name:     "link text",
+			content:  "[@alice](https://example.com)",
+			expected: `<p><a href="https://example.com">@alice</a></p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := md.Convert([]byte(tt.content), &buf)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected+"\n", buf.String())
+		})
+	}
+}
+
+func TestUsernames(t *testing.T) {
+	usernames := mentions.Usernames("@alice and @bob, again @alice.\n\n```\n@carol\n```\n\nme@dave [@erin](/x)")
+	assert.Equal(t, []string{"alice", "bob"}, usernames)
+}
@@ -0,0 +1,29 @@
+package mentions
+
+import (
+	"context"
+	"time"
+)
+
+// Mention records that the author of a post or comment mentioned a user in it.
+type Mention struct {
+	ID       string
+	UserID   string
+	AuthorID string
+	PostID   string
+	// CommentID is the comment the user is mentioned in, empty for mentions in the post itself.
+	CommentID string
+	CreatedAt time.Time
+}
+
+type MentionRepository interface {
+	Insert(ctx context.Context, mention *Mention) (err error)
+	// List lists the mentions of the user, the newest first.
+	List(ctx context.Context, params *ListMentionsParams) (mentions []*Mention, err error)
+}
+
+type ListMentionsParams struct {
+	UserID string
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,60 @@
+package mentions
+
+import (
+	"context"
+	"fmt"
+
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/authorization"
+)
+
+const ServiceName = "github.com/nasermirzaei89/scribble/mentions"
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 200
+)
+
+// Service lists where users were mentioned. Mentions are recorded by the Recorder.
+type Service interface {
+	ListMyMentions(ctx context.Context, req ListMentionsRequest) ([]*Mention, error)
+}
+
+type BaseService struct {
+	mentionRepo MentionRepository
+}
+
+var _ Service = (*BaseService)(nil)
+
+func NewService(mentionRepo MentionRepository, authzClient *authorization.Client) Service { //nolint:ireturn
+	return NewAuthorizationMiddleware(authzClient, NewBaseService(mentionRepo))
+}
+
+func NewBaseService(mentionRepo MentionRepository) *BaseService {
+	return &BaseService{
+		mentionRepo: mentionRepo,
+	}
+}
+
+type ListMentionsRequest struct {
+	Limit  int
+	Offset int
+}
+
+func (svc *BaseService) ListMyMentions(ctx context.Context, req ListMentionsRequest) ([]*Mention, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	mentions, err := svc.mentionRepo.List(ctx, &ListMentionsParams{
+		UserID: authcontext.GetSubject(ctx),
+		Limit:  min(limit, maxListLimit),
+		Offset: max(req.Offset, 0),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mentions: %w", err)
+	}
+
+	return mentions, nil
+}
@@ -0,0 +1,111 @@
+package mentions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nasermirzaei89/scribble/authentication"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/discuss"
+	"github.com/nasermirzaei89/scribble/events"
+)
+
+// Recorder records the mentions in new posts and comments, in the transaction they are created in, and publishes a
+// UserMentioned event for each.
+type Recorder struct {
+	bus         *events.Bus
+	mentionRepo MentionRepository
+	userRepo    authentication.UserRepository
+	postRepo    contents.PostRepository
+	commentRepo discuss.CommentRepository
+	now         func() time.Time
+}
+
+func NewRecorder(
+	bus *events.Bus,
+	mentionRepo MentionRepository,
+	userRepo authentication.UserRepository,
+	postRepo contents.PostRepository,
+	commentRepo discuss.CommentRepository,
+) *Recorder {
+	return &Recorder{
+		bus:         bus,
+		mentionRepo: mentionRepo,
+		userRepo:    userRepo,
+		postRepo:    postRepo,
+		commentRepo: commentRepo,
+		now:         time.Now,
+	}
+}
+
+func (recorder *Recorder) Subscribe() {
+	events.Subscribe(recorder.bus, recorder.handlePostCreated)
+	events.Subscribe(recorder.bus, recorder.handleCommentCreated)
+}
+
+func (recorder *Recorder) handlePostCreated(ctx context.Context, event events.PostCreated) error {
+	post, err := recorder.postRepo.Find(ctx, event.PostID)
+	if err != nil {
+		return fmt.Errorf("failed to find post: %w", err)
+	}
+
+	return recorder.record(ctx, post.Content, post.AuthorID, post.ID, "")
+}
+
+func (recorder *Recorder) handleCommentCreated(ctx context.Context, event events.CommentCreated) error {
+	comment, err := recorder.commentRepo.Find(ctx, event.CommentID)
+	if err != nil {
+		return fmt.Errorf("failed to find comment: %w", err)
+	}
+
+	return recorder.record(ctx, comment.Content, comment.AuthorID, comment.PostID, comment.ID)
+}
+
+// record records the mentions of existing users in the content. Authors mentioning themselves are not recorded.
+func (recorder *Recorder) record(ctx context.Context, content, authorID, postID, commentID string) error {
+	for _, username := range Usernames(content) {
+		user, err := recorder.userRepo.FindByUsername(ctx, username)
+		if err != nil {
+			if _, ok := errors.AsType[*authentication.UserByUsernameNotFoundError](err); ok {
+				continue
+			}
+
+			return fmt.Errorf("failed to find user by username: %w", err)
+		}
+
+		if user.ID == authorID {
+			continue
+		}
+
+		mention := &Mention{
+			ID:        uuid.NewString(),
+			UserID:    user.ID,
+			AuthorID:  authorID,
+			PostID:    postID,
+			CommentID: commentID,
+			CreatedAt: recorder.now(),
+		}
+
+		err = recorder.mentionRepo.Insert(ctx, mention)
+		if err != nil {
+			return fmt.Errorf("failed to insert mention: %w", err)
+		}
+
+		err = recorder.bus.Publish(ctx, events.UserMentioned{
+			MentionID: mention.ID,
+			UserID:    mention.UserID,
+			AuthorID:  mention.AuthorID,
+			PostID:    mention.PostID,
+			CommentID: mention.CommentID,
+			CreatedAt: mention.CreatedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to publish event: %w", err)
+		}
+	}
+
+	return nil
+}
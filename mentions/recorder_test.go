@@ -0,0 +1,114 @@
+package mentions_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/authentication"
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/database/sqlite3"
+	"github.com/nasermirzaei89/scribble/discuss"
+	"github.com/nasermirzaei89/scribble/events"
+	"github.com/nasermirzaei89/scribble/mentions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sqlite3.NewDB(ctx, "file:TestRecorder?mode=memory&cache=shared")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := db.Close()
+		require.NoError(t, err)
+	})
+
+	err = sqlite3.MigrateUp(ctx, db)
+	require.NoError(t, err)
+
+	userRepo := sqlite3.NewUserRepository(db)
+	postRepo := sqlite3.NewPostRepository(db)
+	commentRepo := sqlite3.NewCommentRepository(db)
+	commentEditRepo := sqlite3.NewCommentEditRepository(db)
+	mentionRepo := sqlite3.NewMentionRepository(db)
+
+	bus := events.NewBus(sqlite3.NewEventOutboxRepository(db), sqlite3.NewTransactor(db))
+	mentions.NewRecorder(bus, mentionRepo, userRepo, postRepo, commentRepo).Subscribe()
+
+	mentioned := make([]events.UserMentioned, 0)
+
+	events.SubscribeAsync(bus, func(_ context.Context, event events.UserMentioned) error {
+		mentioned = append(mentioned, event)
+
+		return nil
+	})
+
+	for _, username := range []string{"alice", "bob", "carol"} {
+		err := userRepo.Insert(ctx, &authentication.User{
+			ID:           username + "-id",
+			Username:     username,
+			PasswordHash: "password-hash",
+			RegisteredAt: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+		})
+		require.NoError(t, err)
+	}
+
+	contentsSvc := contents.NewEventsMiddleware(bus, contents.NewBaseService(postRepo))
+	discussSvc := discuss.NewEventsMiddleware(bus, discuss.NewBaseService(commentRepo, commentEditRepo))
+	svc := mentions.NewBaseService(mentionRepo)
+
+	post, err := contentsSvc.CreatePost(ctx, contents.CreatePostRequest{
+		AuthorID:    "alice-id",
+		CommunityID: "",
+		Content:     "Hello @bob, @alice and @nobody. `@carol`",
+	})
+	require.NoError(t, err)
+
+	comment, err := discussSvc.CreateComment(ctx, discuss.CreateCommentRequest{
+		PostID:   post.ID,
+		AuthorID: "carol-id",
+		Content:  "@bob @bob",
+		ReplyTo:  "",
+	})
+	require.NoError(t, err)
+
+	_, err = bus.ProcessOutbox(ctx)
+	require.NoError(t, err)
+
+	bobMentions, err := svc.ListMyMentions(
+		authcontext.WithSubject(ctx, "bob-id"),
+		mentions.ListMentionsRequest{Limit: 0, Offset: 0},
+	)
+	require.NoError(t, err)
+	require.Len(t, bobMentions, 2)
+
+	postMention, commentMention := bobMentions[1], bobMentions[0]
+	if postMention.CommentID != "" {
+		postMention, commentMention = commentMention, postMention
+	}
+
+	assert.Equal(t, "alice-id", postMention.AuthorID)
+	assert.Equal(t, post.ID, postMention.PostID)
+	assert.Empty(t, postMention.CommentID)
+	assert.Equal(t, "carol-id", commentMention.AuthorID)
+	assert.Equal(t, comment.ID, commentMention.CommentID)
+
+	for _, userID := range []string{"alice-id", "carol-id"} {
+		list, err := svc.ListMyMentions(
+			authcontext.WithSubject(ctx, userID),
+			mentions.ListMentionsRequest{Limit: 0, Offset: 0},
+		)
+		require.NoError(t, err)
+		assert.Empty(t, list)
+	}
+
+	require.Len(t, mentioned, 2)
+	assert.ElementsMatch(t, []string{postMention.ID, commentMention.ID}, []string{
+		mentioned[0].MentionID,
+		mentioned[1].MentionID,
+	})
+}
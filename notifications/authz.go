@@ -0,0 +1,163 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nasermirzaei89/scribble/authorization"
+)
+
+const (
+	ActionListMyNotifications        = "listMyNotifications"
+	ActionCountMyUnreadNotifications = "countMyUnreadNotifications"
+	ActionMarkNotificationRead       = "markNotificationRead"
+	ActionMarkAllNotificationsRead   = "markAllNotificationsRead"
+	ActionGetMyPreferences           = "getMyPreferences"
+	ActionSetMyPreferences           = "setMyPreferences"
+	ActionGetMyEmailSettings         = "getMyEmailSettings"
+	ActionSetMyEmailSettings         = "setMyEmailSettings"
+	ActionUnsubscribeFromEmails      = "unsubscribeFromEmails"
+)
+
+type AuthorizationMiddleware struct {
+	authzClient *authorization.Client
+	next        Service
+}
+
+var _ Service = (*AuthorizationMiddleware)(nil)
+
+func NewAuthorizationMiddleware(authzClient *authorization.Client, next Service) *AuthorizationMiddleware {
+	return &AuthorizationMiddleware{
+		authzClient: authzClient,
+		next:        next,
+	}
+}
+
+func (mw *AuthorizationMiddleware) ListMyNotifications(
+	ctx context.Context,
+	req ListNotificationsRequest,
+) ([]*Notification, error) {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, "", ActionListMyNotifications)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	notifications, err := mw.next.ListMyNotifications(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return notifications, nil
+}
+
+func (mw *AuthorizationMiddleware) CountMyUnreadNotifications(ctx context.Context) (int, error) {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, "", ActionCountMyUnreadNotifications)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	count, err := mw.next.CountMyUnreadNotifications(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return count, nil
+}
+
+func (mw *AuthorizationMiddleware) MarkNotificationRead(ctx context.Context, notificationID string) error {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, "", ActionMarkNotificationRead)
+	if err != nil {
+		return fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	err = mw.next.MarkNotificationRead(ctx, notificationID)
+	if err != nil {
+		return fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return nil
+}
+
+func (mw *AuthorizationMiddleware) MarkAllNotificationsRead(ctx context.Context) error {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, "", ActionMarkAllNotificationsRead)
+	if err != nil {
+		return fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	err = mw.next.MarkAllNotificationsRead(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return nil
+}
+
+func (mw *AuthorizationMiddleware) GetMyPreferences(ctx context.Context) (*Preferences, error) {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, "", ActionGetMyPreferences)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	prefs, err := mw.next.GetMyPreferences(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return prefs, nil
+}
+
+func (mw *AuthorizationMiddleware) SetMyPreferences(ctx context.Context, prefs Preferences) error {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, "", ActionSetMyPreferences)
+	if err != nil {
+		return fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	err = mw.next.SetMyPreferences(ctx, prefs)
+	if err != nil {
+		return fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return nil
+}
+
+func (mw *AuthorizationMiddleware) GetMyEmailSettings(ctx context.Context) (*EmailSettings, error) {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, "", ActionGetMyEmailSettings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	settings, err := mw.next.GetMyEmailSettings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return settings, nil
+}
+
+func (mw *AuthorizationMiddleware) SetMyEmailSettings(ctx context.Context, req SetEmailSettingsRequest) error {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, "", ActionSetMyEmailSettings)
+	if err != nil {
+		return fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	err = mw.next.SetMyEmailSettings(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return nil
+}
+
+func (mw *AuthorizationMiddleware) UnsubscribeFromEmails(ctx context.Context, userID, token string) error {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, "", ActionUnsubscribeFromEmails)
+	if err != nil {
+		return fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	err = mw.next.UnsubscribeFromEmails(ctx, userID, token)
+	if err != nil {
+		return fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package notifications_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	fileadapter "github.com/casbin/casbin/v3/persist/file-adapter"
+	"github.com/google/uuid"
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/authorization/casbin"
+	"github.com/nasermirzaei89/scribble/notifications"
+	"github.com/stretchr/testify/require"
+)
+
+type stubService struct{}
+
+func (s *stubService) ListMyNotifications(
+	ctx context.Context,
+	req notifications.ListNotificationsRequest,
+) ([]*notifications.Notification, error) {
+	return []*notifications.Notification{}, nil
+}
+
+func (s *stubService) CountMyUnreadNotifications(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+func (s *stubService) MarkNotificationRead(ctx context.Context, notificationID string) error {
+	return nil
+}
+
+func (s *stubService) MarkAllNotificationsRead(ctx context.Context) error {
+	return nil
+}
+
+func (s *stubService) GetMyPreferences(ctx context.Context) (*notifications.Preferences, error) {
+	return &notifications.Preferences{Muted: []notifications.Type{}}, nil
+}
+
+func (s *stubService) SetMyPreferences(ctx context.Context, prefs notifications.Preferences) error {
+	return nil
+}
+
+func (s *stubService) GetMyEmailSettings(ctx context.Context) (*notifications.EmailSettings, error) {
+	return &notifications.EmailSettings{}, nil
+}
+
+func (s *stubService) SetMyEmailSettings(ctx context.Context, req notifications.SetEmailSettingsRequest) error {
+	return nil
+}
+
+func (s *stubService) UnsubscribeFromEmails(ctx context.Context, userID, token string) error {
+	return nil
+}
+
+func TestAuthorizationMiddleware(t *testing.T) {
+	ctx := context.Background()
+
+	tmpDir := t.TempDir()
+	tmpFile := filepath.Join(tmpDir, "policy.csv")
+	content := []byte(`g, system:anonymous, system:unauthenticated, *
+
+p, system:authenticated, github.com/nasermirzaei89/scribble/notifications, -, listMyNotifications
+p, system:authenticated, github.com/nasermirzaei89/scribble/notifications, -, countMyUnreadNotifications
+p, system:authenticated, github.com/nasermirzaei89/scribble/notifications, -, markNotificationRead
+p, system:authenticated, github.com/nasermirzaei89/scribble/notifications, -, markAllNotificationsRead
+p, system:authenticated, github.com/nasermirzaei89/scribble/notifications, -, getMyPreferences
+p, system:authenticated, github.com/nasermirzaei89/scribble/notifications, -, setMyPreferences
+p, system:authenticated, github.com/nasermirzaei89/scribble/notifications, -, getMyEmailSettings
+p, system:authenticated, github.com/nasermirzaei89/scribble/notifications, -, setMyEmailSettings
+p, system:authenticated, github.com/nasermirzaei89/scribble/notifications, -, unsubscribeFromEmails
+p, system:unauthenticated, github.com/nasermirzaei89/scribble/notifications, -, unsubscribeFromEmails
+`)
+
+	err := os.WriteFile(tmpFile, content, 0o600)
+	require.NoError(t, err)
+
+	adapter := fileadapter.NewAdapter(tmpFile)
+
+	provider, err := casbin.NewAuthorizationProvider(adapter)
+	require.NoError(t, err)
+
+	authzSvc, err := authorization.NewService(provider)
+	require.NoError(t, err)
+
+	client := authorization.NewClient(authzSvc)
+	svc := notifications.NewAuthorizationMiddleware(client, &stubService{})
+
+	userID := uuid.NewString()
+	err = client.AddToGroup(ctx, userID, authcontext.Authenticated)
+	require.NoError(t, err)
+
+	calls := map[string]func(ctx context.Context) error{
+		"ListMyNotifications": func(ctx context.Context) error {
+			_, err := svc.ListMyNotifications(ctx, notifications.ListNotificationsRequest{})
+
+			return err
+		},
+		"CountMyUnreadNotifications": func(ctx context.Context) error {
+			_, err := svc.CountMyUnreadNotifications(ctx)
+
+			return err
+		},
+		"MarkNotificationRead": func(ctx context.Context) error {
+			return svc.MarkNotificationRead(ctx, "notification1")
+		},
+		"MarkAllNotificationsRead": func(ctx context.Context) error {
+			return svc.MarkAllNotificationsRead(ctx)
+		},
+		"GetMyPreferences": func(ctx context.Context) error {
+			_, err := svc.GetMyPreferences(ctx)
+
+			return err
+		},
+		"SetMyPreferences": func(ctx context.Context) error {
+			return svc.SetMyPreferences(ctx, notifications.Preferences{})
+		},
+		"GetMyEmailSettings": func(ctx context.Context) error {
+			_, err := svc.GetMyEmailSettings(ctx)
+
+			return err
+		},
+		"SetMyEmailSettings": func(ctx context.Context) error {
+			return svc.SetMyEmailSettings(ctx, notifications.SetEmailSettingsRequest{})
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			err := call(ctx)
+			require.Error(t, err)
+
+			accessDeniedErr := &authorization.AccessDeniedError{}
+			require.ErrorAs(t, err, &accessDeniedErr)
+
+			err = call(authcontext.WithSubject(ctx, userID))
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("UnsubscribeFromEmails", func(t *testing.T) {
+		// Unsubscribe links in emails work without logging in.
+		err := svc.UnsubscribeFromEmails(ctx, userID, "token")
+		require.NoError(t, err)
+
+		err = svc.UnsubscribeFromEmails(authcontext.WithSubject(ctx, userID), userID, "token")
+		require.NoError(t, err)
+	})
+}
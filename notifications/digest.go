@@ -0,0 +1,279 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"log/slog"
+	"net/url"
+	"strconv"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/authentication"
+	"github.com/nasermirzaei89/scribble/mailer"
+)
+
+const (
+	siteName = "Scribble"
+
+	// digestSize is how many notifications an email lists at most. The rest are on the notifications page.
+	digestSize = 20
+
+	// DigestInterval is how often the digester checks for emails due.
+	DigestInterval = time.Minute
+)
+
+//go:embed templates/*
+var templatesFS embed.FS
+
+type DigestConfig struct {
+	// BaseURL is the URL of the site, which links in emails point to.
+	BaseURL string
+	// UnsubscribeKey signs the unsubscribe links. The service verifies them with the same key.
+	UnsubscribeKey []byte
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Digester emails users about their unread notifications, at the frequency they chose. Each email lists the
+// notifications updated since the last one.
+type Digester struct {
+	baseURL           string
+	unsubscribeKey    []byte
+	now               func() time.Time
+	mailer            mailer.Mailer
+	emailSettingsRepo EmailSettingsRepository
+	notificationRepo  NotificationRepository
+	userRepo          authentication.UserRepository
+	htmlTemplate      *htmltemplate.Template
+	textTemplate      *texttemplate.Template
+}
+
+func NewDigester(
+	cfg DigestConfig,
+	mailer mailer.Mailer,
+	emailSettingsRepo EmailSettingsRepository,
+	notificationRepo NotificationRepository,
+	userRepo authentication.UserRepository,
+) *Digester {
+	now := cfg.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	return &Digester{
+		baseURL:           cfg.BaseURL,
+		unsubscribeKey:    cfg.UnsubscribeKey,
+		now:               now,
+		mailer:            mailer,
+		emailSettingsRepo: emailSettingsRepo,
+		notificationRepo:  notificationRepo,
+		userRepo:          userRepo,
+		htmlTemplate:      htmltemplate.Must(htmltemplate.ParseFS(templatesFS, "templates/digest.html")),
+		textTemplate:      texttemplate.Must(texttemplate.ParseFS(templatesFS, "templates/digest.txt")),
+	}
+}
+
+// SendDigests emails the users whose emails are due. It returns how many emails were sent. Failed emails are logged
+// and tried again on the next call.
+func (digester *Digester) SendDigests(ctx context.Context) (int, error) {
+	enabled, err := digester.emailSettingsRepo.ListEnabled(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list enabled email settings: %w", err)
+	}
+
+	sent := 0
+
+	for _, settings := range enabled {
+		now := digester.now()
+		if !settings.IsDue(now) {
+			continue
+		}
+
+		ok, err := digester.send(ctx, settings)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to send notification email", "userId", settings.UserID, "error", err)
+
+			continue
+		}
+
+		if ok {
+			sent++
+		}
+
+		settings.LastSentAt = now
+
+		err = digester.emailSettingsRepo.Save(ctx, settings)
+		if err != nil {
+			return sent, fmt.Errorf("failed to save email settings of user %q: %w", settings.UserID, err)
+		}
+	}
+
+	return sent, nil
+}
+
+// digestItem is a notification as emails list it.
+type digestItem struct {
+	Summary string
+	URL     string
+}
+
+// send emails the user about the notifications updated since the last email, if any. It reports whether it did.
+func (digester *Digester) send(ctx context.Context, settings *EmailSettings) (bool, error) {
+	user, err := digester.userRepo.Find(ctx, settings.UserID)
+	if err != nil {
+		return false, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	notifications, err := digester.notificationRepo.List(ctx, &ListNotificationsParams{
+		UserID:       settings.UserID,
+		UnreadOnly:   true,
+		UpdatedAfter: settings.LastSentAt,
+		Limit:        digestSize + 1,
+		Offset:       0,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to list notifications: %w", err)
+	}
+
+	if len(notifications) == 0 {
+		return false, nil
+	}
+
+	more := len(notifications) > digestSize
+	notifications = notifications[:min(len(notifications), digestSize)]
+
+	items := make([]digestItem, 0, len(notifications))
+	for _, notification := range notifications {
+		items = append(items, digestItem{
+			Summary: Summary(notification, digester.actorNames(ctx, notification)),
+			URL:     digester.baseURL + Path(notification),
+		})
+	}
+
+	count := strconv.Itoa(len(notifications))
+	if more {
+		count = "More than " + count
+	}
+
+	noun := " new notifications"
+	if len(notifications) == 1 {
+		noun = " new notification"
+	}
+
+	subject := count + noun + " on " + siteName
+	intro := "Here is what just happened on " + siteName + ":"
+	cadence := "as things happen"
+
+	switch settings.Frequency {
+	case EmailFrequencyDaily:
+		subject = "Your daily digest: " + count + noun
+		intro = "Here is what happened on " + siteName + " today:"
+		cadence = "daily"
+	case EmailFrequencyWeekly:
+		subject = "Your weekly digest: " + count + noun
+		intro = "Here is what happened on " + siteName + " this week:"
+		cadence = "weekly"
+	default:
+		if len(items) == 1 {
+			subject = items[0].Summary
+		}
+	}
+
+	unsubscribeURL := digester.UnsubscribeURL(settings.UserID)
+
+	data := map[string]any{
+		"Subject":          subject,
+		"Username":         user.Username,
+		"Intro":            intro,
+		"Items":            items,
+		"More":             more,
+		"Frequency":        cadence,
+		"SiteName":         siteName,
+		"NotificationsURL": digester.baseURL + "/notifications",
+		"UnsubscribeURL":   unsubscribeURL,
+	}
+
+	var htmlBody, textBody bytes.Buffer
+
+	err = digester.htmlTemplate.Execute(&htmlBody, data)
+	if err != nil {
+		return false, fmt.Errorf("failed to execute html template: %w", err)
+	}
+
+	err = digester.textTemplate.Execute(&textBody, data)
+	if err != nil {
+		return false, fmt.Errorf("failed to execute text template: %w", err)
+	}
+
+	err = digester.mailer.Send(ctx, &mailer.Message{
+		To:      settings.Address,
+		Subject: subject,
+		Text:    textBody.String(),
+		HTML:    htmlBody.String(),
+		Headers: map[string]string{
+			// One-click unsubscribe of RFC 8058.
+			"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return true, nil
+}
+
+// actorNames returns the usernames of the actors the summary of the notification names.
+func (digester *Digester) actorNames(ctx context.Context, notification *Notification) []string {
+	names := make([]string, 0, ActorsShown)
+
+	for _, actorID := range notification.ActorIDs[:min(len(notification.ActorIDs), ActorsShown)] {
+		user, err := digester.userRepo.Find(ctx, actorID)
+		if err != nil {
+			if _, ok := errors.AsType[*authentication.UserNotFoundError](err); !ok {
+				slog.ErrorContext(ctx, "failed to find notification actor", "actorId", actorID, "error", err)
+			}
+
+			names = append(names, "Someone")
+
+			continue
+		}
+
+		names = append(names, user.Username)
+	}
+
+	return names
+}
+
+// UnsubscribeURL returns the signed one-click unsubscribe link of the user.
+func (digester *Digester) UnsubscribeURL(userID string) string {
+	query := url.Values{
+		"user":  {userID},
+		"token": {UnsubscribeToken(digester.unsubscribeKey, userID)},
+	}
+
+	return digester.baseURL + "/email/unsubscribe?" + query.Encode()
+}
+
+func (digester *Digester) Run(ctx context.Context) {
+	ticker := time.NewTicker(DigestInterval)
+	defer ticker.Stop()
+
+	for {
+		_, err := digester.SendDigests(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to send notification digests", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package notifications_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/authentication"
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/database/sqlite3"
+	"github.com/nasermirzaei89/scribble/mailer"
+	"github.com/nasermirzaei89/scribble/notifications"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMailer struct {
+	sent []*mailer.Message
+}
+
+func (m *fakeMailer) Send(_ context.Context, msg *mailer.Message) error {
+	m.sent = append(m.sent, msg)
+
+	return nil
+}
+
+func TestDigester(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sqlite3.NewDB(ctx, "file:TestDigester?mode=memory&cache=shared")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := db.Close()
+		require.NoError(t, err)
+	})
+
+	err = sqlite3.MigrateUp(ctx, db)
+	require.NoError(t, err)
+
+	userRepo := sqlite3.NewUserRepository(db)
+	notificationRepo := sqlite3.NewNotificationRepository(db)
+	emailSettingsRepo := sqlite3.NewNotificationEmailSettingsRepository(db)
+
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	now := start
+
+	key := []byte("key")
+	mail := &fakeMailer{sent: nil}
+	digester := notifications.NewDigester(
+		notifications.DigestConfig{
+			BaseURL:        "https://scribble.example",
+			UnsubscribeKey: key,
+			Now:            func() time.Time { return now },
+		},
+		mail,
+		emailSettingsRepo,
+		notificationRepo,
+		userRepo,
+	)
+
+	for _, user := range []*authentication.User{
+		{ID: "alice", Username: "alice", PasswordHash: "", RegisteredAt: start},
+		{ID: "bob", Username: "bob", PasswordHash: "", RegisteredAt: start},
+		{ID: "carol", Username: "carol", PasswordHash: "", RegisteredAt: start},
+	} {
+		err = userRepo.Insert(ctx, user)
+		require.NoError(t, err)
+	}
+
+	for _, settings := range []*notifications.EmailSettings{
+		{UserID: "alice", Address: "alice@example.com", Frequency: notifications.EmailFrequencyDaily, LastSentAt: start},
+		{UserID: "bob", Address: "bob@example.com", Frequency: notifications.EmailFrequencyImmediate, LastSentAt: start},
+		{UserID: "carol", Address: "carol@example.com", Frequency: notifications.EmailFrequencyOff, LastSentAt: start},
+	} {
+		err = emailSettingsRepo.Save(ctx, settings)
+		require.NoError(t, err)
+	}
+
+	notify := func(t *testing.T, id, userID string, typ notifications.Type, actorIDs ...string) {
+		t.Helper()
+
+		err := notificationRepo.Insert(ctx, &notifications.Notification{
+			ID:        id,
+			UserID:    userID,
+			Type:      typ,
+			GroupKey:  id,
+			PostID:    "post1",
+			CommentID: "comment1",
+			Emoji:     "👍",
+			ActorIDs:  actorIDs,
+			ReadAt:    nil,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		require.NoError(t, err)
+	}
+
+	sendDigests := func(t *testing.T) []*mailer.Message {
+		t.Helper()
+
+		mail.sent = nil
+
+		_, err := digester.SendDigests(ctx)
+		require.NoError(t, err)
+
+		return mail.sent
+	}
+
+	t.Run("NothingNew", func(t *testing.T) {
+		now = start.Add(time.Hour)
+
+		assert.Empty(t, sendDigests(t))
+	})
+
+	t.Run("Immediate", func(t *testing.T) {
+		now = start.Add(2 * time.Hour)
+		notify(t, "n1", "bob", notifications.TypeReply, "alice")
+		notify(t, "n2", "carol", notifications.TypeReply, "alice")
+
+		now = start.Add(3 * time.Hour)
+		sent := sendDigests(t)
+		require.Len(t, sent, 1)
+
+		msg := sent[0]
+		assert.Equal(t, "bob@example.com", msg.To)
+		assert.Equal(t, "alice replied to your comment", msg.Subject)
+		assert.Contains(t, msg.Text, "Hi bob,")
+		assert.Contains(t, msg.Text, "https://scribble.example/p/post1#comment-comment1")
+		assert.Contains(t, msg.HTML, `<a href="https://scribble.example/p/post1#comment-comment1"`)
+
+		assert.Equal(t, "List-Unsubscribe=One-Click", msg.Headers["List-Unsubscribe-Post"])
+
+		unsubscribeURL := strings.Trim(msg.Headers["List-Unsubscribe"], "<>")
+		assert.Contains(t, msg.Text, unsubscribeURL)
+
+		parsed, err := url.Parse(unsubscribeURL)
+		require.NoError(t, err)
+		assert.Equal(t, "/email/unsubscribe", parsed.Path)
+		assert.Equal(t, "bob", parsed.Query().Get("user"))
+		assert.Equal(t, notifications.UnsubscribeToken(key, "bob"), parsed.Query().Get("token"))
+
+		// Sent notifications are not sent again.
+		now = start.Add(4 * time.Hour)
+		assert.Empty(t, sendDigests(t))
+	})
+
+	t.Run("Daily", func(t *testing.T) {
+		now = start.Add(5 * time.Hour)
+		notify(t, "n3", "alice", notifications.TypeReaction, "bob", "carol")
+		notify(t, "n4", "alice", notifications.TypeMention, "bob")
+
+		// The daily digest is not due yet.
+		now = start.Add(6 * time.Hour)
+		assert.Empty(t, sendDigests(t))
+
+		now = start.Add(24 * time.Hour)
+		sent := sendDigests(t)
+		require.Len(t, sent, 1)
+
+		msg := sent[0]
+		assert.Equal(t, "alice@example.com", msg.To)
+		assert.Equal(t, "Your daily digest: 2 new notifications", msg.Subject)
+		assert.Contains(t, msg.Text, "bob and carol reacted 👍 to your comment")
+		assert.Contains(t, msg.Text, "bob mentioned you in a comment")
+		assert.Contains(t, msg.HTML, "bob mentioned you in a comment")
+	})
+
+	t.Run("ReadNotificationsAreNotSent", func(t *testing.T) {
+		now = start.Add(25 * time.Hour)
+		notify(t, "n5", "bob", notifications.TypeReply, "carol")
+
+		readAt := now
+		notification, err := notificationRepo.Find(ctx, "n5")
+		require.NoError(t, err)
+
+		notification.ReadAt = &readAt
+		err = notificationRepo.Update(ctx, notification)
+		require.NoError(t, err)
+
+		now = start.Add(26 * time.Hour)
+		assert.Empty(t, sendDigests(t))
+	})
+
+	t.Run("Unsubscribe", func(t *testing.T) {
+		svc := notifications.NewBaseService(
+			notificationRepo,
+			sqlite3.NewNotificationMuteRepository(db),
+			emailSettingsRepo,
+			key,
+		)
+
+		err := svc.UnsubscribeFromEmails(ctx, "bob", notifications.UnsubscribeToken(key, "alice"))
+		require.ErrorAs(t, err, &notifications.InvalidUnsubscribeTokenError{})
+
+		err = svc.UnsubscribeFromEmails(ctx, "bob", notifications.UnsubscribeToken(key, "bob"))
+		require.NoError(t, err)
+
+		settings, err := svc.GetMyEmailSettings(authcontext.WithSubject(ctx, "bob"))
+		require.NoError(t, err)
+		assert.Equal(t, notifications.EmailFrequencyOff, settings.Frequency)
+
+		now = start.Add(27 * time.Hour)
+		notify(t, "n6", "bob", notifications.TypeReply, "carol")
+
+		now = start.Add(28 * time.Hour)
+		assert.Empty(t, sendDigests(t))
+	})
+}
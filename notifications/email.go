@@ -0,0 +1,130 @@
+package notifications
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"time"
+)
+
+// EmailFrequency is how often a user is emailed about their unread notifications.
+type EmailFrequency string
+
+const (
+	EmailFrequencyOff       EmailFrequency = "off"
+	EmailFrequencyImmediate EmailFrequency = "immediate"
+	EmailFrequencyDaily     EmailFrequency = "daily"
+	EmailFrequencyWeekly    EmailFrequency = "weekly"
+)
+
+// EmailFrequencies returns all email frequencies, in the order they are offered.
+func EmailFrequencies() []EmailFrequency {
+	return []EmailFrequency{
+		EmailFrequencyOff,
+		EmailFrequencyImmediate,
+		EmailFrequencyDaily,
+		EmailFrequencyWeekly,
+	}
+}
+
+func (frequency EmailFrequency) IsValid() bool {
+	switch frequency {
+	case EmailFrequencyOff, EmailFrequencyImmediate, EmailFrequencyDaily, EmailFrequencyWeekly:
+		return true
+	default:
+		return false
+	}
+}
+
+// Title returns the human-readable name of the frequency.
+func (frequency EmailFrequency) Title() string {
+	switch frequency {
+	case EmailFrequencyOff:
+		return "Off"
+	case EmailFrequencyImmediate:
+		return "Immediately"
+	case EmailFrequencyDaily:
+		return "Daily digest"
+	case EmailFrequencyWeekly:
+		return "Weekly digest"
+	default:
+		return string(frequency)
+	}
+}
+
+// Period returns how long after an email the next one is due. Immediate emails are due as soon as there is news.
+func (frequency EmailFrequency) Period() time.Duration {
+	switch frequency {
+	case EmailFrequencyDaily:
+		return 24 * time.Hour
+	case EmailFrequencyWeekly:
+		return 7 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// EmailSettings tell where and how often a user is emailed.
+type EmailSettings struct {
+	UserID    string
+	Address   string
+	Frequency EmailFrequency
+	// LastSentAt is when the last email was sent, or the settings changed. Notifications updated since go into the
+	// next email.
+	LastSentAt time.Time
+}
+
+// IsDue reports whether the next email is due at the time.
+func (settings *EmailSettings) IsDue(now time.Time) bool {
+	return settings.Frequency != EmailFrequencyOff && !now.Before(settings.LastSentAt.Add(settings.Frequency.Period()))
+}
+
+type EmailSettingsRepository interface {
+	Find(ctx context.Context, userID string) (settings *EmailSettings, err error)
+	// Save inserts the settings of the user or replaces them.
+	Save(ctx context.Context, settings *EmailSettings) (err error)
+	// ListEnabled lists the settings of the users who get emails.
+	ListEnabled(ctx context.Context) (settings []*EmailSettings, err error)
+}
+
+// UnsubscribeToken signs the user ID, so unsubscribe links in emails work without logging in.
+func UnsubscribeToken(key []byte, userID string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("unsubscribe:" + userID))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+type EmailSettingsNotFoundError struct {
+	UserID string
+}
+
+func (err EmailSettingsNotFoundError) Error() string {
+	return fmt.Sprintf("email settings of user %q not found", err.UserID)
+}
+
+type InvalidEmailFrequencyError struct {
+	Frequency EmailFrequency
+}
+
+func (err InvalidEmailFrequencyError) Error() string {
+	return fmt.Sprintf("invalid email frequency: %q", err.Frequency)
+}
+
+type InvalidEmailAddressError struct {
+	Address string
+}
+
+func (err InvalidEmailAddressError) Error() string {
+	return fmt.Sprintf("invalid email address: %q", err.Address)
+}
+
+type InvalidUnsubscribeTokenError struct {
+	UserID string
+}
+
+func (err InvalidUnsubscribeTokenError) Error() string {
+	return fmt.Sprintf("invalid unsubscribe token for user %q", err.UserID)
+}
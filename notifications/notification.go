@@ -0,0 +1,126 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Type is the kind of a notification. Users can mute each type.
+type Type string
+
+const (
+	// TypeComment notifies the author of a post of comments on it.
+	TypeComment Type = "comment"
+	// TypeReply notifies the author of a comment of replies to it.
+	TypeReply Type = "reply"
+	// TypeReaction notifies the author of a post or comment of reactions to it.
+	TypeReaction Type = "reaction"
+	// TypeMention notifies users of posts and comments mentioning them.
+	TypeMention Type = "mention"
+)
+
+// Types returns all notification types, in the order they are offered.
+func Types() []Type {
+	return []Type{TypeComment, TypeReply, TypeReaction, TypeMention}
+}
+
+func (typ Type) IsValid() bool {
+	switch typ {
+	case TypeComment, TypeReply, TypeReaction, TypeMention:
+		return true
+	default:
+		return false
+	}
+}
+
+// Title returns the human-readable name of the type.
+func (typ Type) Title() string {
+	switch typ {
+	case TypeComment:
+		return "Comments on my posts"
+	case TypeReply:
+		return "Replies to my comments"
+	case TypeReaction:
+		return "Reactions to my posts and comments"
+	case TypeMention:
+		return "Mentions of me"
+	default:
+		return string(typ)
+	}
+}
+
+// Notification tells a user that others acted on their post or comment. Actions on the same thing, like reactions
+// with the same emoji to a post, are grouped into one notification while it is unread.
+type Notification struct {
+	ID     string
+	UserID string
+	Type   Type
+	// GroupKey identifies what the actions are on. An unread notification with the key takes further actors.
+	GroupKey string
+	PostID   string
+	// CommentID is the comment the actions are on, or the user is mentioned in, if it is not the post.
+	CommentID string
+	Emoji     string
+	// ActorIDs are the users who acted, the latest first.
+	ActorIDs  []string
+	ReadAt    *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (notification *Notification) IsRead() bool {
+	return notification.ReadAt != nil
+}
+
+type NotificationRepository interface {
+	Insert(ctx context.Context, notification *Notification) (err error)
+	Update(ctx context.Context, notification *Notification) (err error)
+	Delete(ctx context.Context, notificationID string) (err error)
+	Find(ctx context.Context, notificationID string) (notification *Notification, err error)
+	// FindUnread finds the unread notification of the user with the group key.
+	FindUnread(ctx context.Context, userID, groupKey string) (notification *Notification, err error)
+	// List lists the notifications of the user, the most recently updated first.
+	List(ctx context.Context, params *ListNotificationsParams) (notifications []*Notification, err error)
+	CountUnread(ctx context.Context, userID string) (count int, err error)
+	MarkAllRead(ctx context.Context, userID string, readAt time.Time) (err error)
+}
+
+type ListNotificationsParams struct {
+	UserID     string
+	UnreadOnly bool
+	// UpdatedAfter lists notifications updated after the time only, unless it is zero.
+	UpdatedAfter time.Time
+	Limit        int
+	Offset       int
+}
+
+type PreferenceRepository interface {
+	ListMuted(ctx context.Context, userID string) (types []Type, err error)
+	SetMuted(ctx context.Context, userID string, typ Type, muted bool) (err error)
+}
+
+type NotificationNotFoundError struct {
+	ID string
+}
+
+func (err NotificationNotFoundError) Error() string {
+	return fmt.Sprintf("notification with id %q not found", err.ID)
+}
+
+type UnreadNotificationNotFoundError struct {
+	UserID   string
+	GroupKey string
+}
+
+func (err UnreadNotificationNotFoundError) Error() string {
+	return fmt.Sprintf("unread notification %q of user %q not found", err.GroupKey, err.UserID)
+}
+
+type InvalidTypeError struct {
+	Type Type
+}
+
+func (err InvalidTypeError) Error() string {
+	return fmt.Sprintf("invalid notification type: %q", err.Type)
+}
@@ -0,0 +1,278 @@
+package notifications
+
+import (
+	"context"
+	"crypto/hmac"
+	"errors"
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/authorization"
+)
+
+const ServiceName = "github.com/nasermirzaei89/scribble/notifications"
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 200
+)
+
+// Service gives users their notifications. Notifications are created by the Notifier.
+type Service interface {
+	ListMyNotifications(ctx context.Context, req ListNotificationsRequest) ([]*Notification, error)
+	CountMyUnreadNotifications(ctx context.Context) (int, error)
+	MarkNotificationRead(ctx context.Context, notificationID string) error
+	MarkAllNotificationsRead(ctx context.Context) error
+	GetMyPreferences(ctx context.Context) (*Preferences, error)
+	SetMyPreferences(ctx context.Context, prefs Preferences) error
+	GetMyEmailSettings(ctx context.Context) (*EmailSettings, error)
+	SetMyEmailSettings(ctx context.Context, req SetEmailSettingsRequest) error
+	// UnsubscribeFromEmails turns emails off for the user, authenticated by the token of the unsubscribe link.
+	UnsubscribeFromEmails(ctx context.Context, userID, token string) error
+}
+
+type BaseService struct {
+	notificationRepo  NotificationRepository
+	preferenceRepo    PreferenceRepository
+	emailSettingsRepo EmailSettingsRepository
+	unsubscribeKey    []byte
+}
+
+var _ Service = (*BaseService)(nil)
+
+func NewService( //nolint:ireturn
+	notificationRepo NotificationRepository,
+	preferenceRepo PreferenceRepository,
+	emailSettingsRepo EmailSettingsRepository,
+	unsubscribeKey []byte,
+	authzClient *authorization.Client,
+) Service {
+	return NewAuthorizationMiddleware(
+		authzClient,
+		NewBaseService(notificationRepo, preferenceRepo, emailSettingsRepo, unsubscribeKey),
+	)
+}
+
+// NewBaseService returns the service. The unsubscribe key verifies the links the Digester signs with it.
+func NewBaseService(
+	notificationRepo NotificationRepository,
+	preferenceRepo PreferenceRepository,
+	emailSettingsRepo EmailSettingsRepository,
+	unsubscribeKey []byte,
+) *BaseService {
+	return &BaseService{
+		notificationRepo:  notificationRepo,
+		preferenceRepo:    preferenceRepo,
+		emailSettingsRepo: emailSettingsRepo,
+		unsubscribeKey:    unsubscribeKey,
+	}
+}
+
+type ListNotificationsRequest struct {
+	Limit  int
+	Offset int
+}
+
+func (svc *BaseService) ListMyNotifications(
+	ctx context.Context,
+	req ListNotificationsRequest,
+) ([]*Notification, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	notifications, err := svc.notificationRepo.List(ctx, &ListNotificationsParams{
+		UserID:       authcontext.GetSubject(ctx),
+		UnreadOnly:   false,
+		UpdatedAfter: time.Time{},
+		Limit:        min(limit, maxListLimit),
+		Offset:       max(req.Offset, 0),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+func (svc *BaseService) CountMyUnreadNotifications(ctx context.Context) (int, error) {
+	count, err := svc.notificationRepo.CountUnread(ctx, authcontext.GetSubject(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	return count, nil
+}
+
+// MarkNotificationRead marks a notification of the current user as read.
+func (svc *BaseService) MarkNotificationRead(ctx context.Context, notificationID string) error {
+	notification, err := svc.notificationRepo.Find(ctx, notificationID)
+	if err != nil {
+		return fmt.Errorf("failed to find notification: %w", err)
+	}
+
+	// Notifications of other users are not disclosed.
+	if notification.UserID != authcontext.GetSubject(ctx) {
+		return NotificationNotFoundError{ID: notificationID}
+	}
+
+	if notification.IsRead() {
+		return nil
+	}
+
+	now := time.Now()
+	notification.ReadAt = &now
+
+	err = svc.notificationRepo.Update(ctx, notification)
+	if err != nil {
+		return fmt.Errorf("failed to update notification: %w", err)
+	}
+
+	return nil
+}
+
+func (svc *BaseService) MarkAllNotificationsRead(ctx context.Context) error {
+	err := svc.notificationRepo.MarkAllRead(ctx, authcontext.GetSubject(ctx), time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to mark all notifications read: %w", err)
+	}
+
+	return nil
+}
+
+// Preferences are the notification settings of a user.
+type Preferences struct {
+	// Muted are the types of notifications the user does not get.
+	Muted []Type
+}
+
+func (prefs *Preferences) IsMuted(typ Type) bool {
+	return slices.Contains(prefs.Muted, typ)
+}
+
+func (svc *BaseService) GetMyPreferences(ctx context.Context) (*Preferences, error) {
+	muted, err := svc.preferenceRepo.ListMuted(ctx, authcontext.GetSubject(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list muted notification types: %w", err)
+	}
+
+	return &Preferences{Muted: muted}, nil
+}
+
+// SetMyPreferences replaces the preferences of the current user.
+func (svc *BaseService) SetMyPreferences(ctx context.Context, prefs Preferences) error {
+	for _, typ := range prefs.Muted {
+		if !typ.IsValid() {
+			return InvalidTypeError{Type: typ}
+		}
+	}
+
+	userID := authcontext.GetSubject(ctx)
+
+	for _, typ := range Types() {
+		err := svc.preferenceRepo.SetMuted(ctx, userID, typ, prefs.IsMuted(typ))
+		if err != nil {
+			return fmt.Errorf("failed to set notification type %q muted: %w", typ, err)
+		}
+	}
+
+	return nil
+}
+
+// GetMyEmailSettings returns the email settings of the current user. Users who never set them get no emails.
+func (svc *BaseService) GetMyEmailSettings(ctx context.Context) (*EmailSettings, error) {
+	userID := authcontext.GetSubject(ctx)
+
+	settings, err := svc.emailSettingsRepo.Find(ctx, userID)
+	if err != nil {
+		if _, ok := errors.AsType[EmailSettingsNotFoundError](err); ok {
+			return &EmailSettings{
+				UserID:     userID,
+				Address:    "",
+				Frequency:  EmailFrequencyOff,
+				LastSentAt: time.Time{},
+			}, nil
+		}
+
+		return nil, fmt.Errorf("failed to find email settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+type SetEmailSettingsRequest struct {
+	Address   string
+	Frequency EmailFrequency
+}
+
+// SetMyEmailSettings replaces the email settings of the current user. A new frequency starts counting now, so
+// notifications from before are not emailed.
+func (svc *BaseService) SetMyEmailSettings(ctx context.Context, req SetEmailSettingsRequest) error {
+	if !req.Frequency.IsValid() {
+		return InvalidEmailFrequencyError{Frequency: req.Frequency}
+	}
+
+	address := strings.TrimSpace(req.Address)
+
+	if address != "" || req.Frequency != EmailFrequencyOff {
+		parsed, err := mail.ParseAddress(address)
+		if err != nil {
+			return InvalidEmailAddressError{Address: req.Address}
+		}
+
+		address = parsed.Address
+	}
+
+	settings, err := svc.GetMyEmailSettings(ctx)
+	if err != nil {
+		return err
+	}
+
+	if settings.Frequency != req.Frequency {
+		settings.LastSentAt = time.Now()
+	}
+
+	settings.Address = address
+	settings.Frequency = req.Frequency
+
+	err = svc.emailSettingsRepo.Save(ctx, settings)
+	if err != nil {
+		return fmt.Errorf("failed to save email settings: %w", err)
+	}
+
+	return nil
+}
+
+func (svc *BaseService) UnsubscribeFromEmails(ctx context.Context, userID, token string) error {
+	if !hmac.Equal([]byte(token), []byte(UnsubscribeToken(svc.unsubscribeKey, userID))) {
+		return InvalidUnsubscribeTokenError{UserID: userID}
+	}
+
+	settings, err := svc.emailSettingsRepo.Find(ctx, userID)
+	if err != nil {
+		if _, ok := errors.AsType[EmailSettingsNotFoundError](err); ok {
+			return nil
+		}
+
+		return fmt.Errorf("failed to find email settings: %w", err)
+	}
+
+	if settings.Frequency == EmailFrequencyOff {
+		return nil
+	}
+
+	settings.Frequency = EmailFrequencyOff
+	settings.LastSentAt = time.Now()
+
+	err = svc.emailSettingsRepo.Save(ctx, settings)
+	if err != nil {
+		return fmt.Errorf("failed to save email settings: %w", err)
+	}
+
+	return nil
+}
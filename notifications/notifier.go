@@ -0,0 +1,266 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/discuss"
+	"github.com/nasermirzaei89/scribble/events"
+	"github.com/nasermirzaei89/scribble/reactions"
+)
+
+// Notifier creates notifications from the events of the bus. It looks posts and comments up in their repositories,
+// as it acts for no user.
+type Notifier struct {
+	notificationRepo NotificationRepository
+	preferenceRepo   PreferenceRepository
+	postRepo         contents.PostRepository
+	commentRepo      discuss.CommentRepository
+	now              func() time.Time
+}
+
+func NewNotifier(
+	notificationRepo NotificationRepository,
+	preferenceRepo PreferenceRepository,
+	postRepo contents.PostRepository,
+	commentRepo discuss.CommentRepository,
+) *Notifier {
+	return &Notifier{
+		notificationRepo: notificationRepo,
+		preferenceRepo:   preferenceRepo,
+		postRepo:         postRepo,
+		commentRepo:      commentRepo,
+		now:              time.Now,
+	}
+}
+
+func (notifier *Notifier) Subscribe(bus *events.Bus) {
+	events.SubscribeAsync(bus, notifier.handleCommentCreated)
+	events.SubscribeAsync(bus, notifier.handleReactionToggled)
+	events.SubscribeAsync(bus, notifier.handleUserMentioned)
+}
+
+// handleCommentCreated notifies the author of the comment replied to, and the author of the post if someone else.
+func (notifier *Notifier) handleCommentCreated(ctx context.Context, event events.CommentCreated) error {
+	repliedToAuthorID := ""
+
+	if event.ReplyTo != "" {
+		comment, err := notifier.commentRepo.Find(ctx, event.ReplyTo)
+		if err != nil && !isNotFound(err) {
+			return fmt.Errorf("failed to find comment: %w", err)
+		}
+
+		if comment != nil {
+			repliedToAuthorID = comment.AuthorID
+
+			err = notifier.add(ctx, comment.AuthorID, event.AuthorID, subject{
+				typ:       TypeReply,
+				groupKey:  "reply:" + comment.ID,
+				postID:    comment.PostID,
+				commentID: comment.ID,
+				emoji:     "",
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	post, err := notifier.postRepo.Find(ctx, event.PostID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to find post: %w", err)
+	}
+
+	// A reply to the post author is notified as a reply only.
+	if post.AuthorID == repliedToAuthorID {
+		return nil
+	}
+
+	return notifier.add(ctx, post.AuthorID, event.AuthorID, subject{
+		typ:       TypeComment,
+		groupKey:  "comment:" + post.ID,
+		postID:    post.ID,
+		commentID: "",
+		emoji:     "",
+	})
+}
+
+// handleReactionToggled notifies the author of the post or comment reacted to. Removed reactions are taken back from
+// unread notifications.
+func (notifier *Notifier) handleReactionToggled(ctx context.Context, event events.ReactionToggled) error {
+	subj := subject{
+		typ:       TypeReaction,
+		groupKey:  "reaction:" + event.TargetType + ":" + event.TargetID + ":" + event.Emoji,
+		postID:    "",
+		commentID: "",
+		emoji:     event.Emoji,
+	}
+
+	var authorID string
+
+	switch reactions.TargetType(event.TargetType) {
+	case reactions.TargetTypePost:
+		post, err := notifier.postRepo.Find(ctx, event.TargetID)
+		if err != nil {
+			if isNotFound(err) {
+				return nil
+			}
+
+			return fmt.Errorf("failed to find post: %w", err)
+		}
+
+		authorID = post.AuthorID
+		subj.postID = post.ID
+	case reactions.TargetTypeComment:
+		comment, err := notifier.commentRepo.Find(ctx, event.TargetID)
+		if err != nil {
+			if isNotFound(err) {
+				return nil
+			}
+
+			return fmt.Errorf("failed to find comment: %w", err)
+		}
+
+		authorID = comment.AuthorID
+		subj.postID = comment.PostID
+		subj.commentID = comment.ID
+	default:
+		return nil
+	}
+
+	if !event.Added {
+		return notifier.remove(ctx, authorID, event.UserID, subj.groupKey)
+	}
+
+	return notifier.add(ctx, authorID, event.UserID, subj)
+}
+
+// handleUserMentioned notifies the user mentioned. Each mention is a notification of its own.
+func (notifier *Notifier) handleUserMentioned(ctx context.Context, event events.UserMentioned) error {
+	return notifier.add(ctx, event.UserID, event.AuthorID, subject{
+		typ:       TypeMention,
+		groupKey:  "mention:" + event.MentionID,
+		postID:    event.PostID,
+		commentID: event.CommentID,
+		emoji:     "",
+	})
+}
+
+// subject is what a notification is about.
+type subject struct {
+	typ       Type
+	groupKey  string
+	postID    string
+	commentID string
+	emoji     string
+}
+
+// add adds the actor to the unread notification of the user about the subject, or creates one. Users are not notified
+// of their own actions, nor of muted types.
+func (notifier *Notifier) add(ctx context.Context, userID, actorID string, subj subject) error {
+	if userID == "" || userID == actorID {
+		return nil
+	}
+
+	muted, err := notifier.preferenceRepo.ListMuted(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list muted notification types: %w", err)
+	}
+
+	if slices.Contains(muted, subj.typ) {
+		return nil
+	}
+
+	now := notifier.now()
+
+	notification, err := notifier.notificationRepo.FindUnread(ctx, userID, subj.groupKey)
+	if err != nil {
+		if _, ok := errors.AsType[UnreadNotificationNotFoundError](err); !ok {
+			return fmt.Errorf("failed to find unread notification: %w", err)
+		}
+
+		err = notifier.notificationRepo.Insert(ctx, &Notification{
+			ID:        uuid.NewString(),
+			UserID:    userID,
+			Type:      subj.typ,
+			GroupKey:  subj.groupKey,
+			PostID:    subj.postID,
+			CommentID: subj.commentID,
+			Emoji:     subj.emoji,
+			ActorIDs:  []string{actorID},
+			ReadAt:    nil,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to insert notification: %w", err)
+		}
+
+		return nil
+	}
+
+	// Events may be handled more than once, so actors are kept unique.
+	notification.ActorIDs = slices.Insert(slices.DeleteFunc(notification.ActorIDs, func(id string) bool {
+		return id == actorID
+	}), 0, actorID)
+	notification.UpdatedAt = now
+
+	err = notifier.notificationRepo.Update(ctx, notification)
+	if err != nil {
+		return fmt.Errorf("failed to update notification: %w", err)
+	}
+
+	return nil
+}
+
+// remove takes the actor back from the unread notification of the user with the group key, deleting it if no actor
+// is left.
+func (notifier *Notifier) remove(ctx context.Context, userID, actorID, groupKey string) error {
+	notification, err := notifier.notificationRepo.FindUnread(ctx, userID, groupKey)
+	if err != nil {
+		if _, ok := errors.AsType[UnreadNotificationNotFoundError](err); ok {
+			return nil
+		}
+
+		return fmt.Errorf("failed to find unread notification: %w", err)
+	}
+
+	notification.ActorIDs = slices.DeleteFunc(notification.ActorIDs, func(id string) bool {
+		return id == actorID
+	})
+
+	if len(notification.ActorIDs) == 0 {
+		err = notifier.notificationRepo.Delete(ctx, notification.ID)
+		if err != nil {
+			return fmt.Errorf("failed to delete notification: %w", err)
+		}
+
+		return nil
+	}
+
+	err = notifier.notificationRepo.Update(ctx, notification)
+	if err != nil {
+		return fmt.Errorf("failed to update notification: %w", err)
+	}
+
+	return nil
+}
+
+func isNotFound(err error) bool {
+	if _, ok := errors.AsType[contents.PostNotFoundError](err); ok {
+		return true
+	}
+
+	_, ok := errors.AsType[discuss.CommentNotFoundError](err)
+
+	return ok
+}
@@ -0,0 +1,217 @@
+package notifications_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/database/sqlite3"
+	"github.com/nasermirzaei89/scribble/discuss"
+	"github.com/nasermirzaei89/scribble/events"
+	"github.com/nasermirzaei89/scribble/notifications"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifier(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sqlite3.NewDB(ctx, "file:TestNotifier?mode=memory&cache=shared")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := db.Close()
+		require.NoError(t, err)
+	})
+
+	err = sqlite3.MigrateUp(ctx, db)
+	require.NoError(t, err)
+
+	postRepo := sqlite3.NewPostRepository(db)
+	commentRepo := sqlite3.NewCommentRepository(db)
+	notificationRepo := sqlite3.NewNotificationRepository(db)
+	muteRepo := sqlite3.NewNotificationMuteRepository(db)
+
+	bus := events.NewBus(sqlite3.NewEventOutboxRepository(db), sqlite3.NewTransactor(db))
+	notifications.NewNotifier(notificationRepo, muteRepo, postRepo, commentRepo).Subscribe(bus)
+
+	svc := notifications.NewBaseService(
+		notificationRepo,
+		muteRepo,
+		sqlite3.NewNotificationEmailSettingsRepository(db),
+		[]byte("key"),
+	)
+
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	err = postRepo.Insert(ctx, &contents.Post{
+		ID:          "post1",
+		AuthorID:    "alice",
+		CommunityID: "",
+		Content:     "post",
+		CreatedAt:   now,
+	})
+	require.NoError(t, err)
+
+	err = commentRepo.Insert(ctx, &discuss.Comment{
+		ID:        "comment1",
+		PostID:    "post1",
+		AuthorID:  "bob",
+		ReplyTo:   nil,
+		Content:   "comment",
+		CreatedAt: now,
+	})
+	require.NoError(t, err)
+
+	publish := func(t *testing.T, event events.Event) {
+		t.Helper()
+
+		err := bus.Publish(ctx, event)
+		require.NoError(t, err)
+
+		_, err = bus.ProcessOutbox(ctx)
+		require.NoError(t, err)
+	}
+
+	list := func(t *testing.T, userID string) []*notifications.Notification {
+		t.Helper()
+
+		list, err := svc.ListMyNotifications(
+			authcontext.WithSubject(ctx, userID),
+			notifications.ListNotificationsRequest{Limit: 0, Offset: 0},
+		)
+		require.NoError(t, err)
+
+		return list
+	}
+
+	reaction := func(userID, emoji string, added bool) events.ReactionToggled {
+		return events.ReactionToggled{TargetType: "post", TargetID: "post1", UserID: userID, Emoji: emoji, Added: added}
+	}
+
+	t.Run("comments notify the post author", func(t *testing.T) {
+		publish(t, events.CommentCreated{
+			CommentID: "comment1",
+			PostID:    "post1",
+			AuthorID:  "bob",
+			ReplyTo:   "",
+			CreatedAt: now,
+		})
+
+		aliceNotifications := list(t, "alice")
+		require.Len(t, aliceNotifications, 1)
+		assert.Equal(t, notifications.TypeComment, aliceNotifications[0].Type)
+		assert.Equal(t, []string{"bob"}, aliceNotifications[0].ActorIDs)
+		assert.Empty(t, list(t, "bob"))
+	})
+
+	t.Run("replies notify the comment author", func(t *testing.T) {
+		publish(t, events.CommentCreated{
+			CommentID: "comment2",
+			PostID:    "post1",
+			AuthorID:  "alice",
+			ReplyTo:   "comment1",
+			CreatedAt: now,
+		})
+
+		bobNotifications := list(t, "bob")
+		require.Len(t, bobNotifications, 1)
+		assert.Equal(t, notifications.TypeReply, bobNotifications[0].Type)
+		assert.Equal(t, "comment1", bobNotifications[0].CommentID)
+		assert.Equal(t, []string{"alice"}, bobNotifications[0].ActorIDs)
+
+		// Alice replied on her own post.
+		assert.Len(t, list(t, "alice"), 1)
+	})
+
+	t.Run("reactions are grouped", func(t *testing.T) {
+		publish(t, reaction("bob", "👍", true))
+		publish(t, reaction("carol", "👍", true))
+		publish(t, reaction("bob", "👍", true))
+		publish(t, reaction("dave", "😂", true))
+		publish(t, reaction("alice", "👍", true))
+
+		aliceNotifications := list(t, "alice")
+		require.Len(t, aliceNotifications, 3)
+		assert.Equal(t, "😂", aliceNotifications[0].Emoji)
+		assert.Equal(t, "👍", aliceNotifications[1].Emoji)
+		assert.Equal(t, []string{"bob", "carol"}, aliceNotifications[1].ActorIDs)
+
+		publish(t, reaction("dave", "😂", false))
+		publish(t, reaction("bob", "👍", false))
+
+		aliceNotifications = list(t, "alice")
+		require.Len(t, aliceNotifications, 2)
+		assert.Equal(t, []string{"carol"}, aliceNotifications[0].ActorIDs)
+	})
+
+	t.Run("read notifications are not grouped further", func(t *testing.T) {
+		aliceCtx := authcontext.WithSubject(ctx, "alice")
+
+		err := svc.MarkAllNotificationsRead(aliceCtx)
+		require.NoError(t, err)
+
+		count, err := svc.CountMyUnreadNotifications(aliceCtx)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+
+		publish(t, reaction("erin", "👍", true))
+
+		aliceNotifications := list(t, "alice")
+		require.Len(t, aliceNotifications, 3)
+		assert.False(t, aliceNotifications[0].IsRead())
+		assert.Equal(t, []string{"erin"}, aliceNotifications[0].ActorIDs)
+
+		err = svc.MarkNotificationRead(authcontext.WithSubject(ctx, "bob"), aliceNotifications[0].ID)
+		require.ErrorAs(t, err, &notifications.NotificationNotFoundError{})
+
+		err = svc.MarkNotificationRead(aliceCtx, aliceNotifications[0].ID)
+		require.NoError(t, err)
+
+		count, err = svc.CountMyUnreadNotifications(aliceCtx)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("mentions notify the user mentioned", func(t *testing.T) {
+		publish(t, events.UserMentioned{
+			MentionID: "mention1",
+			UserID:    "bob",
+			AuthorID:  "carol",
+			PostID:    "post1",
+			CommentID: "comment3",
+			CreatedAt: now,
+		})
+
+		bobNotifications := list(t, "bob")
+		require.Len(t, bobNotifications, 2)
+		assert.Equal(t, notifications.TypeMention, bobNotifications[0].Type)
+		assert.Equal(t, "comment3", bobNotifications[0].CommentID)
+		assert.Equal(t, []string{"carol"}, bobNotifications[0].ActorIDs)
+	})
+
+	t.Run("muted types are not notified", func(t *testing.T) {
+		aliceCtx := authcontext.WithSubject(ctx, "alice")
+
+		err := svc.SetMyPreferences(aliceCtx, notifications.Preferences{
+			Muted: []notifications.Type{notifications.TypeReaction},
+		})
+		require.NoError(t, err)
+
+		prefs, err := svc.GetMyPreferences(aliceCtx)
+		require.NoError(t, err)
+		assert.True(t, prefs.IsMuted(notifications.TypeReaction))
+		assert.False(t, prefs.IsMuted(notifications.TypeComment))
+
+		publish(t, reaction("frank", "👎", true))
+
+		count, err := svc.CountMyUnreadNotifications(aliceCtx)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+
+		err = svc.SetMyPreferences(aliceCtx, notifications.Preferences{Muted: []notifications.Type{"bogus"}})
+		require.ErrorAs(t, err, &notifications.InvalidTypeError{})
+	})
+}
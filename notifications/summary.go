@@ -0,0 +1,54 @@
+package notifications
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ActorsShown is how many actors a summary names before counting the rest.
+const ActorsShown = 2
+
+// Summary describes the notification in a sentence, like "carol, bob and 3 others reacted 👍 to your post". names
+// are the usernames of the first actors, up to ActorsShown.
+func Summary(notification *Notification, names []string) string {
+	actors := strings.Join(names, ", ")
+
+	switch others := len(notification.ActorIDs) - len(names); {
+	case others == 1:
+		actors += " and 1 other"
+	case others > 1:
+		actors += " and " + strconv.Itoa(others) + " others"
+	case len(names) > 1:
+		actors = strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+	}
+
+	target := "post"
+	if notification.CommentID != "" {
+		target = "comment"
+	}
+
+	var action string
+
+	switch notification.Type {
+	case TypeComment:
+		action = "commented on your post"
+	case TypeReply:
+		action = "replied to your comment"
+	case TypeReaction:
+		action = "reacted " + notification.Emoji + " to your " + target
+	case TypeMention:
+		action = "mentioned you in a " + target
+	}
+
+	return actors + " " + action
+}
+
+// Path returns the path of the page the notification is about, relative to the site.
+func Path(notification *Notification) string {
+	path := "/p/" + notification.PostID
+	if notification.CommentID != "" {
+		path += "#comment-" + notification.CommentID
+	}
+
+	return path
+}
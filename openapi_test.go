@@ -0,0 +1,351 @@
+package scribble_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/nasermirzaei89/scribble"
+	"github.com/nasermirzaei89/scribble/web/openapi"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const specURL = "file:///openapi.json"
+
+// specClient sends API requests and checks every response against the OpenAPI document the app serves: the status
+// must be documented for the operation and the body must match the documented schema.
+type specClient struct {
+	t         *testing.T
+	serverURL string
+	doc       openapi.Document
+	compiler  *jsonschema.Compiler
+	schemas   map[string]*jsonschema.Schema
+	exercised map[string]bool
+	token     string
+}
+
+func newSpecClient(t *testing.T, serverURL string) *specClient {
+	t.Helper()
+
+	res, err := http.Get(serverURL + "/api/openapi.json") //nolint:noctx
+	require.NoError(t, err)
+
+	defer func() { _ = res.Body.Close() }()
+
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	raw, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	var client specClient
+
+	err = json.Unmarshal(raw, &client.doc)
+	require.NoError(t, err)
+	require.Equal(t, openapi.Version, client.doc.OpenAPI)
+
+	rawDoc, err := jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+	require.NoError(t, err)
+
+	client.compiler = jsonschema.NewCompiler()
+	client.compiler.DefaultDraft(jsonschema.Draft2020)
+
+	err = client.compiler.AddResource(specURL, rawDoc)
+	require.NoError(t, err)
+
+	client.t = t
+	client.serverURL = serverURL
+	client.schemas = make(map[string]*jsonschema.Schema)
+	client.exercised = make(map[string]bool)
+
+	return &client
+}
+
+func (c *specClient) schema(schema *openapi.Schema) *jsonschema.Schema {
+	c.t.Helper()
+
+	require.NotEmpty(c.t, schema.Ref, "bodies must be described by component schemas")
+
+	compiled, ok := c.schemas[schema.Ref]
+	if !ok {
+		var err error
+
+		compiled, err = c.compiler.Compile(specURL + schema.Ref)
+		require.NoError(c.t, err)
+
+		c.schemas[schema.Ref] = compiled
+	}
+
+	return compiled
+}
+
+// do sends the request and returns the status and the decoded body of the response.
+func (c *specClient) do(method, target string, body any) (int, map[string]any) {
+	c.t.Helper()
+
+	u, err := url.Parse(target)
+	require.NoError(c.t, err)
+
+	template, op, ok := c.doc.FindOperation(method, u.Path)
+	require.True(c.t, ok, "%s %s is not documented", method, u.Path)
+
+	c.exercised[method+" "+template] = true
+
+	for name := range u.Query() {
+		documented := false
+
+		for _, parameter := range op.Parameters {
+			documented = documented || parameter.In == openapi.InQuery && parameter.Name == name
+		}
+
+		assert.True(c.t, documented, "query parameter %q of %s %s is not documented", name, method, template)
+	}
+
+	var reqBody io.Reader
+
+	if body != nil {
+		raw, err := json.Marshal(body)
+		require.NoError(c.t, err)
+
+		if op.RequestBody != nil {
+			// Bodies the handlers accept must match the documented request schema too.
+			instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+			require.NoError(c.t, err)
+
+			err = c.schema(op.RequestBody.Content["application/json"].Schema).Validate(instance)
+			assert.NoError(c.t, err, "request body of %s %s", method, template)
+		}
+
+		reqBody = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequestWithContext(c.t.Context(), method, c.serverURL+target, reqBody)
+	require.NoError(c.t, err)
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(c.t, err)
+
+	defer func() { _ = res.Body.Close() }()
+
+	raw, err := io.ReadAll(res.Body)
+	require.NoError(c.t, err)
+
+	response, ok := op.Response(res.StatusCode)
+	require.True(c.t, ok, "status %d of %s %s is not documented: %s", res.StatusCode, method, template, raw)
+
+	content, hasContent := response.Content["application/json"]
+	if !hasContent {
+		assert.Empty(c.t, raw, "%s %s responded %d with an undocumented body", method, template, res.StatusCode)
+
+		return res.StatusCode, nil
+	}
+
+	assert.Equal(c.t, "application/json; charset=utf-8", res.Header.Get("Content-Type"))
+
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+	require.NoError(c.t, err)
+
+	err = c.schema(content.Schema).Validate(instance)
+	require.NoError(c.t, err, "response %d of %s %s does not match the spec: %s", res.StatusCode, method, template, raw)
+
+	var decoded map[string]any
+
+	err = json.Unmarshal(raw, &decoded)
+	require.NoError(c.t, err)
+
+	return res.StatusCode, decoded
+}
+
+func TestAPIMatchesOpenAPISpec(t *testing.T) {
+	t.Setenv("DB_DSN", "file:"+t.Name()+"?mode=memory&cache=shared")
+
+	app, err := scribble.NewApp(t.Context())
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = app.Close() })
+
+	server := httptest.NewServer(app.Handler())
+	t.Cleanup(server.Close)
+
+	c := newSpecClient(t, server.URL)
+
+	credentials := map[string]any{"username": "alice", "password": "secret123"}
+
+	status, user := c.do(http.MethodPost, "/api/v1/users", credentials)
+	require.Equal(t, http.StatusCreated, status)
+
+	status, _ = c.do(http.MethodPost, "/api/v1/users", credentials)
+	assert.Equal(t, http.StatusConflict, status)
+
+	status, _ = c.do(http.MethodPost, "/api/v1/users", map[string]any{"username": "", "password": "secret123"})
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, _ = c.do(http.MethodGet, "/api/v1/users/me", nil)
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	status, _ = c.do(http.MethodPost, "/api/v1/sessions", map[string]any{"username": "alice", "password": "wrong"})
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	status, session := c.do(http.MethodPost, "/api/v1/sessions", credentials)
+	require.Equal(t, http.StatusCreated, status)
+
+	c.token = session["token"].(string)
+
+	status, _ = c.do(http.MethodGet, "/api/v1/users/me", nil)
+	assert.Equal(t, http.StatusOK, status)
+
+	status, _ = c.do(http.MethodGet, "/api/v1/users/"+user["id"].(string), nil)
+	assert.Equal(t, http.StatusOK, status)
+
+	status, _ = c.do(http.MethodGet, "/api/v1/users/missing", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, _ = c.do(http.MethodGet, "/api/v1/users/"+user["id"].(string)+"/reputation", nil)
+	assert.Equal(t, http.StatusOK, status)
+
+	status, _ = c.do(http.MethodGet, "/api/v1/users/missing/reputation", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	var postID string
+
+	for _, content := range []string{"first", "second", "third"} {
+		var post map[string]any
+
+		status, post = c.do(http.MethodPost, "/api/v1/posts", map[string]any{"content": content})
+		require.Equal(t, http.StatusCreated, status)
+
+		postID = post["id"].(string)
+	}
+
+	status, _ = c.do(http.MethodPost, "/api/v1/posts", map[string]any{"content": " "})
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, page := c.do(http.MethodGet, "/api/v1/posts?limit=2", nil)
+	require.Equal(t, http.StatusOK, status)
+	require.NotEmpty(t, page["nextCursor"])
+
+	status, page = c.do(http.MethodGet, "/api/v1/posts?limit=2&cursor="+page["nextCursor"].(string), nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, page["items"], 1)
+	assert.NotContains(t, page, "nextCursor")
+
+	status, _ = c.do(http.MethodGet, "/api/v1/posts?cursor=invalid", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	for _, sort := range []string{"top&period=week", "hot"} {
+		status, page = c.do(http.MethodGet, "/api/v1/posts?limit=2&sort="+sort, nil)
+		require.Equal(t, http.StatusOK, status)
+		require.NotEmpty(t, page["nextCursor"])
+
+		status, page = c.do(http.MethodGet, "/api/v1/posts?limit=2&sort="+sort+"&cursor="+
+			page["nextCursor"].(string), nil)
+		require.Equal(t, http.StatusOK, status)
+		assert.Len(t, page["items"], 1)
+	}
+
+	status, _ = c.do(http.MethodGet, "/api/v1/posts?sort=best", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, _ = c.do(http.MethodGet, "/api/v1/posts?sort=top&period=year", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, _ = c.do(http.MethodGet, "/api/v1/posts?communityId=missing", nil)
+	assert.Equal(t, http.StatusOK, status)
+
+	status, _ = c.do(http.MethodGet, "/api/v1/posts/"+postID, nil)
+	assert.Equal(t, http.StatusOK, status)
+
+	status, _ = c.do(http.MethodGet, "/api/v1/posts/missing", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, comment := c.do(http.MethodPost, "/api/v1/posts/"+postID+"/comments", map[string]any{"content": "nice"})
+	require.Equal(t, http.StatusCreated, status)
+
+	status, _ = c.do(http.MethodPost, "/api/v1/posts/"+postID+"/comments", map[string]any{
+		"content": "thanks",
+		"replyTo": comment["id"],
+	})
+	require.Equal(t, http.StatusCreated, status)
+
+	status, _ = c.do(http.MethodPost, "/api/v1/posts/missing/comments", map[string]any{"content": "lost"})
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, page = c.do(http.MethodGet, "/api/v1/posts/"+postID+"/comments?limit=1", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.NotEmpty(t, page["nextCursor"])
+
+	for _, sort := range []string{"newest", "top"} {
+		status, page = c.do(http.MethodGet, "/api/v1/posts/"+postID+"/comments?limit=1&sort="+sort, nil)
+		require.Equal(t, http.StatusOK, status)
+		require.NotEmpty(t, page["nextCursor"])
+
+		status, page = c.do(http.MethodGet, "/api/v1/posts/"+postID+"/comments?limit=1&sort="+sort+"&cursor="+
+			page["nextCursor"].(string), nil)
+		require.Equal(t, http.StatusOK, status)
+		assert.Len(t, page["items"], 1)
+		assert.NotContains(t, page, "nextCursor")
+	}
+
+	status, _ = c.do(http.MethodGet, "/api/v1/posts/"+postID+"/comments?sort=best", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, _ = c.do(http.MethodGet, "/api/v1/posts/missing/comments", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, _ = c.do(http.MethodPost, "/api/v1/reactions/post/"+postID, map[string]any{"emoji": "👍"})
+	assert.Equal(t, http.StatusOK, status)
+
+	status, _ = c.do(http.MethodPost, "/api/v1/reactions/post/"+postID, map[string]any{"emoji": "x"})
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, _ = c.do(http.MethodGet, "/api/v1/reactions/comment/"+comment["id"].(string), nil)
+	assert.Equal(t, http.StatusOK, status)
+
+	status, _ = c.do(http.MethodGet, "/api/v1/reactions/page/"+postID, nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, page = c.do(http.MethodGet, "/api/v1/reactions/post/"+postID+"/reactors?emoji=👍", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, page["items"], 1)
+
+	status, _ = c.do(http.MethodGet, "/api/v1/reactions/page/"+postID+"/reactors", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, page = c.do(http.MethodGet, "/api/v1/emojis", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Empty(t, page["items"])
+
+	status, _ = c.do(http.MethodDelete, "/api/v1/sessions/current", nil)
+	assert.Equal(t, http.StatusNoContent, status)
+
+	status, _ = c.do(http.MethodGet, "/api/v1/users/me", nil)
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	c.token = ""
+
+	status, _ = c.do(http.MethodPost, "/api/v1/posts", map[string]any{"content": "anonymous"})
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	status, _ = c.do(http.MethodGet, "/api/v1/reactions/post/"+postID+"/reactors", nil)
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	for path, item := range c.doc.Paths {
+		for method := range *item {
+			assert.True(t, c.exercised[strings.ToUpper(method)+" "+path], "%s %s is not exercised", method, path)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package scribble_test
+
+import (
+	"testing"
+
+	"github.com/nasermirzaei89/scribble"
+	"github.com/nasermirzaei89/scribble/authorization/policytest"
+)
+
+func TestDefaultAuthorizationPolicy(t *testing.T) {
+	policytest.Run(t, scribble.DefaultAuthorizationPolicy(), "policy_assertions.txt")
+}
@@ -0,0 +1,96 @@
+// Package queue runs the background workers that process stored work in batches, like the event outbox and the
+// deliveries to remote servers, and spaces out the retries of the work that failed.
+package queue
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Backoff doubles the wait after every failed attempt, starting at Base and never longer than Max.
+type Backoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// Delay returns how long to wait before the next attempt, after the number of failed attempts.
+func (backoff Backoff) Delay(attempts int) time.Duration {
+	delay := backoff.Base
+	for range attempts - 1 {
+		delay *= 2
+		if delay >= backoff.Max {
+			return backoff.Max
+		}
+	}
+
+	return delay
+}
+
+// Waker wakes a worker up to process new work before its next poll.
+type Waker struct {
+	wake chan struct{}
+}
+
+func NewWaker() *Waker {
+	return &Waker{wake: make(chan struct{}, 1)}
+}
+
+// Wake wakes the worker up, unless it is already due to run.
+func (waker *Waker) Wake() {
+	select {
+	case waker.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Worker processes batches of due work, whenever it is woken up and every Interval.
+type Worker struct {
+	// Name tells the logs which work failed.
+	Name      string
+	Interval  time.Duration
+	BatchSize int
+	// Process processes the next batch of due work, and returns how many it processed.
+	Process func(ctx context.Context) (processed int, err error)
+	// Waker, if any, wakes the worker up as work is added.
+	Waker *Waker
+}
+
+// Run processes due work until the context is done.
+func (worker Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(worker.Interval)
+	defer ticker.Stop()
+
+	// A nil channel never receives, for workers that only poll.
+	var wake <-chan struct{}
+	if worker.Waker != nil {
+		wake = worker.Waker.wake
+	}
+
+	for {
+		worker.drain(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-wake:
+		}
+	}
+}
+
+// drain keeps processing while full batches come back, so a backlog drains without waiting for the next tick.
+func (worker Worker) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		processed, err := worker.Process(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to process queue", "queue", worker.Name, "error", err)
+
+			return
+		}
+
+		if processed < worker.BatchSize {
+			return
+		}
+	}
+}
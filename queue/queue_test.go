@@ -0,0 +1,101 @@
+package queue_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/queue"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	backoff := queue.Backoff{Base: time.Minute, Max: 10 * time.Minute}
+
+	assert.Equal(t, time.Minute, backoff.Delay(1))
+	assert.Equal(t, 2*time.Minute, backoff.Delay(2))
+	assert.Equal(t, 8*time.Minute, backoff.Delay(4))
+	assert.Equal(t, 10*time.Minute, backoff.Delay(5))
+	assert.Equal(t, 10*time.Minute, backoff.Delay(50))
+}
+
+func TestWorker(t *testing.T) {
+	t.Parallel()
+
+	t.Run("drains full batches and waits to be woken up", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+
+		var (
+			calls   atomic.Int32
+			backlog atomic.Int32
+		)
+
+		backlog.Store(5)
+
+		waker := queue.NewWaker()
+
+		go queue.Worker{
+			Name:      "test",
+			Interval:  time.Hour,
+			BatchSize: 2,
+			Process: func(_ context.Context) (int, error) {
+				calls.Add(1)
+
+				processed := min(backlog.Load(), 2)
+				backlog.Add(-processed)
+
+				return int(processed), nil
+			},
+			Waker: waker,
+		}.Run(ctx)
+
+		// Batches of 2, 2 and 1 drain the backlog of 5.
+		assert.Eventually(t, func() bool { return calls.Load() == 3 }, time.Second, time.Millisecond)
+
+		backlog.Store(1)
+		waker.Wake()
+
+		assert.Eventually(t, func() bool { return calls.Load() == 4 }, time.Second, time.Millisecond)
+		assert.Equal(t, int32(0), backlog.Load())
+	})
+
+	t.Run("stops draining on errors", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		var calls atomic.Int32
+
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+
+			queue.Worker{
+				Name:      "test",
+				Interval:  time.Hour,
+				BatchSize: 1,
+				Process: func(_ context.Context) (int, error) {
+					calls.Add(1)
+
+					return 0, errors.New("boom")
+				},
+				Waker: nil,
+			}.Run(ctx)
+		}()
+
+		assert.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+
+		cancel()
+		<-done
+
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
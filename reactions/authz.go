@@ -5,27 +5,63 @@ import (
 	"fmt"
 
 	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/communities"
 )
 
 const (
-	ActionToggleReaction = "toggleReaction"
-	ActionGetMyReactions = "getMyReactions"
+	ActionToggleReaction   = "toggleReaction"
+	ActionGetMyReactions   = "getMyReactions"
+	ActionListReactors     = "listReactors"
+	ActionListReactionSets = "listReactionSets"
+	// ActionManageReactionSets is checked in the community domain for sets of communities, and in the service domain
+	// for the sets of the site and target types.
+	ActionManageReactionSets = "manageReactionSets"
+	// ActionSetPostReactionSet lets users choose the sets of posts. The service lets only the author of the post.
+	ActionSetPostReactionSet = "setPostReactionSet"
 )
 
 type AuthorizationMiddleware struct {
 	authzClient *authorization.Client
+	targets     *TargetRegistry
 	next        Service
 }
 
 var _ Service = (*AuthorizationMiddleware)(nil)
 
-func NewAuthorizationMiddleware(authzClient *authorization.Client, next Service) *AuthorizationMiddleware {
+// NewAuthorizationMiddleware returns the middleware. The actions on the reactions to a target are checked on the
+// object the registry maps the target to.
+func NewAuthorizationMiddleware(
+	authzClient *authorization.Client,
+	targets *TargetRegistry,
+	next Service,
+) *AuthorizationMiddleware {
 	return &AuthorizationMiddleware{
 		authzClient: authzClient,
+		targets:     targets,
 		next:        next,
 	}
 }
 
+// checkTargetAccess checks the action on the reactions to the target.
+func (mw *AuthorizationMiddleware) checkTargetAccess(
+	ctx context.Context,
+	targetType TargetType,
+	targetID string,
+	action string,
+) error {
+	object, err := mw.targets.Object(targetType, targetID)
+	if err != nil {
+		return err
+	}
+
+	err = mw.authzClient.CheckAccess(ctx, ServiceName, object, action)
+	if err != nil {
+		return fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	return nil
+}
+
 func (mw *AuthorizationMiddleware) AllowedEmojis(
 	ctx context.Context,
 	targetType TargetType,
@@ -45,9 +81,9 @@ func (mw *AuthorizationMiddleware) ToggleMyReaction(
 	targetID string,
 	emoji string,
 ) error {
-	err := mw.authzClient.CheckAccess(ctx, ServiceName, "", ActionToggleReaction)
+	err := mw.checkTargetAccess(ctx, targetType, targetID, ActionToggleReaction)
 	if err != nil {
-		return fmt.Errorf("failed to check authorization: %w", err)
+		return err
 	}
 
 	err = mw.next.ToggleMyReaction(ctx, targetType, targetID, emoji)
@@ -63,9 +99,9 @@ func (mw *AuthorizationMiddleware) GetMyReactions(
 	targetType TargetType,
 	targetID string,
 ) (*TargetReactions, error) {
-	err := mw.authzClient.CheckAccess(ctx, ServiceName, "", ActionGetMyReactions)
+	err := mw.checkTargetAccess(ctx, targetType, targetID, ActionGetMyReactions)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check authorization: %w", err)
+		return nil, err
 	}
 
 	res, err := mw.next.GetMyReactions(ctx, targetType, targetID)
@@ -75,3 +111,88 @@ func (mw *AuthorizationMiddleware) GetMyReactions(
 
 	return res, nil
 }
+
+func (mw *AuthorizationMiddleware) ListReactors(ctx context.Context, req ListReactorsRequest) ([]*UserReaction, error) {
+	err := mw.checkTargetAccess(ctx, req.TargetType, req.TargetID, ActionListReactors)
+	if err != nil {
+		return nil, err
+	}
+
+	reactors, err := mw.next.ListReactors(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return reactors, nil
+}
+
+func (mw *AuthorizationMiddleware) ListReactionSets(ctx context.Context) ([]*ReactionSet, error) {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, "", ActionListReactionSets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	sets, err := mw.next.ListReactionSets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return sets, nil
+}
+
+func (mw *AuthorizationMiddleware) GetReactionSet(
+	ctx context.Context,
+	scope SetScope,
+	scopeID string,
+) (*ReactionSet, error) {
+	set, err := mw.next.GetReactionSet(ctx, scope, scopeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return set, nil
+}
+
+func (mw *AuthorizationMiddleware) SetReactionSet(ctx context.Context, req SetReactionSetRequest) error {
+	domain, action := setAccess(req.Scope, req.ScopeID)
+
+	err := mw.authzClient.CheckAccess(ctx, domain, "", action)
+	if err != nil {
+		return fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	err = mw.next.SetReactionSet(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return nil
+}
+
+func (mw *AuthorizationMiddleware) DeleteReactionSet(ctx context.Context, scope SetScope, scopeID string) error {
+	domain, action := setAccess(scope, scopeID)
+
+	err := mw.authzClient.CheckAccess(ctx, domain, "", action)
+	if err != nil {
+		return fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	err = mw.next.DeleteReactionSet(ctx, scope, scopeID)
+	if err != nil {
+		return fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return nil
+}
+
+// setAccess returns the domain and action to check for changing the reaction set of the scope.
+func setAccess(scope SetScope, scopeID string) (string, string) {
+	switch scope {
+	case SetScopePost:
+		return ServiceName, ActionSetPostReactionSet
+	case SetScopeCommunity:
+		return communities.Domain(scopeID), ActionManageReactionSets
+	default:
+		return ServiceName, ActionManageReactionSets
+	}
+}
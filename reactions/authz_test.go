@@ -11,6 +11,7 @@ import (
 	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
 	"github.com/nasermirzaei89/scribble/authorization"
 	"github.com/nasermirzaei89/scribble/authorization/casbin"
+	"github.com/nasermirzaei89/scribble/communities"
 	"github.com/nasermirzaei89/scribble/reactions"
 	"github.com/stretchr/testify/require"
 )
@@ -46,15 +47,45 @@ func (s *stubService) GetMyReactions(
 	}, nil
 }
 
+func (s *stubService) ListReactors(
+	ctx context.Context,
+	req reactions.ListReactorsRequest,
+) ([]*reactions.UserReaction, error) {
+	return []*reactions.UserReaction{}, nil
+}
+
+func (s *stubService) ListReactionSets(ctx context.Context) ([]*reactions.ReactionSet, error) {
+	return []*reactions.ReactionSet{}, nil
+}
+
+func (s *stubService) GetReactionSet(
+	ctx context.Context,
+	scope reactions.SetScope,
+	scopeID string,
+) (*reactions.ReactionSet, error) {
+	return &reactions.ReactionSet{Scope: scope, ScopeID: scopeID}, nil
+}
+
+func (s *stubService) SetReactionSet(ctx context.Context, req reactions.SetReactionSetRequest) error {
+	return nil
+}
+
+func (s *stubService) DeleteReactionSet(ctx context.Context, scope reactions.SetScope, scopeID string) error {
+	return nil
+}
+
 func TestAuthorizationMiddleware(t *testing.T) {
 	ctx := context.Background()
 
 	tmpDir := t.TempDir()
 	tmpFile := filepath.Join(tmpDir, "policy.csv")
-	content := []byte(`g, system:anonymous, system:unauthenticated
+	content := []byte(`g, system:anonymous, system:unauthenticated, *
 
-p, system:authenticated, github.com/nasermirzaei89/scribble/reactions, -, toggleReaction
-p, system:authenticated, github.com/nasermirzaei89/scribble/reactions, -, getMyReactions
+p, system:authenticated, github.com/nasermirzaei89/scribble/reactions, *, toggleReaction
+p, system:authenticated, github.com/nasermirzaei89/scribble/reactions, *, getMyReactions
+p, system:authenticated, github.com/nasermirzaei89/scribble/reactions, *, listReactors
+p, system:authenticated, github.com/nasermirzaei89/scribble/reactions, -, setPostReactionSet
+p, community:owner, github.com/nasermirzaei89/scribble/communities/*, -, manageReactionSets
 `)
 
 	err := os.WriteFile(tmpFile, content, 0o600)
@@ -68,7 +99,16 @@ p, system:authenticated, github.com/nasermirzaei89/scribble/reactions, -, getMyR
 	require.NoError(t, err)
 
 	client := authorization.NewClient(authzSvc)
-	svc := reactions.NewAuthorizationMiddleware(client, &stubService{})
+
+	targets := reactions.NewTargetRegistry()
+	err = targets.Register(reactions.TargetTypeSpec{
+		Type:   reactions.TargetTypePost,
+		Exists: func(ctx context.Context, targetID string) (bool, error) { return true, nil },
+		Object: reactions.TypedObject(reactions.TargetTypePost),
+	})
+	require.NoError(t, err)
+
+	svc := reactions.NewAuthorizationMiddleware(client, targets, &stubService{})
 
 	userID := uuid.NewString()
 	err = client.AddToGroup(ctx, userID, authcontext.Authenticated)
@@ -91,6 +131,15 @@ p, system:authenticated, github.com/nasermirzaei89/scribble/reactions, -, getMyR
 		_, err = svc.GetMyReactions(anonymousCtx, targetType, targetID)
 		require.Error(t, err)
 		require.ErrorAs(t, err, &accessDeniedErr)
+
+		_, err = svc.ListReactors(anonymousCtx, reactions.ListReactorsRequest{
+			TargetType: targetType,
+			TargetID:   targetID,
+			Emoji:      emoji,
+			Before:     nil,
+			Limit:      0,
+		})
+		require.ErrorAs(t, err, &accessDeniedErr)
 	})
 
 	t.Run("authenticated", func(t *testing.T) {
@@ -99,5 +148,93 @@ p, system:authenticated, github.com/nasermirzaei89/scribble/reactions, -, getMyR
 
 		_, err = svc.GetMyReactions(authenticatedCtx, targetType, targetID)
 		require.NoError(t, err)
+
+		_, err = svc.ListReactors(authenticatedCtx, reactions.ListReactorsRequest{
+			TargetType: targetType,
+			TargetID:   targetID,
+			Emoji:      emoji,
+			Before:     nil,
+			Limit:      0,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("targets are objects", func(t *testing.T) {
+		guestID := uuid.NewString()
+		err := client.AddPolicyForSubject(
+			ctx,
+			guestID,
+			reactions.ServiceName,
+			"post:"+targetID,
+			reactions.ActionToggleReaction,
+		)
+		require.NoError(t, err)
+
+		guestCtx := authcontext.WithSubject(ctx, guestID)
+
+		err = svc.ToggleMyReaction(guestCtx, targetType, targetID, emoji)
+		require.NoError(t, err)
+
+		err = svc.ToggleMyReaction(guestCtx, targetType, uuid.NewString(), emoji)
+		accessDeniedErr := &authorization.AccessDeniedError{}
+		require.ErrorAs(t, err, &accessDeniedErr)
+
+		err = svc.ToggleMyReaction(authenticatedCtx, reactions.TargetTypeComment, targetID, emoji)
+		require.ErrorAs(t, err, &reactions.InvalidTargetTypeError{})
+	})
+
+	t.Run("reaction sets", func(t *testing.T) {
+		ownerID := uuid.NewString()
+		err := client.AddToDomainGroup(ctx, ownerID, communities.Domain("c1"), "community:owner")
+		require.NoError(t, err)
+
+		ownerCtx := authcontext.WithSubject(ctx, ownerID)
+		accessDeniedErr := &authorization.AccessDeniedError{}
+
+		_, err = svc.ListReactionSets(authenticatedCtx)
+		require.ErrorAs(t, err, &accessDeniedErr)
+
+		siteSet := reactions.SetReactionSetRequest{
+			Scope:      reactions.SetScopeSite,
+			ScopeID:    "",
+			Emojis:     []string{"👍"},
+			MaxPerUser: 1,
+		}
+
+		err = svc.SetReactionSet(authenticatedCtx, siteSet)
+		require.ErrorAs(t, err, &accessDeniedErr)
+
+		err = svc.SetReactionSet(ownerCtx, siteSet)
+		require.ErrorAs(t, err, &accessDeniedErr)
+
+		// Community owners manage the sets of their community only.
+		err = svc.SetReactionSet(ownerCtx, reactions.SetReactionSetRequest{
+			Scope:      reactions.SetScopeCommunity,
+			ScopeID:    "c1",
+			Emojis:     []string{"👍"},
+			MaxPerUser: 1,
+		})
+		require.NoError(t, err)
+
+		err = svc.DeleteReactionSet(ownerCtx, reactions.SetScopeCommunity, "c2")
+		require.ErrorAs(t, err, &accessDeniedErr)
+
+		err = svc.DeleteReactionSet(authenticatedCtx, reactions.SetScopeCommunity, "c1")
+		require.ErrorAs(t, err, &accessDeniedErr)
+
+		// Posts are checked for authorship by the service.
+		err = svc.SetReactionSet(authenticatedCtx, reactions.SetReactionSetRequest{
+			Scope:      reactions.SetScopePost,
+			ScopeID:    targetID,
+			Emojis:     []string{"👍"},
+			MaxPerUser: 1,
+		})
+		require.NoError(t, err)
+
+		err = svc.DeleteReactionSet(anonymousCtx, reactions.SetScopePost, targetID)
+		require.ErrorAs(t, err, &accessDeniedErr)
+
+		_, err = svc.GetReactionSet(anonymousCtx, reactions.SetScopePost, targetID)
+		require.NoError(t, err)
 	})
 }
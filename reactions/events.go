@@ -0,0 +1,186 @@
+package reactions
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/events"
+)
+
+// EventsMiddleware publishes the changes of the next service, in the same transaction.
+type EventsMiddleware struct {
+	bus  *events.Bus
+	next Service
+}
+
+var _ Service = (*EventsMiddleware)(nil)
+
+func NewEventsMiddleware(bus *events.Bus, next Service) *EventsMiddleware {
+	return &EventsMiddleware{
+		bus:  bus,
+		next: next,
+	}
+}
+
+func (mw *EventsMiddleware) AllowedEmojis(
+	ctx context.Context,
+	targetType TargetType,
+	targetID string,
+) ([]string, error) {
+	emojis, err := mw.next.AllowedEmojis(ctx, targetType, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return emojis, nil
+}
+
+func (mw *EventsMiddleware) ToggleMyReaction(
+	ctx context.Context,
+	targetType TargetType,
+	targetID string,
+	emoji string,
+) error {
+	err := mw.bus.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Toggling does not tell which reactions it added or removed, so the reactions before and after it do. Adding
+		// one can replace others, when a user has only one reaction on a target.
+		before, err := mw.mySelectedEmojis(ctx, targetType, targetID)
+		if err != nil {
+			return err
+		}
+
+		err = mw.next.ToggleMyReaction(ctx, targetType, targetID, emoji)
+		if err != nil {
+			return fmt.Errorf("failed to call next method: %w", err)
+		}
+
+		after, err := mw.mySelectedEmojis(ctx, targetType, targetID)
+		if err != nil {
+			return err
+		}
+
+		toggled := make([]events.Event, 0, len(before)+1)
+
+		for _, selected := range before {
+			if !slices.Contains(after, selected) {
+				toggled = append(toggled, reactionToggled(ctx, targetType, targetID, selected, false))
+			}
+		}
+
+		for _, selected := range after {
+			if !slices.Contains(before, selected) {
+				toggled = append(toggled, reactionToggled(ctx, targetType, targetID, selected, true))
+			}
+		}
+
+		err = mw.bus.Publish(ctx, toggled...)
+		if err != nil {
+			return fmt.Errorf("failed to publish event: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to toggle reaction: %w", err)
+	}
+
+	return nil
+}
+
+// mySelectedEmojis returns the emojis the current user reacted to the target with.
+func (mw *EventsMiddleware) mySelectedEmojis(
+	ctx context.Context,
+	targetType TargetType,
+	targetID string,
+) ([]string, error) {
+	targetReactions, err := mw.next.GetMyReactions(ctx, targetType, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get my reactions: %w", err)
+	}
+
+	selected := make([]string, 0)
+
+	for _, option := range targetReactions.Options {
+		if option.Selected {
+			selected = append(selected, option.Emoji)
+		}
+	}
+
+	return selected, nil
+}
+
+func reactionToggled(
+	ctx context.Context,
+	targetType TargetType,
+	targetID string,
+	emoji string,
+	added bool,
+) events.ReactionToggled {
+	return events.ReactionToggled{
+		TargetType: string(targetType),
+		TargetID:   targetID,
+		UserID:     authcontext.GetSubject(ctx),
+		Emoji:      emoji,
+		Added:      added,
+	}
+}
+
+func (mw *EventsMiddleware) GetMyReactions(
+	ctx context.Context,
+	targetType TargetType,
+	targetID string,
+) (*TargetReactions, error) {
+	targetReactions, err := mw.next.GetMyReactions(ctx, targetType, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return targetReactions, nil
+}
+
+func (mw *EventsMiddleware) ListReactors(ctx context.Context, req ListReactorsRequest) ([]*UserReaction, error) {
+	reactors, err := mw.next.ListReactors(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return reactors, nil
+}
+
+func (mw *EventsMiddleware) ListReactionSets(ctx context.Context) ([]*ReactionSet, error) {
+	sets, err := mw.next.ListReactionSets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return sets, nil
+}
+
+func (mw *EventsMiddleware) GetReactionSet(ctx context.Context, scope SetScope, scopeID string) (*ReactionSet, error) {
+	set, err := mw.next.GetReactionSet(ctx, scope, scopeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return set, nil
+}
+
+func (mw *EventsMiddleware) SetReactionSet(ctx context.Context, req SetReactionSetRequest) error {
+	err := mw.next.SetReactionSet(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return nil
+}
+
+func (mw *EventsMiddleware) DeleteReactionSet(ctx context.Context, scope SetScope, scopeID string) error {
+	err := mw.next.DeleteReactionSet(ctx, scope, scopeID)
+	if err != nil {
+		return fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return nil
+}
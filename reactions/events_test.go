@@ -0,0 +1,121 @@
+package reactions_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/database/sqlite3"
+	"github.com/nasermirzaei89/scribble/events"
+	"github.com/nasermirzaei89/scribble/reactions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventsMiddleware(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sqlite3.NewDB(ctx, "file:TestEventsMiddleware?mode=memory&cache=shared")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := db.Close()
+		require.NoError(t, err)
+	})
+
+	err = sqlite3.MigrateUp(ctx, db)
+	require.NoError(t, err)
+
+	bus := events.NewBus(sqlite3.NewEventOutboxRepository(db), sqlite3.NewTransactor(db))
+	svc := reactions.NewEventsMiddleware(bus, reactions.NewBaseService(
+		reactions.Config{DefaultEmojis: []string{"👍", "👎", "😂", "❤️"}, MaxPerUser: 0},
+		newTargetRegistry(t, db),
+		sqlite3.NewUserReactionRepository(db),
+		sqlite3.NewReactionSetRepository(db),
+		sqlite3.NewPostRepository(db),
+		sqlite3.NewCommentRepository(db),
+		sqlite3.NewCustomEmojiRepository(db),
+	))
+
+	var (
+		published []events.ReactionToggled
+		errVeto   error
+	)
+
+	events.Subscribe(bus, func(_ context.Context, event events.ReactionToggled) error {
+		if errVeto != nil {
+			return errVeto
+		}
+
+		published = append(published, event)
+
+		return nil
+	})
+
+	err = sqlite3.NewPostRepository(db).Insert(ctx, &contents.Post{
+		ID:          "post1",
+		AuthorID:    "alice",
+		CommunityID: "",
+		Content:     "post",
+		CreatedAt:   time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	ctx = authcontext.WithSubject(ctx, "user1")
+
+	for _, emoji := range []string{"👍", "😂", "😂"} {
+		err = svc.ToggleMyReaction(ctx, reactions.TargetTypePost, "post1", emoji)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []events.ReactionToggled{
+		{TargetType: "post", TargetID: "post1", UserID: "user1", Emoji: "👍", Added: true},
+		{TargetType: "post", TargetID: "post1", UserID: "user1", Emoji: "👍", Added: false},
+		{TargetType: "post", TargetID: "post1", UserID: "user1", Emoji: "😂", Added: true},
+		{TargetType: "post", TargetID: "post1", UserID: "user1", Emoji: "😂", Added: false},
+	}, published)
+
+	t.Run("switching reactions publishes the removed one", func(t *testing.T) {
+		published = nil
+
+		for _, emoji := range []string{"👍", "❤️"} {
+			err := svc.ToggleMyReaction(ctx, reactions.TargetTypePost, "post1", emoji)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, []events.ReactionToggled{
+			{TargetType: "post", TargetID: "post1", UserID: "user1", Emoji: "👍", Added: true},
+			{TargetType: "post", TargetID: "post1", UserID: "user1", Emoji: "👍", Added: false},
+			{TargetType: "post", TargetID: "post1", UserID: "user1", Emoji: "❤️", Added: true},
+		}, published)
+
+		err := svc.ToggleMyReaction(ctx, reactions.TargetTypePost, "post1", "❤️")
+		require.NoError(t, err)
+	})
+
+	t.Run("failing subscriber rolls the change back", func(t *testing.T) {
+		errVeto = errors.New("veto")
+
+		err := svc.ToggleMyReaction(ctx, reactions.TargetTypePost, "post1", "👎")
+		require.ErrorIs(t, err, errVeto)
+
+		targetReactions, err := svc.GetMyReactions(ctx, reactions.TargetTypePost, "post1")
+		require.NoError(t, err)
+
+		for _, option := range targetReactions.Options {
+			assert.False(t, option.Selected, option.Emoji)
+		}
+	})
+
+	t.Run("invalid emoji is not published", func(t *testing.T) {
+		errVeto = nil
+		published = nil
+
+		err := svc.ToggleMyReaction(ctx, reactions.TargetTypePost, "post1", "🚀")
+		require.ErrorAs(t, err, new(reactions.InvalidEmojiError))
+		assert.Empty(t, published)
+	})
+}
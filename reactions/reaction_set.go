@@ -0,0 +1,134 @@
+package reactions
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode"
+)
+
+// SetScope is what a reaction set applies to. Narrower scopes override broader ones: the set of a post applies to it
+// and its comments, then the set of its community, then the set of the target type, then the set of the site.
+type SetScope string
+
+const (
+	SetScopeSite       SetScope = "site"
+	SetScopeTargetType SetScope = "targetType"
+	SetScopeCommunity  SetScope = "community"
+	SetScopePost       SetScope = "post"
+)
+
+// SetScopes returns all set scopes, from the broadest to the narrowest.
+func SetScopes() []SetScope {
+	return []SetScope{SetScopeSite, SetScopeTargetType, SetScopeCommunity, SetScopePost}
+}
+
+func (scope SetScope) IsValid() bool {
+	switch scope {
+	case SetScopeSite, SetScopeTargetType, SetScopeCommunity, SetScopePost:
+		return true
+	default:
+		return false
+	}
+}
+
+// Title returns the human-readable name of the scope.
+func (scope SetScope) Title() string {
+	switch scope {
+	case SetScopeSite:
+		return "Site"
+	case SetScopeTargetType:
+		return "Target type"
+	case SetScopeCommunity:
+		return "Community"
+	case SetScopePost:
+		return "Post"
+	default:
+		return string(scope)
+	}
+}
+
+// MaxSetSize is how many emojis a reaction set holds at most.
+const MaxSetSize = 12
+
+// ReactionSet is the emojis users can react with in its scope. Emojis removed from a set are retired: their
+// reactions are kept and still counted, but no new ones are accepted.
+type ReactionSet struct {
+	Scope SetScope
+	// ScopeID is the target type, community ID or post ID the set applies to. It is empty for the site.
+	ScopeID string
+	Emojis  []string
+	// MaxPerUser is how many of the emojis a user can react with at once. With one, reacting again replaces the
+	// reaction.
+	MaxPerUser int
+	UpdatedAt  time.Time
+}
+
+type ReactionSetRepository interface {
+	Find(ctx context.Context, scope SetScope, scopeID string) (set *ReactionSet, err error)
+	List(ctx context.Context) (sets []*ReactionSet, err error)
+	// Save inserts the set or replaces the one of its scope.
+	Save(ctx context.Context, set *ReactionSet) (err error)
+	Delete(ctx context.Context, scope SetScope, scopeID string) (err error)
+}
+
+// isEmoji reports whether the text looks like a single Unicode emoji, including sequences joined with ZWJ, skin
+// tones, flags and keycaps.
+func isEmoji(text string) bool {
+	if text == "" {
+		return false
+	}
+
+	symbols := 0
+
+	for _, r := range text {
+		switch {
+		case unicode.IsSymbol(r) && r > unicode.MaxLatin1, r == '\u20e3':
+			// Symbols like + and $ are not emojis. The keycap makes one of the digit or sign before it.
+			symbols++
+		case r == '\u200d', r == '\ufe0f', r >= '\U000e0020' && r <= '\U000e007f':
+			// Joiners, the emoji presentation selector and tag characters only modify symbols.
+		case r == '#', r == '*', r >= '0' && r <= '9':
+			// Keycaps start with these.
+		default:
+			return false
+		}
+	}
+
+	return symbols > 0
+}
+
+type ReactionSetNotFoundError struct {
+	Scope   SetScope
+	ScopeID string
+}
+
+func (err ReactionSetNotFoundError) Error() string {
+	return fmt.Sprintf("reaction set of %s %q not found", err.Scope, err.ScopeID)
+}
+
+type InvalidSetScopeError struct {
+	Scope   SetScope
+	ScopeID string
+}
+
+func (err InvalidSetScopeError) Error() string {
+	return fmt.Sprintf("invalid reaction set scope: %s %q", err.Scope, err.ScopeID)
+}
+
+type InvalidReactionSetError struct {
+	Reason string
+}
+
+func (err InvalidReactionSetError) Error() string {
+	return "invalid reaction set: " + err.Reason
+}
+
+// NotPostAuthorError is returned for changes only the author of the post may make.
+type NotPostAuthorError struct {
+	PostID string
+}
+
+func (err NotPostAuthorError) Error() string {
+	return fmt.Sprintf("not the author of post %q", err.PostID)
+}
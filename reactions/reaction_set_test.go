@@ -0,0 +1,424 @@
+package reactions_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/database/sqlite3"
+	"github.com/nasermirzaei89/scribble/discuss"
+	"github.com/nasermirzaei89/scribble/emojis"
+	"github.com/nasermirzaei89/scribble/reactions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReactionSets(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sqlite3.NewDB(ctx, "file:TestReactionSets?mode=memory&cache=shared")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := db.Close()
+		require.NoError(t, err)
+	})
+
+	err = sqlite3.MigrateUp(ctx, db)
+	require.NoError(t, err)
+
+	postRepo := sqlite3.NewPostRepository(db)
+	commentRepo := sqlite3.NewCommentRepository(db)
+
+	svc := reactions.NewBaseService(
+		reactions.Config{DefaultEmojis: []string{"👍", "👎"}, MaxPerUser: 1},
+		newTargetRegistry(t, db),
+		sqlite3.NewUserReactionRepository(db),
+		sqlite3.NewReactionSetRepository(db),
+		postRepo,
+		commentRepo,
+		sqlite3.NewCustomEmojiRepository(db),
+	)
+
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	for _, post := range []*contents.Post{
+		{ID: "post1", AuthorID: "alice", CommunityID: "", Content: "post", CreatedAt: now},
+		{ID: "post2", AuthorID: "alice", CommunityID: "c1", Content: "post", CreatedAt: now},
+	} {
+		err = postRepo.Insert(ctx, post)
+		require.NoError(t, err)
+	}
+
+	err = commentRepo.Insert(ctx, &discuss.Comment{
+		ID:        "comment1",
+		PostID:    "post2",
+		AuthorID:  "bob",
+		ReplyTo:   nil,
+		Content:   "comment",
+		CreatedAt: now,
+	})
+	require.NoError(t, err)
+
+	aliceCtx := authcontext.WithSubject(ctx, "alice")
+	bobCtx := authcontext.WithSubject(ctx, "bob")
+
+	allowed := func(t *testing.T, targetType reactions.TargetType, targetID string) []string {
+		t.Helper()
+
+		emojis, err := svc.AllowedEmojis(ctx, targetType, targetID)
+		require.NoError(t, err)
+
+		return emojis
+	}
+
+	set := func(t *testing.T, ctx context.Context, scope reactions.SetScope, scopeID string, emojis ...string) {
+		t.Helper()
+
+		err := svc.SetReactionSet(ctx, reactions.SetReactionSetRequest{
+			Scope:      scope,
+			ScopeID:    scopeID,
+			Emojis:     emojis,
+			MaxPerUser: 1,
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("narrower sets override broader ones", func(t *testing.T) {
+		assert.Equal(t, []string{"👍", "👎"}, allowed(t, reactions.TargetTypePost, "post1"))
+
+		set(t, ctx, reactions.SetScopeSite, "", "❤️", "😂")
+		assert.Equal(t, []string{"❤️", "😂"}, allowed(t, reactions.TargetTypePost, "post1"))
+		assert.Equal(t, []string{"❤️", "😂"}, allowed(t, reactions.TargetTypeComment, "comment1"))
+
+		set(t, ctx, reactions.SetScopeTargetType, "comment", "👀")
+		assert.Equal(t, []string{"❤️", "😂"}, allowed(t, reactions.TargetTypePost, "post2"))
+		assert.Equal(t, []string{"👀"}, allowed(t, reactions.TargetTypeComment, "comment1"))
+
+		set(t, ctx, reactions.SetScopeCommunity, "c1", "🚀")
+		assert.Equal(t, []string{"❤️", "😂"}, allowed(t, reactions.TargetTypePost, "post1"))
+		assert.Equal(t, []string{"🚀"}, allowed(t, reactions.TargetTypePost, "post2"))
+		assert.Equal(t, []string{"🚀"}, allowed(t, reactions.TargetTypeComment, "comment1"))
+
+		// The set of a post applies to its comments too.
+		set(t, aliceCtx, reactions.SetScopePost, "post2", "🎉", "🙏")
+		assert.Equal(t, []string{"🎉", "🙏"}, allowed(t, reactions.TargetTypePost, "post2"))
+		assert.Equal(t, []string{"🎉", "🙏"}, allowed(t, reactions.TargetTypeComment, "comment1"))
+
+		err := svc.DeleteReactionSet(aliceCtx, reactions.SetScopePost, "post2")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"🚀"}, allowed(t, reactions.TargetTypePost, "post2"))
+
+		err = svc.DeleteReactionSet(aliceCtx, reactions.SetScopePost, "post2")
+		require.ErrorAs(t, err, &reactions.ReactionSetNotFoundError{})
+	})
+
+	t.Run("only the author chooses the set of a post", func(t *testing.T) {
+		err := svc.SetReactionSet(bobCtx, reactions.SetReactionSetRequest{
+			Scope:      reactions.SetScopePost,
+			ScopeID:    "post1",
+			Emojis:     []string{"👎"},
+			MaxPerUser: 1,
+		})
+		require.ErrorAs(t, err, &reactions.NotPostAuthorError{})
+
+		err = svc.SetReactionSet(aliceCtx, reactions.SetReactionSetRequest{
+			Scope:      reactions.SetScopePost,
+			ScopeID:    "post404",
+			Emojis:     []string{"👎"},
+			MaxPerUser: 1,
+		})
+		require.ErrorAs(t, err, &contents.PostNotFoundError{})
+	})
+
+	t.Run("invalid sets", func(t *testing.T) {
+		for name, req := range map[string]reactions.SetReactionSetRequest{
+			"no emojis":    {Scope: reactions.SetScopeSite, ScopeID: "", Emojis: nil},
+			"not an emoji": {Scope: reactions.SetScopeSite, ScopeID: "", Emojis: []string{"+1"}},
+			"listed twice": {Scope: reactions.SetScopeSite, ScopeID: "", Emojis: []string{"👍", "👍"}},
+			"too many emojis": {Scope: reactions.SetScopeSite, ScopeID: "", MaxPerUser: 1, Emojis: []string{
+				"😀", "😃", "😄", "😁", "😆", "😅", "🤣", "😂", "🙂", "🙃", "🫠", "😉", "😊",
+			}},
+			"no reactions per user": {
+				Scope:      reactions.SetScopeSite,
+				ScopeID:    "",
+				Emojis:     []string{"👍"},
+				MaxPerUser: 0,
+			},
+			"too many reactions per user": {
+				Scope:      reactions.SetScopeSite,
+				ScopeID:    "",
+				Emojis:     []string{"👍"},
+				MaxPerUser: reactions.MaxSetSize + 1,
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				err := svc.SetReactionSet(ctx, req)
+				require.ErrorAs(t, err, &reactions.InvalidReactionSetError{})
+			})
+		}
+
+		for name, req := range map[string]reactions.SetReactionSetRequest{
+			"site with an ID":     {Scope: reactions.SetScopeSite, ScopeID: "x", Emojis: []string{"👍"}},
+			"unknown target type": {Scope: reactions.SetScopeTargetType, ScopeID: "page", Emojis: []string{"👍"}},
+			"unknown scope":       {Scope: "user", ScopeID: "alice", Emojis: []string{"👍"}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				err := svc.SetReactionSet(ctx, req)
+				require.ErrorAs(t, err, &reactions.InvalidSetScopeError{})
+			})
+		}
+
+		err := svc.SetReactionSet(ctx, reactions.SetReactionSetRequest{
+			Scope:      reactions.SetScopeSite,
+			ScopeID:    "",
+			Emojis:     []string{"👍🏽", "❤️", "🏳️‍🌈", "1️⃣", "🇳🇱"},
+			MaxPerUser: 1,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("retired emojis keep their counts", func(t *testing.T) {
+		set(t, ctx, reactions.SetScopeSite, "", "👍", "😂")
+
+		err := svc.ToggleMyReaction(bobCtx, reactions.TargetTypePost, "post1", "😂")
+		require.NoError(t, err)
+
+		set(t, ctx, reactions.SetScopeSite, "", "👍")
+
+		targetReactions, err := svc.GetMyReactions(bobCtx, reactions.TargetTypePost, "post1")
+		require.NoError(t, err)
+		assert.Equal(t, []reactions.ReactionOption{
+			{Emoji: "👍", Count: 0, Selected: false, Available: true},
+			{Emoji: "😂", Count: 1, Selected: true, Available: false},
+		}, targetReactions.Options)
+
+		err = svc.ToggleMyReaction(aliceCtx, reactions.TargetTypePost, "post1", "😂")
+		require.ErrorAs(t, err, &reactions.InvalidEmojiError{})
+
+		// The reaction can still be taken back.
+		err = svc.ToggleMyReaction(bobCtx, reactions.TargetTypePost, "post1", "😂")
+		require.NoError(t, err)
+
+		targetReactions, err = svc.GetMyReactions(bobCtx, reactions.TargetTypePost, "post1")
+		require.NoError(t, err)
+		assert.Equal(t, []reactions.ReactionOption{
+			{Emoji: "👍", Count: 0, Selected: false, Available: true},
+		}, targetReactions.Options)
+	})
+}
+
+func TestMultipleReactions(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sqlite3.NewDB(ctx, "file:TestMultipleReactions?mode=memory&cache=shared")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := db.Close()
+		require.NoError(t, err)
+	})
+
+	err = sqlite3.MigrateUp(ctx, db)
+	require.NoError(t, err)
+
+	postRepo := sqlite3.NewPostRepository(db)
+
+	svc := reactions.NewBaseService(
+		reactions.Config{DefaultEmojis: []string{"👍", "❤️", "😂"}, MaxPerUser: 2},
+		newTargetRegistry(t, db),
+		sqlite3.NewUserReactionRepository(db),
+		sqlite3.NewReactionSetRepository(db),
+		postRepo,
+		sqlite3.NewCommentRepository(db),
+		sqlite3.NewCustomEmojiRepository(db),
+	)
+
+	err = postRepo.Insert(ctx, &contents.Post{
+		ID:          "post1",
+		AuthorID:    "alice",
+		CommunityID: "",
+		Content:     "post",
+		CreatedAt:   time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	bobCtx := authcontext.WithSubject(ctx, "bob")
+
+	selected := func(t *testing.T) []string {
+		t.Helper()
+
+		targetReactions, err := svc.GetMyReactions(bobCtx, reactions.TargetTypePost, "post1")
+		require.NoError(t, err)
+
+		selectedEmojis := []string{}
+
+		for _, option := range targetReactions.Options {
+			if option.Selected {
+				selectedEmojis = append(selectedEmojis, option.Emoji)
+			}
+		}
+
+		return selectedEmojis
+	}
+
+	t.Run("users react with several emojis up to the maximum", func(t *testing.T) {
+		err := svc.ToggleMyReaction(bobCtx, reactions.TargetTypePost, "post1", "👍")
+		require.NoError(t, err)
+
+		err = svc.ToggleMyReaction(bobCtx, reactions.TargetTypePost, "post1", "❤️")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"👍", "❤️"}, selected(t))
+
+		err = svc.ToggleMyReaction(bobCtx, reactions.TargetTypePost, "post1", "😂")
+		require.ErrorAs(t, err, &reactions.TooManyReactionsError{})
+		assert.Equal(t, []string{"👍", "❤️"}, selected(t))
+
+		err = svc.ToggleMyReaction(bobCtx, reactions.TargetTypePost, "post1", "👍")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"❤️"}, selected(t))
+
+		err = svc.ToggleMyReaction(bobCtx, reactions.TargetTypePost, "post1", "😂")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"❤️", "😂"}, selected(t))
+	})
+
+	t.Run("sets of one reaction per user replace the reactions", func(t *testing.T) {
+		err := svc.SetReactionSet(ctx, reactions.SetReactionSetRequest{
+			Scope:      reactions.SetScopeSite,
+			ScopeID:    "",
+			Emojis:     []string{"👍", "❤️", "😂"},
+			MaxPerUser: 1,
+		})
+		require.NoError(t, err)
+
+		err = svc.ToggleMyReaction(bobCtx, reactions.TargetTypePost, "post1", "👍")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"👍"}, selected(t))
+
+		err = svc.ToggleMyReaction(bobCtx, reactions.TargetTypePost, "post1", "😂")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"😂"}, selected(t))
+	})
+
+	t.Run("reactors are listed by emoji", func(t *testing.T) {
+		err := svc.ToggleMyReaction(authcontext.WithSubject(ctx, "carol"), reactions.TargetTypePost, "post1", "👍")
+		require.NoError(t, err)
+
+		reactors, err := svc.ListReactors(bobCtx, reactions.ListReactorsRequest{
+			TargetType: reactions.TargetTypePost,
+			TargetID:   "post1",
+			Emoji:      "😂",
+			Before:     nil,
+			Limit:      0,
+		})
+		require.NoError(t, err)
+		require.Len(t, reactors, 1)
+		assert.Equal(t, "bob", reactors[0].UserID)
+
+		reactors, err = svc.ListReactors(bobCtx, reactions.ListReactorsRequest{
+			TargetType: reactions.TargetTypePost,
+			TargetID:   "post1",
+			Emoji:      "",
+			Before:     nil,
+			Limit:      0,
+		})
+		require.NoError(t, err)
+		assert.Len(t, reactors, 2)
+
+		_, err = svc.ListReactors(bobCtx, reactions.ListReactorsRequest{
+			TargetType: "page",
+			TargetID:   "post1",
+			Emoji:      "",
+			Before:     nil,
+			Limit:      0,
+		})
+		require.ErrorAs(t, err, &reactions.InvalidTargetTypeError{})
+	})
+}
+
+func TestCustomEmojiReactions(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sqlite3.NewDB(ctx, "file:TestCustomEmojiReactions?mode=memory&cache=shared")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := db.Close()
+		require.NoError(t, err)
+	})
+
+	err = sqlite3.MigrateUp(ctx, db)
+	require.NoError(t, err)
+
+	emojiRepo := sqlite3.NewCustomEmojiRepository(db)
+	postRepo := sqlite3.NewPostRepository(db)
+
+	svc := reactions.NewBaseService(
+		reactions.Config{DefaultEmojis: []string{"👍"}, MaxPerUser: 1},
+		newTargetRegistry(t, db),
+		sqlite3.NewUserReactionRepository(db),
+		sqlite3.NewReactionSetRepository(db),
+		postRepo,
+		sqlite3.NewCommentRepository(db),
+		emojiRepo,
+	)
+
+	err = postRepo.Insert(ctx, &contents.Post{
+		ID:          "post1",
+		AuthorID:    "alice",
+		CommunityID: "",
+		Content:     "post",
+		CreatedAt:   time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	bobCtx := authcontext.WithSubject(ctx, "bob")
+
+	setRequest := reactions.SetReactionSetRequest{
+		Scope:      reactions.SetScopeSite,
+		ScopeID:    "",
+		Emojis:     []string{"👍", ":wave:"},
+		MaxPerUser: 1,
+	}
+
+	err = svc.SetReactionSet(ctx, setRequest)
+	require.ErrorAs(t, err, &reactions.InvalidReactionSetError{})
+
+	err = emojiRepo.Insert(ctx, &emojis.CustomEmoji{
+		Shortcode:   "wave",
+		ContentType: "image/png",
+		CreatedBy:   "admin",
+		CreatedAt:   time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	err = svc.SetReactionSet(ctx, setRequest)
+	require.NoError(t, err)
+
+	err = svc.ToggleMyReaction(bobCtx, reactions.TargetTypePost, "post1", ":wave:")
+	require.NoError(t, err)
+
+	targetReactions, err := svc.GetMyReactions(bobCtx, reactions.TargetTypePost, "post1")
+	require.NoError(t, err)
+	assert.Equal(t, []reactions.ReactionOption{
+		{Emoji: "👍", Count: 0, Selected: false, Available: true},
+		{Emoji: ":wave:", Count: 1, Selected: true, Available: true},
+	}, targetReactions.Options)
+
+	// Deleted custom emojis are retired: their reactions stay, but take no new ones.
+	err = emojiRepo.Delete(ctx, "wave")
+	require.NoError(t, err)
+
+	targetReactions, err = svc.GetMyReactions(bobCtx, reactions.TargetTypePost, "post1")
+	require.NoError(t, err)
+	assert.Equal(t, []reactions.ReactionOption{
+		{Emoji: "👍", Count: 0, Selected: false, Available: true},
+		{Emoji: ":wave:", Count: 1, Selected: true, Available: false},
+	}, targetReactions.Options)
+
+	err = svc.ToggleMyReaction(authcontext.WithSubject(ctx, "carol"), reactions.TargetTypePost, "post1", ":wave:")
+	require.ErrorAs(t, err, &reactions.InvalidEmojiError{})
+}
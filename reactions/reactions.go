@@ -9,11 +9,21 @@ import (
 
 	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
 	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/discuss"
+	"github.com/nasermirzaei89/scribble/emojis"
+	"github.com/nasermirzaei89/scribble/events"
 )
 
 const ServiceName = "github.com/nasermirzaei89/scribble/reactions"
 
+const (
+	defaultReactorsLimit = 50
+	maxReactorsLimit     = 200
+)
+
 type Service interface {
+	// AllowedEmojis returns the emojis of the narrowest reaction set that applies to the target.
 	AllowedEmojis(ctx context.Context, targetType TargetType, targetID string) ([]string, error)
 	ToggleMyReaction(ctx context.Context, targetType TargetType, targetID string, emoji string) error
 	GetMyReactions(
@@ -21,20 +31,94 @@ type Service interface {
 		targetType TargetType,
 		targetID string,
 	) (*TargetReactions, error)
+	// ListReactors lists the reactions to the target, so users see who reacted with each emoji.
+	ListReactors(ctx context.Context, req ListReactorsRequest) ([]*UserReaction, error)
+	ListReactionSets(ctx context.Context) ([]*ReactionSet, error)
+	// GetReactionSet returns the set of the scope itself, not the one that applies to it.
+	GetReactionSet(ctx context.Context, scope SetScope, scopeID string) (*ReactionSet, error)
+	SetReactionSet(ctx context.Context, req SetReactionSetRequest) error
+	// DeleteReactionSet deletes the set of the scope, so the set of the broader scope applies again.
+	DeleteReactionSet(ctx context.Context, scope SetScope, scopeID string) error
+}
+
+// DefaultEmojis are the reactions of the site when it has no reaction set.
+func DefaultEmojis() []string {
+	return []string{"👍", "👎", "😂"}
+}
+
+type Config struct {
+	// DefaultEmojis are the reactions when no reaction set applies. Defaults to DefaultEmojis.
+	DefaultEmojis []string
+	// MaxPerUser is how many of the default emojis a user can react with at once. Defaults to one, so reacting
+	// again replaces the reaction.
+	MaxPerUser int
 }
 
 type BaseService struct {
+	defaultEmojis    []string
+	maxPerUser       int
+	targets          *TargetRegistry
 	userReactionRepo UserReactionRepository
+	reactionSetRepo  ReactionSetRepository
+	postRepo         contents.PostRepository
+	commentRepo      discuss.CommentRepository
+	emojiRepo        emojis.CustomEmojiRepository
 }
 
 var _ Service = (*BaseService)(nil)
 
-func NewService(userReactionRepo UserReactionRepository, authzClient *authorization.Client) Service { //nolint:ireturn
-	return NewAuthorizationMiddleware(authzClient, NewBaseService(userReactionRepo))
+func NewService( //nolint:ireturn
+	cfg Config,
+	targets *TargetRegistry,
+	userReactionRepo UserReactionRepository,
+	reactionSetRepo ReactionSetRepository,
+	postRepo contents.PostRepository,
+	commentRepo discuss.CommentRepository,
+	emojiRepo emojis.CustomEmojiRepository,
+	authzClient *authorization.Client,
+	bus *events.Bus,
+) Service {
+	return NewAuthorizationMiddleware(
+		authzClient,
+		targets,
+		NewEventsMiddleware(
+			bus,
+			NewBaseService(cfg, targets, userReactionRepo, reactionSetRepo, postRepo, commentRepo, emojiRepo),
+		),
+	)
 }
 
-func NewBaseService(userReactionRepo UserReactionRepository) *BaseService {
-	return &BaseService{userReactionRepo: userReactionRepo}
+// NewBaseService returns the service for reactions to the targets of the registry. Posts and comments are looked up for
+// the reaction sets that apply to them, and custom emojis for the sets that have them.
+func NewBaseService(
+	cfg Config,
+	targets *TargetRegistry,
+	userReactionRepo UserReactionRepository,
+	reactionSetRepo ReactionSetRepository,
+	postRepo contents.PostRepository,
+	commentRepo discuss.CommentRepository,
+	emojiRepo emojis.CustomEmojiRepository,
+) *BaseService {
+	defaultEmojis := cfg.DefaultEmojis
+	if len(defaultEmojis) == 0 {
+		defaultEmojis = DefaultEmojis()
+	}
+
+	maxPerUser := cfg.MaxPerUser
+	if maxPerUser < 1 {
+		maxPerUser = 1
+	}
+
+	return &BaseService{
+		defaultEmojis:    defaultEmojis,
+		maxPerUser:       maxPerUser,
+		targets:          targets,
+		userReactionRepo: userReactionRepo,
+		reactionSetRepo:  reactionSetRepo,
+		postRepo:         postRepo,
+		commentRepo:      commentRepo,
+		emojiRepo:        emojiRepo,
+	}
 }
 
 type ReactionOption struct {
@@ -51,17 +135,159 @@ type TargetReactions struct {
 }
 
 func (svc *BaseService) AllowedEmojis(
-	_ context.Context,
+	ctx context.Context,
 	targetType TargetType,
-	_ string,
+	targetID string,
 ) ([]string, error) {
-	if !targetType.IsValid() {
-		return nil, InvalidTargetTypeError{TargetType: targetType}
+	set, err := svc.effectiveSet(ctx, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	return set.Emojis, nil
+}
+
+// effectiveSet returns the narrowest reaction set that applies to the target, or the default one. Custom emojis
+// that were deleted are left out of it, like retired emojis.
+func (svc *BaseService) effectiveSet(
+	ctx context.Context,
+	targetType TargetType,
+	targetID string,
+) (*ReactionSet, error) {
+	_, err := svc.targets.Lookup(targetType)
+	if err != nil {
+		return nil, err
+	}
+
+	postID, communityID, err := svc.targetPost(ctx, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := []struct {
+		scope SetScope
+		id    string
+	}{
+		{scope: SetScopePost, id: postID},
+		{scope: SetScopeCommunity, id: communityID},
+		{scope: SetScopeTargetType, id: string(targetType)},
+		{scope: SetScopeSite, id: ""},
 	}
 
-	return []string{"👍", "👎", "😂"}, nil
+	for _, scope := range scopes {
+		if scope.scope != SetScopeSite && scope.id == "" {
+			continue
+		}
+
+		set, err := svc.reactionSetRepo.Find(ctx, scope.scope, scope.id)
+		if err != nil {
+			if _, ok := errors.AsType[ReactionSetNotFoundError](err); ok {
+				continue
+			}
+
+			return nil, fmt.Errorf("failed to find reaction set: %w", err)
+		}
+
+		set.Emojis, err = svc.existingEmojis(ctx, set.Emojis)
+		if err != nil {
+			return nil, err
+		}
+
+		return set, nil
+	}
+
+	defaultEmojis, err := svc.existingEmojis(ctx, svc.defaultEmojis)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReactionSet{
+		Scope:      SetScopeSite,
+		ScopeID:    "",
+		Emojis:     defaultEmojis,
+		MaxPerUser: svc.maxPerUser,
+		UpdatedAt:  time.Time{},
+	}, nil
 }
 
+// existingEmojis returns the emojis without the custom emojis that do not exist.
+func (svc *BaseService) existingEmojis(ctx context.Context, emojiList []string) ([]string, error) {
+	result := make([]string, 0, len(emojiList))
+
+	for _, emoji := range emojiList {
+		exists, err := svc.customEmojiExists(ctx, emoji)
+		if err != nil {
+			return nil, err
+		}
+
+		if exists {
+			result = append(result, emoji)
+		}
+	}
+
+	return result, nil
+}
+
+// customEmojiExists reports whether the emoji is not a custom emoji reference, or refers to one that exists.
+func (svc *BaseService) customEmojiExists(ctx context.Context, emoji string) (bool, error) {
+	shortcode, ok := emojis.ParseReference(emoji)
+	if !ok {
+		return true, nil
+	}
+
+	_, err := svc.emojiRepo.Find(ctx, shortcode)
+	if err != nil {
+		if _, ok := errors.AsType[emojis.CustomEmojiNotFoundError](err); ok {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to find custom emoji: %w", err)
+	}
+
+	return true, nil
+}
+
+// targetPost returns the post the target is or is on, and the community of the post. Targets that are not found, or
+// are not on posts, have neither, so only the broader reaction sets apply to them.
+func (svc *BaseService) targetPost(
+	ctx context.Context,
+	targetType TargetType,
+	targetID string,
+) (string, string, error) {
+	var postID string
+
+	switch targetType {
+	case TargetTypePost:
+		postID = targetID
+	case TargetTypeComment:
+		comment, err := svc.commentRepo.Find(ctx, targetID)
+		if err != nil {
+			if _, ok := errors.AsType[discuss.CommentNotFoundError](err); ok {
+				return "", "", nil
+			}
+
+			return "", "", fmt.Errorf("failed to find comment: %w", err)
+		}
+
+		postID = comment.PostID
+	default:
+		return "", "", nil
+	}
+
+	post, err := svc.postRepo.Find(ctx, postID)
+	if err != nil {
+		if _, ok := errors.AsType[contents.PostNotFoundError](err); ok {
+			return "", "", nil
+		}
+
+		return "", "", fmt.Errorf("failed to find post: %w", err)
+	}
+
+	return post.ID, post.CommunityID, nil
+}
+
+// ToggleMyReaction adds the reaction of the current user with the emoji, or removes it if they reacted with it.
+// When the set allows a single reaction per user, adding replaces the one they had.
 func (svc *BaseService) ToggleMyReaction(
 	ctx context.Context,
 	targetType TargetType,
@@ -70,38 +296,57 @@ func (svc *BaseService) ToggleMyReaction(
 ) error {
 	userID := authcontext.GetSubject(ctx)
 
-	if !targetType.IsValid() {
-		return InvalidTargetTypeError{TargetType: targetType}
+	spec, err := svc.targets.Lookup(targetType)
+	if err != nil {
+		return err
+	}
+
+	set, err := svc.effectiveSet(ctx, targetType, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to get reaction set: %w", err)
 	}
 
-	allowedEmojis, err := svc.AllowedEmojis(ctx, targetType, targetID)
+	existingReactions, err := svc.userReactionRepo.ListByUserTarget(ctx, targetType, targetID, userID)
 	if err != nil {
-		return fmt.Errorf("failed to get allowed emojis: %w", err)
+		return fmt.Errorf("failed to list existing reactions: %w", err)
+	}
+
+	// Reactions with retired emojis can still be taken back.
+	if slices.ContainsFunc(existingReactions, func(reaction *UserReaction) bool { return reaction.Emoji == emoji }) {
+		err = svc.userReactionRepo.Delete(ctx, targetType, targetID, userID, emoji)
+		if err != nil {
+			return fmt.Errorf("failed to remove reaction: %w", err)
+		}
+
+		return nil
 	}
 
-	if !slices.Contains(allowedEmojis, emoji) {
+	if !slices.Contains(set.Emojis, emoji) {
 		return InvalidEmojiError{
 			TargetType: targetType,
 			TargetID:   targetID,
 			Emoji:      emoji,
-			Allowed:    allowedEmojis,
+			Allowed:    set.Emojis,
 		}
 	}
 
-	existingReaction, err := svc.userReactionRepo.FindByUserTarget(ctx, targetType, targetID, userID)
+	exists, err := spec.Exists(ctx, targetID)
 	if err != nil {
-		if _, ok := errors.AsType[*UserReactionNotFoundError](err); !ok {
-			return fmt.Errorf("failed to get existing reaction: %w", err)
-		}
+		return fmt.Errorf("failed to check target: %w", err)
+	}
+
+	if !exists {
+		return TargetNotFoundError{TargetType: targetType, TargetID: targetID}
 	}
 
-	if existingReaction != nil && existingReaction.Emoji == emoji {
+	switch {
+	case set.MaxPerUser <= 1:
 		err = svc.userReactionRepo.DeleteByUserTarget(ctx, targetType, targetID, userID)
 		if err != nil {
-			return fmt.Errorf("failed to remove reaction: %w", err)
+			return fmt.Errorf("failed to remove reactions: %w", err)
 		}
-
-		return nil
+	case len(existingReactions) >= set.MaxPerUser:
+		return TooManyReactionsError{TargetType: targetType, TargetID: targetID, Max: set.MaxPerUser}
 	}
 
 	userReaction := &UserReaction{
@@ -112,9 +357,9 @@ func (svc *BaseService) ToggleMyReaction(
 		CreatedAt:  time.Now(),
 	}
 
-	err = svc.userReactionRepo.Upsert(ctx, userReaction)
+	err = svc.userReactionRepo.Insert(ctx, userReaction)
 	if err != nil {
-		return fmt.Errorf("failed to set reaction: %w", err)
+		return fmt.Errorf("failed to add reaction: %w", err)
 	}
 
 	return nil
@@ -135,19 +380,17 @@ func (svc *BaseService) GetMyReactions(
 		return nil, fmt.Errorf("failed to get counts by target: %w", err)
 	}
 
-	selectedEmoji := ""
+	selectedEmojis := make(map[string]struct{})
 	currentUserID := authcontext.GetSubject(ctx)
 
 	if currentUserID != "" && currentUserID != authcontext.Anonymous {
-		userReaction, err := svc.userReactionRepo.FindByUserTarget(ctx, targetType, targetID, currentUserID)
+		userReactions, err := svc.userReactionRepo.ListByUserTarget(ctx, targetType, targetID, currentUserID)
 		if err != nil {
-			if _, ok := errors.AsType[*UserReactionNotFoundError](err); !ok {
-				return nil, fmt.Errorf("failed to get user reaction: %w", err)
-			}
+			return nil, fmt.Errorf("failed to list user reactions: %w", err)
 		}
 
-		if userReaction != nil {
-			selectedEmoji = userReaction.Emoji
+		for _, userReaction := range userReactions {
+			selectedEmojis[userReaction.Emoji] = struct{}{}
 		}
 	}
 
@@ -160,7 +403,7 @@ func (svc *BaseService) GetMyReactions(
 		options = append(options, ReactionOption{
 			Emoji:     emoji,
 			Count:     counts[emoji],
-			Selected:  emoji == selectedEmoji,
+			Selected:  isSelected(selectedEmojis, emoji),
 			Available: true,
 		})
 	}
@@ -185,7 +428,7 @@ func (svc *BaseService) GetMyReactions(
 		options = append(options, ReactionOption{
 			Emoji:     emoji,
 			Count:     counts[emoji],
-			Selected:  emoji == selectedEmoji,
+			Selected:  isSelected(selectedEmojis, emoji),
 			Available: false,
 		})
 	}
@@ -196,3 +439,184 @@ func (svc *BaseService) GetMyReactions(
 		Options:    options,
 	}, nil
 }
+
+type ListReactorsRequest struct {
+	TargetType TargetType
+	TargetID   string
+	// Emoji limits the list to reactions with the emoji. Empty means reactions with every emoji.
+	Emoji  string
+	Before *ReactorCursor
+	Limit  int
+}
+
+// ListReactors lists the reactions to the target from newest to oldest. Reactions with retired emojis are listed
+// too, as they are still counted.
+func (svc *BaseService) ListReactors(ctx context.Context, req ListReactorsRequest) ([]*UserReaction, error) {
+	_, err := svc.targets.Lookup(req.TargetType)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultReactorsLimit
+	}
+
+	reactors, err := svc.userReactionRepo.ListByTarget(ctx, &ListReactorsParams{
+		TargetType: req.TargetType,
+		TargetID:   req.TargetID,
+		Emoji:      req.Emoji,
+		Before:     req.Before,
+		Limit:      min(limit, maxReactorsLimit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reactions by target: %w", err)
+	}
+
+	return reactors, nil
+}
+
+func isSelected(selectedEmojis map[string]struct{}, emoji string) bool {
+	_, ok := selectedEmojis[emoji]
+
+	return ok
+}
+
+func (svc *BaseService) ListReactionSets(ctx context.Context) ([]*ReactionSet, error) {
+	sets, err := svc.reactionSetRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reaction sets: %w", err)
+	}
+
+	return sets, nil
+}
+
+func (svc *BaseService) GetReactionSet(ctx context.Context, scope SetScope, scopeID string) (*ReactionSet, error) {
+	set, err := svc.reactionSetRepo.Find(ctx, scope, scopeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find reaction set: %w", err)
+	}
+
+	return set, nil
+}
+
+type SetReactionSetRequest struct {
+	Scope      SetScope
+	ScopeID    string
+	Emojis     []string
+	MaxPerUser int
+}
+
+// SetReactionSet replaces the set of the scope. Emojis left out are retired. Only the author of a post may choose
+// its set.
+func (svc *BaseService) SetReactionSet(ctx context.Context, req SetReactionSetRequest) error {
+	err := svc.checkSetScope(ctx, req.Scope, req.ScopeID)
+	if err != nil {
+		return err
+	}
+
+	err = svc.validateSetEmojis(ctx, req.Emojis)
+	if err != nil {
+		return err
+	}
+
+	if req.MaxPerUser < 1 || req.MaxPerUser > MaxSetSize {
+		return InvalidReactionSetError{Reason: fmt.Sprintf("reactions per user must be between 1 and %d", MaxSetSize)}
+	}
+
+	err = svc.reactionSetRepo.Save(ctx, &ReactionSet{
+		Scope:      req.Scope,
+		ScopeID:    req.ScopeID,
+		Emojis:     req.Emojis,
+		MaxPerUser: req.MaxPerUser,
+		UpdatedAt:  time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save reaction set: %w", err)
+	}
+
+	return nil
+}
+
+func (svc *BaseService) DeleteReactionSet(ctx context.Context, scope SetScope, scopeID string) error {
+	err := svc.checkSetScope(ctx, scope, scopeID)
+	if err != nil {
+		return err
+	}
+
+	_, err = svc.reactionSetRepo.Find(ctx, scope, scopeID)
+	if err != nil {
+		return fmt.Errorf("failed to find reaction set: %w", err)
+	}
+
+	err = svc.reactionSetRepo.Delete(ctx, scope, scopeID)
+	if err != nil {
+		return fmt.Errorf("failed to delete reaction set: %w", err)
+	}
+
+	return nil
+}
+
+// checkSetScope checks that the scope exists and, for posts, that the current user wrote it.
+func (svc *BaseService) checkSetScope(ctx context.Context, scope SetScope, scopeID string) error {
+	switch scope {
+	case SetScopeSite:
+		if scopeID != "" {
+			return InvalidSetScopeError{Scope: scope, ScopeID: scopeID}
+		}
+	case SetScopeTargetType:
+		_, err := svc.targets.Lookup(TargetType(scopeID))
+		if err != nil {
+			return InvalidSetScopeError{Scope: scope, ScopeID: scopeID}
+		}
+	case SetScopeCommunity:
+		if scopeID == "" {
+			return InvalidSetScopeError{Scope: scope, ScopeID: scopeID}
+		}
+	case SetScopePost:
+		post, err := svc.postRepo.Find(ctx, scopeID)
+		if err != nil {
+			return fmt.Errorf("failed to find post: %w", err)
+		}
+
+		if post.AuthorID != authcontext.GetSubject(ctx) {
+			return NotPostAuthorError{PostID: post.ID}
+		}
+	default:
+		return InvalidSetScopeError{Scope: scope, ScopeID: scopeID}
+	}
+
+	return nil
+}
+
+// validateSetEmojis checks the emojis of a set. Custom emojis are listed by their reference, like :wave:.
+func (svc *BaseService) validateSetEmojis(ctx context.Context, emojiList []string) error {
+	if len(emojiList) == 0 {
+		return InvalidReactionSetError{Reason: "no emojis"}
+	}
+
+	if len(emojiList) > MaxSetSize {
+		return InvalidReactionSetError{Reason: fmt.Sprintf("more than %d emojis", MaxSetSize)}
+	}
+
+	for i, emoji := range emojiList {
+		if _, ok := emojis.ParseReference(emoji); ok {
+			exists, err := svc.customEmojiExists(ctx, emoji)
+			if err != nil {
+				return err
+			}
+
+			if !exists {
+				return InvalidReactionSetError{Reason: fmt.Sprintf("%s is not a custom emoji", emoji)}
+			}
+		} else if !isEmoji(emoji) {
+			return InvalidReactionSetError{Reason: fmt.Sprintf("%q is not an emoji", emoji)}
+		}
+
+		if slices.Contains(emojiList[:i], emoji) {
+			return InvalidReactionSetError{Reason: fmt.Sprintf("%q is listed twice", emoji)}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package reactions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/nasermirzaei89/scribble/authentication"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/discuss"
+)
+
+type TargetType string
+
+const (
+	TargetTypePost    TargetType = "post"
+	TargetTypeComment TargetType = "comment"
+	// TargetTypeUser is for reactions to the profiles of users.
+	TargetTypeUser TargetType = "user"
+)
+
+// TargetTypeSpec describes a type of things users react to.
+type TargetTypeSpec struct {
+	Type TargetType
+	// Exists reports whether the target exists. Users cannot react to targets that do not.
+	Exists func(ctx context.Context, targetID string) (bool, error)
+	// Object returns the authorization object of the target, which the actions on its reactions are checked on.
+	Object func(targetID string) string
+}
+
+// TargetRegistry holds the types of things users can react to.
+type TargetRegistry struct {
+	mu    sync.RWMutex
+	specs map[TargetType]TargetTypeSpec
+}
+
+func NewTargetRegistry() *TargetRegistry {
+	return &TargetRegistry{
+		mu:    sync.RWMutex{},
+		specs: make(map[TargetType]TargetTypeSpec),
+	}
+}
+
+// Register adds the type. Each type can be registered once.
+func (registry *TargetRegistry) Register(spec TargetTypeSpec) error {
+	if spec.Type == "" || spec.Exists == nil || spec.Object == nil {
+		return InvalidTargetTypeSpecError{TargetType: spec.Type}
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if _, ok := registry.specs[spec.Type]; ok {
+		return TargetTypeRegisteredError{TargetType: spec.Type}
+	}
+
+	registry.specs[spec.Type] = spec
+
+	return nil
+}
+
+// Lookup returns the spec of the type, or InvalidTargetTypeError if it is not registered.
+func (registry *TargetRegistry) Lookup(targetType TargetType) (TargetTypeSpec, error) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	spec, ok := registry.specs[targetType]
+	if !ok {
+		return TargetTypeSpec{}, InvalidTargetTypeError{TargetType: targetType}
+	}
+
+	return spec, nil
+}
+
+// Object returns the authorization object of the target, or InvalidTargetTypeError if its type is not registered.
+func (registry *TargetRegistry) Object(targetType TargetType, targetID string) (string, error) {
+	spec, err := registry.Lookup(targetType)
+	if err != nil {
+		return "", err
+	}
+
+	return spec.Object(targetID), nil
+}
+
+// TypedObject returns an object mapping that prefixes target IDs with the type, like post:123, so the targets of
+// different types never share an object.
+func TypedObject(targetType TargetType) func(targetID string) string {
+	return func(targetID string) string {
+		return string(targetType) + ":" + targetID
+	}
+}
+
+func PostTargetType(postRepo contents.PostRepository) TargetTypeSpec {
+	return TargetTypeSpec{
+		Type: TargetTypePost,
+		Exists: func(ctx context.Context, targetID string) (bool, error) {
+			_, err := postRepo.Find(ctx, targetID)
+			if err != nil {
+				if _, ok := errors.AsType[contents.PostNotFoundError](err); ok {
+					return false, nil
+				}
+
+				return false, fmt.Errorf("failed to find post: %w", err)
+			}
+
+			return true, nil
+		},
+		Object: TypedObject(TargetTypePost),
+	}
+}
+
+func CommentTargetType(commentRepo discuss.CommentRepository) TargetTypeSpec {
+	return TargetTypeSpec{
+		Type: TargetTypeComment,
+		Exists: func(ctx context.Context, targetID string) (bool, error) {
+			_, err := commentRepo.Find(ctx, targetID)
+			if err != nil {
+				if _, ok := errors.AsType[discuss.CommentNotFoundError](err); ok {
+					return false, nil
+				}
+
+				return false, fmt.Errorf("failed to find comment: %w", err)
+			}
+
+			return true, nil
+		},
+		Object: TypedObject(TargetTypeComment),
+	}
+}
+
+func UserTargetType(userRepo authentication.UserRepository) TargetTypeSpec {
+	return TargetTypeSpec{
+		Type: TargetTypeUser,
+		Exists: func(ctx context.Context, targetID string) (bool, error) {
+			_, err := userRepo.Find(ctx, targetID)
+			if err != nil {
+				if _, ok := errors.AsType[*authentication.UserNotFoundError](err); ok {
+					return false, nil
+				}
+
+				return false, fmt.Errorf("failed to find user: %w", err)
+			}
+
+			return true, nil
+		},
+		Object: TypedObject(TargetTypeUser),
+	}
+}
+
+type TargetNotFoundError struct {
+	TargetType TargetType
+	TargetID   string
+}
+
+func (err TargetNotFoundError) Error() string {
+	return fmt.Sprintf("%s %q not found", err.TargetType, err.TargetID)
+}
+
+type InvalidTargetTypeSpecError struct {
+	TargetType TargetType
+}
+
+func (err InvalidTargetTypeSpecError) Error() string {
+	return fmt.Sprintf("target type %q needs a name, an existence check and an object mapping", err.TargetType)
+}
+
+type TargetTypeRegisteredError struct {
+	TargetType TargetType
+}
+
+func (err TargetTypeRegisteredError) Error() string {
+	return fmt.Sprintf("target type %q is already registered", err.TargetType)
+}
@@ -0,0 +1,137 @@
+package reactions_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/authentication"
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/database/sqlite3"
+	"github.com/nasermirzaei89/scribble/reactions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTargetRegistry(t *testing.T, db *sql.DB) *reactions.TargetRegistry {
+	t.Helper()
+
+	targets := reactions.NewTargetRegistry()
+
+	for _, spec := range []reactions.TargetTypeSpec{
+		reactions.PostTargetType(sqlite3.NewPostRepository(db)),
+		reactions.CommentTargetType(sqlite3.NewCommentRepository(db)),
+		reactions.UserTargetType(sqlite3.NewUserRepository(db)),
+	} {
+		err := targets.Register(spec)
+		require.NoError(t, err)
+	}
+
+	return targets
+}
+
+func TestTargetRegistry(t *testing.T) {
+	exists := func(context.Context, string) (bool, error) { return true, nil }
+
+	t.Run("register and look up", func(t *testing.T) {
+		targets := reactions.NewTargetRegistry()
+
+		err := targets.Register(reactions.TargetTypeSpec{
+			Type:   "photo",
+			Exists: exists,
+			Object: reactions.TypedObject("photo"),
+		})
+		require.NoError(t, err)
+
+		object, err := targets.Object("photo", "p1")
+		require.NoError(t, err)
+		assert.Equal(t, "photo:p1", object)
+
+		_, err = targets.Lookup(reactions.TargetTypePost)
+		require.ErrorAs(t, err, new(reactions.InvalidTargetTypeError))
+	})
+
+	t.Run("types are registered once", func(t *testing.T) {
+		targets := reactions.NewTargetRegistry()
+		spec := reactions.TargetTypeSpec{Type: "photo", Exists: exists, Object: reactions.TypedObject("photo")}
+
+		err := targets.Register(spec)
+		require.NoError(t, err)
+
+		err = targets.Register(spec)
+		require.ErrorAs(t, err, new(reactions.TargetTypeRegisteredError))
+	})
+
+	t.Run("incomplete specs are rejected", func(t *testing.T) {
+		targets := reactions.NewTargetRegistry()
+
+		err := targets.Register(reactions.TargetTypeSpec{Type: "photo", Exists: exists, Object: nil})
+		require.ErrorAs(t, err, new(reactions.InvalidTargetTypeSpecError))
+
+		err = targets.Register(reactions.TargetTypeSpec{Type: "", Exists: exists, Object: reactions.TypedObject("")})
+		require.ErrorAs(t, err, new(reactions.InvalidTargetTypeSpecError))
+	})
+}
+
+func TestReactionTargets(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sqlite3.NewDB(ctx, "file:TestReactionTargets?mode=memory&cache=shared")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := db.Close()
+		require.NoError(t, err)
+	})
+
+	err = sqlite3.MigrateUp(ctx, db)
+	require.NoError(t, err)
+
+	svc := reactions.NewBaseService(
+		reactions.Config{DefaultEmojis: []string{"👍", "👎"}, MaxPerUser: 1},
+		newTargetRegistry(t, db),
+		sqlite3.NewUserReactionRepository(db),
+		sqlite3.NewReactionSetRepository(db),
+		sqlite3.NewPostRepository(db),
+		sqlite3.NewCommentRepository(db),
+		sqlite3.NewCustomEmojiRepository(db),
+	)
+
+	err = sqlite3.NewUserRepository(db).Insert(ctx, &authentication.User{
+		ID:           "alice",
+		Username:     "alice",
+		PasswordHash: "hash",
+		RegisteredAt: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	bobCtx := authcontext.WithSubject(ctx, "bob")
+
+	t.Run("users can react to profiles", func(t *testing.T) {
+		err := svc.ToggleMyReaction(bobCtx, reactions.TargetTypeUser, "alice", "👍")
+		require.NoError(t, err)
+
+		targetReactions, err := svc.GetMyReactions(bobCtx, reactions.TargetTypeUser, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, "👍", targetReactions.Options[0].Emoji)
+		assert.Equal(t, 1, targetReactions.Options[0].Count)
+		assert.True(t, targetReactions.Options[0].Selected)
+	})
+
+	t.Run("missing targets are rejected", func(t *testing.T) {
+		for _, targetType := range []reactions.TargetType{
+			reactions.TargetTypePost,
+			reactions.TargetTypeComment,
+			reactions.TargetTypeUser,
+		} {
+			err := svc.ToggleMyReaction(bobCtx, targetType, "missing", "👍")
+			require.ErrorAs(t, err, new(reactions.TargetNotFoundError))
+		}
+	})
+
+	t.Run("unregistered types are rejected", func(t *testing.T) {
+		err := svc.ToggleMyReaction(bobCtx, "photo", "p1", "👍")
+		require.ErrorAs(t, err, new(reactions.InvalidTargetTypeError))
+	})
+}
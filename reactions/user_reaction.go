@@ -6,22 +6,6 @@ import (
 	"time"
 )
 
-type TargetType string
-
-const (
-	TargetTypePost    TargetType = "post"
-	TargetTypeComment TargetType = "comment"
-)
-
-func (targetType TargetType) IsValid() bool {
-	switch targetType {
-	case TargetTypePost, TargetTypeComment:
-		return true
-	default:
-		return false
-	}
-}
-
 type UserReaction struct {
 	TargetType TargetType
 	TargetID   string
@@ -31,30 +15,38 @@ type UserReaction struct {
 }
 
 type UserReactionRepository interface {
-	FindByUserTarget(
+	// ListByUserTarget lists the reactions of the user on the target, the oldest first.
+	ListByUserTarget(
 		ctx context.Context,
 		targetType TargetType,
 		targetID string,
 		userID string,
-	) (reaction *UserReaction, err error)
-	Upsert(ctx context.Context, reaction *UserReaction) (err error)
+	) (reactions []*UserReaction, err error)
+	Insert(ctx context.Context, reaction *UserReaction) (err error)
+	// Delete removes the reaction of the user on the target with the emoji.
+	Delete(ctx context.Context, targetType TargetType, targetID string, userID string, emoji string) (err error)
+	// DeleteByUserTarget removes all reactions of the user on the target.
 	DeleteByUserTarget(ctx context.Context, targetType TargetType, targetID string, userID string) (err error)
 	CountByTarget(ctx context.Context, targetType TargetType, targetID string) (counts map[string]int, err error)
+	ListByTarget(ctx context.Context, params *ListReactorsParams) (reactions []*UserReaction, err error)
 }
 
-type UserReactionNotFoundError struct {
-	TargetType TargetType
-	TargetID   string
-	UserID     string
+// ReactorCursor is the position of a reaction in the list of reactors, which is ordered from newest to oldest with
+// the user ID breaking ties.
+type ReactorCursor struct {
+	CreatedAt time.Time
+	UserID    string
 }
 
-func (err UserReactionNotFoundError) Error() string {
-	return fmt.Sprintf(
-		"reaction for user %q on %s:%q not found",
-		err.UserID,
-		err.TargetType,
-		err.TargetID,
-	)
+type ListReactorsParams struct {
+	TargetType TargetType
+	TargetID   string
+	// Emoji limits the list to reactions with the emoji. Empty means reactions with every emoji.
+	Emoji string
+	// Before limits the list to reactions after the cursor in list order, that is, older ones.
+	Before *ReactorCursor
+	// Limit is the maximum number of reactions to return. Zero means no limit.
+	Limit int
 }
 
 type InvalidTargetTypeError struct {
@@ -81,3 +73,13 @@ func (err InvalidEmojiError) Error() string {
 		err.Allowed,
 	)
 }
+
+type TooManyReactionsError struct {
+	TargetType TargetType
+	TargetID   string
+	Max        int
+}
+
+func (err TooManyReactionsError) Error() string {
+	return fmt.Sprintf("at most %d reactions are allowed on %s:%q", err.Max, err.TargetType, err.TargetID)
+}
@@ -0,0 +1,58 @@
+package reputation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/nasermirzaei89/scribble/authorization"
+)
+
+const ServiceName = "github.com/nasermirzaei89/scribble/reputation"
+
+// Service reads the reputations of users. Reputations are kept up to date by the Scorer.
+type Service interface {
+	GetReputation(ctx context.Context, userID string) (*Reputation, error)
+}
+
+type BaseService struct {
+	reputationRepo ReputationRepository
+	scorer         *Scorer
+}
+
+var _ Service = (*BaseService)(nil)
+
+func NewService( //nolint:ireturn
+	reputationRepo ReputationRepository,
+	scorer *Scorer,
+	authzClient *authorization.Client,
+) Service {
+	return NewAuthorizationMiddleware(authzClient, NewBaseService(reputationRepo, scorer))
+}
+
+func NewBaseService(reputationRepo ReputationRepository, scorer *Scorer) *BaseService {
+	return &BaseService{
+		reputationRepo: reputationRepo,
+		scorer:         scorer,
+	}
+}
+
+// GetReputation returns the reputation of the user. Users that were not scored yet, like those from before
+// reputations, are scored on the first read.
+func (svc *BaseService) GetReputation(ctx context.Context, userID string) (*Reputation, error) {
+	reputation, err := svc.reputationRepo.Find(ctx, userID)
+	if err == nil {
+		return reputation, nil
+	}
+
+	if _, ok := errors.AsType[ReputationNotFoundError](err); !ok {
+		return nil, fmt.Errorf("failed to find reputation: %w", err)
+	}
+
+	reputation, err = svc.scorer.Recompute(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recompute reputation: %w", err)
+	}
+
+	return reputation, nil
+}
@@ -0,0 +1,139 @@
+package reputation
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TrustedGroup is the authorization group of users whose score reached the trusted threshold.
+const TrustedGroup = "system:trusted"
+
+// Reputation is how much a user is trusted by the community, scored from the reactions others gave to their posts,
+// comments and profile, how much they wrote and how long they have been around.
+type Reputation struct {
+	UserID string
+	// ReactionPoints is the sum of the weights of the reactions received.
+	ReactionPoints int
+	Posts          int
+	Comments       int
+	Score          int
+	// Trusted reports whether the user is in the TrustedGroup.
+	Trusted   bool
+	UpdatedAt time.Time
+}
+
+// DefaultEmojiWeights returns the weights of the emojis that are not worth the default weight.
+func DefaultEmojiWeights() map[string]int {
+	return map[string]int{"👎": -1}
+}
+
+type Config struct {
+	// EmojiWeights are the points reactions with each emoji are worth, which can be negative. Defaults to
+	// DefaultEmojiWeights.
+	EmojiWeights map[string]int
+	// DefaultEmojiWeight is what reactions with emojis missing from EmojiWeights are worth.
+	DefaultEmojiWeight int
+	PostWeight         int
+	CommentWeight      int
+	// AgePointDays is how many days of account age are worth a point. Zero disables points for age.
+	AgePointDays int
+	// MaxAgePoints caps the points for age, so old accounts do not outscore active ones.
+	MaxAgePoints int
+	// TrustedThreshold is the score from which users are added to the TrustedGroup. Zero disables the group.
+	TrustedThreshold int
+}
+
+// DefaultConfig returns the weights used when they are not configured.
+func DefaultConfig() Config {
+	return Config{
+		EmojiWeights:       DefaultEmojiWeights(),
+		DefaultEmojiWeight: 1,
+		PostWeight:         2,
+		CommentWeight:      1,
+		AgePointDays:       30,
+		MaxAgePoints:       12,
+		TrustedThreshold:   50,
+	}
+}
+
+// ParseEmojiWeights parses weights written like 👎=-1, one per entry.
+func ParseEmojiWeights(entries []string) (map[string]int, error) {
+	weights := make(map[string]int, len(entries))
+
+	for _, entry := range entries {
+		emoji, weight, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || emoji == "" {
+			return nil, InvalidEmojiWeightError{Entry: entry}
+		}
+
+		value, err := strconv.Atoi(weight)
+		if err != nil {
+			return nil, InvalidEmojiWeightError{Entry: entry}
+		}
+
+		weights[emoji] = value
+	}
+
+	return weights, nil
+}
+
+// score returns the reaction points and the score of a user with the activity.
+func (cfg Config) score(reactionCounts map[string]int, posts, comments int, age time.Duration) (int, int) {
+	reactionPoints := 0
+
+	for emoji, count := range reactionCounts {
+		weight, ok := cfg.EmojiWeights[emoji]
+		if !ok {
+			weight = cfg.DefaultEmojiWeight
+		}
+
+		reactionPoints += weight * count
+	}
+
+	agePoints := 0
+
+	if cfg.AgePointDays > 0 && age > 0 {
+		agePoints = min(int(age/(time.Duration(cfg.AgePointDays)*24*time.Hour)), cfg.MaxAgePoints)
+	}
+
+	score := reactionPoints + posts*cfg.PostWeight + comments*cfg.CommentWeight + agePoints
+
+	return reactionPoints, score
+}
+
+// isTrusted reports whether users with the score belong in the TrustedGroup.
+func (cfg Config) isTrusted(score int) bool {
+	return cfg.TrustedThreshold > 0 && score >= cfg.TrustedThreshold
+}
+
+type ReputationRepository interface {
+	Find(ctx context.Context, userID string) (reputation *Reputation, err error)
+	// Save inserts the reputation of the user or replaces it.
+	Save(ctx context.Context, reputation *Reputation) (err error)
+	// CountReactionsReceived returns how many reactions with each emoji others gave to the posts, comments and
+	// profile of the user. Reactions of users to their own things are not counted.
+	CountReactionsReceived(ctx context.Context, userID string) (counts map[string]int, err error)
+	// CountActivity returns how many posts and comments the user wrote.
+	CountActivity(ctx context.Context, userID string) (posts int, comments int, err error)
+	// ListUserIDs returns the IDs of all users, scored or not.
+	ListUserIDs(ctx context.Context) (userIDs []string, err error)
+}
+
+type ReputationNotFoundError struct {
+	UserID string
+}
+
+func (err ReputationNotFoundError) Error() string {
+	return fmt.Sprintf("reputation of user %q not found", err.UserID)
+}
+
+type InvalidEmojiWeightError struct {
+	Entry string
+}
+
+func (err InvalidEmojiWeightError) Error() string {
+	return fmt.Sprintf("invalid emoji weight %q, expected an emoji and a whole number like 👎=-1", err.Entry)
+}
@@ -0,0 +1,49 @@
+package reputation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigScore(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultConfig()
+	day := 24 * time.Hour
+
+	reactionPoints, score := cfg.score(map[string]int{"👍": 3, "👎": 2}, 2, 5, 0)
+	assert.Equal(t, 1, reactionPoints)
+	assert.Equal(t, 1+2*2+5, score)
+
+	_, score = cfg.score(nil, 0, 0, 95*day)
+	assert.Equal(t, 3, score, "a point per 30 days")
+
+	_, score = cfg.score(nil, 0, 0, 10*365*day)
+	assert.Equal(t, cfg.MaxAgePoints, score, "age points are capped")
+
+	cfg.AgePointDays = 0
+	_, score = cfg.score(nil, 0, 0, 95*day)
+	assert.Equal(t, 0, score)
+
+	assert.True(t, cfg.isTrusted(cfg.TrustedThreshold))
+	assert.False(t, cfg.isTrusted(cfg.TrustedThreshold-1))
+
+	cfg.TrustedThreshold = 0
+	assert.False(t, cfg.isTrusted(1000))
+}
+
+func TestParseEmojiWeights(t *testing.T) {
+	t.Parallel()
+
+	weights, err := ParseEmojiWeights([]string{"👎=-1", " 🎉=3"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"👎": -1, "🎉": 3}, weights)
+
+	for _, entry := range []string{"👎", "=1", "👎=much"} {
+		_, err := ParseEmojiWeights([]string{entry})
+		require.ErrorAs(t, err, &InvalidEmojiWeightError{}, entry)
+	}
+}
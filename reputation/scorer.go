@@ -0,0 +1,200 @@
+package reputation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/authentication"
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/discuss"
+	"github.com/nasermirzaei89/scribble/events"
+	"github.com/nasermirzaei89/scribble/reactions"
+)
+
+// Scorer keeps the reputations of users up to date. Each event recomputes the score of the one user it changes, and
+// moves them in or out of the TrustedGroup when the score crosses the threshold.
+type Scorer struct {
+	cfg            Config
+	reputationRepo ReputationRepository
+	userRepo       authentication.UserRepository
+	postRepo       contents.PostRepository
+	commentRepo    discuss.CommentRepository
+	authzClient    *authorization.Client
+	now            func() time.Time
+}
+
+func NewScorer(
+	cfg Config,
+	reputationRepo ReputationRepository,
+	userRepo authentication.UserRepository,
+	postRepo contents.PostRepository,
+	commentRepo discuss.CommentRepository,
+	authzClient *authorization.Client,
+) *Scorer {
+	if cfg.EmojiWeights == nil {
+		cfg.EmojiWeights = DefaultEmojiWeights()
+	}
+
+	return &Scorer{
+		cfg:            cfg,
+		reputationRepo: reputationRepo,
+		userRepo:       userRepo,
+		postRepo:       postRepo,
+		commentRepo:    commentRepo,
+		authzClient:    authzClient,
+		now:            time.Now,
+	}
+}
+
+func (scorer *Scorer) Subscribe(bus *events.Bus) {
+	events.SubscribeAsync(bus, scorer.handleUserRegistered)
+	events.SubscribeAsync(bus, scorer.handlePostCreated)
+	events.SubscribeAsync(bus, scorer.handleCommentCreated)
+	events.SubscribeAsync(bus, scorer.handleReactionToggled)
+}
+
+func (scorer *Scorer) handleUserRegistered(ctx context.Context, event events.UserRegistered) error {
+	return scorer.update(ctx, event.UserID)
+}
+
+func (scorer *Scorer) handlePostCreated(ctx context.Context, event events.PostCreated) error {
+	return scorer.update(ctx, event.AuthorID)
+}
+
+func (scorer *Scorer) handleCommentCreated(ctx context.Context, event events.CommentCreated) error {
+	return scorer.update(ctx, event.AuthorID)
+}
+
+// handleReactionToggled rescores the user the reaction was given to, the author of the post or comment or the user
+// of the profile.
+func (scorer *Scorer) handleReactionToggled(ctx context.Context, event events.ReactionToggled) error {
+	switch reactions.TargetType(event.TargetType) {
+	case reactions.TargetTypePost:
+		post, err := scorer.postRepo.Find(ctx, event.TargetID)
+		if err != nil {
+			if _, ok := errors.AsType[contents.PostNotFoundError](err); ok {
+				return nil
+			}
+
+			return fmt.Errorf("failed to find post: %w", err)
+		}
+
+		return scorer.update(ctx, post.AuthorID)
+	case reactions.TargetTypeComment:
+		comment, err := scorer.commentRepo.Find(ctx, event.TargetID)
+		if err != nil {
+			if _, ok := errors.AsType[discuss.CommentNotFoundError](err); ok {
+				return nil
+			}
+
+			return fmt.Errorf("failed to find comment: %w", err)
+		}
+
+		return scorer.update(ctx, comment.AuthorID)
+	case reactions.TargetTypeUser:
+		return scorer.update(ctx, event.TargetID)
+	default:
+		return nil
+	}
+}
+
+// update recomputes the reputation of the user, if the user still exists.
+func (scorer *Scorer) update(ctx context.Context, userID string) error {
+	_, err := scorer.Recompute(ctx, userID)
+	if err != nil {
+		if _, ok := errors.AsType[*authentication.UserNotFoundError](err); ok {
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// Recompute scores the user from scratch and saves the reputation.
+func (scorer *Scorer) Recompute(ctx context.Context, userID string) (*Reputation, error) {
+	user, err := scorer.userRepo.Find(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	reactionCounts, err := scorer.reputationRepo.CountReactionsReceived(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count reactions received: %w", err)
+	}
+
+	posts, comments, err := scorer.reputationRepo.CountActivity(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count activity: %w", err)
+	}
+
+	now := scorer.now()
+	reactionPoints, score := scorer.cfg.score(reactionCounts, posts, comments, now.Sub(user.RegisteredAt))
+
+	reputation := &Reputation{
+		UserID:         userID,
+		ReactionPoints: reactionPoints,
+		Posts:          posts,
+		Comments:       comments,
+		Score:          score,
+		Trusted:        scorer.cfg.isTrusted(score),
+		UpdatedAt:      now,
+	}
+
+	wasTrusted := false
+
+	previous, err := scorer.reputationRepo.Find(ctx, userID)
+	if err != nil {
+		if _, ok := errors.AsType[ReputationNotFoundError](err); !ok {
+			return nil, fmt.Errorf("failed to find reputation: %w", err)
+		}
+	} else {
+		wasTrusted = previous.Trusted
+	}
+
+	// The group is changed before the reputation is saved, so a failure is retried by the next recompute. The change
+	// is made by the scorer, whoever caused it.
+	serviceCtx := authcontext.WithServiceSubject(ctx, ServiceName)
+
+	switch {
+	case reputation.Trusted && !wasTrusted:
+		err = scorer.authzClient.AddToGroup(serviceCtx, userID, TrustedGroup)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add user to trusted group: %w", err)
+		}
+	case !reputation.Trusted && wasTrusted:
+		err = scorer.authzClient.RemoveFromGroup(serviceCtx, userID, TrustedGroup)
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove user from trusted group: %w", err)
+		}
+	}
+
+	err = scorer.reputationRepo.Save(ctx, reputation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save reputation: %w", err)
+	}
+
+	return reputation, nil
+}
+
+// RecomputeAll rescores every user, like after the weights changed, and returns how many were scored.
+func (scorer *Scorer) RecomputeAll(ctx context.Context) (int, error) {
+	userIDs, err := scorer.reputationRepo.ListUserIDs(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list user ids: %w", err)
+	}
+
+	for _, userID := range userIDs {
+		_, err := scorer.Recompute(ctx, userID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to recompute reputation of user %q: %w", userID, err)
+		}
+	}
+
+	return len(userIDs), nil
+}
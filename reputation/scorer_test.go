@@ -0,0 +1,194 @@
+package reputation_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	fileadapter "github.com/casbin/casbin/v3/persist/file-adapter"
+	"github.com/nasermirzaei89/scribble/authentication"
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/authorization/casbin"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/database/sqlite3"
+	"github.com/nasermirzaei89/scribble/events"
+	"github.com/nasermirzaei89/scribble/reactions"
+	"github.com/nasermirzaei89/scribble/reputation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAuthorizationClient(t *testing.T, policy string) *authorization.Client {
+	t.Helper()
+
+	tmpFile := filepath.Join(t.TempDir(), "policy.csv")
+
+	err := os.WriteFile(tmpFile, []byte(policy), 0o600)
+	require.NoError(t, err)
+
+	provider, err := casbin.NewAuthorizationProvider(fileadapter.NewAdapter(tmpFile))
+	require.NoError(t, err)
+
+	authzSvc, err := authorization.NewService(provider)
+	require.NoError(t, err)
+
+	return authorization.NewClient(authzSvc)
+}
+
+func TestScorer(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sqlite3.NewDB(ctx, "file:TestScorer?mode=memory&cache=shared")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := db.Close()
+		require.NoError(t, err)
+	})
+
+	err = sqlite3.MigrateUp(ctx, db)
+	require.NoError(t, err)
+
+	userRepo := sqlite3.NewUserRepository(db)
+	postRepo := sqlite3.NewPostRepository(db)
+	commentRepo := sqlite3.NewCommentRepository(db)
+	reputationRepo := sqlite3.NewReputationRepository(db)
+
+	client := newAuthorizationClient(t, "p, system:trusted, test, -, moderate\n")
+
+	bus := events.NewBus(sqlite3.NewEventOutboxRepository(db), sqlite3.NewTransactor(db))
+
+	scorer := reputation.NewScorer(
+		reputation.Config{
+			EmojiWeights:       map[string]int{"👎": -3},
+			DefaultEmojiWeight: 2,
+			PostWeight:         1,
+			CommentWeight:      0,
+			AgePointDays:       0,
+			MaxAgePoints:       0,
+			TrustedThreshold:   5,
+		},
+		reputationRepo,
+		userRepo,
+		postRepo,
+		commentRepo,
+		client,
+	)
+	scorer.Subscribe(bus)
+
+	svc := reputation.NewBaseService(reputationRepo, scorer)
+
+	for _, username := range []string{"alice", "bob", "carol"} {
+		err := userRepo.Insert(ctx, &authentication.User{
+			ID:           username,
+			Username:     username,
+			PasswordHash: "hash",
+			RegisteredAt: time.Now(),
+		})
+		require.NoError(t, err)
+	}
+
+	targets := reactions.NewTargetRegistry()
+
+	for _, spec := range []reactions.TargetTypeSpec{
+		reactions.PostTargetType(postRepo),
+		reactions.CommentTargetType(commentRepo),
+		reactions.UserTargetType(userRepo),
+	} {
+		err := targets.Register(spec)
+		require.NoError(t, err)
+	}
+
+	contentsSvc := contents.NewEventsMiddleware(bus, contents.NewBaseService(postRepo))
+	reactionsSvc := reactions.NewEventsMiddleware(bus, reactions.NewBaseService(
+		reactions.Config{DefaultEmojis: []string{"👍", "👎", "😂"}, MaxPerUser: 1},
+		targets,
+		sqlite3.NewUserReactionRepository(db),
+		sqlite3.NewReactionSetRepository(db),
+		postRepo,
+		commentRepo,
+		sqlite3.NewCustomEmojiRepository(db),
+	))
+
+	post, err := contentsSvc.CreatePost(ctx, contents.CreatePostRequest{
+		AuthorID:    "alice",
+		CommunityID: "",
+		Content:     "post",
+	})
+	require.NoError(t, err)
+
+	react := func(userID string, targetType reactions.TargetType, targetID, emoji string) {
+		t.Helper()
+
+		err := reactionsSvc.ToggleMyReaction(authcontext.WithSubject(ctx, userID), targetType, targetID, emoji)
+		require.NoError(t, err)
+
+		_, err = bus.ProcessOutbox(ctx)
+		require.NoError(t, err)
+	}
+
+	isTrusted := func(userID string) bool {
+		return client.Can(ctx, userID, "test", "", "moderate")
+	}
+
+	t.Run("activity is scored", func(t *testing.T) {
+		_, err := bus.ProcessOutbox(ctx)
+		require.NoError(t, err)
+
+		rep, err := svc.GetReputation(ctx, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, 1, rep.Posts)
+		assert.Equal(t, 1, rep.Score)
+		assert.False(t, rep.Trusted)
+	})
+
+	t.Run("reactions received are weighted", func(t *testing.T) {
+		react("bob", reactions.TargetTypePost, post.ID, "👍")
+		react("carol", reactions.TargetTypeUser, "alice", "😂")
+		// Reactions to one's own things do not count.
+		react("alice", reactions.TargetTypePost, post.ID, "😂")
+
+		rep, err := svc.GetReputation(ctx, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, 4, rep.ReactionPoints)
+		assert.Equal(t, 5, rep.Score)
+		assert.True(t, rep.Trusted)
+		assert.True(t, isTrusted("alice"))
+	})
+
+	t.Run("falling below the threshold takes trust back", func(t *testing.T) {
+		// Replacing the reaction takes the previous one back.
+		react("bob", reactions.TargetTypePost, post.ID, "👎")
+
+		rep, err := svc.GetReputation(ctx, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, -1, rep.ReactionPoints)
+		assert.Equal(t, 0, rep.Score)
+		assert.False(t, rep.Trusted)
+		assert.False(t, isTrusted("alice"))
+	})
+
+	t.Run("unscored users are scored on read", func(t *testing.T) {
+		_, err := reputationRepo.Find(ctx, "bob")
+		require.ErrorAs(t, err, &reputation.ReputationNotFoundError{})
+
+		rep, err := svc.GetReputation(ctx, "bob")
+		require.NoError(t, err)
+		assert.Equal(t, 0, rep.Score)
+
+		_, err = reputationRepo.Find(ctx, "bob")
+		require.NoError(t, err)
+	})
+
+	t.Run("recompute all", func(t *testing.T) {
+		count, err := scorer.RecomputeAll(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+
+		_, err = reputationRepo.Find(ctx, "carol")
+		require.NoError(t, err)
+	})
+}
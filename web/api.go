@@ -0,0 +1,469 @@
+package web
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/csrf"
+	"github.com/nasermirzaei89/scribble/authentication"
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/discuss"
+	"github.com/nasermirzaei89/scribble/reactions"
+)
+
+const (
+	apiPathPrefix = "/api/"
+
+	apiDefaultPageSize = 20
+	apiMaxPageSize     = 100
+	apiMaxBodySize     = 1 << 20
+
+	bearerPrefix = "Bearer "
+)
+
+func (h *Handler) registerAPIRoutes() {
+	h.mux.HandleFunc("/api/", h.HandleAPINotFound)
+
+	operations := h.apiOperations()
+
+	for _, op := range operations {
+		h.mux.Handle(op.Method+" "+op.Path, op.Handler)
+	}
+
+	h.apiSpec = newAPISpec(operations, h.sessionName)
+
+	h.mux.Handle("GET /api/openapi.json", h.HandleOpenAPISpec())
+	h.mux.Handle("GET /api/docs", h.HandleAPIDocsPage())
+}
+
+func isAPIRequest(r *http.Request) bool {
+	return strings.HasPrefix(r.URL.Path, apiPathPrefix)
+}
+
+// bearerToken returns the session token of an API request authenticated with an Authorization: Bearer header.
+func bearerToken(r *http.Request) (string, bool) {
+	if !isAPIRequest(r) {
+		return "", false
+	}
+
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, bearerPrefix) {
+		return "", false
+	}
+
+	token := strings.TrimSpace(strings.TrimPrefix(header, bearerPrefix))
+
+	return token, token != ""
+}
+
+// apiAuthMiddleware authenticates API requests that carry a bearer token and exempts API requests that cannot ride on
+// the browser session from CSRF protection. It must wrap the CSRF middleware.
+//
+// Cookie authenticated API requests still need the CSRF token, sent in the X-CSRF-Token header, since browsers attach
+// the session cookie to forged requests. Bearer tokens are never attached automatically, so requests carrying one, or
+// carrying no session cookie at all, have nothing to forge.
+func (h *Handler) apiAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isAPIRequest(r) {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		token, ok := bearerToken(r)
+		if !ok {
+			if _, err := r.Cookie(h.sessionName); err != nil {
+				r = csrf.UnsafeSkipCheck(r)
+			}
+
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		session, err := h.authSvc.GetSession(r.Context(), token)
+		if err != nil {
+			_, notFound := errors.AsType[*authentication.SessionNotFoundError](err)
+			_, expired := errors.AsType[*authentication.SessionExpiredError](err)
+
+			if notFound || expired {
+				writeAPIError(w, http.StatusUnauthorized, "invalid_token", "Invalid or expired token")
+
+				return
+			}
+
+			slog.ErrorContext(r.Context(), "error on getting session", "error", err)
+			writeAPIError(w, http.StatusInternalServerError, "internal_error", "Internal Server Error")
+
+			return
+		}
+
+		user, err := h.authSvc.GetUser(r.Context(), session.UserID)
+		if err != nil {
+			if _, ok := errors.AsType[*authentication.UserNotFoundError](err); ok {
+				writeAPIError(w, http.StatusUnauthorized, "invalid_token", "Invalid or expired token")
+
+				return
+			}
+
+			slog.ErrorContext(r.Context(), "error retrieving user", "error", err)
+			writeAPIError(w, http.StatusInternalServerError, "internal_error", "Internal Server Error")
+
+			return
+		}
+
+		ctx := authcontext.WithSessionID(r.Context(), session.ID)
+		ctx = authcontext.WithSubject(ctx, user.ID)
+
+		next.ServeHTTP(w, csrf.UnsafeSkipCheck(r.WithContext(ctx)))
+	})
+}
+
+// APIErrorBody is the body of every failed API response.
+type APIErrorBody struct {
+	Error APIError `json:"error"`
+}
+
+type APIError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// APIPage is a page of a cursor paginated list. NextCursor is empty on the last page.
+type APIPage[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+func writeAPIJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+
+	err := json.NewEncoder(w).Encode(body)
+	if err != nil {
+		slog.Error("failed to encode api response", "error", err)
+	}
+}
+
+func writeAPIError(w http.ResponseWriter, status int, code, message string) {
+	writeAPIJSON(w, status, APIErrorBody{Error: APIError{Code: code, Message: message}})
+}
+
+type InvalidAPIRequestError struct {
+	Reason string
+}
+
+func (err InvalidAPIRequestError) Error() string {
+	return "invalid api request: " + err.Reason
+}
+
+type InvalidCursorError struct {
+	Cursor string
+}
+
+func (err InvalidCursorError) Error() string {
+	return fmt.Sprintf("invalid cursor: %q", err.Cursor)
+}
+
+// handleAPIError maps typed service errors to JSON error bodies.
+func handleAPIError(w http.ResponseWriter, r *http.Request, err error) {
+	if _, ok := errors.AsType[*authorization.AccessDeniedError](err); ok {
+		if !isAuthenticatedRequest(r) {
+			writeAPIError(w, http.StatusUnauthorized, "unauthenticated", "Authentication required")
+
+			return
+		}
+
+		writeAPIError(w, http.StatusForbidden, "forbidden", "Forbidden")
+
+		return
+	}
+
+	if _, ok := errors.AsType[*authentication.UserNotFoundError](err); ok {
+		writeAPIError(w, http.StatusNotFound, "user_not_found", "User not found")
+
+		return
+	}
+
+	if _, ok := errors.AsType[*authentication.UserAlreadyExistsError](err); ok {
+		writeAPIError(w, http.StatusConflict, "user_already_exists", "Username already exists")
+
+		return
+	}
+
+	if invalidUsernameErr, ok := errors.AsType[*authentication.InvalidUsernameError](err); ok {
+		writeAPIError(w, http.StatusBadRequest, "invalid_username", "Username "+invalidUsernameErr.Reason)
+
+		return
+	}
+
+	if invalidRequestErr, ok := errors.AsType[InvalidAPIRequestError](err); ok {
+		writeAPIError(w, http.StatusBadRequest, "invalid_request", invalidRequestErr.Reason)
+
+		return
+	}
+
+	if _, ok := errors.AsType[InvalidCursorError](err); ok {
+		writeAPIError(w, http.StatusBadRequest, "invalid_cursor", "Invalid cursor")
+
+		return
+	}
+
+	if _, ok := errors.AsType[contents.PostNotFoundError](err); ok {
+		writeAPIError(w, http.StatusNotFound, "post_not_found", "Post not found")
+
+		return
+	}
+
+	if _, ok := errors.AsType[contents.InvalidPostSortError](err); ok {
+		writeAPIError(w, http.StatusBadRequest, "invalid_sort", "Invalid sort")
+
+		return
+	}
+
+	if _, ok := errors.AsType[discuss.InvalidCommentSortError](err); ok {
+		writeAPIError(w, http.StatusBadRequest, "invalid_sort", "Invalid sort")
+
+		return
+	}
+
+	if _, ok := errors.AsType[contents.InvalidTopPeriodError](err); ok {
+		writeAPIError(w, http.StatusBadRequest, "invalid_period", "Invalid period")
+
+		return
+	}
+
+	if _, ok := errors.AsType[discuss.CommentNotFoundError](err); ok {
+		writeAPIError(w, http.StatusNotFound, "comment_not_found", "Comment not found")
+
+		return
+	}
+
+	if _, ok := errors.AsType[discuss.CommentDeletedError](err); ok {
+		writeAPIError(w, http.StatusConflict, "comment_deleted", "Comment is deleted")
+
+		return
+	}
+
+	if _, ok := errors.AsType[reactions.InvalidTargetTypeError](err); ok {
+		writeAPIError(w, http.StatusBadRequest, "invalid_target_type", "Invalid reaction target")
+
+		return
+	}
+
+	if _, ok := errors.AsType[reactions.TargetNotFoundError](err); ok {
+		writeAPIError(w, http.StatusNotFound, "target_not_found", "Reaction target not found")
+
+		return
+	}
+
+	if _, ok := errors.AsType[reactions.InvalidEmojiError](err); ok {
+		writeAPIError(w, http.StatusBadRequest, "invalid_emoji", "Invalid reaction emoji")
+
+		return
+	}
+
+	if tooManyReactionsErr, ok := errors.AsType[reactions.TooManyReactionsError](err); ok {
+		writeAPIError(w, http.StatusBadRequest, "too_many_reactions", tooManyReactionsMessage(tooManyReactionsErr))
+
+		return
+	}
+
+	switch {
+	case errors.Is(err, authentication.ErrInvalidCredentials):
+		writeAPIError(w, http.StatusUnauthorized, "invalid_credentials", "Invalid username or password")
+	case errors.Is(err, authentication.ErrCurrentUserNotFound):
+		writeAPIError(w, http.StatusUnauthorized, "unauthenticated", "Authentication required")
+	default:
+		slog.ErrorContext(r.Context(), "failed to handle api request", "path", r.URL.Path, "error", err)
+		writeAPIError(w, http.StatusInternalServerError, "internal_error", "Internal Server Error")
+	}
+}
+
+// handleCSRFError rejects requests that failed CSRF validation, with a JSON body for API requests.
+func handleCSRFError(w http.ResponseWriter, r *http.Request) {
+	if isAPIRequest(r) {
+		writeAPIError(w, http.StatusForbidden, "csrf_failed", csrf.FailureReason(r).Error())
+
+		return
+	}
+
+	http.Error(
+		w,
+		fmt.Sprintf("%s - %s", http.StatusText(http.StatusForbidden), csrf.FailureReason(r)),
+		http.StatusForbidden,
+	)
+}
+
+func (h *Handler) HandleAPINotFound(w http.ResponseWriter, _ *http.Request) {
+	writeAPIError(w, http.StatusNotFound, "not_found", "Not found")
+}
+
+func decodeAPIRequest(w http.ResponseWriter, r *http.Request, body any) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, apiMaxBodySize))
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(body)
+	if err != nil {
+		return InvalidAPIRequestError{Reason: "malformed JSON body"}
+	}
+
+	return nil
+}
+
+// encodeCursor builds the opaque cursor of the item at the given position of a list ordered by creation time.
+func encodeCursor(createdAt time.Time, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(createdAt.Format(time.RFC3339Nano) + "|" + id))
+}
+
+func decodeCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", InvalidCursorError{Cursor: cursor}
+	}
+
+	return parseCursor(cursor, string(raw))
+}
+
+// encodeScoredCursor builds the opaque cursor of the item at the given position of a list ordered by a score, and then
+// by creation time.
+func encodeScoredCursor(score float64, createdAt time.Time, id string) string {
+	value := strconv.FormatFloat(score, 'g', -1, 64) + "|" + createdAt.Format(time.RFC3339Nano) + "|" + id
+
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
+
+func decodeScoredCursor(cursor string) (float64, time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, time.Time{}, "", InvalidCursorError{Cursor: cursor}
+	}
+
+	scoreValue, rest, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return 0, time.Time{}, "", InvalidCursorError{Cursor: cursor}
+	}
+
+	score, err := strconv.ParseFloat(scoreValue, 64)
+	if err != nil {
+		return 0, time.Time{}, "", InvalidCursorError{Cursor: cursor}
+	}
+
+	createdAt, id, err := parseCursor(cursor, rest)
+	if err != nil {
+		return 0, time.Time{}, "", err
+	}
+
+	return score, createdAt, id, nil
+}
+
+// parseCursor parses the creation time and ID of a decoded cursor.
+func parseCursor(cursor, raw string) (time.Time, string, error) {
+	createdAtValue, id, ok := strings.Cut(raw, "|")
+	if !ok || id == "" {
+		return time.Time{}, "", InvalidCursorError{Cursor: cursor}
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, createdAtValue)
+	if err != nil {
+		return time.Time{}, "", InvalidCursorError{Cursor: cursor}
+	}
+
+	return createdAt, id, nil
+}
+
+// apiPageParams are the pagination query parameters shared by list endpoints.
+type apiPageParams struct {
+	Limit     int
+	CreatedAt time.Time
+	ID        string
+}
+
+func (params apiPageParams) hasCursor() bool {
+	return params.ID != ""
+}
+
+func parseAPIPageParams(r *http.Request) (apiPageParams, error) {
+	params := apiPageParams{
+		Limit:     apiDefaultPageSize,
+		CreatedAt: time.Time{},
+		ID:        "",
+	}
+
+	limit, err := parseAPILimit(r)
+	if err != nil {
+		return params, err
+	}
+
+	params.Limit = limit
+
+	if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+		createdAt, id, err := decodeCursor(cursor)
+		if err != nil {
+			return params, err
+		}
+
+		params.CreatedAt = createdAt
+		params.ID = id
+	}
+
+	return params, nil
+}
+
+// parseAPILimit returns the page size of a list request, for lists with cursors of their own.
+func parseAPILimit(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return apiDefaultPageSize, nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > apiMaxPageSize {
+		return 0, InvalidAPIRequestError{
+			Reason: fmt.Sprintf("limit must be between 1 and %d", apiMaxPageSize),
+		}
+	}
+
+	return limit, nil
+}
+
+// newAPIPage converts a list fetched with one item more than the page size into a page, so the extra item tells
+// whether there is a next page.
+func newAPIPage[S, T any](items []S, limit int, convert func(S) T, cursor func(S) string) APIPage[T] {
+	page := APIPage[T]{
+		Items:      make([]T, 0, min(len(items), limit)),
+		NextCursor: "",
+	}
+
+	if len(items) > limit {
+		items = items[:limit]
+		page.NextCursor = cursor(items[len(items)-1])
+	}
+
+	for _, item := range items {
+		page.Items = append(page.Items, convert(item))
+	}
+
+	return page
+}
+
+func (h *Handler) APIAuthenticatedOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isAuthenticatedRequest(r) {
+			writeAPIError(w, http.StatusUnauthorized, "unauthenticated", "Authentication required")
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
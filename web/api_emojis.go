@@ -0,0 +1,42 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/nasermirzaei89/scribble/emojis"
+)
+
+type APICustomEmoji struct {
+	Shortcode string `json:"shortcode"`
+	// Reference is how posts, comments and reactions use the emoji, like :wave:.
+	Reference string `json:"reference"`
+	URL       string `json:"url"`
+}
+
+func newAPICustomEmoji(emoji *emojis.CustomEmoji) APICustomEmoji {
+	return APICustomEmoji{
+		Shortcode: emoji.Shortcode,
+		Reference: emoji.Reference(),
+		URL:       emojiImagePath(emoji.Shortcode),
+	}
+}
+
+// HandleAPIListCustomEmojis lists every custom emoji in a single page, for emoji pickers.
+func (h *Handler) HandleAPIListCustomEmojis() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		customEmojis, err := h.emojisSvc.ListCustomEmojis(r.Context())
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		items := make([]APICustomEmoji, 0, len(customEmojis))
+
+		for _, emoji := range customEmojis {
+			items = append(items, newAPICustomEmoji(emoji))
+		}
+
+		writeAPIJSON(w, http.StatusOK, APIPage[APICustomEmoji]{Items: items, NextCursor: ""})
+	})
+}
@@ -0,0 +1,273 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/authentication"
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/discuss"
+	"github.com/nasermirzaei89/scribble/reactions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2026, 3, 1, 10, 0, 0, 123456789, time.FixedZone("CET", 3600))
+
+	cursor := encodeCursor(createdAt, "post1")
+
+	decodedCreatedAt, id, err := decodeCursor(cursor)
+	require.NoError(t, err)
+	assert.Equal(t, "post1", id)
+	assert.True(t, createdAt.Equal(decodedCreatedAt))
+
+	for _, invalid := range []string{"!!!", "bm8tc2VwYXJhdG9y", "bm90LWEtdGltZXxwb3N0MQ"} {
+		_, _, err := decodeCursor(invalid)
+		require.ErrorAs(t, err, &InvalidCursorError{}, invalid)
+	}
+}
+
+func TestPostCursor(t *testing.T) {
+	t.Parallel()
+
+	post := &contents.Post{
+		ID:            "post1",
+		AuthorID:      "user1",
+		CommunityID:   "",
+		Content:       "post",
+		CreatedAt:     time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+		ReactionScore: -3,
+		Hotness:       39447.123456789,
+	}
+
+	for _, sort := range contents.PostSorts() {
+		cursor, err := decodePostCursor(sort, postCursor(sort)(post))
+		require.NoError(t, err, sort)
+		assert.Equal(t, post.Cursor(sort).Score, cursor.Score, sort)
+		assert.True(t, post.CreatedAt.Equal(cursor.CreatedAt), sort)
+		assert.Equal(t, post.ID, cursor.ID, sort)
+	}
+
+	_, err := decodePostCursor(contents.PostSortHot, postCursor(contents.PostSortNew)(post))
+	require.ErrorAs(t, err, &InvalidCursorError{})
+}
+
+func TestCommentCursor(t *testing.T) {
+	t.Parallel()
+
+	comment := &discuss.Comment{
+		ID:            "comment1",
+		PostID:        "post1",
+		AuthorID:      "user1",
+		ReplyTo:       nil,
+		Content:       "comment",
+		CreatedAt:     time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+		ReactionScore: -2,
+	}
+
+	for _, sort := range discuss.CommentSorts() {
+		cursor, err := decodeCommentCursor(sort, commentCursor(sort)(comment))
+		require.NoError(t, err, sort)
+		assert.Equal(t, comment.Cursor(sort), cursor, sort)
+	}
+
+	_, err := decodeCommentCursor(discuss.CommentSortTop, encodeScoredCursor(1.5, comment.CreatedAt, comment.ID))
+	require.ErrorAs(t, err, &InvalidCursorError{})
+}
+
+func TestParseAPIPageParams(t *testing.T) {
+	t.Parallel()
+
+	t.Run("defaults", func(t *testing.T) {
+		t.Parallel()
+
+		params, err := parseAPIPageParams(httptest.NewRequest(http.MethodGet, "/api/v1/posts", nil))
+		require.NoError(t, err)
+		assert.Equal(t, apiDefaultPageSize, params.Limit)
+		assert.False(t, params.hasCursor())
+	})
+
+	t.Run("limit and cursor", func(t *testing.T) {
+		t.Parallel()
+
+		cursor := encodeCursor(time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC), "post1")
+
+		params, err := parseAPIPageParams(
+			httptest.NewRequest(http.MethodGet, "/api/v1/posts?limit=5&cursor="+cursor, nil),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, 5, params.Limit)
+		assert.True(t, params.hasCursor())
+		assert.Equal(t, "post1", params.ID)
+	})
+
+	for _, limit := range []string{"0", "-1", strconv.Itoa(apiMaxPageSize + 1), "ten"} {
+		t.Run("invalid limit "+limit, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := parseAPIPageParams(httptest.NewRequest(http.MethodGet, "/api/v1/posts?limit="+limit, nil))
+			require.ErrorAs(t, err, &InvalidAPIRequestError{})
+		})
+	}
+}
+
+func TestNewAPIPage(t *testing.T) {
+	t.Parallel()
+
+	identity := func(item int) int { return item }
+	cursor := strconv.Itoa
+
+	page := newAPIPage([]int{1, 2, 3}, 2, identity, cursor)
+	assert.Equal(t, []int{1, 2}, page.Items)
+	assert.Equal(t, "2", page.NextCursor)
+
+	page = newAPIPage([]int{1, 2}, 2, identity, cursor)
+	assert.Equal(t, []int{1, 2}, page.Items)
+	assert.Empty(t, page.NextCursor)
+
+	page = newAPIPage([]int{}, 2, identity, cursor)
+	assert.NotNil(t, page.Items)
+	assert.Empty(t, page.Items)
+}
+
+func TestHandleAPIError(t *testing.T) {
+	t.Parallel()
+
+	accessDeniedErr := &authorization.AccessDeniedError{
+		Subject: "user1",
+		Domain:  contents.ServiceName,
+		Object:  "",
+		Action:  contents.ActionCreatePost,
+	}
+
+	tt := []struct {
+		name          string
+		err           error
+		authenticated bool
+		status        int
+		code          string
+	}{
+		{
+			name:          "access denied for anonymous",
+			err:           accessDeniedErr,
+			authenticated: false,
+			status:        http.StatusUnauthorized,
+			code:          "unauthenticated",
+		},
+		{
+			name:          "access denied for authenticated",
+			err:           fmt.Errorf("failed to check authorization: %w", accessDeniedErr),
+			authenticated: true,
+			status:        http.StatusForbidden,
+			code:          "forbidden",
+		},
+		{
+			name:   "post not found",
+			err:    fmt.Errorf("failed to call next method: %w", contents.PostNotFoundError{ID: "post1"}),
+			status: http.StatusNotFound,
+			code:   "post_not_found",
+		},
+		{
+			name:   "user not found",
+			err:    fmt.Errorf("failed to find user by id: %w", &authentication.UserNotFoundError{ID: "user1"}),
+			status: http.StatusNotFound,
+			code:   "user_not_found",
+		},
+		{
+			name:   "user already exists",
+			err:    &authentication.UserAlreadyExistsError{Username: "alice"},
+			status: http.StatusConflict,
+			code:   "user_already_exists",
+		},
+		{
+			name:   "invalid username",
+			err:    &authentication.InvalidUsernameError{Username: "alice@example.com", Reason: "must not contain @"},
+			status: http.StatusBadRequest,
+			code:   "invalid_username",
+		},
+		{
+			name:   "invalid credentials",
+			err:    authentication.ErrInvalidCredentials,
+			status: http.StatusUnauthorized,
+			code:   "invalid_credentials",
+		},
+		{
+			name:   "invalid target type",
+			err:    reactions.InvalidTargetTypeError{TargetType: "page"},
+			status: http.StatusBadRequest,
+			code:   "invalid_target_type",
+		},
+		{
+			name:   "invalid emoji",
+			err:    reactions.InvalidEmojiError{TargetType: reactions.TargetTypePost, TargetID: "post1", Emoji: "x"},
+			status: http.StatusBadRequest,
+			code:   "invalid_emoji",
+		},
+		{
+			name:   "too many reactions",
+			err:    reactions.TooManyReactionsError{TargetType: reactions.TargetTypePost, TargetID: "post1", Max: 2},
+			status: http.StatusBadRequest,
+			code:   "too_many_reactions",
+		},
+		{
+			name:   "comment not found",
+			err:    fmt.Errorf("wrapped: %w", discuss.CommentNotFoundError{ID: "comment1"}),
+			status: http.StatusNotFound,
+			code:   "comment_not_found",
+		},
+		{
+			name:   "comment deleted",
+			err:    discuss.CommentDeletedError{ID: "comment1"},
+			status: http.StatusConflict,
+			code:   "comment_deleted",
+		},
+		{
+			name:   "invalid cursor",
+			err:    InvalidCursorError{Cursor: "x"},
+			status: http.StatusBadRequest,
+			code:   "invalid_cursor",
+		},
+		{
+			name:   "unknown",
+			err:    errors.New("boom"),
+			status: http.StatusInternalServerError,
+			code:   "internal_error",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/posts", nil)
+			if tc.authenticated {
+				r = r.WithContext(authcontext.WithSubject(r.Context(), "user1"))
+			}
+
+			w := httptest.NewRecorder()
+
+			handleAPIError(w, r, tc.err)
+
+			assert.Equal(t, tc.status, w.Code)
+			assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+			var body APIErrorBody
+
+			err := json.Unmarshal(w.Body.Bytes(), &body)
+			require.NoError(t, err)
+			assert.Equal(t, tc.code, body.Error.Code)
+			assert.NotEmpty(t, body.Error.Message)
+		})
+	}
+}
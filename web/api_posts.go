@@ -0,0 +1,338 @@
+package web
+
+import (
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/discuss"
+)
+
+type APIPost struct {
+	ID          string    `json:"id"`
+	AuthorID    string    `json:"authorId"`
+	CommunityID string    `json:"communityId,omitempty"`
+	Content     string    `json:"content"`
+	CreatedAt   time.Time `json:"createdAt"`
+	// ReactionScore is the weighted sum of the reactions to the post, which the top sort ranks by.
+	ReactionScore int `json:"reactionScore"`
+}
+
+func newAPIPost(post *contents.Post) APIPost {
+	return APIPost{
+		ID:            post.ID,
+		AuthorID:      post.AuthorID,
+		CommunityID:   post.CommunityID,
+		Content:       post.Content,
+		CreatedAt:     post.CreatedAt,
+		ReactionScore: post.ReactionScore,
+	}
+}
+
+// postCursor returns the function building the cursors of posts in a list in the sort.
+func postCursor(sort contents.PostSort) func(post *contents.Post) string {
+	return func(post *contents.Post) string {
+		cursor := post.Cursor(sort)
+
+		if sort == contents.PostSortTop || sort == contents.PostSortHot {
+			return encodeScoredCursor(cursor.Score, cursor.CreatedAt, cursor.ID)
+		}
+
+		return encodeCursor(cursor.CreatedAt, cursor.ID)
+	}
+}
+
+// decodePostCursor parses a cursor built by postCursor with the same sort.
+func decodePostCursor(sort contents.PostSort, cursor string) (*contents.PostCursor, error) {
+	if sort == contents.PostSortTop || sort == contents.PostSortHot {
+		score, createdAt, id, err := decodeScoredCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		return &contents.PostCursor{Score: score, CreatedAt: createdAt, ID: id}, nil
+	}
+
+	createdAt, id, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	return &contents.PostCursor{Score: 0, CreatedAt: createdAt, ID: id}, nil
+}
+
+type APIComment struct {
+	ID        string    `json:"id"`
+	PostID    string    `json:"postId"`
+	AuthorID  string    `json:"authorId"`
+	ReplyTo   *string   `json:"replyTo,omitempty"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"createdAt"`
+	// ReactionScore is the weighted sum of the reactions to the comment, which the top sort ranks by.
+	ReactionScore int `json:"reactionScore"`
+	// EditedAt is when the author last edited the content. DeletedAt is when the comment was deleted, leaving it without
+	// content.
+	EditedAt  *time.Time `json:"editedAt,omitempty"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+}
+
+func newAPIComment(comment *discuss.Comment) APIComment {
+	return APIComment{
+		ID:            comment.ID,
+		PostID:        comment.PostID,
+		AuthorID:      comment.AuthorID,
+		ReplyTo:       comment.ReplyTo,
+		Content:       comment.Content,
+		CreatedAt:     comment.CreatedAt,
+		ReactionScore: comment.ReactionScore,
+		EditedAt:      comment.EditedAt,
+		DeletedAt:     comment.DeletedAt,
+	}
+}
+
+// commentCursor returns the function building the cursors of comments in a list in the sort.
+func commentCursor(sort discuss.CommentSort) func(comment *discuss.Comment) string {
+	return func(comment *discuss.Comment) string {
+		cursor := comment.Cursor(sort)
+
+		if sort == discuss.CommentSortTop {
+			return encodeScoredCursor(float64(cursor.ReactionScore), cursor.CreatedAt, cursor.ID)
+		}
+
+		return encodeCursor(cursor.CreatedAt, cursor.ID)
+	}
+}
+
+// decodeCommentCursor parses a cursor built by commentCursor with the same sort.
+func decodeCommentCursor(sort discuss.CommentSort, cursor string) (*discuss.CommentCursor, error) {
+	if sort == discuss.CommentSortTop {
+		score, createdAt, id, err := decodeScoredCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		if score != math.Trunc(score) {
+			return nil, InvalidCursorError{Cursor: cursor}
+		}
+
+		return &discuss.CommentCursor{ReactionScore: int(score), CreatedAt: createdAt, ID: id}, nil
+	}
+
+	createdAt, id, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	return &discuss.CommentCursor{ReactionScore: 0, CreatedAt: createdAt, ID: id}, nil
+}
+
+type APICreatePostRequest struct {
+	Content     string `json:"content"`
+	CommunityID string `json:"communityId,omitempty"`
+}
+
+type APICreateCommentRequest struct {
+	Content string `json:"content"`
+	ReplyTo string `json:"replyTo,omitempty"`
+}
+
+// HandleAPIListPosts lists posts in the requested sort, from newest to oldest by default.
+func (h *Handler) HandleAPIListPosts() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit, err := parseAPILimit(r)
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		sort := contents.PostSort(r.URL.Query().Get("sort"))
+		if sort == "" {
+			sort = contents.PostSortNew
+		}
+
+		req := contents.ListPostsRequest{
+			AuthorID:    "",
+			CommunityID: r.URL.Query().Get("communityId"),
+			Sort:        sort,
+			Period:      contents.TopPeriod(r.URL.Query().Get("period")),
+			Before:      nil,
+			Limit:       limit + 1,
+		}
+
+		if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+			req.Before, err = decodePostCursor(sort, cursor)
+			if err != nil {
+				handleAPIError(w, r, err)
+
+				return
+			}
+		}
+
+		posts, err := h.contentsSvc.ListPosts(r.Context(), req)
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		writeAPIJSON(w, http.StatusOK, newAPIPage(posts, limit, newAPIPost, postCursor(sort)))
+	})
+}
+
+func (h *Handler) HandleAPICreatePost() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body APICreatePostRequest
+
+		err := decodeAPIRequest(w, r, &body)
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		if strings.TrimSpace(body.Content) == "" {
+			handleAPIError(w, r, InvalidAPIRequestError{Reason: "content is required"})
+
+			return
+		}
+
+		currentUser, err := h.authSvc.GetCurrentUser(r.Context())
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		post, err := h.contentsSvc.CreatePost(r.Context(), contents.CreatePostRequest{
+			AuthorID:    currentUser.ID,
+			CommunityID: body.CommunityID,
+			Content:     body.Content,
+		})
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		writeAPIJSON(w, http.StatusCreated, newAPIPost(post))
+	})
+
+	return h.APIAuthenticatedOnly(hf)
+}
+
+func (h *Handler) HandleAPIGetPost() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		post, err := h.contentsSvc.GetPost(r.Context(), r.PathValue("postId"))
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		writeAPIJSON(w, http.StatusOK, newAPIPost(post))
+	})
+}
+
+// HandleAPIListComments lists the comments of a post in the sort, from oldest to newest by default. Replies are flat,
+// pointing at their parent with replyTo.
+func (h *Handler) HandleAPIListComments() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit, err := parseAPILimit(r)
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		sort := discuss.CommentSort(r.URL.Query().Get("sort"))
+		if sort == "" {
+			sort = discuss.CommentSortOldest
+		}
+
+		post, err := h.contentsSvc.GetPost(r.Context(), r.PathValue("postId"))
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		req := discuss.ListCommentsRequest{
+			PostID:   post.ID,
+			TopLevel: false,
+			ReplyTo:  nil,
+			Sort:     sort,
+			After:    nil,
+			Limit:    limit + 1,
+		}
+
+		if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+			req.After, err = decodeCommentCursor(sort, cursor)
+			if err != nil {
+				handleAPIError(w, r, err)
+
+				return
+			}
+		}
+
+		comments, err := h.discussSvc.ListComments(r.Context(), req)
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		writeAPIJSON(w, http.StatusOK, newAPIPage(comments, limit, newAPIComment, commentCursor(sort)))
+	})
+}
+
+func (h *Handler) HandleAPICreateComment() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body APICreateCommentRequest
+
+		err := decodeAPIRequest(w, r, &body)
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		if strings.TrimSpace(body.Content) == "" {
+			handleAPIError(w, r, InvalidAPIRequestError{Reason: "content is required"})
+
+			return
+		}
+
+		post, err := h.contentsSvc.GetPost(r.Context(), r.PathValue("postId"))
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		currentUser, err := h.authSvc.GetCurrentUser(r.Context())
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		comment, err := h.discussSvc.CreateComment(r.Context(), discuss.CreateCommentRequest{
+			PostID:   post.ID,
+			AuthorID: currentUser.ID,
+			Content:  body.Content,
+			ReplyTo:  body.ReplyTo,
+		})
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		writeAPIJSON(w, http.StatusCreated, newAPIComment(comment))
+	})
+
+	return h.APIAuthenticatedOnly(hf)
+}
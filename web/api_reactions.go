@@ -0,0 +1,149 @@
+package web
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/reactions"
+)
+
+type APIReactionOption struct {
+	Emoji     string `json:"emoji"`
+	Count     int    `json:"count"`
+	Selected  bool   `json:"selected"`
+	Available bool   `json:"available"`
+}
+
+type APIReactions struct {
+	TargetType reactions.TargetType `json:"targetType"`
+	TargetID   string               `json:"targetId"`
+	Options    []APIReactionOption  `json:"options"`
+}
+
+func newAPIReactions(targetReactions *reactions.TargetReactions) APIReactions {
+	options := make([]APIReactionOption, 0, len(targetReactions.Options))
+
+	for _, option := range targetReactions.Options {
+		options = append(options, APIReactionOption{
+			Emoji:     option.Emoji,
+			Count:     option.Count,
+			Selected:  option.Selected,
+			Available: option.Available,
+		})
+	}
+
+	return APIReactions{
+		TargetType: targetReactions.TargetType,
+		TargetID:   targetReactions.TargetID,
+		Options:    options,
+	}
+}
+
+type APIReactor struct {
+	UserID    string    `json:"userId"`
+	Emoji     string    `json:"emoji"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func newAPIReactor(reaction *reactions.UserReaction) APIReactor {
+	return APIReactor{
+		UserID:    reaction.UserID,
+		Emoji:     reaction.Emoji,
+		CreatedAt: reaction.CreatedAt,
+	}
+}
+
+func reactorCursor(reaction *reactions.UserReaction) string {
+	return encodeCursor(reaction.CreatedAt, reaction.UserID)
+}
+
+type APIToggleReactionRequest struct {
+	Emoji string `json:"emoji"`
+}
+
+func (h *Handler) HandleAPIGetReactions() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		targetReactions, err := h.reactionsSvc.GetMyReactions(
+			r.Context(),
+			reactions.TargetType(r.PathValue("targetType")),
+			r.PathValue("targetId"),
+		)
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		writeAPIJSON(w, http.StatusOK, newAPIReactions(targetReactions))
+	})
+}
+
+// HandleAPIToggleReaction toggles the emoji of the current user on the target and returns the updated reactions.
+func (h *Handler) HandleAPIToggleReaction() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body APIToggleReactionRequest
+
+		err := decodeAPIRequest(w, r, &body)
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		targetType := reactions.TargetType(r.PathValue("targetType"))
+		targetID := r.PathValue("targetId")
+
+		err = h.reactionsSvc.ToggleMyReaction(r.Context(), targetType, targetID, body.Emoji)
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		targetReactions, err := h.reactionsSvc.GetMyReactions(r.Context(), targetType, targetID)
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		writeAPIJSON(w, http.StatusOK, newAPIReactions(targetReactions))
+	})
+
+	return h.APIAuthenticatedOnly(hf)
+}
+
+// HandleAPIListReactors lists who reacted to the target, from newest to oldest.
+func (h *Handler) HandleAPIListReactors() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params, err := parseAPIPageParams(r)
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		req := reactions.ListReactorsRequest{
+			TargetType: reactions.TargetType(r.PathValue("targetType")),
+			TargetID:   r.PathValue("targetId"),
+			Emoji:      r.URL.Query().Get("emoji"),
+			Before:     nil,
+			Limit:      params.Limit + 1,
+		}
+
+		if params.hasCursor() {
+			req.Before = &reactions.ReactorCursor{CreatedAt: params.CreatedAt, UserID: params.ID}
+		}
+
+		reactors, err := h.reactionsSvc.ListReactors(r.Context(), req)
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		writeAPIJSON(w, http.StatusOK, newAPIPage(reactors, params.Limit, newAPIReactor, reactorCursor))
+	})
+
+	return h.APIAuthenticatedOnly(hf)
+}
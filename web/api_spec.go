@@ -0,0 +1,484 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"github.com/gorilla/csrf"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/discuss"
+	"github.com/nasermirzaei89/scribble/web/openapi"
+)
+
+const (
+	apiVersion = "1.0.0"
+
+	apiTagUsers     = "users"
+	apiTagSessions  = "sessions"
+	apiTagPosts     = "posts"
+	apiTagComments  = "comments"
+	apiTagReactions = "reactions"
+	apiTagEmojis    = "emojis"
+
+	securitySchemeBearer = "bearerAuth"
+	securitySchemeCookie = "cookieAuth"
+)
+
+// apiOperation describes an API endpoint once, for both routing and the OpenAPI document, so the two cannot drift
+// apart.
+type apiOperation struct {
+	Method  string
+	Path    string
+	ID      string
+	Summary string
+	Tag     string
+	// Authenticated operations reject anonymous requests.
+	Authenticated bool
+	// Paginated operations accept the limit and cursor query parameters.
+	Paginated bool
+	Query     []*openapi.Parameter
+	// Request is the type of the JSON request body, if the operation takes one.
+	Request reflect.Type
+	Status  int
+	// Response is the type of the JSON response body, or nil for responses without content.
+	Response reflect.Type
+	// Errors lists the statuses of the operation's error responses besides those implied by the other fields.
+	Errors  []int
+	Handler http.Handler
+}
+
+func (h *Handler) apiOperations() []apiOperation {
+	return []apiOperation{
+		{
+			Method:   http.MethodPost,
+			Path:     "/api/v1/users",
+			ID:       "registerUser",
+			Summary:  "Register a user",
+			Tag:      apiTagUsers,
+			Request:  reflect.TypeFor[APICredentials](),
+			Status:   http.StatusCreated,
+			Response: reflect.TypeFor[APIUser](),
+			Errors:   []int{http.StatusConflict},
+			Handler:  h.HandleAPIRegister(),
+		},
+		{
+			Method:        http.MethodGet,
+			Path:          "/api/v1/users/me",
+			ID:            "getCurrentUser",
+			Summary:       "Get the current user",
+			Tag:           apiTagUsers,
+			Authenticated: true,
+			Status:        http.StatusOK,
+			Response:      reflect.TypeFor[APIUser](),
+			Handler:       h.HandleAPIGetCurrentUser(),
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/v1/users/{userId}",
+			ID:       "getUser",
+			Summary:  "Get a user",
+			Tag:      apiTagUsers,
+			Status:   http.StatusOK,
+			Response: reflect.TypeFor[APIUser](),
+			Errors:   []int{http.StatusNotFound},
+			Handler:  h.HandleAPIGetUser(),
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/v1/users/{userId}/reputation",
+			ID:       "getUserReputation",
+			Summary:  "Get the reputation of a user",
+			Tag:      apiTagUsers,
+			Status:   http.StatusOK,
+			Response: reflect.TypeFor[APIReputation](),
+			Errors:   []int{http.StatusNotFound},
+			Handler:  h.HandleAPIGetUserReputation(),
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/api/v1/sessions",
+			ID:       "createSession",
+			Summary:  "Log in and obtain a bearer token",
+			Tag:      apiTagSessions,
+			Request:  reflect.TypeFor[APICredentials](),
+			Status:   http.StatusCreated,
+			Response: reflect.TypeFor[APISession](),
+			Handler:  h.HandleAPILogin(),
+		},
+		{
+			Method:        http.MethodDelete,
+			Path:          "/api/v1/sessions/current",
+			ID:            "deleteCurrentSession",
+			Summary:       "Log out",
+			Tag:           apiTagSessions,
+			Authenticated: true,
+			Status:        http.StatusNoContent,
+			Handler:       h.HandleAPILogout(),
+		},
+		{
+			Method:    http.MethodGet,
+			Path:      "/api/v1/posts",
+			ID:        "listPosts",
+			Summary:   "List posts, newest, top or hot first",
+			Tag:       apiTagPosts,
+			Paginated: true,
+			Query: []*openapi.Parameter{
+				{
+					Name:        "communityId",
+					In:          openapi.InQuery,
+					Description: "Only list the posts of the community.",
+					Schema:      &openapi.Schema{Type: "string"},
+				},
+				{
+					Name: "sort",
+					In:   openapi.InQuery,
+					Description: "Order of the posts: new from newest to oldest, top by reaction score and hot by " +
+						"reaction score and comments decayed by age. Defaults to new. Cursors only work with the sort " +
+						"they were returned with.",
+					Schema: &openapi.Schema{Type: "string", Enum: enumValues(contents.PostSorts())},
+				},
+				{
+					Name:        "period",
+					In:          openapi.InQuery,
+					Description: "How far back the top sort looks for posts. Defaults to all.",
+					Schema:      &openapi.Schema{Type: "string", Enum: enumValues(contents.TopPeriods())},
+				},
+			},
+			Status:   http.StatusOK,
+			Response: reflect.TypeFor[APIPage[APIPost]](),
+			Handler:  h.HandleAPIListPosts(),
+		},
+		{
+			Method:        http.MethodPost,
+			Path:          "/api/v1/posts",
+			ID:            "createPost",
+			Summary:       "Create a post",
+			Tag:           apiTagPosts,
+			Authenticated: true,
+			Request:       reflect.TypeFor[APICreatePostRequest](),
+			Status:        http.StatusCreated,
+			Response:      reflect.TypeFor[APIPost](),
+			Handler:       h.HandleAPICreatePost(),
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/v1/posts/{postId}",
+			ID:       "getPost",
+			Summary:  "Get a post",
+			Tag:      apiTagPosts,
+			Status:   http.StatusOK,
+			Response: reflect.TypeFor[APIPost](),
+			Errors:   []int{http.StatusNotFound},
+			Handler:  h.HandleAPIGetPost(),
+		},
+		{
+			Method:    http.MethodGet,
+			Path:      "/api/v1/posts/{postId}/comments",
+			ID:        "listComments",
+			Summary:   "List the comments of a post, oldest, newest or top first",
+			Tag:       apiTagComments,
+			Paginated: true,
+			Query: []*openapi.Parameter{
+				{
+					Name: "sort",
+					In:   openapi.InQuery,
+					Description: "Order of the comments: oldest from oldest to newest, newest from newest to oldest and " +
+						"top by reaction score. Defaults to oldest. Cursors only work with the sort they were returned " +
+						"with.",
+					Schema: &openapi.Schema{Type: "string", Enum: enumValues(discuss.CommentSorts())},
+				},
+			},
+			Status:   http.StatusOK,
+			Response: reflect.TypeFor[APIPage[APIComment]](),
+			Errors:   []int{http.StatusNotFound},
+			Handler:  h.HandleAPIListComments(),
+		},
+		{
+			Method:        http.MethodPost,
+			Path:          "/api/v1/posts/{postId}/comments",
+			ID:            "createComment",
+			Summary:       "Comment on a post",
+			Tag:           apiTagComments,
+			Authenticated: true,
+			Request:       reflect.TypeFor[APICreateCommentRequest](),
+			Status:        http.StatusCreated,
+			Response:      reflect.TypeFor[APIComment](),
+			Errors:        []int{http.StatusNotFound},
+			Handler:       h.HandleAPICreateComment(),
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/v1/reactions/{targetType}/{targetId}",
+			ID:       "getReactions",
+			Summary:  "Get the reactions to a post, comment or user",
+			Tag:      apiTagReactions,
+			Status:   http.StatusOK,
+			Response: reflect.TypeFor[APIReactions](),
+			Errors:   []int{http.StatusBadRequest},
+			Handler:  h.HandleAPIGetReactions(),
+		},
+		{
+			Method:        http.MethodPost,
+			Path:          "/api/v1/reactions/{targetType}/{targetId}",
+			ID:            "toggleReaction",
+			Summary:       "Toggle a reaction of the current user",
+			Tag:           apiTagReactions,
+			Authenticated: true,
+			Request:       reflect.TypeFor[APIToggleReactionRequest](),
+			Status:        http.StatusOK,
+			Response:      reflect.TypeFor[APIReactions](),
+			Errors:        []int{http.StatusNotFound},
+			Handler:       h.HandleAPIToggleReaction(),
+		},
+		{
+			Method:        http.MethodGet,
+			Path:          "/api/v1/reactions/{targetType}/{targetId}/reactors",
+			ID:            "listReactors",
+			Summary:       "List who reacted to a post, comment or user, newest first",
+			Tag:           apiTagReactions,
+			Authenticated: true,
+			Paginated:     true,
+			Query: []*openapi.Parameter{
+				{
+					Name:        "emoji",
+					In:          openapi.InQuery,
+					Description: "Only list the reactions with the emoji.",
+					Schema:      &openapi.Schema{Type: "string"},
+				},
+			},
+			Status:   http.StatusOK,
+			Response: reflect.TypeFor[APIPage[APIReactor]](),
+			Handler:  h.HandleAPIListReactors(),
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/v1/emojis",
+			ID:       "listCustomEmojis",
+			Summary:  "List the custom emojis of the site",
+			Tag:      apiTagEmojis,
+			Status:   http.StatusOK,
+			Response: reflect.TypeFor[APIPage[APICustomEmoji]](),
+			Handler:  h.HandleAPIListCustomEmojis(),
+		},
+	}
+}
+
+// enumValues returns the values of a string enum, for the Enum of a schema.
+func enumValues[T ~string](values []T) []string {
+	strs := make([]string, 0, len(values))
+
+	for _, value := range values {
+		strs = append(strs, string(value))
+	}
+
+	return strs
+}
+
+// errorStatuses returns the statuses of every error response the operation can produce.
+func (op apiOperation) errorStatuses() []int {
+	statuses := slices.Clone(op.Errors)
+
+	if op.Request != nil || op.Paginated {
+		statuses = append(statuses, http.StatusBadRequest)
+	}
+
+	// Any request may carry an invalid bearer token.
+	statuses = append(statuses, http.StatusUnauthorized)
+
+	if op.Authenticated {
+		statuses = append(statuses, http.StatusForbidden)
+	}
+
+	if op.Method != http.MethodGet {
+		// Cookie authenticated requests failing CSRF validation.
+		statuses = append(statuses, http.StatusForbidden)
+	}
+
+	statuses = append(statuses, http.StatusInternalServerError)
+
+	slices.Sort(statuses)
+
+	return slices.Compact(statuses)
+}
+
+func newAPISpec(operations []apiOperation, sessionName string) *openapi.Document {
+	builder := openapi.NewBuilder(openapi.Info{
+		Title:   "Scribble API",
+		Version: apiVersion,
+		Description: "Authenticate with the token of a session created by createSession, sent as " +
+			"\"Authorization: Bearer <token>\", or with the browser session cookie. Cookie authenticated requests " +
+			"that change state must send the CSRF token in the X-CSRF-Token header.",
+	}, "API")
+
+	builder.AddSecurityScheme(securitySchemeBearer, &openapi.SecurityScheme{
+		Type:        "http",
+		Scheme:      "bearer",
+		Description: "Token of a session created by createSession.",
+	})
+	builder.AddSecurityScheme(securitySchemeCookie, &openapi.SecurityScheme{
+		Type:        "apiKey",
+		In:          "cookie",
+		Name:        sessionName,
+		Description: "Session cookie of the web interface.",
+	})
+
+	pageParameters := []*openapi.Parameter{
+		{
+			Name:        "limit",
+			In:          openapi.InQuery,
+			Description: "Maximum number of items in the page.",
+			Schema: &openapi.Schema{
+				Type:    "integer",
+				Minimum: new(1),
+				Maximum: new(apiMaxPageSize),
+			},
+		},
+		{
+			Name:        "cursor",
+			In:          openapi.InQuery,
+			Description: "The nextCursor of the previous page.",
+			Schema:      &openapi.Schema{Type: "string"},
+		},
+	}
+
+	for _, op := range operations {
+		operation := &openapi.Operation{
+			OperationID: op.ID,
+			Summary:     op.Summary,
+			Tags:        []string{op.Tag},
+			Parameters:  slices.Clone(op.Query),
+			Responses:   nil,
+		}
+
+		if op.Paginated {
+			operation.Parameters = append(operation.Parameters, pageParameters...)
+		}
+
+		if op.Request != nil {
+			operation.RequestBody = builder.JSONBody(op.Request)
+		}
+
+		if op.Authenticated {
+			operation.Security = []map[string][]string{
+				{securitySchemeBearer: {}},
+				{securitySchemeCookie: {}},
+			}
+		}
+
+		responses := map[int]*openapi.Response{
+			op.Status: builder.JSONResponse(op.Status, op.Response),
+		}
+
+		for _, status := range op.errorStatuses() {
+			responses[status] = builder.JSONResponse(status, reflect.TypeFor[APIErrorBody]())
+		}
+
+		operation.Responses = openapi.Responses(responses)
+
+		builder.AddOperation(op.Method, op.Path, operation)
+	}
+
+	return builder.Document()
+}
+
+func (h *Handler) HandleOpenAPISpec() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeAPIJSON(w, http.StatusOK, h.apiSpec)
+	})
+}
+
+// APIDocsOperation is an operation as the docs page presents it.
+type APIDocsOperation struct {
+	Method      string
+	Path        string
+	ID          string
+	Summary     string
+	Tag         string
+	Parameters  []*openapi.Parameter
+	RequestBody string
+	Responses   []APIDocsResponse
+	Secured     bool
+}
+
+type APIDocsResponse struct {
+	Status      string
+	Description string
+	Schema      string
+}
+
+func (h *Handler) apiDocsOperations() []APIDocsOperation {
+	operations := h.apiOperations()
+	result := make([]APIDocsOperation, 0, len(operations))
+
+	for _, op := range operations {
+		_, operation, ok := h.apiSpec.FindOperation(op.Method, op.Path)
+		if !ok {
+			continue
+		}
+
+		docsOperation := APIDocsOperation{
+			Method:      op.Method,
+			Path:        op.Path,
+			ID:          operation.OperationID,
+			Summary:     operation.Summary,
+			Tag:         op.Tag,
+			Parameters:  operation.Parameters,
+			RequestBody: "",
+			Responses:   make([]APIDocsResponse, 0, len(operation.Responses)),
+			Secured:     len(operation.Security) > 0,
+		}
+
+		if op.Request != nil {
+			// The zero value of the request type shows the shape of the body to fill in.
+			example, err := json.MarshalIndent(reflect.New(op.Request).Elem().Interface(), "", "  ")
+			if err != nil {
+				panic(fmt.Errorf("failed to marshal request example of %s: %w", op.ID, err))
+			}
+
+			docsOperation.RequestBody = string(example)
+		}
+
+		for status, response := range operation.Responses {
+			docsResponse := APIDocsResponse{
+				Status:      status,
+				Description: response.Description,
+				Schema:      "",
+			}
+
+			if content, ok := response.Content["application/json"]; ok {
+				docsResponse.Schema = content.Schema.ComponentName()
+			}
+
+			docsOperation.Responses = append(docsOperation.Responses, docsResponse)
+		}
+
+		slices.SortFunc(docsOperation.Responses, func(a, b APIDocsResponse) int {
+			statusA, _ := strconv.Atoi(a.Status)
+			statusB, _ := strconv.Atoi(b.Status)
+
+			return statusA - statusB
+		})
+
+		result = append(result, docsOperation)
+	}
+
+	return result
+}
+
+// HandleAPIDocsPage renders the API reference with forms that send requests with the visitor's session.
+func (h *Handler) HandleAPIDocsPage() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data := map[string]any{
+			"SiteTitle":  "API",
+			"CSRFToken":  csrf.Token(r),
+			"Info":       h.apiSpec.Info,
+			"Operations": h.apiDocsOperations(),
+		}
+
+		h.renderTemplate(w, r, "api-docs-page.gohtml", data)
+	})
+}
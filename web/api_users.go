@@ -0,0 +1,199 @@
+package web
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/authentication"
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/reputation"
+)
+
+type APIUser struct {
+	ID           string    `json:"id"`
+	Username     string    `json:"username"`
+	RegisteredAt time.Time `json:"registeredAt"`
+}
+
+func newAPIUser(user *authentication.User) APIUser {
+	return APIUser{
+		ID:           user.ID,
+		Username:     user.Username,
+		RegisteredAt: user.RegisteredAt,
+	}
+}
+
+type APIReputation struct {
+	UserID         string    `json:"userId"`
+	Score          int       `json:"score"`
+	ReactionPoints int       `json:"reactionPoints"`
+	Posts          int       `json:"posts"`
+	Comments       int       `json:"comments"`
+	Trusted        bool      `json:"trusted"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+func newAPIReputation(rep *reputation.Reputation) APIReputation {
+	return APIReputation{
+		UserID:         rep.UserID,
+		Score:          rep.Score,
+		ReactionPoints: rep.ReactionPoints,
+		Posts:          rep.Posts,
+		Comments:       rep.Comments,
+		Trusted:        rep.Trusted,
+		UpdatedAt:      rep.UpdatedAt,
+	}
+}
+
+// APISession holds the token to send as "Authorization: Bearer <token>".
+type APISession struct {
+	Token     string    `json:"token"`
+	UserID    string    `json:"userId"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type APICredentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func decodeAPICredentials(w http.ResponseWriter, r *http.Request) (APICredentials, error) {
+	var credentials APICredentials
+
+	err := decodeAPIRequest(w, r, &credentials)
+	if err != nil {
+		return credentials, err
+	}
+
+	if credentials.Username == "" || credentials.Password == "" {
+		return credentials, InvalidAPIRequestError{Reason: "username and password are required"}
+	}
+
+	return credentials, nil
+}
+
+func (h *Handler) HandleAPIRegister() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		credentials, err := decodeAPICredentials(w, r)
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		user, err := h.authSvc.Register(r.Context(), credentials.Username, credentials.Password)
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		writeAPIJSON(w, http.StatusCreated, newAPIUser(user))
+	})
+}
+
+func (h *Handler) HandleAPIGetCurrentUser() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, err := h.authSvc.GetCurrentUser(r.Context())
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		writeAPIJSON(w, http.StatusOK, newAPIUser(user))
+	})
+
+	return h.APIAuthenticatedOnly(hf)
+}
+
+func (h *Handler) HandleAPIGetUser() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, err := h.authSvc.GetUser(r.Context(), r.PathValue("userId"))
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		writeAPIJSON(w, http.StatusOK, newAPIUser(user))
+	})
+}
+
+func (h *Handler) HandleAPIGetUserReputation() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, err := h.authSvc.GetUser(r.Context(), r.PathValue("userId"))
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		rep, err := h.reputationSvc.GetReputation(r.Context(), user.ID)
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		writeAPIJSON(w, http.StatusOK, newAPIReputation(rep))
+	})
+}
+
+func (h *Handler) HandleAPILogin() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		credentials, err := decodeAPICredentials(w, r)
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		session, err := h.authSvc.Login(r.Context(), credentials.Username, credentials.Password)
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		writeAPIJSON(w, http.StatusCreated, APISession{
+			Token:     session.ID,
+			UserID:    session.UserID,
+			CreatedAt: session.CreatedAt,
+			ExpiresAt: session.ExpiresAt,
+		})
+	})
+}
+
+// HandleAPILogout ends the session the request is authenticated with, whether it came from a bearer token or the
+// session cookie.
+func (h *Handler) HandleAPILogout() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID, ok := authcontext.SessionIDFromContext(r.Context())
+		if !ok {
+			writeAPIError(w, http.StatusUnauthorized, "unauthenticated", "Authentication required")
+
+			return
+		}
+
+		err := h.authSvc.Logout(r.Context(), sessionID)
+		if err != nil {
+			handleAPIError(w, r, err)
+
+			return
+		}
+
+		if _, ok := bearerToken(r); !ok {
+			err = h.deleteSessionValue(w, r, sessionIDKey)
+			if err != nil {
+				slog.ErrorContext(r.Context(), "error on deleting session value", "key", sessionIDKey, "error", err)
+			}
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	return h.APIAuthenticatedOnly(hf)
+}
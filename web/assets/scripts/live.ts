@@ -0,0 +1,43 @@
+import htmx from "htmx.org";
+
+// Event types sent by GET /events. Their data are out of band swaps rendered for the viewer.
+const liveEventTypes = [
+    "post-created",
+    "comment-created",
+    "comment-changed",
+    "reactions-changed",
+];
+
+function connectLiveEvents(element: HTMLElement) {
+    const url = element.dataset.liveEvents;
+    if (!url) {
+        return;
+    }
+
+    const source = new EventSource(url);
+
+    for (const type of liveEventTypes) {
+        source.addEventListener(type, (event: MessageEvent<string>) => {
+            htmx.swap(element, event.data, { swapStyle: "none" });
+        });
+    }
+
+    // Boosted navigation replaces the page without unloading it.
+    element.addEventListener("htmx:beforeCleanupElement", () => source.close(), {
+        once: true,
+    });
+}
+
+htmx.onLoad((content) => {
+    if (!(content instanceof HTMLElement)) {
+        return;
+    }
+
+    if (content.matches("[data-live-events]")) {
+        connectLiveEvents(content);
+    }
+
+    content
+        .querySelectorAll<HTMLElement>("[data-live-events]")
+        .forEach(connectLiveEvents);
+});
@@ -0,0 +1,271 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/audit"
+	"github.com/nasermirzaei89/scribble/authorization"
+)
+
+const (
+	auditLogPageSize   = 50
+	auditLogExportSize = 1000
+	auditLogDateLayout = "2006-01-02"
+)
+
+// clientInfoMiddleware stores the client address and user agent in the context, so that audit events can be attributed.
+func (h *Handler) clientInfoMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+
+		r = r.WithContext(audit.WithClientInfo(r.Context(), audit.ClientInfo{
+			IP:        ip,
+			UserAgent: r.UserAgent(),
+		}))
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+type auditLogFilters struct {
+	Actor   string
+	Action  string
+	Target  string
+	Outcome string
+	Since   string
+	Until   string
+}
+
+func (filters auditLogFilters) query() url.Values {
+	query := url.Values{}
+
+	for key, value := range map[string]string{
+		"actor":   filters.Actor,
+		"action":  filters.Action,
+		"target":  filters.Target,
+		"outcome": filters.Outcome,
+		"since":   filters.Since,
+		"until":   filters.Until,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+
+	return query
+}
+
+type InvalidAuditLogDateError struct {
+	Value string
+}
+
+func (err InvalidAuditLogDateError) Error() string {
+	return fmt.Sprintf("invalid audit log date: %q", err.Value)
+}
+
+func parseAuditLogFilters(r *http.Request) (auditLogFilters, audit.ListEventsRequest, error) {
+	query := r.URL.Query()
+
+	filters := auditLogFilters{
+		Actor:   query.Get("actor"),
+		Action:  query.Get("action"),
+		Target:  query.Get("target"),
+		Outcome: query.Get("outcome"),
+		Since:   query.Get("since"),
+		Until:   query.Get("until"),
+	}
+
+	req := audit.ListEventsRequest{
+		Actor:   filters.Actor,
+		Action:  filters.Action,
+		Target:  filters.Target,
+		Outcome: audit.Outcome(filters.Outcome),
+		Since:   time.Time{},
+		Until:   time.Time{},
+		Limit:   0,
+		Offset:  0,
+	}
+
+	if filters.Since != "" {
+		since, err := time.Parse(auditLogDateLayout, filters.Since)
+		if err != nil {
+			return filters, req, InvalidAuditLogDateError{Value: filters.Since}
+		}
+
+		req.Since = since
+	}
+
+	if filters.Until != "" {
+		until, err := time.Parse(auditLogDateLayout, filters.Until)
+		if err != nil {
+			return filters, req, InvalidAuditLogDateError{Value: filters.Until}
+		}
+
+		// The until date is inclusive, so the range ends at the start of the next day.
+		req.Until = until.AddDate(0, 0, 1)
+	}
+
+	return filters, req, nil
+}
+
+func handleAuditLogError(w http.ResponseWriter, r *http.Request, err error) {
+	if _, ok := errors.AsType[audit.InvalidOutcomeError](err); ok {
+		http.Error(w, "Invalid audit outcome", http.StatusBadRequest)
+
+		return
+	}
+
+	if _, ok := errors.AsType[InvalidAuditLogDateError](err); ok {
+		http.Error(w, "Invalid audit date", http.StatusBadRequest)
+
+		return
+	}
+
+	if _, ok := errors.AsType[*authorization.AccessDeniedError](err); ok {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+
+		return
+	}
+
+	slog.ErrorContext(r.Context(), "failed to list audit events", "error", err)
+	http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+}
+
+func (h *Handler) HandleAuditLogPage() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filters, req, err := parseAuditLogFilters(r)
+		if err != nil {
+			handleAuditLogError(w, r, err)
+
+			return
+		}
+
+		if format := r.URL.Query().Get("format"); format != "" {
+			h.exportAuditLog(w, r, audit.ExportFormat(format), req)
+
+			return
+		}
+
+		offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+		if err != nil || offset < 0 {
+			offset = 0
+		}
+
+		req.Offset = offset
+		// One extra event tells whether there is a next page.
+		req.Limit = auditLogPageSize + 1
+
+		events, err := h.auditSvc.ListEvents(r.Context(), req)
+		if err != nil {
+			handleAuditLogError(w, r, err)
+
+			return
+		}
+
+		hasNext := len(events) > auditLogPageSize
+		if hasNext {
+			events = events[:auditLogPageSize]
+		}
+
+		query := filters.query()
+
+		pageURL := func(offset int) string {
+			pageQuery := maps.Clone(query)
+
+			if offset > 0 {
+				pageQuery.Set("offset", strconv.Itoa(offset))
+			}
+
+			return "/admin/audit?" + pageQuery.Encode()
+		}
+
+		exportURL := func(format audit.ExportFormat) string {
+			exportQuery := maps.Clone(query)
+
+			exportQuery.Set("format", string(format))
+
+			return "/admin/audit?" + exportQuery.Encode()
+		}
+
+		data := map[string]any{
+			"SiteTitle": "Audit Log",
+			"Events":    events,
+			"Filters":   filters,
+			"Outcomes":  []audit.Outcome{audit.OutcomeSuccess, audit.OutcomeFailure, audit.OutcomeDenied},
+			"PrevURL":   "",
+			"NextURL":   "",
+			"CSVURL":    exportURL(audit.ExportFormatCSV),
+			"JSONLURL":  exportURL(audit.ExportFormatJSONL),
+		}
+
+		if offset > 0 {
+			data["PrevURL"] = pageURL(max(offset-auditLogPageSize, 0))
+		}
+
+		if hasNext {
+			data["NextURL"] = pageURL(offset + auditLogPageSize)
+		}
+
+		h.renderTemplate(w, r, "admin-audit-page.gohtml", data)
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+func (h *Handler) exportAuditLog(
+	w http.ResponseWriter,
+	r *http.Request,
+	format audit.ExportFormat,
+	req audit.ListEventsRequest,
+) {
+	if format != audit.ExportFormatCSV && format != audit.ExportFormatJSONL {
+		http.Error(w, "Unsupported audit export format", http.StatusBadRequest)
+
+		return
+	}
+
+	events := make([]*audit.Event, 0)
+
+	req.Limit = auditLogExportSize
+
+	for {
+		page, err := h.auditSvc.ListEvents(r.Context(), req)
+		if err != nil {
+			handleAuditLogError(w, r, err)
+
+			return
+		}
+
+		events = append(events, page...)
+
+		if len(page) < auditLogExportSize {
+			break
+		}
+
+		req.Offset += auditLogExportSize
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set(
+		"Content-Disposition",
+		fmt.Sprintf(`attachment; filename="audit-%s.%s"`, time.Now().UTC().Format("20060102T150405Z"), format),
+	)
+
+	err := audit.Export(w, format, events)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to export audit events", "format", format, "error", err)
+
+		return
+	}
+}
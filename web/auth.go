@@ -12,6 +12,14 @@ import (
 
 func (h *Handler) authMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Requests with a bearer token are already authenticated by apiAuthMiddleware, and must not fall back to the
+		// session cookie.
+		if _, ok := bearerToken(r); ok {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
 		sessionID, err := h.getSessionValue(r, sessionIDKey)
 		if err != nil {
 			if _, ok := errors.AsType[*SessionValueNotFoundError](err); !ok {
@@ -0,0 +1,469 @@
+package web
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/csrf"
+	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/discuss"
+)
+
+// commentsPageSize is how many top-level comments a page of a post shows, with their threads.
+const commentsPageSize = 20
+
+// commentThreadDepth is how many levels of a thread are shown at once. Deeper replies are shown by continuing the
+// thread.
+const commentThreadDepth = 4
+
+// parseCommentsQuery reads the sort and cursor of a page of comments from the query, writing a bad request error when
+// either is invalid.
+func parseCommentsQuery(w http.ResponseWriter, r *http.Request) (discuss.CommentSort, *discuss.CommentCursor, bool) {
+	query := r.URL.Query()
+
+	sort := discuss.CommentSort(query.Get("sort"))
+	if sort == "" {
+		sort = discuss.CommentSortOldest
+	}
+
+	if !sort.IsValid() {
+		http.Error(w, "Invalid sort", http.StatusBadRequest)
+
+		return "", nil, false
+	}
+
+	cursor := query.Get("cursor")
+	if cursor == "" {
+		return sort, nil, true
+	}
+
+	after, err := decodeCommentCursor(sort, cursor)
+	if err != nil {
+		http.Error(w, "Invalid cursor", http.StatusBadRequest)
+
+		return "", nil, false
+	}
+
+	return sort, after, true
+}
+
+// commentsQuery returns the query string of a page of comments, leaving out the default sort.
+func commentsQuery(sort discuss.CommentSort, cursor string) string {
+	query := url.Values{}
+
+	if sort != discuss.CommentSortOldest {
+		query.Set("sort", string(sort))
+	}
+
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+
+	if len(query) == 0 {
+		return ""
+	}
+
+	return "?" + query.Encode()
+}
+
+func commentThreadPath(postID, commentID string) string {
+	return "/p/" + postID + "/comments/" + commentID
+}
+
+func commentSortOptions(postID string, sort discuss.CommentSort) []map[string]any {
+	options := make([]map[string]any, 0, len(discuss.CommentSorts()))
+
+	for _, option := range discuss.CommentSorts() {
+		options = append(options, map[string]any{
+			"Name":     string(option),
+			"URL":      "/p/" + postID + commentsQuery(option, "") + "#comments",
+			"Selected": option == sort,
+		})
+	}
+
+	return options
+}
+
+// moreComments links to the next page of comments, as a page and as a fragment to add in place. It returns nil on the
+// last page.
+func moreComments(postID string, sort discuss.CommentSort, nextCursor string) map[string]any {
+	if nextCursor == "" {
+		return nil
+	}
+
+	return map[string]any{
+		"URL":         "/p/" + postID + commentsQuery(sort, nextCursor) + "#comments",
+		"FragmentURL": "/p/" + postID + "/comments" + commentsQuery(sort, nextCursor),
+	}
+}
+
+// HandleComments renders the next page of the comments of a post for htmx to add in place. Other requests are sent to
+// the page of the post.
+func (h *Handler) HandleComments() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		postID := r.PathValue("postId")
+
+		sort, after, ok := parseCommentsQuery(w, r)
+		if !ok {
+			return
+		}
+
+		if r.Header.Get(htmxRequestHeader) != htmxRequestValueTrue {
+			target := "/p/" + postID
+			if r.URL.RawQuery != "" {
+				target += "?" + r.URL.RawQuery
+			}
+
+			http.Redirect(w, r, target+"#comments", http.StatusSeeOther)
+
+			return
+		}
+
+		post, err := h.contentsSvc.GetPost(r.Context(), postID)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to get post", "postId", postID, "error", err)
+			http.Error(w, "Post not found", http.StatusNotFound)
+
+			return
+		}
+
+		comments, nextCursor, err := h.listCommentsWithAuthors(
+			r.Context(),
+			post.ID,
+			sort,
+			after,
+			"/p/"+post.ID,
+			csrf.TemplateField(r),
+		)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to list comments with authors", "postId", post.ID, "error", err)
+			http.Error(w, "Failed to list comments", http.StatusInternalServerError)
+
+			return
+		}
+
+		h.renderTemplate(w, r, "comments-page.gohtml", map[string]any{
+			"Comments":     comments,
+			"MoreComments": moreComments(post.ID, sort, nextCursor),
+		})
+	})
+}
+
+// HandleCommentThreadPage shows a comment with its thread, for threads too deep to show on the page of the post.
+func (h *Handler) HandleCommentThreadPage() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sort, _, ok := parseCommentsQuery(w, r)
+		if !ok {
+			return
+		}
+
+		comment, ok := h.findPostComment(w, r)
+		if !ok {
+			return
+		}
+
+		threads, err := h.loadCommentThreads(
+			r.Context(),
+			[]*discuss.Comment{comment},
+			sort,
+			r.URL.RequestURI(),
+			csrf.TemplateField(r),
+		)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to load comment thread", "commentId", comment.ID, "error", err)
+			http.Error(w, "Failed to load comment thread", http.StatusInternalServerError)
+
+			return
+		}
+
+		parentURL := ""
+		if comment.ReplyTo != nil {
+			parentURL = commentThreadPath(comment.PostID, *comment.ReplyTo) + commentsQuery(sort, "")
+		}
+
+		h.renderTemplate(w, r, "thread-page.gohtml", map[string]any{
+			"SiteTitle":      "Thread",
+			"PostID":         comment.PostID,
+			"PostURL":        "/p/" + comment.PostID + commentsQuery(sort, "") + "#comment-" + comment.ID,
+			"ParentURL":      parentURL,
+			"Comments":       threads,
+			csrf.TemplateTag: csrf.TemplateField(r),
+		})
+	})
+}
+
+// HandleCommentReplies renders the replies to a comment with their threads, for htmx to continue a thread in place.
+// Other requests are sent to the page of the thread.
+func (h *Handler) HandleCommentReplies() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sort, _, ok := parseCommentsQuery(w, r)
+		if !ok {
+			return
+		}
+
+		comment, ok := h.findPostComment(w, r)
+		if !ok {
+			return
+		}
+
+		if r.Header.Get(htmxRequestHeader) != htmxRequestValueTrue {
+			http.Redirect(w, r, commentThreadPath(comment.PostID, comment.ID)+commentsQuery(sort, ""), http.StatusSeeOther)
+
+			return
+		}
+
+		replies, err := h.discussSvc.ListComments(r.Context(), discuss.ListCommentsRequest{
+			PostID:   comment.PostID,
+			TopLevel: false,
+			ReplyTo:  []string{comment.ID},
+			Sort:     sort,
+			After:    nil,
+			Limit:    0,
+		})
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to list replies", "commentId", comment.ID, "error", err)
+			http.Error(w, "Failed to list replies", http.StatusInternalServerError)
+
+			return
+		}
+
+		threads, err := h.loadCommentThreads(r.Context(), replies, sort, "/p/"+comment.PostID, csrf.TemplateField(r))
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to load comment threads", "commentId", comment.ID, "error", err)
+			http.Error(w, "Failed to load replies", http.StatusInternalServerError)
+
+			return
+		}
+
+		h.renderTemplate(w, r, "comments-page.gohtml", map[string]any{
+			"Comments":     threads,
+			"MoreComments": nil,
+		})
+	})
+}
+
+// findPostComment finds the comment of the path, writing a not found error when it is not on the post of the path.
+func (h *Handler) findPostComment(w http.ResponseWriter, r *http.Request) (*discuss.Comment, bool) {
+	postID := r.PathValue("postId")
+	commentID := r.PathValue("commentId")
+
+	comment, err := h.discussSvc.GetComment(r.Context(), commentID)
+	if err != nil {
+		if _, ok := errors.AsType[discuss.CommentNotFoundError](err); ok {
+			http.Error(w, "Comment not found", http.StatusNotFound)
+
+			return nil, false
+		}
+
+		if _, ok := errors.AsType[*authorization.AccessDeniedError](err); ok {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+
+			return nil, false
+		}
+
+		slog.ErrorContext(r.Context(), "failed to get comment", "commentId", commentID, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+		return nil, false
+	}
+
+	if comment.PostID != postID {
+		http.Error(w, "Comment not found", http.StatusNotFound)
+
+		return nil, false
+	}
+
+	return comment, true
+}
+
+// HandleEditCommentForm renders the form to edit a comment, for htmx to show under it. Other requests get a page of its
+// own.
+func (h *Handler) HandleEditCommentForm() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		comment, ok := h.findPostComment(w, r)
+		if !ok {
+			return
+		}
+
+		if comment.IsDeleted() {
+			http.Error(w, "Comment is deleted", http.StatusConflict)
+
+			return
+		}
+
+		check := commentEditCheck(r.Context(), comment)
+		if !h.authzClient.CanI(r.Context(), discuss.ServiceName, check.Object, check.Action) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+
+			return
+		}
+
+		inline := r.Header.Get(htmxRequestHeader) == htmxRequestValueTrue
+
+		data := map[string]any{
+			csrf.TemplateTag: csrf.TemplateField(r),
+			"PostID":         comment.PostID,
+			"CommentID":      comment.ID,
+			"Content":        comment.Content,
+			"Inline":         inline,
+			"SiteTitle":      "Edit comment",
+		}
+
+		if inline {
+			h.renderTemplate(w, r, "comment-edit-form.gohtml", data)
+
+			return
+		}
+
+		h.renderTemplate(w, r, "comment-edit-page.gohtml", data)
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+// HandleEditComment saves the edited content of a comment. htmx requests get the updated comment to swap in place,
+// others are sent back to the comment on the page of the post.
+func (h *Handler) HandleEditComment() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to parse form", "error", err)
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+
+			return
+		}
+
+		content := r.FormValue("comment")
+		if strings.TrimSpace(content) == "" {
+			http.Error(w, "Comment is required", http.StatusBadRequest)
+
+			return
+		}
+
+		comment, ok := h.findPostComment(w, r)
+		if !ok {
+			return
+		}
+
+		comment, _, err = h.discussSvc.UpdateComment(r.Context(), discuss.UpdateCommentRequest{
+			CommentID: comment.ID,
+			Content:   content,
+		})
+		if err != nil {
+			writeCommentChangeError(w, r, "failed to update comment", err)
+
+			return
+		}
+
+		h.respondCommentChanged(w, r, comment)
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+// HandleDeleteComment deletes a comment, leaving a tombstone in its place for its replies. htmx requests get the
+// tombstone to swap in place, others are sent back to it on the page of the post.
+func (h *Handler) HandleDeleteComment() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		comment, ok := h.findPostComment(w, r)
+		if !ok {
+			return
+		}
+
+		comment, err := h.discussSvc.DeleteComment(r.Context(), comment.ID)
+		if err != nil {
+			writeCommentChangeError(w, r, "failed to delete comment", err)
+
+			return
+		}
+
+		h.respondCommentChanged(w, r, comment)
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+// HandleCommentEditsPage shows the previous versions of an edited comment, newest first.
+func (h *Handler) HandleCommentEditsPage() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		comment, ok := h.findPostComment(w, r)
+		if !ok {
+			return
+		}
+
+		edits, err := h.discussSvc.ListCommentEdits(r.Context(), comment.ID)
+		if err != nil {
+			if _, ok := errors.AsType[*authorization.AccessDeniedError](err); ok {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+
+				return
+			}
+
+			slog.ErrorContext(r.Context(), "failed to list comment edits", "commentId", comment.ID, "error", err)
+			http.Error(w, "Failed to list comment edits", http.StatusInternalServerError)
+
+			return
+		}
+
+		h.renderTemplate(w, r, "comment-edits-page.gohtml", map[string]any{
+			"SiteTitle": "Edit history",
+			"Comment":   comment,
+			"Edits":     edits,
+		})
+	})
+}
+
+// respondCommentChanged renders the changed comment for htmx to swap in place of the old one, closing its edit form.
+// Other requests are redirected to the comment.
+func (h *Handler) respondCommentChanged(w http.ResponseWriter, r *http.Request, comment *discuss.Comment) {
+	if r.Header.Get(htmxRequestHeader) != htmxRequestValueTrue {
+		http.Redirect(w, r, "/p/"+comment.PostID+"#comment-"+comment.ID, http.StatusSeeOther)
+
+		return
+	}
+
+	loaded, err := h.loadCommentWithAuthor(r.Context(), comment, "/p/"+comment.PostID, csrf.TemplateField(r))
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to load comment with author", "commentId", comment.ID, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+		return
+	}
+
+	err = h.preloadCommentCapabilities(r.Context(), []*CommentWithAuthor{loaded})
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to load comment capabilities", "commentId", comment.ID, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+		return
+	}
+
+	h.renderTemplate(w, r, "comment-updated.gohtml", map[string]any{"Comment": loaded})
+}
+
+// writeCommentChangeError writes the error of editing or deleting a comment.
+func writeCommentChangeError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	if _, ok := errors.AsType[*authorization.AccessDeniedError](err); ok {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+
+		return
+	}
+
+	if _, ok := errors.AsType[discuss.CommentDeletedError](err); ok {
+		http.Error(w, "Comment is deleted", http.StatusConflict)
+
+		return
+	}
+
+	if _, ok := errors.AsType[discuss.CommentNotFoundError](err); ok {
+		http.Error(w, "Comment not found", http.StatusNotFound)
+
+		return
+	}
+
+	slog.ErrorContext(r.Context(), msg, "error", err)
+	http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+}
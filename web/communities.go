@@ -0,0 +1,449 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/csrf"
+	"github.com/nasermirzaei89/scribble/authentication"
+	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/communities"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/reactions"
+)
+
+type MemberWithUser struct {
+	communities.Member
+
+	User *authentication.User
+	Can  MemberCapabilities
+}
+
+// MemberCapabilities tells templates which actions the current user may perform on a community member.
+type MemberCapabilities struct {
+	Promote bool
+	Demote  bool
+	Remove  bool
+}
+
+func handleCommunityError(w http.ResponseWriter, r *http.Request, err error) {
+	if _, ok := errors.AsType[*authorization.AccessDeniedError](err); ok {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+
+		return
+	}
+
+	var (
+		communityNotFoundErr       communities.CommunityNotFoundError
+		communityBySlugNotFoundErr communities.CommunityBySlugNotFoundError
+		communityAlreadyExistsErr  communities.CommunityAlreadyExistsError
+		invalidSlugErr             communities.InvalidSlugError
+		invalidNameErr             communities.InvalidNameError
+		memberNotFoundErr          communities.MemberNotFoundError
+		alreadyMemberErr           communities.AlreadyMemberError
+		invalidRoleErr             communities.InvalidRoleError
+		ownerMembershipErr         communities.OwnerMembershipError
+	)
+
+	switch {
+	case errors.As(err, &communityNotFoundErr), errors.As(err, &communityBySlugNotFoundErr):
+		http.Error(w, "Community not found", http.StatusNotFound)
+	case errors.As(err, &memberNotFoundErr):
+		http.Error(w, "Member not found", http.StatusNotFound)
+	case errors.As(err, &communityAlreadyExistsErr):
+		http.Error(w, "Community already exists", http.StatusConflict)
+	case errors.As(err, &alreadyMemberErr):
+		http.Error(w, "Already a member", http.StatusConflict)
+	case errors.As(err, &invalidSlugErr):
+		http.Error(w, "Invalid community slug", http.StatusBadRequest)
+	case errors.As(err, &invalidNameErr):
+		http.Error(w, "Invalid community name", http.StatusBadRequest)
+	case errors.As(err, &invalidRoleErr):
+		http.Error(w, "Invalid role", http.StatusBadRequest)
+	case errors.As(err, &ownerMembershipErr):
+		http.Error(w, "The owner's membership cannot be changed", http.StatusBadRequest)
+	default:
+		slog.ErrorContext(r.Context(), "failed to handle community request", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) HandleCommunitiesPage() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		list, err := h.communitiesSvc.ListCommunities(r.Context())
+		if err != nil {
+			handleCommunityError(w, r, err)
+
+			return
+		}
+
+		data := map[string]any{
+			"SiteTitle":   "Communities",
+			"Communities": list,
+			"CanCreate": h.authzClient.CanI(
+				r.Context(),
+				communities.ServiceName,
+				"",
+				communities.ActionCreateCommunity,
+			),
+		}
+
+		h.renderTemplate(w, r, "communities-page.gohtml", data)
+	})
+
+	return hf
+}
+
+func (h *Handler) HandleCreateCommunityPage() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data := map[string]any{
+			csrf.TemplateTag: csrf.TemplateField(r),
+			"SiteTitle":      "Create Community",
+		}
+
+		h.renderTemplate(w, r, "create-community-page.gohtml", data)
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+func (h *Handler) HandleCreateCommunity() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to parse form", "error", err)
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+
+			return
+		}
+
+		currentUser, err := h.authSvc.GetCurrentUser(r.Context())
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to get current user", "error", err)
+			http.Error(w, "Failed to get current user", http.StatusInternalServerError)
+
+			return
+		}
+
+		community, err := h.communitiesSvc.CreateCommunity(r.Context(), communities.CreateCommunityRequest{
+			OwnerID:     currentUser.ID,
+			Slug:        r.FormValue("slug"),
+			Name:        r.FormValue("name"),
+			Description: r.FormValue("description"),
+		})
+		if err != nil {
+			handleCommunityError(w, r, err)
+
+			return
+		}
+
+		http.Redirect(w, r, "/c/"+community.Slug, http.StatusSeeOther)
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+// currentMember returns the membership of the current user in the community, or nil if they are not a member.
+func (h *Handler) currentMember(ctx context.Context, communityID string) (*communities.Member, error) {
+	if !isAuthenticated(ctx) {
+		return nil, nil //nolint:nilnil
+	}
+
+	currentUser, err := h.authSvc.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+
+	member, err := h.communitiesSvc.GetMember(ctx, communityID, currentUser.ID)
+	if err != nil {
+		if _, ok := errors.AsType[communities.MemberNotFoundError](err); ok {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, fmt.Errorf("failed to get member: %w", err)
+	}
+
+	return member, nil
+}
+
+func (h *Handler) HandleCommunityPage() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		community, err := h.communitiesSvc.GetCommunityBySlug(r.Context(), r.PathValue("slug"))
+		if err != nil {
+			handleCommunityError(w, r, err)
+
+			return
+		}
+
+		returnTo := "/c/" + community.Slug
+
+		posts, err := h.contentsSvc.ListPosts(r.Context(), contents.ListPostsRequest{CommunityID: community.ID})
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to list community posts", "communityId", community.ID, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+			return
+		}
+
+		postsWithAuthors, err := h.preloadPostAuthor(r.Context(), posts, returnTo, csrf.TemplateField(r))
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to preload post authors", "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+			return
+		}
+
+		member, err := h.currentMember(r.Context(), community.ID)
+		if err != nil {
+			handleCommunityError(w, r, err)
+
+			return
+		}
+
+		domain := communities.Domain(community.ID)
+
+		data := map[string]any{
+			"SiteTitle":  community.Name,
+			"Community":  community,
+			"Posts":      postsWithAuthors,
+			"Membership": member,
+			"CanJoin": member == nil && h.authzClient.CanI(
+				r.Context(),
+				communities.ServiceName,
+				community.ID,
+				communities.ActionJoinCommunity,
+			),
+			"CanLeave": member != nil && member.Role != communities.RoleOwner &&
+				h.authzClient.CanI(r.Context(), domain, "", communities.ActionLeaveCommunity),
+			"CanPost":        h.authzClient.CanI(r.Context(), domain, "", contents.ActionCreatePost),
+			csrf.TemplateTag: csrf.TemplateField(r),
+		}
+
+		if h.canManageCommunityReactionSet(r.Context(), community) {
+			data["ReactionSetForm"], err = h.reactionSetForm(
+				r.Context(),
+				reactions.SetScopeCommunity,
+				community.ID,
+				"/c/"+community.Slug+"/reactions",
+				[]string{},
+				csrf.TemplateField(r),
+			)
+			if err != nil {
+				handleReactionSetError(w, r, err)
+
+				return
+			}
+		}
+
+		h.renderTemplate(w, r, "community-page.gohtml", data)
+	})
+
+	return hf
+}
+
+func (h *Handler) HandleJoinCommunity() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		community, err := h.communitiesSvc.GetCommunityBySlug(r.Context(), r.PathValue("slug"))
+		if err != nil {
+			handleCommunityError(w, r, err)
+
+			return
+		}
+
+		currentUser, err := h.authSvc.GetCurrentUser(r.Context())
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to get current user", "error", err)
+			http.Error(w, "Failed to get current user", http.StatusInternalServerError)
+
+			return
+		}
+
+		_, err = h.communitiesSvc.JoinCommunity(r.Context(), community.ID, currentUser.ID)
+		if err != nil {
+			handleCommunityError(w, r, err)
+
+			return
+		}
+
+		http.Redirect(w, r, "/c/"+community.Slug, http.StatusSeeOther)
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+func (h *Handler) HandleLeaveCommunity() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		community, err := h.communitiesSvc.GetCommunityBySlug(r.Context(), r.PathValue("slug"))
+		if err != nil {
+			handleCommunityError(w, r, err)
+
+			return
+		}
+
+		currentUser, err := h.authSvc.GetCurrentUser(r.Context())
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to get current user", "error", err)
+			http.Error(w, "Failed to get current user", http.StatusInternalServerError)
+
+			return
+		}
+
+		err = h.communitiesSvc.LeaveCommunity(r.Context(), community.ID, currentUser.ID)
+		if err != nil {
+			handleCommunityError(w, r, err)
+
+			return
+		}
+
+		http.Redirect(w, r, "/c/"+community.Slug, http.StatusSeeOther)
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+func (h *Handler) HandleCommunityMembersPage() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		community, err := h.communitiesSvc.GetCommunityBySlug(r.Context(), r.PathValue("slug"))
+		if err != nil {
+			handleCommunityError(w, r, err)
+
+			return
+		}
+
+		members, err := h.communitiesSvc.ListMembers(r.Context(), community.ID)
+		if err != nil {
+			handleCommunityError(w, r, err)
+
+			return
+		}
+
+		membersWithUsers, err := h.preloadMemberUsers(r.Context(), community.ID, members)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to preload member users", "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+			return
+		}
+
+		data := map[string]any{
+			"SiteTitle":      community.Name + " Members",
+			"Community":      community,
+			"Members":        membersWithUsers,
+			csrf.TemplateTag: csrf.TemplateField(r),
+		}
+
+		h.renderTemplate(w, r, "community-members-page.gohtml", data)
+	})
+
+	return hf
+}
+
+// memberCapabilityChecks mirror the permissions communities.AuthorizationMiddleware enforces on member management.
+var memberCapabilityChecks = []authorization.ObjectAction{
+	{Object: string(communities.RoleModerator), Action: communities.ActionSetMemberRole},
+	{Object: string(communities.RoleMember), Action: communities.ActionSetMemberRole},
+	{Object: string(communities.RoleModerator), Action: communities.ActionRemoveMember},
+	{Object: string(communities.RoleMember), Action: communities.ActionRemoveMember},
+}
+
+func (h *Handler) preloadMemberUsers(
+	ctx context.Context,
+	communityID string,
+	members []*communities.Member,
+) ([]*MemberWithUser, error) {
+	allowed, err := h.authzClient.CheckAccessBatch(ctx, communities.Domain(communityID), memberCapabilityChecks...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check member permissions: %w", err)
+	}
+
+	result := make([]*MemberWithUser, 0, len(members))
+
+	// TODO: optimize this by batching user retrieval instead of doing it one by one
+	for _, member := range members {
+		user, err := h.authSvc.GetUser(ctx, member.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+
+		result = append(result, &MemberWithUser{
+			Member: *member,
+			User:   user,
+			Can: MemberCapabilities{
+				Promote: member.Role == communities.RoleMember && allowed[authorization.ObjectAction{
+					Object: string(communities.RoleModerator),
+					Action: communities.ActionSetMemberRole,
+				}],
+				Demote: member.Role == communities.RoleModerator && allowed[authorization.ObjectAction{
+					Object: string(communities.RoleMember),
+					Action: communities.ActionSetMemberRole,
+				}],
+				Remove: member.Role != communities.RoleOwner && allowed[authorization.ObjectAction{
+					Object: string(member.Role),
+					Action: communities.ActionRemoveMember,
+				}],
+			},
+		})
+	}
+
+	return result, nil
+}
+
+func (h *Handler) HandleSetMemberRole() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to parse form", "error", err)
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+
+			return
+		}
+
+		community, err := h.communitiesSvc.GetCommunityBySlug(r.Context(), r.PathValue("slug"))
+		if err != nil {
+			handleCommunityError(w, r, err)
+
+			return
+		}
+
+		_, err = h.communitiesSvc.SetMemberRole(
+			r.Context(),
+			community.ID,
+			r.PathValue("userId"),
+			communities.Role(r.FormValue("role")),
+		)
+		if err != nil {
+			handleCommunityError(w, r, err)
+
+			return
+		}
+
+		http.Redirect(w, r, "/c/"+community.Slug+"/members", http.StatusSeeOther)
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+func (h *Handler) HandleRemoveMember() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		community, err := h.communitiesSvc.GetCommunityBySlug(r.Context(), r.PathValue("slug"))
+		if err != nil {
+			handleCommunityError(w, r, err)
+
+			return
+		}
+
+		err = h.communitiesSvc.RemoveMember(r.Context(), community.ID, r.PathValue("userId"))
+		if err != nil {
+			handleCommunityError(w, r, err)
+
+			return
+		}
+
+		http.Redirect(w, r, "/c/"+community.Slug+"/members", http.StatusSeeOther)
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
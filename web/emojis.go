@@ -0,0 +1,217 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/csrf"
+	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/blobs"
+	"github.com/nasermirzaei89/scribble/emojis"
+)
+
+func handleEmojiError(w http.ResponseWriter, r *http.Request, err error) {
+	if _, ok := errors.AsType[*authorization.AccessDeniedError](err); ok {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+
+		return
+	}
+
+	var (
+		notFoundErr         emojis.CustomEmojiNotFoundError
+		blobNotFoundErr     blobs.BlobNotFoundError
+		existsErr           emojis.CustomEmojiExistsError
+		invalidShortcodeErr emojis.InvalidShortcodeError
+		invalidImageErr     emojis.InvalidImageError
+	)
+
+	switch {
+	case errors.As(err, &notFoundErr), errors.As(err, &blobNotFoundErr):
+		http.Error(w, "Custom emoji not found", http.StatusNotFound)
+	case errors.As(err, &existsErr):
+		http.Error(w, "A custom emoji with this shortcode already exists", http.StatusConflict)
+	case errors.As(err, &invalidShortcodeErr):
+		http.Error(
+			w,
+			"Shortcodes are 2 to 32 lowercase letters, digits, underscores, pluses or hyphens",
+			http.StatusBadRequest,
+		)
+	case errors.As(err, &invalidImageErr):
+		http.Error(w, "Invalid image: "+invalidImageErr.Reason, http.StatusBadRequest)
+	default:
+		slog.ErrorContext(r.Context(), "failed to handle custom emoji request", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+func emojiImagePath(shortcode string) string {
+	return "/emojis/" + url.PathEscape(shortcode)
+}
+
+// customEmojiResolver links the custom emojis content uses to their images. Markdown is rendered out of any request,
+// so emojis are looked up without one.
+type customEmojiResolver struct {
+	emojisSvc emojis.Service
+}
+
+func (resolver customEmojiResolver) ResolveEmoji(shortcode string) (string, bool) {
+	_, err := resolver.emojisSvc.GetCustomEmoji(context.Background(), shortcode)
+	if err != nil {
+		if _, ok := errors.AsType[emojis.CustomEmojiNotFoundError](err); !ok {
+			slog.Error("failed to get custom emoji", "shortcode", shortcode, "error", err)
+		}
+
+		return "", false
+	}
+
+	return emojiImagePath(shortcode), true
+}
+
+// renderEmoji renders an emoji of a reaction. Custom emojis are rendered as their images.
+func renderEmoji(emoji string) template.HTML {
+	shortcode, ok := emojis.ParseReference(emoji)
+	if !ok {
+		return template.HTML(template.HTMLEscapeString(emoji)) //nolint:gosec
+	}
+
+	reference := template.HTMLEscapeString(emojis.Reference(shortcode))
+
+	return template.HTML( //nolint:gosec
+		`<img src="` + template.HTMLEscapeString(emojiImagePath(shortcode)) + `" alt="` + reference + `" title="` +
+			reference + `" class="custom-emoji">`,
+	)
+}
+
+// HandleEmojiImage serves the image of a custom emoji. A deleted shortcode can be uploaded again with another image,
+// so images are cached only briefly.
+func (h *Handler) HandleEmojiImage() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		emoji, image, err := h.emojisSvc.GetCustomEmojiImage(r.Context(), r.PathValue("shortcode"))
+		if err != nil {
+			handleEmojiError(w, r, err)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", emoji.ContentType)
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+
+		_, err = w.Write(image)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to write custom emoji image", "error", err)
+		}
+	})
+}
+
+// HandleEmojisPage lists the custom emojis for admins to add and delete them. Everyone can list them for the picker,
+// so the page checks that the user can manage them.
+func (h *Handler) HandleEmojisPage() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !h.authzClient.CanI(r.Context(), emojis.ServiceName, "", emojis.ActionManageCustomEmojis) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+
+			return
+		}
+
+		customEmojis, err := h.emojisSvc.ListCustomEmojis(r.Context())
+		if err != nil {
+			handleEmojiError(w, r, err)
+
+			return
+		}
+
+		h.renderTemplate(w, r, "admin-emojis-page.gohtml", map[string]any{
+			"SiteTitle":      "Custom Emojis",
+			"Emojis":         customEmojis,
+			"ContentTypes":   strings.Join(emojis.ContentTypes(), ","),
+			"MaxImageSizeKB": emojis.MaxImageSize >> 10,
+			csrf.TemplateTag: csrf.TemplateField(r),
+		})
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+// emojiFormOverhead is how much the shortcode, the CSRF token and the multipart framing may add to the image.
+const emojiFormOverhead = 64 << 10
+
+// emojiUploadMiddleware limits the body of custom emoji uploads. It parses the form itself, since the CSRF check reads
+// the token from the form before the upload reaches its handler.
+func emojiUploadMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/admin/emojis" {
+			r.Body = http.MaxBytesReader(w, r.Body, emojis.MaxImageSize+emojiFormOverhead)
+
+			err := r.ParseMultipartForm(emojis.MaxImageSize + emojiFormOverhead)
+			if _, ok := errors.AsType[*http.MaxBytesError](err); ok {
+				http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h *Handler) HandleCreateEmoji() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("image")
+		if err != nil {
+			handleEmojiError(w, r, emojis.InvalidImageError{Reason: "no image"})
+
+			return
+		}
+
+		defer func() {
+			err := file.Close()
+			if err != nil {
+				slog.ErrorContext(r.Context(), "failed to close uploaded image", "error", err)
+			}
+		}()
+
+		// Reading a byte past the limit is enough for the service to reject larger images.
+		image, err := io.ReadAll(io.LimitReader(file, emojis.MaxImageSize+1))
+		if err != nil {
+			handleEmojiError(w, r, emojis.InvalidImageError{Reason: "unreadable"})
+
+			return
+		}
+
+		_, err = h.emojisSvc.CreateCustomEmoji(r.Context(), emojis.CreateCustomEmojiRequest{
+			Shortcode: strings.Trim(strings.TrimSpace(r.FormValue("shortcode")), ":"),
+			Image:     image,
+		})
+		if err != nil {
+			handleEmojiError(w, r, err)
+
+			return
+		}
+
+		http.Redirect(w, r, "/admin/emojis", http.StatusSeeOther)
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+func (h *Handler) HandleDeleteEmoji() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := h.emojisSvc.DeleteCustomEmoji(r.Context(), r.FormValue("shortcode"))
+		if err != nil {
+			handleEmojiError(w, r, err)
+
+			return
+		}
+
+		http.Redirect(w, r, "/admin/emojis", http.StatusSeeOther)
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
@@ -0,0 +1,293 @@
+package web
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/csrf"
+	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/discuss"
+	"github.com/nasermirzaei89/scribble/live"
+	"github.com/nasermirzaei89/scribble/reactions"
+)
+
+// liveKeepAliveInterval keeps idle event streams from being closed by proxies.
+const liveKeepAliveInterval = 30 * time.Second
+
+// liveStream is what a viewer follows with an event stream.
+type liveStream struct {
+	topics []string
+	// returnTo is the page the stream updates, for forms in rendered fragments.
+	returnTo string
+}
+
+// HandleEvents streams live updates of the home feed or a post as server-sent events. Each event is an HTML fragment
+// of htmx out of band swaps, rendered for the viewer, so pages apply it as is.
+func (h *Handler) HandleEvents() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stream, ok := h.openLiveStream(w, r)
+		if !ok {
+			return
+		}
+
+		sub := h.liveBroker.Subscribe(stream.topics...)
+		defer sub.Close()
+
+		rc := http.NewResponseController(w)
+
+		// The server's write timeout would otherwise cut the stream.
+		err := rc.SetWriteDeadline(time.Time{})
+		if err != nil && !errors.Is(err, http.ErrNotSupported) {
+			slog.ErrorContext(r.Context(), "failed to clear write deadline", "error", err)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		_ = rc.Flush()
+
+		ticker := time.NewTicker(liveKeepAliveInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-ticker.C:
+				_, err = io.WriteString(w, ": keep-alive\n\n")
+			case event, ok := <-sub.Events():
+				if !ok {
+					return
+				}
+
+				err = h.sendLiveEvent(w, r, stream, event)
+			}
+
+			if err != nil {
+				slog.DebugContext(r.Context(), "closing event stream", "error", err)
+
+				return
+			}
+
+			err = rc.Flush()
+			if err != nil {
+				return
+			}
+		}
+	})
+}
+
+// openLiveStream resolves the requested topic, making sure the viewer may see what it covers.
+func (h *Handler) openLiveStream(w http.ResponseWriter, r *http.Request) (*liveStream, bool) {
+	topic := r.URL.Query().Get("topic")
+
+	if topic == live.TopicHome {
+		if !h.authzClient.CanI(r.Context(), contents.ServiceName, "", contents.ActionListPosts) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+
+			return nil, false
+		}
+
+		return &liveStream{topics: []string{live.TopicHome}, returnTo: "/"}, true
+	}
+
+	postID, ok := strings.CutPrefix(topic, live.PostTopic(""))
+	if !ok || postID == "" {
+		http.Error(w, "Unknown topic", http.StatusBadRequest)
+
+		return nil, false
+	}
+
+	post, err := h.contentsSvc.GetPost(r.Context(), postID)
+	if err != nil {
+		if _, ok := errors.AsType[*authorization.AccessDeniedError](err); ok {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+
+			return nil, false
+		}
+
+		if _, ok := errors.AsType[contents.PostNotFoundError](err); ok {
+			http.Error(w, "Post not found", http.StatusNotFound)
+
+			return nil, false
+		}
+
+		slog.ErrorContext(r.Context(), "failed to get post", "postId", postID, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+		return nil, false
+	}
+
+	// Changes to the comments of the post, and reactions to them, come on the topic of the post.
+	return &liveStream{topics: []string{live.PostTopic(post.ID)}, returnTo: "/p/" + post.ID}, true
+}
+
+// sendLiveEvent writes the event if the viewer may see it. Failing to render one event does not end the stream.
+func (h *Handler) sendLiveEvent(w io.Writer, r *http.Request, stream *liveStream, event live.Event) error {
+	fragment, err := h.renderLiveEvent(r, stream, event)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to render live event", "type", event.Type, "error", err)
+
+		return nil
+	}
+
+	if fragment == "" {
+		return nil
+	}
+
+	return writeServerSentEvent(w, string(event.Type), fragment)
+}
+
+// renderLiveEvent renders the event for the viewer of the stream. It returns an empty fragment for events the viewer
+// may not see.
+func (h *Handler) renderLiveEvent(r *http.Request, stream *liveStream, event live.Event) (string, error) {
+	ctx := r.Context()
+
+	var (
+		name string
+		data map[string]any
+	)
+
+	switch event.Type {
+	case live.EventPostCreated:
+		visible, err := h.isPostVisible(r, event.Post.ID)
+		if err != nil || !visible {
+			return "", err
+		}
+
+		posts, err := h.preloadPostAuthor(ctx, []*contents.Post{event.Post}, stream.returnTo, csrf.TemplateField(r))
+		if err != nil {
+			return "", fmt.Errorf("failed to preload post author: %w", err)
+		}
+
+		name = "live-post-created.gohtml"
+		data = map[string]any{"Post": posts[0]}
+	case live.EventCommentCreated:
+		if !h.authzClient.CanI(ctx, discuss.ServiceName, "", discuss.ActionListComments) {
+			return "", nil
+		}
+
+		comment, err := h.loadCommentWithAuthor(ctx, event.Comment, stream.returnTo, csrf.TemplateField(r))
+		if err != nil {
+			return "", err
+		}
+
+		err = h.preloadCommentCapabilities(ctx, []*CommentWithAuthor{comment})
+		if err != nil {
+			return "", fmt.Errorf("failed to load comment capabilities: %w", err)
+		}
+
+		target := "#comment-list"
+		if comment.ReplyTo != nil {
+			target = "#replies-" + *comment.ReplyTo
+		}
+
+		name = "live-comment-created.gohtml"
+		data = map[string]any{"Comment": comment, "Target": target}
+	case live.EventCommentChanged:
+		if !h.authzClient.CanI(ctx, discuss.ServiceName, "", discuss.ActionListComments) {
+			return "", nil
+		}
+
+		comment, err := h.loadCommentWithAuthor(ctx, event.Comment, stream.returnTo, csrf.TemplateField(r))
+		if err != nil {
+			return "", err
+		}
+
+		err = h.preloadCommentCapabilities(ctx, []*CommentWithAuthor{comment})
+		if err != nil {
+			return "", fmt.Errorf("failed to load comment capabilities: %w", err)
+		}
+
+		comment.SwapOOB = true
+
+		name = "live-comment-changed.gohtml"
+		data = map[string]any{"Comment": comment}
+	case live.EventReactionsChanged:
+		// Anonymous viewers are not shown reaction counts.
+		if !isAuthenticated(ctx) {
+			return "", nil
+		}
+
+		if event.TargetType == reactions.TargetTypePost {
+			visible, err := h.isPostVisible(r, event.TargetID)
+			if err != nil || !visible {
+				return "", err
+			}
+		}
+
+		widgetData, err := h.buildReactionWidgetData(
+			ctx,
+			event.TargetType,
+			event.TargetID,
+			stream.returnTo,
+			csrf.TemplateField(r),
+		)
+		if err != nil {
+			return "", fmt.Errorf("failed to build reaction widget data: %w", err)
+		}
+
+		widgetData["SwapOOB"] = true
+
+		name = "reactions.gohtml"
+		data = widgetData
+	default:
+		return "", nil
+	}
+
+	var buf bytes.Buffer
+
+	err := h.tpl.ExecuteTemplate(&buf, name, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to render template %s: %w", name, err)
+	}
+
+	return buf.String(), nil
+}
+
+// isPostVisible tells whether the viewer may get the post.
+func (h *Handler) isPostVisible(r *http.Request, postID string) (bool, error) {
+	_, err := h.contentsSvc.GetPost(r.Context(), postID)
+	if err != nil {
+		if _, ok := errors.AsType[*authorization.AccessDeniedError](err); ok {
+			return false, nil
+		}
+
+		if _, ok := errors.AsType[contents.PostNotFoundError](err); ok {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to get post: %w", err)
+	}
+
+	return true, nil
+}
+
+// writeServerSentEvent writes an event in the text/event-stream format, splitting multi-line data into data fields.
+func writeServerSentEvent(w io.Writer, event, data string) error {
+	var buf strings.Builder
+
+	buf.WriteString("event: " + event + "\n")
+
+	for line := range strings.SplitSeq(data, "\n") {
+		buf.WriteString("data: " + strings.TrimSuffix(line, "\r") + "\n")
+	}
+
+	buf.WriteString("\n")
+
+	_, err := io.WriteString(w, buf.String())
+	if err != nil {
+		return fmt.Errorf("failed to write event: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,20 @@
+package web
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteServerSentEvent(t *testing.T) {
+	t.Parallel()
+
+	var buf strings.Builder
+
+	err := writeServerSentEvent(&buf, "comment-created", "<div>\r\n  <p>Hello</p>\n</div>")
+	require.NoError(t, err)
+
+	assert.Equal(t, "event: comment-created\ndata: <div>\ndata:   <p>Hello</p>\ndata: </div>\n\n", buf.String())
+}
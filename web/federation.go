@@ -0,0 +1,227 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/csrf"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/federation"
+)
+
+const (
+	federationMaxBodySize = 1 << 20
+
+	jrdContentType = "application/jrd+json"
+)
+
+func (h *Handler) registerFederationRoutes() {
+	h.mux.Handle("GET /.well-known/webfinger", h.HandleWebFinger())
+	h.mux.Handle("GET /users/{username}", h.HandleActor())
+	h.mux.Handle("GET /users/{username}/outbox", h.HandleOutbox())
+	h.mux.Handle("GET /users/{username}/followers", h.HandleFollowers())
+	h.mux.Handle("POST /users/{username}/inbox", h.HandleInbox())
+	h.mux.Handle("POST /inbox", h.HandleInbox())
+}
+
+// isInboxRequest reports whether the request posts an activity to an inbox.
+func isInboxRequest(r *http.Request) bool {
+	if r.Method != http.MethodPost {
+		return false
+	}
+
+	if r.URL.Path == "/inbox" {
+		return true
+	}
+
+	rest, ok := strings.CutPrefix(r.URL.Path, "/users/")
+
+	return ok && strings.Count(rest, "/") == 1 && strings.HasSuffix(rest, "/inbox")
+}
+
+// federationMiddleware exempts inbox requests from CSRF protection, since other servers post to them and the HTTP
+// signature already proves the sender. It must wrap the CSRF middleware.
+func federationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isInboxRequest(r) {
+			r = csrf.UnsafeSkipCheck(r)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// wantsActivity reports whether the request asks for the ActivityStreams representation of a page.
+func wantsActivity(r *http.Request) bool {
+	for accepted := range strings.SplitSeq(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil {
+			continue
+		}
+
+		if mediaType == federation.ContentType ||
+			(mediaType == "application/ld+json" && strings.Contains(params["profile"], "activitystreams")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// negotiateActivity serves the ActivityStreams representation of a page to servers asking for it, and the page to
+// everyone else.
+func negotiateActivity(activity, page http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+
+		if wantsActivity(r) {
+			activity.ServeHTTP(w, r)
+
+			return
+		}
+
+		page.ServeHTTP(w, r)
+	})
+}
+
+func writeFederationJSON(w http.ResponseWriter, contentType string, body any) {
+	w.Header().Set("Content-Type", contentType+"; charset=utf-8")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	err := json.NewEncoder(w).Encode(body)
+	if err != nil {
+		slog.Error("failed to encode federation response", "error", err)
+	}
+}
+
+// handleFederationError maps federation errors to statuses other servers understand.
+func handleFederationError(w http.ResponseWriter, r *http.Request, err error) {
+	if _, ok := errors.AsType[federation.ActorNotFoundError](err); ok {
+		http.Error(w, "Not Found", http.StatusNotFound)
+
+		return
+	}
+
+	if _, ok := errors.AsType[contents.PostNotFoundError](err); ok {
+		http.Error(w, "Not Found", http.StatusNotFound)
+
+		return
+	}
+
+	if invalidSignatureErr, ok := errors.AsType[federation.InvalidSignatureError](err); ok {
+		// The reason may tell about the servers this one reached, so only the logs get it.
+		slog.InfoContext(r.Context(), "rejected federation request", "path", r.URL.Path, "error", invalidSignatureErr)
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+
+		return
+	}
+
+	if invalidActivityErr, ok := errors.AsType[federation.InvalidActivityError](err); ok {
+		http.Error(w, invalidActivityErr.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	slog.ErrorContext(r.Context(), "failed to handle federation request", "path", r.URL.Path, "error", err)
+	http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+}
+
+func (h *Handler) HandleWebFinger() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resource := r.URL.Query().Get("resource")
+		if resource == "" {
+			http.Error(w, "Missing resource", http.StatusBadRequest)
+
+			return
+		}
+
+		webFinger, err := h.federationSvc.WebFinger(r.Context(), resource)
+		if err != nil {
+			handleFederationError(w, r, err)
+
+			return
+		}
+
+		writeFederationJSON(w, jrdContentType, webFinger)
+	})
+}
+
+func (h *Handler) HandleActor() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor, err := h.federationSvc.Actor(r.Context(), r.PathValue("username"))
+		if err != nil {
+			handleFederationError(w, r, err)
+
+			return
+		}
+
+		writeFederationJSON(w, federation.ContentType, actor)
+	})
+}
+
+func (h *Handler) HandleOutbox() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		outbox, err := h.federationSvc.Outbox(r.Context(), r.PathValue("username"))
+		if err != nil {
+			handleFederationError(w, r, err)
+
+			return
+		}
+
+		writeFederationJSON(w, federation.ContentType, outbox)
+	})
+}
+
+func (h *Handler) HandleFollowers() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		followers, err := h.federationSvc.Followers(r.Context(), r.PathValue("username"))
+		if err != nil {
+			handleFederationError(w, r, err)
+
+			return
+		}
+
+		writeFederationJSON(w, federation.ContentType, followers)
+	})
+}
+
+// HandleNote serves a post as a note, for servers resolving the post URL.
+func (h *Handler) HandleNote() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		note, err := h.federationSvc.Note(r.Context(), r.PathValue("postId"))
+		if err != nil {
+			handleFederationError(w, r, err)
+
+			return
+		}
+
+		writeFederationJSON(w, federation.ContentType, note)
+	})
+}
+
+// HandleInbox accepts an activity posted by another server. The shared inbox and the inboxes of all users are the
+// same, since activities name their recipients themselves.
+func (h *Handler) HandleInbox() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, federationMaxBodySize))
+		if err != nil {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+
+			return
+		}
+
+		err = h.federationSvc.ReceiveActivity(r.Context(), r, body)
+		if err != nil {
+			handleFederationError(w, r, err)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
@@ -0,0 +1,53 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWantsActivity(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		accept   string
+		expected bool
+	}{
+		{accept: "application/activity+json", expected: true},
+		{accept: `application/ld+json; profile="https://www.w3.org/ns/activitystreams"`, expected: true},
+		{accept: "text/html, application/activity+json;q=0.9", expected: true},
+		{accept: "application/ld+json", expected: false},
+		{accept: "text/html,application/xhtml+xml,*/*;q=0.8", expected: false},
+		{accept: "", expected: false},
+	}
+
+	for _, tc := range tt {
+		r := httptest.NewRequest(http.MethodGet, "/p/post1", nil)
+		r.Header.Set("Accept", tc.accept)
+
+		assert.Equal(t, tc.expected, wantsActivity(r), tc.accept)
+	}
+}
+
+func TestIsInboxRequest(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		method   string
+		path     string
+		expected bool
+	}{
+		{method: http.MethodPost, path: "/inbox", expected: true},
+		{method: http.MethodPost, path: "/users/alice/inbox", expected: true},
+		{method: http.MethodGet, path: "/inbox", expected: false},
+		{method: http.MethodPost, path: "/users/alice/outbox", expected: false},
+		{method: http.MethodPost, path: "/users/alice/x/inbox", expected: false},
+		{method: http.MethodPost, path: "/create-post", expected: false},
+	}
+
+	for _, tc := range tt {
+		assert.Equal(t, tc.expected, isInboxRequest(httptest.NewRequest(tc.method, tc.path, nil)), tc.method+" "+tc.path)
+	}
+}
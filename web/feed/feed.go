@@ -0,0 +1,85 @@
+// Package feed encodes syndication feeds as RSS 2.0 and Atom 1.0 from one format independent description.
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+type Feed struct {
+	// ID identifies the feed permanently. The URL of the page the feed follows is a good choice.
+	ID       string
+	Title    string
+	Subtitle string
+	// Link is the URL of the page the feed follows.
+	Link string
+	// SelfLink is the URL the feed is served at.
+	SelfLink string
+	// Updated is when the feed last changed. Zero means the newest entry's update time.
+	Updated time.Time
+	Entries []Entry
+}
+
+type Entry struct {
+	ID     string
+	Title  string
+	Link   string
+	Author string
+	// Content is the HTML of the entry.
+	Content   string
+	Published time.Time
+	Updated   time.Time
+}
+
+// LastModified returns when the feed last changed.
+func (f *Feed) LastModified() time.Time {
+	updated := f.Updated
+
+	for _, entry := range f.Entries {
+		if entry.Updated.After(updated) {
+			updated = entry.Updated
+		}
+	}
+
+	return updated
+}
+
+const (
+	rssContentType  = "application/rss+xml; charset=utf-8"
+	atomContentType = "application/atom+xml; charset=utf-8"
+)
+
+// Format is a feed document format.
+type Format interface {
+	ContentType() string
+	Encode(w io.Writer, f *Feed) error
+}
+
+var (
+	RSS  Format = rssFormat{}
+	Atom Format = atomFormat{}
+)
+
+func encodeXML(w io.Writer, document any) error {
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return fmt.Errorf("failed to write xml header: %w", err)
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	err = encoder.Encode(document)
+	if err != nil {
+		return fmt.Errorf("failed to encode feed: %w", err)
+	}
+
+	err = encoder.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close xml encoder: %w", err)
+	}
+
+	return nil
+}
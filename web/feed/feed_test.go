@@ -0,0 +1,130 @@
+package feed_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/web/feed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFeed() *feed.Feed {
+	return &feed.Feed{
+		ID:       "https://example.com/",
+		Title:    "Scribble",
+		Subtitle: "Latest posts",
+		Link:     "https://example.com/",
+		SelfLink: "https://example.com/feed.atom",
+		Updated:  time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+		Entries: []feed.Entry{
+			{
+				ID:        "https://example.com/p/post2",
+				Title:     "Second & last",
+				Link:      "https://example.com/p/post2",
+				Author:    "alice",
+				Content:   "<p>Hello <strong>world</strong></p>",
+				Published: time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC),
+				Updated:   time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC),
+			},
+			{
+				ID:        "https://example.com/p/post1",
+				Title:     "First",
+				Link:      "https://example.com/p/post1",
+				Author:    "bob",
+				Content:   "<p>First</p>",
+				Published: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+				Updated:   time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+}
+
+func TestLastModified(t *testing.T) {
+	t.Parallel()
+
+	f := testFeed()
+	assert.Equal(t, time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC), f.LastModified())
+
+	f.Entries = nil
+	assert.Equal(t, time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC), f.LastModified())
+}
+
+func TestRSS(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	err := feed.RSS.Encode(&buf, testFeed())
+	require.NoError(t, err)
+
+	var document struct {
+		Version string `xml:"version,attr"`
+		Channel struct {
+			Title         string `xml:"title"`
+			LastBuildDate string `xml:"lastBuildDate"`
+			Items         []struct {
+				Title       string `xml:"title"`
+				GUID        string `xml:"guid"`
+				Creator     string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+				PubDate     string `xml:"pubDate"`
+				Description string `xml:"description"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+
+	err = xml.Unmarshal(buf.Bytes(), &document)
+	require.NoError(t, err)
+
+	assert.Equal(t, "2.0", document.Version)
+	assert.Equal(t, "Scribble", document.Channel.Title)
+	assert.Equal(t, "Mon, 02 Mar 2026 10:00:00 +0000", document.Channel.LastBuildDate)
+	require.Len(t, document.Channel.Items, 2)
+	assert.Equal(t, "Second & last", document.Channel.Items[0].Title)
+	assert.Equal(t, "https://example.com/p/post2", document.Channel.Items[0].GUID)
+	assert.Equal(t, "alice", document.Channel.Items[0].Creator)
+	assert.Equal(t, "<p>Hello <strong>world</strong></p>", document.Channel.Items[0].Description)
+}
+
+func TestAtom(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	err := feed.Atom.Encode(&buf, testFeed())
+	require.NoError(t, err)
+
+	var document struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		ID      string   `xml:"id"`
+		Updated string   `xml:"updated"`
+		Links   []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Entries []struct {
+			ID      string `xml:"id"`
+			Author  string `xml:"author>name"`
+			Updated string `xml:"updated"`
+			Content struct {
+				Type  string `xml:"type,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"content"`
+		} `xml:"entry"`
+	}
+
+	err = xml.Unmarshal(buf.Bytes(), &document)
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://example.com/", document.ID)
+	assert.Equal(t, "2026-03-02T10:00:00Z", document.Updated)
+	require.Len(t, document.Links, 2)
+	assert.Equal(t, "self", document.Links[1].Rel)
+	assert.Equal(t, "https://example.com/feed.atom", document.Links[1].Href)
+	require.Len(t, document.Entries, 2)
+	assert.Equal(t, "bob", document.Entries[1].Author)
+	assert.Equal(t, "html", document.Entries[0].Content.Type)
+	assert.Equal(t, "<p>Hello <strong>world</strong></p>", document.Entries[0].Content.Value)
+}
@@ -0,0 +1,93 @@
+package feed
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+type rssFormat struct{}
+
+func (rssFormat) ContentType() string {
+	return rssContentType
+}
+
+type rssDocument struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	AtomNS    string     `xml:"xmlns:atom,attr"`
+	DCNS      string     `xml:"xmlns:dc,attr"`
+	ContentNS string     `xml:"xmlns:content,attr"`
+	Channel   rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	SelfLink      rssLink   `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+type rssItem struct {
+	Title   string  `xml:"title"`
+	Link    string  `xml:"link"`
+	GUID    rssGUID `xml:"guid"`
+	Creator string  `xml:"dc:creator,omitempty"`
+	PubDate string  `xml:"pubDate"`
+	// Description holds the HTML too, since not every reader supports content:encoded.
+	Description string `xml:"description"`
+	Content     string `xml:"content:encoded"`
+}
+
+func rssDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC1123Z)
+}
+
+func (rssFormat) Encode(w io.Writer, f *Feed) error {
+	document := rssDocument{
+		XMLName:   xml.Name{Space: "", Local: "rss"},
+		Version:   "2.0",
+		AtomNS:    "http://www.w3.org/2005/Atom",
+		DCNS:      "http://purl.org/dc/elements/1.1/",
+		ContentNS: "http://purl.org/rss/1.0/modules/content/",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.Link,
+			Description:   f.Subtitle,
+			SelfLink:      rssLink{Href: f.SelfLink, Rel: "self", Type: rssContentType},
+			LastBuildDate: rssDate(f.LastModified()),
+			Items:         make([]rssItem, 0, len(f.Entries)),
+		},
+	}
+
+	for _, entry := range f.Entries {
+		document.Channel.Items = append(document.Channel.Items, rssItem{
+			Title:       entry.Title,
+			Link:        entry.Link,
+			GUID:        rssGUID{Value: entry.ID, IsPermaLink: entry.ID == entry.Link},
+			Creator:     entry.Author,
+			PubDate:     rssDate(entry.Published),
+			Description: entry.Content,
+			Content:     entry.Content,
+		})
+	}
+
+	return encodeXML(w, document)
+}
@@ -0,0 +1,385 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nasermirzaei89/scribble/authentication"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/discuss"
+	"github.com/nasermirzaei89/scribble/web/feed"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+const (
+	feedSize = 50
+
+	feedEntryTitleLength = 80
+)
+
+// FeedLink is a feed announced with <link rel="alternate"> on the pages it follows.
+type FeedLink struct {
+	Title string
+	Type  string
+	URL   string
+}
+
+// feedLinks returns the RSS and Atom links of the feed served at the path without its extension.
+func feedLinks(title, path string) []FeedLink {
+	return []FeedLink{
+		{Title: title + " (RSS)", Type: "application/rss+xml", URL: path + ".xml"},
+		{Title: title + " (Atom)", Type: "application/atom+xml", URL: path + ".atom"},
+	}
+}
+
+func siteFeedLinks() []FeedLink {
+	return feedLinks(defaultSiteTitle, "/feed")
+}
+
+func userFeedPath(username string) string {
+	return "/u/" + url.PathEscape(username) + "/feed"
+}
+
+func commentsFeedPath(postID string) string {
+	return "/p/" + postID + "/comments/feed"
+}
+
+// absoluteURL resolves the path against the base URL of the site, since feed readers show entries away from it. The
+// Host header of the request is up to the client, so it is not used.
+func (h *Handler) absoluteURL(path string) string {
+	return h.baseURL + path
+}
+
+// feedEntryTitle derives a plain text title from the first block of the Markdown content, since posts and comments
+// have none.
+func (h *Handler) feedEntryTitle(content string) string {
+	source := []byte(content)
+	doc := h.feedMarkdown.Parser().Parse(text.NewReader(source))
+
+	for block := doc.FirstChild(); block != nil; block = block.NextSibling() {
+		title := strings.Join(strings.Fields(markdownText(block, source)), " ")
+		if title == "" {
+			continue
+		}
+
+		if utf8.RuneCountInString(title) > feedEntryTitleLength {
+			title = string([]rune(title)[:feedEntryTitleLength-1]) + "…"
+		}
+
+		return title
+	}
+
+	return "Untitled"
+}
+
+// markdownText returns the text of the node without markup. Raw HTML is left out.
+func markdownText(node ast.Node, source []byte) string {
+	var buf strings.Builder
+
+	_ = ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n := n.(type) {
+		case *ast.Text:
+			buf.Write(n.Value(source))
+
+			if n.SoftLineBreak() || n.HardLineBreak() {
+				buf.WriteByte(' ')
+			}
+		case *ast.String:
+			buf.Write(n.Value)
+		case *ast.AutoLink:
+			buf.Write(n.Label(source))
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return buf.String()
+}
+
+// renderFeedContent renders Markdown without raw HTML, which the site allows but feed readers should not be trusted
+// to sanitize.
+func (h *Handler) renderFeedContent(content string) (string, error) {
+	var buf bytes.Buffer
+
+	err := h.feedMarkdown.Convert([]byte(content), &buf)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert markdown: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// feedAuthors resolves and caches usernames of entry authors.
+type feedAuthors struct {
+	authSvc   *authentication.Service
+	usernames map[string]string
+}
+
+func (authors *feedAuthors) username(ctx context.Context, userID string) (string, error) {
+	if username, ok := authors.usernames[userID]; ok {
+		return username, nil
+	}
+
+	user, err := authors.authSvc.GetUser(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	authors.usernames[userID] = user.Username
+
+	return user.Username, nil
+}
+
+func (h *Handler) newFeedAuthors() *feedAuthors {
+	return &feedAuthors{authSvc: h.authSvc, usernames: make(map[string]string)}
+}
+
+func (h *Handler) postFeedEntries(r *http.Request, posts []*contents.Post) ([]feed.Entry, error) {
+	authors := h.newFeedAuthors()
+	entries := make([]feed.Entry, 0, len(posts))
+
+	for _, post := range posts {
+		username, err := authors.username(r.Context(), post.AuthorID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get post author: %w", err)
+		}
+
+		content, err := h.renderFeedContent(post.Content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render post %s: %w", post.ID, err)
+		}
+
+		link := h.absoluteURL("/p/" + post.ID)
+
+		entries = append(entries, feed.Entry{
+			ID:        link,
+			Title:     h.feedEntryTitle(post.Content),
+			Link:      link,
+			Author:    username,
+			Content:   content,
+			Published: post.CreatedAt,
+			Updated:   post.CreatedAt,
+		})
+	}
+
+	return entries, nil
+}
+
+// serveFeed encodes the feed and serves it with validators, so readers polling an unchanged feed get 304 Not
+// Modified.
+func serveFeed(w http.ResponseWriter, r *http.Request, f *feed.Feed, format feed.Format) {
+	var buf bytes.Buffer
+
+	err := format.Encode(&buf, f)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to encode feed", "path", r.URL.Path, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+		return
+	}
+
+	hash := sha256.Sum256(buf.Bytes())
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("ETag", `"`+hex.EncodeToString(hash[:16])+`"`)
+
+	http.ServeContent(w, r, "", f.LastModified(), bytes.NewReader(buf.Bytes()))
+}
+
+func (h *Handler) HandleSiteFeed(format feed.Format) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts, err := h.contentsSvc.ListPosts(r.Context(), contents.ListPostsRequest{
+			AuthorID:    "",
+			CommunityID: "",
+			Sort:        contents.PostSortNew,
+			Period:      contents.TopPeriodAll,
+			Before:      nil,
+			Limit:       feedSize,
+		})
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to list posts", "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+			return
+		}
+
+		entries, err := h.postFeedEntries(r, posts)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to build feed entries", "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+			return
+		}
+
+		serveFeed(w, r, &feed.Feed{
+			ID:       h.absoluteURL("/"),
+			Title:    defaultSiteTitle,
+			Subtitle: "Latest posts on " + defaultSiteTitle,
+			Link:     h.absoluteURL("/"),
+			SelfLink: h.absoluteURL(r.URL.Path),
+			Updated:  time.Time{},
+			Entries:  entries,
+		}, format)
+	})
+}
+
+func (h *Handler) HandleUserFeed(format feed.Format) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, err := h.authSvc.GetUserByUsername(r.Context(), r.PathValue("username"))
+		if err != nil {
+			if _, ok := errors.AsType[*authentication.UserByUsernameNotFoundError](err); ok {
+				http.Error(w, "User not found", http.StatusNotFound)
+
+				return
+			}
+
+			slog.ErrorContext(r.Context(), "failed to get user", "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+			return
+		}
+
+		posts, err := h.contentsSvc.ListPosts(r.Context(), contents.ListPostsRequest{
+			AuthorID:    user.ID,
+			CommunityID: "",
+			Sort:        contents.PostSortNew,
+			Period:      contents.TopPeriodAll,
+			Before:      nil,
+			Limit:       feedSize,
+		})
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to list posts", "userId", user.ID, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+			return
+		}
+
+		entries, err := h.postFeedEntries(r, posts)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to build feed entries", "userId", user.ID, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+			return
+		}
+
+		serveFeed(w, r, &feed.Feed{
+			ID:       h.absoluteURL(userFeedPath(user.Username)),
+			Title:    "@" + user.Username + " on " + defaultSiteTitle,
+			Subtitle: "Latest posts by @" + user.Username,
+			Link:     h.absoluteURL("/"),
+			SelfLink: h.absoluteURL(r.URL.Path),
+			Updated:  user.RegisteredAt,
+			Entries:  entries,
+		}, format)
+	})
+}
+
+// HandleCommentsFeed serves the latest comments of a post, replies included, newest first.
+func (h *Handler) HandleCommentsFeed(format feed.Format) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		post, err := h.contentsSvc.GetPost(r.Context(), r.PathValue("postId"))
+		if err != nil {
+			if _, ok := errors.AsType[contents.PostNotFoundError](err); ok {
+				http.Error(w, "Post not found", http.StatusNotFound)
+
+				return
+			}
+
+			slog.ErrorContext(r.Context(), "failed to get post", "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+			return
+		}
+
+		comments, err := h.discussSvc.ListComments(r.Context(), discuss.ListCommentsRequest{
+			PostID:   post.ID,
+			TopLevel: false,
+			ReplyTo:  nil,
+			Sort:     discuss.CommentSortNewest,
+			After:    nil,
+			Limit:    feedSize,
+		})
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to list comments", "postId", post.ID, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+			return
+		}
+
+		entries, err := h.commentFeedEntries(r, comments)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to build feed entries", "postId", post.ID, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+			return
+		}
+
+		title := h.feedEntryTitle(post.Content)
+
+		serveFeed(w, r, &feed.Feed{
+			ID:       h.absoluteURL(commentsFeedPath(post.ID)),
+			Title:    "Comments on " + title,
+			Subtitle: "",
+			Link:     h.absoluteURL("/p/" + post.ID),
+			SelfLink: h.absoluteURL(r.URL.Path),
+			Updated:  post.CreatedAt,
+			Entries:  entries,
+		}, format)
+	})
+}
+
+func (h *Handler) commentFeedEntries(r *http.Request, comments []*discuss.Comment) ([]feed.Entry, error) {
+	authors := h.newFeedAuthors()
+	entries := make([]feed.Entry, 0, len(comments))
+
+	for _, comment := range comments {
+		// Deleted comments have nothing left to syndicate.
+		if comment.IsDeleted() {
+			continue
+		}
+
+		username, err := authors.username(r.Context(), comment.AuthorID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get comment author: %w", err)
+		}
+
+		content, err := h.renderFeedContent(comment.Content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render comment %s: %w", comment.ID, err)
+		}
+
+		link := h.absoluteURL("/p/" + comment.PostID + "#comment-" + comment.ID)
+
+		updated := comment.CreatedAt
+		if comment.EditedAt != nil {
+			updated = *comment.EditedAt
+		}
+
+		entries = append(entries, feed.Entry{
+			ID:        link,
+			Title:     "Comment by @" + username,
+			Link:      link,
+			Author:    username,
+			Content:   content,
+			Published: comment.CreatedAt,
+			Updated:   updated,
+		})
+	}
+
+	return entries, nil
+}
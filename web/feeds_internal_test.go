@@ -0,0 +1,109 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/web/feed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+func TestFeedEntryTitle(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{name: "first paragraph", content: "Hello\nworld\n\nMore", expected: "Hello world"},
+		{name: "markup", content: "Hello **world**, see [docs](/docs) and `code`", expected: "Hello world, see docs and code"},
+		{name: "heading", content: "## Release notes\n\nDetails", expected: "Release notes"},
+		{name: "raw html", content: "<div>hidden</div>\n\n> quoted", expected: "quoted"},
+		{name: "empty", content: " \n", expected: "Untitled"},
+		{
+			name:     "long",
+			content:  strings.Repeat("ab", feedEntryTitleLength),
+			expected: strings.Repeat("ab", feedEntryTitleLength)[:feedEntryTitleLength-1] + "…",
+		},
+	}
+
+	h := &Handler{feedMarkdown: goldmark.New(goldmark.WithExtensions(extension.GFM))}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, h.feedEntryTitle(tc.content))
+		})
+	}
+}
+
+func TestServeFeed(t *testing.T) {
+	t.Parallel()
+
+	updated := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	f := &feed.Feed{
+		ID:       "http://example.com/",
+		Title:    "Scribble",
+		Subtitle: "",
+		Link:     "http://example.com/",
+		SelfLink: "http://example.com/feed.xml",
+		Updated:  updated,
+		Entries:  nil,
+	}
+
+	w := httptest.NewRecorder()
+	serveFeed(w, httptest.NewRequest(http.MethodGet, "/feed.xml", nil), f, feed.RSS)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/rss+xml; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, updated.Format(http.TimeFormat), w.Header().Get("Last-Modified"))
+
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	t.Run("if none match", func(t *testing.T) {
+		t.Parallel()
+
+		r := httptest.NewRequest(http.MethodGet, "/feed.xml", nil)
+		r.Header.Set("If-None-Match", etag)
+
+		w := httptest.NewRecorder()
+		serveFeed(w, r, f, feed.RSS)
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("if modified since", func(t *testing.T) {
+		t.Parallel()
+
+		r := httptest.NewRequest(http.MethodGet, "/feed.xml", nil)
+		r.Header.Set("If-Modified-Since", updated.Format(http.TimeFormat))
+
+		w := httptest.NewRecorder()
+		serveFeed(w, r, f, feed.RSS)
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		t.Parallel()
+
+		r := httptest.NewRequest(http.MethodGet, "/feed.atom", nil)
+		r.Header.Set("If-None-Match", etag)
+
+		w := httptest.NewRecorder()
+		serveFeed(w, r, f, feed.Atom)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotEqual(t, etag, w.Header().Get("ETag"))
+	})
+}
@@ -32,6 +32,7 @@ func (h *Handler) funcs() template.FuncMap {
 			return t.Format(layout)
 		},
 		"hashed": h.getAssetHashedURL,
+		"emoji":  renderEmoji,
 	}
 }
 
@@ -12,15 +12,28 @@ import (
 	"net/http"
 	"net/url"
 	"runtime/debug"
+	"slices"
 	"strings"
 
 	"github.com/gorilla/csrf"
 	"github.com/gorilla/sessions"
+	"github.com/nasermirzaei89/scribble/audit"
 	"github.com/nasermirzaei89/scribble/authentication"
 	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/communities"
 	"github.com/nasermirzaei89/scribble/contents"
 	"github.com/nasermirzaei89/scribble/discuss"
+	"github.com/nasermirzaei89/scribble/emojis"
+	"github.com/nasermirzaei89/scribble/federation"
+	"github.com/nasermirzaei89/scribble/live"
+	"github.com/nasermirzaei89/scribble/mentions"
+	"github.com/nasermirzaei89/scribble/notifications"
 	"github.com/nasermirzaei89/scribble/reactions"
+	"github.com/nasermirzaei89/scribble/reputation"
+	"github.com/nasermirzaei89/scribble/web/feed"
+	"github.com/nasermirzaei89/scribble/web/openapi"
+	"github.com/nasermirzaei89/scribble/webhooks"
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/extension"
 	"github.com/yuin/goldmark/renderer/html"
@@ -42,55 +55,104 @@ const (
 )
 
 type Handler struct {
-	mux          *http.ServeMux
-	handler      http.Handler
-	tpl          *template.Template
-	static       fs.FS
-	authSvc      *authentication.Service
-	contentsSvc  contents.Service
-	discussSvc   discuss.Service
-	reactionsSvc reactions.Service
-	cookieStore  *sessions.CookieStore
-	sessionName  string
-	assetHashes  map[string]string
-	markdown     goldmark.Markdown
+	mux              *http.ServeMux
+	handler          http.Handler
+	tpl              *template.Template
+	static           fs.FS
+	authSvc          *authentication.Service
+	authzClient      *authorization.Client
+	contentsSvc      contents.Service
+	discussSvc       discuss.Service
+	reactionsSvc     reactions.Service
+	reactionTargets  *reactions.TargetRegistry
+	auditSvc         audit.Service
+	communitiesSvc   communities.Service
+	federationSvc    *federation.Service
+	liveBroker       *live.Broker
+	webhooksSvc      webhooks.Service
+	notificationsSvc notifications.Service
+	mentionsSvc      mentions.Service
+	emojisSvc        emojis.Service
+	reputationSvc    reputation.Service
+	baseURL          string
+	cookieStore      *sessions.CookieStore
+	sessionName      string
+	assetHashes      map[string]string
+	markdown         goldmark.Markdown
+	feedMarkdown     goldmark.Markdown
+	apiSpec          *openapi.Document
 }
 
 var _ http.Handler = (*Handler)(nil)
 
 func NewHandler(
 	authSvc *authentication.Service,
+	authzClient *authorization.Client,
 	contentsSvc contents.Service,
 	discussSvc discuss.Service,
 	reactionsSvc reactions.Service,
+	reactionTargets *reactions.TargetRegistry,
+	auditSvc audit.Service,
+	communitiesSvc communities.Service,
+	federationSvc *federation.Service,
+	liveBroker *live.Broker,
+	webhooksSvc webhooks.Service,
+	notificationsSvc notifications.Service,
+	mentionsSvc mentions.Service,
+	emojisSvc emojis.Service,
+	reputationSvc reputation.Service,
+	baseURL string,
 	cookieStore *sessions.CookieStore,
 	sessionName string,
 	csrfAuthKeys []byte,
 	csrfTrustedOrigins []string,
 ) (*Handler, error) {
 	h := &Handler{
-		mux:          nil,
-		handler:      nil,
-		tpl:          nil,
-		authSvc:      authSvc,
-		contentsSvc:  contentsSvc,
-		discussSvc:   discussSvc,
-		reactionsSvc: reactionsSvc,
-		cookieStore:  cookieStore,
-		sessionName:  sessionName,
-		assetHashes:  make(map[string]string),
-		markdown:     nil,
+		mux:              nil,
+		handler:          nil,
+		tpl:              nil,
+		authSvc:          authSvc,
+		authzClient:      authzClient,
+		contentsSvc:      contentsSvc,
+		discussSvc:       discussSvc,
+		reactionsSvc:     reactionsSvc,
+		reactionTargets:  reactionTargets,
+		auditSvc:         auditSvc,
+		communitiesSvc:   communitiesSvc,
+		federationSvc:    federationSvc,
+		liveBroker:       liveBroker,
+		webhooksSvc:      webhooksSvc,
+		notificationsSvc: notificationsSvc,
+		mentionsSvc:      mentionsSvc,
+		emojisSvc:        emojisSvc,
+		reputationSvc:    reputationSvc,
+		baseURL:          strings.TrimSuffix(baseURL, "/"),
+		cookieStore:      cookieStore,
+		sessionName:      sessionName,
+		assetHashes:      make(map[string]string),
+		markdown:         nil,
+		feedMarkdown:     nil,
+		apiSpec:          nil,
 	}
 
 	{
+		extensions := goldmark.WithExtensions(
+			extension.GFM, // tables, strikethrough, task lists
+		)
+
 		h.markdown = goldmark.New(
+			extensions,
 			goldmark.WithExtensions(
-				extension.GFM, // tables, strikethrough, task lists
+				mentions.NewExtension(mentionResolver{authSvc: authSvc}),
+				emojis.NewExtension(customEmojiResolver{emojisSvc: emojisSvc}),
 			),
 			goldmark.WithRendererOptions(
 				html.WithUnsafe(), // allow raw HTML (REMOVE if you want stricter)
 			),
 		)
+
+		// Feeds omit raw HTML and dangerous links, as their entries are shown outside the site.
+		h.feedMarkdown = goldmark.New(extensions)
 	}
 
 	{
@@ -120,16 +182,23 @@ func NewHandler(
 
 	{
 		h.handler = h.authMiddleware(h.handler)
+		h.handler = h.clientInfoMiddleware(h.handler)
 
 		{
 			csrfMiddleware := csrf.Protect(
 				csrfAuthKeys,
 				csrf.TrustedOrigins(csrfTrustedOrigins),
+				csrf.ErrorHandler(http.HandlerFunc(handleCSRFError)),
 			)
 
 			h.handler = csrfMiddleware(h.handler)
 		}
 
+		h.handler = h.apiAuthMiddleware(h.handler)
+		h.handler = federationMiddleware(h.handler)
+		h.handler = unsubscribeMiddleware(h.handler)
+		h.handler = emojiUploadMiddleware(h.handler)
+
 		h.handler = recoverMiddleware(h.handler)
 	}
 
@@ -152,10 +221,68 @@ func (h *Handler) registerRoutes() {
 
 	h.mux.Handle("GET /create-post", h.HandleCreatePostPage())
 	h.mux.Handle("POST /create-post", h.HandleCreatePost())
-	h.mux.Handle("GET /p/{postId}", h.HandleViewPostPage())
+	h.mux.Handle("GET /p/{postId}", negotiateActivity(h.HandleNote(), h.HandleViewPostPage()))
 	h.mux.Handle("POST /p/{postId}/comment", h.HandlePostComment())
+	h.mux.Handle("POST /p/{postId}/reactions", h.HandleSetPostReactionSet())
+	h.mux.Handle("POST /p/{postId}/reactions/reset", h.HandleResetPostReactionSet())
+	h.mux.Handle("GET /p/{postId}/comments", h.HandleComments())
+	h.mux.Handle("GET /p/{postId}/comments/{commentId}", h.HandleCommentThreadPage())
+	h.mux.Handle("GET /p/{postId}/comments/{commentId}/replies", h.HandleCommentReplies())
 	h.mux.Handle("GET /p/{postId}/comments/{commentId}/reply", h.HandleReplyForm())
+	h.mux.Handle("GET /p/{postId}/comments/{commentId}/edit", h.HandleEditCommentForm())
+	h.mux.Handle("POST /p/{postId}/comments/{commentId}/edit", h.HandleEditComment())
+	h.mux.Handle("POST /p/{postId}/comments/{commentId}/delete", h.HandleDeleteComment())
+	h.mux.Handle("GET /p/{postId}/comments/{commentId}/edits", h.HandleCommentEditsPage())
 	h.mux.Handle("POST /react/{targetType}/{targetId}", h.HandleToggleReaction())
+	h.mux.Handle("GET /react/{targetType}/{targetId}/reactors", h.HandleReactors())
+	h.mux.Handle("GET /events", h.HandleEvents())
+	h.mux.Handle("GET /emojis/{shortcode}", h.HandleEmojiImage())
+
+	h.mux.Handle("GET /communities", h.HandleCommunitiesPage())
+	h.mux.Handle("GET /create-community", h.HandleCreateCommunityPage())
+	h.mux.Handle("POST /create-community", h.HandleCreateCommunity())
+	h.mux.Handle("GET /c/{slug}", h.HandleCommunityPage())
+	h.mux.Handle("POST /c/{slug}/join", h.HandleJoinCommunity())
+	h.mux.Handle("POST /c/{slug}/leave", h.HandleLeaveCommunity())
+	h.mux.Handle("GET /c/{slug}/members", h.HandleCommunityMembersPage())
+	h.mux.Handle("POST /c/{slug}/members/{userId}/role", h.HandleSetMemberRole())
+	h.mux.Handle("POST /c/{slug}/members/{userId}/remove", h.HandleRemoveMember())
+	h.mux.Handle("POST /c/{slug}/reactions", h.HandleSetCommunityReactionSet())
+	h.mux.Handle("POST /c/{slug}/reactions/reset", h.HandleResetCommunityReactionSet())
+
+	h.mux.Handle("GET /u/{username}", h.HandleUserPage())
+	h.mux.Handle("GET /mentions", h.HandleMentionsPage())
+
+	h.mux.Handle("GET /notifications", h.HandleNotificationsPage())
+	h.mux.Handle("POST /notifications/read-all", h.HandleReadAllNotifications())
+	h.mux.Handle("POST /notifications/preferences", h.HandleNotificationPreferences())
+	h.mux.Handle("POST /notifications/{notificationId}/read", h.HandleReadNotification())
+	h.mux.Handle("POST /notifications/email", h.HandleEmailSettings())
+	h.mux.Handle("GET /email/unsubscribe", h.HandleUnsubscribePage())
+	h.mux.Handle("POST /email/unsubscribe", h.HandleUnsubscribe())
+
+	h.mux.Handle("GET /admin/audit", h.HandleAuditLogPage())
+	h.mux.Handle("GET /admin/reactions", h.HandleReactionSetsPage())
+	h.mux.Handle("POST /admin/reactions", h.HandleSetReactionSet())
+	h.mux.Handle("POST /admin/reactions/delete", h.HandleDeleteReactionSet())
+	h.mux.Handle("GET /admin/emojis", h.HandleEmojisPage())
+	h.mux.Handle("POST /admin/emojis", h.HandleCreateEmoji())
+	h.mux.Handle("POST /admin/emojis/delete", h.HandleDeleteEmoji())
+	h.mux.Handle("GET /admin/webhooks", h.HandleWebhooksPage())
+	h.mux.Handle("POST /admin/webhooks", h.HandleCreateWebhook())
+	h.mux.Handle("GET /admin/webhooks/{endpointId}", h.HandleWebhookPage())
+	h.mux.Handle("POST /admin/webhooks/{endpointId}/delete", h.HandleDeleteWebhook())
+	h.mux.Handle("POST /admin/webhooks/{endpointId}/deliveries/{deliveryId}/redeliver", h.HandleRedeliverWebhook())
+
+	h.mux.Handle("GET /feed.xml", h.HandleSiteFeed(feed.RSS))
+	h.mux.Handle("GET /feed.atom", h.HandleSiteFeed(feed.Atom))
+	h.mux.Handle("GET /u/{username}/feed.xml", h.HandleUserFeed(feed.RSS))
+	h.mux.Handle("GET /u/{username}/feed.atom", h.HandleUserFeed(feed.Atom))
+	h.mux.Handle("GET /p/{postId}/comments/feed.xml", h.HandleCommentsFeed(feed.RSS))
+	h.mux.Handle("GET /p/{postId}/comments/feed.atom", h.HandleCommentsFeed(feed.Atom))
+
+	h.registerFederationRoutes()
+	h.registerAPIRoutes()
 }
 
 func recoverMiddleware(next http.Handler) http.Handler {
@@ -196,11 +323,27 @@ func (h *Handler) renderTemplate(w http.ResponseWriter, r *http.Request, name st
 	}
 
 	data := map[string]any{
-		"CurrentPath":     r.URL.Path,
-		"Lang":            "en",
-		"Dir":             "ltr",
-		"IsAuthenticated": isAuthenticatedRequest(r),
-		"CurrentUser":     currentUser,
+		"CurrentPath":       r.URL.Path,
+		"Lang":              "en",
+		"Dir":               "ltr",
+		"IsAuthenticated":   isAuthenticatedRequest(r),
+		"CurrentUser":       currentUser,
+		"CanViewAuditLog":   h.authzClient.CanI(r.Context(), audit.ServiceName, "", audit.ActionListEvents),
+		"CanManageWebhooks": h.authzClient.CanI(r.Context(), webhooks.ServiceName, "", webhooks.ActionListEndpoints),
+		"CanManageReactions": h.authzClient.CanI(
+			r.Context(),
+			reactions.ServiceName,
+			"",
+			reactions.ActionListReactionSets,
+		),
+		"CanManageEmojis": h.authzClient.CanI(
+			r.Context(),
+			emojis.ServiceName,
+			"",
+			emojis.ActionManageCustomEmojis,
+		),
+		"UnreadNotifications": h.unreadNotificationsCount(r),
+		"Feeds":               siteFeedLinks(),
 	}
 
 	maps.Copy(data, extraData)
@@ -236,19 +379,96 @@ func (h *Handler) HandleStatic(w http.ResponseWriter, r *http.Request) {
 	http.FileServer(http.FS(h.static)).ServeHTTP(w, r)
 }
 
+// homePageSize is how many posts a page of the home feed shows.
+const homePageSize = 20
+
+// HandleHomePage shows the posts in the sort and period of the query, newest first by default, a page at a time.
 func (h *Handler) HandleHomePage(w http.ResponseWriter, r *http.Request) {
-	posts, err := h.contentsSvc.ListPosts(r.Context())
+	query := r.URL.Query()
+
+	sort := contents.PostSort(query.Get("sort"))
+	if sort == "" {
+		sort = contents.PostSortNew
+	}
+
+	period := contents.TopPeriod(query.Get("period"))
+	if period == "" {
+		period = contents.TopPeriodAll
+	}
+
+	req := contents.ListPostsRequest{
+		AuthorID:    "",
+		CommunityID: "",
+		Sort:        sort,
+		Period:      period,
+		Before:      nil,
+		Limit:       homePageSize + 1,
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		before, err := decodePostCursor(sort, cursor)
+		if err != nil {
+			http.Error(w, "Invalid cursor", http.StatusBadRequest)
+
+			return
+		}
+
+		req.Before = before
+	}
+
+	posts, err := h.contentsSvc.ListPosts(r.Context(), req)
 	if err != nil {
+		if _, ok := errors.AsType[contents.InvalidPostSortError](err); ok {
+			http.Error(w, "Invalid sort", http.StatusBadRequest)
+
+			return
+		}
+
+		if _, ok := errors.AsType[contents.InvalidTopPeriodError](err); ok {
+			http.Error(w, "Invalid period", http.StatusBadRequest)
+
+			return
+		}
+
 		slog.ErrorContext(r.Context(), "failed to list posts", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 
 		return
 	}
 
+	pageURL := func(sort contents.PostSort, period contents.TopPeriod, cursor string) string {
+		pageQuery := url.Values{}
+
+		if sort != contents.PostSortNew {
+			pageQuery.Set("sort", string(sort))
+		}
+
+		if sort == contents.PostSortTop && period != contents.TopPeriodAll {
+			pageQuery.Set("period", string(period))
+		}
+
+		if cursor != "" {
+			pageQuery.Set("cursor", cursor)
+		}
+
+		if len(pageQuery) == 0 {
+			return "/"
+		}
+
+		return "/?" + pageQuery.Encode()
+	}
+
+	nextURL := ""
+
+	if len(posts) > homePageSize {
+		posts = posts[:homePageSize]
+		nextURL = pageURL(sort, period, postCursor(sort)(posts[len(posts)-1]))
+	}
+
 	postsWithAuthors, err := h.preloadPostAuthor(
 		r.Context(),
 		posts,
-		"/",
+		r.URL.RequestURI(),
 		csrf.TemplateField(r),
 	)
 	if err != nil {
@@ -258,8 +478,34 @@ func (h *Handler) HandleHomePage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	sorts := make([]map[string]any, 0, len(contents.PostSorts()))
+	for _, option := range contents.PostSorts() {
+		sorts = append(sorts, map[string]any{
+			"Name":     string(option),
+			"URL":      pageURL(option, period, ""),
+			"Selected": option == sort,
+		})
+	}
+
+	periods := make([]map[string]any, 0, len(contents.TopPeriods()))
+	for _, option := range contents.TopPeriods() {
+		periods = append(periods, map[string]any{
+			"Name":     string(option),
+			"URL":      pageURL(sort, option, ""),
+			"Selected": option == period,
+		})
+	}
+
+	// New posts are only added live to the first page of the newest posts, where they belong.
+	livePosts := sort == contents.PostSortNew && req.Before == nil
+
 	data := map[string]any{
 		"Posts":          postsWithAuthors,
+		"Sorts":          sorts,
+		"Periods":        periods,
+		"IsTop":          sort == contents.PostSortTop,
+		"LivePosts":      livePosts,
+		"NextURL":        nextURL,
 		csrf.TemplateTag: csrf.TemplateField(r),
 	}
 
@@ -270,6 +516,7 @@ type FullPost struct {
 	contents.Post
 
 	Author        *authentication.User
+	Community     *communities.Community
 	CommentsCount *int
 	Comments      []*CommentWithAuthor
 	Reactions     map[string]any
@@ -280,8 +527,40 @@ type CommentWithAuthor struct {
 
 	Author *authentication.User
 
-	Replies   []*CommentWithAuthor
-	Reactions map[string]any
+	Replies []*CommentWithAuthor
+	// ThreadURL and RepliesURL link to the replies of a comment at the depth the thread is cut at, as a page of their
+	// own and as a fragment to show in place. Both are empty when the replies are shown or there are none.
+	ThreadURL  string
+	RepliesURL string
+	Reactions  map[string]any
+	Can        CommentCapabilities
+	// CSRFField is for the forms of the comment, like deleting it.
+	CSRFField template.HTML
+	// SwapOOB renders the body of the comment to swap out of band, for live updates.
+	SwapOOB bool
+}
+
+// CommentCapabilities tells templates which actions the current user may perform on a comment.
+type CommentCapabilities struct {
+	Reply  bool
+	Edit   bool
+	Delete bool
+}
+
+// commentReplyCheck mirrors the permission discuss.AuthorizationMiddleware enforces when replying to a comment. It is
+// the same for every comment.
+func commentReplyCheck() authorization.ObjectAction {
+	return authorization.ObjectAction{Object: "", Action: discuss.ActionCreateComment}
+}
+
+// commentEditCheck mirrors the permission discuss.AuthorizationMiddleware enforces when editing the comment.
+func commentEditCheck(ctx context.Context, comment *discuss.Comment) authorization.ObjectAction {
+	return authorization.ObjectAction{Object: discuss.CommentObject(ctx, comment), Action: discuss.ActionUpdateComment}
+}
+
+// commentDeleteCheck mirrors the permission discuss.AuthorizationMiddleware enforces when deleting the comment.
+func commentDeleteCheck(ctx context.Context, comment *discuss.Comment) authorization.ObjectAction {
+	return authorization.ObjectAction{Object: discuss.CommentObject(ctx, comment), Action: discuss.ActionDeleteComment}
 }
 
 func (h *Handler) preloadPostAuthor(
@@ -299,6 +578,11 @@ func (h *Handler) preloadPostAuthor(
 			return nil, fmt.Errorf("failed to get author: %w", err)
 		}
 
+		community, err := h.postCommunity(ctx, post)
+		if err != nil {
+			return nil, err
+		}
+
 		commentsCount, err := h.discussSvc.CountComments(ctx, post.ID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to count comments: %w", err)
@@ -318,6 +602,7 @@ func (h *Handler) preloadPostAuthor(
 		result = append(result, &FullPost{
 			Post:          *post,
 			Author:        author,
+			Community:     community,
 			CommentsCount: &commentsCount,
 			Reactions:     reactionData,
 		})
@@ -326,6 +611,20 @@ func (h *Handler) preloadPostAuthor(
 	return result, nil
 }
 
+// postCommunity returns the community the post was published in, or nil for posts outside communities.
+func (h *Handler) postCommunity(ctx context.Context, post *contents.Post) (*communities.Community, error) {
+	if post.CommunityID == "" {
+		return nil, nil //nolint:nilnil
+	}
+
+	community, err := h.communitiesSvc.GetCommunity(ctx, post.CommunityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get community: %w", err)
+	}
+
+	return community, nil
+}
+
 func (h *Handler) HandleRegisterPage() http.Handler {
 	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		data := map[string]any{
@@ -352,13 +651,18 @@ func (h *Handler) HandleRegister() http.Handler {
 		username := r.FormValue("username")
 		password := r.FormValue("password")
 
-		err = h.authSvc.Register(r.Context(), username, password)
+		_, err = h.authSvc.Register(r.Context(), username, password)
 		if err != nil {
-			var userAlreadyExistsErr *authentication.UserAlreadyExistsError
+			var (
+				userAlreadyExistsErr *authentication.UserAlreadyExistsError
+				invalidUsernameErr   *authentication.InvalidUsernameError
+			)
 
 			switch {
 			case errors.As(err, &userAlreadyExistsErr):
 				http.Error(w, "Username already exists", http.StatusConflict)
+			case errors.As(err, &invalidUsernameErr):
+				http.Error(w, "Username "+invalidUsernameErr.Reason, http.StatusBadRequest)
 			default:
 				slog.ErrorContext(r.Context(), "failed to register user", "error", err)
 				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
@@ -506,17 +810,47 @@ func (h *Handler) HandleCreatePost() http.Handler {
 			return
 		}
 
-		_, err = h.contentsSvc.CreatePost(r.Context(), contents.CreatePostRequest{
-			AuthorID: currentUser.ID,
-			Content:  content,
-		})
+		var community *communities.Community
+
+		if communityID := r.FormValue("community_id"); communityID != "" {
+			community, err = h.communitiesSvc.GetCommunity(r.Context(), communityID)
+			if err != nil {
+				handleCommunityError(w, r, err)
+
+				return
+			}
+		}
+
+		req := contents.CreatePostRequest{
+			AuthorID:    currentUser.ID,
+			CommunityID: "",
+			Content:     content,
+		}
+
+		if community != nil {
+			req.CommunityID = community.ID
+		}
+
+		_, err = h.contentsSvc.CreatePost(r.Context(), req)
 		if err != nil {
+			if _, ok := errors.AsType[*authorization.AccessDeniedError](err); ok {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+
+				return
+			}
+
 			slog.ErrorContext(r.Context(), "failed to create post", "error", err)
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 
 			return
 		}
 
+		if community != nil {
+			http.Redirect(w, r, "/c/"+community.Slug, http.StatusSeeOther)
+
+			return
+		}
+
 		http.Redirect(w, r, "/", http.StatusSeeOther)
 	})
 
@@ -526,7 +860,12 @@ func (h *Handler) HandleCreatePost() http.Handler {
 func (h *Handler) HandleViewPostPage() http.Handler {
 	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		postID := r.PathValue("postId")
-		returnTo := "/p/" + postID
+		returnTo := r.URL.RequestURI()
+
+		sort, after, ok := parseCommentsQuery(w, r)
+		if !ok {
+			return
+		}
 
 		post, err := h.contentsSvc.GetPost(r.Context(), postID)
 		if err != nil {
@@ -544,9 +883,19 @@ func (h *Handler) HandleViewPostPage() http.Handler {
 			return
 		}
 
-		comments, err := h.listCommentsWithAuthors(
+		community, err := h.postCommunity(r.Context(), post)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to get post community", "postId", post.ID, "error", err)
+			http.Error(w, "Failed to get post community", http.StatusInternalServerError)
+
+			return
+		}
+
+		comments, nextCursor, err := h.listCommentsWithAuthors(
 			r.Context(),
 			post.ID,
+			sort,
+			after,
 			returnTo,
 			csrf.TemplateField(r),
 		)
@@ -572,85 +921,263 @@ func (h *Handler) HandleViewPostPage() http.Handler {
 		}
 
 		data := map[string]any{
-			"Post": FullPost{Post: *post, Author: author, Comments: comments, Reactions: reactionData},
+			"Post": FullPost{
+				Post:          *post,
+				Author:        author,
+				Community:     community,
+				CommentsCount: nil,
+				Comments:      comments,
+				Reactions:     reactionData,
+			},
 			// "SiteTitle": "View Post", TODO: set post title as site title
+			"Feeds": slices.Concat(
+				siteFeedLinks(),
+				feedLinks("Posts by @"+author.Username, userFeedPath(author.Username)),
+				feedLinks("Comments", commentsFeedPath(post.ID)),
+			),
+			"CanComment":   h.authzClient.CanI(r.Context(), discuss.ServiceName, "", discuss.ActionCreateComment),
+			"CommentSorts": commentSortOptions(post.ID, sort),
+			"MoreComments": moreComments(post.ID, sort, nextCursor),
+			// New comments are only added live to the last page of the oldest comments, where they belong.
+			"LiveComments":   sort == discuss.CommentSortOldest && nextCursor == "",
 			csrf.TemplateTag: csrf.TemplateField(r),
 		}
 
+		if h.canSetPostReactionSet(r.Context(), post) {
+			allowedEmojis, err := h.reactionsSvc.AllowedEmojis(r.Context(), reactions.TargetTypePost, post.ID)
+			if err != nil {
+				handleReactionSetError(w, r, err)
+
+				return
+			}
+
+			data["ReactionSetForm"], err = h.reactionSetForm(
+				r.Context(),
+				reactions.SetScopePost,
+				post.ID,
+				"/p/"+post.ID+"/reactions",
+				allowedEmojis,
+				csrf.TemplateField(r),
+			)
+			if err != nil {
+				handleReactionSetError(w, r, err)
+
+				return
+			}
+		}
+
 		h.renderTemplate(w, r, "view-post-page.gohtml", data)
 	})
 
 	return hf
 }
 
+// listCommentsWithAuthors loads a page of the top-level comments of the post in the sort, after the cursor, with their
+// threads down to commentThreadDepth. It returns the cursor of the next page, or an empty one on the last page.
 func (h *Handler) listCommentsWithAuthors(
 	ctx context.Context,
 	postID string,
+	sort discuss.CommentSort,
+	after *discuss.CommentCursor,
+	returnTo string,
+	csrfField template.HTML,
+) ([]*CommentWithAuthor, string, error) {
+	comments, err := h.discussSvc.ListComments(ctx, discuss.ListCommentsRequest{
+		PostID:   postID,
+		TopLevel: true,
+		ReplyTo:  nil,
+		Sort:     sort,
+		After:    after,
+		Limit:    commentsPageSize + 1,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list comments: %w", err)
+	}
+
+	nextCursor := ""
+
+	if len(comments) > commentsPageSize {
+		comments = comments[:commentsPageSize]
+		nextCursor = commentCursor(sort)(comments[len(comments)-1])
+	}
+
+	threads, err := h.loadCommentThreads(ctx, comments, sort, returnTo, csrfField)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return threads, nextCursor, nil
+}
+
+// loadCommentThreads loads the authors of the comments and their replies, a level at a time down to
+// commentThreadDepth. Comments at the last level with replies of their own link to the rest of their thread instead.
+func (h *Handler) loadCommentThreads(
+	ctx context.Context,
+	comments []*discuss.Comment,
+	sort discuss.CommentSort,
 	returnTo string,
 	csrfField template.HTML,
 ) ([]*CommentWithAuthor, error) {
-	comments, err := h.discussSvc.ListComments(ctx, postID)
+	roots, err := h.loadCommentsWithAuthors(ctx, comments, returnTo, csrfField)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list comments: %w", err)
+		return nil, err
 	}
 
-	result := make([]*CommentWithAuthor, 0, len(comments))
-	commentsByID := make(map[string]*CommentWithAuthor, len(comments))
+	loaded := slices.Clone(roots)
+	level := roots
 
-	for _, comment := range comments {
-		author, err := h.authSvc.GetUser(ctx, comment.AuthorID)
+	for depth := 1; depth <= commentThreadDepth && len(level) > 0; depth++ {
+		parents := make(map[string]*CommentWithAuthor, len(level))
+		parentIDs := make([]string, 0, len(level))
+
+		for _, comment := range level {
+			parents[comment.ID] = comment
+			parentIDs = append(parentIDs, comment.ID)
+		}
+
+		replies, err := h.discussSvc.ListComments(ctx, discuss.ListCommentsRequest{
+			PostID:   "",
+			TopLevel: false,
+			ReplyTo:  parentIDs,
+			Sort:     sort,
+			After:    nil,
+			Limit:    0,
+		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to get comment author: %w", err)
+			return nil, fmt.Errorf("failed to list replies: %w", err)
 		}
 
-		commentWithAuthor := &CommentWithAuthor{
-			Comment: *comment,
-			Author:  author,
+		// The replies past the depth are only looked up to tell which threads continue.
+		if depth == commentThreadDepth {
+			for _, reply := range replies {
+				parent := parents[*reply.ReplyTo]
+				parent.ThreadURL = commentThreadPath(parent.PostID, parent.ID) + commentsQuery(sort, "")
+				parent.RepliesURL = commentThreadPath(parent.PostID, parent.ID) + "/replies" + commentsQuery(sort, "")
+			}
+
+			break
 		}
 
-		reactionData, err := h.buildReactionWidgetData(
-			ctx,
-			reactions.TargetTypeComment,
-			comment.ID,
-			returnTo,
-			csrfField,
-		)
+		level, err = h.loadCommentsWithAuthors(ctx, replies, returnTo, csrfField)
 		if err != nil {
-			return nil, fmt.Errorf("failed to load comment reactions: %w", err)
+			return nil, err
 		}
 
-		// TODO: optimize this by batching comment reaction retrieval instead of doing it one by one
-		// Similar to post reactions, this creates N+1 queries for comment reactions.
-		// For a post with many comments, this compounds the performance issue.
-		// Each comment triggers 2 database queries for reaction data (counts + user reaction).
-		// The same batching optimization suggested for posts should be applied here
-		// to load all comment reactions in a single batch operation.
-		commentWithAuthor.Reactions = reactionData
+		for _, reply := range level {
+			parent := parents[*reply.ReplyTo]
+			parent.Replies = append(parent.Replies, reply)
+		}
 
-		result = append(result, commentWithAuthor)
-		commentsByID[comment.ID] = commentWithAuthor
+		loaded = append(loaded, level...)
 	}
 
-	roots := make([]*CommentWithAuthor, 0, len(result))
+	err = h.preloadCommentCapabilities(ctx, loaded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load comment capabilities: %w", err)
+	}
 
-	for _, comment := range result {
-		if comment.ReplyTo == nil {
-			roots = append(roots, comment)
+	return roots, nil
+}
 
-			continue
+func (h *Handler) loadCommentsWithAuthors(
+	ctx context.Context,
+	comments []*discuss.Comment,
+	returnTo string,
+	csrfField template.HTML,
+) ([]*CommentWithAuthor, error) {
+	result := make([]*CommentWithAuthor, 0, len(comments))
+
+	for _, comment := range comments {
+		commentWithAuthor, err := h.loadCommentWithAuthor(ctx, comment, returnTo, csrfField)
+		if err != nil {
+			return nil, err
 		}
 
-		parent, found := commentsByID[*comment.ReplyTo]
-		if !found {
-			roots = append(roots, comment)
+		result = append(result, commentWithAuthor)
+	}
+
+	return result, nil
+}
+
+// loadCommentWithAuthor loads the author and reactions of the comment. Capabilities are left to
+// preloadCommentCapabilities, which checks them for many comments at once.
+func (h *Handler) loadCommentWithAuthor(
+	ctx context.Context,
+	comment *discuss.Comment,
+	returnTo string,
+	csrfField template.HTML,
+) (*CommentWithAuthor, error) {
+	author, err := h.authSvc.GetUser(ctx, comment.AuthorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment author: %w", err)
+	}
+
+	// TODO: optimize this by batching comment reaction retrieval instead of doing it one by one
+	// Similar to post reactions, this creates N+1 queries for comment reactions.
+	// For a post with many comments, this compounds the performance issue.
+	// Each comment triggers 2 database queries for reaction data (counts + user reaction).
+	// The same batching optimization suggested for posts should be applied here
+	// to load all comment reactions in a single batch operation.
+	reactionData, err := h.buildReactionWidgetData(
+		ctx,
+		reactions.TargetTypeComment,
+		comment.ID,
+		returnTo,
+		csrfField,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load comment reactions: %w", err)
+	}
+
+	return &CommentWithAuthor{
+		Comment:    *comment,
+		Author:     author,
+		Replies:    nil,
+		ThreadURL:  "",
+		RepliesURL: "",
+		Reactions:  reactionData,
+		Can:        CommentCapabilities{Reply: false, Edit: false, Delete: false},
+		CSRFField:  csrfField,
+		SwapOOB:    false,
+	}, nil
+}
+
+// preloadCommentCapabilities resolves the capability flags of all comments with a single batch permission check.
+// Deleted comments allow nothing.
+func (h *Handler) preloadCommentCapabilities(ctx context.Context, comments []*CommentWithAuthor) error {
+	checks := make([]authorization.ObjectAction, 0, 1+len(comments)*2)
+	checks = append(checks, commentReplyCheck())
+
+	for _, comment := range comments {
+		checks = append(
+			checks,
+			commentEditCheck(ctx, &comment.Comment),
+			commentDeleteCheck(ctx, &comment.Comment),
+		)
+	}
+
+	allowed, err := h.authzClient.CheckAccessBatch(ctx, discuss.ServiceName, checks...)
+	if err != nil {
+		return fmt.Errorf("failed to check comment permissions: %w", err)
+	}
+
+	canReply := allowed[commentReplyCheck()]
+
+	for _, comment := range comments {
+		if comment.IsDeleted() {
+			comment.Can = CommentCapabilities{Reply: false, Edit: false, Delete: false}
 
 			continue
 		}
 
-		parent.Replies = append(parent.Replies, comment)
+		comment.Can = CommentCapabilities{
+			Reply:  canReply,
+			Edit:   allowed[commentEditCheck(ctx, &comment.Comment)],
+			Delete: allowed[commentDeleteCheck(ctx, &comment.Comment)],
+		}
 	}
 
-	return roots, nil
+	return nil
 }
 
 func (h *Handler) buildReactionWidgetData(
@@ -669,6 +1196,9 @@ func (h *Handler) buildReactionWidgetData(
 			"Options":         []reactions.ReactionOption{},
 			"ReturnTo":        returnTo,
 			"IsAuthenticated": false,
+			"CanReact":        false,
+			"CanListReactors": false,
+			"HasReactions":    false,
 			csrf.TemplateTag:  csrfField,
 		}, nil
 	}
@@ -678,13 +1208,23 @@ func (h *Handler) buildReactionWidgetData(
 		return nil, fmt.Errorf("failed to get my reactions: %w", err)
 	}
 
+	object, err := h.reactionTargets.Object(targetType, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reaction target object: %w", err)
+	}
+
 	return map[string]any{
 		"TargetType":      targetReactions.TargetType,
 		"TargetID":        targetReactions.TargetID,
 		"Options":         targetReactions.Options,
 		"ReturnTo":        returnTo,
 		"IsAuthenticated": isAuthenticated,
-		csrf.TemplateTag:  csrfField,
+		"CanReact":        h.authzClient.CanI(ctx, reactions.ServiceName, object, reactions.ActionToggleReaction),
+		"CanListReactors": h.authzClient.CanI(ctx, reactions.ServiceName, object, reactions.ActionListReactors),
+		"HasReactions": slices.ContainsFunc(targetReactions.Options, func(option reactions.ReactionOption) bool {
+			return option.Count > 0
+		}),
+		csrf.TemplateTag: csrfField,
 	}, nil
 }
 
@@ -718,6 +1258,12 @@ func (h *Handler) HandlePostComment() http.Handler {
 			ReplyTo:  replyToID,
 		})
 		if err != nil {
+			if _, ok := errors.AsType[discuss.CommentNotFoundError](err); ok {
+				http.Error(w, "Comment not found", http.StatusNotFound)
+
+				return
+			}
+
 			slog.ErrorContext(r.Context(), "failed to create comment", "error", err)
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 
@@ -788,14 +1334,20 @@ func (h *Handler) HandleToggleReaction() http.Handler {
 		if err != nil {
 			var (
 				invalidTargetTypeErr reactions.InvalidTargetTypeError
+				targetNotFoundErr    reactions.TargetNotFoundError
 				invalidEmojiErr      reactions.InvalidEmojiError
+				tooManyReactionsErr  reactions.TooManyReactionsError
 			)
 
 			switch {
 			case errors.As(err, &invalidTargetTypeErr):
 				http.Error(w, "Invalid reaction target", http.StatusBadRequest)
+			case errors.As(err, &targetNotFoundErr):
+				http.Error(w, "Reaction target not found", http.StatusNotFound)
 			case errors.As(err, &invalidEmojiErr):
 				http.Error(w, "Invalid reaction emoji", http.StatusBadRequest)
+			case errors.As(err, &tooManyReactionsErr):
+				http.Error(w, tooManyReactionsMessage(tooManyReactionsErr), http.StatusBadRequest)
 			default:
 				slog.ErrorContext(r.Context(), "failed to toggle reaction", "error", err)
 				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
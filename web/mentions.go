@@ -0,0 +1,63 @@
+package web
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/nasermirzaei89/scribble/authentication"
+	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/mentions"
+)
+
+// MentionView is a mention as the mentions page shows it.
+type MentionView struct {
+	mentions.Mention
+
+	Author *authentication.User
+	URL    string
+}
+
+func (h *Handler) HandleMentionsPage() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		list, err := h.mentionsSvc.ListMyMentions(r.Context(), mentions.ListMentionsRequest{Limit: 0, Offset: 0})
+		if err != nil {
+			if _, ok := errors.AsType[*authorization.AccessDeniedError](err); ok {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+
+				return
+			}
+
+			slog.ErrorContext(r.Context(), "failed to list mentions", "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+			return
+		}
+
+		views := make([]*MentionView, 0, len(list))
+
+		for _, mention := range list {
+			author, err := h.authSvc.GetUser(r.Context(), mention.AuthorID)
+			if err != nil {
+				slog.ErrorContext(r.Context(), "failed to get mention author", "authorId", mention.AuthorID, "error", err)
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+				return
+			}
+
+			url := "/p/" + mention.PostID
+			if mention.CommentID != "" {
+				url += "#comment-" + mention.CommentID
+			}
+
+			views = append(views, &MentionView{Mention: *mention, Author: author, URL: url})
+		}
+
+		h.renderTemplate(w, r, "mentions-page.gohtml", map[string]any{
+			"SiteTitle": "Mentions",
+			"Mentions":  views,
+		})
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
@@ -0,0 +1,259 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/csrf"
+	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/notifications"
+)
+
+func handleNotificationError(w http.ResponseWriter, r *http.Request, err error) {
+	if _, ok := errors.AsType[*authorization.AccessDeniedError](err); ok {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+
+		return
+	}
+
+	var (
+		notificationNotFoundErr  notifications.NotificationNotFoundError
+		invalidTypeErr           notifications.InvalidTypeError
+		invalidEmailFrequencyErr notifications.InvalidEmailFrequencyError
+		invalidEmailAddressErr   notifications.InvalidEmailAddressError
+		invalidUnsubscribeErr    notifications.InvalidUnsubscribeTokenError
+	)
+
+	switch {
+	case errors.As(err, &notificationNotFoundErr):
+		http.Error(w, "Notification not found", http.StatusNotFound)
+	case errors.As(err, &invalidTypeErr):
+		http.Error(w, "Invalid notification type", http.StatusBadRequest)
+	case errors.As(err, &invalidEmailFrequencyErr):
+		http.Error(w, "Invalid email frequency", http.StatusBadRequest)
+	case errors.As(err, &invalidEmailAddressErr):
+		http.Error(w, "Invalid email address", http.StatusBadRequest)
+	case errors.As(err, &invalidUnsubscribeErr):
+		http.Error(w, "Invalid unsubscribe link", http.StatusBadRequest)
+	default:
+		slog.ErrorContext(r.Context(), "failed to handle notification request", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// NotificationView is a notification as the notifications page shows it.
+type NotificationView struct {
+	notifications.Notification
+
+	Text string
+	URL  string
+}
+
+func (h *Handler) notificationView(ctx context.Context, notification *notifications.Notification) *NotificationView {
+	names := make([]string, 0, notifications.ActorsShown)
+
+	for _, actorID := range notification.ActorIDs[:min(len(notification.ActorIDs), notifications.ActorsShown)] {
+		user, err := h.authSvc.GetUser(ctx, actorID)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to get notification actor", "actorId", actorID, "error", err)
+
+			names = append(names, "Someone")
+
+			continue
+		}
+
+		names = append(names, user.Username)
+	}
+
+	return &NotificationView{
+		Notification: *notification,
+		Text:         notifications.Summary(notification, names),
+		URL:          notifications.Path(notification),
+	}
+}
+
+// unreadNotificationsCount returns the count for the header badge, which is not worth failing a page for.
+func (h *Handler) unreadNotificationsCount(r *http.Request) int {
+	if !isAuthenticatedRequest(r) {
+		return 0
+	}
+
+	count, err := h.notificationsSvc.CountMyUnreadNotifications(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to count unread notifications", "error", err)
+
+		return 0
+	}
+
+	return count
+}
+
+func (h *Handler) HandleNotificationsPage() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		list, err := h.notificationsSvc.ListMyNotifications(r.Context(), notifications.ListNotificationsRequest{
+			Limit:  0,
+			Offset: 0,
+		})
+		if err != nil {
+			handleNotificationError(w, r, err)
+
+			return
+		}
+
+		prefs, err := h.notificationsSvc.GetMyPreferences(r.Context())
+		if err != nil {
+			handleNotificationError(w, r, err)
+
+			return
+		}
+
+		emailSettings, err := h.notificationsSvc.GetMyEmailSettings(r.Context())
+		if err != nil {
+			handleNotificationError(w, r, err)
+
+			return
+		}
+
+		views := make([]*NotificationView, 0, len(list))
+		for _, notification := range list {
+			views = append(views, h.notificationView(r.Context(), notification))
+		}
+
+		h.renderTemplate(w, r, "notifications-page.gohtml", map[string]any{
+			"SiteTitle":        "Notifications",
+			"Notifications":    views,
+			"Preferences":      prefs,
+			"Types":            notifications.Types(),
+			"EmailSettings":    emailSettings,
+			"EmailFrequencies": notifications.EmailFrequencies(),
+			csrf.TemplateTag:   csrf.TemplateField(r),
+		})
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+// HandleReadNotification marks the notification read and takes the user back, or on to what it is about.
+func (h *Handler) HandleReadNotification() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := h.notificationsSvc.MarkNotificationRead(r.Context(), r.PathValue("notificationId"))
+		if err != nil {
+			handleNotificationError(w, r, err)
+
+			return
+		}
+
+		http.Redirect(w, r, sanitizeReturnToPath(r.FormValue("return_to")), http.StatusSeeOther)
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+func (h *Handler) HandleReadAllNotifications() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := h.notificationsSvc.MarkAllNotificationsRead(r.Context())
+		if err != nil {
+			handleNotificationError(w, r, err)
+
+			return
+		}
+
+		http.Redirect(w, r, "/notifications", http.StatusSeeOther)
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+func (h *Handler) HandleNotificationPreferences() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to parse form", "error", err)
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+
+			return
+		}
+
+		muted := make([]notifications.Type, 0, len(r.Form["muted"]))
+		for _, typ := range r.Form["muted"] {
+			muted = append(muted, notifications.Type(typ))
+		}
+
+		err = h.notificationsSvc.SetMyPreferences(r.Context(), notifications.Preferences{Muted: muted})
+		if err != nil {
+			handleNotificationError(w, r, err)
+
+			return
+		}
+
+		http.Redirect(w, r, "/notifications", http.StatusSeeOther)
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+func (h *Handler) HandleEmailSettings() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := h.notificationsSvc.SetMyEmailSettings(r.Context(), notifications.SetEmailSettingsRequest{
+			Address:   r.FormValue("address"),
+			Frequency: notifications.EmailFrequency(r.FormValue("frequency")),
+		})
+		if err != nil {
+			handleNotificationError(w, r, err)
+
+			return
+		}
+
+		http.Redirect(w, r, "/notifications", http.StatusSeeOther)
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+// HandleUnsubscribePage asks to confirm the unsubscribe link, so link scanners opening it do not unsubscribe users.
+func (h *Handler) HandleUnsubscribePage() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.renderTemplate(w, r, "unsubscribe-page.gohtml", map[string]any{
+			"SiteTitle":      "Unsubscribe",
+			"UserID":         r.FormValue("user"),
+			"Token":          r.FormValue("token"),
+			"Unsubscribed":   false,
+			csrf.TemplateTag: csrf.TemplateField(r),
+		})
+	})
+}
+
+// HandleUnsubscribe turns emails off for the user of the unsubscribe link. Mail clients post to the link directly for
+// one-click unsubscribe, with the user and token in the query.
+func (h *Handler) HandleUnsubscribe() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := h.notificationsSvc.UnsubscribeFromEmails(r.Context(), r.FormValue("user"), r.FormValue("token"))
+		if err != nil {
+			handleNotificationError(w, r, err)
+
+			return
+		}
+
+		h.renderTemplate(w, r, "unsubscribe-page.gohtml", map[string]any{
+			"SiteTitle":      "Unsubscribe",
+			"UserID":         "",
+			"Token":          "",
+			"Unsubscribed":   true,
+			csrf.TemplateTag: csrf.TemplateField(r),
+		})
+	})
+}
+
+// unsubscribeMiddleware lets mail clients post to unsubscribe links, which are signed instead of protected against
+// CSRF.
+func unsubscribeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/email/unsubscribe" {
+			r = csrf.UnsafeSkipCheck(r)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
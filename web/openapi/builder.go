@@ -0,0 +1,121 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const mediaTypeJSON = "application/json"
+
+// Builder accumulates operations into a document, adding the schemas of the types they use to its components.
+type Builder struct {
+	doc            *Document
+	typeNamePrefix string
+}
+
+// NewBuilder returns a builder of a document with the info. The type name prefix is stripped from the names of Go
+// types when they become components.
+func NewBuilder(info Info, typeNamePrefix string) *Builder {
+	return &Builder{
+		doc: &Document{
+			OpenAPI: Version,
+			Info:    info,
+			Paths:   make(map[string]*PathItem),
+			Components: Components{
+				Schemas:         make(map[string]*Schema),
+				SecuritySchemes: make(map[string]*SecurityScheme),
+			},
+		},
+		typeNamePrefix: typeNamePrefix,
+	}
+}
+
+// AddSecurityScheme registers a security scheme operations can require by name.
+func (b *Builder) AddSecurityScheme(name string, scheme *SecurityScheme) {
+	b.doc.Components.SecuritySchemes[name] = scheme
+}
+
+// AddOperation adds the operation for the method and path template. Parameters of the path template are documented
+// as required strings unless the operation already describes them.
+func (b *Builder) AddOperation(method, path string, op *Operation) {
+	for _, name := range pathParameters(path) {
+		if hasParameter(op.Parameters, name, InPath) {
+			continue
+		}
+
+		op.Parameters = append(op.Parameters, &Parameter{
+			Name:     name,
+			In:       InPath,
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+
+	item, ok := b.doc.Paths[path]
+	if !ok {
+		item = &PathItem{}
+		b.doc.Paths[path] = item
+	}
+
+	(*item)[strings.ToLower(method)] = op
+}
+
+// JSONBody returns a required request body of the type encoded as JSON.
+func (b *Builder) JSONBody(t reflect.Type) *RequestBody {
+	return &RequestBody{
+		Required: true,
+		Content:  map[string]MediaType{mediaTypeJSON: {Schema: b.SchemaFor(t)}},
+	}
+}
+
+// JSONResponse returns a response with the status code whose body is the type encoded as JSON. A nil type describes a
+// response without content.
+func (b *Builder) JSONResponse(status int, t reflect.Type) *Response {
+	response := &Response{Description: http.StatusText(status)}
+
+	if t != nil {
+		response.Content = map[string]MediaType{mediaTypeJSON: {Schema: b.SchemaFor(t)}}
+	}
+
+	return response
+}
+
+// Responses keys responses by their status codes, as the document does.
+func Responses(responses map[int]*Response) map[string]*Response {
+	result := make(map[string]*Response, len(responses))
+
+	for status, response := range responses {
+		result[strconv.Itoa(status)] = response
+	}
+
+	return result
+}
+
+// Document returns the built document.
+func (b *Builder) Document() *Document {
+	return b.doc
+}
+
+func pathParameters(path string) []string {
+	var names []string
+
+	for segment := range strings.SplitSeq(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			names = append(names, strings.TrimSuffix(strings.TrimPrefix(segment, "{"), "}"))
+		}
+	}
+
+	return names
+}
+
+func hasParameter(parameters []*Parameter, name string, in ParameterLocation) bool {
+	for _, parameter := range parameters {
+		if parameter.Name == name && parameter.In == in {
+			return true
+		}
+	}
+
+	return false
+}
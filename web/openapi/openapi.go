@@ -0,0 +1,145 @@
+// Package openapi builds OpenAPI 3.1 documents whose schemas are derived from Go types, so the document cannot
+// disagree with the types the handlers encode and decode.
+package openapi
+
+import (
+	"strconv"
+	"strings"
+)
+
+const Version = "3.1.0"
+
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem maps lowercase HTTP methods to operations.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// Response returns the documented response for the status code.
+func (op *Operation) Response(status int) (*Response, bool) {
+	response, ok := op.Responses[strconv.Itoa(status)]
+
+	return response, ok
+}
+
+type ParameterLocation string
+
+const (
+	InPath  ParameterLocation = "path"
+	InQuery ParameterLocation = "query"
+)
+
+type Parameter struct {
+	Name        string            `json:"name"`
+	In          ParameterLocation `json:"in"`
+	Description string            `json:"description,omitempty"`
+	Required    bool              `json:"required,omitempty"`
+	Schema      *Schema           `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	Scheme      string `json:"scheme,omitempty"`
+	In          string `json:"in,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// FindOperation returns the operation serving the method and path, matching path templates such as
+// "/posts/{postId}" segment by segment. Like http.ServeMux, literal segments win over parameters, so "/posts/me"
+// prefers its own operation to "/posts/{postId}".
+func (doc *Document) FindOperation(method, path string) (string, *Operation, bool) {
+	var (
+		found          *Operation
+		foundTemplate  string
+		foundParameter = -1
+	)
+
+	for template, item := range doc.Paths {
+		op, ok := (*item)[strings.ToLower(method)]
+		if !ok {
+			continue
+		}
+
+		parameters, ok := matchPathTemplate(template, path)
+		if !ok {
+			continue
+		}
+
+		if found == nil || parameters < foundParameter {
+			found, foundTemplate, foundParameter = op, template, parameters
+		}
+	}
+
+	return foundTemplate, found, found != nil
+}
+
+// matchPathTemplate reports whether the path matches the template, and how many of its segments are parameters.
+func matchPathTemplate(template, path string) (int, bool) {
+	templateSegments := strings.Split(template, "/")
+	pathSegments := strings.Split(path, "/")
+
+	if len(templateSegments) != len(pathSegments) {
+		return 0, false
+	}
+
+	parameters := 0
+
+	for i, segment := range templateSegments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			if pathSegments[i] == "" {
+				return 0, false
+			}
+
+			parameters++
+
+			continue
+		}
+
+		if segment != pathSegments[i] {
+			return 0, false
+		}
+	}
+
+	return parameters, true
+}
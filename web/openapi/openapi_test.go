@@ -0,0 +1,91 @@
+package openapi_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/web/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type APIAuthor struct {
+	Name string `json:"name"`
+}
+
+type APIArticle struct {
+	Title     string              `json:"title"`
+	Author    *APIAuthor          `json:"author"`
+	Tags      []string            `json:"tags,omitempty"`
+	Subtitle  *string             `json:"subtitle,omitempty"`
+	Metadata  map[string]int      `json:"metadata,omitempty"`
+	CreatedAt time.Time           `json:"createdAt"`
+	Internal  string              `json:"-"`
+	Embedded  `json:",omitempty"` //nolint:tagliatelle
+}
+
+type Embedded struct {
+	Views int `json:"views"`
+}
+
+type APIList[T any] struct {
+	Items []T `json:"items"`
+}
+
+func TestSchemaFor(t *testing.T) {
+	t.Parallel()
+
+	builder := openapi.NewBuilder(openapi.Info{Title: "Test", Version: "1"}, "API")
+
+	schema := builder.SchemaFor(reflect.TypeFor[APIList[APIArticle]]())
+	assert.Equal(t, "ArticleList", schema.ComponentName())
+
+	schemas := builder.Document().Components.Schemas
+	require.Contains(t, schemas, "ArticleList")
+	require.Contains(t, schemas, "Article")
+	require.Contains(t, schemas, "Author")
+
+	assert.Equal(t, "Article", schemas["ArticleList"].Properties["items"].Items.ComponentName())
+
+	article := schemas["Article"]
+	assert.Equal(t, []string{"title", "author", "createdAt", "views"}, article.Required)
+	assert.Equal(t, false, article.AdditionalProperties)
+	assert.NotContains(t, article.Properties, "Internal")
+
+	assert.Equal(t, "Author", article.Properties["author"].AnyOf[0].ComponentName())
+	assert.Equal(t, "null", article.Properties["author"].AnyOf[1].Type)
+	assert.Equal(t, []string{"string", "null"}, article.Properties["subtitle"].Type)
+	assert.Equal(t, "integer", article.Properties["metadata"].AdditionalProperties.(*openapi.Schema).Type)
+	assert.Equal(t, "date-time", article.Properties["createdAt"].Format)
+	assert.Equal(t, "integer", article.Properties["views"].Type)
+}
+
+func TestFindOperation(t *testing.T) {
+	t.Parallel()
+
+	builder := openapi.NewBuilder(openapi.Info{Title: "Test", Version: "1"}, "")
+	builder.AddOperation("GET", "/posts/{postId}", &openapi.Operation{OperationID: "getPost"})
+	builder.AddOperation("GET", "/posts/me", &openapi.Operation{OperationID: "getMyPosts"})
+
+	doc := builder.Document()
+
+	template, op, ok := doc.FindOperation("GET", "/posts/post1")
+	require.True(t, ok)
+	assert.Equal(t, "/posts/{postId}", template)
+	assert.Equal(t, "getPost", op.OperationID)
+	require.Len(t, op.Parameters, 1)
+	assert.Equal(t, "postId", op.Parameters[0].Name)
+	assert.True(t, op.Parameters[0].Required)
+
+	template, op, ok = doc.FindOperation("GET", "/posts/me")
+	require.True(t, ok)
+	assert.Equal(t, "/posts/me", template)
+	assert.Equal(t, "getMyPosts", op.OperationID)
+
+	_, _, ok = doc.FindOperation("POST", "/posts/post1")
+	assert.False(t, ok)
+
+	_, _, ok = doc.FindOperation("GET", "/posts/post1/comments")
+	assert.False(t, ok)
+}
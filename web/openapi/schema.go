@@ -0,0 +1,197 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Schema is the subset of JSON Schema 2020-12 the builder emits.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Type                 any                `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties any                `json:"additionalProperties,omitempty"`
+	Minimum              *int               `json:"minimum,omitempty"`
+	Maximum              *int               `json:"maximum,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+}
+
+const schemaRefPrefix = "#/components/schemas/"
+
+// ComponentName returns the component a reference schema points to, or an empty string for inline schemas.
+func (schema *Schema) ComponentName() string {
+	return strings.TrimPrefix(schema.Ref, schemaRefPrefix)
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// SchemaFor returns the schema of values of the type as encoding/json marshals them. Named struct types are added to
+// the components and referenced, so each appears once in the document.
+func (b *Builder) SchemaFor(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Pointer:
+		return b.SchemaFor(t.Elem())
+	case t.Kind() == reflect.Struct && t.Name() != "":
+		return b.componentRef(t)
+	}
+
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+
+		return &Schema{Type: "array", Items: b.SchemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.SchemaFor(t.Elem())}
+	case reflect.Struct:
+		return b.structSchema(t)
+	default:
+		// Interfaces marshal as whatever they hold.
+		return &Schema{}
+	}
+}
+
+func (b *Builder) componentRef(t reflect.Type) *Schema {
+	name := b.componentName(t)
+
+	if _, ok := b.doc.Components.Schemas[name]; !ok {
+		// Reserve the name first, so recursive types refer to themselves instead of recursing forever.
+		b.doc.Components.Schemas[name] = &Schema{}
+		*b.doc.Components.Schemas[name] = *b.structSchema(t)
+	}
+
+	return &Schema{Ref: schemaRefPrefix + name}
+}
+
+// componentName derives a component name from the Go type name without the builder's type name prefix, naming
+// instances of generic types after their type arguments, so APIPage[APIPost] becomes PostPage.
+func (b *Builder) componentName(t reflect.Type) string {
+	name := t.Name()
+
+	base, args, generic := strings.Cut(name, "[")
+	if !generic {
+		return strings.TrimPrefix(name, b.typeNamePrefix)
+	}
+
+	var result strings.Builder
+
+	for arg := range strings.SplitSeq(strings.TrimSuffix(args, "]"), ",") {
+		// Type arguments are qualified with their package path.
+		arg = arg[strings.LastIndex(arg, ".")+1:]
+		result.WriteString(strings.TrimPrefix(arg, b.typeNamePrefix))
+	}
+
+	result.WriteString(strings.TrimPrefix(base, b.typeNamePrefix))
+
+	return result.String()
+}
+
+func (b *Builder) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: false,
+	}
+
+	b.addStructFields(schema, t)
+
+	return schema
+}
+
+func (b *Builder) addStructFields(schema *Schema, t reflect.Type) {
+	for field := range t.Fields() {
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			b.addStructFields(schema, field.Type)
+
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldSchema := b.SchemaFor(field.Type)
+
+		if field.Type.Kind() == reflect.Pointer {
+			fieldSchema = nullable(fieldSchema)
+		}
+
+		if description := field.Tag.Get("description"); description != "" {
+			fieldSchema = described(fieldSchema, description)
+		}
+
+		schema.Properties[name] = fieldSchema
+
+		if !hasOption(options, "omitempty") && !hasOption(options, "omitzero") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+func hasOption(options, option string) bool {
+	for candidate := range strings.SplitSeq(options, ",") {
+		if candidate == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nullable allows null besides the values of the schema, which is how encoding/json marshals nil pointers.
+func nullable(schema *Schema) *Schema {
+	if schema.Ref != "" {
+		return &Schema{AnyOf: []*Schema{schema, {Type: "null"}}}
+	}
+
+	if typeName, ok := schema.Type.(string); ok {
+		copied := *schema
+		copied.Type = []string{typeName, "null"}
+
+		return &copied
+	}
+
+	return schema
+}
+
+// described attaches a description to the schema. References cannot carry siblings in every tool, so they are left
+// as they are.
+func described(schema *Schema, description string) *Schema {
+	if schema.Ref != "" {
+		return schema
+	}
+
+	copied := *schema
+	copied.Description = description
+
+	return &copied
+}
@@ -0,0 +1,277 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gorilla/csrf"
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/communities"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/reactions"
+)
+
+func handleReactionSetError(w http.ResponseWriter, r *http.Request, err error) {
+	if _, ok := errors.AsType[*authorization.AccessDeniedError](err); ok {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+
+		return
+	}
+
+	var (
+		setNotFoundErr   reactions.ReactionSetNotFoundError
+		postNotFoundErr  contents.PostNotFoundError
+		invalidScopeErr  reactions.InvalidSetScopeError
+		invalidSetErr    reactions.InvalidReactionSetError
+		notPostAuthorErr reactions.NotPostAuthorError
+	)
+
+	switch {
+	case errors.As(err, &setNotFoundErr):
+		http.Error(w, "Reaction set not found", http.StatusNotFound)
+	case errors.As(err, &postNotFoundErr):
+		http.Error(w, "Post not found", http.StatusNotFound)
+	case errors.As(err, &invalidScopeErr):
+		http.Error(w, "Invalid reaction set scope", http.StatusBadRequest)
+	case errors.As(err, &invalidSetErr):
+		http.Error(w, "Invalid reaction set: "+invalidSetErr.Reason, http.StatusBadRequest)
+	case errors.As(err, &notPostAuthorErr):
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	default:
+		slog.ErrorContext(r.Context(), "failed to handle reaction set request", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// tooManyReactionsMessage tells users to take a reaction back before adding another one.
+func tooManyReactionsMessage(err reactions.TooManyReactionsError) string {
+	return fmt.Sprintf("You can react with at most %d emojis here; remove one first", err.Max)
+}
+
+// parseEmojis splits the emojis of a form field, which are separated by spaces or commas.
+func parseEmojis(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return unicode.IsSpace(r) || r == ','
+	})
+}
+
+// parseMaxPerUser reads how many reactions per user a form allows. Empty fields allow one; malformed ones allow none,
+// which the service rejects.
+func parseMaxPerUser(value string) int {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 1
+	}
+
+	maxPerUser, err := strconv.Atoi(value)
+	if err != nil {
+		return 0
+	}
+
+	return maxPerUser
+}
+
+// reactionSetForm returns the data of the form changing the reaction set of a post or community. The form shows the
+// emojis of the scope's own set, or the given ones when it has none.
+func (h *Handler) reactionSetForm(
+	ctx context.Context,
+	scope reactions.SetScope,
+	scopeID string,
+	action string,
+	emojis []string,
+	csrfField template.HTML,
+) (map[string]any, error) {
+	custom := true
+	maxPerUser := 1
+
+	set, err := h.reactionsSvc.GetReactionSet(ctx, scope, scopeID)
+	if err != nil {
+		if _, ok := errors.AsType[reactions.ReactionSetNotFoundError](err); !ok {
+			return nil, fmt.Errorf("failed to get reaction set: %w", err)
+		}
+
+		custom = false
+	}
+
+	if custom {
+		emojis = set.Emojis
+		maxPerUser = set.MaxPerUser
+	}
+
+	return map[string]any{
+		"Action":         action,
+		"Emojis":         strings.Join(emojis, " "),
+		"MaxPerUser":     maxPerUser,
+		"MaxSetSize":     reactions.MaxSetSize,
+		"Custom":         custom,
+		csrf.TemplateTag: csrfField,
+	}, nil
+}
+
+func (h *Handler) HandleReactionSetsPage() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sets, err := h.reactionsSvc.ListReactionSets(r.Context())
+		if err != nil {
+			handleReactionSetError(w, r, err)
+
+			return
+		}
+
+		h.renderTemplate(w, r, "admin-reactions-page.gohtml", map[string]any{
+			"SiteTitle":      "Reactions",
+			"ReactionSets":   sets,
+			"Scopes":         reactions.SetScopes(),
+			"MaxSetSize":     reactions.MaxSetSize,
+			csrf.TemplateTag: csrf.TemplateField(r),
+		})
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+func (h *Handler) HandleSetReactionSet() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := h.reactionsSvc.SetReactionSet(r.Context(), reactions.SetReactionSetRequest{
+			Scope:      reactions.SetScope(r.FormValue("scope")),
+			ScopeID:    strings.TrimSpace(r.FormValue("scopeId")),
+			Emojis:     parseEmojis(r.FormValue("emojis")),
+			MaxPerUser: parseMaxPerUser(r.FormValue("maxPerUser")),
+		})
+		if err != nil {
+			handleReactionSetError(w, r, err)
+
+			return
+		}
+
+		http.Redirect(w, r, "/admin/reactions", http.StatusSeeOther)
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+func (h *Handler) HandleDeleteReactionSet() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := h.reactionsSvc.DeleteReactionSet(
+			r.Context(),
+			reactions.SetScope(r.FormValue("scope")),
+			r.FormValue("scopeId"),
+		)
+		if err != nil {
+			handleReactionSetError(w, r, err)
+
+			return
+		}
+
+		http.Redirect(w, r, "/admin/reactions", http.StatusSeeOther)
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+func (h *Handler) HandleSetPostReactionSet() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		postID := r.PathValue("postId")
+
+		err := h.reactionsSvc.SetReactionSet(r.Context(), reactions.SetReactionSetRequest{
+			Scope:      reactions.SetScopePost,
+			ScopeID:    postID,
+			Emojis:     parseEmojis(r.FormValue("emojis")),
+			MaxPerUser: parseMaxPerUser(r.FormValue("maxPerUser")),
+		})
+		if err != nil {
+			handleReactionSetError(w, r, err)
+
+			return
+		}
+
+		http.Redirect(w, r, "/p/"+postID, http.StatusSeeOther)
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+// HandleResetPostReactionSet deletes the set of the post, so the set of its community or the site applies again.
+func (h *Handler) HandleResetPostReactionSet() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		postID := r.PathValue("postId")
+
+		err := h.reactionsSvc.DeleteReactionSet(r.Context(), reactions.SetScopePost, postID)
+		if err != nil {
+			handleReactionSetError(w, r, err)
+
+			return
+		}
+
+		http.Redirect(w, r, "/p/"+postID, http.StatusSeeOther)
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+func (h *Handler) HandleSetCommunityReactionSet() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		community, err := h.communitiesSvc.GetCommunityBySlug(r.Context(), r.PathValue("slug"))
+		if err != nil {
+			handleCommunityError(w, r, err)
+
+			return
+		}
+
+		err = h.reactionsSvc.SetReactionSet(r.Context(), reactions.SetReactionSetRequest{
+			Scope:      reactions.SetScopeCommunity,
+			ScopeID:    community.ID,
+			Emojis:     parseEmojis(r.FormValue("emojis")),
+			MaxPerUser: parseMaxPerUser(r.FormValue("maxPerUser")),
+		})
+		if err != nil {
+			handleReactionSetError(w, r, err)
+
+			return
+		}
+
+		http.Redirect(w, r, "/c/"+community.Slug, http.StatusSeeOther)
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+func (h *Handler) HandleResetCommunityReactionSet() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		community, err := h.communitiesSvc.GetCommunityBySlug(r.Context(), r.PathValue("slug"))
+		if err != nil {
+			handleCommunityError(w, r, err)
+
+			return
+		}
+
+		err = h.reactionsSvc.DeleteReactionSet(r.Context(), reactions.SetScopeCommunity, community.ID)
+		if err != nil {
+			handleReactionSetError(w, r, err)
+
+			return
+		}
+
+		http.Redirect(w, r, "/c/"+community.Slug, http.StatusSeeOther)
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+// canSetPostReactionSet reports whether the current user may choose the reaction set of the post.
+func (h *Handler) canSetPostReactionSet(ctx context.Context, post *contents.Post) bool {
+	return post.AuthorID == authcontext.GetSubject(ctx) &&
+		h.authzClient.CanI(ctx, reactions.ServiceName, "", reactions.ActionSetPostReactionSet)
+}
+
+// canManageCommunityReactionSet reports whether the current user may choose the reaction set of the community.
+func (h *Handler) canManageCommunityReactionSet(ctx context.Context, community *communities.Community) bool {
+	return h.authzClient.CanI(ctx, communities.Domain(community.ID), "", reactions.ActionManageReactionSets)
+}
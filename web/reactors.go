@@ -0,0 +1,215 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/discuss"
+	"github.com/nasermirzaei89/scribble/reactions"
+)
+
+// reactorsPageSize is how many reactors of each emoji the popover shows at a time.
+const reactorsPageSize = 10
+
+func handleReactorsError(w http.ResponseWriter, r *http.Request, err error) {
+	if _, ok := errors.AsType[*authorization.AccessDeniedError](err); ok {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+
+		return
+	}
+
+	var (
+		invalidTargetTypeErr reactions.InvalidTargetTypeError
+		invalidCursorErr     InvalidCursorError
+	)
+
+	switch {
+	case errors.As(err, &invalidTargetTypeErr):
+		http.Error(w, "Invalid reaction target", http.StatusBadRequest)
+	case errors.As(err, &invalidCursorErr):
+		http.Error(w, "Invalid cursor", http.StatusBadRequest)
+	default:
+		slog.ErrorContext(r.Context(), "failed to list reactors", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// ReactorView is a reaction as the who reacted popover shows it. Username is empty for users that are gone.
+type ReactorView struct {
+	Username  string
+	ReactedAt time.Time
+}
+
+// reactorsPage returns the data of a page of the users who reacted to the target with the emoji, starting after the
+// cursor.
+func (h *Handler) reactorsPage(
+	ctx context.Context,
+	targetType reactions.TargetType,
+	targetID string,
+	emoji string,
+	before *reactions.ReactorCursor,
+) (map[string]any, error) {
+	reactors, err := h.reactionsSvc.ListReactors(ctx, reactions.ListReactorsRequest{
+		TargetType: targetType,
+		TargetID:   targetID,
+		Emoji:      emoji,
+		Before:     before,
+		Limit:      reactorsPageSize + 1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reactors: %w", err)
+	}
+
+	moreURL := ""
+
+	if len(reactors) > reactorsPageSize {
+		reactors = reactors[:reactorsPageSize]
+		last := reactors[len(reactors)-1]
+
+		query := url.Values{}
+		query.Set("emoji", emoji)
+		query.Set("cursor", reactorCursor(last))
+
+		moreURL = "/react/" + string(targetType) + "/" + url.PathEscape(targetID) + "/reactors?" + query.Encode()
+	}
+
+	userIDs := make([]string, 0, len(reactors))
+	for _, reactor := range reactors {
+		userIDs = append(userIDs, reactor.UserID)
+	}
+
+	users, err := h.authSvc.GetUsers(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reactors: %w", err)
+	}
+
+	usernames := make(map[string]string, len(users))
+	for _, user := range users {
+		usernames[user.ID] = user.Username
+	}
+
+	views := make([]ReactorView, 0, len(reactors))
+	for _, reactor := range reactors {
+		views = append(views, ReactorView{Username: usernames[reactor.UserID], ReactedAt: reactor.CreatedAt})
+	}
+
+	return map[string]any{
+		"Emoji":    emoji,
+		"Reactors": views,
+		"MoreURL":  moreURL,
+	}, nil
+}
+
+// isReactionTargetVisible tells whether the viewer may see the post or comment, or the post the comment is on.
+func (h *Handler) isReactionTargetVisible(
+	r *http.Request,
+	targetType reactions.TargetType,
+	targetID string,
+) (bool, error) {
+	switch targetType {
+	case reactions.TargetTypePost:
+		return h.isPostVisible(r, targetID)
+	case reactions.TargetTypeComment:
+		comment, err := h.discussSvc.GetComment(r.Context(), targetID)
+		if err != nil {
+			if _, ok := errors.AsType[*authorization.AccessDeniedError](err); ok {
+				return false, nil
+			}
+
+			if _, ok := errors.AsType[discuss.CommentNotFoundError](err); ok {
+				return false, nil
+			}
+
+			return false, fmt.Errorf("failed to get comment: %w", err)
+		}
+
+		return h.isPostVisible(r, comment.PostID)
+	default:
+		return true, nil
+	}
+}
+
+// HandleReactors renders who reacted to the target with each emoji, for the popover of the reaction widget. With an
+// emoji and a cursor, it renders the next page of the users who reacted with the emoji.
+func (h *Handler) HandleReactors() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		targetType := reactions.TargetType(r.PathValue("targetType"))
+		targetID := r.PathValue("targetId")
+
+		visible, err := h.isReactionTargetVisible(r, targetType, targetID)
+		if err != nil {
+			handleReactorsError(w, r, err)
+
+			return
+		}
+
+		if !visible {
+			http.Error(w, "Not found", http.StatusNotFound)
+
+			return
+		}
+
+		if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+			createdAt, userID, err := decodeCursor(cursor)
+			if err != nil {
+				handleReactorsError(w, r, err)
+
+				return
+			}
+
+			page, err := h.reactorsPage(
+				r.Context(),
+				targetType,
+				targetID,
+				r.URL.Query().Get("emoji"),
+				&reactions.ReactorCursor{CreatedAt: createdAt, UserID: userID},
+			)
+			if err != nil {
+				handleReactorsError(w, r, err)
+
+				return
+			}
+
+			h.renderTemplate(w, r, "reactors-page.gohtml", page)
+
+			return
+		}
+
+		targetReactions, err := h.reactionsSvc.GetMyReactions(r.Context(), targetType, targetID)
+		if err != nil {
+			handleReactorsError(w, r, err)
+
+			return
+		}
+
+		sections := make([]map[string]any, 0, len(targetReactions.Options))
+
+		for _, option := range targetReactions.Options {
+			if option.Count == 0 {
+				continue
+			}
+
+			page, err := h.reactorsPage(r.Context(), targetType, targetID, option.Emoji, nil)
+			if err != nil {
+				handleReactorsError(w, r, err)
+
+				return
+			}
+
+			page["Count"] = option.Count
+			sections = append(sections, page)
+		}
+
+		h.renderTemplate(w, r, "reactors.gohtml", map[string]any{
+			"Sections": sections,
+		})
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
@@ -0,0 +1,112 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/gorilla/csrf"
+	"github.com/nasermirzaei89/scribble/authentication"
+	"github.com/nasermirzaei89/scribble/contents"
+	"github.com/nasermirzaei89/scribble/reactions"
+)
+
+func userPath(username string) string {
+	return "/u/" + url.PathEscape(username)
+}
+
+// mentionResolver links mentions of existing users to their profiles. Markdown is rendered out of any request, so
+// users are looked up without one.
+type mentionResolver struct {
+	authSvc *authentication.Service
+}
+
+func (resolver mentionResolver) ResolveMention(username string) (string, bool) {
+	user, err := resolver.authSvc.GetUserByUsername(context.Background(), username)
+	if err != nil {
+		if _, ok := errors.AsType[*authentication.UserByUsernameNotFoundError](err); !ok {
+			slog.Error("failed to get mentioned user", "username", username, "error", err)
+		}
+
+		return "", false
+	}
+
+	return userPath(user.Username), true
+}
+
+// HandleUserPage shows the profile of a user with their latest posts.
+func (h *Handler) HandleUserPage() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, err := h.authSvc.GetUserByUsername(r.Context(), r.PathValue("username"))
+		if err != nil {
+			if _, ok := errors.AsType[*authentication.UserByUsernameNotFoundError](err); ok {
+				http.Error(w, "User not found", http.StatusNotFound)
+
+				return
+			}
+
+			slog.ErrorContext(r.Context(), "failed to get user", "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+			return
+		}
+
+		posts, err := h.contentsSvc.ListPosts(r.Context(), contents.ListPostsRequest{
+			AuthorID:    user.ID,
+			CommunityID: "",
+			Sort:        contents.PostSortNew,
+			Period:      contents.TopPeriodAll,
+			Before:      nil,
+			Limit:       0,
+		})
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to list user posts", "userId", user.ID, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+			return
+		}
+
+		postsWithAuthors, err := h.preloadPostAuthor(r.Context(), posts, userPath(user.Username), csrf.TemplateField(r))
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to preload post authors", "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+			return
+		}
+
+		rep, err := h.reputationSvc.GetReputation(r.Context(), user.ID)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to get user reputation", "userId", user.ID, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+			return
+		}
+
+		reactionData, err := h.buildReactionWidgetData(
+			r.Context(),
+			reactions.TargetTypeUser,
+			user.ID,
+			userPath(user.Username),
+			csrf.TemplateField(r),
+		)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to load user reactions", "userId", user.ID, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+			return
+		}
+
+		h.renderTemplate(w, r, "user-page.gohtml", map[string]any{
+			"SiteTitle":      "@" + user.Username,
+			"User":           user,
+			"Reputation":     rep,
+			"Reactions":      reactionData,
+			"Posts":          postsWithAuthors,
+			"Feeds":          slices.Concat(siteFeedLinks(), feedLinks("Posts by @"+user.Username, userFeedPath(user.Username))),
+			csrf.TemplateTag: csrf.TemplateField(r),
+		})
+	})
+}
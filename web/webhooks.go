@@ -0,0 +1,149 @@
+package web
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/csrf"
+	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/webhooks"
+)
+
+func handleWebhookError(w http.ResponseWriter, r *http.Request, err error) {
+	if _, ok := errors.AsType[*authorization.AccessDeniedError](err); ok {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+
+		return
+	}
+
+	var (
+		endpointNotFoundErr  webhooks.EndpointNotFoundError
+		deliveryNotFoundErr  webhooks.DeliveryNotFoundError
+		invalidURLErr        webhooks.InvalidURLError
+		invalidEventTypeErr  webhooks.InvalidEventTypeError
+		missingEventTypesErr webhooks.MissingEventTypesError
+	)
+
+	switch {
+	case errors.As(err, &endpointNotFoundErr):
+		http.Error(w, "Webhook not found", http.StatusNotFound)
+	case errors.As(err, &deliveryNotFoundErr):
+		http.Error(w, "Delivery not found", http.StatusNotFound)
+	case errors.As(err, &invalidURLErr):
+		http.Error(w, "Invalid webhook URL", http.StatusBadRequest)
+	case errors.As(err, &invalidEventTypeErr):
+		http.Error(w, "Invalid event type", http.StatusBadRequest)
+	case errors.As(err, &missingEventTypesErr):
+		http.Error(w, "Choose at least one event type", http.StatusBadRequest)
+	default:
+		slog.ErrorContext(r.Context(), "failed to handle webhook request", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) HandleWebhooksPage() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		endpoints, err := h.webhooksSvc.ListEndpoints(r.Context())
+		if err != nil {
+			handleWebhookError(w, r, err)
+
+			return
+		}
+
+		h.renderTemplate(w, r, "admin-webhooks-page.gohtml", map[string]any{
+			"SiteTitle":      "Webhooks",
+			"Endpoints":      endpoints,
+			"EventTypes":     webhooks.EventTypes(),
+			csrf.TemplateTag: csrf.TemplateField(r),
+		})
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+func (h *Handler) HandleCreateWebhook() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to parse form", "error", err)
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+
+			return
+		}
+
+		endpoint, err := h.webhooksSvc.CreateEndpoint(r.Context(), webhooks.CreateEndpointRequest{
+			URL:         r.FormValue("url"),
+			Description: r.FormValue("description"),
+			EventTypes:  r.Form["eventTypes"],
+		})
+		if err != nil {
+			handleWebhookError(w, r, err)
+
+			return
+		}
+
+		http.Redirect(w, r, "/admin/webhooks/"+endpoint.ID, http.StatusSeeOther)
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+func (h *Handler) HandleWebhookPage() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		endpoint, err := h.webhooksSvc.GetEndpoint(r.Context(), r.PathValue("endpointId"))
+		if err != nil {
+			handleWebhookError(w, r, err)
+
+			return
+		}
+
+		deliveries, err := h.webhooksSvc.ListDeliveries(r.Context(), endpoint.ID)
+		if err != nil {
+			handleWebhookError(w, r, err)
+
+			return
+		}
+
+		h.renderTemplate(w, r, "admin-webhook-page.gohtml", map[string]any{
+			"SiteTitle":      "Webhook",
+			"Endpoint":       endpoint,
+			"Deliveries":     deliveries,
+			csrf.TemplateTag: csrf.TemplateField(r),
+		})
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+func (h *Handler) HandleDeleteWebhook() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := h.webhooksSvc.DeleteEndpoint(r.Context(), r.PathValue("endpointId"))
+		if err != nil {
+			handleWebhookError(w, r, err)
+
+			return
+		}
+
+		http.Redirect(w, r, "/admin/webhooks", http.StatusSeeOther)
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
+
+func (h *Handler) HandleRedeliverWebhook() http.Handler {
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		endpointID := r.PathValue("endpointId")
+
+		_, err := h.webhooksSvc.Redeliver(r.Context(), endpointID, r.PathValue("deliveryId"))
+		if err != nil {
+			handleWebhookError(w, r, err)
+
+			return
+		}
+
+		http.Redirect(w, r, "/admin/webhooks/"+endpointID, http.StatusSeeOther)
+	})
+
+	return h.AuthenticatedOnly(hf)
+}
@@ -0,0 +1,115 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nasermirzaei89/scribble/authorization"
+)
+
+const (
+	ActionCreateEndpoint = "createEndpoint"
+	ActionGetEndpoint    = "getEndpoint"
+	ActionListEndpoints  = "listEndpoints"
+	ActionDeleteEndpoint = "deleteEndpoint"
+	ActionListDeliveries = "listDeliveries"
+	ActionRedeliver      = "redeliver"
+)
+
+type AuthorizationMiddleware struct {
+	authzClient *authorization.Client
+	next        Service
+}
+
+var _ Service = (*AuthorizationMiddleware)(nil)
+
+func NewAuthorizationMiddleware(authzClient *authorization.Client, next Service) *AuthorizationMiddleware {
+	return &AuthorizationMiddleware{
+		authzClient: authzClient,
+		next:        next,
+	}
+}
+
+func (mw *AuthorizationMiddleware) CreateEndpoint(ctx context.Context, req CreateEndpointRequest) (*Endpoint, error) {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, "", ActionCreateEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	endpoint, err := mw.next.CreateEndpoint(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return endpoint, nil
+}
+
+func (mw *AuthorizationMiddleware) GetEndpoint(ctx context.Context, endpointID string) (*Endpoint, error) {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, endpointID, ActionGetEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	endpoint, err := mw.next.GetEndpoint(ctx, endpointID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return endpoint, nil
+}
+
+func (mw *AuthorizationMiddleware) ListEndpoints(ctx context.Context) ([]*Endpoint, error) {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, "", ActionListEndpoints)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	endpoints, err := mw.next.ListEndpoints(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return endpoints, nil
+}
+
+func (mw *AuthorizationMiddleware) DeleteEndpoint(ctx context.Context, endpointID string) error {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, endpointID, ActionDeleteEndpoint)
+	if err != nil {
+		return fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	err = mw.next.DeleteEndpoint(ctx, endpointID)
+	if err != nil {
+		return fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return nil
+}
+
+func (mw *AuthorizationMiddleware) ListDeliveries(ctx context.Context, endpointID string) ([]*Delivery, error) {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, endpointID, ActionListDeliveries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	deliveries, err := mw.next.ListDeliveries(ctx, endpointID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (mw *AuthorizationMiddleware) Redeliver(ctx context.Context, endpointID, deliveryID string) (*Delivery, error) {
+	err := mw.authzClient.CheckAccess(ctx, ServiceName, endpointID, ActionRedeliver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check authorization: %w", err)
+	}
+
+	delivery, err := mw.next.Redeliver(ctx, endpointID, deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call next method: %w", err)
+	}
+
+	return delivery, nil
+}
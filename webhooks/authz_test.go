@@ -0,0 +1,124 @@
+package webhooks_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	fileadapter "github.com/casbin/casbin/v3/persist/file-adapter"
+	"github.com/google/uuid"
+	authcontext "github.com/nasermirzaei89/scribble/authentication/context"
+	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/authorization/casbin"
+	"github.com/nasermirzaei89/scribble/webhooks"
+	"github.com/stretchr/testify/require"
+)
+
+type stubService struct{}
+
+func (s *stubService) CreateEndpoint(
+	ctx context.Context,
+	req webhooks.CreateEndpointRequest,
+) (*webhooks.Endpoint, error) {
+	return &webhooks.Endpoint{}, nil
+}
+
+func (s *stubService) GetEndpoint(ctx context.Context, endpointID string) (*webhooks.Endpoint, error) {
+	return &webhooks.Endpoint{}, nil
+}
+
+func (s *stubService) ListEndpoints(ctx context.Context) ([]*webhooks.Endpoint, error) {
+	return []*webhooks.Endpoint{}, nil
+}
+
+func (s *stubService) DeleteEndpoint(ctx context.Context, endpointID string) error {
+	return nil
+}
+
+func (s *stubService) ListDeliveries(ctx context.Context, endpointID string) ([]*webhooks.Delivery, error) {
+	return []*webhooks.Delivery{}, nil
+}
+
+func (s *stubService) Redeliver(ctx context.Context, endpointID, deliveryID string) (*webhooks.Delivery, error) {
+	return &webhooks.Delivery{}, nil
+}
+
+func TestAuthorizationMiddleware(t *testing.T) {
+	ctx := context.Background()
+
+	tmpDir := t.TempDir()
+	tmpFile := filepath.Join(tmpDir, "policy.csv")
+	content := []byte(`g, system:anonymous, system:unauthenticated, *
+
+p, system:group:root, *, *, *
+`)
+
+	err := os.WriteFile(tmpFile, content, 0o600)
+	require.NoError(t, err)
+
+	adapter := fileadapter.NewAdapter(tmpFile)
+
+	provider, err := casbin.NewAuthorizationProvider(adapter)
+	require.NoError(t, err)
+
+	authzSvc, err := authorization.NewService(provider)
+	require.NoError(t, err)
+
+	client := authorization.NewClient(authzSvc)
+	svc := webhooks.NewAuthorizationMiddleware(client, &stubService{})
+
+	userID := uuid.NewString()
+	err = client.AddToGroup(ctx, userID, authcontext.Authenticated)
+	require.NoError(t, err)
+
+	rootID := uuid.NewString()
+	err = client.AddToGroup(ctx, rootID, authcontext.Authenticated, "system:group:root")
+	require.NoError(t, err)
+
+	calls := map[string]func(ctx context.Context) error{
+		"CreateEndpoint": func(ctx context.Context) error {
+			_, err := svc.CreateEndpoint(ctx, webhooks.CreateEndpointRequest{})
+
+			return err
+		},
+		"GetEndpoint": func(ctx context.Context) error {
+			_, err := svc.GetEndpoint(ctx, "endpoint1")
+
+			return err
+		},
+		"ListEndpoints": func(ctx context.Context) error {
+			_, err := svc.ListEndpoints(ctx)
+
+			return err
+		},
+		"DeleteEndpoint": func(ctx context.Context) error {
+			return svc.DeleteEndpoint(ctx, "endpoint1")
+		},
+		"ListDeliveries": func(ctx context.Context) error {
+			_, err := svc.ListDeliveries(ctx, "endpoint1")
+
+			return err
+		},
+		"Redeliver": func(ctx context.Context) error {
+			_, err := svc.Redeliver(ctx, "endpoint1", "delivery1")
+
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			for _, subjectCtx := range []context.Context{ctx, authcontext.WithSubject(ctx, userID)} {
+				err := call(subjectCtx)
+				require.Error(t, err)
+
+				accessDeniedErr := &authorization.AccessDeniedError{}
+				require.ErrorAs(t, err, &accessDeniedErr)
+			}
+
+			err := call(authcontext.WithSubject(ctx, rootID))
+			require.NoError(t, err)
+		})
+	}
+}
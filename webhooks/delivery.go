@@ -0,0 +1,69 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusDelivered DeliveryStatus = "delivered"
+	DeliveryStatusFailed    DeliveryStatus = "failed"
+)
+
+// Delivery is an event queued for posting to an endpoint.
+type Delivery struct {
+	ID         string
+	EndpointID string
+	EventID    string
+	EventType  string
+	Payload    []byte
+	// RedeliveryOf is the delivery this one repeats, if it was redelivered by an admin.
+	RedeliveryOf  string
+	Status        DeliveryStatus
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	// ResponseStatus is the status code of the last response of the endpoint, or zero if it did not respond.
+	ResponseStatus int
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type DeliveryRepository interface {
+	// Insert queues the delivery. A delivery of an event already queued for the endpoint is skipped, unless it is a
+	// redelivery, and false is returned.
+	Insert(ctx context.Context, delivery *Delivery) (inserted bool, err error)
+	Find(ctx context.Context, deliveryID string) (delivery *Delivery, err error)
+	Update(ctx context.Context, delivery *Delivery) (err error)
+	// ListDue lists pending deliveries whose next attempt is due, oldest first.
+	ListDue(ctx context.Context, now time.Time, limit int) (deliveries []*Delivery, err error)
+	// ListByEndpoint lists the deliveries to the endpoint, newest first.
+	ListByEndpoint(ctx context.Context, endpointID string, limit int) (deliveries []*Delivery, err error)
+}
+
+type DeliveryNotFoundError struct {
+	ID string
+}
+
+func (err DeliveryNotFoundError) Error() string {
+	return fmt.Sprintf("webhook delivery with id %q not found", err.ID)
+}
+
+// DeliveryStatusError is returned when an endpoint responds with a status other than 2xx.
+type DeliveryStatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (err DeliveryStatusError) Error() string {
+	return fmt.Sprintf("endpoint %q responded with status %d", err.URL, err.StatusCode)
+}
+
+func isSuccessStatus(statusCode int) bool {
+	return statusCode >= http.StatusOK && statusCode < http.StatusMultipleChoices
+}
@@ -0,0 +1,61 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Endpoint is a URL deliveries of the events it subscribes to are posted to.
+type Endpoint struct {
+	ID          string
+	URL         string
+	Description string
+	// Secret signs the deliveries, so the receiver can tell they come from this site.
+	Secret     string
+	EventTypes []string
+	CreatedAt  time.Time
+}
+
+// Subscribes tells whether the endpoint receives events of the type.
+func (endpoint *Endpoint) Subscribes(eventType string) bool {
+	return slices.Contains(endpoint.EventTypes, eventType)
+}
+
+type EndpointRepository interface {
+	Insert(ctx context.Context, endpoint *Endpoint) (err error)
+	Find(ctx context.Context, endpointID string) (endpoint *Endpoint, err error)
+	List(ctx context.Context) (endpoints []*Endpoint, err error)
+	Delete(ctx context.Context, endpointID string) (err error)
+}
+
+type EndpointNotFoundError struct {
+	ID string
+}
+
+func (err EndpointNotFoundError) Error() string {
+	return fmt.Sprintf("webhook endpoint with id %q not found", err.ID)
+}
+
+type InvalidURLError struct {
+	URL string
+}
+
+func (err InvalidURLError) Error() string {
+	return fmt.Sprintf("invalid webhook url %q: use an absolute http or https url", err.URL)
+}
+
+type InvalidEventTypeError struct {
+	EventType string
+}
+
+func (err InvalidEventTypeError) Error() string {
+	return fmt.Sprintf("invalid webhook event type %q", err.EventType)
+}
+
+type MissingEventTypesError struct{}
+
+func (err MissingEventTypesError) Error() string {
+	return "webhook endpoint must subscribe to at least one event type"
+}
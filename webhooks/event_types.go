@@ -0,0 +1,50 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/nasermirzaei89/scribble/events"
+)
+
+type eventType struct {
+	name      string
+	subscribe func(bus *events.Bus, handler func(ctx context.Context, event events.Event) error)
+}
+
+func eventTypeOf[E events.Event]() eventType {
+	var zero E
+
+	return eventType{
+		name: zero.EventName(),
+		subscribe: func(bus *events.Bus, handler func(ctx context.Context, event events.Event) error) {
+			events.SubscribeAsync(bus, func(ctx context.Context, event E) error {
+				return handler(ctx, event)
+			})
+		},
+	}
+}
+
+// eventTypes are the events endpoints can subscribe to, in the order they are offered.
+var eventTypes = []eventType{
+	eventTypeOf[events.PostCreated](),
+	eventTypeOf[events.CommentCreated](),
+	eventTypeOf[events.CommentUpdated](),
+	eventTypeOf[events.CommentDeleted](),
+	eventTypeOf[events.ReactionToggled](),
+	eventTypeOf[events.UserMentioned](),
+	eventTypeOf[events.UserRegistered](),
+	eventTypeOf[events.CommunityCreated](),
+	eventTypeOf[events.CommunityMemberJoined](),
+	eventTypeOf[events.CommunityMemberRoleChanged](),
+	eventTypeOf[events.CommunityMemberLeft](),
+}
+
+// EventTypes returns the names of the events endpoints can subscribe to.
+func EventTypes() []string {
+	names := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		names = append(names, eventType.name)
+	}
+
+	return names
+}
@@ -0,0 +1,184 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nasermirzaei89/scribble/authorization"
+	"github.com/nasermirzaei89/scribble/random"
+)
+
+const ServiceName = "github.com/nasermirzaei89/scribble/webhooks"
+
+const (
+	secretSize          = 32
+	deliveryLogPageSize = 50
+)
+
+// Service manages webhook endpoints and their deliveries. Deliveries themselves are made by the Worker.
+type Service interface {
+	CreateEndpoint(ctx context.Context, req CreateEndpointRequest) (*Endpoint, error)
+	GetEndpoint(ctx context.Context, endpointID string) (*Endpoint, error)
+	ListEndpoints(ctx context.Context) ([]*Endpoint, error)
+	DeleteEndpoint(ctx context.Context, endpointID string) error
+	ListDeliveries(ctx context.Context, endpointID string) ([]*Delivery, error)
+	Redeliver(ctx context.Context, endpointID, deliveryID string) (*Delivery, error)
+}
+
+type BaseService struct {
+	endpointRepo EndpointRepository
+	deliveryRepo DeliveryRepository
+}
+
+var _ Service = (*BaseService)(nil)
+
+func NewService( //nolint:ireturn
+	endpointRepo EndpointRepository,
+	deliveryRepo DeliveryRepository,
+	authzClient *authorization.Client,
+) Service {
+	return NewAuthorizationMiddleware(authzClient, NewBaseService(endpointRepo, deliveryRepo))
+}
+
+func NewBaseService(endpointRepo EndpointRepository, deliveryRepo DeliveryRepository) *BaseService {
+	return &BaseService{
+		endpointRepo: endpointRepo,
+		deliveryRepo: deliveryRepo,
+	}
+}
+
+type CreateEndpointRequest struct {
+	URL         string
+	Description string
+	EventTypes  []string
+}
+
+func (svc *BaseService) CreateEndpoint(ctx context.Context, req CreateEndpointRequest) (*Endpoint, error) {
+	endpointURL := strings.TrimSpace(req.URL)
+
+	parsedURL, err := url.Parse(endpointURL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return nil, InvalidURLError{URL: req.URL}
+	}
+
+	if len(req.EventTypes) == 0 {
+		return nil, MissingEventTypesError{}
+	}
+
+	for _, eventType := range req.EventTypes {
+		if !slices.Contains(EventTypes(), eventType) {
+			return nil, InvalidEventTypeError{EventType: eventType}
+		}
+	}
+
+	// Event types are kept in the order they are offered, without duplicates.
+	subscribed := slices.DeleteFunc(EventTypes(), func(eventType string) bool {
+		return !slices.Contains(req.EventTypes, eventType)
+	})
+
+	endpoint := &Endpoint{
+		ID:          uuid.NewString(),
+		URL:         endpointURL,
+		Description: strings.TrimSpace(req.Description),
+		Secret:      random.String(secretSize),
+		EventTypes:  subscribed,
+		CreatedAt:   time.Now(),
+	}
+
+	err = svc.endpointRepo.Insert(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert webhook endpoint: %w", err)
+	}
+
+	return endpoint, nil
+}
+
+func (svc *BaseService) GetEndpoint(ctx context.Context, endpointID string) (*Endpoint, error) {
+	endpoint, err := svc.endpointRepo.Find(ctx, endpointID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find webhook endpoint: %w", err)
+	}
+
+	return endpoint, nil
+}
+
+func (svc *BaseService) ListEndpoints(ctx context.Context) ([]*Endpoint, error) {
+	endpoints, err := svc.endpointRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+
+	return endpoints, nil
+}
+
+// DeleteEndpoint deletes the endpoint with its deliveries.
+func (svc *BaseService) DeleteEndpoint(ctx context.Context, endpointID string) error {
+	_, err := svc.endpointRepo.Find(ctx, endpointID)
+	if err != nil {
+		return fmt.Errorf("failed to find webhook endpoint: %w", err)
+	}
+
+	err = svc.endpointRepo.Delete(ctx, endpointID)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook endpoint: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeliveries lists the latest deliveries to the endpoint, newest first.
+func (svc *BaseService) ListDeliveries(ctx context.Context, endpointID string) ([]*Delivery, error) {
+	_, err := svc.endpointRepo.Find(ctx, endpointID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find webhook endpoint: %w", err)
+	}
+
+	deliveries, err := svc.deliveryRepo.ListByEndpoint(ctx, endpointID, deliveryLogPageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// Redeliver queues the payload of the delivery again, as a new delivery.
+func (svc *BaseService) Redeliver(ctx context.Context, endpointID, deliveryID string) (*Delivery, error) {
+	delivery, err := svc.deliveryRepo.Find(ctx, deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find webhook delivery: %w", err)
+	}
+
+	if delivery.EndpointID != endpointID {
+		return nil, DeliveryNotFoundError{ID: deliveryID}
+	}
+
+	now := time.Now()
+
+	redelivery := &Delivery{
+		ID:             uuid.NewString(),
+		EndpointID:     delivery.EndpointID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		RedeliveryOf:   delivery.ID,
+		Status:         DeliveryStatusPending,
+		Attempts:       0,
+		NextAttemptAt:  now,
+		LastError:      "",
+		ResponseStatus: 0,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	_, err = svc.deliveryRepo.Insert(ctx, redelivery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert webhook delivery: %w", err)
+	}
+
+	return redelivery, nil
+}
@@ -0,0 +1,232 @@
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/scribble/database/sqlite3"
+	"github.com/nasermirzaei89/scribble/events"
+	"github.com/nasermirzaei89/scribble/webhooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+type receiver struct {
+	mu       sync.Mutex
+	status   int
+	requests []receivedRequest
+}
+
+func (rcv *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+
+	rcv.requests = append(rcv.requests, receivedRequest{header: r.Header.Clone(), body: body})
+
+	w.WriteHeader(rcv.status)
+}
+
+func (rcv *receiver) received() []receivedRequest {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+
+	return rcv.requests
+}
+
+func TestWebhooks(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sqlite3.NewDB(ctx, "file:TestWebhooks?mode=memory&cache=shared")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := db.Close()
+		require.NoError(t, err)
+	})
+
+	err = sqlite3.MigrateUp(ctx, db)
+	require.NoError(t, err)
+
+	rcv := &receiver{mu: sync.Mutex{}, status: http.StatusInternalServerError, requests: nil}
+	srv := httptest.NewServer(rcv)
+	t.Cleanup(srv.Close)
+
+	// Redeliveries are queued at the current time, so the worker's clock starts there.
+	now := time.Now().UTC().Truncate(time.Second)
+
+	endpointRepo := sqlite3.NewWebhookEndpointRepository(db)
+	deliveryRepo := sqlite3.NewWebhookDeliveryRepository(db)
+	svc := webhooks.NewBaseService(endpointRepo, deliveryRepo)
+	worker := webhooks.NewWorker(
+		webhooks.Config{HTTPClient: srv.Client(), Now: func() time.Time { return now }},
+		endpointRepo,
+		deliveryRepo,
+	)
+
+	bus := events.NewBus(sqlite3.NewEventOutboxRepository(db), sqlite3.NewTransactor(db))
+	worker.Subscribe(bus)
+
+	t.Run("invalid endpoints", func(t *testing.T) {
+		_, err := svc.CreateEndpoint(ctx, webhooks.CreateEndpointRequest{
+			URL:         "ftp://chat.example/hooks",
+			Description: "",
+			EventTypes:  []string{"post.created"},
+		})
+		require.ErrorAs(t, err, &webhooks.InvalidURLError{})
+
+		_, err = svc.CreateEndpoint(ctx, webhooks.CreateEndpointRequest{
+			URL:         srv.URL,
+			Description: "",
+			EventTypes:  nil,
+		})
+		require.ErrorAs(t, err, &webhooks.MissingEventTypesError{})
+
+		_, err = svc.CreateEndpoint(ctx, webhooks.CreateEndpointRequest{
+			URL:         srv.URL,
+			Description: "",
+			EventTypes:  []string{"post.deleted"},
+		})
+		require.ErrorAs(t, err, &webhooks.InvalidEventTypeError{})
+	})
+
+	endpoint, err := svc.CreateEndpoint(ctx, webhooks.CreateEndpointRequest{
+		URL:         srv.URL + "/posts",
+		Description: "Posts",
+		EventTypes:  []string{"user.registered", "post.created", "post.created"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"post.created", "user.registered"}, endpoint.EventTypes)
+	assert.NotEmpty(t, endpoint.Secret)
+
+	_, err = svc.CreateEndpoint(ctx, webhooks.CreateEndpointRequest{
+		URL:         srv.URL + "/comments",
+		Description: "Comments",
+		EventTypes:  []string{"comment.created"},
+	})
+	require.NoError(t, err)
+
+	err = bus.Publish(ctx, events.PostCreated{PostID: "post1", AuthorID: "user1", CommunityID: "", CreatedAt: now})
+	require.NoError(t, err)
+
+	_, err = bus.ProcessOutbox(ctx)
+	require.NoError(t, err)
+
+	deliveries, err := svc.ListDeliveries(ctx, endpoint.ID)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+
+	delivery := deliveries[0]
+	assert.Equal(t, "post.created", delivery.EventType)
+	assert.Equal(t, webhooks.DeliveryStatusPending, delivery.Status)
+
+	t.Run("failed attempts are retried later", func(t *testing.T) {
+		processed, err := worker.ProcessDeliveries(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, processed)
+
+		deliveries, err := svc.ListDeliveries(ctx, endpoint.ID)
+		require.NoError(t, err)
+		assert.Equal(t, webhooks.DeliveryStatusPending, deliveries[0].Status)
+		assert.Equal(t, 1, deliveries[0].Attempts)
+		assert.Equal(t, http.StatusInternalServerError, deliveries[0].ResponseStatus)
+		assert.NotEmpty(t, deliveries[0].LastError)
+
+		processed, err = worker.ProcessDeliveries(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, processed)
+
+		rcv.status = http.StatusNoContent
+		now = now.Add(time.Minute)
+
+		processed, err = worker.ProcessDeliveries(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, processed)
+
+		deliveries, err = svc.ListDeliveries(ctx, endpoint.ID)
+		require.NoError(t, err)
+		assert.Equal(t, webhooks.DeliveryStatusDelivered, deliveries[0].Status)
+		assert.Equal(t, 2, deliveries[0].Attempts)
+		assert.Equal(t, http.StatusNoContent, deliveries[0].ResponseStatus)
+		assert.Empty(t, deliveries[0].LastError)
+	})
+
+	requests := rcv.received()
+	require.Len(t, requests, 2)
+
+	request := requests[1]
+
+	t.Run("deliveries are signed", func(t *testing.T) {
+		assert.Equal(t, "application/json", request.header.Get("Content-Type"))
+		assert.Equal(t, "post.created", request.header.Get(webhooks.HeaderEvent))
+		assert.Equal(t, delivery.ID, request.header.Get(webhooks.HeaderDelivery))
+
+		timestamp, err := strconv.ParseInt(request.header.Get(webhooks.HeaderTimestamp), 10, 64)
+		require.NoError(t, err)
+		assert.Equal(t, now.Unix(), timestamp)
+
+		assert.Equal(
+			t,
+			webhooks.Signature(endpoint.Secret, time.Unix(timestamp, 0), request.body),
+			request.header.Get(webhooks.HeaderSignature),
+		)
+		assert.NotEqual(
+			t,
+			webhooks.Signature("other", time.Unix(timestamp, 0), request.body),
+			request.header.Get(webhooks.HeaderSignature),
+		)
+
+		var payload map[string]any
+
+		err = json.Unmarshal(request.body, &payload)
+		require.NoError(t, err)
+		assert.Equal(t, "post.created", payload["type"])
+		assert.NotEmpty(t, payload["id"])
+		assert.Equal(t, "post1", payload["data"].(map[string]any)["postId"])
+	})
+
+	t.Run("redeliver", func(t *testing.T) {
+		_, err := svc.Redeliver(ctx, "other", delivery.ID)
+		require.ErrorAs(t, err, &webhooks.DeliveryNotFoundError{})
+
+		redelivery, err := svc.Redeliver(ctx, endpoint.ID, delivery.ID)
+		require.NoError(t, err)
+		assert.Equal(t, delivery.ID, redelivery.RedeliveryOf)
+
+		_, err = worker.ProcessDeliveries(ctx)
+		require.NoError(t, err)
+
+		requests := rcv.received()
+		require.Len(t, requests, 3)
+		assert.Equal(t, redelivery.ID, requests[2].header.Get(webhooks.HeaderDelivery))
+		assert.JSONEq(t, string(request.body), string(requests[2].body))
+
+		deliveries, err := svc.ListDeliveries(ctx, endpoint.ID)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 2)
+	})
+
+	t.Run("delete endpoint", func(t *testing.T) {
+		err := svc.DeleteEndpoint(ctx, endpoint.ID)
+		require.NoError(t, err)
+
+		_, err = svc.GetEndpoint(ctx, endpoint.ID)
+		require.ErrorAs(t, err, &webhooks.EndpointNotFoundError{})
+
+		_, err = svc.ListDeliveries(ctx, endpoint.ID)
+		require.ErrorAs(t, err, &webhooks.EndpointNotFoundError{})
+	})
+}
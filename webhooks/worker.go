@@ -0,0 +1,268 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nasermirzaei89/scribble/events"
+	"github.com/nasermirzaei89/scribble/queue"
+)
+
+// Headers of deliveries. The signature is "sha256=" followed by the hex encoded HMAC-SHA256 of the timestamp, a dot
+// and the body, keyed with the secret of the endpoint.
+const (
+	HeaderEvent     = "X-Scribble-Event"
+	HeaderDelivery  = "X-Scribble-Delivery"
+	HeaderTimestamp = "X-Scribble-Timestamp"
+	HeaderSignature = "X-Scribble-Signature"
+)
+
+const (
+	maxDeliveryAttempts = 8
+	deliveryBaseDelay   = time.Minute
+	deliveryMaxDelay    = 12 * time.Hour
+	deliveryBatchSize   = 50
+	maxResponseSize     = 64 << 10
+	defaultHTTPTimeout  = 30 * time.Second
+
+	// DeliveryPollInterval is how often the worker checks the queue for due deliveries.
+	DeliveryPollInterval = 10 * time.Second
+)
+
+var errMissingEventMetadata = errors.New("missing event metadata")
+
+// deliveryBackoff doubles the wait after every failed attempt, starting at a minute.
+var deliveryBackoff = queue.Backoff{Base: deliveryBaseDelay, Max: deliveryMaxDelay}
+
+// Payload is the body of a delivery. ID is the same on retries and redeliveries, so receivers can skip events they
+// already handled.
+type Payload struct {
+	ID         string       `json:"id"`
+	Type       string       `json:"type"`
+	OccurredAt time.Time    `json:"occurredAt"`
+	Data       events.Event `json:"data"`
+}
+
+// Signature signs the body of a delivery sent at the timestamp, as in HeaderSignature.
+func Signature(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10) + "."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+type Config struct {
+	// HTTPClient delivers payloads. Defaults to a client with a timeout.
+	HTTPClient *http.Client
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Worker queues events for the endpoints subscribed to them, and delivers them.
+type Worker struct {
+	endpointRepo EndpointRepository
+	deliveryRepo DeliveryRepository
+	httpClient   *http.Client
+	now          func() time.Time
+	waker        *queue.Waker
+}
+
+func NewWorker(cfg Config, endpointRepo EndpointRepository, deliveryRepo DeliveryRepository) *Worker {
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+
+	now := cfg.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	return &Worker{
+		endpointRepo: endpointRepo,
+		deliveryRepo: deliveryRepo,
+		httpClient:   httpClient,
+		now:          now,
+		waker:        queue.NewWaker(),
+	}
+}
+
+// Subscribe queues deliveries of the events endpoints can subscribe to, as they are dispatched by the bus.
+func (worker *Worker) Subscribe(bus *events.Bus) {
+	for _, eventType := range eventTypes {
+		eventType.subscribe(bus, worker.enqueue)
+	}
+}
+
+// enqueue queues the event for each endpoint subscribed to it. Events dispatched again are not queued twice.
+func (worker *Worker) enqueue(ctx context.Context, event events.Event) error {
+	metadata, ok := events.MetadataFromContext(ctx)
+	if !ok {
+		return errMissingEventMetadata
+	}
+
+	endpoints, err := worker.endpointRepo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+
+	payload, err := json.Marshal(Payload{
+		ID:         metadata.ID,
+		Type:       metadata.Name,
+		OccurredAt: metadata.OccurredAt,
+		Data:       event,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	now := worker.now()
+	queued := false
+
+	for _, endpoint := range endpoints {
+		if !endpoint.Subscribes(metadata.Name) {
+			continue
+		}
+
+		inserted, err := worker.deliveryRepo.Insert(ctx, &Delivery{
+			ID:             uuid.NewString(),
+			EndpointID:     endpoint.ID,
+			EventID:        metadata.ID,
+			EventType:      metadata.Name,
+			Payload:        payload,
+			RedeliveryOf:   "",
+			Status:         DeliveryStatusPending,
+			Attempts:       0,
+			NextAttemptAt:  now,
+			LastError:      "",
+			ResponseStatus: 0,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to insert delivery to endpoint %q: %w", endpoint.ID, err)
+		}
+
+		queued = queued || inserted
+	}
+
+	if queued {
+		worker.waker.Wake()
+	}
+
+	return nil
+}
+
+// ProcessDeliveries attempts the deliveries that are due and returns how many were attempted. Failed attempts are
+// retried with exponential backoff until they run out of attempts.
+func (worker *Worker) ProcessDeliveries(ctx context.Context) (int, error) {
+	deliveries, err := worker.deliveryRepo.ListDue(ctx, worker.now(), deliveryBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list due deliveries: %w", err)
+	}
+
+	for _, delivery := range deliveries {
+		worker.attempt(ctx, delivery)
+
+		err = worker.deliveryRepo.Update(ctx, delivery)
+		if err != nil {
+			return 0, fmt.Errorf("failed to update delivery %q: %w", delivery.ID, err)
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+func (worker *Worker) attempt(ctx context.Context, delivery *Delivery) {
+	statusCode, err := worker.deliver(ctx, delivery)
+
+	now := worker.now()
+	delivery.Attempts++
+	delivery.ResponseStatus = statusCode
+	delivery.UpdatedAt = now
+
+	if err == nil {
+		delivery.Status = DeliveryStatusDelivered
+		delivery.LastError = ""
+
+		return
+	}
+
+	delivery.LastError = err.Error()
+
+	if delivery.Attempts >= maxDeliveryAttempts {
+		delivery.Status = DeliveryStatusFailed
+
+		slog.WarnContext(ctx, "webhook delivery failed", "deliveryId", delivery.ID, "endpointId", delivery.EndpointID,
+			"attempts", delivery.Attempts, "error", err)
+
+		return
+	}
+
+	delivery.NextAttemptAt = now.Add(deliveryBackoff.Delay(delivery.Attempts))
+}
+
+// deliver posts the payload to the endpoint and returns the status code of the response.
+func (worker *Worker) deliver(ctx context.Context, delivery *Delivery) (int, error) {
+	endpoint, err := worker.endpointRepo.Find(ctx, delivery.EndpointID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find webhook endpoint: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := worker.now()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp.Unix(), 10))
+	req.Header.Set(HeaderSignature, Signature(endpoint.Secret, timestamp, delivery.Payload))
+
+	resp, err := worker.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	defer func() {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseSize))
+
+		err := resp.Body.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to close response body", "error", err)
+		}
+	}()
+
+	if !isSuccessStatus(resp.StatusCode) {
+		return resp.StatusCode, DeliveryStatusError{URL: endpoint.URL, StatusCode: resp.StatusCode}
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Run processes due deliveries whenever events are queued, and every DeliveryPollInterval, until the context is done.
+func (worker *Worker) Run(ctx context.Context) {
+	queue.Worker{
+		Name:      "webhook deliveries",
+		Interval:  DeliveryPollInterval,
+		BatchSize: deliveryBatchSize,
+		Process:   worker.ProcessDeliveries,
+		Waker:     worker.waker,
+	}.Run(ctx)
+}